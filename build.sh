@@ -23,6 +23,9 @@ npm run build
 echo "===== Copying frontend resources for build"
 cp -rf "$web_static_dir"/dist/* "$web_static_build_dir"
 
+echo "===== Vendoring Swagger UI assets"
+"$project_root"/vendor-swagger-ui.sh || exit
+
 echo "===== Copying backend resources for build"
 cp -rf "$project_root"/src/main/go/* "$go_build_dir"
 
@@ -46,11 +46,36 @@ ATTACH '' AS pgsql (TYPE POSTGRES);
 .print '=> Starting data ingestion transaction'
 BEGIN TRANSACTION;
 
+.print '=> Creating portfolio observation time dimension record, if it does not exist'
+INSERT INTO pgsql.portfolio_allocation_obs_time (observation_time_tag, observation_timestamp)
+SELECT getenv('PORTFOLIO_ALLOCATION_OBS_TIME_TAG') AS observation_time_tag, current_timestamp AS observation_timestamp
+    WHERE NOT EXISTS (
+    SELECT 1 FROM pgsql.portfolio_allocation_obs_time
+    WHERE observation_time_tag = getenv('PORTFOLIO_ALLOCATION_OBS_TIME_TAG')
+)
+;
+
+.print '=> Resolving tickers to assets, through current tickers and ticker aliases valid at the observation date'
+-- Same resolution as the API imports: valid aliases have priority over current tickers, as the ticker may have
+-- been reused by another asset since the observation
+CREATE TEMP VIEW asset_ticker_resolution AS
+    SELECT atl.lookup_ticker AS ticker, atl.asset_id
+    FROM pgsql.asset_ticker_lookup atl
+    JOIN pgsql.portfolio_allocation_obs_time paot
+        ON paot.observation_time_tag = getenv('PORTFOLIO_ALLOCATION_OBS_TIME_TAG')
+    WHERE (atl.valid_from IS NULL OR atl.valid_from <= paot.observation_timestamp::DATE)
+        AND (atl.valid_to IS NULL OR atl.valid_to >= paot.observation_timestamp::DATE)
+    QUALIFY row_number() OVER (
+        PARTITION BY atl.lookup_ticker
+        ORDER BY NOT atl.alias, atl.valid_to DESC NULLS FIRST, atl.valid_from DESC NULLS LAST
+    ) = 1
+;
+
 .print '=> Asset data to be inserted into the asset table (WHEN it does not exist)'
 CREATE TEMP VIEW asset_insertion AS
     SELECT swss.asset AS ticker FROM sws_summary swss
-    LEFT JOIN pgsql.asset ass ON swss.asset = ass.ticker
-    WHERE ass.ticker IS NULL
+    LEFT JOIN asset_ticker_resolution atr ON swss.asset = atr.ticker
+    WHERE atr.asset_id IS NULL
 ;
 
 SELECT * FROM asset_insertion;
@@ -60,19 +85,10 @@ INSERT INTO pgsql.asset (ticker)
     SELECT ticker FROM asset_insertion
 ;
 
-.print '=> Creating portfolio observation time dimension record, if it does not exist'
-INSERT INTO pgsql.portfolio_allocation_obs_time (observation_time_tag, observation_timestamp)
-SELECT getenv('PORTFOLIO_ALLOCATION_OBS_TIME_TAG') AS observation_time_tag, current_timestamp AS observation_timestamp
-    WHERE NOT EXISTS (
-    SELECT 1 FROM pgsql.portfolio_allocation_obs_time
-    WHERE observation_time_tag = getenv('PORTFOLIO_ALLOCATION_OBS_TIME_TAG')
-)
-;
-
 .print '=> Portfolio data to be inserted, joining data classification from asset dimension mapping classifier file'
 CREATE TEMP VIEW portfolio_allocation_fact_insertion AS
     SELECT
-        ass.id as asset_id,
+        atr.asset_id as asset_id,
         adm.class as class,
         adm.cash_reserve as cash_reserve,
         if(adm.asset_quantity > 0, adm.asset_quantity, swss.total_shares) as asset_quantity,
@@ -86,7 +102,7 @@ CREATE TEMP VIEW portfolio_allocation_fact_insertion AS
         getenv('PORTFOLIO_ID')::INTEGER AS portfolio_id
     FROM sws_summary swss
     LEFT JOIN asset_dimension_mapping adm ON adm.ticker = swss.asset
-    LEFT JOIN pgsql.asset ass ON ass.ticker = swss.asset
+    LEFT JOIN asset_ticker_resolution atr ON atr.ticker = swss.asset
     JOIN pgsql.portfolio_allocation_obs_time paot ON paot.observation_time_tag = getenv('PORTFOLIO_ALLOCATION_OBS_TIME_TAG')
 ;
 
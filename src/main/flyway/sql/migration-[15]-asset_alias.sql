@@ -0,0 +1,29 @@
+-- Migration: Ticker alias history for assets
+-- Keeps previous tickers (and tickers used by other data sources) so that historical imports and lookups
+-- still resolve to the correct asset after a symbol change
+
+CREATE TABLE asset_alias (
+    id serial NOT NULL,
+    asset_id int NOT NULL,
+    ticker varchar(40) NOT NULL,
+    source varchar(50) NOT NULL DEFAULT '',
+    valid_from date NULL,
+    valid_to date NULL,
+    CONSTRAINT asset_alias_pk PRIMARY KEY (id),
+    CONSTRAINT asset_alias_asset_fk FOREIGN KEY (asset_id) REFERENCES asset(id) ON DELETE CASCADE,
+    CONSTRAINT asset_alias_validity_ck CHECK (valid_from IS NULL OR valid_to IS NULL OR valid_from <= valid_to),
+    CONSTRAINT asset_alias_uk UNIQUE NULLS NOT DISTINCT (ticker, source, valid_from)
+);
+
+CREATE INDEX asset_alias_ticker_idx ON asset_alias (ticker);
+CREATE INDEX asset_alias_asset_id_idx ON asset_alias (asset_id);
+
+-- Unified lookup of current tickers and aliases, used for ticker resolution
+CREATE VIEW asset_ticker_lookup AS
+    SELECT ass.ticker AS lookup_ticker, ass.id AS asset_id, NULL::date AS valid_from, NULL::date AS valid_to,
+        false AS alias
+    FROM asset ass
+    UNION ALL
+    SELECT aa.ticker AS lookup_ticker, aa.asset_id, aa.valid_from, aa.valid_to, true AS alias
+    FROM asset_alias aa
+;
@@ -0,0 +1,11 @@
+-- Migration: Asset metadata
+-- Instrument type, trading currency (ISO 4217), security identifiers and primary exchange of assets
+
+ALTER TABLE asset
+    ADD COLUMN instrument_type varchar(20) NULL,
+    ADD COLUMN currency varchar(3) NULL,
+    ADD COLUMN isin varchar(12) NULL,
+    ADD COLUMN cusip varchar(9) NULL,
+    ADD COLUMN exchange varchar(40) NULL,
+    ADD CONSTRAINT asset_instrument_type_ck
+        CHECK (instrument_type IN ('EQUITY', 'ETF', 'BOND', 'CRYPTO', 'CASH', 'COMMODITY'));
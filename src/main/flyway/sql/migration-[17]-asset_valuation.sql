@@ -0,0 +1,15 @@
+-- Migration: Manual asset valuations
+-- User-entered price points for assets without market quotes (private funds, real estate, fixed deposits),
+-- used by the MANUAL external source
+
+CREATE TABLE asset_valuation (
+    id serial NOT NULL,
+    asset_id int NOT NULL,
+    valuation_date date NOT NULL,
+    price numeric(18,8) NOT NULL,
+    currency varchar(3) NOT NULL,
+    CONSTRAINT asset_valuation_pk PRIMARY KEY (id),
+    CONSTRAINT asset_valuation_asset_fk FOREIGN KEY (asset_id) REFERENCES asset(id) ON DELETE CASCADE,
+    CONSTRAINT asset_valuation_price_ck CHECK (price >= 0),
+    CONSTRAINT asset_valuation_uk UNIQUE (asset_id, valuation_date)
+);
@@ -0,0 +1,12 @@
+-- Migration: Asset integration cache
+-- Cached search and quote responses of the external market data providers, used when the cache is
+-- configured with RDBMS storage
+
+CREATE TABLE asset_integration_cache (
+    cache_key text NOT NULL,
+    payload jsonb NOT NULL,
+    stored_at timestamptz NOT NULL,
+    CONSTRAINT asset_integration_cache_pk PRIMARY KEY (cache_key)
+);
+
+CREATE INDEX asset_integration_cache_stored_at_idx ON asset_integration_cache (stored_at);
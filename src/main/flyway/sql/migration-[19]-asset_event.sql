@@ -0,0 +1,25 @@
+-- Migration: Dividend and split events of assets
+-- Corporate events imported from market data providers, used to derive dividend income and to adjust
+-- historical quantities and prices for splits
+
+CREATE TABLE asset_event (
+    id serial NOT NULL,
+    asset_id int NOT NULL,
+    event_type varchar(20) NOT NULL,
+    event_date date NOT NULL,
+    source varchar(50) NOT NULL,
+    amount numeric(18,8) NULL,
+    currency varchar(3) NULL,
+    split_numerator numeric(18,8) NULL,
+    split_denominator numeric(18,8) NULL,
+    CONSTRAINT asset_event_pk PRIMARY KEY (id),
+    CONSTRAINT asset_event_asset_fk FOREIGN KEY (asset_id) REFERENCES asset(id) ON DELETE CASCADE,
+    CONSTRAINT asset_event_type_ck CHECK (event_type IN ('DIVIDEND', 'SPLIT')),
+    CONSTRAINT asset_event_dividend_ck CHECK (
+        event_type <> 'DIVIDEND' OR (amount IS NOT NULL AND amount >= 0 AND currency IS NOT NULL)
+    ),
+    CONSTRAINT asset_event_split_ck CHECK (
+        event_type <> 'SPLIT' OR (split_numerator > 0 AND split_denominator > 0)
+    ),
+    CONSTRAINT asset_event_uk UNIQUE (asset_id, event_type, event_date)
+);
@@ -0,0 +1,36 @@
+-- Migration: Corporate actions of assets
+-- Splits, reverse splits, symbol changes and mergers with an effective date. Applying an action adjusts the
+-- later portfolio observations, planned allocations and aliases, keeping in reversal_data what is needed to
+-- revert it
+
+CREATE TABLE corporate_action (
+    id serial NOT NULL,
+    asset_id int NOT NULL,
+    action_type varchar(20) NOT NULL,
+    effective_date date NOT NULL,
+    ratio_numerator numeric(18,8) NULL,
+    ratio_denominator numeric(18,8) NULL,
+    new_ticker varchar(40) NULL,
+    target_asset_id int NULL,
+    cash_per_share numeric(18,8) NULL,
+    cash_currency varchar(3) NULL,
+    status varchar(20) NOT NULL DEFAULT 'PENDING',
+    applied_at timestamp with time zone NULL,
+    reversal_data jsonb NULL,
+    CONSTRAINT corporate_action_pk PRIMARY KEY (id),
+    CONSTRAINT corporate_action_asset_fk FOREIGN KEY (asset_id) REFERENCES asset(id) ON DELETE CASCADE,
+    CONSTRAINT corporate_action_target_asset_fk FOREIGN KEY (target_asset_id) REFERENCES asset(id)
+        ON DELETE RESTRICT,
+    CONSTRAINT corporate_action_type_ck CHECK (
+        action_type IN ('SPLIT', 'REVERSE_SPLIT', 'SYMBOL_CHANGE', 'MERGER')
+    ),
+    CONSTRAINT corporate_action_status_ck CHECK (status IN ('PENDING', 'APPLIED', 'REVERTED')),
+    CONSTRAINT corporate_action_ratio_ck CHECK (
+        (ratio_numerator IS NULL AND ratio_denominator IS NULL)
+        OR (ratio_numerator > 0 AND ratio_denominator > 0)
+    ),
+    CONSTRAINT corporate_action_cash_ck CHECK (cash_per_share IS NULL OR cash_per_share >= 0)
+);
+
+CREATE INDEX corporate_action_asset_id_idx ON corporate_action (asset_id);
+CREATE INDEX corporate_action_target_asset_id_idx ON corporate_action (target_asset_id);
@@ -0,0 +1,23 @@
+-- Migration: Background jobs
+-- Long operations run by the in-process job runner, with their parameters, progress and outcome. Jobs left
+-- pending or running when the application stops are resumed on the next start
+
+CREATE TABLE job (
+    id serial NOT NULL,
+    job_type varchar(50) NOT NULL,
+    params jsonb NOT NULL DEFAULT '{}'::jsonb,
+    status varchar(20) NOT NULL DEFAULT 'PENDING',
+    progress_completed int NOT NULL DEFAULT 0,
+    progress_total int NOT NULL DEFAULT 0,
+    result jsonb NULL,
+    error_message text NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    started_at timestamp with time zone NULL,
+    finished_at timestamp with time zone NULL,
+    CONSTRAINT job_pk PRIMARY KEY (id),
+    CONSTRAINT job_status_ck CHECK (status IN ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED', 'CANCELLED')),
+    CONSTRAINT job_progress_ck CHECK (progress_completed >= 0 AND progress_total >= 0)
+);
+
+CREATE INDEX job_status_idx ON job (status);
+CREATE INDEX job_created_at_idx ON job (created_at);
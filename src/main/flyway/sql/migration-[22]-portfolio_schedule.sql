@@ -0,0 +1,39 @@
+-- Migration: Scheduled portfolio tasks
+-- Recurring tasks of a portfolio, such as revaluations and divergence analyses, run by the scheduler on a cron
+-- expression. Every activation is kept in the run history, including the ones missed while the application
+-- was not running
+
+CREATE TABLE portfolio_schedule (
+    id serial NOT NULL,
+    portfolio_id int NOT NULL,
+    task_type varchar(50) NOT NULL,
+    cron_expression varchar(100) NOT NULL,
+    timezone varchar(64) NOT NULL DEFAULT 'UTC',
+    params jsonb NOT NULL DEFAULT '{}'::jsonb,
+    enabled boolean NOT NULL DEFAULT true,
+    next_run_at timestamp with time zone NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT portfolio_schedule_pk PRIMARY KEY (id),
+    CONSTRAINT portfolio_schedule_portfolio_fk FOREIGN KEY (portfolio_id) REFERENCES portfolio(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX portfolio_schedule_portfolio_id_idx ON portfolio_schedule (portfolio_id);
+CREATE INDEX portfolio_schedule_next_run_at_idx ON portfolio_schedule (next_run_at) WHERE enabled;
+
+CREATE TABLE portfolio_schedule_run (
+    id serial NOT NULL,
+    schedule_id int NOT NULL,
+    scheduled_for timestamp with time zone NOT NULL,
+    status varchar(20) NOT NULL,
+    started_at timestamp with time zone NULL,
+    finished_at timestamp with time zone NULL,
+    result jsonb NULL,
+    error_message text NULL,
+    CONSTRAINT portfolio_schedule_run_pk PRIMARY KEY (id),
+    CONSTRAINT portfolio_schedule_run_schedule_fk FOREIGN KEY (schedule_id) REFERENCES portfolio_schedule(id)
+        ON DELETE CASCADE,
+    CONSTRAINT portfolio_schedule_run_status_ck CHECK (status IN ('SUCCEEDED', 'FAILED', 'MISSED'))
+);
+
+CREATE INDEX portfolio_schedule_run_schedule_id_idx ON portfolio_schedule_run (schedule_id, scheduled_for);
@@ -0,0 +1,56 @@
+-- Migration: Outbound webhooks
+-- Webhook URLs registered per portfolio for its domain events. Events are written to the outbox in the same
+-- transaction as the change that emits them, and fanned out by the dispatcher to one delivery per subscribed
+-- webhook, retried with backoff until delivered or out of attempts
+
+CREATE TABLE portfolio_webhook (
+    id serial NOT NULL,
+    portfolio_id int NOT NULL,
+    url varchar(2048) NOT NULL,
+    secret varchar(128) NOT NULL,
+    event_types text[] NOT NULL,
+    enabled boolean NOT NULL DEFAULT true,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT portfolio_webhook_pk PRIMARY KEY (id),
+    CONSTRAINT portfolio_webhook_portfolio_fk FOREIGN KEY (portfolio_id) REFERENCES portfolio(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX portfolio_webhook_portfolio_id_idx ON portfolio_webhook (portfolio_id);
+
+CREATE TABLE outbox_event (
+    id serial NOT NULL,
+    portfolio_id int NOT NULL,
+    event_type varchar(50) NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    dispatched_at timestamp with time zone NULL,
+    CONSTRAINT outbox_event_pk PRIMARY KEY (id),
+    CONSTRAINT outbox_event_portfolio_fk FOREIGN KEY (portfolio_id) REFERENCES portfolio(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX outbox_event_undispatched_idx ON outbox_event (id) WHERE dispatched_at IS NULL;
+
+CREATE TABLE webhook_delivery (
+    id serial NOT NULL,
+    webhook_id int NOT NULL,
+    event_id int NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'PENDING',
+    attempt_count int NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NULL,
+    last_status_code int NULL,
+    last_error text NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    delivered_at timestamp with time zone NULL,
+    CONSTRAINT webhook_delivery_pk PRIMARY KEY (id),
+    CONSTRAINT webhook_delivery_webhook_fk FOREIGN KEY (webhook_id) REFERENCES portfolio_webhook(id)
+        ON DELETE CASCADE,
+    CONSTRAINT webhook_delivery_event_fk FOREIGN KEY (event_id) REFERENCES outbox_event(id)
+        ON DELETE CASCADE,
+    CONSTRAINT webhook_delivery_webhook_event_uk UNIQUE (webhook_id, event_id),
+    CONSTRAINT webhook_delivery_status_ck CHECK (status IN ('PENDING', 'SUCCEEDED', 'FAILED'))
+);
+
+CREATE INDEX webhook_delivery_webhook_id_idx ON webhook_delivery (webhook_id, created_at);
+CREATE INDEX webhook_delivery_next_attempt_at_idx ON webhook_delivery (next_attempt_at) WHERE status = 'PENDING';
@@ -0,0 +1,52 @@
+-- Migration: Divergence alerts
+-- Alert rules on the divergence of a portfolio from an allocation plan, evaluated when observations are merged
+-- or prices are refreshed. A breach of a rule triggers an alert, open until the breach is over or the alert is
+-- resolved by the user, with at most one open alert per rule and hierarchical id
+
+CREATE TABLE divergence_alert_rule (
+    id serial NOT NULL,
+    portfolio_id int NOT NULL,
+    allocation_plan_id int NOT NULL,
+    name varchar(100) NOT NULL,
+    rule_type varchar(30) NOT NULL,
+    hierarchical_id varchar(500) NULL,
+    threshold_percent numeric(5, 2) NOT NULL,
+    enabled boolean NOT NULL DEFAULT true,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT divergence_alert_rule_pk PRIMARY KEY (id),
+    CONSTRAINT divergence_alert_rule_portfolio_fk FOREIGN KEY (portfolio_id) REFERENCES portfolio(id)
+        ON DELETE CASCADE,
+    CONSTRAINT divergence_alert_rule_allocation_plan_fk FOREIGN KEY (allocation_plan_id)
+        REFERENCES allocation_plan(id) ON DELETE CASCADE,
+    CONSTRAINT divergence_alert_rule_type_ck CHECK (rule_type IN ('DRIFT_ABOVE', 'CASH_RESERVE_BELOW')),
+    CONSTRAINT divergence_alert_rule_threshold_ck CHECK (threshold_percent BETWEEN 0 AND 100)
+);
+
+CREATE INDEX divergence_alert_rule_portfolio_id_idx ON divergence_alert_rule (portfolio_id);
+
+CREATE TABLE divergence_alert (
+    id serial NOT NULL,
+    rule_id int NOT NULL,
+    portfolio_id int NOT NULL,
+    hierarchical_id varchar(500) NULL,
+    status varchar(20) NOT NULL DEFAULT 'TRIGGERED',
+    threshold_percent numeric(5, 2) NOT NULL,
+    observed_percent numeric(7, 2) NOT NULL,
+    observation_time_id int NOT NULL,
+    triggered_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_evaluated_at timestamp with time zone NOT NULL DEFAULT now(),
+    acknowledged_at timestamp with time zone NULL,
+    resolved_at timestamp with time zone NULL,
+    CONSTRAINT divergence_alert_pk PRIMARY KEY (id),
+    CONSTRAINT divergence_alert_rule_fk FOREIGN KEY (rule_id) REFERENCES divergence_alert_rule(id)
+        ON DELETE CASCADE,
+    CONSTRAINT divergence_alert_portfolio_fk FOREIGN KEY (portfolio_id) REFERENCES portfolio(id)
+        ON DELETE CASCADE,
+    CONSTRAINT divergence_alert_observation_time_fk FOREIGN KEY (observation_time_id)
+        REFERENCES portfolio_allocation_obs_time(id) ON DELETE CASCADE,
+    CONSTRAINT divergence_alert_status_ck CHECK (status IN ('TRIGGERED', 'ACKNOWLEDGED', 'RESOLVED'))
+);
+
+CREATE UNIQUE INDEX divergence_alert_open_uk ON divergence_alert (rule_id, coalesce(hierarchical_id, ''))
+    WHERE status <> 'RESOLVED';
+CREATE INDEX divergence_alert_portfolio_id_idx ON divergence_alert (portfolio_id, triggered_at);
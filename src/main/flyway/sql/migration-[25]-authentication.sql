@@ -0,0 +1,45 @@
+-- Migration: Authentication
+-- Local user accounts with bcrypt password hashes, the web UI sessions of the users and their scoped personal
+-- API tokens. Sessions and tokens are only stored as SHA-256 hashes, so the database never holds a usable
+-- credential
+
+CREATE TABLE app_user (
+    id serial NOT NULL,
+    username varchar(100) NOT NULL,
+    password_hash varchar(100) NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT app_user_pk PRIMARY KEY (id),
+    CONSTRAINT app_user_username_uk UNIQUE (username)
+);
+
+CREATE TABLE user_session (
+    id serial NOT NULL,
+    user_id int NOT NULL,
+    token_hash char(64) NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    expires_at timestamp with time zone NOT NULL,
+    CONSTRAINT user_session_pk PRIMARY KEY (id),
+    CONSTRAINT user_session_user_fk FOREIGN KEY (user_id) REFERENCES app_user(id)
+        ON DELETE CASCADE,
+    CONSTRAINT user_session_token_hash_uk UNIQUE (token_hash)
+);
+
+CREATE INDEX user_session_expires_at_idx ON user_session (expires_at);
+
+CREATE TABLE api_token (
+    id serial NOT NULL,
+    user_id int NOT NULL,
+    name varchar(100) NOT NULL,
+    token_hash char(64) NOT NULL,
+    scopes text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    expires_at timestamp with time zone NULL,
+    last_used_at timestamp with time zone NULL,
+    revoked_at timestamp with time zone NULL,
+    CONSTRAINT api_token_pk PRIMARY KEY (id),
+    CONSTRAINT api_token_user_fk FOREIGN KEY (user_id) REFERENCES app_user(id)
+        ON DELETE CASCADE,
+    CONSTRAINT api_token_token_hash_uk UNIQUE (token_hash)
+);
+
+CREATE INDEX api_token_user_id_idx ON api_token (user_id);
@@ -0,0 +1,42 @@
+-- Migration: Portfolio access
+-- Portfolios are owned by a user, who can grant other users editor or viewer access and create expiring
+-- read-only share links. Share links are only stored as SHA-256 hashes of their tokens. Portfolios created
+-- before authentication was enabled stay without an owner until one is assigned on startup
+
+ALTER TABLE portfolio ADD COLUMN owner_user_id int NULL;
+
+ALTER TABLE portfolio ADD CONSTRAINT portfolio_owner_user_fk FOREIGN KEY (owner_user_id) REFERENCES app_user(id)
+    ON DELETE SET NULL;
+
+CREATE TABLE portfolio_grant (
+    portfolio_id int NOT NULL,
+    user_id int NOT NULL,
+    role varchar(20) NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT portfolio_grant_pk PRIMARY KEY (portfolio_id, user_id),
+    CONSTRAINT portfolio_grant_portfolio_fk FOREIGN KEY (portfolio_id) REFERENCES portfolio(id)
+        ON DELETE CASCADE,
+    CONSTRAINT portfolio_grant_user_fk FOREIGN KEY (user_id) REFERENCES app_user(id)
+        ON DELETE CASCADE,
+    CONSTRAINT portfolio_grant_role_ck CHECK (role IN ('EDITOR', 'VIEWER'))
+);
+
+CREATE INDEX portfolio_grant_user_id_idx ON portfolio_grant (user_id);
+
+CREATE TABLE portfolio_share_link (
+    id serial NOT NULL,
+    portfolio_id int NOT NULL,
+    token_hash char(64) NOT NULL,
+    created_by_user_id int NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    expires_at timestamp with time zone NOT NULL,
+    revoked_at timestamp with time zone NULL,
+    CONSTRAINT portfolio_share_link_pk PRIMARY KEY (id),
+    CONSTRAINT portfolio_share_link_portfolio_fk FOREIGN KEY (portfolio_id) REFERENCES portfolio(id)
+        ON DELETE CASCADE,
+    CONSTRAINT portfolio_share_link_created_by_user_fk FOREIGN KEY (created_by_user_id) REFERENCES app_user(id)
+        ON DELETE CASCADE,
+    CONSTRAINT portfolio_share_link_token_hash_uk UNIQUE (token_hash)
+);
+
+CREATE INDEX portfolio_share_link_portfolio_id_idx ON portfolio_share_link (portfolio_id);
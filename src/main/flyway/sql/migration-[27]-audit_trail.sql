@@ -0,0 +1,36 @@
+-- Migration: Audit trail
+-- Append-only record of the changes to portfolios, observations, allocation facts, allocation plans and
+-- assets, with the acting user and JSON snapshots of the changed data before and after each change. Entries
+-- have no foreign keys, so they outlive the data and users they refer to
+
+CREATE TABLE audit_entry (
+    id bigserial NOT NULL,
+    entity_type varchar(40) NOT NULL,
+    entity_id bigint NOT NULL,
+    portfolio_id int NULL,
+    action varchar(20) NOT NULL,
+    actor_user_id int NULL,
+    actor_username varchar(100) NULL,
+    before_data jsonb NULL,
+    after_data jsonb NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT audit_entry_pk PRIMARY KEY (id),
+    CONSTRAINT audit_entry_entity_type_ck CHECK (
+        entity_type IN ('PORTFOLIO', 'PORTFOLIO_OBSERVATION', 'PORTFOLIO_ALLOCATION', 'ALLOCATION_PLAN', 'ASSET')
+    ),
+    CONSTRAINT audit_entry_action_ck CHECK (action IN ('CREATE', 'UPDATE', 'DELETE'))
+);
+
+CREATE INDEX audit_entry_entity_idx ON audit_entry (entity_type, entity_id);
+CREATE INDEX audit_entry_portfolio_id_idx ON audit_entry (portfolio_id);
+CREATE INDEX audit_entry_created_at_idx ON audit_entry (created_at);
+
+CREATE FUNCTION audit_entry_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_entry is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_entry_append_only
+    BEFORE UPDATE OR DELETE ON audit_entry
+    FOR EACH ROW EXECUTE FUNCTION audit_entry_append_only();
@@ -0,0 +1,23 @@
+-- Migration: Optimistic concurrency
+-- Versions incremented on each update of portfolios, assets and allocation plans, and on each merge of the
+-- allocation snapshot of a portfolio in an observation. Updates based on a stale version are rejected
+
+ALTER TABLE portfolio ADD COLUMN version bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE asset ADD COLUMN version bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE allocation_plan ADD COLUMN version bigint NOT NULL DEFAULT 1;
+
+CREATE TABLE portfolio_snapshot_version (
+    portfolio_id int NOT NULL,
+    observation_time_id int NOT NULL,
+    version bigint NOT NULL DEFAULT 1,
+    CONSTRAINT portfolio_snapshot_version_pk PRIMARY KEY (portfolio_id, observation_time_id),
+    CONSTRAINT portfolio_snapshot_version_portfolio_fk FOREIGN KEY (portfolio_id) REFERENCES portfolio(id)
+        ON DELETE CASCADE,
+    CONSTRAINT portfolio_snapshot_version_obs_time_fk FOREIGN KEY (observation_time_id)
+        REFERENCES portfolio_allocation_obs_time(id) ON DELETE CASCADE
+);
+
+INSERT INTO portfolio_snapshot_version (portfolio_id, observation_time_id)
+SELECT DISTINCT portfolio_id, observation_time_id FROM portfolio_allocation_fact;
@@ -0,0 +1,19 @@
+-- Migration: Idempotency keys
+-- Requests sent with an Idempotency-Key header, by key and caller scope, with the fingerprint of the request
+-- and, once it is completed, the response replayed to its duplicates until the key expires
+
+CREATE TABLE idempotency_key (
+    scope varchar(40) NOT NULL,
+    idempotency_key varchar(255) NOT NULL,
+    request_fingerprint varchar(64) NOT NULL,
+    status varchar(20) NOT NULL,
+    response_status int NULL,
+    response_headers jsonb NULL,
+    response_body bytea NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    CONSTRAINT idempotency_key_pk PRIMARY KEY (scope, idempotency_key),
+    CONSTRAINT idempotency_key_status_ck CHECK (status IN ('PROCESSING', 'COMPLETED'))
+);
+
+CREATE INDEX idempotency_key_expires_at_idx ON idempotency_key (expires_at);
@@ -0,0 +1,10 @@
+-- Migration: Job creator
+-- Jobs record the user submitting them, so they are only listed to that user when authentication is enabled.
+-- Jobs submitted while authentication was disabled, or whose user was deleted, stay without a creator
+
+ALTER TABLE job ADD COLUMN created_by_user_id int NULL;
+
+ALTER TABLE job ADD CONSTRAINT job_created_by_user_fk FOREIGN KEY (created_by_user_id) REFERENCES app_user(id)
+    ON DELETE SET NULL;
+
+CREATE INDEX job_created_by_user_id_idx ON job (created_by_user_id);
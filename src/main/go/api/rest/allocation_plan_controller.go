@@ -2,11 +2,13 @@ package rest
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/benizzio/open-asset-allocator/api/rest/model"
 	"github.com/benizzio/open-asset-allocator/application"
+	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/domain/allocation"
 	"github.com/benizzio/open-asset-allocator/domain/service"
 	"github.com/benizzio/open-asset-allocator/infra"
@@ -25,11 +27,28 @@ func (controller *AllocationPlanRESTController) BuildRoutes() []infra.RESTRoute
 			Method:   http.MethodGet,
 			Path:     "/api/portfolio/:" + portfolioIdParam + "/allocation-plan",
 			Handlers: gin.HandlersChain{controller.getAllocationPlans},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the asset allocation plans of the portfolio",
+				ResponseBody: []*model.AllocationPlanDTS{},
+			},
 		},
 		{
 			Method:   http.MethodPost,
 			Path:     "/api/portfolio/:" + portfolioIdParam + "/allocation-plan",
 			Handlers: gin.HandlersChain{controller.postAssetAllocationPlan},
+			Doc: infra.RESTRouteDoc{
+				Summary:     "Create or update an asset allocation plan of the portfolio",
+				RequestBody: model.AllocationPlanDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/allocation-plan/:" + planIdParam,
+			Handlers: gin.HandlersChain{controller.getAllocationPlan},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get an asset allocation plan of the portfolio",
+				ResponseBody: model.AllocationPlanDTS{},
+			},
 		},
 	}
 }
@@ -56,6 +75,58 @@ func (controller *AllocationPlanRESTController) getAllocationPlans(context *gin.
 	context.JSON(http.StatusOK, allocationPlansDTS)
 }
 
+// getAllocationPlan handles GET requests for a single asset allocation plan of a portfolio, with its version
+// as the ETag to be sent back as If-Match when updating it.
+func (controller *AllocationPlanRESTController) getAllocationPlan(context *gin.Context) {
+
+	var portfolioIdParamValue = context.Param(portfolioIdParam)
+	portfolioId, err := langext.ParseInt64(portfolioIdParamValue)
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	var planIdParamValue = context.Param(planIdParam)
+	planId, err := langext.ParseInt64(planIdParamValue)
+	if gininfra.HandleAPIError(context, getPlanIdErrorMessage, err) {
+		return
+	}
+
+	allocationPlan, err := controller.findPortfolioAllocationPlan(portfolioId, planId)
+	if gininfra.HandleAPIError(context, "Error getting allocation plan", err) {
+		return
+	}
+
+	if allocationPlan == nil {
+		gininfra.SendDataNotFoundResponse(context, "Allocation plan", planIdParamValue)
+		return
+	}
+
+	gininfra.SetVersionETag(context, allocationPlan.Version)
+	context.JSON(http.StatusOK, model.MapToAllocationPlanDTS(allocationPlan))
+}
+
+// findPortfolioAllocationPlan finds an asset allocation plan among the ones of a portfolio, returning nil
+// when the portfolio has no such plan.
+func (controller *AllocationPlanRESTController) findPortfolioAllocationPlan(
+	portfolioId int64,
+	planId int64,
+) (*domain.AllocationPlan, error) {
+
+	var planType = allocation.AssetAllocationPlan
+	allocationPlans, err := controller.allocationPlanService.GetAllocationPlans(portfolioId, &planType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, allocationPlan := range allocationPlans {
+		if allocationPlan.Id == planId {
+			return allocationPlan, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func (controller *AllocationPlanRESTController) postAssetAllocationPlan(context *gin.Context) {
 
 	var portfolioIdParamValue = context.Param(portfolioIdParam)
@@ -78,14 +149,46 @@ func (controller *AllocationPlanRESTController) postAssetAllocationPlan(context
 		return
 	}
 
-	err = controller.allocationPlanManagementAppService.PersistAllocationPlan(allocationPlan)
+	allocationPlan.Version = gininfra.GetIfMatchVersion(context)
+
+	err = controller.allocationPlanManagementAppService.PersistAllocationPlan(auditContext(context), allocationPlan)
+	if isVersionConflict(err) {
+		controller.sendCurrentAllocationPlan(context, portfolioId, allocationPlan.Id)
+		return
+	}
 	if gininfra.HandleAPIError(context, "Error persisting allocation plan", err) {
 		return
 	}
 
+	gininfra.SetVersionETag(context, allocationPlan.Version)
 	context.Status(http.StatusNoContent)
 }
 
+// sendCurrentAllocationPlan answers an update based on a stale version of an allocation plan with its
+// current version.
+func (controller *AllocationPlanRESTController) sendCurrentAllocationPlan(
+	context *gin.Context,
+	portfolioId int64,
+	planId int64,
+) {
+
+	currentAllocationPlan, err := controller.findPortfolioAllocationPlan(portfolioId, planId)
+	if gininfra.HandleAPIError(context, "Error getting current allocation plan", err) {
+		return
+	}
+
+	if currentAllocationPlan == nil {
+		gininfra.SendDataNotFoundResponse(context, "Allocation plan", strconv.FormatInt(planId, 10))
+		return
+	}
+
+	gininfra.SendPreconditionFailedResponse(
+		context,
+		currentAllocationPlan.Version,
+		model.MapToAllocationPlanDTS(currentAllocationPlan),
+	)
+}
+
 func BuildAllocationPlanRESTController(
 	allocationPlanService *service.AllocationPlanDomService,
 	allocationPlanManagementAppService *application.AllocationPlanManagementAppService,
@@ -2,10 +2,13 @@ package rest
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/application"
+	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/domain/service"
 	"github.com/benizzio/open-asset-allocator/infra"
 	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
@@ -14,7 +17,8 @@ import (
 )
 
 type AssetRESTController struct {
-	assetDomService *service.AssetDomService
+	assetDomService           *service.AssetDomService
+	assetManagementAppService *application.AssetManagementAppService
 }
 
 func (controller *AssetRESTController) BuildRoutes() []infra.RESTRoute {
@@ -23,21 +27,171 @@ func (controller *AssetRESTController) BuildRoutes() []infra.RESTRoute {
 			Method:   http.MethodGet,
 			Path:     "/api/asset",
 			Handlers: gin.HandlersChain{controller.getKnownAssets},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the known assets",
+				ResponseBody: []*model.AssetDTS{},
+			},
 		},
 		{
 			Method:   http.MethodGet,
 			Path:     "/api/asset/:" + assetIdOrTickerParam,
 			Handlers: gin.HandlersChain{controller.getAssetById},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get an asset by id or ticker",
+				ResponseBody: model.AssetDTS{},
+			},
 		},
 		{
 			Method:   http.MethodPut,
 			Path:     "/api/asset",
 			Handlers: gin.HandlersChain{controller.putAsset},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Update an asset",
+				RequestBody:  model.AssetDTS{},
+				ResponseBody: model.AssetDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/alias",
+			Handlers: gin.HandlersChain{controller.getAssetAliases},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the aliases of an asset",
+				ResponseBody: []*model.AssetAliasDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/alias",
+			Handlers: gin.HandlersChain{controller.postAssetAlias},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Add an alias to an asset",
+				RequestBody:    model.AssetAliasDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.AssetAliasDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/alias/:" + assetAliasIdParam,
+			Handlers: gin.HandlersChain{controller.deleteAssetAlias},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Remove an alias of an asset",
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/valuation",
+			Handlers: gin.HandlersChain{controller.getAssetValuations},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the manual valuations of an asset",
+				ResponseBody: []*model.AssetValuationDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/valuation",
+			Handlers: gin.HandlersChain{controller.postAssetValuation},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Add a manual valuation to an asset",
+				RequestBody:    model.AssetValuationDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.AssetValuationDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/valuation/:" + assetValuationIdParam,
+			Handlers: gin.HandlersChain{controller.deleteAssetValuation},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Remove a manual valuation of an asset",
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/external-asset",
+			Handlers: gin.HandlersChain{controller.getLinkedExternalAssets},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the external assets linked to an asset",
+				ResponseBody: []*model.ExternalAssetDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/external-asset",
+			Handlers: gin.HandlersChain{controller.postLinkedExternalAsset},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Link an external asset to an asset",
+				RequestBody:    model.ExternalAssetDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   []*model.ExternalAssetDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPut,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/external-asset/priority",
+			Handlers: gin.HandlersChain{controller.putLinkedExternalAssetsPriority},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Prioritize the external assets linked to an asset",
+				RequestBody:  model.ExternalAssetPriorityDTS{},
+				ResponseBody: []*model.ExternalAssetDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/external-asset",
+			Handlers: gin.HandlersChain{controller.deleteLinkedExternalAsset},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Unlink an external asset from an asset",
+				Query:   model.ExternalAssetReferenceQueryDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/quote",
+			Handlers: gin.HandlersChain{controller.getAssetQuote},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Quote the last close price of an asset",
+				ResponseBody: model.AssetQuoteDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/event",
+			Handlers: gin.HandlersChain{controller.getAssetEvents},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the dividend and split events of an asset",
+				ResponseBody: []*model.AssetEventDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/event/import",
+			Handlers: gin.HandlersChain{controller.postAssetEventsImport},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Import the dividend and split events of an asset from its external assets",
+				ResponseStatus: http.StatusOK,
+				ResponseBody:   []*model.AssetEventDTS{},
+			},
 		},
 		{
 			Method:   http.MethodGet,
 			Path:     "/api/external-asset",
 			Handlers: gin.HandlersChain{controller.getExternalAssets},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Search the external asset providers",
+				Query:        model.ExternalAssetSearchQueryDTS{},
+				ResponseBody: []*model.ExternalAssetDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/external-asset/cache-metrics",
+			Handlers: gin.HandlersChain{controller.getExternalAssetCacheMetrics},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get the metrics of the external asset provider cache",
+				ResponseBody: []*model.AssetIntegrationCacheMetricsDTS{},
+			},
 		},
 	}
 }
@@ -69,6 +223,7 @@ func (controller *AssetRESTController) getAssetById(context *gin.Context) {
 	}
 
 	var assetDTS = model.MapToAssetDTS(asset)
+	gininfra.SetVersionETag(context, asset.Version)
 	context.JSON(http.StatusOK, assetDTS)
 }
 
@@ -106,18 +261,421 @@ func (controller *AssetRESTController) putAsset(context *gin.Context) {
 	}
 
 	var asset = model.MapToAsset(&assetDTS)
-	updatedAsset, err := controller.assetDomService.UpdateAsset(asset)
+	asset.Version = gininfra.GetIfMatchVersion(context)
+
+	updatedAsset, err := controller.assetManagementAppService.UpdateAsset(auditContext(context), asset)
+	if isVersionConflict(err) {
+		controller.sendCurrentAsset(context, asset.Id)
+		return
+	}
 	if gininfra.HandleAPIError(context, "Error updating asset", err) {
 		return
 	}
 
+	if updatedAsset == nil {
+		gininfra.SendDataNotFoundResponse(context, "Asset", strconv.FormatInt(asset.Id, 10))
+		return
+	}
+
 	var responseBody = model.MapToAssetDTS(updatedAsset)
+	gininfra.SetVersionETag(context, updatedAsset.Version)
 	context.JSON(http.StatusOK, responseBody)
 }
 
-func BuildAssetRESTController(assetDomService *service.AssetDomService) *AssetRESTController {
+// sendCurrentAsset answers an update based on a stale version of an asset with its current version.
+func (controller *AssetRESTController) sendCurrentAsset(context *gin.Context, assetId int64) {
+
+	var assetIdValue = strconv.FormatInt(assetId, 10)
+
+	currentAsset, err := controller.assetDomService.FindAssetById(assetId)
+	if gininfra.HandleAPIError(context, "Error getting current asset", err) {
+		return
+	}
+
+	if currentAsset == nil {
+		gininfra.SendDataNotFoundResponse(context, "Asset", assetIdValue)
+		return
+	}
+
+	gininfra.SendPreconditionFailedResponse(context, currentAsset.Version, model.MapToAssetDTS(currentAsset))
+}
+
+// findAssetFromParam resolves the asset referenced by the id or ticker URL parameter, sending the
+// error or not found response when it cannot be resolved.
+func findAssetFromParam(context *gin.Context, assetDomService *service.AssetDomService) (*domain.Asset, bool) {
+
+	var assetIdOrTickerParamValue = context.Param(assetIdOrTickerParam)
+
+	asset, err := assetDomService.FindAssetByUniqueIdentifier(assetIdOrTickerParamValue)
+	if gininfra.HandleAPIError(context, "Error getting asset by Id or Ticker", err) {
+		return nil, false
+	}
+
+	if asset == nil {
+		gininfra.SendDataNotFoundResponse(context, "Asset", assetIdOrTickerParamValue)
+		return nil, false
+	}
+
+	return asset, true
+}
+
+// getAssetAliases handles GET requests listing the ticker aliases of an asset.
+func (controller *AssetRESTController) getAssetAliases(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	aliases, err := controller.assetDomService.GetAssetAliases(asset.Id)
+	if gininfra.HandleAPIError(context, "Error getting asset aliases", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToAssetAliasDTSs(aliases))
+}
+
+// postAssetAlias handles POST requests registering a new ticker alias for an asset.
+func (controller *AssetRESTController) postAssetAlias(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var aliasDTS model.AssetAliasDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &aliasDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindAssetAliasErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var alias = model.MapToAssetAlias(asset.Id, &aliasDTS)
+	persistedAlias, err := controller.assetManagementAppService.InsertAssetAlias(auditContext(context), alias)
+	if gininfra.HandleAPIError(context, "Error inserting asset alias", err) {
+		return
+	}
+
+	context.JSON(http.StatusCreated, model.MapToAssetAliasDTS(persistedAlias))
+}
+
+// deleteAssetAlias handles DELETE requests removing a ticker alias from an asset.
+func (controller *AssetRESTController) deleteAssetAlias(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var aliasIdParamValue = context.Param(assetAliasIdParam)
+	aliasId, err := langext.ParseInt64(aliasIdParamValue)
+	if gininfra.HandleAPIError(context, getAssetAliasIdErrorMessage, err) {
+		return
+	}
+
+	deleted, err := controller.assetManagementAppService.DeleteAssetAlias(auditContext(context), asset.Id, aliasId)
+	if gininfra.HandleAPIError(context, "Error deleting asset alias", err) {
+		return
+	}
+
+	if !deleted {
+		gininfra.SendDataNotFoundResponse(context, "Asset alias", aliasIdParamValue)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// getAssetValuations handles GET requests listing the manual valuations of an asset, from the most
+// recent to the oldest.
+func (controller *AssetRESTController) getAssetValuations(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	valuations, err := controller.assetDomService.GetAssetValuations(asset.Id)
+	if gininfra.HandleAPIError(context, "Error getting asset valuations", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToAssetValuationDTSs(valuations))
+}
+
+// postAssetValuation handles POST requests recording a manual valuation of an asset. A valuation on
+// an already valued date replaces the previous one.
+func (controller *AssetRESTController) postAssetValuation(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var valuationDTS model.AssetValuationDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &valuationDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindAssetValuationErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var valuation = model.MapToAssetValuation(asset.Id, &valuationDTS)
+	persistedValuation, err := controller.assetManagementAppService.RecordAssetValuation(
+		auditContext(context),
+		asset,
+		valuation,
+	)
+	if gininfra.HandleAPIError(context, "Error recording asset valuation", err) {
+		return
+	}
+
+	if persistedValuation == nil {
+		gininfra.SendDataNotFoundResponse(context, "Asset", strconv.FormatInt(asset.Id, 10))
+		return
+	}
+
+	context.JSON(http.StatusCreated, model.MapToAssetValuationDTS(persistedValuation))
+}
+
+// deleteAssetValuation handles DELETE requests removing a manual valuation from an asset.
+func (controller *AssetRESTController) deleteAssetValuation(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var valuationIdParamValue = context.Param(assetValuationIdParam)
+	valuationId, err := langext.ParseInt64(valuationIdParamValue)
+	if gininfra.HandleAPIError(context, getAssetValuationIdErrorMessage, err) {
+		return
+	}
+
+	deleted, err := controller.assetManagementAppService.DeleteAssetValuation(
+		auditContext(context),
+		asset.Id,
+		valuationId,
+	)
+	if gininfra.HandleAPIError(context, "Error deleting asset valuation", err) {
+		return
+	}
+
+	if !deleted {
+		gininfra.SendDataNotFoundResponse(context, "Asset valuation", valuationIdParamValue)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// getLinkedExternalAssets handles GET requests listing the external assets linked to an asset, from
+// highest to lowest priority.
+func (controller *AssetRESTController) getLinkedExternalAssets(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToLinkedExternalAssetDTSs(asset))
+}
+
+// postLinkedExternalAsset handles POST requests linking an external asset, usually found through
+// /api/external-asset, to an asset as its lowest priority external source.
+func (controller *AssetRESTController) postLinkedExternalAsset(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var externalAssetDTS model.ExternalAssetDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &externalAssetDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindExternalAssetErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var externalAsset = model.MapToExternalAsset(&externalAssetDTS)
+	asset.Version = gininfra.GetIfMatchVersion(context)
+
+	updatedAsset, err := controller.assetManagementAppService.LinkExternalAsset(
+		auditContext(context),
+		asset,
+		*externalAsset,
+	)
+	if isVersionConflict(err) {
+		controller.sendCurrentAsset(context, asset.Id)
+		return
+	}
+	if gininfra.HandleAPIError(context, "Error linking external asset", err) {
+		return
+	}
+
+	if updatedAsset == nil {
+		gininfra.SendDataNotFoundResponse(context, "Asset", strconv.FormatInt(asset.Id, 10))
+		return
+	}
+
+	gininfra.SetVersionETag(context, updatedAsset.Version)
+	context.JSON(http.StatusCreated, model.MapToLinkedExternalAssetDTSs(updatedAsset))
+}
+
+// putLinkedExternalAssetsPriority handles PUT requests reordering the external assets linked to an
+// asset. The request lists every linked external asset from highest to lowest priority.
+func (controller *AssetRESTController) putLinkedExternalAssetsPriority(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var priorityDTS model.ExternalAssetPriorityDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &priorityDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindExternalAssetErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var references = model.MapToExternalAssets(priorityDTS.ExternalAssets)
+	asset.Version = gininfra.GetIfMatchVersion(context)
+
+	updatedAsset, err := controller.assetManagementAppService.PrioritizeExternalAssets(
+		auditContext(context),
+		asset,
+		references,
+	)
+	if isVersionConflict(err) {
+		controller.sendCurrentAsset(context, asset.Id)
+		return
+	}
+	if gininfra.HandleAPIError(context, "Error prioritizing external assets", err) {
+		return
+	}
+
+	if updatedAsset == nil {
+		gininfra.SendDataNotFoundResponse(context, "Asset", strconv.FormatInt(asset.Id, 10))
+		return
+	}
+
+	gininfra.SetVersionETag(context, updatedAsset.Version)
+	context.JSON(http.StatusOK, model.MapToLinkedExternalAssetDTSs(updatedAsset))
+}
+
+// deleteLinkedExternalAsset handles DELETE requests unlinking the external asset identified by the
+// source, ticker and exchangeId query parameters from an asset.
+func (controller *AssetRESTController) deleteLinkedExternalAsset(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var referenceDTS model.ExternalAssetReferenceQueryDTS
+	valid, err := gininfra.BindAndValidateQueryWithInvalidResponse(context, &referenceDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindExternalAssetErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var reference = model.MapExternalAssetReferenceToExternalAsset(&referenceDTS)
+	asset.Version = gininfra.GetIfMatchVersion(context)
+
+	updatedAsset, err := controller.assetManagementAppService.UnlinkExternalAsset(
+		auditContext(context),
+		asset,
+		reference,
+	)
+	if isVersionConflict(err) {
+		controller.sendCurrentAsset(context, asset.Id)
+		return
+	}
+	if gininfra.HandleAPIError(context, "Error unlinking external asset", err) {
+		return
+	}
+
+	if updatedAsset == nil {
+		gininfra.SendDataNotFoundResponse(context, "External asset", reference.String())
+		return
+	}
+
+	gininfra.SetVersionETag(context, updatedAsset.Version)
+	context.Status(http.StatusNoContent)
+}
+
+// getAssetQuote handles GET requests quoting the last close price of an asset through its linked
+// external assets, in priority order.
+func (controller *AssetRESTController) getAssetQuote(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	quote, err := controller.assetDomService.QuoteAssetLastClosePrice(asset)
+	if gininfra.HandleAPIError(context, "Error quoting asset", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToAssetQuoteDTS(quote))
+}
+
+// getAssetEvents handles GET requests listing the recorded dividend and split events of an asset, ordered
+// by date.
+func (controller *AssetRESTController) getAssetEvents(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	events, err := controller.assetDomService.GetAssetEvents(asset.Id)
+	if gininfra.HandleAPIError(context, "Error getting asset events", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToAssetEventDTSs(events))
+}
+
+// postAssetEventsImport handles POST requests importing the dividend and split history of an asset from
+// its linked external assets, responding with every event recorded for the asset.
+func (controller *AssetRESTController) postAssetEventsImport(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	events, err := controller.assetDomService.ImportAssetEvents(context.Request.Context(), asset)
+	if gininfra.HandleAPIError(context, "Error importing asset events", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToAssetEventDTSs(events))
+}
+
+func BuildAssetRESTController(
+	assetDomService *service.AssetDomService,
+	assetManagementAppService *application.AssetManagementAppService,
+) *AssetRESTController {
 	return &AssetRESTController{
-		assetDomService: assetDomService,
+		assetDomService:           assetDomService,
+		assetManagementAppService: assetManagementAppService,
 	}
 }
 
@@ -147,3 +705,8 @@ func (controller *AssetRESTController) getExternalAssets(context *gin.Context) {
 	var externalAssetDTSs = model.MapToExternalAssetDTSs(externalAssets)
 	context.JSON(http.StatusOK, externalAssetDTSs)
 }
+
+func (controller *AssetRESTController) getExternalAssetCacheMetrics(context *gin.Context) {
+	var metrics = controller.assetDomService.GetIntegrationCacheMetrics()
+	context.JSON(http.StatusOK, model.MapToAssetIntegrationCacheMetricsDTSs(metrics))
+}
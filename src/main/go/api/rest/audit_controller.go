@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+)
+
+// AuditRESTController exposes the append-only audit trail of the changes to portfolios, observations,
+// allocations, allocation plans and assets.
+type AuditRESTController struct {
+	auditDomService *service.AuditDomService
+}
+
+func (controller *AuditRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/audit",
+			Handlers: gin.HandlersChain{controller.getAuditEntries},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the audit trail of the changes to the data",
+				Query:        model.AuditQueryDTS{},
+				ResponseBody: []*model.AuditEntryDTS{},
+			},
+		},
+	}
+}
+
+// getAuditEntries handles GET requests listing the audit entries, newest first, optionally filtered by
+// entity, portfolio, actor and time range. With authentication enabled, the entries of portfolios are
+// restricted to the ones the principal can access.
+func (controller *AuditRESTController) getAuditEntries(context *gin.Context) {
+
+	var queryDTS model.AuditQueryDTS
+	valid, err := gininfra.BindAndValidateQueryWithInvalidResponse(context, &queryDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindAuditQueryErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var filter = model.MapToAuditFilter(&queryDTS)
+	if principal := findPrincipal(context); principal != nil && principal.User != nil {
+		filter.AccessibleByUserId = &principal.User.Id
+	}
+
+	entries, err := controller.auditDomService.GetAuditEntries(filter)
+	if gininfra.HandleAPIError(context, "Error getting audit entries", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToAuditEntryDTSs(entries))
+}
+
+func BuildAuditRESTController(auditDomService *service.AuditDomService) *AuditRESTController {
+	return &AuditRESTController{auditDomService: auditDomService}
+}
@@ -0,0 +1,299 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+// AuthRESTController handles the login and logout of the web UI sessions, and the management of the users
+// and of their personal API tokens. Users and tokens are only managed through sessions, so an API token
+// cannot create other credentials.
+type AuthRESTController struct {
+	authDomService *service.AuthDomService
+	config         infra.AuthConfiguration
+}
+
+func (controller *AuthRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/auth/login",
+			Handlers:        gin.HandlersChain{controller.postLogin},
+			Unauthenticated: true,
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Log in, starting a web UI session",
+				RequestBody:  model.LoginDTS{},
+				ResponseBody: model.UserDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/logout",
+			Handlers: gin.HandlersChain{controller.postLogout},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Log out, ending the web UI session",
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/me",
+			Handlers: gin.HandlersChain{controller.getCurrentUser},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get the authenticated user",
+				ResponseBody: model.UserDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPut,
+			Path:     "/api/auth/me/password",
+			Handlers: gin.HandlersChain{requireSession, controller.putPassword},
+			Doc: infra.RESTRouteDoc{
+				Summary:     "Change the password of the session user",
+				RequestBody: model.PasswordChangeDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/user",
+			Handlers: gin.HandlersChain{requireSession, controller.postUser},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Create a user",
+				RequestBody:    model.UserDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.UserDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/token",
+			Handlers: gin.HandlersChain{requireSession, controller.getAPITokens},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the API tokens of the session user",
+				ResponseBody: []*model.APITokenDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/token",
+			Handlers: gin.HandlersChain{requireSession, controller.postAPIToken},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Create an API token of the session user",
+				RequestBody:    model.APITokenDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.APITokenDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/auth/token/:" + apiTokenIdParam,
+			Handlers: gin.HandlersChain{requireSession, controller.deleteAPIToken},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Revoke an API token of the session user",
+			},
+		},
+	}
+}
+
+// postLogin handles POST requests logging a user in, starting a web UI session kept in the session cookie.
+func (controller *AuthRESTController) postLogin(context *gin.Context) {
+
+	var loginDTS model.LoginDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &loginDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindLoginErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	user, sessionToken, expiresAt, err := controller.authDomService.Login(loginDTS.Username, loginDTS.Password)
+	if gininfra.HandleAPIError(context, "Error logging in", err) {
+		return
+	}
+
+	if user == nil {
+		gininfra.SendProblemResponse(
+			context,
+			http.StatusUnauthorized,
+			gininfra.HTTPStatusProblemType(http.StatusUnauthorized),
+			"Invalid username or password",
+		)
+		return
+	}
+
+	controller.setSessionCookie(context, sessionToken, int(time.Until(expiresAt).Seconds()))
+	context.JSON(http.StatusOK, model.MapToUserDTS(user))
+}
+
+// postLogout handles POST requests ending the web UI session of the request, if any.
+func (controller *AuthRESTController) postLogout(context *gin.Context) {
+
+	sessionToken, err := context.Cookie(controller.config.SessionCookieName)
+	if err == nil && sessionToken != "" {
+		err = controller.authDomService.Logout(sessionToken)
+		if gininfra.HandleAPIError(context, "Error logging out", err) {
+			return
+		}
+	}
+
+	controller.setSessionCookie(context, "", -1)
+	context.Status(http.StatusNoContent)
+}
+
+// getCurrentUser handles GET requests for the authenticated user.
+func (controller *AuthRESTController) getCurrentUser(context *gin.Context) {
+	context.JSON(http.StatusOK, model.MapToUserDTS(getPrincipal(context).User))
+}
+
+// putPassword handles PUT requests changing the password of the authenticated user, which ends all of
+// their sessions.
+func (controller *AuthRESTController) putPassword(context *gin.Context) {
+
+	var passwordChangeDTS model.PasswordChangeDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &passwordChangeDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindPasswordChangeErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	err = controller.authDomService.ChangePassword(
+		getPrincipal(context).User.Id,
+		passwordChangeDTS.CurrentPassword,
+		passwordChangeDTS.NewPassword,
+	)
+	if gininfra.HandleAPIError(context, "Error changing password", err) {
+		return
+	}
+
+	controller.setSessionCookie(context, "", -1)
+	context.Status(http.StatusNoContent)
+}
+
+// postUser handles POST requests creating a local user.
+func (controller *AuthRESTController) postUser(context *gin.Context) {
+
+	var userDTS model.UserDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &userDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindUserErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	user, err := controller.authDomService.CreateUser(userDTS.Username, userDTS.Password)
+	if gininfra.HandleAPIError(context, "Error creating user", err) {
+		return
+	}
+
+	context.JSON(http.StatusCreated, model.MapToUserDTS(user))
+}
+
+// getAPITokens handles GET requests listing the API tokens of the authenticated user.
+func (controller *AuthRESTController) getAPITokens(context *gin.Context) {
+
+	tokens, err := controller.authDomService.GetUserAPITokens(getPrincipal(context).User.Id)
+	if gininfra.HandleAPIError(context, "Error getting API tokens", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToAPITokenDTSs(tokens))
+}
+
+// postAPIToken handles POST requests creating an API token of the authenticated user. The response is the
+// only one holding the token itself.
+func (controller *AuthRESTController) postAPIToken(context *gin.Context) {
+
+	var tokenDTS model.APITokenDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &tokenDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindAPITokenErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	token, rawToken, err := controller.authDomService.CreateAPIToken(
+		getPrincipal(context).User.Id,
+		tokenDTS.Name,
+		model.MapToAPITokenScopes(&tokenDTS),
+		tokenDTS.ExpiresAt,
+	)
+	if gininfra.HandleAPIError(context, "Error creating API token", err) {
+		return
+	}
+
+	var createdTokenDTS = model.MapToAPITokenDTS(token)
+	createdTokenDTS.Token = rawToken
+	context.JSON(http.StatusCreated, createdTokenDTS)
+}
+
+// deleteAPIToken handles DELETE requests revoking an API token of the authenticated user.
+func (controller *AuthRESTController) deleteAPIToken(context *gin.Context) {
+
+	tokenId, err := langext.ParseInt64(context.Param(apiTokenIdParam))
+	if gininfra.HandleAPIError(context, getAPITokenIdErrorMessage, err) {
+		return
+	}
+
+	revoked, err := controller.authDomService.RevokeAPIToken(getPrincipal(context).User.Id, tokenId)
+	if gininfra.HandleAPIError(context, "Error revoking API token", err) {
+		return
+	}
+
+	if !revoked {
+		gininfra.SendDataNotFoundResponse(context, "API token", context.Param(apiTokenIdParam))
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// setSessionCookie sets the session cookie, which is never readable by scripts nor sent on cross-site
+// requests. A negative maxAge deletes it.
+func (controller *AuthRESTController) setSessionCookie(context *gin.Context, sessionToken string, maxAge int) {
+	context.SetSameSite(http.SameSiteStrictMode)
+	context.SetCookie(
+		controller.config.SessionCookieName,
+		sessionToken,
+		maxAge,
+		"/",
+		"",
+		controller.config.SecureCookie,
+		true,
+	)
+}
+
+// requireSession rejects the requests authenticated through API tokens.
+func requireSession(context *gin.Context) {
+
+	if !getPrincipal(context).IsSession() {
+		gininfra.AbortWithProblemResponse(
+			context,
+			http.StatusForbidden,
+			gininfra.HTTPStatusProblemType(http.StatusForbidden),
+			"Users and API tokens can only be managed through a web UI session",
+		)
+		return
+	}
+
+	context.Next()
+}
+
+func BuildAuthRESTController(authDomService *service.AuthDomService, config infra.AuthConfiguration) *AuthRESTController {
+	return &AuthRESTController{authDomService: authDomService, config: config}
+}
@@ -0,0 +1,255 @@
+package rest
+
+import (
+	stdcontext "context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	principalContextKey = "authenticatedPrincipal"
+	bearerAuthPrefix    = "Bearer "
+	shareTokenHeader    = "X-Share-Token"
+	shareTokenQuery     = "shareToken"
+)
+
+// shareLinkRoutes are the portfolio data routes readable through share links: the portfolio, its
+// allocations and history, its allocation plans and their divergence analysis. Any other route of the
+// portfolio is reported as not found to share link principals.
+var shareLinkRoutes = map[string]bool{
+	"/api/portfolio/:" + portfolioIdParam:                                      true,
+	"/api/portfolio/:" + portfolioIdParam + "/allocation-classes":              true,
+	"/api/portfolio/:" + portfolioIdParam + "/history":                         true,
+	"/api/portfolio/:" + portfolioIdParam + "/history/observation":             true,
+	"/api/portfolio/:" + portfolioIdParam + "/allocation-plan":                 true,
+	"/api/portfolio/:" + portfolioIdParam + "/allocation-plan/:" + planIdParam: true,
+	"/api/portfolio/:" + portfolioIdParam + "/divergence/options":              true,
+	"/api/v2/portfolio/:" + portfolioIdParam + "/divergence/:" + observationTimestampIdParam +
+		"/allocation-plan/:" + planIdParam: true,
+}
+
+// AuthenticationMiddleware authenticates the API requests by the API token in their Authorization header
+// (Authorization: Bearer oaa_...), or otherwise by the web UI session in their session cookie. Requests
+// changing data through API tokens require the WRITE scope.
+//
+// Requests of portfolio routes are also authorized against the role of the caller on the portfolio:
+// VIEWER to read and EDITOR to change data. Portfolios the caller has no access to are reported as not
+// found. Reads of the portfolio data routes can also be authenticated by a read-only share link token, in
+// the X-Share-Token header or the shareToken query parameter.
+type AuthenticationMiddleware struct {
+	authDomService            *service.AuthDomService
+	portfolioAccessDomService *service.PortfolioAccessDomService
+	config                    infra.AuthConfiguration
+}
+
+func (middleware *AuthenticationMiddleware) Handle(context *gin.Context) {
+
+	var principal, err = middleware.authenticate(context)
+	if err != nil {
+		gininfra.HandleAPIError(context, "Error authenticating request", err)
+		context.Abort()
+		return
+	}
+
+	if principal == nil && isSafeMethod(context.Request.Method) && context.Param(portfolioIdParam) != "" {
+		principal, err = middleware.authenticateShareLink(context)
+		if err != nil {
+			gininfra.HandleAPIError(context, "Error authenticating request", err)
+			context.Abort()
+			return
+		}
+	}
+
+	if principal == nil {
+		gininfra.AbortWithProblemResponse(
+			context,
+			http.StatusUnauthorized,
+			gininfra.HTTPStatusProblemType(http.StatusUnauthorized),
+			"Authentication required",
+		)
+		return
+	}
+
+	if !isSafeMethod(context.Request.Method) && !principal.CanWrite() {
+		gininfra.AbortWithProblemResponse(
+			context,
+			http.StatusForbidden,
+			gininfra.HTTPStatusProblemType(http.StatusForbidden),
+			"API token requires the WRITE scope to change data",
+		)
+		return
+	}
+
+	if !authorizeShareLinkRoute(context, principal) {
+		return
+	}
+
+	context.Set(principalContextKey, principal)
+
+	if context.Param(portfolioIdParam) != "" && !middleware.authorizePortfolio(context, principal) {
+		return
+	}
+
+	context.Next()
+}
+
+func (middleware *AuthenticationMiddleware) authenticateShareLink(context *gin.Context) (*domain.Principal, error) {
+
+	var shareToken = context.GetHeader(shareTokenHeader)
+	if shareToken == "" {
+		shareToken = context.Query(shareTokenQuery)
+	}
+	if shareToken == "" {
+		return nil, nil
+	}
+
+	return middleware.portfolioAccessDomService.AuthenticateShareLink(shareToken)
+}
+
+// authorizeShareLinkRoute restricts share link principals to the portfolio data routes, aborting requests
+// of any other route as not found.
+func authorizeShareLinkRoute(context *gin.Context, principal *domain.Principal) bool {
+
+	if !principal.IsShareLink() || shareLinkRoutes[context.FullPath()] {
+		return true
+	}
+
+	gininfra.SendDataNotFoundResponse(context, "Portfolio", context.Param(portfolioIdParam))
+	context.Abort()
+	return false
+}
+
+// authorizePortfolio checks the role of the principal on the portfolio of the request, aborting it when
+// the role is not enough. Invalid portfolio ids are left for the route handlers to report.
+func (middleware *AuthenticationMiddleware) authorizePortfolio(
+	context *gin.Context,
+	principal *domain.Principal,
+) bool {
+
+	var portfolioIdParamValue = context.Param(portfolioIdParam)
+	portfolioId, err := langext.ParseInt64(portfolioIdParamValue)
+	if err != nil {
+		return true
+	}
+
+	var requiredRole = domain.ViewerPortfolioRole
+	if !isSafeMethod(context.Request.Method) {
+		requiredRole = domain.EditorPortfolioRole
+	}
+
+	return authorizePortfolioRole(context, middleware.portfolioAccessDomService, principal, portfolioId, requiredRole)
+}
+
+// authorizePortfolioRole checks that the principal has at least the required role on a portfolio,
+// aborting the request otherwise. Portfolios the principal cannot access at all are reported as not
+// found, so their existence is not revealed.
+func authorizePortfolioRole(
+	context *gin.Context,
+	portfolioAccessDomService *service.PortfolioAccessDomService,
+	principal *domain.Principal,
+	portfolioId int64,
+	requiredRole domain.PortfolioRole,
+) bool {
+
+	role, err := portfolioAccessDomService.GetPrincipalPortfolioRole(principal, portfolioId)
+	if err != nil {
+		gininfra.HandleAPIError(context, "Error authorizing portfolio access", err)
+		context.Abort()
+		return false
+	}
+
+	if role == "" {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio", strconv.FormatInt(portfolioId, 10))
+		context.Abort()
+		return false
+	}
+
+	if !role.Includes(requiredRole) {
+		gininfra.AbortWithProblemResponse(
+			context,
+			http.StatusForbidden,
+			gininfra.HTTPStatusProblemType(http.StatusForbidden),
+			fmt.Sprintf("Portfolio requires the %s role", requiredRole),
+		)
+		return false
+	}
+
+	return true
+}
+
+func (middleware *AuthenticationMiddleware) authenticate(context *gin.Context) (*domain.Principal, error) {
+
+	var authorization = context.GetHeader("Authorization")
+	if authorization != "" {
+		if !strings.HasPrefix(authorization, bearerAuthPrefix) {
+			return nil, nil
+		}
+		return middleware.authDomService.AuthenticateAPIToken(strings.TrimPrefix(authorization, bearerAuthPrefix))
+	}
+
+	sessionToken, err := context.Cookie(middleware.config.SessionCookieName)
+	if err != nil || sessionToken == "" {
+		return nil, nil
+	}
+
+	return middleware.authDomService.AuthenticateSession(sessionToken)
+}
+
+// getPrincipal returns the principal authenticated by the AuthenticationMiddleware for the request.
+func getPrincipal(context *gin.Context) *domain.Principal {
+	var principal, _ = context.Get(principalContextKey)
+	return principal.(*domain.Principal)
+}
+
+// findPrincipal returns the principal authenticated by the AuthenticationMiddleware for the request, or nil
+// when authentication is disabled.
+func findPrincipal(context *gin.Context) *domain.Principal {
+	var principal, _ = context.Get(principalContextKey)
+	authenticatedPrincipal, _ := principal.(*domain.Principal)
+	return authenticatedPrincipal
+}
+
+// auditContext returns the context of the request carrying the user of its principal as the actor of the
+// audited changes, or the bare request context when authentication is disabled.
+func auditContext(context *gin.Context) stdcontext.Context {
+
+	var principal = findPrincipal(context)
+	if principal == nil || principal.User == nil {
+		return context.Request.Context()
+	}
+
+	return domain.WithAuditActor(
+		context.Request.Context(),
+		&domain.AuditActor{UserId: principal.User.Id, Username: principal.User.Username},
+	)
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func BuildAuthenticationMiddleware(
+	authDomService *service.AuthDomService,
+	portfolioAccessDomService *service.PortfolioAccessDomService,
+	config infra.AuthConfiguration,
+) *AuthenticationMiddleware {
+	return &AuthenticationMiddleware{
+		authDomService:            authDomService,
+		portfolioAccessDomService: portfolioAccessDomService,
+		config:                    config,
+	}
+}
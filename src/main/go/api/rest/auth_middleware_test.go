@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+)
+
+func TestAuthorizeShareLinkRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var testCases = []struct {
+		name           string
+		routePath      string
+		requestPath    string
+		principal      *domain.Principal
+		expectedStatus int
+	}{
+		{
+			"share link reading the history",
+			"/api/portfolio/:" + portfolioIdParam + "/history",
+			"/api/portfolio/1/history",
+			&domain.Principal{ShareLink: &domain.PortfolioShareLink{PortfolioId: 1}},
+			http.StatusOK,
+		},
+		{
+			"share link reading an allocation plan",
+			"/api/portfolio/:" + portfolioIdParam + "/allocation-plan/:" + planIdParam,
+			"/api/portfolio/1/allocation-plan/2",
+			&domain.Principal{ShareLink: &domain.PortfolioShareLink{PortfolioId: 1}},
+			http.StatusOK,
+		},
+		{
+			"share link reading the webhooks",
+			"/api/portfolio/:" + portfolioIdParam + "/webhook",
+			"/api/portfolio/1/webhook",
+			&domain.Principal{ShareLink: &domain.PortfolioShareLink{PortfolioId: 1}},
+			http.StatusNotFound,
+		},
+		{
+			"share link reading the schedule runs",
+			"/api/portfolio/:" + portfolioIdParam + "/schedule/:" + scheduleIdParam + "/run",
+			"/api/portfolio/1/schedule/2/run",
+			&domain.Principal{ShareLink: &domain.PortfolioShareLink{PortfolioId: 1}},
+			http.StatusNotFound,
+		},
+		{
+			"share link reading the alert rules",
+			"/api/portfolio/:" + portfolioIdParam + "/alert-rule",
+			"/api/portfolio/1/alert-rule",
+			&domain.Principal{ShareLink: &domain.PortfolioShareLink{PortfolioId: 1}},
+			http.StatusNotFound,
+		},
+		{
+			"session reading the webhooks",
+			"/api/portfolio/:" + portfolioIdParam + "/webhook",
+			"/api/portfolio/1/webhook",
+			&domain.Principal{User: &domain.User{Id: 1}},
+			http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			var router = gin.New()
+			router.GET(testCase.routePath, func(context *gin.Context) {
+				if authorizeShareLinkRoute(context, testCase.principal) {
+					context.Status(http.StatusOK)
+				}
+			})
+
+			var recorder = httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testCase.requestPath, nil))
+
+			assert.Equal(t, testCase.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -5,6 +5,17 @@ const (
 	observationTimestampIdParam           = "observationTimestampId"
 	planIdParam                           = "planId"
 	assetIdOrTickerParam                  = "assetIdOrTicker"
+	assetAliasIdParam                     = "aliasId"
+	assetValuationIdParam                 = "valuationId"
+	corporateActionIdParam                = "corporateActionId"
+	jobIdParam                            = "jobId"
+	scheduleIdParam                       = "scheduleId"
+	webhookIdParam                        = "webhookId"
+	alertRuleIdParam                      = "alertRuleId"
+	alertIdParam                          = "alertId"
+	apiTokenIdParam                       = "tokenId"
+	grantUserIdParam                      = "userId"
+	shareLinkIdParam                      = "shareLinkId"
 	externalAssetQueryParam               = "query"
 	externalAssetSourceParam              = "externalAssetSource"
 	getPortfolioIdErrorMessage            = "Error getting portfolioId url parameter"
@@ -13,4 +24,35 @@ const (
 	bindPortfolioErrorMessage             = "Error binding portfolio from request body"
 	bindPortfolioSnapshotErrorMessage     = "Error binding portfolio snapshot from request body"
 	bindAssetErrorMessage                 = "Error binding asset from request body"
+	bindAssetAliasErrorMessage            = "Error binding asset alias from request body"
+	getAssetAliasIdErrorMessage           = "Error getting aliasId url parameter"
+	bindAssetValuationErrorMessage        = "Error binding asset valuation from request body"
+	getAssetValuationIdErrorMessage       = "Error getting valuationId url parameter"
+	bindExternalAssetErrorMessage         = "Error binding external asset from request"
+	bindPortfolioHistoryQueryErrorMessage = "Error binding portfolio history query parameters"
+	bindCorporateActionErrorMessage       = "Error binding corporate action from request body"
+	getCorporateActionIdErrorMessage      = "Error getting corporateActionId url parameter"
+	getJobIdErrorMessage                  = "Error getting jobId url parameter"
+	bindJobErrorMessage                   = "Error binding job from request body"
+	bindJobQueryErrorMessage              = "Error binding job query parameters"
+	getScheduleIdErrorMessage             = "Error getting scheduleId url parameter"
+	bindPortfolioScheduleErrorMessage     = "Error binding portfolio schedule from request body"
+	bindScheduleRunQueryErrorMessage      = "Error binding schedule run query parameters"
+	getWebhookIdErrorMessage              = "Error getting webhookId url parameter"
+	bindPortfolioWebhookErrorMessage      = "Error binding portfolio webhook from request body"
+	bindWebhookDeliveryQueryErrorMessage  = "Error binding webhook delivery query parameters"
+	getAlertRuleIdErrorMessage            = "Error getting alertRuleId url parameter"
+	bindDivergenceAlertRuleErrorMessage   = "Error binding divergence alert rule from request body"
+	getAlertIdErrorMessage                = "Error getting alertId url parameter"
+	bindDivergenceAlertQueryErrorMessage  = "Error binding divergence alert query parameters"
+	bindLoginErrorMessage                 = "Error binding login from request body"
+	bindPasswordChangeErrorMessage        = "Error binding password change from request body"
+	bindUserErrorMessage                  = "Error binding user from request body"
+	bindAPITokenErrorMessage              = "Error binding API token from request body"
+	getAPITokenIdErrorMessage             = "Error getting tokenId url parameter"
+	bindPortfolioGrantErrorMessage        = "Error binding portfolio grant from request body"
+	getGrantUserIdErrorMessage            = "Error getting userId url parameter"
+	bindPortfolioShareLinkErrorMessage    = "Error binding portfolio share link from request body"
+	getShareLinkIdErrorMessage            = "Error getting shareLinkId url parameter"
+	bindAuditQueryErrorMessage            = "Error binding audit query parameters"
 )
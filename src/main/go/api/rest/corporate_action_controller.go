@@ -0,0 +1,227 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/application"
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+type CorporateActionRESTController struct {
+	assetDomService                     *service.AssetDomService
+	corporateActionDomService           *service.CorporateActionDomService
+	corporateActionManagementAppService *application.CorporateActionManagementAppService
+}
+
+func (controller *CorporateActionRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/corporate-action",
+			Handlers: gin.HandlersChain{controller.getAssetCorporateActions},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the corporate actions of an asset",
+				ResponseBody: []*model.CorporateActionDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/corporate-action",
+			Handlers: gin.HandlersChain{controller.postAssetCorporateAction},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Register a corporate action of an asset",
+				RequestBody:    model.CorporateActionDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.CorporateActionDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/corporate-action/:" + corporateActionIdParam,
+			Handlers: gin.HandlersChain{controller.deleteAssetCorporateAction},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Delete a corporate action of an asset that is not applied",
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/corporate-action/:" + corporateActionIdParam + "/apply",
+			Handlers: gin.HandlersChain{controller.postCorporateActionApply},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Apply a corporate action to the observations of its asset",
+				ResponseStatus: http.StatusOK,
+				ResponseBody:   model.CorporateActionDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/asset/:" + assetIdOrTickerParam + "/corporate-action/:" + corporateActionIdParam + "/revert",
+			Handlers: gin.HandlersChain{controller.postCorporateActionRevert},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Revert a corporate action applied to the observations of its asset",
+				ResponseStatus: http.StatusOK,
+				ResponseBody:   model.CorporateActionDTS{},
+			},
+		},
+	}
+}
+
+// getAssetCorporateActions handles GET requests listing the corporate actions of an asset, ordered by
+// effective date.
+func (controller *CorporateActionRESTController) getAssetCorporateActions(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	actions, err := controller.corporateActionDomService.GetAssetCorporateActions(asset.Id)
+	if gininfra.HandleAPIError(context, "Error getting corporate actions", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToCorporateActionDTSs(actions))
+}
+
+// postAssetCorporateAction handles POST requests registering a new pending corporate action of an
+// asset.
+func (controller *CorporateActionRESTController) postAssetCorporateAction(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var actionDTS model.CorporateActionDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &actionDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindCorporateActionErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var action = model.MapToCorporateAction(asset.Id, &actionDTS)
+	persistedAction, err := controller.corporateActionDomService.InsertCorporateAction(action)
+	if gininfra.HandleAPIError(context, "Error inserting corporate action", err) {
+		return
+	}
+
+	context.JSON(http.StatusCreated, model.MapToCorporateActionDTS(persistedAction))
+}
+
+// deleteAssetCorporateAction handles DELETE requests removing a corporate action that is not applied.
+func (controller *CorporateActionRESTController) deleteAssetCorporateAction(context *gin.Context) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return
+	}
+
+	var corporateActionIdParamValue = context.Param(corporateActionIdParam)
+	corporateActionId, err := langext.ParseInt64(corporateActionIdParamValue)
+	if gininfra.HandleAPIError(context, getCorporateActionIdErrorMessage, err) {
+		return
+	}
+
+	deleted, err := controller.corporateActionDomService.DeleteCorporateAction(asset.Id, corporateActionId)
+	if gininfra.HandleAPIError(context, "Error deleting corporate action", err) {
+		return
+	}
+
+	if !deleted {
+		gininfra.SendDataNotFoundResponse(context, "Corporate action", corporateActionIdParamValue)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// postCorporateActionApply handles POST requests applying a corporate action to the portfolio
+// allocations, planned allocations and aliases of its asset.
+func (controller *CorporateActionRESTController) postCorporateActionApply(context *gin.Context) {
+
+	action, found := controller.findCorporateActionFromParams(context)
+	if !found {
+		return
+	}
+
+	appliedAction, err := controller.corporateActionManagementAppService.ApplyCorporateAction(
+		auditContext(context),
+		action.Id,
+	)
+	if gininfra.HandleAPIError(context, "Error applying corporate action", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToCorporateActionDTS(appliedAction))
+}
+
+// postCorporateActionRevert handles POST requests reverting an applied corporate action.
+func (controller *CorporateActionRESTController) postCorporateActionRevert(context *gin.Context) {
+
+	action, found := controller.findCorporateActionFromParams(context)
+	if !found {
+		return
+	}
+
+	revertedAction, err := controller.corporateActionManagementAppService.RevertCorporateAction(
+		auditContext(context),
+		action.Id,
+	)
+	if gininfra.HandleAPIError(context, "Error reverting corporate action", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToCorporateActionDTS(revertedAction))
+}
+
+// findCorporateActionFromParams resolves the corporate action referenced by the URL parameters,
+// sending the error or not found response when the asset or the action cannot be resolved.
+func (controller *CorporateActionRESTController) findCorporateActionFromParams(
+	context *gin.Context,
+) (*domain.CorporateAction, bool) {
+
+	asset, found := findAssetFromParam(context, controller.assetDomService)
+	if !found {
+		return nil, false
+	}
+
+	var corporateActionIdParamValue = context.Param(corporateActionIdParam)
+	corporateActionId, err := langext.ParseInt64(corporateActionIdParamValue)
+	if gininfra.HandleAPIError(context, getCorporateActionIdErrorMessage, err) {
+		return nil, false
+	}
+
+	action, err := controller.corporateActionDomService.GetAssetCorporateAction(asset.Id, corporateActionId)
+	if gininfra.HandleAPIError(context, "Error getting corporate action", err) {
+		return nil, false
+	}
+
+	if action == nil {
+		gininfra.SendDataNotFoundResponse(context, "Corporate action", corporateActionIdParamValue)
+		return nil, false
+	}
+
+	return action, true
+}
+
+func BuildCorporateActionRESTController(
+	assetDomService *service.AssetDomService,
+	corporateActionDomService *service.CorporateActionDomService,
+	corporateActionManagementAppService *application.CorporateActionManagementAppService,
+) *CorporateActionRESTController {
+	return &CorporateActionRESTController{
+		assetDomService:                     assetDomService,
+		corporateActionDomService:           corporateActionDomService,
+		corporateActionManagementAppService: corporateActionManagementAppService,
+	}
+}
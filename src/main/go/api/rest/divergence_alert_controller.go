@@ -0,0 +1,356 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const defaultDivergenceAlertsLimit = 50
+
+type DivergenceAlertRESTController struct {
+	divergenceAlertDomService *service.DivergenceAlertDomService
+}
+
+func (controller *DivergenceAlertRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert-rule",
+			Handlers: gin.HandlersChain{controller.getAlertRules},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the divergence alert rules of the portfolio",
+				ResponseBody: []*model.DivergenceAlertRuleDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert-rule",
+			Handlers: gin.HandlersChain{controller.postAlertRule},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Create a divergence alert rule of the portfolio",
+				RequestBody:    model.DivergenceAlertRuleDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.DivergenceAlertRuleDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert-rule/:" + alertRuleIdParam,
+			Handlers: gin.HandlersChain{controller.getAlertRule},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get a divergence alert rule of the portfolio",
+				ResponseBody: model.DivergenceAlertRuleDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPut,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert-rule/:" + alertRuleIdParam,
+			Handlers: gin.HandlersChain{controller.putAlertRule},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Update a divergence alert rule of the portfolio",
+				RequestBody:  model.DivergenceAlertRuleDTS{},
+				ResponseBody: model.DivergenceAlertRuleDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert-rule/:" + alertRuleIdParam,
+			Handlers: gin.HandlersChain{controller.deleteAlertRule},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Delete a divergence alert rule of the portfolio",
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert",
+			Handlers: gin.HandlersChain{controller.getAlerts},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the divergence alerts of the portfolio",
+				Query:        model.DivergenceAlertQueryDTS{},
+				ResponseBody: []*model.DivergenceAlertDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert/:" + alertIdParam,
+			Handlers: gin.HandlersChain{controller.getAlert},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get a divergence alert of the portfolio",
+				ResponseBody: model.DivergenceAlertDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert/:" + alertIdParam + "/acknowledge",
+			Handlers: gin.HandlersChain{controller.postAlertAcknowledgement},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Acknowledge a divergence alert of the portfolio",
+				ResponseStatus: http.StatusOK,
+				ResponseBody:   model.DivergenceAlertDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/alert/:" + alertIdParam + "/resolve",
+			Handlers: gin.HandlersChain{controller.postAlertResolution},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Resolve a divergence alert of the portfolio",
+				ResponseStatus: http.StatusOK,
+				ResponseBody:   model.DivergenceAlertDTS{},
+			},
+		},
+	}
+}
+
+// getAlertRules handles GET requests listing the divergence alert rules of a portfolio.
+func (controller *DivergenceAlertRESTController) getAlertRules(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	rules, err := controller.divergenceAlertDomService.GetPortfolioAlertRules(portfolioId)
+	if gininfra.HandleAPIError(context, "Error getting divergence alert rules", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToDivergenceAlertRuleDTSs(rules))
+}
+
+// postAlertRule handles POST requests creating a divergence alert rule of a portfolio. The rule is first
+// evaluated on the next merge or price refresh of the portfolio.
+func (controller *DivergenceAlertRESTController) postAlertRule(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	var ruleDTS model.DivergenceAlertRuleDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &ruleDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindDivergenceAlertRuleErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var rule = model.MapToDivergenceAlertRule(portfolioId, &ruleDTS)
+	persistedRule, err := controller.divergenceAlertDomService.InsertAlertRule(rule)
+	if gininfra.HandleAPIError(context, "Error inserting divergence alert rule", err) {
+		return
+	}
+
+	context.JSON(http.StatusCreated, model.MapToDivergenceAlertRuleDTS(persistedRule))
+}
+
+// getAlertRule handles GET requests for a divergence alert rule of a portfolio.
+func (controller *DivergenceAlertRESTController) getAlertRule(context *gin.Context) {
+
+	portfolioId, ruleId, ok := getPortfolioChildIdParams(context, alertRuleIdParam, getAlertRuleIdErrorMessage)
+	if !ok {
+		return
+	}
+
+	rule, err := controller.divergenceAlertDomService.GetPortfolioAlertRule(portfolioId, ruleId)
+	if gininfra.HandleAPIError(context, "Error getting divergence alert rule", err) {
+		return
+	}
+
+	if rule == nil {
+		gininfra.SendDataNotFoundResponse(context, "Divergence alert rule", context.Param(alertRuleIdParam))
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToDivergenceAlertRuleDTS(rule))
+}
+
+// putAlertRule handles PUT requests replacing the definition of a divergence alert rule of a portfolio.
+func (controller *DivergenceAlertRESTController) putAlertRule(context *gin.Context) {
+
+	portfolioId, ruleId, ok := getPortfolioChildIdParams(context, alertRuleIdParam, getAlertRuleIdErrorMessage)
+	if !ok {
+		return
+	}
+
+	var ruleDTS model.DivergenceAlertRuleDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &ruleDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindDivergenceAlertRuleErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var rule = model.MapToDivergenceAlertRule(portfolioId, &ruleDTS)
+	rule.Id = ruleId
+	updatedRule, err := controller.divergenceAlertDomService.UpdateAlertRule(rule)
+	if gininfra.HandleAPIError(context, "Error updating divergence alert rule", err) {
+		return
+	}
+
+	if updatedRule == nil {
+		gininfra.SendDataNotFoundResponse(context, "Divergence alert rule", context.Param(alertRuleIdParam))
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToDivergenceAlertRuleDTS(updatedRule))
+}
+
+// deleteAlertRule handles DELETE requests removing a divergence alert rule of a portfolio with its alerts.
+func (controller *DivergenceAlertRESTController) deleteAlertRule(context *gin.Context) {
+
+	portfolioId, ruleId, ok := getPortfolioChildIdParams(context, alertRuleIdParam, getAlertRuleIdErrorMessage)
+	if !ok {
+		return
+	}
+
+	deleted, err := controller.divergenceAlertDomService.DeleteAlertRule(portfolioId, ruleId)
+	if gininfra.HandleAPIError(context, "Error deleting divergence alert rule", err) {
+		return
+	}
+
+	if !deleted {
+		gininfra.SendDataNotFoundResponse(context, "Divergence alert rule", context.Param(alertRuleIdParam))
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// getAlerts handles GET requests listing the latest divergence alerts of a portfolio, the most recently
+// triggered first, optionally filtered by status.
+func (controller *DivergenceAlertRESTController) getAlerts(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	var queryDTS model.DivergenceAlertQueryDTS
+	valid, err := gininfra.BindAndValidateQueryWithInvalidResponse(context, &queryDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindDivergenceAlertQueryErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var status *domain.DivergenceAlertStatus
+	if queryDTS.Status != "" {
+		var queryStatus = domain.DivergenceAlertStatus(queryDTS.Status)
+		status = &queryStatus
+	}
+
+	var limit = queryDTS.Limit
+	if limit == 0 {
+		limit = defaultDivergenceAlertsLimit
+	}
+
+	alerts, err := controller.divergenceAlertDomService.GetPortfolioAlerts(portfolioId, status, limit)
+	if gininfra.HandleAPIError(context, "Error getting divergence alerts", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToDivergenceAlertDTSs(alerts))
+}
+
+// getAlert handles GET requests for a divergence alert of a portfolio.
+func (controller *DivergenceAlertRESTController) getAlert(context *gin.Context) {
+
+	portfolioId, alertId, ok := getPortfolioChildIdParams(context, alertIdParam, getAlertIdErrorMessage)
+	if !ok {
+		return
+	}
+
+	alert, err := controller.divergenceAlertDomService.GetPortfolioAlert(portfolioId, alertId)
+	if gininfra.HandleAPIError(context, "Error getting divergence alert", err) {
+		return
+	}
+
+	controller.sendAlertResponse(context, alert)
+}
+
+// postAlertAcknowledgement handles POST requests acknowledging a divergence alert of a portfolio.
+func (controller *DivergenceAlertRESTController) postAlertAcknowledgement(context *gin.Context) {
+
+	portfolioId, alertId, ok := getPortfolioChildIdParams(context, alertIdParam, getAlertIdErrorMessage)
+	if !ok {
+		return
+	}
+
+	alert, err := controller.divergenceAlertDomService.AcknowledgeAlert(portfolioId, alertId)
+	if gininfra.HandleAPIError(context, "Error acknowledging divergence alert", err) {
+		return
+	}
+
+	controller.sendAlertResponse(context, alert)
+}
+
+// postAlertResolution handles POST requests resolving a divergence alert of a portfolio.
+func (controller *DivergenceAlertRESTController) postAlertResolution(context *gin.Context) {
+
+	portfolioId, alertId, ok := getPortfolioChildIdParams(context, alertIdParam, getAlertIdErrorMessage)
+	if !ok {
+		return
+	}
+
+	alert, err := controller.divergenceAlertDomService.ResolveAlert(portfolioId, alertId)
+	if gininfra.HandleAPIError(context, "Error resolving divergence alert", err) {
+		return
+	}
+
+	controller.sendAlertResponse(context, alert)
+}
+
+func (controller *DivergenceAlertRESTController) sendAlertResponse(
+	context *gin.Context,
+	alert *domain.DivergenceAlert,
+) {
+
+	if alert == nil {
+		gininfra.SendDataNotFoundResponse(context, "Divergence alert", context.Param(alertIdParam))
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToDivergenceAlertDTS(alert))
+}
+
+// getPortfolioChildIdParams parses the portfolio id url parameter and the id parameter of a resource nested
+// in the portfolio, handling the response when any of them is invalid.
+func getPortfolioChildIdParams(context *gin.Context, childIdParam string, childIdErrorMessage string) (
+	int64,
+	int64,
+	bool,
+) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return 0, 0, false
+	}
+
+	childId, err := langext.ParseInt64(context.Param(childIdParam))
+	if gininfra.HandleAPIError(context, childIdErrorMessage, err) {
+		return 0, 0, false
+	}
+
+	return portfolioId, childId, true
+}
+
+func BuildDivergenceAlertRESTController(
+	divergenceAlertDomService *service.DivergenceAlertDomService,
+) *DivergenceAlertRESTController {
+	return &DivergenceAlertRESTController{divergenceAlertDomService: divergenceAlertDomService}
+}
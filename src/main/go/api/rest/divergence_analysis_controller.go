@@ -23,6 +23,10 @@ func (controller *DivergenceAnalysisRESTController) BuildRoutes() []infra.RESTRo
 			Method:   http.MethodGet,
 			Path:     "/api/portfolio/:" + portfolioIdParam + "/divergence/options",
 			Handlers: gin.HandlersChain{controller.getDivergenceAnalysisOptions},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the observations and allocation plans the portfolio divergence can be analyzed with",
+				ResponseBody: model.AnalysisOptionsDTS{},
+			},
 		},
 		{
 			Method: http.MethodGet,
@@ -30,6 +34,10 @@ func (controller *DivergenceAnalysisRESTController) BuildRoutes() []infra.RESTRo
 				"/divergence/:" + observationTimestampIdParam +
 				"/allocation-plan/:" + planIdParam,
 			Handlers: gin.HandlersChain{controller.GetDivergenceAnalysis},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Analyze the divergence of a portfolio observation from an allocation plan",
+				ResponseBody: model.DivergenceAnalysisDTS{},
+			},
 		},
 	}
 }
@@ -73,6 +81,7 @@ func (controller *DivergenceAnalysisRESTController) GetDivergenceAnalysis(contex
 	}
 
 	analysis, err := controller.portfolioDivergenceAnalysisService.GeneratePortfolioDivergenceAnalysis(
+		context.Request.Context(),
 		portfolioId,
 		observationTimestampId,
 		planId,
@@ -0,0 +1,211 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+var (
+	idempotencyKeyReusedProblemType        = gininfra.BuildProblemType("idempotency-key-reused", "Idempotency key reused")
+	idempotentRequestInProgressProblemType = gininfra.BuildProblemType(
+		"idempotent-request-in-progress",
+		"Idempotent request in progress",
+	)
+)
+
+// replayedResponseHeaders are the response headers stored with an idempotent response and replayed with it.
+var replayedResponseHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// IdempotencyMiddleware makes the requests sent with an Idempotency-Key header idempotent. The first request
+// with a key is processed and its response stored, to be replayed, with the Idempotent-Replayed header, to
+// every duplicate sent by the same caller until the key expires. Duplicates must be the same request: the
+// same key sent with a different method, path or body is rejected with 422, and a duplicate of a request
+// still being processed is rejected with 409. Server errors are not stored, so the request can be retried
+// with the same key.
+type IdempotencyMiddleware struct {
+	idempotencyDomService *service.IdempotencyDomService
+}
+
+// idempotentResponseRecorder copies the body written to the response, to be stored as the idempotent
+// response.
+type idempotentResponseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *idempotentResponseRecorder) Write(data []byte) (int, error) {
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *idempotentResponseRecorder) WriteString(data string) (int, error) {
+	recorder.body.WriteString(data)
+	return recorder.ResponseWriter.WriteString(data)
+}
+
+func (middleware *IdempotencyMiddleware) Handle(context *gin.Context) {
+
+	var key = context.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		context.Next()
+		return
+	}
+
+	fingerprint, err := fingerprintRequest(context)
+	if gininfra.HandleAPIError(context, "Error reading idempotent request", err) {
+		context.Abort()
+		return
+	}
+
+	request, claimed, err := middleware.idempotencyDomService.BeginIdempotentRequest(
+		idempotencyScope(context),
+		key,
+		fingerprint,
+	)
+	if gininfra.HandleAPIError(context, "Error beginning idempotent request", err) {
+		context.Abort()
+		return
+	}
+
+	if !claimed {
+		answerDuplicateRequest(context, request, fingerprint)
+		return
+	}
+
+	middleware.processRequest(context, request)
+}
+
+// processRequest processes a request with a claimed key, storing its response when successful or releasing
+// the key otherwise, including when the processing panics.
+func (middleware *IdempotencyMiddleware) processRequest(context *gin.Context, request *domain.IdempotentRequest) {
+
+	var recorder = &idempotentResponseRecorder{ResponseWriter: context.Writer}
+	context.Writer = recorder
+
+	var completed = false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := middleware.idempotencyDomService.ReleaseIdempotentRequest(request); err != nil {
+			slog.ErrorContext(context.Request.Context(), "Error releasing idempotent request", "error", err)
+		}
+	}()
+
+	context.Next()
+
+	if recorder.Status() >= http.StatusInternalServerError {
+		return
+	}
+
+	var headers = make(map[string]string)
+	for _, header := range replayedResponseHeaders {
+		if value := recorder.Header().Get(header); value != "" {
+			headers[header] = value
+		}
+	}
+
+	var response = &domain.IdempotentResponse{
+		StatusCode: recorder.Status(),
+		Headers:    headers,
+		Body:       recorder.body.Bytes(),
+	}
+	if err := middleware.idempotencyDomService.CompleteIdempotentRequest(request, response); err != nil {
+		slog.ErrorContext(context.Request.Context(), "Error completing idempotent request", "error", err)
+		return
+	}
+
+	completed = true
+}
+
+// answerDuplicateRequest answers a duplicate of a request previously sent with the same key, replaying its
+// response when it was completed.
+func answerDuplicateRequest(context *gin.Context, previousRequest *domain.IdempotentRequest, fingerprint string) {
+
+	if previousRequest.Fingerprint != fingerprint {
+		gininfra.AbortWithProblemResponse(
+			context,
+			http.StatusUnprocessableEntity,
+			idempotencyKeyReusedProblemType,
+			"Idempotency key was already used with a different request",
+		)
+		return
+	}
+
+	if !previousRequest.IsCompleted() {
+		gininfra.AbortWithProblemResponse(
+			context,
+			http.StatusConflict,
+			idempotentRequestInProgressProblemType,
+			"A request with the same idempotency key is still being processed",
+		)
+		return
+	}
+
+	var response = previousRequest.Response
+	for header, value := range response.Headers {
+		context.Header(header, value)
+	}
+	context.Header(idempotentReplayedHeader, "true")
+	context.Status(response.StatusCode)
+	if len(response.Body) > 0 {
+		if _, err := context.Writer.Write(response.Body); err != nil {
+			slog.ErrorContext(context.Request.Context(), "Error replaying idempotent response", "error", err)
+		}
+	}
+	context.Abort()
+}
+
+// fingerprintRequest hashes the method, URI and body of a request, identifying its duplicates. The body is
+// restored to be read again by the route handlers.
+func fingerprintRequest(context *gin.Context) (string, error) {
+
+	var body []byte
+	if context.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(context.Request.Body)
+		if err != nil {
+			return "", err
+		}
+		context.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	var hash = sha256.New()
+	hash.Write([]byte(context.Request.Method + " " + context.Request.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// idempotencyScope returns the scope of the idempotency keys of the caller of a request: the id of its user,
+// or the empty scope shared by every caller when authentication is disabled.
+func idempotencyScope(context *gin.Context) string {
+
+	var principal = findPrincipal(context)
+	if principal == nil || principal.User == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(principal.User.Id, 10)
+}
+
+func BuildIdempotencyMiddleware(idempotencyDomService *service.IdempotencyDomService) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		idempotencyDomService: idempotencyDomService,
+	}
+}
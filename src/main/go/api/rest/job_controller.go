@@ -0,0 +1,208 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/application"
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+type JobRESTController struct {
+	jobDomService       *service.JobDomService
+	jobRunnerAppService *application.JobRunnerAppService
+}
+
+func (controller *JobRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/job",
+			Handlers: gin.HandlersChain{controller.getJobs},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the background jobs",
+				Query:        model.JobQueryDTS{},
+				ResponseBody: []*model.JobDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/job",
+			Handlers: gin.HandlersChain{controller.postJob},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Submit a background job",
+				RequestBody:    model.JobSubmissionDTS{},
+				ResponseStatus: http.StatusAccepted,
+				ResponseBody:   model.JobDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/job/:" + jobIdParam,
+			Handlers: gin.HandlersChain{controller.getJob},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get a background job",
+				ResponseBody: model.JobDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/job/:" + jobIdParam + "/cancel",
+			Handlers: gin.HandlersChain{controller.postJobCancel},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Cancel a background job",
+				ResponseStatus: http.StatusAccepted,
+				ResponseBody:   model.JobDTS{},
+			},
+		},
+	}
+}
+
+// getJobs handles GET requests listing the background jobs of the caller, the most recently created first,
+// optionally filtered by status and type.
+func (controller *JobRESTController) getJobs(context *gin.Context) {
+
+	var queryDTS model.JobQueryDTS
+	valid, err := gininfra.BindAndValidateQueryWithInvalidResponse(context, &queryDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindJobQueryErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var filter = model.MapToJobFilter(&queryDTS)
+	filter.CreatedByUserId = getJobUserId(context)
+
+	jobs, err := controller.jobDomService.GetJobs(filter)
+	if gininfra.HandleAPIError(context, "Error getting jobs", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToJobDTSs(jobs))
+}
+
+// postJob handles POST requests submitting a job to run in the background. The job is accepted as
+// pending, and its status is followed through GET /api/job/:jobId.
+func (controller *JobRESTController) postJob(context *gin.Context) {
+
+	var submissionDTS model.JobSubmissionDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &submissionDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindJobErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	job, err := controller.jobRunnerAppService.SubmitJob(
+		domain.JobType(submissionDTS.Type),
+		submissionDTS.Params,
+		getJobUserId(context),
+	)
+	if gininfra.HandleAPIError(context, "Error submitting job", err) {
+		return
+	}
+
+	context.JSON(http.StatusAccepted, model.MapToJobDTS(job))
+}
+
+// getJob handles GET requests for the status, progress and outcome of a job.
+func (controller *JobRESTController) getJob(context *gin.Context) {
+
+	var jobIdParamValue = context.Param(jobIdParam)
+	jobId, err := langext.ParseInt64(jobIdParamValue)
+	if gininfra.HandleAPIError(context, getJobIdErrorMessage, err) {
+		return
+	}
+
+	job, err := controller.getVisibleJob(context, jobId)
+	if gininfra.HandleAPIError(context, "Error getting job", err) {
+		return
+	}
+
+	if job == nil {
+		gininfra.SendDataNotFoundResponse(context, "Job", jobIdParamValue)
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToJobDTS(job))
+}
+
+// postJobCancel handles POST requests cancelling a job of the caller that is not finished. A running job is
+// cancelled asynchronously, so the request is accepted with the job as it was before the cancellation.
+func (controller *JobRESTController) postJobCancel(context *gin.Context) {
+
+	var jobIdParamValue = context.Param(jobIdParam)
+	jobId, err := langext.ParseInt64(jobIdParamValue)
+	if gininfra.HandleAPIError(context, getJobIdErrorMessage, err) {
+		return
+	}
+
+	job, err := controller.getVisibleJob(context, jobId)
+	if gininfra.HandleAPIError(context, "Error getting job", err) {
+		return
+	}
+
+	if job == nil {
+		gininfra.SendDataNotFoundResponse(context, "Job", jobIdParamValue)
+		return
+	}
+
+	job, err = controller.jobRunnerAppService.CancelJob(jobId)
+	if gininfra.HandleAPIError(context, "Error cancelling job", err) {
+		return
+	}
+
+	if job == nil {
+		gininfra.SendDataNotFoundResponse(context, "Job", jobIdParamValue)
+		return
+	}
+
+	context.JSON(http.StatusAccepted, model.MapToJobDTS(job))
+}
+
+// getVisibleJob retrieves a job by id, or nil when it does not exist or was submitted by another user than
+// the caller, so the jobs of other users are reported as not found.
+func (controller *JobRESTController) getVisibleJob(context *gin.Context, jobId int64) (*domain.Job, error) {
+
+	job, err := controller.jobDomService.GetJob(jobId)
+	if err != nil || job == nil {
+		return nil, err
+	}
+
+	if !job.IsVisibleTo(getJobUserId(context)) {
+		return nil, nil
+	}
+
+	return job, nil
+}
+
+// getJobUserId returns the id of the user calling the job routes, or zero when authentication is disabled.
+func getJobUserId(context *gin.Context) int64 {
+
+	var principal = findPrincipal(context)
+	if principal == nil || principal.User == nil {
+		return 0
+	}
+
+	return principal.User.Id
+}
+
+func BuildJobRESTController(
+	jobDomService *service.JobDomService,
+	jobRunnerAppService *application.JobRunnerAppService,
+) *JobRESTController {
+	return &JobRESTController{
+		jobDomService:       jobDomService,
+		jobRunnerAppService: jobRunnerAppService,
+	}
+}
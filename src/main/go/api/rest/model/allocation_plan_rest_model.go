@@ -47,13 +47,13 @@ type AllocationPlanDTS struct {
 func MapToAllocationPlanDTSs(allocationPlans []*domain.AllocationPlan) []*AllocationPlanDTS {
 	var allocationPlansDTS = make([]*AllocationPlanDTS, 0)
 	for _, allocationPlan := range allocationPlans {
-		var allocationPlanDTS = mapToAllocationPlanDTS(allocationPlan)
+		var allocationPlanDTS = MapToAllocationPlanDTS(allocationPlan)
 		allocationPlansDTS = append(allocationPlansDTS, allocationPlanDTS)
 	}
 	return allocationPlansDTS
 }
 
-func mapToAllocationPlanDTS(allocationPlan *domain.AllocationPlan) *AllocationPlanDTS {
+func MapToAllocationPlanDTS(allocationPlan *domain.AllocationPlan) *AllocationPlanDTS {
 	var allocations = mapToPlannedAllocationDTSs(allocationPlan)
 	var allocationPlanId = langext.ParseableInt64(allocationPlan.Id)
 	return &AllocationPlanDTS{
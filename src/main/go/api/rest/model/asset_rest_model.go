@@ -1,6 +1,11 @@
 package model
 
 import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
 	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/langext"
 )
@@ -10,9 +15,61 @@ import (
 // ================================================
 
 type AssetDTS struct {
-	Id     *langext.ParseableInt64 `json:"id"`
-	Name   string                  `json:"name" validate:"required,max=100"`
-	Ticker string                  `json:"ticker" validate:"required,max=40"`
+	Id             *langext.ParseableInt64 `json:"id"`
+	Name           string                  `json:"name" validate:"required,max=100"`
+	Ticker         string                  `json:"ticker" validate:"required,max=40"`
+	InstrumentType string                  `json:"instrumentType,omitempty" validate:"max=20"`
+	Currency       string                  `json:"currency,omitempty" validate:"max=3"`
+	ISIN           string                  `json:"isin,omitempty" validate:"max=12"`
+	CUSIP          string                  `json:"cusip,omitempty" validate:"max=9"`
+	Exchange       string                  `json:"exchange,omitempty" validate:"max=40"`
+}
+
+// AssetAliasDTS is the REST data transfer structure for a ticker alias of an asset. The validity
+// dates are optional and limit when the alias resolves to the asset.
+type AssetAliasDTS struct {
+	Id        *langext.ParseableInt64 `json:"id,omitempty"`
+	Ticker    string                  `json:"ticker" validate:"required,max=40"`
+	Source    string                  `json:"source,omitempty" validate:"max=50"`
+	ValidFrom *time.Time              `json:"validFrom,omitempty"`
+	ValidTo   *time.Time              `json:"validTo,omitempty"`
+}
+
+// AssetValuationDTS is the REST data transfer structure for a manual valuation of an asset.
+type AssetValuationDTS struct {
+	Id            *langext.ParseableInt64 `json:"id,omitempty"`
+	ValuationDate *time.Time              `json:"valuationDate" validate:"required"`
+	Price         *decimal.Decimal        `json:"price" validate:"required"`
+	Currency      string                  `json:"currency" validate:"required,max=3"`
+}
+
+// AssetEventDTS is the response data transfer structure of a dividend or split event of an asset. Only
+// the fields of the event type are present.
+type AssetEventDTS struct {
+	Id               int64            `json:"id"`
+	EventType        string           `json:"eventType"`
+	EventDate        time.Time        `json:"eventDate"`
+	Source           string           `json:"source"`
+	Amount           *decimal.Decimal `json:"amount,omitempty"`
+	Currency         string           `json:"currency,omitempty"`
+	SplitNumerator   *decimal.Decimal `json:"splitNumerator,omitempty"`
+	SplitDenominator *decimal.Decimal `json:"splitDenominator,omitempty"`
+}
+
+// CorporateActionDTS is the REST data transfer structure for a corporate action of an asset. Only the
+// terms of the action type are present in responses.
+type CorporateActionDTS struct {
+	Id               *langext.ParseableInt64 `json:"id,omitempty"`
+	ActionType       string                  `json:"actionType" validate:"required,max=20"`
+	EffectiveDate    *time.Time              `json:"effectiveDate" validate:"required"`
+	RatioNumerator   *decimal.Decimal        `json:"ratioNumerator,omitempty"`
+	RatioDenominator *decimal.Decimal        `json:"ratioDenominator,omitempty"`
+	NewTicker        string                  `json:"newTicker,omitempty" validate:"max=40"`
+	TargetAssetId    *langext.ParseableInt64 `json:"targetAssetId,omitempty"`
+	CashPerShare     *decimal.Decimal        `json:"cashPerShare,omitempty"`
+	CashCurrency     string                  `json:"cashCurrency,omitempty" validate:"max=3"`
+	Status           string                  `json:"status,omitempty"`
+	AppliedAt        *time.Time              `json:"appliedAt,omitempty"`
 }
 
 // ExternalAssetDTS is the REST data transfer structure for external asset search results.
@@ -22,11 +79,46 @@ type AssetDTS struct {
 //
 // Authored by: GitHub Copilot (claude-opus-4.6)
 type ExternalAssetDTS struct {
-	Source       string `json:"source" validate:"required"`
-	Ticker       string `json:"ticker" validate:"required"`
-	ExchangeId   string `json:"exchangeId" validate:"required"`
-	Name         string `json:"name,omitempty"`
-	ExchangeName string `json:"exchangeName,omitempty"`
+	Source         string `json:"source" validate:"required"`
+	Ticker         string `json:"ticker" validate:"required"`
+	ExchangeId     string `json:"exchangeId" validate:"required"`
+	Name           string `json:"name,omitempty"`
+	ExchangeName   string `json:"exchangeName,omitempty"`
+	InstrumentType string `json:"instrumentType,omitempty" validate:"max=20"`
+}
+
+// ExternalAssetReferenceQueryDTS is the request data transfer structure identifying a linked
+// external asset through query parameters.
+type ExternalAssetReferenceQueryDTS struct {
+	Source     string `form:"source" json:"source" validate:"required"`
+	Ticker     string `form:"ticker" json:"ticker" validate:"required"`
+	ExchangeId string `form:"exchangeId" json:"exchangeId" validate:"required"`
+}
+
+// ExternalAssetPriorityDTS is the request data transfer structure listing the external assets linked
+// to an asset, from highest to lowest priority.
+type ExternalAssetPriorityDTS struct {
+	ExternalAssets []*ExternalAssetDTS `json:"externalAssets" validate:"required,min=1"`
+}
+
+// AssetQuoteDTS is the REST data transfer structure for the last close quote of an asset, including
+// the external source that provided it.
+type AssetQuoteDTS struct {
+	Source         string          `json:"source"`
+	Ticker         string          `json:"ticker"`
+	ExchangeId     string          `json:"exchangeId"`
+	Currency       string          `json:"currency"`
+	LastCloseQuote decimal.Decimal `json:"lastCloseQuote"`
+	LastCloseDate  time.Time       `json:"lastCloseDate"`
+}
+
+// AssetQuoteResultDTS is the response data transfer structure of one asset of a batch quote, with
+// either its quote or the error that prevented quoting it.
+type AssetQuoteResultDTS struct {
+	AssetId     int64          `json:"assetId"`
+	AssetTicker string         `json:"assetTicker"`
+	Quote       *AssetQuoteDTS `json:"quote,omitempty"`
+	Error       string         `json:"error,omitempty"`
 }
 
 // ExternalAssetSearchQueryDTS is the request data transfer structure for external asset
@@ -37,6 +129,23 @@ type ExternalAssetSearchQueryDTS struct {
 	Query string `form:"query" json:"query" validate:"required,max=100"`
 }
 
+// AssetIntegrationCacheMetricsDTS is the response data transfer structure of the cache metrics of an
+// external source.
+type AssetIntegrationCacheMetricsDTS struct {
+	Source string                                    `json:"source"`
+	Search *AssetIntegrationCacheOperationMetricsDTS `json:"search"`
+	Quote  *AssetIntegrationCacheOperationMetricsDTS `json:"quote"`
+}
+
+// AssetIntegrationCacheOperationMetricsDTS counts how the requests of one cached operation were served.
+type AssetIntegrationCacheOperationMetricsDTS struct {
+	Hits          int64   `json:"hits"`
+	StaleHits     int64   `json:"staleHits"`
+	Misses        int64   `json:"misses"`
+	RefreshErrors int64   `json:"refreshErrors"`
+	HitRatio      float64 `json:"hitRatio"`
+}
+
 // ================================================
 // MAPPING FUNCTIONS
 // ================================================
@@ -49,9 +158,14 @@ func MapToAssetDTS(asset *domain.Asset) *AssetDTS {
 
 	var assetId = langext.ParseableInt64(asset.Id)
 	return &AssetDTS{
-		Id:     &assetId,
-		Name:   asset.Name,
-		Ticker: asset.Ticker,
+		Id:             &assetId,
+		Name:           asset.Name,
+		Ticker:         asset.Ticker,
+		InstrumentType: string(asset.InstrumentType),
+		Currency:       asset.Currency,
+		ISIN:           asset.ISIN,
+		CUSIP:          asset.CUSIP,
+		Exchange:       asset.Exchange,
 	}
 }
 
@@ -74,9 +188,14 @@ func MapToAsset(assetDTS *AssetDTS) *domain.Asset {
 		assetId = int64(*assetDTS.Id)
 	}
 	return &domain.Asset{
-		Id:     assetId,
-		Name:   assetDTS.Name,
-		Ticker: assetDTS.Ticker,
+		Id:             assetId,
+		Name:           assetDTS.Name,
+		Ticker:         assetDTS.Ticker,
+		InstrumentType: domain.AssetInstrumentType(assetDTS.InstrumentType),
+		Currency:       assetDTS.Currency,
+		ISIN:           assetDTS.ISIN,
+		CUSIP:          assetDTS.CUSIP,
+		Exchange:       assetDTS.Exchange,
 	}
 }
 
@@ -88,6 +207,188 @@ func MapToAssets(assetsDTS []*AssetDTS) []*domain.Asset {
 	return assets
 }
 
+// MapToAssetAliasDTS maps a domain AssetAlias to its REST DTS representation.
+func MapToAssetAliasDTS(alias *domain.AssetAlias) *AssetAliasDTS {
+
+	if alias == nil {
+		return nil
+	}
+
+	var aliasId = langext.ParseableInt64(alias.Id)
+	return &AssetAliasDTS{
+		Id:        &aliasId,
+		Ticker:    alias.Ticker,
+		Source:    alias.Source,
+		ValidFrom: alias.ValidFrom,
+		ValidTo:   alias.ValidTo,
+	}
+}
+
+func MapToAssetAliasDTSs(aliases []*domain.AssetAlias) []*AssetAliasDTS {
+	var aliasDTSs = make([]*AssetAliasDTS, len(aliases))
+	for index, alias := range aliases {
+		aliasDTSs[index] = MapToAssetAliasDTS(alias)
+	}
+	return aliasDTSs
+}
+
+// MapToAssetAlias maps a REST alias DTS to the domain AssetAlias of the given asset. The DTS id is
+// ignored, since aliases are only created through this mapping.
+func MapToAssetAlias(assetId int64, aliasDTS *AssetAliasDTS) *domain.AssetAlias {
+
+	if aliasDTS == nil {
+		return nil
+	}
+
+	return &domain.AssetAlias{
+		AssetId:   assetId,
+		Ticker:    aliasDTS.Ticker,
+		Source:    aliasDTS.Source,
+		ValidFrom: aliasDTS.ValidFrom,
+		ValidTo:   aliasDTS.ValidTo,
+	}
+}
+
+// MapToAssetValuationDTS maps a domain AssetValuation to its REST DTS representation.
+func MapToAssetValuationDTS(valuation *domain.AssetValuation) *AssetValuationDTS {
+
+	if valuation == nil {
+		return nil
+	}
+
+	var valuationId = langext.ParseableInt64(valuation.Id)
+	return &AssetValuationDTS{
+		Id:            &valuationId,
+		ValuationDate: &valuation.ValuationDate,
+		Price:         &valuation.Price,
+		Currency:      valuation.Currency,
+	}
+}
+
+func MapToAssetValuationDTSs(valuations []*domain.AssetValuation) []*AssetValuationDTS {
+	var valuationDTSs = make([]*AssetValuationDTS, len(valuations))
+	for index, valuation := range valuations {
+		valuationDTSs[index] = MapToAssetValuationDTS(valuation)
+	}
+	return valuationDTSs
+}
+
+// MapToAssetValuation maps a REST valuation DTS to the domain AssetValuation of the given asset. The
+// DTS id is ignored, since valuations are identified by asset and date when recorded.
+func MapToAssetValuation(assetId int64, valuationDTS *AssetValuationDTS) *domain.AssetValuation {
+
+	if valuationDTS == nil {
+		return nil
+	}
+
+	return &domain.AssetValuation{
+		AssetId:       assetId,
+		ValuationDate: *valuationDTS.ValuationDate,
+		Price:         *valuationDTS.Price,
+		Currency:      strings.ToUpper(valuationDTS.Currency),
+	}
+}
+
+// MapToAssetEventDTSs maps domain AssetEvents to their REST DTS representations.
+func MapToAssetEventDTSs(events []*domain.AssetEvent) []*AssetEventDTS {
+	var eventDTSs = make([]*AssetEventDTS, len(events))
+	for index, event := range events {
+		var eventDTS = &AssetEventDTS{
+			Id:        event.Id,
+			EventType: string(event.EventType),
+			EventDate: event.EventDate,
+			Source:    string(event.Source),
+		}
+		switch event.EventType {
+		case domain.DividendAssetEventType:
+			eventDTS.Amount = &event.Amount
+			eventDTS.Currency = event.Currency
+		case domain.SplitAssetEventType:
+			eventDTS.SplitNumerator = &event.SplitNumerator
+			eventDTS.SplitDenominator = &event.SplitDenominator
+		}
+		eventDTSs[index] = eventDTS
+	}
+	return eventDTSs
+}
+
+// MapToCorporateActionDTS maps a domain CorporateAction to its REST DTS representation.
+func MapToCorporateActionDTS(action *domain.CorporateAction) *CorporateActionDTS {
+
+	if action == nil {
+		return nil
+	}
+
+	var actionId = langext.ParseableInt64(action.Id)
+	var actionDTS = &CorporateActionDTS{
+		Id:            &actionId,
+		ActionType:    string(action.ActionType),
+		EffectiveDate: &action.EffectiveDate,
+		Status:        string(action.Status),
+		AppliedAt:     action.AppliedAt,
+	}
+
+	switch action.ActionType {
+	case domain.SplitCorporateActionType, domain.ReverseSplitCorporateActionType:
+		actionDTS.RatioNumerator = &action.RatioNumerator
+		actionDTS.RatioDenominator = &action.RatioDenominator
+	case domain.SymbolChangeCorporateActionType:
+		actionDTS.NewTicker = action.NewTicker
+	case domain.MergerCorporateActionType:
+		var targetAssetId = langext.ParseableInt64(action.TargetAssetId)
+		actionDTS.RatioNumerator = &action.RatioNumerator
+		actionDTS.RatioDenominator = &action.RatioDenominator
+		actionDTS.TargetAssetId = &targetAssetId
+		if action.CashPerShare.IsPositive() {
+			actionDTS.CashPerShare = &action.CashPerShare
+			actionDTS.CashCurrency = action.CashCurrency
+		}
+	}
+
+	return actionDTS
+}
+
+func MapToCorporateActionDTSs(actions []*domain.CorporateAction) []*CorporateActionDTS {
+	var actionDTSs = make([]*CorporateActionDTS, len(actions))
+	for index, action := range actions {
+		actionDTSs[index] = MapToCorporateActionDTS(action)
+	}
+	return actionDTSs
+}
+
+// MapToCorporateAction maps a REST corporate action DTS to a new pending domain CorporateAction of the
+// given asset. The DTS id, status and application time are ignored.
+func MapToCorporateAction(assetId int64, actionDTS *CorporateActionDTS) *domain.CorporateAction {
+
+	if actionDTS == nil {
+		return nil
+	}
+
+	var action = &domain.CorporateAction{
+		AssetId:       assetId,
+		ActionType:    domain.CorporateActionType(strings.ToUpper(actionDTS.ActionType)),
+		EffectiveDate: *actionDTS.EffectiveDate,
+		NewTicker:     actionDTS.NewTicker,
+		CashCurrency:  strings.ToUpper(actionDTS.CashCurrency),
+		Status:        domain.PendingCorporateActionStatus,
+	}
+
+	if actionDTS.RatioNumerator != nil {
+		action.RatioNumerator = *actionDTS.RatioNumerator
+	}
+	if actionDTS.RatioDenominator != nil {
+		action.RatioDenominator = *actionDTS.RatioDenominator
+	}
+	if actionDTS.TargetAssetId != nil {
+		action.TargetAssetId = int64(*actionDTS.TargetAssetId)
+	}
+	if actionDTS.CashPerShare != nil {
+		action.CashPerShare = *actionDTS.CashPerShare
+	}
+
+	return action
+}
+
 // MapToExternalAssetDTS maps a domain ExternalAsset to its REST DTS representation.
 //
 // Parameters:
@@ -108,14 +409,84 @@ func MapToExternalAssetDTS(externalAsset *domain.ExternalAsset) *ExternalAssetDT
 	}
 
 	return &ExternalAssetDTS{
-		Source:       string(externalAsset.Source),
-		Ticker:       externalAsset.Ticker,
-		ExchangeId:   externalAsset.ExchangeId,
-		Name:         externalAsset.Name,
-		ExchangeName: externalAsset.ExchangeName,
+		Source:         string(externalAsset.Source),
+		Ticker:         externalAsset.Ticker,
+		ExchangeId:     externalAsset.ExchangeId,
+		Name:           externalAsset.Name,
+		ExchangeName:   externalAsset.ExchangeName,
+		InstrumentType: string(externalAsset.InstrumentType),
 	}
 }
 
+// MapToLinkedExternalAssetDTSs maps the external assets linked to an asset, in priority order, to
+// their REST DTS representations. Returns an empty slice when no external asset is linked.
+func MapToLinkedExternalAssetDTSs(asset *domain.Asset) []*ExternalAssetDTS {
+
+	var externalAssetDTSs = make([]*ExternalAssetDTS, 0)
+	if asset.ExternalData == nil {
+		return externalAssetDTSs
+	}
+
+	for index := range asset.ExternalData.Data {
+		externalAssetDTSs = append(externalAssetDTSs, MapToExternalAssetDTS(&asset.ExternalData.Data[index]))
+	}
+	return externalAssetDTSs
+}
+
+// MapToExternalAsset maps a REST external asset DTS to the domain ExternalAsset reference.
+func MapToExternalAsset(externalAssetDTS *ExternalAssetDTS) *domain.ExternalAsset {
+	return mapToExternalAsset(externalAssetDTS)
+}
+
+// MapToExternalAssets maps REST external asset DTSs to domain ExternalAsset values, keeping their order.
+func MapToExternalAssets(externalAssetDTSs []*ExternalAssetDTS) []domain.ExternalAsset {
+	var externalAssets = make([]domain.ExternalAsset, len(externalAssetDTSs))
+	for index, externalAssetDTS := range externalAssetDTSs {
+		externalAssets[index] = *mapToExternalAsset(externalAssetDTS)
+	}
+	return externalAssets
+}
+
+// MapExternalAssetReferenceToExternalAsset maps an external asset reference query to the domain
+// ExternalAsset reference.
+func MapExternalAssetReferenceToExternalAsset(referenceDTS *ExternalAssetReferenceQueryDTS) *domain.ExternalAsset {
+	return &domain.ExternalAsset{
+		Source:     domain.AssetExternalSource(referenceDTS.Source),
+		Ticker:     referenceDTS.Ticker,
+		ExchangeId: referenceDTS.ExchangeId,
+	}
+}
+
+// MapToAssetQuoteDTS maps a domain ExternalAssetQuote to its REST DTS representation.
+func MapToAssetQuoteDTS(quote *domain.ExternalAssetQuote) *AssetQuoteDTS {
+	return &AssetQuoteDTS{
+		Source:         string(quote.Source),
+		Ticker:         quote.Ticker,
+		ExchangeId:     quote.ExchangeId,
+		Currency:       quote.Currency.String(),
+		LastCloseQuote: quote.LastCloseQuote,
+		LastCloseDate:  quote.LastCloseDate,
+	}
+}
+
+// MapToAssetQuoteResultDTSs maps the domain results of a batch quote to their REST DTS representations.
+func MapToAssetQuoteResultDTSs(results []*domain.AssetQuoteResult) []*AssetQuoteResultDTS {
+	var resultDTSs = make([]*AssetQuoteResultDTS, len(results))
+	for index, result := range results {
+		var resultDTS = &AssetQuoteResultDTS{
+			AssetId:     result.Asset.Id,
+			AssetTicker: result.Asset.Ticker,
+		}
+		if result.Err != nil {
+			resultDTS.Error = result.Err.Error()
+		} else {
+			resultDTS.Quote = MapToAssetQuoteDTS(result.Quote)
+		}
+		resultDTSs[index] = resultDTS
+	}
+	return resultDTSs
+}
+
 // MapToExternalAssetDTSs maps a slice of domain ExternalAsset pointers to their REST DTS
 // representations.
 //
@@ -133,3 +504,38 @@ func MapToExternalAssetDTSs(externalAssets []*domain.ExternalAsset) []*ExternalA
 	}
 	return externalAssetDTSs
 }
+
+// MapToAssetIntegrationCacheMetricsDTSs maps the domain cache metrics of the external sources to their
+// REST DTS representations. Stale hits count as hits in the hit ratio, as they are served from the cache.
+func MapToAssetIntegrationCacheMetricsDTSs(
+	metrics []*domain.AssetIntegrationCacheMetrics,
+) []*AssetIntegrationCacheMetricsDTS {
+	var metricsDTSs = make([]*AssetIntegrationCacheMetricsDTS, len(metrics))
+	for index, sourceMetrics := range metrics {
+		metricsDTSs[index] = &AssetIntegrationCacheMetricsDTS{
+			Source: string(sourceMetrics.Source),
+			Search: mapToAssetIntegrationCacheOperationMetricsDTS(&sourceMetrics.Search),
+			Quote:  mapToAssetIntegrationCacheOperationMetricsDTS(&sourceMetrics.Quote),
+		}
+	}
+	return metricsDTSs
+}
+
+func mapToAssetIntegrationCacheOperationMetricsDTS(
+	metrics *domain.AssetIntegrationCacheOperationMetrics,
+) *AssetIntegrationCacheOperationMetricsDTS {
+
+	var hitRatio float64
+	var servedFromCache = metrics.Hits + metrics.StaleHits
+	if total := servedFromCache + metrics.Misses; total > 0 {
+		hitRatio = float64(servedFromCache) / float64(total)
+	}
+
+	return &AssetIntegrationCacheOperationMetricsDTS{
+		Hits:          metrics.Hits,
+		StaleHits:     metrics.StaleHits,
+		Misses:        metrics.Misses,
+		RefreshErrors: metrics.RefreshErrors,
+		HitRatio:      hitRatio,
+	}
+}
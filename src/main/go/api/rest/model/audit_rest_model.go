@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+type AuditEntryDTS struct {
+	Id            *langext.ParseableInt64 `json:"id"`
+	EntityType    string                  `json:"entityType"`
+	EntityId      *langext.ParseableInt64 `json:"entityId"`
+	PortfolioId   *langext.ParseableInt64 `json:"portfolioId,omitempty"`
+	Action        string                  `json:"action"`
+	ActorUserId   *langext.ParseableInt64 `json:"actorUserId,omitempty"`
+	ActorUsername *string                 `json:"actorUsername,omitempty"`
+	Before        json.RawMessage         `json:"before,omitempty"`
+	After         json.RawMessage         `json:"after,omitempty"`
+	CreatedAt     time.Time               `json:"createdAt"`
+}
+
+// AuditQueryDTS filters the audit trail. From and To are RFC 3339 timestamps, From inclusive and To
+// exclusive.
+type AuditQueryDTS struct {
+	EntityType  string     `form:"entityType" json:"entityType" validate:"omitempty,oneof=PORTFOLIO PORTFOLIO_OBSERVATION PORTFOLIO_ALLOCATION ALLOCATION_PLAN ASSET"`
+	EntityId    *int64     `form:"entityId" json:"entityId"`
+	PortfolioId *int64     `form:"portfolioId" json:"portfolioId"`
+	ActorUserId *int64     `form:"actorUserId" json:"actorUserId"`
+	From        *time.Time `form:"from" json:"from"`
+	To          *time.Time `form:"to" json:"to"`
+	Limit       int        `form:"limit" json:"limit" validate:"min=0,max=1000"`
+}
+
+func toParseableInt64Pointer(value *int64) *langext.ParseableInt64 {
+	if value == nil {
+		return nil
+	}
+	var parseableValue = langext.ParseableInt64(*value)
+	return &parseableValue
+}
+
+func MapToAuditEntryDTS(entry *domain.AuditEntry) *AuditEntryDTS {
+
+	if entry == nil {
+		return nil
+	}
+
+	var entryId = langext.ParseableInt64(entry.Id)
+	var entityId = langext.ParseableInt64(entry.EntityId)
+	return &AuditEntryDTS{
+		Id:            &entryId,
+		EntityType:    string(entry.EntityType),
+		EntityId:      &entityId,
+		PortfolioId:   toParseableInt64Pointer(entry.PortfolioId),
+		Action:        string(entry.Action),
+		ActorUserId:   toParseableInt64Pointer(entry.ActorUserId),
+		ActorUsername: entry.ActorUsername,
+		Before:        entry.Before,
+		After:         entry.After,
+		CreatedAt:     entry.CreatedAt,
+	}
+}
+
+func MapToAuditEntryDTSs(entries []*domain.AuditEntry) []*AuditEntryDTS {
+	var entryDTSs = make([]*AuditEntryDTS, 0, len(entries))
+	for _, entry := range entries {
+		entryDTSs = append(entryDTSs, MapToAuditEntryDTS(entry))
+	}
+	return entryDTSs
+}
+
+func MapToAuditFilter(queryDTS *AuditQueryDTS) *domain.AuditFilter {
+	return &domain.AuditFilter{
+		EntityType:  domain.AuditEntityType(queryDTS.EntityType),
+		EntityId:    queryDTS.EntityId,
+		PortfolioId: queryDTS.PortfolioId,
+		ActorUserId: queryDTS.ActorUserId,
+		From:        queryDTS.From,
+		To:          queryDTS.To,
+		Limit:       queryDTS.Limit,
+	}
+}
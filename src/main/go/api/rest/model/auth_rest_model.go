@@ -0,0 +1,95 @@
+package model
+
+import (
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+type LoginDTS struct {
+	Username string `json:"username" validate:"required,max=100"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// UserDTS is the REST data transfer structure of a local user. The password is only received, on the
+// creation of the user, and never returned.
+type UserDTS struct {
+	Id        *langext.ParseableInt64 `json:"id,omitempty"`
+	Username  string                  `json:"username" validate:"required,max=100"`
+	Password  string                  `json:"password,omitempty" validate:"required,max=72"`
+	CreatedAt *time.Time              `json:"createdAt,omitempty"`
+}
+
+type PasswordChangeDTS struct {
+	CurrentPassword string `json:"currentPassword" validate:"required,max=72"`
+	NewPassword     string `json:"newPassword" validate:"required,max=72"`
+}
+
+// APITokenDTS is the REST data transfer structure of a personal API token. The token itself is only
+// returned on creation.
+type APITokenDTS struct {
+	Id         *langext.ParseableInt64 `json:"id,omitempty"`
+	Name       string                  `json:"name" validate:"required,max=100"`
+	Scopes     []string                `json:"scopes" validate:"required"`
+	Token      string                  `json:"token,omitempty"`
+	CreatedAt  *time.Time              `json:"createdAt,omitempty"`
+	ExpiresAt  *time.Time              `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time              `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time              `json:"revokedAt,omitempty"`
+}
+
+func MapToUserDTS(user *domain.User) *UserDTS {
+
+	if user == nil {
+		return nil
+	}
+
+	var userId = langext.ParseableInt64(user.Id)
+
+	return &UserDTS{
+		Id:        &userId,
+		Username:  user.Username,
+		CreatedAt: &user.CreatedAt,
+	}
+}
+
+// MapToAPITokenDTS maps an API token to its DTS, leaving the token itself out.
+func MapToAPITokenDTS(token *domain.APIToken) *APITokenDTS {
+
+	if token == nil {
+		return nil
+	}
+
+	var tokenId = langext.ParseableInt64(token.Id)
+	var scopes = make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &APITokenDTS{
+		Id:         &tokenId,
+		Name:       token.Name,
+		Scopes:     scopes,
+		CreatedAt:  &token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+	}
+}
+
+func MapToAPITokenDTSs(tokens []*domain.APIToken) []*APITokenDTS {
+	var tokenDTSs = make([]*APITokenDTS, 0, len(tokens))
+	for _, token := range tokens {
+		tokenDTSs = append(tokenDTSs, MapToAPITokenDTS(token))
+	}
+	return tokenDTSs
+}
+
+func MapToAPITokenScopes(tokenDTS *APITokenDTS) []domain.APITokenScope {
+	var scopes = make([]domain.APITokenScope, len(tokenDTS.Scopes))
+	for i, scope := range tokenDTS.Scopes {
+		scopes[i] = domain.APITokenScope(scope)
+	}
+	return scopes
+}
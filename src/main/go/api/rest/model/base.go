@@ -1,6 +1,20 @@
 package model
 
-type ErrorResponse struct {
-	ErrorMessage string   `json:"errorMessage"`
-	Details      []string `json:"details,omitempty"`
+// ProblemDetails is an RFC 7807 error response, sent as application/problem+json. Type is a URI identifying
+// the kind of problem, summarized by Title, and Instance identifies the request with the problem. Errors
+// extends the standard members with the individual problems of the request, like its invalid fields.
+type ProblemDetails struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Errors   []*ProblemError `json:"errors,omitempty"`
+}
+
+// ProblemError is an individual problem of a request, with the JSON pointer (RFC 6901) of the request body
+// field it refers to, when there is one.
+type ProblemError struct {
+	Pointer string `json:"pointer,omitempty"`
+	Detail  string `json:"detail"`
 }
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+// DivergenceAlertRuleDTS is the REST data transfer structure of an alert rule of a portfolio. Enabled
+// defaults to true. HierarchicalId restricts a DRIFT_ABOVE rule to a single point of the hierarchy.
+type DivergenceAlertRuleDTS struct {
+	Id               *langext.ParseableInt64 `json:"id,omitempty"`
+	AllocationPlanId *langext.ParseableInt64 `json:"allocationPlanId" validate:"required"`
+	Name             string                  `json:"name" validate:"required,max=100"`
+	RuleType         string                  `json:"ruleType" validate:"required,max=30"`
+	HierarchicalId   *string                 `json:"hierarchicalId,omitempty" validate:"omitempty,max=500"`
+	ThresholdPercent *decimal.Decimal        `json:"thresholdPercent" validate:"required"`
+	Enabled          *bool                   `json:"enabled,omitempty"`
+	CreatedAt        *time.Time              `json:"createdAt,omitempty"`
+}
+
+type DivergenceAlertDTS struct {
+	Id                     *langext.ParseableInt64 `json:"id"`
+	RuleId                 *langext.ParseableInt64 `json:"ruleId"`
+	RuleName               string                  `json:"ruleName"`
+	RuleType               string                  `json:"ruleType"`
+	HierarchicalId         *string                 `json:"hierarchicalId,omitempty"`
+	Status                 string                  `json:"status"`
+	ThresholdPercent       decimal.Decimal         `json:"thresholdPercent"`
+	ObservedPercent        decimal.Decimal         `json:"observedPercent"`
+	ObservationTimestampId *langext.ParseableInt64 `json:"observationTimestampId"`
+	TriggeredAt            time.Time               `json:"triggeredAt"`
+	LastEvaluatedAt        time.Time               `json:"lastEvaluatedAt"`
+	AcknowledgedAt         *time.Time              `json:"acknowledgedAt,omitempty"`
+	ResolvedAt             *time.Time              `json:"resolvedAt,omitempty"`
+}
+
+type DivergenceAlertQueryDTS struct {
+	Status string `form:"status" json:"status" validate:"omitempty,oneof=TRIGGERED ACKNOWLEDGED RESOLVED"`
+	Limit  int    `form:"limit" json:"limit" validate:"min=0,max=1000"`
+}
+
+func MapToDivergenceAlertRuleDTS(rule *domain.DivergenceAlertRule) *DivergenceAlertRuleDTS {
+
+	if rule == nil {
+		return nil
+	}
+
+	var ruleId = langext.ParseableInt64(rule.Id)
+	var allocationPlanId = langext.ParseableInt64(rule.AllocationPlanId)
+	var enabled = rule.Enabled
+
+	return &DivergenceAlertRuleDTS{
+		Id:               &ruleId,
+		AllocationPlanId: &allocationPlanId,
+		Name:             rule.Name,
+		RuleType:         string(rule.RuleType),
+		HierarchicalId:   rule.HierarchicalId,
+		ThresholdPercent: &rule.ThresholdPercent,
+		Enabled:          &enabled,
+		CreatedAt:        &rule.CreatedAt,
+	}
+}
+
+func MapToDivergenceAlertRuleDTSs(rules []*domain.DivergenceAlertRule) []*DivergenceAlertRuleDTS {
+	var ruleDTSs = make([]*DivergenceAlertRuleDTS, 0, len(rules))
+	for _, rule := range rules {
+		ruleDTSs = append(ruleDTSs, MapToDivergenceAlertRuleDTS(rule))
+	}
+	return ruleDTSs
+}
+
+func MapToDivergenceAlertRule(portfolioId int64, ruleDTS *DivergenceAlertRuleDTS) *domain.DivergenceAlertRule {
+	return &domain.DivergenceAlertRule{
+		PortfolioId:      portfolioId,
+		AllocationPlanId: int64(*ruleDTS.AllocationPlanId),
+		Name:             ruleDTS.Name,
+		RuleType:         domain.DivergenceAlertRuleType(ruleDTS.RuleType),
+		HierarchicalId:   ruleDTS.HierarchicalId,
+		ThresholdPercent: *ruleDTS.ThresholdPercent,
+		Enabled:          ruleDTS.Enabled == nil || *ruleDTS.Enabled,
+	}
+}
+
+func MapToDivergenceAlertDTS(alert *domain.DivergenceAlert) *DivergenceAlertDTS {
+
+	if alert == nil {
+		return nil
+	}
+
+	var alertId = langext.ParseableInt64(alert.Id)
+	var ruleId = langext.ParseableInt64(alert.RuleId)
+	var observationTimestampId = langext.ParseableInt64(alert.ObservationTimestampId)
+
+	return &DivergenceAlertDTS{
+		Id:                     &alertId,
+		RuleId:                 &ruleId,
+		RuleName:               alert.RuleName,
+		RuleType:               string(alert.RuleType),
+		HierarchicalId:         alert.HierarchicalId,
+		Status:                 string(alert.Status),
+		ThresholdPercent:       alert.ThresholdPercent,
+		ObservedPercent:        alert.ObservedPercent,
+		ObservationTimestampId: &observationTimestampId,
+		TriggeredAt:            alert.TriggeredAt,
+		LastEvaluatedAt:        alert.LastEvaluatedAt,
+		AcknowledgedAt:         alert.AcknowledgedAt,
+		ResolvedAt:             alert.ResolvedAt,
+	}
+}
+
+func MapToDivergenceAlertDTSs(alerts []*domain.DivergenceAlert) []*DivergenceAlertDTS {
+	var alertDTSs = make([]*DivergenceAlertDTS, 0, len(alerts))
+	for _, alert := range alerts {
+		alertDTSs = append(alertDTSs, MapToDivergenceAlertDTS(alert))
+	}
+	return alertDTSs
+}
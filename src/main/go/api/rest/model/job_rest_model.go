@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+type JobDTS struct {
+	Id                *langext.ParseableInt64 `json:"id"`
+	Type              string                  `json:"type"`
+	Params            json.RawMessage         `json:"params,omitempty"`
+	Status            string                  `json:"status"`
+	ProgressCompleted int                     `json:"progressCompleted"`
+	ProgressTotal     int                     `json:"progressTotal"`
+	Result            json.RawMessage         `json:"result,omitempty"`
+	ErrorMessage      string                  `json:"errorMessage,omitempty"`
+	CreatedAt         time.Time               `json:"createdAt"`
+	StartedAt         *time.Time              `json:"startedAt,omitempty"`
+	FinishedAt        *time.Time              `json:"finishedAt,omitempty"`
+}
+
+// JobSubmissionDTS is the REST data transfer structure of a job to run in the background. Params depend
+// on the job type.
+type JobSubmissionDTS struct {
+	Type   string          `json:"type" validate:"required,max=50"`
+	Params json.RawMessage `json:"params"`
+}
+
+type JobQueryDTS struct {
+	Status string `form:"status" json:"status" validate:"omitempty,oneof=PENDING RUNNING SUCCEEDED FAILED CANCELLED"`
+	Type   string `form:"type" json:"type" validate:"max=50"`
+	Limit  int    `form:"limit" json:"limit" validate:"min=0,max=1000"`
+}
+
+func MapToJobDTS(job *domain.Job) *JobDTS {
+
+	if job == nil {
+		return nil
+	}
+
+	var jobId = langext.ParseableInt64(job.Id)
+	return &JobDTS{
+		Id:                &jobId,
+		Type:              string(job.JobType),
+		Params:            job.Params,
+		Status:            string(job.Status),
+		ProgressCompleted: job.ProgressCompleted,
+		ProgressTotal:     job.ProgressTotal,
+		Result:            job.Result,
+		ErrorMessage:      job.ErrorMessage,
+		CreatedAt:         job.CreatedAt,
+		StartedAt:         job.StartedAt,
+		FinishedAt:        job.FinishedAt,
+	}
+}
+
+func MapToJobDTSs(jobs []*domain.Job) []*JobDTS {
+	var jobDTSs = make([]*JobDTS, 0, len(jobs))
+	for _, job := range jobs {
+		jobDTSs = append(jobDTSs, MapToJobDTS(job))
+	}
+	return jobDTSs
+}
+
+func MapToJobFilter(queryDTS *JobQueryDTS) *domain.JobFilter {
+	return &domain.JobFilter{
+		Status:  domain.JobStatus(queryDTS.Status),
+		JobType: domain.JobType(queryDTS.Type),
+		Limit:   queryDTS.Limit,
+	}
+}
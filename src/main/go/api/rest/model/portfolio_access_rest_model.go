@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+// PortfolioGrantDTS is the REST data transfer structure of the access granted to a user on a portfolio.
+// Grants are received by username.
+type PortfolioGrantDTS struct {
+	UserId    *langext.ParseableInt64 `json:"userId,omitempty"`
+	Username  string                  `json:"username" validate:"required,max=100"`
+	Role      string                  `json:"role" validate:"required,oneof=EDITOR VIEWER"`
+	CreatedAt *time.Time              `json:"createdAt,omitempty"`
+}
+
+// PortfolioShareLinkDTS is the REST data transfer structure of a read-only share link of a portfolio. The
+// token of the link is only returned on creation.
+type PortfolioShareLinkDTS struct {
+	Id              *langext.ParseableInt64 `json:"id,omitempty"`
+	Token           string                  `json:"token,omitempty"`
+	CreatedByUserId *langext.ParseableInt64 `json:"createdByUserId,omitempty"`
+	CreatedAt       *time.Time              `json:"createdAt,omitempty"`
+	ExpiresAt       *time.Time              `json:"expiresAt" validate:"required"`
+	RevokedAt       *time.Time              `json:"revokedAt,omitempty"`
+}
+
+func MapToPortfolioGrantDTS(grant *domain.PortfolioGrant) *PortfolioGrantDTS {
+
+	if grant == nil {
+		return nil
+	}
+
+	var userId = langext.ParseableInt64(grant.UserId)
+	return &PortfolioGrantDTS{
+		UserId:    &userId,
+		Username:  grant.Username,
+		Role:      string(grant.Role),
+		CreatedAt: &grant.CreatedAt,
+	}
+}
+
+func MapToPortfolioGrantDTSs(grants []*domain.PortfolioGrant) []*PortfolioGrantDTS {
+	var grantDTSs = make([]*PortfolioGrantDTS, 0, len(grants))
+	for _, grant := range grants {
+		grantDTSs = append(grantDTSs, MapToPortfolioGrantDTS(grant))
+	}
+	return grantDTSs
+}
+
+// MapToPortfolioShareLinkDTS maps a share link to its DTS, leaving the token out.
+func MapToPortfolioShareLinkDTS(shareLink *domain.PortfolioShareLink) *PortfolioShareLinkDTS {
+
+	if shareLink == nil {
+		return nil
+	}
+
+	var shareLinkId = langext.ParseableInt64(shareLink.Id)
+	var createdByUserId = langext.ParseableInt64(shareLink.CreatedByUserId)
+	return &PortfolioShareLinkDTS{
+		Id:              &shareLinkId,
+		CreatedByUserId: &createdByUserId,
+		CreatedAt:       &shareLink.CreatedAt,
+		ExpiresAt:       &shareLink.ExpiresAt,
+		RevokedAt:       shareLink.RevokedAt,
+	}
+}
+
+func MapToPortfolioShareLinkDTSs(shareLinks []*domain.PortfolioShareLink) []*PortfolioShareLinkDTS {
+	var shareLinkDTSs = make([]*PortfolioShareLinkDTS, 0, len(shareLinks))
+	for _, shareLink := range shareLinks {
+		shareLinkDTSs = append(shareLinkDTSs, MapToPortfolioShareLinkDTS(shareLink))
+	}
+	return shareLinkDTSs
+}
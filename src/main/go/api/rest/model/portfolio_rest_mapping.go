@@ -45,6 +45,10 @@ func MapToPortfolioDTS(portfolio *domain.Portfolio) *PortfolioDTS {
 		Name:                portfolio.Name,
 		AllocationStructure: &structure,
 	}
+	if portfolio.OwnerUserId != nil {
+		var ownerUserId = langext.ParseableInt64(*portfolio.OwnerUserId)
+		portfolioDTS.OwnerUserId = &ownerUserId
+	}
 	return &portfolioDTS
 }
 
@@ -253,7 +257,8 @@ func mapToExternalAssetData(externalAssetDTS *ExternalAssetDTS) *domain.External
 }
 
 // mapToExternalAsset converts the REST external asset payload into the persisted domain shape,
-// keeping transient display fields out of the stored representation.
+// keeping transient display fields out of the stored representation. The instrument type is kept as
+// a transient field, used only to fill the metadata of the new asset.
 //
 // Authored by: OpenCode
 func mapToExternalAsset(externalAssetDTS *ExternalAssetDTS) *domain.ExternalAsset {
@@ -262,9 +267,10 @@ func mapToExternalAsset(externalAssetDTS *ExternalAssetDTS) *domain.ExternalAsse
 	}
 
 	return &domain.ExternalAsset{
-		Source:     domain.AssetExternalSource(externalAssetDTS.Source),
-		Ticker:     externalAssetDTS.Ticker,
-		ExchangeId: externalAssetDTS.ExchangeId,
+		Source:         domain.AssetExternalSource(externalAssetDTS.Source),
+		Ticker:         externalAssetDTS.Ticker,
+		ExchangeId:     externalAssetDTS.ExchangeId,
+		InstrumentType: domain.AssetInstrumentType(externalAssetDTS.InstrumentType),
 	}
 }
 
@@ -335,3 +341,25 @@ func mapToPotentialDivergenceDTS(divergence *domain.PotentialDivergence, depth i
 	}
 	return &divergenceDTS
 }
+
+// ==========================================
+// INCOME
+// ==========================================
+
+func MapToPortfolioIncomeRecordDTSs(incomeRecords []*domain.AssetIncomeRecord) []*PortfolioIncomeRecordDTS {
+	var incomeRecordDTSs = make([]*PortfolioIncomeRecordDTS, len(incomeRecords))
+	for index, incomeRecord := range incomeRecords {
+		incomeRecordDTSs[index] = &PortfolioIncomeRecordDTS{
+			AssetId:              incomeRecord.Asset.Id,
+			AssetTicker:          incomeRecord.Asset.Ticker,
+			AssetName:            incomeRecord.Asset.Name,
+			ExDividendDate:       incomeRecord.ExDividendDate,
+			AmountPerShare:       incomeRecord.AmountPerShare,
+			Currency:             incomeRecord.Currency,
+			AssetQuantity:        incomeRecord.AssetQuantity,
+			TotalAmount:          incomeRecord.TotalAmount(),
+			ObservationTimestamp: incomeRecord.ObservationTimestamp,
+		}
+	}
+	return incomeRecordDTSs
+}
@@ -8,10 +8,13 @@ import (
 	"github.com/benizzio/open-asset-allocator/langext"
 )
 
+// PortfolioDTS is the REST data transfer structure of a portfolio. OwnerUserId is only returned, as the
+// owner is the user creating the portfolio.
 type PortfolioDTS struct {
 	Id                  *langext.ParseableInt64 `json:"id"`
 	Name                string                  `json:"name" validate:"required,max=100"`
 	AllocationStructure *AllocationStructureDTS `json:"allocationStructure"`
+	OwnerUserId         *langext.ParseableInt64 `json:"ownerUserId,omitempty"`
 }
 
 type PortfolioAllocationDTS struct {
@@ -34,6 +37,27 @@ type PortfolioSnapshotDTS struct {
 	TotalMarketValue     *decimal.Decimal                  `json:"totalMarketValue"`
 }
 
+// PortfolioHistoryQueryDTS is the request data transfer structure of the optional portfolio history
+// query parameters. With SplitAdjusted, historical quantities and prices are converted to the current
+// share basis using the recorded splits of each asset.
+type PortfolioHistoryQueryDTS struct {
+	SplitAdjusted bool `form:"splitAdjusted" json:"splitAdjusted"`
+}
+
+// PortfolioIncomeRecordDTS is the response data transfer structure of the dividend income of a
+// portfolio from one dividend event.
+type PortfolioIncomeRecordDTS struct {
+	AssetId              int64           `json:"assetId"`
+	AssetTicker          string          `json:"assetTicker"`
+	AssetName            string          `json:"assetName"`
+	ExDividendDate       time.Time       `json:"exDividendDate"`
+	AmountPerShare       decimal.Decimal `json:"amountPerShare"`
+	Currency             string          `json:"currency"`
+	AssetQuantity        decimal.Decimal `json:"assetQuantity"`
+	TotalAmount          decimal.Decimal `json:"totalAmount"`
+	ObservationTimestamp time.Time       `json:"observationTimestamp"`
+}
+
 type portfolioAllocationsPerObservationTimestamp map[PortfolioObservationTimestampDTS][]*PortfolioAllocationDTS
 
 func (aggregationMap portfolioAllocationsPerObservationTimestamp) getOrBuild(
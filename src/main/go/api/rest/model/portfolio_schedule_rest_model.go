@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+// PortfolioScheduleDTS is the REST data transfer structure of a recurring portfolio task. Timezone defaults
+// to UTC and Enabled to true. Params depend on the task type.
+type PortfolioScheduleDTS struct {
+	Id             *langext.ParseableInt64 `json:"id,omitempty"`
+	TaskType       string                  `json:"taskType" validate:"required,max=50"`
+	CronExpression string                  `json:"cronExpression" validate:"required,max=100"`
+	Timezone       string                  `json:"timezone,omitempty" validate:"max=64"`
+	Params         json.RawMessage         `json:"params,omitempty"`
+	Enabled        *bool                   `json:"enabled,omitempty"`
+	NextRunAt      *time.Time              `json:"nextRunAt,omitempty"`
+	CreatedAt      *time.Time              `json:"createdAt,omitempty"`
+}
+
+type PortfolioScheduleRunDTS struct {
+	Id           *langext.ParseableInt64 `json:"id"`
+	ScheduledFor time.Time               `json:"scheduledFor"`
+	Status       string                  `json:"status"`
+	StartedAt    *time.Time              `json:"startedAt,omitempty"`
+	FinishedAt   *time.Time              `json:"finishedAt,omitempty"`
+	Result       json.RawMessage         `json:"result,omitempty"`
+	ErrorMessage string                  `json:"errorMessage,omitempty"`
+}
+
+type PortfolioScheduleRunQueryDTS struct {
+	Limit int `form:"limit" json:"limit" validate:"min=0,max=1000"`
+}
+
+func MapToPortfolioScheduleDTS(schedule *domain.PortfolioSchedule) *PortfolioScheduleDTS {
+
+	if schedule == nil {
+		return nil
+	}
+
+	var scheduleId = langext.ParseableInt64(schedule.Id)
+	var enabled = schedule.Enabled
+	return &PortfolioScheduleDTS{
+		Id:             &scheduleId,
+		TaskType:       string(schedule.TaskType),
+		CronExpression: schedule.CronExpression,
+		Timezone:       schedule.Timezone,
+		Params:         schedule.Params,
+		Enabled:        &enabled,
+		NextRunAt:      schedule.NextRunAt,
+		CreatedAt:      &schedule.CreatedAt,
+	}
+}
+
+func MapToPortfolioScheduleDTSs(schedules []*domain.PortfolioSchedule) []*PortfolioScheduleDTS {
+	var scheduleDTSs = make([]*PortfolioScheduleDTS, 0, len(schedules))
+	for _, schedule := range schedules {
+		scheduleDTSs = append(scheduleDTSs, MapToPortfolioScheduleDTS(schedule))
+	}
+	return scheduleDTSs
+}
+
+func MapToPortfolioSchedule(portfolioId int64, scheduleDTS *PortfolioScheduleDTS) *domain.PortfolioSchedule {
+
+	var enabled = scheduleDTS.Enabled == nil || *scheduleDTS.Enabled
+	return &domain.PortfolioSchedule{
+		PortfolioId:    portfolioId,
+		TaskType:       domain.ScheduledTaskType(scheduleDTS.TaskType),
+		CronExpression: scheduleDTS.CronExpression,
+		Timezone:       scheduleDTS.Timezone,
+		Params:         scheduleDTS.Params,
+		Enabled:        enabled,
+	}
+}
+
+func MapToPortfolioScheduleRunDTSs(runs []*domain.PortfolioScheduleRun) []*PortfolioScheduleRunDTS {
+	var runDTSs = make([]*PortfolioScheduleRunDTS, 0, len(runs))
+	for _, run := range runs {
+		var runId = langext.ParseableInt64(run.Id)
+		runDTSs = append(
+			runDTSs,
+			&PortfolioScheduleRunDTS{
+				Id:           &runId,
+				ScheduledFor: run.ScheduledFor,
+				Status:       string(run.Status),
+				StartedAt:    run.StartedAt,
+				FinishedAt:   run.FinishedAt,
+				Result:       run.Result,
+				ErrorMessage: run.ErrorMessage,
+			},
+		)
+	}
+	return runDTSs
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+// PortfolioWebhookDTS is the REST data transfer structure of a webhook of a portfolio. Enabled defaults to
+// true. The secret signing the deliveries is generated when not given, and is only returned on creation.
+type PortfolioWebhookDTS struct {
+	Id         *langext.ParseableInt64 `json:"id,omitempty"`
+	URL        string                  `json:"url" validate:"required,max=2048"`
+	Secret     string                  `json:"secret,omitempty" validate:"max=128"`
+	EventTypes []string                `json:"eventTypes" validate:"required"`
+	Enabled    *bool                   `json:"enabled,omitempty"`
+	CreatedAt  *time.Time              `json:"createdAt,omitempty"`
+}
+
+type WebhookDeliveryDTS struct {
+	Id             *langext.ParseableInt64 `json:"id"`
+	EventId        *langext.ParseableInt64 `json:"eventId"`
+	EventType      string                  `json:"eventType"`
+	Status         string                  `json:"status"`
+	AttemptCount   int                     `json:"attemptCount"`
+	NextAttemptAt  *time.Time              `json:"nextAttemptAt,omitempty"`
+	LastStatusCode *int                    `json:"lastStatusCode,omitempty"`
+	LastError      string                  `json:"lastError,omitempty"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	DeliveredAt    *time.Time              `json:"deliveredAt,omitempty"`
+}
+
+type WebhookDeliveryQueryDTS struct {
+	Limit int `form:"limit" json:"limit" validate:"min=0,max=1000"`
+}
+
+// MapToPortfolioWebhookDTS maps a webhook to its DTS, leaving the secret out.
+func MapToPortfolioWebhookDTS(webhook *domain.PortfolioWebhook) *PortfolioWebhookDTS {
+
+	if webhook == nil {
+		return nil
+	}
+
+	var webhookId = langext.ParseableInt64(webhook.Id)
+	var enabled = webhook.Enabled
+	var eventTypes = make([]string, len(webhook.EventTypes))
+	for i, eventType := range webhook.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	return &PortfolioWebhookDTS{
+		Id:         &webhookId,
+		URL:        webhook.URL,
+		EventTypes: eventTypes,
+		Enabled:    &enabled,
+		CreatedAt:  &webhook.CreatedAt,
+	}
+}
+
+func MapToPortfolioWebhookDTSs(webhooks []*domain.PortfolioWebhook) []*PortfolioWebhookDTS {
+	var webhookDTSs = make([]*PortfolioWebhookDTS, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookDTSs = append(webhookDTSs, MapToPortfolioWebhookDTS(webhook))
+	}
+	return webhookDTSs
+}
+
+func MapToPortfolioWebhook(portfolioId int64, webhookDTS *PortfolioWebhookDTS) *domain.PortfolioWebhook {
+
+	var enabled = webhookDTS.Enabled == nil || *webhookDTS.Enabled
+	var eventTypes = make([]domain.WebhookEventType, len(webhookDTS.EventTypes))
+	for i, eventType := range webhookDTS.EventTypes {
+		eventTypes[i] = domain.WebhookEventType(eventType)
+	}
+
+	return &domain.PortfolioWebhook{
+		PortfolioId: portfolioId,
+		URL:         webhookDTS.URL,
+		Secret:      webhookDTS.Secret,
+		EventTypes:  eventTypes,
+		Enabled:     enabled,
+	}
+}
+
+func MapToWebhookDeliveryDTSs(deliveries []*domain.WebhookDelivery) []*WebhookDeliveryDTS {
+	var deliveryDTSs = make([]*WebhookDeliveryDTS, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		var deliveryId = langext.ParseableInt64(delivery.Id)
+		var eventId = langext.ParseableInt64(delivery.EventId)
+		deliveryDTSs = append(
+			deliveryDTSs,
+			&WebhookDeliveryDTS{
+				Id:             &deliveryId,
+				EventId:        &eventId,
+				EventType:      string(delivery.EventType),
+				Status:         string(delivery.Status),
+				AttemptCount:   delivery.AttemptCount,
+				NextAttemptAt:  delivery.NextAttemptAt,
+				LastStatusCode: delivery.LastStatusCode,
+				LastError:      delivery.LastError,
+				CreatedAt:      delivery.CreatedAt,
+				DeliveredAt:    delivery.DeliveredAt,
+			},
+		)
+	}
+	return deliveryDTSs
+}
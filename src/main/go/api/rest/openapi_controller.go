@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/infra/openapi"
+)
+
+const (
+	openAPIDocumentTitle   = "Open Asset Allocator API"
+	openAPIDocumentVersion = "pre-alpha"
+	swaggerUIContentType   = "text/html; charset=utf-8"
+	swaggerUIAssetParam    = "asset"
+	// swaggerUIContentSecurityPolicy only allows the Swagger UI page to load its assets from the application
+	swaggerUIContentSecurityPolicy = "default-src 'self'; img-src 'self' data:; style-src 'self' 'unsafe-inline'"
+)
+
+// OpenAPIRESTController serves the OpenAPI document of the routes of the other REST controllers, built once
+// from their metadata, and the Swagger UI browsing it with its embedded assets. All are served without
+// authentication, so the API can be explored before logging in.
+type OpenAPIRESTController struct {
+	document *openapi.Document
+}
+
+func (controller *OpenAPIRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/openapi.json",
+			Handlers:        gin.HandlersChain{controller.getOpenAPIDocument},
+			Unauthenticated: true,
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/docs",
+			Handlers:        gin.HandlersChain{controller.getSwaggerUI},
+			Unauthenticated: true,
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/docs/assets/:" + swaggerUIAssetParam,
+			Handlers:        gin.HandlersChain{controller.getSwaggerUIAsset},
+			Unauthenticated: true,
+		},
+	}
+}
+
+func (controller *OpenAPIRESTController) getOpenAPIDocument(context *gin.Context) {
+	context.JSON(http.StatusOK, controller.document)
+}
+
+// getSwaggerUI serves the Swagger UI page, or a 503 problem when its assets were not vendored in the build.
+func (controller *OpenAPIRESTController) getSwaggerUI(context *gin.Context) {
+
+	if !openapi.IsSwaggerUIVendored() {
+		gininfra.SendProblemResponse(
+			context,
+			http.StatusServiceUnavailable,
+			gininfra.HTTPStatusProblemType(http.StatusServiceUnavailable),
+			"Swagger UI assets are not vendored, run make vendor-swagger-ui before building",
+		)
+		return
+	}
+
+	context.Header("Content-Security-Policy", swaggerUIContentSecurityPolicy)
+	context.Data(http.StatusOK, swaggerUIContentType, openapi.SwaggerUIHTML)
+}
+
+func (controller *OpenAPIRESTController) getSwaggerUIAsset(context *gin.Context) {
+
+	var assetName = context.Param(swaggerUIAssetParam)
+	content, found := openapi.FindSwaggerUIAsset(assetName)
+	if !found {
+		gininfra.SendDataNotFoundResponse(context, "Swagger UI asset", assetName)
+		return
+	}
+
+	context.Data(http.StatusOK, mime.TypeByExtension(path.Ext(assetName)), content)
+}
+
+// BuildOpenAPIRESTController builds the controller documenting the routes of the given controllers. The
+// ways of authenticating the requests are documented when authentication is enabled.
+func BuildOpenAPIRESTController(
+	controllers []infra.GinServerRESTController,
+	authConfig infra.AuthConfiguration,
+) *OpenAPIRESTController {
+
+	var config = openapi.DocumentConfig{
+		Title:                openAPIDocumentTitle,
+		Version:              openAPIDocumentVersion,
+		ErrorContentType:     gininfra.ProblemContentType,
+		ErrorBody:            model.ProblemDetails{},
+		IdempotencyKeyHeader: idempotencyKeyHeader,
+	}
+
+	if authConfig.IsEnabled() {
+		config.SecuritySchemes = map[string]*openapi.SecurityScheme{
+			"apiToken": {
+				Type:        "http",
+				Scheme:      "bearer",
+				Description: "Personal API token of a user",
+			},
+			"session": {
+				Type:        "apiKey",
+				In:          "cookie",
+				Name:        authConfig.SessionCookieName,
+				Description: "Web UI session started by the login",
+			},
+			"shareLink": {
+				Type:        "apiKey",
+				In:          "header",
+				Name:        shareTokenHeader,
+				Description: "Read-only share link token, only for reads of the shared portfolio",
+			},
+		}
+	}
+
+	return &OpenAPIRESTController{
+		document: openapi.BuildDocument(config, controllers),
+	}
+}
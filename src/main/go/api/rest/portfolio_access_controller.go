@@ -0,0 +1,246 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+// PortfolioAccessRESTController handles the access grants and the read-only share links of the portfolios,
+// only managed by their owners through web UI sessions.
+type PortfolioAccessRESTController struct {
+	portfolioAccessDomService *service.PortfolioAccessDomService
+}
+
+func (controller *PortfolioAccessRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/grant",
+			Handlers: gin.HandlersChain{requireSession, controller.requireOwner, controller.getGrants},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the access grants of the portfolio",
+				ResponseBody: []*model.PortfolioGrantDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPut,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/grant",
+			Handlers: gin.HandlersChain{requireSession, controller.requireOwner, controller.putGrant},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Grant a user access to the portfolio",
+				RequestBody:  model.PortfolioGrantDTS{},
+				ResponseBody: model.PortfolioGrantDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/grant/:" + grantUserIdParam,
+			Handlers: gin.HandlersChain{requireSession, controller.requireOwner, controller.deleteGrant},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Revoke the access of a user to the portfolio",
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/share-link",
+			Handlers: gin.HandlersChain{requireSession, controller.requireOwner, controller.getShareLinks},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the read-only share links of the portfolio",
+				ResponseBody: []*model.PortfolioShareLinkDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/share-link",
+			Handlers: gin.HandlersChain{requireSession, controller.requireOwner, controller.postShareLink},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Create a read-only share link of the portfolio",
+				RequestBody:    model.PortfolioShareLinkDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.PortfolioShareLinkDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/share-link/:" + shareLinkIdParam,
+			Handlers: gin.HandlersChain{requireSession, controller.requireOwner, controller.deleteShareLink},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Revoke a read-only share link of the portfolio",
+			},
+		},
+	}
+}
+
+// requireOwner rejects the requests of principals other than the owner of the portfolio.
+func (controller *PortfolioAccessRESTController) requireOwner(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		context.Abort()
+		return
+	}
+
+	if !authorizePortfolioRole(
+		context,
+		controller.portfolioAccessDomService,
+		getPrincipal(context),
+		portfolioId,
+		domain.OwnerPortfolioRole,
+	) {
+		return
+	}
+
+	context.Next()
+}
+
+// getGrants handles GET requests listing the users granted access to a portfolio.
+func (controller *PortfolioAccessRESTController) getGrants(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	grants, err := controller.portfolioAccessDomService.GetPortfolioGrants(portfolioId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio grants", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioGrantDTSs(grants))
+}
+
+// putGrant handles PUT requests granting a user access to a portfolio, replacing any role previously
+// granted to them.
+func (controller *PortfolioAccessRESTController) putGrant(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	var grantDTS model.PortfolioGrantDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &grantDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindPortfolioGrantErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	grant, err := controller.portfolioAccessDomService.GrantPortfolioAccess(
+		portfolioId,
+		grantDTS.Username,
+		domain.PortfolioRole(grantDTS.Role),
+	)
+	if gininfra.HandleAPIError(context, "Error granting portfolio access", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioGrantDTS(grant))
+}
+
+// deleteGrant handles DELETE requests removing the access granted to a user on a portfolio.
+func (controller *PortfolioAccessRESTController) deleteGrant(context *gin.Context) {
+
+	portfolioId, userId, ok := getPortfolioChildIdParams(context, grantUserIdParam, getGrantUserIdErrorMessage)
+	if !ok {
+		return
+	}
+
+	revoked, err := controller.portfolioAccessDomService.RevokePortfolioAccess(portfolioId, userId)
+	if gininfra.HandleAPIError(context, "Error revoking portfolio access", err) {
+		return
+	}
+
+	if !revoked {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio grant", context.Param(grantUserIdParam))
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// getShareLinks handles GET requests listing the share links of a portfolio.
+func (controller *PortfolioAccessRESTController) getShareLinks(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	shareLinks, err := controller.portfolioAccessDomService.GetPortfolioShareLinks(portfolioId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio share links", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioShareLinkDTSs(shareLinks))
+}
+
+// postShareLink handles POST requests creating a read-only share link of a portfolio. The response is the
+// only one holding the token of the link.
+func (controller *PortfolioAccessRESTController) postShareLink(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	var shareLinkDTS model.PortfolioShareLinkDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &shareLinkDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindPortfolioShareLinkErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	shareLink, rawToken, err := controller.portfolioAccessDomService.CreateShareLink(
+		portfolioId,
+		getPrincipal(context).User.Id,
+		*shareLinkDTS.ExpiresAt,
+	)
+	if gininfra.HandleAPIError(context, "Error creating portfolio share link", err) {
+		return
+	}
+
+	var createdShareLinkDTS = model.MapToPortfolioShareLinkDTS(shareLink)
+	createdShareLinkDTS.Token = rawToken
+	context.JSON(http.StatusCreated, createdShareLinkDTS)
+}
+
+// deleteShareLink handles DELETE requests revoking a share link of a portfolio.
+func (controller *PortfolioAccessRESTController) deleteShareLink(context *gin.Context) {
+
+	portfolioId, shareLinkId, ok := getPortfolioChildIdParams(context, shareLinkIdParam, getShareLinkIdErrorMessage)
+	if !ok {
+		return
+	}
+
+	revoked, err := controller.portfolioAccessDomService.RevokeShareLink(portfolioId, shareLinkId)
+	if gininfra.HandleAPIError(context, "Error revoking portfolio share link", err) {
+		return
+	}
+
+	if !revoked {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio share link", context.Param(shareLinkIdParam))
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+func BuildPortfolioAccessRESTController(
+	portfolioAccessDomService *service.PortfolioAccessDomService,
+) *PortfolioAccessRESTController {
+	return &PortfolioAccessRESTController{portfolioAccessDomService: portfolioAccessDomService}
+}
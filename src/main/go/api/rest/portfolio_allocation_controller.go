@@ -1,6 +1,7 @@
 package rest
 
 import (
+	stdcontext "context"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -20,6 +21,7 @@ import (
 type PortfolioAllocationRESTController struct {
 	portfolioAllocationDomService           *service.PortfolioAllocationDomService
 	portfolioAllocationManagementAppService *application.PortfolioAllocationManagementAppService
+	assetDomService                         *service.AssetDomService
 }
 
 func (controller *PortfolioAllocationRESTController) BuildRoutes() []infra.RESTRoute {
@@ -28,16 +30,29 @@ func (controller *PortfolioAllocationRESTController) BuildRoutes() []infra.RESTR
 			Method:   http.MethodGet,
 			Path:     "/api/portfolio/:" + portfolioIdParam + "/history",
 			Handlers: gin.HandlersChain{controller.getPortfolioAllocationHistory},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the allocation history of the portfolio, by observation",
+				Query:        model.PortfolioHistoryQueryDTS{},
+				ResponseBody: []*model.PortfolioSnapshotDTS{},
+			},
 		},
 		{
 			Method:   http.MethodPost,
 			Path:     "/api/portfolio/:" + portfolioIdParam + "/history",
 			Handlers: gin.HandlersChain{controller.postPortfolioAllocationHistory},
+			Doc: infra.RESTRouteDoc{
+				Summary:     "Create or update an observation snapshot of the portfolio allocations",
+				RequestBody: model.PortfolioSnapshotDTS{},
+			},
 		},
 		{
 			Method:   http.MethodGet,
 			Path:     "/api/portfolio/:" + portfolioIdParam + "/history/observation",
 			Handlers: gin.HandlersChain{controller.getAvailableHistoryObservations},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the observations of the portfolio history",
+				ResponseBody: []*model.PortfolioObservationTimestampDTS{},
+			},
 		},
 	}
 }
@@ -59,9 +74,27 @@ func (controller *PortfolioAllocationRESTController) getPortfolioAllocationHisto
 		}
 	}
 
+	var historyQueryDTS model.PortfolioHistoryQueryDTS
+	valid, err := gininfra.BindAndValidateQueryWithInvalidResponse(context, &historyQueryDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindPortfolioHistoryQueryErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	// the version is read first, so it is never newer than the allocations read after it
+	if !langext.IsZeroValue(observationTimestampId) &&
+		!controller.setPortfolioSnapshotETag(context, portfolioId, observationTimestampId) {
+		return
+	}
+
 	portfolioHistory, err := controller.getPortfolioAllocationHistoryUpstack(
+		context.Request.Context(),
 		portfolioId,
 		observationTimestampId,
+		historyQueryDTS.SplitAdjusted,
 	)
 	if err != nil {
 		var errorDetail string
@@ -81,9 +114,33 @@ func (controller *PortfolioAllocationRESTController) getPortfolioAllocationHisto
 	context.JSON(http.StatusOK, aggregatedPortfoliohistoryDTS)
 }
 
+// setPortfolioSnapshotETag sets the ETag of the allocations of a portfolio in an observation, when the
+// portfolio has any, returning false when the error response was sent instead.
+func (controller *PortfolioAllocationRESTController) setPortfolioSnapshotETag(
+	context *gin.Context,
+	portfolioId int64,
+	observationTimestampId int64,
+) bool {
+
+	snapshotVersion, err := controller.portfolioAllocationDomService.GetPortfolioSnapshotVersion(
+		portfolioId,
+		observationTimestampId,
+	)
+	if gininfra.HandleAPIError(context, "Error getting portfolio allocations version", err) {
+		return false
+	}
+
+	if !langext.IsZeroValue(snapshotVersion) {
+		gininfra.SetVersionETag(context, snapshotVersion)
+	}
+	return true
+}
+
 func (controller *PortfolioAllocationRESTController) getPortfolioAllocationHistoryUpstack(
+	requestContext stdcontext.Context,
 	portfolioId int64,
 	observationTimestampId int64,
+	splitAdjusted bool,
 ) ([]*domain.PortfolioAllocation, error) {
 
 	var portfolioHistory []*domain.PortfolioAllocation
@@ -91,6 +148,7 @@ func (controller *PortfolioAllocationRESTController) getPortfolioAllocationHisto
 
 	if !langext.IsZeroValue(observationTimestampId) {
 		portfolioHistory, err = controller.portfolioAllocationDomService.FindPortfolioAllocationsByObservationTimestamp(
+			requestContext,
 			portfolioId,
 			observationTimestampId,
 		)
@@ -98,6 +156,10 @@ func (controller *PortfolioAllocationRESTController) getPortfolioAllocationHisto
 		portfolioHistory, err = controller.portfolioAllocationDomService.GetPortfolioAllocationHistory(portfolioId)
 	}
 
+	if err == nil && splitAdjusted {
+		err = controller.assetDomService.AdjustPortfolioAllocationsForSplits(portfolioHistory)
+	}
+
 	return portfolioHistory, err
 }
 
@@ -147,19 +209,58 @@ func (controller *PortfolioAllocationRESTController) postPortfolioAllocationHist
 
 	var observationTimestamp = model.MapToPortfolioObservationTimestamp(portfolioSnapshotDTS.ObservationTimestamp)
 
-	err = controller.portfolioAllocationManagementAppService.MergePortfolioAllocations(
+	snapshotVersion, err := controller.portfolioAllocationManagementAppService.MergePortfolioAllocations(
+		auditContext(context),
 		portfolioId,
 		observationTimestamp,
 		portfolioAllocations,
+		gininfra.GetIfMatchVersion(context),
 	)
 
+	if isVersionConflict(err) {
+		controller.sendCurrentPortfolioSnapshot(context, portfolioId, observationTimestamp.Id)
+		return
+	}
 	if gininfra.HandleAPIError(context, "Error merging portfolio allocations", err) {
 		return
 	}
 
+	gininfra.SetVersionETag(context, snapshotVersion)
 	context.Status(http.StatusNoContent)
 }
 
+// sendCurrentPortfolioSnapshot answers a merge based on a stale version of the allocations of a portfolio in
+// an observation with their current version.
+func (controller *PortfolioAllocationRESTController) sendCurrentPortfolioSnapshot(
+	context *gin.Context,
+	portfolioId int64,
+	observationTimestampId int64,
+) {
+
+	currentVersion, err := controller.portfolioAllocationDomService.GetPortfolioSnapshotVersion(
+		portfolioId,
+		observationTimestampId,
+	)
+	if gininfra.HandleAPIError(context, "Error getting current portfolio allocations version", err) {
+		return
+	}
+
+	currentAllocations, err := controller.portfolioAllocationDomService.FindPortfolioAllocationsByObservationTimestamp(
+		context.Request.Context(),
+		portfolioId,
+		observationTimestampId,
+	)
+	if gininfra.HandleAPIError(context, "Error getting current portfolio allocations", err) {
+		return
+	}
+
+	gininfra.SendPreconditionFailedResponse(
+		context,
+		currentVersion,
+		model.AggregateAndMapToPortfolioHistoryDTSs(currentAllocations),
+	)
+}
+
 func (controller *PortfolioAllocationRESTController) validateCleanPortfolioAllocationHistory(
 	context *gin.Context,
 	portfolioSnapshotDTS *model.PortfolioSnapshotDTS,
@@ -210,9 +311,11 @@ func validateAssetIdentification(
 func BuildPortfolioAllocationRESTController(
 	portfolioAllocationDomService *service.PortfolioAllocationDomService,
 	portfolioAllocationManagementAppService *application.PortfolioAllocationManagementAppService,
+	assetDomService *service.AssetDomService,
 ) *PortfolioAllocationRESTController {
 	return &PortfolioAllocationRESTController{
 		portfolioAllocationDomService,
 		portfolioAllocationManagementAppService,
+		assetDomService,
 	}
 }
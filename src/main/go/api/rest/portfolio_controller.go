@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/application"
+	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/domain/service"
 	"github.com/benizzio/open-asset-allocator/infra"
 	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
@@ -14,8 +16,11 @@ import (
 )
 
 type PortfolioRESTController struct {
-	portfolioDomService  *service.PortfolioDomService
-	allocationDomService *service.AllocationDomService
+	portfolioManagementAppService *application.PortfolioManagementAppService
+	portfolioDomService           *service.PortfolioDomService
+	allocationDomService          *service.AllocationDomService
+	assetDomService               *service.AssetDomService
+	portfolioAccessDomService     *service.PortfolioAccessDomService
 }
 
 func (controller *PortfolioRESTController) BuildRoutes() []infra.RESTRoute {
@@ -24,33 +29,82 @@ func (controller *PortfolioRESTController) BuildRoutes() []infra.RESTRoute {
 			Method:   http.MethodGet,
 			Path:     "/api/portfolio",
 			Handlers: gin.HandlersChain{controller.getPortfolios},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the portfolios",
+				ResponseBody: []model.PortfolioDTS{},
+			},
 		},
 		{
 			Method:   http.MethodGet,
 			Path:     "/api/portfolio/:" + portfolioIdParam,
 			Handlers: gin.HandlersChain{controller.getPortfolio},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get a portfolio",
+				ResponseBody: model.PortfolioDTS{},
+			},
 		},
 		{
 			Method:   http.MethodPost,
 			Path:     "/api/portfolio",
 			Handlers: gin.HandlersChain{controller.postPortfolio},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Create a portfolio",
+				RequestBody:    model.PortfolioDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.PortfolioDTS{},
+			},
 		},
 		{
 			Method:   http.MethodPut,
 			Path:     "/api/portfolio",
 			Handlers: gin.HandlersChain{controller.putPortfolio},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Update a portfolio",
+				RequestBody:  model.PortfolioDTS{},
+				ResponseBody: model.PortfolioDTS{},
+			},
 		},
 		{
 			Method:   http.MethodGet,
 			Path:     "/api/portfolio/:" + portfolioIdParam + "/allocation-classes",
 			Handlers: gin.HandlersChain{controller.getAvailablePortfolioAllocationClasses},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the allocation classes of the portfolio history and allocation plans",
+				ResponseBody: []string{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/quote",
+			Handlers: gin.HandlersChain{controller.getPortfolioAssetQuotes},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Quote the assets of the latest portfolio observation",
+				ResponseBody: []*model.AssetQuoteResultDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/income",
+			Handlers: gin.HandlersChain{controller.getPortfolioIncome},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the dividend income of the portfolio",
+				ResponseBody: []*model.PortfolioIncomeRecordDTS{},
+			},
 		},
 	}
 }
 
+// getPortfolios lists the portfolios, only the ones the authenticated user owns or was granted access to
+// when authentication is enabled.
 func (controller *PortfolioRESTController) getPortfolios(context *gin.Context) {
 
-	portfolios, err := controller.portfolioDomService.GetPortfolios()
+	var portfolios []*domain.Portfolio
+	var err error
+	if principal := findPrincipal(context); principal != nil {
+		portfolios, err = controller.portfolioDomService.GetUserPortfolios(principal.User.Id)
+	} else {
+		portfolios, err = controller.portfolioDomService.GetPortfolios()
+	}
 	if gininfra.HandleAPIError(context, "Error getting portfolios", err) {
 		return
 	}
@@ -68,12 +122,13 @@ func (controller *PortfolioRESTController) getPortfolio(context *gin.Context) {
 		return
 	}
 
-	portfolio, err := controller.portfolioDomService.GetPortfolio(portfolioId)
+	portfolio, err := controller.portfolioDomService.GetPortfolio(context.Request.Context(), portfolioId)
 	if gininfra.HandleAPIError(context, "Error getting portfolio", err) {
 		return
 	}
 
 	portfolioDTS := model.MapToPortfolioDTS(portfolio)
+	gininfra.SetVersionETag(context, portfolio.Version)
 
 	context.JSON(http.StatusOK, portfolioDTS)
 }
@@ -91,12 +146,17 @@ func (controller *PortfolioRESTController) postPortfolio(context *gin.Context) {
 	}
 
 	var portfolio = model.MapToPortfolio(&portfolioDTS)
-	persistedPortfolio, err := controller.portfolioDomService.PersistPortfolio(portfolio)
+	if principal := findPrincipal(context); principal != nil {
+		portfolio.OwnerUserId = &principal.User.Id
+	}
+
+	persistedPortfolio, err := controller.portfolioManagementAppService.PersistPortfolio(auditContext(context), portfolio)
 	if gininfra.HandleAPIError(context, "Error creating portfolio", err) {
 		return
 	}
 
 	var responseBody = model.MapToPortfolioDTS(persistedPortfolio)
+	gininfra.SetVersionETag(context, persistedPortfolio.Version)
 	context.JSON(http.StatusCreated, responseBody)
 }
 
@@ -129,16 +189,49 @@ func (controller *PortfolioRESTController) putPortfolio(context *gin.Context) {
 		return
 	}
 
+	var principal = findPrincipal(context)
+	if principal != nil && !authorizePortfolioRole(
+		context,
+		controller.portfolioAccessDomService,
+		principal,
+		int64(*portfolioDTS.Id),
+		domain.EditorPortfolioRole,
+	) {
+		return
+	}
+
 	var portfolio = model.MapToPortfolio(&portfolioDTS)
-	persistedPortfolio, err := controller.portfolioDomService.PersistPortfolio(portfolio)
+	portfolio.Version = gininfra.GetIfMatchVersion(context)
+
+	persistedPortfolio, err := controller.portfolioManagementAppService.PersistPortfolio(auditContext(context), portfolio)
+	if isVersionConflict(err) {
+		controller.sendCurrentPortfolio(context, portfolio.Id)
+		return
+	}
 	if gininfra.HandleAPIError(context, "Error updating portfolio", err) {
 		return
 	}
 
 	var responseBody = model.MapToPortfolioDTS(persistedPortfolio)
+	gininfra.SetVersionETag(context, persistedPortfolio.Version)
 	context.JSON(http.StatusOK, responseBody)
 }
 
+// sendCurrentPortfolio answers an update based on a stale version of a portfolio with its current version.
+func (controller *PortfolioRESTController) sendCurrentPortfolio(context *gin.Context, portfolioId int64) {
+
+	currentPortfolio, err := controller.portfolioDomService.GetPortfolio(context.Request.Context(), portfolioId)
+	if gininfra.HandleAPIError(context, "Error getting current portfolio", err) {
+		return
+	}
+
+	gininfra.SendPreconditionFailedResponse(
+		context,
+		currentPortfolio.Version,
+		model.MapToPortfolioDTS(currentPortfolio),
+	)
+}
+
 // getAvailablePortfolioAllocationClasses returns allocation classes from both portfolio
 // allocation history and planned allocations. This endpoint replaces the deprecated endpoint
 // in PortfolioAllocationRESTController.
@@ -160,12 +253,57 @@ func (controller *PortfolioRESTController) getAvailablePortfolioAllocationClasse
 	context.JSON(http.StatusOK, availableClasses)
 }
 
+// getPortfolioAssetQuotes quotes the last close prices of the assets held in the latest observation of
+// the portfolio. Assets that cannot be quoted are reported with their error instead of failing the
+// whole request.
+func (controller *PortfolioRESTController) getPortfolioAssetQuotes(context *gin.Context) {
+
+	var portfolioIdParam = context.Param(portfolioIdParam)
+	portfolioId, err := langext.ParseInt64(portfolioIdParam)
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	assets, err := controller.assetDomService.GetPortfolioAssets(portfolioId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio assets", err) {
+		return
+	}
+
+	var results = controller.assetDomService.QuoteAssetsLastClosePrice(context.Request.Context(), assets)
+
+	context.JSON(http.StatusOK, model.MapToAssetQuoteResultDTSs(results))
+}
+
+// getPortfolioIncome lists the dividend income of the portfolio derived from the recorded dividends of
+// its assets, from the most recent ex-dividend date.
+func (controller *PortfolioRESTController) getPortfolioIncome(context *gin.Context) {
+
+	var portfolioIdParam = context.Param(portfolioIdParam)
+	portfolioId, err := langext.ParseInt64(portfolioIdParam)
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	incomeRecords, err := controller.assetDomService.GetPortfolioIncomeRecords(portfolioId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio income", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioIncomeRecordDTSs(incomeRecords))
+}
+
 func BuildPortfolioRESTController(
+	portfolioManagementAppService *application.PortfolioManagementAppService,
 	portfolioDomService *service.PortfolioDomService,
 	allocationDomService *service.AllocationDomService,
+	assetDomService *service.AssetDomService,
+	portfolioAccessDomService *service.PortfolioAccessDomService,
 ) *PortfolioRESTController {
 	return &PortfolioRESTController{
+		portfolioManagementAppService,
 		portfolioDomService,
 		allocationDomService,
+		assetDomService,
+		portfolioAccessDomService,
 	}
 }
@@ -0,0 +1,263 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const defaultScheduleRunsLimit = 50
+
+type PortfolioScheduleRESTController struct {
+	portfolioScheduleDomService *service.PortfolioScheduleDomService
+}
+
+func (controller *PortfolioScheduleRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/schedule",
+			Handlers: gin.HandlersChain{controller.getPortfolioSchedules},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the scheduled tasks of the portfolio",
+				ResponseBody: []*model.PortfolioScheduleDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/schedule",
+			Handlers: gin.HandlersChain{controller.postPortfolioSchedule},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Schedule a task of the portfolio",
+				RequestBody:    model.PortfolioScheduleDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.PortfolioScheduleDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/schedule/:" + scheduleIdParam,
+			Handlers: gin.HandlersChain{controller.getPortfolioSchedule},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get a scheduled task of the portfolio",
+				ResponseBody: model.PortfolioScheduleDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPut,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/schedule/:" + scheduleIdParam,
+			Handlers: gin.HandlersChain{controller.putPortfolioSchedule},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Update a scheduled task of the portfolio",
+				RequestBody:  model.PortfolioScheduleDTS{},
+				ResponseBody: model.PortfolioScheduleDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/schedule/:" + scheduleIdParam,
+			Handlers: gin.HandlersChain{controller.deletePortfolioSchedule},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Delete a scheduled task of the portfolio",
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/schedule/:" + scheduleIdParam + "/run",
+			Handlers: gin.HandlersChain{controller.getPortfolioScheduleRuns},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the runs of a scheduled task of the portfolio",
+				Query:        model.PortfolioScheduleRunQueryDTS{},
+				ResponseBody: []*model.PortfolioScheduleRunDTS{},
+			},
+		},
+	}
+}
+
+// getPortfolioSchedules handles GET requests listing the schedules of a portfolio.
+func (controller *PortfolioScheduleRESTController) getPortfolioSchedules(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	schedules, err := controller.portfolioScheduleDomService.GetPortfolioSchedules(portfolioId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio schedules", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioScheduleDTSs(schedules))
+}
+
+// postPortfolioSchedule handles POST requests creating a schedule of a portfolio.
+func (controller *PortfolioScheduleRESTController) postPortfolioSchedule(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	var scheduleDTS model.PortfolioScheduleDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &scheduleDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindPortfolioScheduleErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var schedule = model.MapToPortfolioSchedule(portfolioId, &scheduleDTS)
+	persistedSchedule, err := controller.portfolioScheduleDomService.InsertSchedule(schedule)
+	if gininfra.HandleAPIError(context, "Error inserting portfolio schedule", err) {
+		return
+	}
+
+	context.JSON(http.StatusCreated, model.MapToPortfolioScheduleDTS(persistedSchedule))
+}
+
+// getPortfolioSchedule handles GET requests for a schedule of a portfolio.
+func (controller *PortfolioScheduleRESTController) getPortfolioSchedule(context *gin.Context) {
+
+	portfolioId, scheduleId, ok := getPortfolioScheduleIdParams(context)
+	if !ok {
+		return
+	}
+
+	schedule, err := controller.portfolioScheduleDomService.GetPortfolioSchedule(portfolioId, scheduleId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio schedule", err) {
+		return
+	}
+
+	if schedule == nil {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio schedule", context.Param(scheduleIdParam))
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioScheduleDTS(schedule))
+}
+
+// putPortfolioSchedule handles PUT requests replacing the definition of a schedule of a portfolio. The
+// next activation is recomputed from the new definition.
+func (controller *PortfolioScheduleRESTController) putPortfolioSchedule(context *gin.Context) {
+
+	portfolioId, scheduleId, ok := getPortfolioScheduleIdParams(context)
+	if !ok {
+		return
+	}
+
+	var scheduleDTS model.PortfolioScheduleDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &scheduleDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindPortfolioScheduleErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var schedule = model.MapToPortfolioSchedule(portfolioId, &scheduleDTS)
+	schedule.Id = scheduleId
+	updatedSchedule, err := controller.portfolioScheduleDomService.UpdateSchedule(schedule)
+	if gininfra.HandleAPIError(context, "Error updating portfolio schedule", err) {
+		return
+	}
+
+	if updatedSchedule == nil {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio schedule", context.Param(scheduleIdParam))
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioScheduleDTS(updatedSchedule))
+}
+
+// deletePortfolioSchedule handles DELETE requests removing a schedule of a portfolio with its run history.
+func (controller *PortfolioScheduleRESTController) deletePortfolioSchedule(context *gin.Context) {
+
+	portfolioId, scheduleId, ok := getPortfolioScheduleIdParams(context)
+	if !ok {
+		return
+	}
+
+	deleted, err := controller.portfolioScheduleDomService.DeleteSchedule(portfolioId, scheduleId)
+	if gininfra.HandleAPIError(context, "Error deleting portfolio schedule", err) {
+		return
+	}
+
+	if !deleted {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio schedule", context.Param(scheduleIdParam))
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// getPortfolioScheduleRuns handles GET requests listing the latest runs of a schedule of a portfolio, the
+// most recent first, including the missed and failed ones.
+func (controller *PortfolioScheduleRESTController) getPortfolioScheduleRuns(context *gin.Context) {
+
+	portfolioId, scheduleId, ok := getPortfolioScheduleIdParams(context)
+	if !ok {
+		return
+	}
+
+	var queryDTS model.PortfolioScheduleRunQueryDTS
+	valid, err := gininfra.BindAndValidateQueryWithInvalidResponse(context, &queryDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindScheduleRunQueryErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	schedule, err := controller.portfolioScheduleDomService.GetPortfolioSchedule(portfolioId, scheduleId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio schedule", err) {
+		return
+	}
+
+	if schedule == nil {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio schedule", context.Param(scheduleIdParam))
+		return
+	}
+
+	var limit = queryDTS.Limit
+	if limit == 0 {
+		limit = defaultScheduleRunsLimit
+	}
+
+	runs, err := controller.portfolioScheduleDomService.GetScheduleRuns(schedule.Id, limit)
+	if gininfra.HandleAPIError(context, "Error getting schedule runs", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioScheduleRunDTSs(runs))
+}
+
+func getPortfolioScheduleIdParams(context *gin.Context) (int64, int64, bool) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return 0, 0, false
+	}
+
+	scheduleId, err := langext.ParseInt64(context.Param(scheduleIdParam))
+	if gininfra.HandleAPIError(context, getScheduleIdErrorMessage, err) {
+		return 0, 0, false
+	}
+
+	return portfolioId, scheduleId, true
+}
+
+func BuildPortfolioScheduleRESTController(
+	portfolioScheduleDomService *service.PortfolioScheduleDomService,
+) *PortfolioScheduleRESTController {
+	return &PortfolioScheduleRESTController{portfolioScheduleDomService: portfolioScheduleDomService}
+}
@@ -0,0 +1,264 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	gininfra "github.com/benizzio/open-asset-allocator/infra/gin"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const defaultWebhookDeliveriesLimit = 50
+
+type PortfolioWebhookRESTController struct {
+	webhookDomService *service.WebhookDomService
+}
+
+func (controller *PortfolioWebhookRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/webhook",
+			Handlers: gin.HandlersChain{controller.getPortfolioWebhooks},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the webhooks of the portfolio",
+				ResponseBody: []*model.PortfolioWebhookDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/webhook",
+			Handlers: gin.HandlersChain{controller.postPortfolioWebhook},
+			Doc: infra.RESTRouteDoc{
+				Summary:        "Register a webhook of the portfolio",
+				RequestBody:    model.PortfolioWebhookDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   model.PortfolioWebhookDTS{},
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/webhook/:" + webhookIdParam,
+			Handlers: gin.HandlersChain{controller.getPortfolioWebhook},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get a webhook of the portfolio",
+				ResponseBody: model.PortfolioWebhookDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPut,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/webhook/:" + webhookIdParam,
+			Handlers: gin.HandlersChain{controller.putPortfolioWebhook},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Update a webhook of the portfolio",
+				RequestBody:  model.PortfolioWebhookDTS{},
+				ResponseBody: model.PortfolioWebhookDTS{},
+			},
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/webhook/:" + webhookIdParam,
+			Handlers: gin.HandlersChain{controller.deletePortfolioWebhook},
+			Doc: infra.RESTRouteDoc{
+				Summary: "Delete a webhook of the portfolio",
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/portfolio/:" + portfolioIdParam + "/webhook/:" + webhookIdParam + "/delivery",
+			Handlers: gin.HandlersChain{controller.getWebhookDeliveries},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "List the deliveries of a webhook of the portfolio",
+				Query:        model.WebhookDeliveryQueryDTS{},
+				ResponseBody: []*model.WebhookDeliveryDTS{},
+			},
+		},
+	}
+}
+
+// getPortfolioWebhooks handles GET requests listing the webhooks of a portfolio.
+func (controller *PortfolioWebhookRESTController) getPortfolioWebhooks(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	webhooks, err := controller.webhookDomService.GetPortfolioWebhooks(portfolioId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio webhooks", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioWebhookDTSs(webhooks))
+}
+
+// postPortfolioWebhook handles POST requests registering a webhook of a portfolio. The response is the
+// only one carrying the secret of the webhook.
+func (controller *PortfolioWebhookRESTController) postPortfolioWebhook(context *gin.Context) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return
+	}
+
+	var webhookDTS model.PortfolioWebhookDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &webhookDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindPortfolioWebhookErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var webhook = model.MapToPortfolioWebhook(portfolioId, &webhookDTS)
+	persistedWebhook, err := controller.webhookDomService.InsertWebhook(webhook)
+	if gininfra.HandleAPIError(context, "Error inserting portfolio webhook", err) {
+		return
+	}
+
+	var persistedWebhookDTS = model.MapToPortfolioWebhookDTS(persistedWebhook)
+	persistedWebhookDTS.Secret = persistedWebhook.Secret
+	context.JSON(http.StatusCreated, persistedWebhookDTS)
+}
+
+// getPortfolioWebhook handles GET requests for a webhook of a portfolio.
+func (controller *PortfolioWebhookRESTController) getPortfolioWebhook(context *gin.Context) {
+
+	portfolioId, webhookId, ok := getPortfolioWebhookIdParams(context)
+	if !ok {
+		return
+	}
+
+	webhook, err := controller.webhookDomService.GetPortfolioWebhook(portfolioId, webhookId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio webhook", err) {
+		return
+	}
+
+	if webhook == nil {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio webhook", context.Param(webhookIdParam))
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioWebhookDTS(webhook))
+}
+
+// putPortfolioWebhook handles PUT requests replacing the definition of a webhook of a portfolio. The
+// secret is kept when the request does not give a new one.
+func (controller *PortfolioWebhookRESTController) putPortfolioWebhook(context *gin.Context) {
+
+	portfolioId, webhookId, ok := getPortfolioWebhookIdParams(context)
+	if !ok {
+		return
+	}
+
+	var webhookDTS model.PortfolioWebhookDTS
+	valid, err := gininfra.BindAndValidateJSONWithInvalidResponse(context, &webhookDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindPortfolioWebhookErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	var webhook = model.MapToPortfolioWebhook(portfolioId, &webhookDTS)
+	webhook.Id = webhookId
+	updatedWebhook, err := controller.webhookDomService.UpdateWebhook(webhook)
+	if gininfra.HandleAPIError(context, "Error updating portfolio webhook", err) {
+		return
+	}
+
+	if updatedWebhook == nil {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio webhook", context.Param(webhookIdParam))
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToPortfolioWebhookDTS(updatedWebhook))
+}
+
+// deletePortfolioWebhook handles DELETE requests removing a webhook of a portfolio with its deliveries.
+func (controller *PortfolioWebhookRESTController) deletePortfolioWebhook(context *gin.Context) {
+
+	portfolioId, webhookId, ok := getPortfolioWebhookIdParams(context)
+	if !ok {
+		return
+	}
+
+	deleted, err := controller.webhookDomService.DeleteWebhook(portfolioId, webhookId)
+	if gininfra.HandleAPIError(context, "Error deleting portfolio webhook", err) {
+		return
+	}
+
+	if !deleted {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio webhook", context.Param(webhookIdParam))
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+// getWebhookDeliveries handles GET requests listing the latest deliveries of a webhook of a portfolio,
+// the most recent first, with the outcome of their last attempt.
+func (controller *PortfolioWebhookRESTController) getWebhookDeliveries(context *gin.Context) {
+
+	portfolioId, webhookId, ok := getPortfolioWebhookIdParams(context)
+	if !ok {
+		return
+	}
+
+	var queryDTS model.WebhookDeliveryQueryDTS
+	valid, err := gininfra.BindAndValidateQueryWithInvalidResponse(context, &queryDTS)
+	if err != nil {
+		gininfra.HandleAPIError(context, bindWebhookDeliveryQueryErrorMessage, err)
+		return
+	}
+	if !valid {
+		return
+	}
+
+	webhook, err := controller.webhookDomService.GetPortfolioWebhook(portfolioId, webhookId)
+	if gininfra.HandleAPIError(context, "Error getting portfolio webhook", err) {
+		return
+	}
+
+	if webhook == nil {
+		gininfra.SendDataNotFoundResponse(context, "Portfolio webhook", context.Param(webhookIdParam))
+		return
+	}
+
+	var limit = queryDTS.Limit
+	if limit == 0 {
+		limit = defaultWebhookDeliveriesLimit
+	}
+
+	deliveries, err := controller.webhookDomService.GetWebhookDeliveries(webhook.Id, limit)
+	if gininfra.HandleAPIError(context, "Error getting webhook deliveries", err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, model.MapToWebhookDeliveryDTSs(deliveries))
+}
+
+func getPortfolioWebhookIdParams(context *gin.Context) (int64, int64, bool) {
+
+	portfolioId, err := langext.ParseInt64(context.Param(portfolioIdParam))
+	if gininfra.HandleAPIError(context, getPortfolioIdErrorMessage, err) {
+		return 0, 0, false
+	}
+
+	webhookId, err := langext.ParseInt64(context.Param(webhookIdParam))
+	if gininfra.HandleAPIError(context, getWebhookIdErrorMessage, err) {
+		return 0, 0, false
+	}
+
+	return portfolioId, webhookId, true
+}
+
+func BuildPortfolioWebhookRESTController(webhookDomService *service.WebhookDomService) *PortfolioWebhookRESTController {
+	return &PortfolioWebhookRESTController{webhookDomService: webhookDomService}
+}
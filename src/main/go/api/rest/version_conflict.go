@@ -0,0 +1,14 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// isVersionConflict reports whether a change was rejected for being based on a stale version, to be answered
+// with the current representation of the changed data.
+func isVersionConflict(err error) bool {
+	var conflictErr *infra.VersionConflictError
+	return errors.As(err, &conflictErr)
+}
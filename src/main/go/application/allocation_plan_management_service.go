@@ -2,52 +2,95 @@ package application
 
 import (
 	"context"
-	"errors"
 
 	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/domain/service"
-	"github.com/benizzio/open-asset-allocator/infra"
 	"github.com/benizzio/open-asset-allocator/infra/rdbms"
 	"github.com/benizzio/open-asset-allocator/langext"
 )
 
+// allocationPlanPersistedEventPayload is the payload of the ALLOCATION_PLAN_PERSISTED webhook event.
+type allocationPlanPersistedEventPayload struct {
+	AllocationPlanId int64  `json:"allocationPlanId"`
+	Name             string `json:"name"`
+	PlanType         string `json:"planType"`
+	Created          bool   `json:"created"`
+	DetailCount      int    `json:"detailCount"`
+}
+
 type AllocationPlanManagementAppService struct {
 	transactionManager       rdbms.TransactionManager
 	allocationPlanDomService *service.AllocationPlanDomService
 	assetDomService          *service.AssetDomService
 	portfolioDomService      *service.PortfolioDomService
+	webhookDomService        *service.WebhookDomService
+	auditDomService          *service.AuditDomService
 }
 
-func (service *AllocationPlanManagementAppService) PersistAllocationPlan(plan *domain.AllocationPlan) error {
+// PersistAllocationPlan inserts an allocation plan without id, or updates an existing one, in a transaction
+// audited as the actor of the request context.
+func (service *AllocationPlanManagementAppService) PersistAllocationPlan(
+	requestContext context.Context,
+	plan *domain.AllocationPlan,
+) error {
 
-	var err = service.transactionManager.RunInTransaction(
+	var err = service.transactionManager.RunInTransactionWithContext(
+		requestContext,
 		func(transContext *rdbms.SQLTransactionalContext) error {
 
-			var err = service.persistNewAssets(transContext, plan.Details)
+			var auditReference = &domain.AuditEntityReference{
+				EntityType:  domain.AllocationPlanAuditEntityType,
+				EntityId:    plan.Id,
+				PortfolioId: &plan.PortfolioId,
+			}
+
+			before, err := snapshotPersistedEntityInTransaction(service.auditDomService, transContext, auditReference)
 			if err != nil {
 				return err
 			}
 
-			portfolio, err := service.portfolioDomService.GetPortfolio(plan.PortfolioId)
+			err = service.persistNewAssets(transContext, plan.Details)
 			if err != nil {
 				return err
 			}
 
-			return service.allocationPlanDomService.PersistAllocationPlanInTransaction(
+			portfolio, err := service.portfolioDomService.GetPortfolio(transContext, plan.PortfolioId)
+			if err != nil {
+				return err
+			}
+
+			var created = langext.IsZeroValue(plan.Id)
+			err = service.allocationPlanDomService.PersistAllocationPlanInTransaction(
 				transContext,
 				plan,
 				&portfolio.AllocationStructure,
 			)
+			if err != nil {
+				return err
+			}
+
+			auditReference.EntityId = plan.Id
+			err = service.auditDomService.RecordChangeInTransaction(transContext, auditReference, before)
+			if err != nil {
+				return err
+			}
+
+			return service.webhookDomService.EmitEventInTransaction(
+				transContext,
+				plan.PortfolioId,
+				domain.AllocationPlanPersistedWebhookEventType,
+				allocationPlanPersistedEventPayload{
+					AllocationPlanId: plan.Id,
+					Name:             plan.Name,
+					PlanType:         plan.PlanType.String(),
+					Created:          created,
+					DetailCount:      len(plan.Details),
+				},
+			)
 		},
 	)
 
-	// if error is DomainValidationError, sent it as is, otherwise propagate
-	var validationErr *infra.DomainValidationError
-	if errors.As(err, &validationErr) {
-		return err
-	}
-
-	return infra.PropagateAsAppErrorWithNewMessage(err, "Failed to persist allocation plan", service)
+	return propagateManagementError(err, "Failed to persist allocation plan", service)
 }
 
 func (service *AllocationPlanManagementAppService) persistNewAssets(
@@ -61,14 +104,21 @@ func (service *AllocationPlanManagementAppService) persistNewAssets(
 		return nil
 	}
 
-	persistedAssetsPerTicker, err := service.assetDomService.InsertMappedAssetsInTransaction(
+	// plans are not bound to a date, so tickers resolve through current tickers first and any alias next
+	persistedAssetsPerTicker, insertedAssetsPerTicker, err := service.assetDomService.PersistMappedAssetsInTransaction(
 		transContext,
 		newAssetsPerTicker,
+		nil,
 	)
 	if err != nil {
 		return err
 	}
 
+	err = recordCreatedAssetsInTransaction(service.auditDomService, transContext, insertedAssetsPerTicker)
+	if err != nil {
+		return err
+	}
+
 	replacePersistedAssetsOnPlannedAllocations(allocations, persistedAssetsPerTicker)
 
 	return nil
@@ -102,11 +152,15 @@ func BuildAllocationPlanManagementAppService(
 	allocationPlanDomService *service.AllocationPlanDomService,
 	assetDomService *service.AssetDomService,
 	portfolioDomService *service.PortfolioDomService,
+	webhookDomService *service.WebhookDomService,
+	auditDomService *service.AuditDomService,
 ) *AllocationPlanManagementAppService {
 	return &AllocationPlanManagementAppService{
 		transactionManager:       transactionManager,
 		allocationPlanDomService: allocationPlanDomService,
 		assetDomService:          assetDomService,
 		portfolioDomService:      portfolioDomService,
+		webhookDomService:        webhookDomService,
+		auditDomService:          auditDomService,
 	}
 }
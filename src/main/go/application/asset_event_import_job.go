@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+type assetEventImportJobParams struct {
+	AssetIds []int64 `json:"assetIds"`
+}
+
+type assetEventImportJobFailure struct {
+	AssetId      int64  `json:"assetId"`
+	Ticker       string `json:"ticker"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+type assetEventImportJobResult struct {
+	ImportedAssets int                          `json:"importedAssets"`
+	SkippedAssets  int                          `json:"skippedAssets"`
+	Failures       []assetEventImportJobFailure `json:"failures"`
+}
+
+// AssetEventImportJobHandler runs the ASSET_EVENT_IMPORT jobs, importing the dividend and split events
+// of the assets in the parameters, or of every known asset when none is given. Assets without a linked
+// external asset providing events are skipped, and the failure of an asset does not stop the job.
+//
+// Example params:
+//
+//	{"assetIds": [1, 2]}
+type AssetEventImportJobHandler struct {
+	assetDomService *service.AssetDomService
+}
+
+func (handler *AssetEventImportJobHandler) ValidateParams(params json.RawMessage) error {
+
+	var jobParams, err = parseAssetEventImportJobParams(params)
+	if err != nil {
+		return err
+	}
+
+	for _, assetId := range jobParams.AssetIds {
+		if assetId <= 0 {
+			return infra.BuildDomainValidationError(fmt.Sprintf("Invalid asset id %d", assetId), nil)
+		}
+	}
+
+	return nil
+}
+
+func (handler *AssetEventImportJobHandler) Run(
+	ctx context.Context,
+	job *domain.Job,
+	progress domain.JobProgressReporter,
+) (any, error) {
+
+	var jobParams, err = parseAssetEventImportJobParams(job.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := handler.getAssetsToImport(jobParams.AssetIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = assetEventImportJobResult{Failures: make([]assetEventImportJobFailure, 0)}
+	progress.ReportProgress(0, len(assets))
+
+	for index, asset := range assets {
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		_, err = handler.assetDomService.ImportAssetEvents(ctx, asset)
+
+		var validationErr *infra.DomainValidationError
+		switch {
+		case err == nil:
+			result.ImportedAssets++
+		case errors.As(err, &validationErr):
+			result.SkippedAssets++
+		default:
+			result.Failures = append(
+				result.Failures,
+				assetEventImportJobFailure{AssetId: asset.Id, Ticker: asset.Ticker, ErrorMessage: err.Error()},
+			)
+		}
+
+		progress.ReportProgress(index+1, len(assets))
+	}
+
+	return result, nil
+}
+
+func (handler *AssetEventImportJobHandler) getAssetsToImport(assetIds []int64) ([]*domain.Asset, error) {
+
+	if len(assetIds) == 0 {
+		return handler.assetDomService.GetKnownAssets()
+	}
+
+	var assets = make([]*domain.Asset, 0, len(assetIds))
+	for _, assetId := range assetIds {
+
+		var asset, err = handler.assetDomService.FindAssetById(assetId)
+		if err != nil {
+			return nil, err
+		}
+
+		if asset == nil {
+			return nil, fmt.Errorf("asset %d not found", assetId)
+		}
+
+		assets = append(assets, asset)
+	}
+
+	return assets, nil
+}
+
+func parseAssetEventImportJobParams(params json.RawMessage) (*assetEventImportJobParams, error) {
+
+	var jobParams assetEventImportJobParams
+	if len(params) == 0 {
+		return &jobParams, nil
+	}
+
+	if err := json.Unmarshal(params, &jobParams); err != nil {
+		return nil, infra.BuildDomainValidationError("Invalid asset event import job params: "+err.Error(), nil)
+	}
+
+	return &jobParams, nil
+}
+
+func BuildAssetEventImportJobHandler(assetDomService *service.AssetDomService) *AssetEventImportJobHandler {
+	return &AssetEventImportJobHandler{assetDomService: assetDomService}
+}
@@ -0,0 +1,306 @@
+package application
+
+import (
+	"context"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+)
+
+type AssetManagementAppService struct {
+	transactionManager rdbms.TransactionManager
+	assetDomService    *service.AssetDomService
+	auditDomService    *service.AuditDomService
+}
+
+// UpdateAsset updates an asset in a transaction audited as the actor of the request context, returning nil
+// when the asset does not exist.
+func (service *AssetManagementAppService) UpdateAsset(
+	requestContext context.Context,
+	asset *domain.Asset,
+) (*domain.Asset, error) {
+
+	var updatedAsset *domain.Asset
+	var err = service.runAuditedAssetChange(
+		requestContext,
+		asset.Id,
+		func(transContext context.Context) error {
+			var err error
+			updatedAsset, err = service.assetDomService.UpdateAssetInTransaction(transContext, asset)
+			return err
+		},
+	)
+
+	if err != nil {
+		return nil, propagateManagementError(err, "Failed to update asset", service)
+	}
+
+	return updatedAsset, nil
+}
+
+// InsertAssetAlias persists a new ticker alias of an asset in a transaction audited as the actor of the
+// request context.
+func (service *AssetManagementAppService) InsertAssetAlias(
+	requestContext context.Context,
+	alias *domain.AssetAlias,
+) (*domain.AssetAlias, error) {
+
+	var persistedAlias *domain.AssetAlias
+	var err = service.runAuditedAssetChange(
+		requestContext,
+		alias.AssetId,
+		func(transContext context.Context) error {
+			var err error
+			persistedAlias, err = service.assetDomService.InsertAssetAliasInTransaction(transContext, alias)
+			return err
+		},
+	)
+
+	if err != nil {
+		return nil, propagateManagementError(err, "Failed to insert asset alias", service)
+	}
+
+	return persistedAlias, nil
+}
+
+// DeleteAssetAlias removes a ticker alias of an asset in a transaction audited as the actor of the request
+// context, returning false when the alias does not exist for the asset.
+func (service *AssetManagementAppService) DeleteAssetAlias(
+	requestContext context.Context,
+	assetId int64,
+	aliasId int64,
+) (bool, error) {
+
+	var deleted bool
+	var err = service.runAuditedAssetChange(
+		requestContext,
+		assetId,
+		func(transContext context.Context) error {
+			var err error
+			deleted, err = service.assetDomService.DeleteAssetAliasInTransaction(transContext, assetId, aliasId)
+			return err
+		},
+	)
+
+	if err != nil {
+		return false, propagateManagementError(err, "Failed to delete asset alias", service)
+	}
+
+	return deleted, nil
+}
+
+// RecordAssetValuation persists a manual valuation of an asset and links the MANUAL external asset to it,
+// when not linked yet, in a single transaction audited as the actor of the request context. Returns nil when
+// the asset does not exist.
+func (service *AssetManagementAppService) RecordAssetValuation(
+	requestContext context.Context,
+	asset *domain.Asset,
+	valuation *domain.AssetValuation,
+) (*domain.AssetValuation, error) {
+
+	var persistedValuation *domain.AssetValuation
+	_, err := service.runAuditedLockedAssetChange(
+		requestContext,
+		asset.Id,
+		0,
+		func(transContext context.Context, lockedAsset *domain.Asset) (*domain.Asset, error) {
+
+			var err error
+			persistedValuation, err = service.assetDomService.RecordAssetValuationInTransaction(
+				transContext,
+				valuation,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return service.assetDomService.LinkManualExternalAssetInTransaction(transContext, lockedAsset)
+		},
+	)
+
+	if err != nil {
+		return nil, propagateManagementError(err, "Failed to record asset valuation", service)
+	}
+
+	return persistedValuation, nil
+}
+
+// DeleteAssetValuation removes a manual valuation of an asset in a transaction audited as the actor of the
+// request context, returning false when the valuation does not exist for the asset. The MANUAL external
+// asset stays linked.
+func (service *AssetManagementAppService) DeleteAssetValuation(
+	requestContext context.Context,
+	assetId int64,
+	valuationId int64,
+) (bool, error) {
+
+	var deleted bool
+	var err = service.runAuditedAssetChange(
+		requestContext,
+		assetId,
+		func(transContext context.Context) error {
+			var err error
+			deleted, err = service.assetDomService.DeleteAssetValuationInTransaction(
+				transContext,
+				assetId,
+				valuationId,
+			)
+			return err
+		},
+	)
+
+	if err != nil {
+		return false, propagateManagementError(err, "Failed to delete asset valuation", service)
+	}
+
+	return deleted, nil
+}
+
+// LinkExternalAsset links an external asset to an asset, as its lowest priority external source, in a
+// transaction audited as the actor of the request context. The asset version, when not zero, must match the
+// persisted one. Returns nil when the asset does not exist.
+func (service *AssetManagementAppService) LinkExternalAsset(
+	requestContext context.Context,
+	asset *domain.Asset,
+	externalAsset domain.ExternalAsset,
+) (*domain.Asset, error) {
+
+	updatedAsset, err := service.runAuditedLockedAssetChange(
+		requestContext,
+		asset.Id,
+		asset.Version,
+		func(transContext context.Context, lockedAsset *domain.Asset) (*domain.Asset, error) {
+			return service.assetDomService.LinkExternalAssetInTransaction(transContext, lockedAsset, externalAsset)
+		},
+	)
+
+	if err != nil {
+		return nil, propagateManagementError(err, "Failed to link external asset", service)
+	}
+
+	return updatedAsset, nil
+}
+
+// UnlinkExternalAsset removes a linked external asset from an asset in a transaction audited as the actor
+// of the request context. The asset version, when not zero, must match the persisted one. Returns nil when
+// the asset does not exist or the external asset was not linked.
+func (service *AssetManagementAppService) UnlinkExternalAsset(
+	requestContext context.Context,
+	asset *domain.Asset,
+	reference *domain.ExternalAsset,
+) (*domain.Asset, error) {
+
+	updatedAsset, err := service.runAuditedLockedAssetChange(
+		requestContext,
+		asset.Id,
+		asset.Version,
+		func(transContext context.Context, lockedAsset *domain.Asset) (*domain.Asset, error) {
+			return service.assetDomService.UnlinkExternalAssetInTransaction(transContext, lockedAsset, reference)
+		},
+	)
+
+	if err != nil {
+		return nil, propagateManagementError(err, "Failed to unlink external asset", service)
+	}
+
+	return updatedAsset, nil
+}
+
+// PrioritizeExternalAssets reorders the external assets linked to an asset in a transaction audited as the
+// actor of the request context. The asset version, when not zero, must match the persisted one. Returns nil
+// when the asset does not exist.
+func (service *AssetManagementAppService) PrioritizeExternalAssets(
+	requestContext context.Context,
+	asset *domain.Asset,
+	references []domain.ExternalAsset,
+) (*domain.Asset, error) {
+
+	updatedAsset, err := service.runAuditedLockedAssetChange(
+		requestContext,
+		asset.Id,
+		asset.Version,
+		func(transContext context.Context, lockedAsset *domain.Asset) (*domain.Asset, error) {
+			return service.assetDomService.PrioritizeExternalAssetsInTransaction(transContext, lockedAsset, references)
+		},
+	)
+
+	if err != nil {
+		return nil, propagateManagementError(err, "Failed to prioritize external assets", service)
+	}
+
+	return updatedAsset, nil
+}
+
+// runAuditedAssetChange runs a change of an asset, or of its aliases, in a transaction audited as the actor
+// of the request context.
+func (service *AssetManagementAppService) runAuditedAssetChange(
+	requestContext context.Context,
+	assetId int64,
+	change func(transContext context.Context) error,
+) error {
+	return service.transactionManager.RunInTransactionWithContext(
+		requestContext,
+		func(transContext *rdbms.SQLTransactionalContext) error {
+			return auditChangesInTransaction(
+				service.auditDomService,
+				transContext,
+				[]*domain.AuditEntityReference{buildAssetAuditReference(assetId)},
+				func() error { return change(transContext) },
+			)
+		},
+	)
+}
+
+// runAuditedLockedAssetChange runs a change of an asset in a transaction audited as the actor of the request
+// context, applying it to the asset as persisted, locked until the transaction ends so concurrent changes
+// are not lost. The expected version, when not zero, must match the persisted one. Returns the changed
+// asset, or nil when the asset does not exist.
+func (service *AssetManagementAppService) runAuditedLockedAssetChange(
+	requestContext context.Context,
+	assetId int64,
+	expectedVersion int64,
+	change func(transContext context.Context, lockedAsset *domain.Asset) (*domain.Asset, error),
+) (*domain.Asset, error) {
+
+	var changedAsset *domain.Asset
+	var err = service.transactionManager.RunInTransactionWithContext(
+		requestContext,
+		func(transContext *rdbms.SQLTransactionalContext) error {
+
+			lockedAsset, err := service.assetDomService.FindAssetForUpdateInTransaction(
+				transContext,
+				assetId,
+				expectedVersion,
+			)
+			if err != nil || lockedAsset == nil {
+				return err
+			}
+
+			return auditChangesInTransaction(
+				service.auditDomService,
+				transContext,
+				[]*domain.AuditEntityReference{buildAssetAuditReference(assetId)},
+				func() error {
+					var err error
+					changedAsset, err = change(transContext, lockedAsset)
+					return err
+				},
+			)
+		},
+	)
+
+	return changedAsset, err
+}
+
+func BuildAssetManagementAppService(
+	transactionManager rdbms.TransactionManager,
+	assetDomService *service.AssetDomService,
+	auditDomService *service.AuditDomService,
+) *AssetManagementAppService {
+	return &AssetManagementAppService{
+		transactionManager: transactionManager,
+		assetDomService:    assetDomService,
+		auditDomService:    auditDomService,
+	}
+}
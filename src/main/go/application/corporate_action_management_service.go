@@ -0,0 +1,119 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+)
+
+type CorporateActionManagementAppService struct {
+	transactionManager        rdbms.TransactionManager
+	corporateActionDomService *service.CorporateActionDomService
+	auditDomService           *service.AuditDomService
+}
+
+// ApplyCorporateAction applies a corporate action in a single transaction, so the portfolio
+// allocations, planned allocations, ticker and aliases it changes are adjusted together or not at all.
+// The changes are audited as the actor of the request context.
+func (service *CorporateActionManagementAppService) ApplyCorporateAction(
+	requestContext context.Context,
+	corporateActionId int64,
+) (*domain.CorporateAction, error) {
+
+	var appliedAction *domain.CorporateAction
+	var err = service.runAuditedCorporateActionChange(
+		requestContext,
+		corporateActionId,
+		func(transContext context.Context) error {
+			var err error
+			appliedAction, err = service.corporateActionDomService.ApplyCorporateActionInTransaction(
+				transContext,
+				corporateActionId,
+			)
+			return err
+		},
+	)
+
+	return appliedAction, propagateCorporateActionError(err, "Failed to apply corporate action", service)
+}
+
+// RevertCorporateAction reverts an applied corporate action in a single transaction, restoring the state
+// recorded when it was applied. The changes are audited as the actor of the request context.
+func (service *CorporateActionManagementAppService) RevertCorporateAction(
+	requestContext context.Context,
+	corporateActionId int64,
+) (*domain.CorporateAction, error) {
+
+	var revertedAction *domain.CorporateAction
+	var err = service.runAuditedCorporateActionChange(
+		requestContext,
+		corporateActionId,
+		func(transContext context.Context) error {
+			var err error
+			revertedAction, err = service.corporateActionDomService.RevertCorporateActionInTransaction(
+				transContext,
+				corporateActionId,
+			)
+			return err
+		},
+	)
+
+	return revertedAction, propagateCorporateActionError(err, "Failed to revert corporate action", service)
+}
+
+// runAuditedCorporateActionChange runs the application or reversal of a corporate action in a transaction,
+// recording in the audit trail the change of every asset, portfolio allocation and allocation plan it
+// affects.
+func (service *CorporateActionManagementAppService) runAuditedCorporateActionChange(
+	requestContext context.Context,
+	corporateActionId int64,
+	change func(transContext context.Context) error,
+) error {
+	return service.transactionManager.RunInTransactionWithContext(
+		requestContext,
+		func(transContext *rdbms.SQLTransactionalContext) error {
+
+			references, err := service.corporateActionDomService.FindChangedEntityReferencesInTransaction(
+				transContext,
+				corporateActionId,
+			)
+			if err != nil {
+				return err
+			}
+
+			return auditChangesInTransaction(
+				service.auditDomService,
+				transContext,
+				references,
+				func() error { return change(transContext) },
+			)
+		},
+	)
+}
+
+func propagateCorporateActionError(err error, message string, origin any) error {
+
+	// if error is DomainValidationError, sent it as is, otherwise propagate
+	var validationErr *infra.DomainValidationError
+	if errors.As(err, &validationErr) {
+		return err
+	}
+
+	return infra.PropagateAsAppErrorWithNewMessage(err, message, origin)
+}
+
+func BuildCorporateActionManagementAppService(
+	transactionManager rdbms.TransactionManager,
+	corporateActionDomService *service.CorporateActionDomService,
+	auditDomService *service.AuditDomService,
+) *CorporateActionManagementAppService {
+	return &CorporateActionManagementAppService{
+		transactionManager:        transactionManager,
+		corporateActionDomService: corporateActionDomService,
+		auditDomService:           auditDomService,
+	}
+}
@@ -0,0 +1,174 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+)
+
+// divergenceAlertTriggeredEventPayload is the payload of the DIVERGENCE_ALERT_TRIGGERED webhook event.
+type divergenceAlertTriggeredEventPayload struct {
+	AlertId                int64                          `json:"alertId"`
+	RuleId                 int64                          `json:"ruleId"`
+	RuleName               string                         `json:"ruleName"`
+	RuleType               domain.DivergenceAlertRuleType `json:"ruleType"`
+	AllocationPlanId       int64                          `json:"allocationPlanId"`
+	HierarchicalId         *string                        `json:"hierarchicalId,omitempty"`
+	ThresholdPercent       decimal.Decimal                `json:"thresholdPercent"`
+	ObservedPercent        decimal.Decimal                `json:"observedPercent"`
+	ObservationTimestampId int64                          `json:"observationTimestampId"`
+	ObservationTimeTag     string                         `json:"observationTimeTag"`
+}
+
+// DivergenceAlertEvaluationAppService evaluates the alert rules of a portfolio on the divergence analysis of
+// its latest observation whenever an observation is merged, or of the revalued observation when prices are
+// refreshed.
+type DivergenceAlertEvaluationAppService struct {
+	transactionManager                    rdbms.TransactionManager
+	divergenceAlertDomService             *service.DivergenceAlertDomService
+	portfolioAllocationDomService         *service.PortfolioAllocationDomService
+	portfolioDivergenceAnalysisAppService *PortfolioDivergenceAnalysisAppService
+	webhookDomService                     *service.WebhookDomService
+}
+
+// EvaluatePortfolioAlertRules evaluates the enabled alert rules of a portfolio, generating one divergence
+// analysis per allocation plan referenced by the rules. The alerts of all the rules are updated in a single
+// transaction, emitting a DIVERGENCE_ALERT_TRIGGERED webhook event for each triggered alert. Portfolios
+// without observations are not evaluated. The evaluation is logged within the given context, like the context
+// of the request merging the observation.
+//
+// Returns:
+//   - int: the number of alerts triggered
+//   - error: when an analysis cannot be generated or the alerts cannot be persisted
+func (service *DivergenceAlertEvaluationAppService) EvaluatePortfolioAlertRules(
+	evaluationContext context.Context,
+	portfolioId int64,
+) (int, error) {
+
+	var observationTimestamps, err = service.portfolioAllocationDomService.GetAvailableObservationTimestamps(
+		portfolioId,
+		1,
+	)
+	if err != nil || len(observationTimestamps) == 0 {
+		return 0, err
+	}
+
+	return service.EvaluateObservationAlertRules(evaluationContext, portfolioId, observationTimestamps[0])
+}
+
+// EvaluateObservationAlertRules evaluates the enabled alert rules of a portfolio like EvaluatePortfolioAlertRules,
+// on the divergence analysis of the given observation instead of the latest one, like the observation recorded
+// by a revaluation.
+//
+// Returns:
+//   - int: the number of alerts triggered
+//   - error: when an analysis cannot be generated or the alerts cannot be persisted
+func (service *DivergenceAlertEvaluationAppService) EvaluateObservationAlertRules(
+	evaluationContext context.Context,
+	portfolioId int64,
+	observation *domain.PortfolioObservationTimestamp,
+) (int, error) {
+
+	var rules, err = service.divergenceAlertDomService.GetEnabledPortfolioAlertRules(portfolioId)
+	if err != nil || len(rules) == 0 {
+		return 0, err
+	}
+
+	var analysesPerAllocationPlanId = make(map[int64]*domain.DivergenceAnalysis)
+	for _, rule := range rules {
+
+		if _, ok := analysesPerAllocationPlanId[rule.AllocationPlanId]; ok {
+			continue
+		}
+
+		analysis, err := service.portfolioDivergenceAnalysisAppService.GeneratePortfolioDivergenceAnalysis(
+			evaluationContext,
+			portfolioId,
+			observation.Id,
+			rule.AllocationPlanId,
+		)
+		if err != nil {
+			return 0, err
+		}
+		// the analysis only references the observation through its allocations, which may be none
+		analysis.ObservationTimestamp = observation
+		analysesPerAllocationPlanId[rule.AllocationPlanId] = analysis
+	}
+
+	var triggeredCount = 0
+	var evaluatedAt = time.Now()
+	err = service.transactionManager.RunInTransactionWithContext(
+		evaluationContext,
+		func(transContext *rdbms.SQLTransactionalContext) error {
+
+			triggeredCount = 0
+			for _, rule := range rules {
+
+				var analysis = analysesPerAllocationPlanId[rule.AllocationPlanId]
+				triggeredAlerts, err := service.divergenceAlertDomService.EvaluateAlertRuleInTransaction(
+					transContext,
+					rule,
+					analysis,
+					evaluatedAt,
+				)
+				if err != nil {
+					return err
+				}
+
+				for _, triggeredAlert := range triggeredAlerts {
+					err = service.webhookDomService.EmitEventInTransaction(
+						transContext,
+						portfolioId,
+						domain.DivergenceAlertTriggeredWebhookEventType,
+						divergenceAlertTriggeredEventPayload{
+							AlertId:                triggeredAlert.Id,
+							RuleId:                 rule.Id,
+							RuleName:               rule.Name,
+							RuleType:               rule.RuleType,
+							AllocationPlanId:       rule.AllocationPlanId,
+							HierarchicalId:         triggeredAlert.HierarchicalId,
+							ThresholdPercent:       triggeredAlert.ThresholdPercent,
+							ObservedPercent:        triggeredAlert.ObservedPercent,
+							ObservationTimestampId: observation.Id,
+							ObservationTimeTag:     observation.TimeTag,
+						},
+					)
+					if err != nil {
+						return err
+					}
+				}
+
+				triggeredCount += len(triggeredAlerts)
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return 0, infra.PropagateAsAppErrorWithNewMessage(err, "Failed to evaluate divergence alert rules", service)
+	}
+
+	return triggeredCount, nil
+}
+
+func BuildDivergenceAlertEvaluationAppService(
+	transactionManager rdbms.TransactionManager,
+	divergenceAlertDomService *service.DivergenceAlertDomService,
+	portfolioAllocationDomService *service.PortfolioAllocationDomService,
+	portfolioDivergenceAnalysisAppService *PortfolioDivergenceAnalysisAppService,
+	webhookDomService *service.WebhookDomService,
+) *DivergenceAlertEvaluationAppService {
+	return &DivergenceAlertEvaluationAppService{
+		transactionManager:                    transactionManager,
+		divergenceAlertDomService:             divergenceAlertDomService,
+		portfolioAllocationDomService:         portfolioAllocationDomService,
+		portfolioDivergenceAnalysisAppService: portfolioDivergenceAnalysisAppService,
+		webhookDomService:                     webhookDomService,
+	}
+}
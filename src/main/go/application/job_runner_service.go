@@ -0,0 +1,289 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// JobRunnerAppService runs the persisted jobs in the background, each in its own goroutine, with at most
+// the configured number of jobs running at the same time. Jobs are cancelled through the context given
+// to their handlers, either on request or when the runner stops. Jobs interrupted by a stop are left
+// running in the persistence and resumed by RecoverInterruptedJobs on the next start.
+type JobRunnerAppService struct {
+	jobDomService  *service.JobDomService
+	workerSlots    chan struct{}
+	runContext     context.Context
+	stopRunner     context.CancelFunc
+	runningJobs    sync.WaitGroup
+	mutex          sync.Mutex
+	cancelPerJobId map[int64]context.CancelCauseFunc
+}
+
+// SubmitJob persists a new job of the type, created by the user, and schedules it to run. The user id is zero
+// when the job is submitted without authentication.
+//
+// Returns:
+//   - *domain.Job: the submitted job, pending
+//   - error: a DomainValidationError when the job type is unknown or the parameters are not valid
+func (service *JobRunnerAppService) SubmitJob(
+	jobType domain.JobType,
+	params json.RawMessage,
+	createdByUserId int64,
+) (*domain.Job, error) {
+
+	var job, err = service.jobDomService.InsertJob(jobType, params, createdByUserId)
+	if err != nil {
+		return nil, service.propagateJobError(err, "Failed to submit job")
+	}
+
+	service.enqueueJob(job)
+	return job, nil
+}
+
+// CancelJob requests the cancellation of a job that is not finished. A running job is cancelled through
+// its context, so it is recorded as cancelled when its handler returns.
+//
+// Returns:
+//   - *domain.Job: the job as persisted when the cancellation was requested, or nil when it does not exist
+//   - error: a DomainValidationError when the job is already finished
+func (service *JobRunnerAppService) CancelJob(id int64) (*domain.Job, error) {
+
+	var job, err = service.jobDomService.GetJob(id)
+	if err != nil || job == nil {
+		return nil, service.propagateJobError(err, "Failed to cancel job")
+	}
+
+	if job.Status.IsFinished() {
+		return nil, infra.BuildDomainValidationError(fmt.Sprintf("Job %d is already finished", id), nil)
+	}
+
+	service.mutex.Lock()
+	var cancel, scheduled = service.cancelPerJobId[id]
+	service.mutex.Unlock()
+
+	if scheduled {
+		cancel(domain.ErrJobCancelled)
+		return job, nil
+	}
+
+	// the job is not scheduled in this process, it can only be cancelled in the persistence
+	job, err = service.jobDomService.CancelJob(job)
+	return job, service.propagateJobError(err, "Failed to cancel job")
+}
+
+// RecoverInterruptedJobs schedules again the jobs left pending or running by a previous execution of the
+// application, oldest first.
+func (service *JobRunnerAppService) RecoverInterruptedJobs() error {
+
+	var jobs, err = service.jobDomService.GetJobsToResume()
+	if err != nil {
+		return infra.PropagateAsAppErrorWithNewMessage(err, "Failed to recover interrupted jobs", service)
+	}
+
+	for _, job := range jobs {
+		service.enqueueJob(job)
+	}
+
+	if len(jobs) > 0 {
+		slog.Info("Recovered interrupted jobs", "count", len(jobs))
+	}
+
+	return nil
+}
+
+// Stop cancels the running jobs, leaving them to be resumed on the next start, and waits for their
+// handlers to return until the stop context is done.
+func (service *JobRunnerAppService) Stop(stopContext context.Context) {
+
+	service.stopRunner()
+
+	var stopped = make(chan struct{})
+	go func() {
+		service.runningJobs.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		slog.Info("Job runner stopped")
+	case <-stopContext.Done():
+		slog.Error("Job runner stop timed out with jobs still running")
+	}
+}
+
+func (service *JobRunnerAppService) enqueueJob(job *domain.Job) {
+
+	var jobContext, cancel = context.WithCancelCause(service.runContext)
+
+	service.mutex.Lock()
+	service.cancelPerJobId[job.Id] = cancel
+	service.mutex.Unlock()
+
+	service.runningJobs.Add(1)
+	go service.runJob(jobContext, job)
+}
+
+func (service *JobRunnerAppService) runJob(jobContext context.Context, job *domain.Job) {
+
+	defer service.runningJobs.Done()
+	defer service.releaseJob(job.Id)
+
+	select {
+	case service.workerSlots <- struct{}{}:
+		defer func() { <-service.workerSlots }()
+	case <-jobContext.Done():
+		service.finishUnstartedJob(jobContext, job)
+		return
+	}
+
+	// checked again, as the slot may be acquired after the cancellation when both are ready
+	if jobContext.Err() != nil {
+		service.finishUnstartedJob(jobContext, job)
+		return
+	}
+
+	startedJob, err := service.jobDomService.StartJob(job.Id)
+	if err != nil {
+		slog.ErrorContext(jobContext, "Error starting job", "jobId", job.Id, "error", err)
+		return
+	}
+
+	if startedJob == nil {
+		slog.InfoContext(jobContext, "Job is not pending anymore, skipping it", "jobId", job.Id)
+		return
+	}
+
+	var handler, exists = service.jobDomService.GetJobHandler(startedJob.JobType)
+	if !exists {
+		var noHandlerErr = fmt.Errorf("no handler for job type %s", startedJob.JobType)
+		service.finishStartedJob(jobContext, startedJob, nil, noHandlerErr)
+		return
+	}
+
+	var progressReporter = &jobProgressReporter{jobId: startedJob.Id, jobDomService: service.jobDomService}
+
+	slog.InfoContext(jobContext, "Running job", "jobId", startedJob.Id, "jobType", startedJob.JobType)
+	result, runErr := runJobHandler(jobContext, handler, startedJob, progressReporter)
+
+	service.finishStartedJob(jobContext, startedJob, result, runErr)
+}
+
+func (service *JobRunnerAppService) finishUnstartedJob(jobContext context.Context, job *domain.Job) {
+
+	if !errors.Is(context.Cause(jobContext), domain.ErrJobCancelled) {
+		// stopping, the job stays pending to be resumed
+		return
+	}
+
+	if _, err := service.jobDomService.CancelJob(job); err != nil {
+		slog.ErrorContext(jobContext, "Error cancelling job", "jobId", job.Id, "error", err)
+	}
+}
+
+func (service *JobRunnerAppService) finishStartedJob(
+	jobContext context.Context,
+	job *domain.Job,
+	result any,
+	runErr error,
+) {
+
+	var err error
+	switch {
+	case errors.Is(context.Cause(jobContext), domain.ErrJobCancelled):
+		slog.InfoContext(jobContext, "Job cancelled", "jobId", job.Id)
+		_, err = service.jobDomService.CancelJob(job)
+	case service.runContext.Err() != nil:
+		slog.InfoContext(
+			jobContext,
+			"Job interrupted by the runner stop, it will be resumed on the next start",
+			"jobId", job.Id,
+		)
+	case runErr != nil:
+		slog.ErrorContext(jobContext, "Job failed", "jobId", job.Id, "error", runErr)
+		_, err = service.jobDomService.FailJob(job, runErr)
+	default:
+		slog.InfoContext(jobContext, "Job succeeded", "jobId", job.Id)
+		_, err = service.jobDomService.SucceedJob(job, result)
+	}
+
+	if err != nil {
+		slog.ErrorContext(jobContext, "Error recording the outcome of job", "jobId", job.Id, "error", err)
+	}
+}
+
+func (service *JobRunnerAppService) releaseJob(id int64) {
+
+	service.mutex.Lock()
+	var cancel = service.cancelPerJobId[id]
+	delete(service.cancelPerJobId, id)
+	service.mutex.Unlock()
+
+	if cancel != nil {
+		cancel(nil)
+	}
+}
+
+// runJobHandler runs the handler, converting a panic into the error of the job so it does not stop the
+// application.
+func runJobHandler(
+	jobContext context.Context,
+	handler domain.JobHandler,
+	job *domain.Job,
+	progressReporter domain.JobProgressReporter,
+) (result any, err error) {
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+
+	return handler.Run(jobContext, job, progressReporter)
+}
+
+func (service *JobRunnerAppService) propagateJobError(err error, message string) error {
+
+	// if error is DomainValidationError, sent it as is, otherwise propagate
+	var validationErr *infra.DomainValidationError
+	if errors.As(err, &validationErr) {
+		return err
+	}
+
+	return infra.PropagateAsAppErrorWithNewMessage(err, message, service)
+}
+
+type jobProgressReporter struct {
+	jobId         int64
+	jobDomService *service.JobDomService
+}
+
+func (reporter *jobProgressReporter) ReportProgress(completed int, total int) {
+	if err := reporter.jobDomService.UpdateJobProgress(reporter.jobId, completed, total); err != nil {
+		slog.Error("Error updating the progress of job", "jobId", reporter.jobId, "error", err)
+	}
+}
+
+func BuildJobRunnerAppService(
+	jobDomService *service.JobDomService,
+	jobConfig infra.JobConfiguration,
+) *JobRunnerAppService {
+
+	var workerCount = max(jobConfig.WorkerCount, 1)
+	var runContext, stopRunner = context.WithCancel(context.Background())
+
+	return &JobRunnerAppService{
+		jobDomService:  jobDomService,
+		workerSlots:    make(chan struct{}, workerCount),
+		runContext:     runContext,
+		stopRunner:     stopRunner,
+		cancelPerJobId: make(map[int64]context.CancelCauseFunc),
+	}
+}
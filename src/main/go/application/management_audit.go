@@ -0,0 +1,90 @@
+package application
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+// snapshotPersistedEntityInTransaction takes the audit snapshot of an entity before its change, or none when
+// the entity has no id yet and is about to be created.
+func snapshotPersistedEntityInTransaction(
+	auditDomService *service.AuditDomService,
+	transContext context.Context,
+	reference *domain.AuditEntityReference,
+) (json.RawMessage, error) {
+	if langext.IsZeroValue(reference.EntityId) {
+		return nil, nil
+	}
+	return auditDomService.SnapshotInTransaction(transContext, reference)
+}
+
+// auditChangesInTransaction runs a change of already persisted entities, recording in the audit trail the
+// change of each referenced entity, as the actor carried by the transactional context.
+func auditChangesInTransaction(
+	auditDomService *service.AuditDomService,
+	transContext context.Context,
+	references []*domain.AuditEntityReference,
+	change func() error,
+) error {
+
+	var befores = make([]json.RawMessage, len(references))
+	for index, reference := range references {
+		before, err := auditDomService.SnapshotInTransaction(transContext, reference)
+		if err != nil {
+			return err
+		}
+		befores[index] = before
+	}
+
+	var err = change()
+	if err != nil {
+		return err
+	}
+
+	for index, reference := range references {
+		err = auditDomService.RecordChangeInTransaction(transContext, reference, befores[index])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordCreatedAssetsInTransaction records in the audit trail the creation of the assets inserted while
+// persisting the data that references them, such as portfolio allocations and allocation plans.
+func recordCreatedAssetsInTransaction(
+	auditDomService *service.AuditDomService,
+	transContext context.Context,
+	insertedAssetsPerTicker domain.AssetsPerTicker,
+) error {
+
+	var insertedAssets = slices.Collect(maps.Values(insertedAssetsPerTicker))
+	slices.SortFunc(insertedAssets, func(first *domain.Asset, second *domain.Asset) int {
+		return cmp.Compare(first.Id, second.Id)
+	})
+
+	for _, insertedAsset := range insertedAssets {
+		var err = auditDomService.RecordChangeInTransaction(
+			transContext,
+			buildAssetAuditReference(insertedAsset.Id),
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func buildAssetAuditReference(assetId int64) *domain.AuditEntityReference {
+	return &domain.AuditEntityReference{EntityType: domain.AssetAuditEntityType, EntityId: assetId}
+}
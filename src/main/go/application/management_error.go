@@ -0,0 +1,24 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// propagateManagementError propagates an error of a data management transaction as an AppError, except for
+// the domain validation and version conflict errors, returned as they are so the API responds to them.
+func propagateManagementError(err error, message string, origin any) error {
+
+	var validationErr *infra.DomainValidationError
+	if errors.As(err, &validationErr) {
+		return err
+	}
+
+	var conflictErr *infra.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		return err
+	}
+
+	return infra.PropagateAsAppErrorWithNewMessage(err, message, origin)
+}
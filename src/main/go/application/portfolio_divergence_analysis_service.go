@@ -2,8 +2,8 @@ package application
 
 import (
 	"context"
+	"log/slog"
 
-	"github.com/golang/glog"
 	"github.com/shopspring/decimal"
 
 	"github.com/benizzio/open-asset-allocator/domain"
@@ -19,21 +19,26 @@ type PortfolioDivergenceAnalysisAppService struct {
 
 type potentialDivergencesPerHierarchicalId map[string]*domain.PotentialDivergence
 
+// GeneratePortfolioDivergenceAnalysis compares the portfolio allocations at an observation to an allocation
+// plan. The analysis is computed, and logged, within the request context, so all of its steps can be traced
+// by the id of the request.
 func (service *PortfolioDivergenceAnalysisAppService) GeneratePortfolioDivergenceAnalysis(
+	requestContext context.Context,
 	portfolioId int64,
 	observationTimestampId int64,
 	allocationPlanId int64,
 ) (*domain.DivergenceAnalysis, error) {
 
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof(
-		"Generating divergence analysis for portfolio %d at observation %d from allocation plan %d",
-		portfolioId,
-		observationTimestampId,
-		allocationPlanId,
+	slog.DebugContext(
+		requestContext,
+		"Generating divergence analysis",
+		"portfolioId", portfolioId,
+		"observationTimestampId", observationTimestampId,
+		"allocationPlanId", allocationPlanId,
 	)
 
 	var analysisContext, err = service.initializeAnalysisContextForObservationTimestamp(
+		requestContext,
 		portfolioId,
 		observationTimestampId,
 		allocationPlanId,
@@ -43,14 +48,12 @@ func (service *PortfolioDivergenceAnalysisAppService) GeneratePortfolioDivergenc
 	}
 
 	var analysisContextValue = getDivergenceAnalysisContextValue(analysisContext)
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof(
-		"Contextual data for divergence analysis obtained: "+
-			"portfolio \"%s\" at observation \"%s\" "+
-			"will be compared to allocation plan %d",
-		analysisContextValue.portfolio.Name,
-		analysisContextValue.divergenceAnalysis.ObservationTimestamp.TimeTag,
-		allocationPlanId,
+	slog.DebugContext(
+		analysisContext,
+		"Contextual data for divergence analysis obtained",
+		"portfolio", analysisContextValue.portfolio.Name,
+		"observationTimeTag", analysisContextValue.divergenceAnalysis.ObservationTimestamp.TimeTag,
+		"allocationPlanId", allocationPlanId,
 	)
 
 	potentialDivergenceMap, err := service.generateDivergenceAnalysisFromPortfolioAllocationSet(analysisContext)
@@ -70,19 +73,21 @@ func (service *PortfolioDivergenceAnalysisAppService) GeneratePortfolioDivergenc
 }
 
 // initializeAnalysisContextForObservationTimestamp initializes the all the basic structures
-// needed to create a divergence analysis and add them to a context.Context.
+// needed to create a divergence analysis and add them to a context.Context derived from the request context.
 func (service *PortfolioDivergenceAnalysisAppService) initializeAnalysisContextForObservationTimestamp(
+	requestContext context.Context,
 	portfolioId int64,
 	observationTimestampId int64,
 	allocationPlanId int64,
 ) (context.Context, error) {
 
-	portfolio, err := service.portfolioDomService.GetPortfolio(portfolioId)
+	portfolio, err := service.portfolioDomService.GetPortfolio(requestContext, portfolioId)
 	if err != nil {
 		return nil, err
 	}
 
 	portfolioAllocations, err := service.portfolioAllocationDomService.FindPortfolioAllocationsByObservationTimestamp(
+		requestContext,
 		portfolioId,
 		observationTimestampId,
 	)
@@ -104,7 +109,7 @@ func (service *PortfolioDivergenceAnalysisAppService) initializeAnalysisContextF
 		divergenceAnalysis:   divergenceAnalysis,
 	}
 
-	var analysisContext = buildDivergenceAnalysisContext(context.Background(), analysisContextValue)
+	var analysisContext = buildDivergenceAnalysisContext(requestContext, analysisContextValue)
 	return analysisContext, nil
 }
 
@@ -138,6 +143,9 @@ func (service *PortfolioDivergenceAnalysisAppService) generateDivergenceAnalysis
 		}
 
 		divergenceAnalysis.PortfolioTotalMarketValue += allocation.TotalMarketValue
+		if allocation.CashReserve {
+			divergenceAnalysis.CashReserveTotalMarketValue += allocation.TotalMarketValue
+		}
 	}
 
 	return potentialDivergenceMap, nil
@@ -157,12 +165,13 @@ func (service *PortfolioDivergenceAnalysisAppService) complementAnalysisWithAllo
 	var divergenceAnalysis = analysisContextValue.divergenceAnalysis
 
 	plannedAllocationMap, err := service.allocationPlanDomService.
-		GetPlannedAllocationsPerHyerarchicalIdMap(allocationPlanId)
+		GetPlannedAllocationsPerHyerarchicalIdMap(analysisContext, allocationPlanId)
 	if err != nil {
 		return err
 	}
 
 	calculateCurrentDivergenceValuesFromReferencedPlan(
+		analysisContext,
 		divergenceAnalysis.Root,
 		plannedAllocationMap,
 		divergenceAnalysis.PortfolioTotalMarketValue,
@@ -246,11 +255,11 @@ func (service *PortfolioDivergenceAnalysisAppService) buildAndConnectPotentialDi
 
 	if lowerLevelDivergence != nil {
 		potentialDivergence.AddInternalDivergence(lowerLevelDivergence)
-		// TODO verification for debug logging, this should be logged only in debug mode
-		glog.Infof(
-			"Potential divergence %s linked to parent level divergence %s",
-			lowerLevelDivergence.HierarchicalId,
-			potentialDivergence.HierarchicalId,
+		slog.DebugContext(
+			hierarchySubIterationMappingContext,
+			"Potential divergence linked to parent level divergence",
+			"hierarchicalId", lowerLevelDivergence.HierarchicalId,
+			"parentHierarchicalId", potentialDivergence.HierarchicalId,
 		)
 	}
 
@@ -316,11 +325,11 @@ func buildAndAttachPotentialDivergenceIfNotExists(
 		var isLowestLevel = currentHierarchyLevelIndex == 0
 		potentialDivergence = buildPotentialDivergence(hierarchyLevelKey, hierarchicalId, isLowestLevel)
 
-		// TODO verification for debug logging, this should be logged only in debug mode
-		glog.Infof(
-			"Potential divergence node created: %s at level %d",
-			hierarchicalId,
-			currentHierarchyLevelIndex,
+		slog.DebugContext(
+			analysisContext,
+			"Potential divergence node created",
+			"hierarchicalId", hierarchicalId,
+			"level", currentHierarchyLevelIndex,
 		)
 
 		attachToRootIfTopLevel(analysisContext, potentialDivergence)
@@ -366,12 +375,16 @@ func attachToRootIfTopLevel(
 
 	if currentHierarchyLevelIndex == topAllocationHierarchyLevelIndex {
 		analysisContextValue.divergenceAnalysis.AddRootDivergence(potentialDivergence)
-		// TODO verification for debug logging, this should be logged only in debug mode
-		glog.Infof("Potential divergence %s linked to parent root", potentialDivergence.HierarchicalId)
+		slog.DebugContext(
+			analysisContext,
+			"Potential divergence linked to parent root",
+			"hierarchicalId", potentialDivergence.HierarchicalId,
+		)
 	}
 }
 
 func calculateCurrentDivergenceValuesFromReferencedPlan(
+	analysisContext context.Context,
 	potentialDivergences []*domain.PotentialDivergence,
 	plannedAllocationMap domain.PlannedAllocationsPerHierarchicalId,
 	levelTotalMarketValue int64,
@@ -379,7 +392,7 @@ func calculateCurrentDivergenceValuesFromReferencedPlan(
 	for _, potentialDivergence := range potentialDivergences {
 
 		var plannedAllocation = plannedAllocationMap.Get(potentialDivergence.HierarchicalId)
-		calculateDivergenceValue(potentialDivergence, plannedAllocation, levelTotalMarketValue)
+		calculateDivergenceValue(analysisContext, potentialDivergence, plannedAllocation, levelTotalMarketValue)
 
 		if plannedAllocation != nil {
 			//To allow for planned side set difference
@@ -388,6 +401,7 @@ func calculateCurrentDivergenceValuesFromReferencedPlan(
 
 		if potentialDivergence.InternalDivergences != nil {
 			calculateCurrentDivergenceValuesFromReferencedPlan(
+				analysisContext,
 				potentialDivergence.InternalDivergences,
 				plannedAllocationMap,
 				potentialDivergence.TotalMarketValue,
@@ -397,6 +411,7 @@ func calculateCurrentDivergenceValuesFromReferencedPlan(
 }
 
 func calculateDivergenceValue(
+	analysisContext context.Context,
 	potentialDivergence *domain.PotentialDivergence,
 	plannedAllocation *domain.PlannedAllocation,
 	levelTotalMarketValue int64,
@@ -417,16 +432,15 @@ func calculateDivergenceValue(
 
 	potentialDivergence.TotalMarketValueDivergence = potentialDivergence.TotalMarketValue - plannedAllocationValue
 
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof(
-		"Calculated divergence value for %s: planned %s%% of level total %d, "+
-			"so planned value %d, current value %d, divergence %d",
-		potentialDivergence.HierarchicalId,
-		plannedPercentage,
-		levelTotalMarketValue,
-		plannedAllocationValue,
-		potentialDivergence.TotalMarketValue,
-		potentialDivergence.TotalMarketValueDivergence,
+	slog.DebugContext(
+		analysisContext,
+		"Calculated divergence value",
+		"hierarchicalId", potentialDivergence.HierarchicalId,
+		"plannedPercentage", plannedPercentage,
+		"levelTotalMarketValue", levelTotalMarketValue,
+		"plannedValue", plannedAllocationValue,
+		"currentValue", potentialDivergence.TotalMarketValue,
+		"divergence", potentialDivergence.TotalMarketValueDivergence,
 	)
 }
 
@@ -493,6 +507,7 @@ func checkAndGeneratePotentialDivergencesOnHierarchy(
 			)
 
 			calculateDivergenceValue(
+				analysisContext,
 				createdPotentialDivergence,
 				plannedAllocationMap[currentLevelHierarchicalId],
 				parentTotalMarketValue,
@@ -540,26 +555,29 @@ func generateAndAttachPotentialDivergenceForPlannedAllocation(
 	} else if isTopHierarchyLevel {
 		levelIdentifier = "at top"
 	}
-	// TODO verification for debug logging, this should be logged only in debug mode\
-	glog.Infof(
-		"Potential divergence node created: %s %s",
-		currentLevelHierarchicalId,
-		levelIdentifier,
+	slog.DebugContext(
+		analysisContext,
+		"Potential divergence node created",
+		"hierarchicalId", currentLevelHierarchicalId,
+		"level", levelIdentifier,
 	)
 
 	potentialDivergenceMap[currentLevelHierarchicalId] = potentialDivergence
 
 	if isTopHierarchyLevel {
 		divergenceAnalysis.AddRootDivergence(potentialDivergence)
-		// TODO verification for debug logging, this should be logged only in debug mode\
-		glog.Infof("Potential divergence %s linked to parent root", currentLevelHierarchicalId)
+		slog.DebugContext(
+			analysisContext,
+			"Potential divergence linked to parent root",
+			"hierarchicalId", currentLevelHierarchicalId,
+		)
 	} else {
 		parentPotentialDivergence.AddInternalDivergence(potentialDivergence)
-		// TODO verification for debug logging, this should be logged only in debug mode\
-		glog.Infof(
-			"Potential divergence %s linked to parent %s",
-			currentLevelHierarchicalId,
-			parentPotentialDivergence.HierarchicalId,
+		slog.DebugContext(
+			analysisContext,
+			"Potential divergence linked to parent",
+			"hierarchicalId", currentLevelHierarchicalId,
+			"parentHierarchicalId", parentPotentialDivergence.HierarchicalId,
 		)
 	}
 
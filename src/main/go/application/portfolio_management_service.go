@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+)
+
+type PortfolioManagementAppService struct {
+	transactionManager  rdbms.TransactionManager
+	portfolioDomService *service.PortfolioDomService
+	auditDomService     *service.AuditDomService
+}
+
+// PersistPortfolio inserts a portfolio without id, or updates an existing one, in a transaction audited as
+// the actor of the request context.
+func (service *PortfolioManagementAppService) PersistPortfolio(
+	requestContext context.Context,
+	portfolio *domain.Portfolio,
+) (*domain.Portfolio, error) {
+
+	var persistedPortfolio *domain.Portfolio
+	var err = service.transactionManager.RunInTransactionWithContext(
+		requestContext,
+		func(transContext *rdbms.SQLTransactionalContext) error {
+
+			var auditReference = &domain.AuditEntityReference{
+				EntityType: domain.PortfolioAuditEntityType,
+				EntityId:   portfolio.Id,
+			}
+
+			before, err := snapshotPersistedEntityInTransaction(service.auditDomService, transContext, auditReference)
+			if err != nil {
+				return err
+			}
+
+			persistedPortfolio, err = service.portfolioDomService.PersistPortfolioInTransaction(transContext, portfolio)
+			if err != nil {
+				return err
+			}
+
+			auditReference.EntityId = persistedPortfolio.Id
+			auditReference.PortfolioId = &persistedPortfolio.Id
+			return service.auditDomService.RecordChangeInTransaction(transContext, auditReference, before)
+		},
+	)
+
+	if err != nil {
+		return nil, propagateManagementError(err, "Failed to persist portfolio", service)
+	}
+
+	return persistedPortfolio, nil
+}
+
+func BuildPortfolioManagementAppService(
+	transactionManager rdbms.TransactionManager,
+	portfolioDomService *service.PortfolioDomService,
+	auditDomService *service.AuditDomService,
+) *PortfolioManagementAppService {
+	return &PortfolioManagementAppService{
+		transactionManager:  transactionManager,
+		portfolioDomService: portfolioDomService,
+		auditDomService:     auditDomService,
+	}
+}
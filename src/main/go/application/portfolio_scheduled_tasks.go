@@ -0,0 +1,438 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+type revaluationQuoteResult struct {
+	AssetId       int64                      `json:"assetId"`
+	Ticker        string                     `json:"ticker"`
+	Source        domain.AssetExternalSource `json:"source"`
+	Price         decimal.Decimal            `json:"price"`
+	Currency      string                     `json:"currency"`
+	LastCloseDate time.Time                  `json:"lastCloseDate"`
+}
+
+type revaluationFailure struct {
+	AssetId      int64  `json:"assetId"`
+	Ticker       string `json:"ticker"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+type revaluationTaskResult struct {
+	ObservationTimestampId int64                    `json:"observationTimestampId,omitempty"`
+	ObservationTimeTag     string                   `json:"observationTimeTag,omitempty"`
+	QuotedAssets           int                      `json:"quotedAssets"`
+	RevaluedAllocations    int                      `json:"revaluedAllocations"`
+	Quotes                 []revaluationQuoteResult `json:"quotes"`
+	Failures               []revaluationFailure     `json:"failures"`
+	TriggeredAlerts        int                      `json:"triggeredAlerts"`
+	AlertEvaluationError   string                   `json:"alertEvaluationError,omitempty"`
+}
+
+// RevaluationTaskHandler runs the REVALUATION scheduled tasks, quoting the latest close prices of the assets
+// held in the latest observation of the portfolio and recording the revalued allocations as a new
+// observation, then evaluating the divergence alert rules of the portfolio against it. Allocations of assets
+// that cannot be quoted, or that are quoted in a currency other than the asset currency, keep their
+// previous market price. Those assets and a failed alert evaluation are reported in the result, and the task
+// fails only when none of the assets is quoted. It takes no parameters.
+type RevaluationTaskHandler struct {
+	assetDomService                         *service.AssetDomService
+	portfolioAllocationDomService           *service.PortfolioAllocationDomService
+	portfolioAllocationManagementAppService *PortfolioAllocationManagementAppService
+	divergenceAlertEvaluationAppService     *DivergenceAlertEvaluationAppService
+}
+
+func (handler *RevaluationTaskHandler) ValidateParams(_ json.RawMessage) error {
+	return nil
+}
+
+func (handler *RevaluationTaskHandler) Run(ctx context.Context, schedule *domain.PortfolioSchedule) (any, error) {
+
+	var observationTimestamps, err = handler.portfolioAllocationDomService.GetAvailableObservationTimestamps(
+		schedule.PortfolioId,
+		1,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = revaluationTaskResult{
+		Quotes:   make([]revaluationQuoteResult, 0),
+		Failures: make([]revaluationFailure, 0),
+	}
+
+	if len(observationTimestamps) == 0 {
+		return result, nil
+	}
+
+	allocations, err := handler.portfolioAllocationDomService.FindPortfolioAllocationsByObservationTimestamp(
+		ctx,
+		schedule.PortfolioId,
+		observationTimestamps[0].Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := handler.assetDomService.GetPortfolioAssets(schedule.PortfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pricesPerAssetId = handler.quoteAssets(ctx, assets, &result)
+	if len(assets) > 0 && result.QuotedAssets == 0 {
+		return nil, fmt.Errorf("none of the %d assets of portfolio %d could be quoted", len(assets), schedule.PortfolioId)
+	}
+
+	var revaluedAllocations []*domain.PortfolioAllocation
+	revaluedAllocations, result.RevaluedAllocations = revalueAllocations(allocations, pricesPerAssetId)
+
+	revaluedObservation, err := handler.portfolioAllocationManagementAppService.RecordPortfolioRevaluation(
+		ctx,
+		schedule.PortfolioId,
+		revaluedAllocations,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ObservationTimestampId = revaluedObservation.Id
+	result.ObservationTimeTag = revaluedObservation.TimeTag
+
+	result.TriggeredAlerts, err = handler.divergenceAlertEvaluationAppService.EvaluateObservationAlertRules(
+		ctx,
+		schedule.PortfolioId,
+		revaluedObservation,
+	)
+	if err != nil {
+		result.AlertEvaluationError = err.Error()
+	}
+
+	return result, nil
+}
+
+// quoteAssets quotes the assets, reporting the quotes and failures in the result, and returns the quoted
+// prices per asset id. A quote in a currency other than the asset currency is reported as a failure.
+func (handler *RevaluationTaskHandler) quoteAssets(
+	ctx context.Context,
+	assets []*domain.Asset,
+	result *revaluationTaskResult,
+) map[int64]decimal.Decimal {
+
+	var pricesPerAssetId = make(map[int64]decimal.Decimal, len(assets))
+	for _, quoteResult := range handler.assetDomService.QuoteAssetsLastClosePrice(ctx, assets) {
+
+		var quoteErr = quoteResult.Err
+		if quoteErr == nil &&
+			quoteResult.Asset.Currency != "" &&
+			quoteResult.Asset.Currency != quoteResult.Quote.Currency.String() {
+			quoteErr = fmt.Errorf(
+				"quote currency %s differs from asset currency %s",
+				quoteResult.Quote.Currency.String(),
+				quoteResult.Asset.Currency,
+			)
+		}
+
+		if quoteErr != nil {
+			result.Failures = append(
+				result.Failures,
+				revaluationFailure{
+					AssetId:      quoteResult.Asset.Id,
+					Ticker:       quoteResult.Asset.Ticker,
+					ErrorMessage: quoteErr.Error(),
+				},
+			)
+			continue
+		}
+
+		result.Quotes = append(
+			result.Quotes,
+			revaluationQuoteResult{
+				AssetId:       quoteResult.Asset.Id,
+				Ticker:        quoteResult.Asset.Ticker,
+				Source:        quoteResult.Quote.Source,
+				Price:         quoteResult.Quote.LastCloseQuote,
+				Currency:      quoteResult.Quote.Currency.String(),
+				LastCloseDate: quoteResult.Quote.LastCloseDate,
+			},
+		)
+		pricesPerAssetId[quoteResult.Asset.Id] = quoteResult.Quote.LastCloseQuote
+	}
+
+	result.QuotedAssets = len(result.Quotes)
+	return pricesPerAssetId
+}
+
+// revalueAllocations copies the allocations for a new observation, pricing the quantity of each allocation
+// with a quoted price at that price. The other allocations, such as cash reserves without quantity, keep
+// their values.
+//
+// Returns:
+//   - []*domain.PortfolioAllocation: the copies of the allocations, without observation
+//   - int: the count of revalued allocations
+func revalueAllocations(
+	allocations []*domain.PortfolioAllocation,
+	pricesPerAssetId map[int64]decimal.Decimal,
+) ([]*domain.PortfolioAllocation, int) {
+
+	var revaluedAllocations = make([]*domain.PortfolioAllocation, 0, len(allocations))
+	var revaluedCount = 0
+	for _, allocation := range allocations {
+
+		var revaluedAllocation = *allocation
+		revaluedAllocation.ObservationTimestamp = nil
+
+		var price, quoted = pricesPerAssetId[allocation.Asset.Id]
+		if quoted && allocation.AssetQuantity.IsPositive() {
+			revaluedAllocation.AssetMarketPrice = price
+			revaluedAllocation.TotalMarketValue = allocation.AssetQuantity.Mul(price).Round(0).IntPart()
+			revaluedCount++
+		}
+
+		revaluedAllocations = append(revaluedAllocations, &revaluedAllocation)
+	}
+
+	return revaluedAllocations, revaluedCount
+}
+
+func BuildRevaluationTaskHandler(
+	assetDomService *service.AssetDomService,
+	portfolioAllocationDomService *service.PortfolioAllocationDomService,
+	portfolioAllocationManagementAppService *PortfolioAllocationManagementAppService,
+	divergenceAlertEvaluationAppService *DivergenceAlertEvaluationAppService,
+) *RevaluationTaskHandler {
+	return &RevaluationTaskHandler{
+		assetDomService:                         assetDomService,
+		portfolioAllocationDomService:           portfolioAllocationDomService,
+		portfolioAllocationManagementAppService: portfolioAllocationManagementAppService,
+		divergenceAlertEvaluationAppService:     divergenceAlertEvaluationAppService,
+	}
+}
+
+type divergenceAnalysisTaskParams struct {
+	AllocationPlanId           int64            `json:"allocationPlanId"`
+	DivergenceThresholdPercent *decimal.Decimal `json:"divergenceThresholdPercent"`
+}
+
+type divergenceAnalysisRootResult struct {
+	HierarchicalId             string `json:"hierarchicalId"`
+	TotalMarketValue           int64  `json:"totalMarketValue"`
+	TotalMarketValueDivergence int64  `json:"totalMarketValueDivergence"`
+}
+
+type divergenceAnalysisTaskResult struct {
+	ObservationTimestampId    int64                          `json:"observationTimestampId"`
+	ObservationTimeTag        string                         `json:"observationTimeTag"`
+	AllocationPlanId          int64                          `json:"allocationPlanId"`
+	PortfolioTotalMarketValue int64                          `json:"portfolioTotalMarketValue"`
+	RootDivergences           []divergenceAnalysisRootResult `json:"rootDivergences"`
+	ThresholdExceeded         bool                           `json:"thresholdExceeded,omitempty"`
+}
+
+type divergenceThresholdExceedance struct {
+	HierarchicalId             string          `json:"hierarchicalId"`
+	TotalMarketValue           int64           `json:"totalMarketValue"`
+	TotalMarketValueDivergence int64           `json:"totalMarketValueDivergence"`
+	DivergencePercent          decimal.Decimal `json:"divergencePercent"`
+}
+
+// divergenceThresholdExceededEventPayload is the payload of the DIVERGENCE_THRESHOLD_EXCEEDED webhook event.
+type divergenceThresholdExceededEventPayload struct {
+	ScheduleId                 int64                           `json:"scheduleId"`
+	ObservationTimestampId     int64                           `json:"observationTimestampId"`
+	ObservationTimeTag         string                          `json:"observationTimeTag"`
+	AllocationPlanId           int64                           `json:"allocationPlanId"`
+	PortfolioTotalMarketValue  int64                           `json:"portfolioTotalMarketValue"`
+	DivergenceThresholdPercent decimal.Decimal                 `json:"divergenceThresholdPercent"`
+	ExceedingDivergences       []divergenceThresholdExceedance `json:"exceedingDivergences"`
+}
+
+// DivergenceAnalysisTaskHandler runs the DIVERGENCE_ANALYSIS scheduled tasks, computing the divergence of
+// the latest observation of the portfolio from an allocation plan, and recording the divergence of the
+// top level of the allocation hierarchy. When the optional divergenceThresholdPercent param is set, a
+// DIVERGENCE_THRESHOLD_EXCEEDED webhook event is emitted if any top level divergence is beyond that
+// percentage of the portfolio total market value.
+//
+// Example params:
+//
+//	{"allocationPlanId": 1, "divergenceThresholdPercent": "5"}
+type DivergenceAnalysisTaskHandler struct {
+	portfolioAllocationDomService         *service.PortfolioAllocationDomService
+	portfolioDivergenceAnalysisAppService *PortfolioDivergenceAnalysisAppService
+	webhookDomService                     *service.WebhookDomService
+}
+
+func (handler *DivergenceAnalysisTaskHandler) ValidateParams(params json.RawMessage) error {
+	var _, err = parseDivergenceAnalysisTaskParams(params)
+	return err
+}
+
+func (handler *DivergenceAnalysisTaskHandler) Run(
+	ctx context.Context,
+	schedule *domain.PortfolioSchedule,
+) (any, error) {
+
+	var taskParams, err = parseDivergenceAnalysisTaskParams(schedule.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	observationTimestamps, err := handler.portfolioAllocationDomService.GetAvailableObservationTimestamps(
+		schedule.PortfolioId,
+		1,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(observationTimestamps) == 0 {
+		return nil, errors.New("portfolio has no observation to analyse")
+	}
+
+	var latestObservation = observationTimestamps[0]
+	analysis, err := handler.portfolioDivergenceAnalysisAppService.GeneratePortfolioDivergenceAnalysis(
+		ctx,
+		schedule.PortfolioId,
+		latestObservation.Id,
+		taskParams.AllocationPlanId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = divergenceAnalysisTaskResult{
+		ObservationTimestampId:    latestObservation.Id,
+		ObservationTimeTag:        latestObservation.TimeTag,
+		AllocationPlanId:          analysis.AllocationPlanId,
+		PortfolioTotalMarketValue: analysis.PortfolioTotalMarketValue,
+		RootDivergences:           make([]divergenceAnalysisRootResult, 0, len(analysis.Root)),
+	}
+
+	for _, rootDivergence := range analysis.Root {
+		result.RootDivergences = append(
+			result.RootDivergences,
+			divergenceAnalysisRootResult{
+				HierarchicalId:             rootDivergence.HierarchicalId,
+				TotalMarketValue:           rootDivergence.TotalMarketValue,
+				TotalMarketValueDivergence: rootDivergence.TotalMarketValueDivergence,
+			},
+		)
+	}
+
+	if taskParams.DivergenceThresholdPercent != nil {
+		result.ThresholdExceeded, err = handler.notifyExceededThreshold(
+			schedule,
+			*taskParams.DivergenceThresholdPercent,
+			latestObservation,
+			analysis,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// notifyExceededThreshold emits a DIVERGENCE_THRESHOLD_EXCEEDED event with the top level divergences
+// beyond the threshold percentage of the portfolio total market value, reporting whether there was any.
+func (handler *DivergenceAnalysisTaskHandler) notifyExceededThreshold(
+	schedule *domain.PortfolioSchedule,
+	thresholdPercent decimal.Decimal,
+	observation *domain.PortfolioObservationTimestamp,
+	analysis *domain.DivergenceAnalysis,
+) (bool, error) {
+
+	if analysis.PortfolioTotalMarketValue == 0 {
+		return false, nil
+	}
+
+	var portfolioTotalMarketValue = decimal.NewFromInt(analysis.PortfolioTotalMarketValue)
+	var exceedances = make([]divergenceThresholdExceedance, 0)
+	for _, rootDivergence := range analysis.Root {
+
+		var divergencePercent = decimal.NewFromInt(rootDivergence.TotalMarketValueDivergence).
+			Div(portfolioTotalMarketValue).
+			Mul(decimal.NewFromInt(100)).
+			Round(2)
+
+		if divergencePercent.Abs().GreaterThan(thresholdPercent) {
+			exceedances = append(
+				exceedances,
+				divergenceThresholdExceedance{
+					HierarchicalId:             rootDivergence.HierarchicalId,
+					TotalMarketValue:           rootDivergence.TotalMarketValue,
+					TotalMarketValueDivergence: rootDivergence.TotalMarketValueDivergence,
+					DivergencePercent:          divergencePercent,
+				},
+			)
+		}
+	}
+
+	if len(exceedances) == 0 {
+		return false, nil
+	}
+
+	var err = handler.webhookDomService.EmitEvent(
+		schedule.PortfolioId,
+		domain.DivergenceThresholdExceededWebhookEventType,
+		divergenceThresholdExceededEventPayload{
+			ScheduleId:                 schedule.Id,
+			ObservationTimestampId:     observation.Id,
+			ObservationTimeTag:         observation.TimeTag,
+			AllocationPlanId:           analysis.AllocationPlanId,
+			PortfolioTotalMarketValue:  analysis.PortfolioTotalMarketValue,
+			DivergenceThresholdPercent: thresholdPercent,
+			ExceedingDivergences:       exceedances,
+		},
+	)
+
+	return true, err
+}
+
+func parseDivergenceAnalysisTaskParams(params json.RawMessage) (*divergenceAnalysisTaskParams, error) {
+
+	var taskParams divergenceAnalysisTaskParams
+	if err := json.Unmarshal(params, &taskParams); err != nil {
+		return nil, infra.BuildDomainValidationError("Invalid divergence analysis task params: "+err.Error(), nil)
+	}
+
+	if taskParams.AllocationPlanId <= 0 {
+		return nil, infra.BuildDomainValidationError(
+			"Divergence analysis task requires the allocationPlanId param",
+			nil,
+		)
+	}
+
+	if taskParams.DivergenceThresholdPercent != nil && taskParams.DivergenceThresholdPercent.IsNegative() {
+		return nil, infra.BuildDomainValidationError(
+			"Divergence analysis task divergenceThresholdPercent param must not be negative",
+			nil,
+		)
+	}
+
+	return &taskParams, nil
+}
+
+func BuildDivergenceAnalysisTaskHandler(
+	portfolioAllocationDomService *service.PortfolioAllocationDomService,
+	portfolioDivergenceAnalysisAppService *PortfolioDivergenceAnalysisAppService,
+	webhookDomService *service.WebhookDomService,
+) *DivergenceAnalysisTaskHandler {
+	return &DivergenceAnalysisTaskHandler{
+		portfolioAllocationDomService:         portfolioAllocationDomService,
+		portfolioDivergenceAnalysisAppService: portfolioDivergenceAnalysisAppService,
+		webhookDomService:                     webhookDomService,
+	}
+}
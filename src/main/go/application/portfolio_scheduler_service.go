@@ -0,0 +1,162 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// PortfolioSchedulerAppService runs the due activations of the portfolio schedules. Every tick interval it
+// claims the due schedules and runs their tasks one at a time, recording each run in the schedule history.
+// Activations reached later than the misfire threshold, such as the ones passed while the application was
+// not running, are recorded as missed.
+type PortfolioSchedulerAppService struct {
+	portfolioScheduleDomService *service.PortfolioScheduleDomService
+	schedulerConfig             infra.SchedulerConfiguration
+	runContext                  context.Context
+	stopScheduler               context.CancelFunc
+	stopped                     sync.WaitGroup
+}
+
+// Start starts the scheduler loop in the background.
+func (service *PortfolioSchedulerAppService) Start() {
+
+	service.stopped.Add(1)
+	go func() {
+		defer service.stopped.Done()
+
+		var ticker = time.NewTicker(service.schedulerConfig.TickInterval)
+		defer ticker.Stop()
+
+		slog.Info("Portfolio scheduler started", "tickInterval", service.schedulerConfig.TickInterval)
+
+		for {
+			service.runDueSchedules(time.Now())
+
+			select {
+			case <-service.runContext.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the running task and waits for the scheduler loop to end until the stop context is done.
+func (service *PortfolioSchedulerAppService) Stop(stopContext context.Context) {
+
+	service.stopScheduler()
+
+	var stopped = make(chan struct{})
+	go func() {
+		service.stopped.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		slog.Info("Portfolio scheduler stopped")
+	case <-stopContext.Done():
+		slog.Error("Portfolio scheduler stop timed out with a task still running")
+	}
+}
+
+func (service *PortfolioSchedulerAppService) runDueSchedules(now time.Time) {
+
+	var dueSchedules, err = service.portfolioScheduleDomService.GetDueSchedules(now)
+	if err != nil {
+		slog.Error("Error getting due schedules", "error", err)
+		return
+	}
+
+	for _, schedule := range dueSchedules {
+
+		if service.runContext.Err() != nil {
+			return
+		}
+
+		service.runDueSchedule(schedule, now)
+	}
+}
+
+func (service *PortfolioSchedulerAppService) runDueSchedule(schedule *domain.PortfolioSchedule, now time.Time) {
+
+	var activation, err = service.portfolioScheduleDomService.ClaimDueActivation(
+		schedule,
+		now,
+		service.schedulerConfig.MisfireThreshold,
+	)
+	if err != nil {
+		slog.Error("Error claiming the activation of schedule", "scheduleId", schedule.Id, "error", err)
+		return
+	}
+
+	if activation == nil {
+		return
+	}
+
+	slog.Info(
+		"Running schedule",
+		"scheduleId", schedule.Id,
+		"portfolioId", schedule.PortfolioId,
+		"taskType", schedule.TaskType,
+		"activation", *activation,
+	)
+
+	var startedAt = time.Now()
+	var result, runErr = service.runScheduledTask(schedule)
+
+	if runErr != nil {
+		slog.Error("Schedule failed", "scheduleId", schedule.Id, "error", runErr)
+	}
+
+	_, err = service.portfolioScheduleDomService.RecordScheduleRun(schedule, *activation, startedAt, result, runErr)
+	if err != nil {
+		slog.Error("Error recording the run of schedule", "scheduleId", schedule.Id, "error", err)
+	}
+}
+
+// runScheduledTask runs the task of the schedule, converting a panic into the error of the run so it does
+// not stop the scheduler.
+func (service *PortfolioSchedulerAppService) runScheduledTask(
+	schedule *domain.PortfolioSchedule,
+) (result any, err error) {
+
+	var handler, exists = service.portfolioScheduleDomService.GetScheduledTaskHandler(schedule.TaskType)
+	if !exists {
+		return nil, fmt.Errorf("no handler for scheduled task type %s", schedule.TaskType)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("scheduled task panicked: %v", recovered)
+		}
+	}()
+
+	return handler.Run(service.runContext, schedule)
+}
+
+func BuildPortfolioSchedulerAppService(
+	portfolioScheduleDomService *service.PortfolioScheduleDomService,
+	schedulerConfig infra.SchedulerConfiguration,
+) *PortfolioSchedulerAppService {
+
+	if schedulerConfig.TickInterval <= 0 {
+		schedulerConfig.TickInterval = time.Minute
+	}
+
+	var runContext, stopScheduler = context.WithCancel(context.Background())
+
+	return &PortfolioSchedulerAppService{
+		portfolioScheduleDomService: portfolioScheduleDomService,
+		schedulerConfig:             schedulerConfig,
+		runContext:                  runContext,
+		stopScheduler:               stopScheduler,
+	}
+}
@@ -2,30 +2,126 @@ package application
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
 	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/domain/service"
-	"github.com/benizzio/open-asset-allocator/infra"
 	"github.com/benizzio/open-asset-allocator/infra/rdbms"
 	"github.com/benizzio/open-asset-allocator/langext"
 )
 
+// snapshotMergedEventPayload is the payload of the SNAPSHOT_MERGED webhook event.
+type snapshotMergedEventPayload struct {
+	ObservationTimestampId int64      `json:"observationTimestampId"`
+	ObservationTimeTag     string     `json:"observationTimeTag,omitempty"`
+	ObservationTimestamp   *time.Time `json:"observationTimestamp,omitempty"`
+	AllocationCount        int        `json:"allocationCount"`
+}
+
 type PortfolioAllocationManagementAppService struct {
-	transactionManager            rdbms.TransactionManager
-	portfolioAllocationDomService *service.PortfolioAllocationDomService
-	assetDomService               *service.AssetDomService
+	transactionManager                  rdbms.TransactionManager
+	portfolioAllocationDomService       *service.PortfolioAllocationDomService
+	assetDomService                     *service.AssetDomService
+	webhookDomService                   *service.WebhookDomService
+	auditDomService                     *service.AuditDomService
+	divergenceAlertEvaluationAppService *DivergenceAlertEvaluationAppService
 }
 
+// MergePortfolioAllocations merges a snapshot of the allocations of a portfolio into an observation, audited
+// as the actor of the request context, then evaluates the divergence alert rules of the portfolio. A failed
+// evaluation is logged, and does not fail the merge. With a nonzero expected snapshot version, the merge only
+// applies to that version of the allocations of the portfolio in the observation.
+//
+// Returns:
+//   - int64: the new version of the allocations of the portfolio in the observation
+//   - error: an infra.VersionConflictError when the expected version is stale, or any merge error
 func (service *PortfolioAllocationManagementAppService) MergePortfolioAllocations(
+	requestContext context.Context,
 	portfolioId int64,
 	observationTimestamp *domain.PortfolioObservationTimestamp,
 	allocations []*domain.PortfolioAllocation,
-) error {
+	expectedSnapshotVersion int64,
+) (int64, error) {
+
+	var _, snapshotVersion, err = service.mergeSnapshot(
+		requestContext,
+		portfolioId,
+		observationTimestamp,
+		allocations,
+		expectedSnapshotVersion,
+	)
+	if err != nil {
+		return 0, propagateManagementError(err, "Failed to merge portfolio allocations", service)
+	}
+
+	service.evaluateDivergenceAlerts(requestContext, portfolioId)
+	return snapshotVersion, nil
+}
+
+// RecordPortfolioRevaluation inserts a new observation of a portfolio with its revalued allocations, audited as
+// the actor of the request context. Unlike MergePortfolioAllocations, the divergence alert rules are not
+// evaluated, so that the caller can report the evaluation of the revalued observation.
+//
+// Returns:
+//   - *domain.PortfolioObservationTimestamp: the inserted observation
+//   - error: any merge error
+func (service *PortfolioAllocationManagementAppService) RecordPortfolioRevaluation(
+	requestContext context.Context,
+	portfolioId int64,
+	allocations []*domain.PortfolioAllocation,
+) (*domain.PortfolioObservationTimestamp, error) {
+
+	var revaluationTime = time.Now().UTC()
+	var observationTimestamp = &domain.PortfolioObservationTimestamp{
+		TimeTag:   "REVALUATION " + revaluationTime.Format(time.RFC3339),
+		Timestamp: revaluationTime,
+	}
+
+	var managedObservationTimestamp, _, err = service.mergeSnapshot(
+		requestContext,
+		portfolioId,
+		observationTimestamp,
+		allocations,
+		0,
+	)
+	if err != nil {
+		return nil, propagateManagementError(err, "Failed to record portfolio revaluation", service)
+	}
+
+	return managedObservationTimestamp, nil
+}
 
-	var err = service.transactionManager.RunInTransaction(
+func (service *PortfolioAllocationManagementAppService) mergeSnapshot(
+	requestContext context.Context,
+	portfolioId int64,
+	observationTimestamp *domain.PortfolioObservationTimestamp,
+	allocations []*domain.PortfolioAllocation,
+	expectedSnapshotVersion int64,
+) (*domain.PortfolioObservationTimestamp, int64, error) {
+
+	var managedObservationTimestamp *domain.PortfolioObservationTimestamp
+	var snapshotVersion int64
+	var err = service.transactionManager.RunInTransactionWithContext(
+		requestContext,
 		func(transContext *rdbms.SQLTransactionalContext) error {
 
-			managedObservationTimestamp, err := service.manageObservationTimestamp(
+			var observationAuditReference = &domain.AuditEntityReference{
+				EntityType:  domain.PortfolioObservationAuditEntityType,
+				EntityId:    observationTimestamp.Id,
+				PortfolioId: &portfolioId,
+			}
+
+			observationBefore, err := snapshotPersistedEntityInTransaction(
+				service.auditDomService,
+				transContext,
+				observationAuditReference,
+			)
+			if err != nil {
+				return err
+			}
+
+			managedObservationTimestamp, err = service.manageObservationTimestamp(
 				transContext,
 				observationTimestamp,
 				allocations,
@@ -34,21 +130,109 @@ func (service *PortfolioAllocationManagementAppService) MergePortfolioAllocation
 				return err
 			}
 
-			err = service.persistNewAssets(transContext, allocations)
+			observationAuditReference.EntityId = managedObservationTimestamp.Id
+			err = service.auditDomService.RecordChangeInTransaction(
+				transContext,
+				observationAuditReference,
+				observationBefore,
+			)
 			if err != nil {
 				return err
 			}
 
-			return service.portfolioAllocationDomService.MergePortfolioAllocationsInTransaction(
+			err = service.persistNewAssets(transContext, managedObservationTimestamp, allocations)
+			if err != nil {
+				return err
+			}
+
+			// locks the allocations of the portfolio in the observation before reading them for the audit
+			snapshotVersion, err = service.portfolioAllocationDomService.IncrementPortfolioSnapshotVersionInTransaction(
+				transContext,
+				portfolioId,
+				managedObservationTimestamp.Id,
+				expectedSnapshotVersion,
+			)
+			if err != nil {
+				return err
+			}
+
+			var allocationsAuditReference = &domain.AuditEntityReference{
+				EntityType:  domain.PortfolioAllocationAuditEntityType,
+				EntityId:    managedObservationTimestamp.Id,
+				PortfolioId: &portfolioId,
+			}
+
+			allocationsBefore, err := service.auditDomService.SnapshotInTransaction(
+				transContext,
+				allocationsAuditReference,
+			)
+			if err != nil {
+				return err
+			}
+
+			err = service.portfolioAllocationDomService.MergePortfolioAllocationsInTransaction(
 				transContext,
 				portfolioId,
 				managedObservationTimestamp,
 				allocations,
 			)
+			if err != nil {
+				return err
+			}
+
+			err = service.auditDomService.RecordChangeInTransaction(
+				transContext,
+				allocationsAuditReference,
+				allocationsBefore,
+			)
+			if err != nil {
+				return err
+			}
+
+			return service.webhookDomService.EmitEventInTransaction(
+				transContext,
+				portfolioId,
+				domain.SnapshotMergedWebhookEventType,
+				snapshotMergedEventPayload{
+					ObservationTimestampId: managedObservationTimestamp.Id,
+					ObservationTimeTag:     managedObservationTimestamp.TimeTag,
+					ObservationTimestamp:   observationReferenceDate(managedObservationTimestamp),
+					AllocationCount:        len(allocations),
+				},
+			)
 		},
 	)
 
-	return infra.PropagateAsAppErrorWithNewMessage(err, "Failed to merge portfolio allocations", service)
+	return managedObservationTimestamp, snapshotVersion, err
+}
+
+func (service *PortfolioAllocationManagementAppService) evaluateDivergenceAlerts(
+	requestContext context.Context,
+	portfolioId int64,
+) {
+
+	var triggeredCount, err = service.divergenceAlertEvaluationAppService.EvaluatePortfolioAlertRules(
+		requestContext,
+		portfolioId,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			requestContext,
+			"Error evaluating divergence alert rules after merge",
+			"portfolioId", portfolioId,
+			"error", err,
+		)
+		return
+	}
+
+	if triggeredCount > 0 {
+		slog.InfoContext(
+			requestContext,
+			"Divergence alerts triggered after merge",
+			"portfolioId", portfolioId,
+			"triggeredCount", triggeredCount,
+		)
+	}
 }
 
 func (service *PortfolioAllocationManagementAppService) manageObservationTimestamp(
@@ -81,6 +265,7 @@ func (service *PortfolioAllocationManagementAppService) manageObservationTimesta
 
 func (service *PortfolioAllocationManagementAppService) persistNewAssets(
 	transContext context.Context,
+	observationTimestamp *domain.PortfolioObservationTimestamp,
 	allocations []*domain.PortfolioAllocation,
 ) error {
 
@@ -90,19 +275,34 @@ func (service *PortfolioAllocationManagementAppService) persistNewAssets(
 		return nil
 	}
 
-	persistedAssetsPerTicker, err := service.assetDomService.InsertMappedAssetsInTransaction(
+	persistedAssetsPerTicker, insertedAssetsPerTicker, err := service.assetDomService.PersistMappedAssetsInTransaction(
 		transContext,
 		assetsToInsertPerTicker,
+		observationReferenceDate(observationTimestamp),
 	)
 	if err != nil {
 		return err
 	}
 
+	err = recordCreatedAssetsInTransaction(service.auditDomService, transContext, insertedAssetsPerTicker)
+	if err != nil {
+		return err
+	}
+
 	replacePersistedAssetsOnPortfolioAllocations(allocations, persistedAssetsPerTicker)
 
 	return nil
 }
 
+// observationReferenceDate returns the date used to resolve tickers of an observation through asset
+// aliases, or nil when the observation timestamp is unknown (e.g. only referenced by id).
+func observationReferenceDate(observationTimestamp *domain.PortfolioObservationTimestamp) *time.Time {
+	if observationTimestamp == nil || observationTimestamp.Timestamp.IsZero() {
+		return nil
+	}
+	return &observationTimestamp.Timestamp
+}
+
 func mapNewAssetsPerTickerFromPortfolioAllocations(allocations []*domain.PortfolioAllocation) domain.AssetsPerTicker {
 	var assetsToInsertPerTicker = make(domain.AssetsPerTicker)
 	for _, allocation := range allocations {
@@ -130,10 +330,16 @@ func BuildPortfolioAllocationManagementAppService(
 	transactionManager rdbms.TransactionManager,
 	portfolioAllocationDomService *service.PortfolioAllocationDomService,
 	assetDomService *service.AssetDomService,
+	webhookDomService *service.WebhookDomService,
+	auditDomService *service.AuditDomService,
+	divergenceAlertEvaluationAppService *DivergenceAlertEvaluationAppService,
 ) *PortfolioAllocationManagementAppService {
 	return &PortfolioAllocationManagementAppService{
 		transactionManager,
 		portfolioAllocationDomService,
 		assetDomService,
+		webhookDomService,
+		auditDomService,
+		divergenceAlertEvaluationAppService,
 	}
 }
@@ -0,0 +1,167 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const (
+	outboxFanOutBatchSize          = 100
+	webhookDeliveriesBatchSize     = 50
+	defaultWebhookDispatchInterval = 5 * time.Second
+)
+
+// WebhookDispatcherAppService delivers the portfolio events written to the outbox. Every dispatch interval
+// it fans the committed events out to one delivery per subscribed webhook, and attempts the deliveries that
+// are due, one at a time. Failed deliveries are retried on later dispatches, as scheduled by the backoff.
+type WebhookDispatcherAppService struct {
+	webhookDomService *service.WebhookDomService
+	webhookConfig     infra.WebhookConfiguration
+	runContext        context.Context
+	stopDispatcher    context.CancelFunc
+	stopped           sync.WaitGroup
+}
+
+// Start starts the dispatcher loop in the background.
+func (service *WebhookDispatcherAppService) Start() {
+
+	service.stopped.Add(1)
+	go func() {
+		defer service.stopped.Done()
+
+		var ticker = time.NewTicker(service.webhookConfig.DispatchInterval)
+		defer ticker.Stop()
+
+		slog.Info("Webhook dispatcher started", "dispatchInterval", service.webhookConfig.DispatchInterval)
+
+		for {
+			service.dispatch()
+
+			select {
+			case <-service.runContext.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the delivery in progress and waits for the dispatcher loop to end until the stop context
+// is done. The cancelled delivery is retried after the next start.
+func (service *WebhookDispatcherAppService) Stop(stopContext context.Context) {
+
+	service.stopDispatcher()
+
+	var stopped = make(chan struct{})
+	go func() {
+		service.stopped.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		slog.Info("Webhook dispatcher stopped")
+	case <-stopContext.Done():
+		slog.Error("Webhook dispatcher stop timed out with a delivery still running")
+	}
+}
+
+func (service *WebhookDispatcherAppService) dispatch() {
+	service.fanOutOutboxEvents()
+	service.attemptDueDeliveries()
+}
+
+func (service *WebhookDispatcherAppService) fanOutOutboxEvents() {
+
+	for service.runContext.Err() == nil {
+
+		var dispatchedCount, err = service.webhookDomService.FanOutOutboxEvents(outboxFanOutBatchSize)
+		if err != nil {
+			slog.Error("Error fanning out outbox events", "error", err)
+			return
+		}
+
+		if dispatchedCount < outboxFanOutBatchSize {
+			return
+		}
+	}
+}
+
+func (service *WebhookDispatcherAppService) attemptDueDeliveries() {
+
+	var dueDeliveries, err = service.webhookDomService.GetDueDeliveries(time.Now(), webhookDeliveriesBatchSize)
+	if err != nil {
+		slog.Error("Error getting due webhook deliveries", "error", err)
+		return
+	}
+
+	for _, dueDelivery := range dueDeliveries {
+
+		if service.runContext.Err() != nil {
+			return
+		}
+
+		service.attemptDelivery(dueDelivery)
+	}
+}
+
+func (service *WebhookDispatcherAppService) attemptDelivery(dueDelivery *domain.DueWebhookDelivery) {
+
+	var delivery, err = service.webhookDomService.AttemptDelivery(service.runContext, dueDelivery)
+	if err != nil && service.runContext.Err() != nil {
+		slog.Info("Webhook delivery attempt interrupted by stop", "deliveryId", dueDelivery.Delivery.Id)
+		return
+	}
+
+	if err != nil {
+		slog.Error("Error attempting webhook delivery", "deliveryId", dueDelivery.Delivery.Id, "error", err)
+		return
+	}
+
+	switch delivery.Status {
+	case domain.SucceededWebhookDeliveryStatus:
+		slog.Info("Webhook delivery delivered", "deliveryId", delivery.Id, "eventId", delivery.EventId)
+	case domain.FailedWebhookDeliveryStatus:
+		slog.Error(
+			"Webhook delivery failed after its last attempt",
+			"deliveryId", delivery.Id,
+			"eventId", delivery.EventId,
+			"attemptCount", delivery.AttemptCount,
+			"error", delivery.LastError,
+		)
+	default:
+		slog.Warn(
+			"Webhook delivery attempt failed, retrying",
+			"deliveryId", delivery.Id,
+			"eventId", delivery.EventId,
+			"attemptCount", delivery.AttemptCount,
+			"nextAttemptAt", delivery.NextAttemptAt,
+			"error", delivery.LastError,
+		)
+	}
+}
+
+func BuildWebhookDispatcherAppService(
+	webhookDomService *service.WebhookDomService,
+	webhookConfig infra.WebhookConfiguration,
+) *WebhookDispatcherAppService {
+
+	if webhookConfig.DispatchInterval <= 0 {
+		webhookConfig.DispatchInterval = defaultWebhookDispatchInterval
+	}
+
+	var runContext, stopDispatcher = context.WithCancel(context.Background())
+
+	return &WebhookDispatcherAppService{
+		webhookDomService: webhookDomService,
+		webhookConfig:     webhookConfig,
+		runContext:        runContext,
+		stopDispatcher:    stopDispatcher,
+	}
+}
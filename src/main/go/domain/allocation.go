@@ -8,6 +8,10 @@ import (
 
 const HierarchicalIdLevelSeparator = "|"
 
+// UnspecifiedHierarchyLevelValue labels a hierarchy level whose field is empty on the allocation, as with
+// optional asset metadata (instrument type, currency, exchange), so it is grouped under a visible name.
+const UnspecifiedHierarchyLevelValue = "UNSPECIFIED"
+
 type AllocationHierarchyLevel struct {
 	Name  string `json:"name,omitempty"`
 	Field string `json:"field,omitempty"`
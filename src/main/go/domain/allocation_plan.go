@@ -33,12 +33,15 @@ type AllocationPlanIdentifier struct {
 	Name string
 }
 
+// AllocationPlan is a planned allocation of a portfolio. Version is incremented on each update, and updates
+// of a plan with a nonzero version only apply to that version.
 type AllocationPlan struct {
 	AllocationPlanIdentifier
 	PlanType             allocation.PlanType
 	PlannedExecutionDate *time.Time
 	PortfolioId          int64
 	Details              []*PlannedAllocation
+	Version              int64
 }
 
 func (allocationPlan *AllocationPlan) AddDetail(detail *PlannedAllocation) {
@@ -47,7 +50,7 @@ func (allocationPlan *AllocationPlan) AddDetail(detail *PlannedAllocation) {
 
 type AllocationPlanRepository interface {
 	GetAllAllocationPlans(portfolioId int64, planType *allocation.PlanType) ([]*AllocationPlan, error)
-	GetAllocationPlan(id int64) (*AllocationPlan, error)
+	GetAllocationPlan(queryContext context.Context, id int64) (*AllocationPlan, error)
 	GetAllAllocationPlanIdentifiers(
 		portfolioId int64,
 		planType *allocation.PlanType,
@@ -1,10 +1,119 @@
 package domain
 
+import (
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+type AssetInstrumentType string
+
+const (
+	EquityInstrumentType    AssetInstrumentType = "EQUITY"
+	ETFInstrumentType       AssetInstrumentType = "ETF"
+	BondInstrumentType      AssetInstrumentType = "BOND"
+	CryptoInstrumentType    AssetInstrumentType = "CRYPTO"
+	CashInstrumentType      AssetInstrumentType = "CASH"
+	CommodityInstrumentType AssetInstrumentType = "COMMODITY"
+)
+
+func (instrumentType AssetInstrumentType) IsValid() bool {
+	switch instrumentType {
+	case EquityInstrumentType,
+		ETFInstrumentType,
+		BondInstrumentType,
+		CryptoInstrumentType,
+		CashInstrumentType,
+		CommodityInstrumentType:
+		return true
+	}
+	return false
+}
+
 type Asset struct {
-	Id           int64
-	Name         string
-	Ticker       string
-	ExternalData *ExternalAssetData
+	Id             int64
+	Name           string
+	Ticker         string
+	InstrumentType AssetInstrumentType
+	Currency       string
+	ISIN           string
+	CUSIP          string
+	Exchange       string
+	ExternalData   *ExternalAssetData
+	// Version is incremented on each update, and updates of an asset with a nonzero version only apply to
+	// that version
+	Version int64
+}
+
+// Validate checks the optional asset metadata: instrument type, ISO 4217 currency code and the
+// check digits of the ISIN and CUSIP identifiers. Empty metadata is always valid.
+//
+// Returns:
+//   - error: a DomainValidationError listing every invalid field, nil when all are valid
+func (asset *Asset) Validate() error {
+
+	var validationErrors = make([]*infra.AppError, 0)
+
+	if asset.InstrumentType != "" && !asset.InstrumentType.IsValid() {
+		validationErrors = append(
+			validationErrors,
+			infra.BuildAppErrorFormattedUnconverted(asset, "Invalid instrument type %s", asset.InstrumentType),
+		)
+	}
+
+	if asset.Currency != "" {
+		if _, err := currency.ParseISO(asset.Currency); err != nil {
+			validationErrors = append(
+				validationErrors,
+				infra.BuildAppErrorFormattedUnconverted(asset, "Invalid currency %s", asset.Currency),
+			)
+		}
+	}
+
+	if asset.ISIN != "" && !IsValidISIN(asset.ISIN) {
+		validationErrors = append(
+			validationErrors,
+			infra.BuildAppErrorFormattedUnconverted(asset, "Invalid ISIN %s", asset.ISIN),
+		)
+	}
+
+	if asset.CUSIP != "" && !IsValidCUSIP(asset.CUSIP) {
+		validationErrors = append(
+			validationErrors,
+			infra.BuildAppErrorFormattedUnconverted(asset, "Invalid CUSIP %s", asset.CUSIP),
+		)
+	}
+
+	if len(validationErrors) > 0 {
+		return infra.BuildDomainValidationError("Asset validation failed", validationErrors)
+	}
+
+	return nil
+}
+
+// FillMetadataFromExternalData completes the missing instrument type and exchange with the values of the
+// linked external assets, as obtained from the external source search, in priority order. The MANUAL
+// external asset is skipped, as it has no exchange.
+func (asset *Asset) FillMetadataFromExternalData() {
+
+	if asset.ExternalData == nil {
+		return
+	}
+
+	for _, externalAsset := range asset.ExternalData.Data {
+
+		if externalAsset.Source == ManualSource {
+			continue
+		}
+
+		if asset.InstrumentType == "" && externalAsset.InstrumentType.IsValid() {
+			asset.InstrumentType = externalAsset.InstrumentType
+		}
+
+		if asset.Exchange == "" {
+			asset.Exchange = externalAsset.ExchangeId
+		}
+	}
 }
 
 type AssetsPerTicker map[string]*Asset
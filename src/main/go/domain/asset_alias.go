@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// AssetAlias is a ticker that identified an asset in the past or in a specific source, such as the
+// previous symbol of an asset after an exchange symbol change. ValidFrom and ValidTo are optional and,
+// when present, restrict the dates in which the alias resolves to the asset.
+type AssetAlias struct {
+	Id        int64
+	AssetId   int64
+	Ticker    string
+	Source    string
+	ValidFrom *time.Time
+	ValidTo   *time.Time
+}
+
+// Validate checks the alias invariants that cannot be expressed through request validation.
+//
+// Returns:
+//   - error: a DomainValidationError when the validity interval is inverted, nil otherwise
+func (alias *AssetAlias) Validate() error {
+
+	if alias.ValidFrom != nil && alias.ValidTo != nil && alias.ValidFrom.After(*alias.ValidTo) {
+		return infra.BuildDomainValidationError(
+			"Asset alias validation failed",
+			[]*infra.AppError{
+				infra.BuildAppErrorFormattedUnconverted(
+					alias,
+					"Asset alias %s validity start must not be after its validity end",
+					alias.Ticker,
+				),
+			},
+		)
+	}
+
+	return nil
+}
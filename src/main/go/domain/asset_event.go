@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type AssetEventType string
+
+const (
+	DividendAssetEventType AssetEventType = "DIVIDEND"
+	SplitAssetEventType    AssetEventType = "SPLIT"
+)
+
+// AssetEvent is a dividend or split of an asset, imported from a market data provider. Dividends carry
+// the amount paid per share and its currency, with EventDate as the ex-dividend date. Splits carry the
+// number of shares after (SplitNumerator) and before (SplitDenominator) the split, so a 4:1 split has
+// numerator 4 and denominator 1 and a 1:10 reverse split has numerator 1 and denominator 10.
+type AssetEvent struct {
+	Id               int64
+	AssetId          int64
+	EventType        AssetEventType
+	EventDate        time.Time
+	Source           AssetExternalSource
+	Amount           decimal.Decimal
+	Currency         string
+	SplitNumerator   decimal.Decimal
+	SplitDenominator decimal.Decimal
+}
+
+// SplitRatio returns the number of shares each share becomes through the split, or one for events that
+// are not valid splits.
+func (event *AssetEvent) SplitRatio() decimal.Decimal {
+	if event.EventType != SplitAssetEventType ||
+		!event.SplitNumerator.IsPositive() ||
+		!event.SplitDenominator.IsPositive() {
+		return decimal.NewFromInt(1)
+	}
+	return event.SplitNumerator.Div(event.SplitDenominator)
+}
+
+// AssetEventIntegrationService defines the contract for external providers able to report the dividend
+// and split events of an asset. The method must honor ctx cancellation.
+type AssetEventIntegrationService interface {
+
+	// GetAssetEvents fetches the full dividend and split history of one external asset, translated to
+	// domain events ordered by date. The returned events are not bound to a persisted asset yet.
+	GetAssetEvents(ctx context.Context, asset *ExternalAsset) ([]*AssetEvent, error)
+}
+
+// SplitAdjustmentFactor returns the product of the ratios of the splits effective after from and up to
+// to, which converts a quantity held at from into the equivalent quantity at to. Prices convert with
+// the inverse factor.
+//
+// Example:
+//
+//	// a 4:1 split between the dates makes 10 shares at from equivalent to 40 shares at to
+//	var factor = domain.SplitAdjustmentFactor(events, observationTimestamp, time.Now())
+//	var adjustedQuantity = quantity.Mul(factor)
+func SplitAdjustmentFactor(events []*AssetEvent, from time.Time, to time.Time) decimal.Decimal {
+
+	var factor = decimal.NewFromInt(1)
+	for _, event := range events {
+		if event.EventType != SplitAssetEventType || !event.EventDate.After(from) || event.EventDate.After(to) {
+			continue
+		}
+		factor = factor.Mul(event.SplitRatio())
+	}
+
+	return factor
+}
+
+// AdjustPortfolioAllocationsForSplits converts the quantities and prices of historical allocations to
+// the share basis at the reference time, applying the splits of each asset effective after the
+// allocation observation. Market values are unchanged, so observations can be compared share by share.
+func AdjustPortfolioAllocationsForSplits(
+	allocations []*PortfolioAllocation,
+	eventsPerAssetId map[int64][]*AssetEvent,
+	referenceTime time.Time,
+) {
+	for _, allocation := range allocations {
+
+		var events = eventsPerAssetId[allocation.Asset.Id]
+		if len(events) == 0 || allocation.ObservationTimestamp == nil {
+			continue
+		}
+
+		var factor = SplitAdjustmentFactor(events, allocation.ObservationTimestamp.Timestamp, referenceTime)
+		if factor.Equal(decimal.NewFromInt(1)) {
+			continue
+		}
+
+		allocation.AssetQuantity = allocation.AssetQuantity.Mul(factor)
+		allocation.AssetMarketPrice = allocation.AssetMarketPrice.Div(factor)
+	}
+}
+
+// AssetIncomeRecord is the dividend income of a portfolio from one dividend event, derived from the
+// asset quantity held in the latest portfolio observation up to the ex-dividend date. The quantity is
+// converted to the share basis of the ex-dividend date when splits happened after the observation.
+type AssetIncomeRecord struct {
+	Asset                Asset
+	ExDividendDate       time.Time
+	AmountPerShare       decimal.Decimal
+	Currency             string
+	AssetQuantity        decimal.Decimal
+	ObservationTimestamp time.Time
+}
+
+// TotalAmount returns the income received, as the amount per share times the quantity held.
+func (record *AssetIncomeRecord) TotalAmount() decimal.Decimal {
+	return record.AmountPerShare.Mul(record.AssetQuantity)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestSplitEvent(eventDate string, numerator int64, denominator int64) *AssetEvent {
+	var date, _ = time.Parse(time.DateOnly, eventDate)
+	return &AssetEvent{
+		EventType:        SplitAssetEventType,
+		EventDate:        date,
+		SplitNumerator:   decimal.NewFromInt(numerator),
+		SplitDenominator: decimal.NewFromInt(denominator),
+	}
+}
+
+func TestSplitAdjustmentFactor(t *testing.T) {
+
+	var dividendDate, _ = time.Parse(time.DateOnly, "2024-03-01")
+	var events = []*AssetEvent{
+		buildTestSplitEvent("2022-06-01", 4, 1),
+		{EventType: DividendAssetEventType, EventDate: dividendDate, Amount: decimal.NewFromFloat(0.5)},
+		buildTestSplitEvent("2024-06-01", 1, 10),
+	}
+
+	var testCases = []struct {
+		name     string
+		from     string
+		to       string
+		expected string
+	}{
+		{"no split in interval", "2022-07-01", "2024-05-31", "1"},
+		{"forward split", "2022-01-01", "2024-05-31", "4"},
+		{"split on the from date is not applied", "2022-06-01", "2024-05-31", "1"},
+		{"split on the to date is applied", "2022-01-01", "2022-06-01", "4"},
+		{"forward and reverse splits", "2022-01-01", "2025-01-01", "0.4"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var from, _ = time.Parse(time.DateOnly, testCase.from)
+			var to, _ = time.Parse(time.DateOnly, testCase.to)
+
+			var factor = SplitAdjustmentFactor(events, from, to)
+
+			assert.True(t, factor.Equal(decimal.RequireFromString(testCase.expected)), factor.String())
+		})
+	}
+}
+
+func TestAdjustPortfolioAllocationsForSplits(t *testing.T) {
+
+	var observationTime, _ = time.Parse(time.DateOnly, "2022-01-01")
+	var referenceTime, _ = time.Parse(time.DateOnly, "2025-01-01")
+
+	var splitAllocation = &PortfolioAllocation{
+		Asset:                Asset{Id: 1},
+		ObservationTimestamp: &PortfolioObservationTimestamp{Timestamp: observationTime},
+		TotalMarketValue:     10000,
+		AssetQuantity:        decimal.NewFromInt(100),
+		AssetMarketPrice:     decimal.NewFromInt(100),
+	}
+	var unsplitAllocation = &PortfolioAllocation{
+		Asset:                Asset{Id: 2},
+		ObservationTimestamp: &PortfolioObservationTimestamp{Timestamp: observationTime},
+		TotalMarketValue:     5000,
+		AssetQuantity:        decimal.NewFromInt(50),
+		AssetMarketPrice:     decimal.NewFromInt(100),
+	}
+
+	AdjustPortfolioAllocationsForSplits(
+		[]*PortfolioAllocation{splitAllocation, unsplitAllocation},
+		map[int64][]*AssetEvent{1: {buildTestSplitEvent("2022-06-01", 4, 1)}},
+		referenceTime,
+	)
+
+	assert.True(t, splitAllocation.AssetQuantity.Equal(decimal.NewFromInt(400)))
+	assert.True(t, splitAllocation.AssetMarketPrice.Equal(decimal.NewFromInt(25)))
+	assert.Equal(t, int64(10000), splitAllocation.TotalMarketValue)
+	assert.True(t, unsplitAllocation.AssetQuantity.Equal(decimal.NewFromInt(50)))
+	assert.True(t, unsplitAllocation.AssetMarketPrice.Equal(decimal.NewFromInt(100)))
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"database/sql/driver"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -16,16 +17,49 @@ type AssetExternalSource string
 
 const (
 	YahooFinanceSource AssetExternalSource = "YAHOO_FINANCE"
+	StooqSource        AssetExternalSource = "STOOQ"
+	CoinGeckoSource    AssetExternalSource = "COINGECKO"
+	ManualSource       AssetExternalSource = "MANUAL"
 )
 
-func (externalSource AssetExternalSource) Validate() error {
+// configuredExternalSources holds the sources of the providers declared in the configuration, valid in
+// addition to the built-in sources.
+var configuredExternalSources sync.Map
+
+func (externalSource AssetExternalSource) isBuiltIn() bool {
 	switch externalSource {
-	case YahooFinanceSource:
+	case YahooFinanceSource, StooqSource, CoinGeckoSource, ManualSource:
+		return true
+	}
+	return false
+}
+
+func (externalSource AssetExternalSource) Validate() error {
+	if externalSource.isBuiltIn() {
+		return nil
+	}
+	if _, configured := configuredExternalSources.Load(externalSource); configured {
 		return nil
 	}
 	return infra.BuildDomainValidationError(fmt.Sprintf("Invalid AssetExternalSource %s", externalSource), nil)
 }
 
+// RegisterConfiguredExternalSource makes the source of a provider declared in the configuration a
+// valid AssetExternalSource. It must be called at startup, before external assets of the source are
+// linked.
+//
+// Returns:
+//   - error: if the source is one of the built-in sources
+func RegisterConfiguredExternalSource(externalSource AssetExternalSource) error {
+
+	if externalSource.isBuiltIn() {
+		return fmt.Errorf("configured external source %s conflicts with a built-in source", externalSource)
+	}
+
+	configuredExternalSources.Store(externalSource, true)
+	return nil
+}
+
 type ExternalAssetData struct {
 	Data []ExternalAsset `json:"data"`
 }
@@ -38,15 +72,109 @@ func (externalData ExternalAssetData) Value() (driver.Value, error) {
 	return sqlext.ValueJsonColumn(externalData)
 }
 
+// FindIndex returns the priority index of the linked external asset with the same reference as the
+// given one, or -1 when it is not linked.
+func (externalData *ExternalAssetData) FindIndex(reference *ExternalAsset) int {
+	for index := range externalData.Data {
+		if externalData.Data[index].HasSameReference(reference) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Link appends an external asset as the lowest priority source.
+//
+// Returns:
+//   - error: a DomainValidationError when the source is invalid or the external asset is already linked
+func (externalData *ExternalAssetData) Link(externalAsset ExternalAsset) error {
+
+	var err = externalAsset.Source.Validate()
+	if err != nil {
+		return err
+	}
+
+	if externalData.FindIndex(&externalAsset) >= 0 {
+		return infra.BuildDomainValidationError(
+			fmt.Sprintf("External asset %s is already linked", externalAsset.String()),
+			nil,
+		)
+	}
+
+	externalData.Data = append(externalData.Data, externalAsset)
+	return nil
+}
+
+// Unlink removes the external asset with the same reference as the given one.
+//
+// Returns:
+//   - bool: false when the external asset is not linked
+func (externalData *ExternalAssetData) Unlink(reference *ExternalAsset) bool {
+
+	var index = externalData.FindIndex(reference)
+	if index < 0 {
+		return false
+	}
+
+	externalData.Data = append(externalData.Data[:index], externalData.Data[index+1:]...)
+	return true
+}
+
+// Prioritize reorders the linked external assets to follow the given references, from highest to
+// lowest priority. The references must contain every linked external asset exactly once.
+//
+// Returns:
+//   - error: a DomainValidationError when the references are not a reordering of the linked external assets
+func (externalData *ExternalAssetData) Prioritize(references []ExternalAsset) error {
+
+	var invalidOrderError = infra.BuildDomainValidationError(
+		"External asset priority must list every linked external asset exactly once",
+		nil,
+	)
+
+	if len(references) != len(externalData.Data) {
+		return invalidOrderError
+	}
+
+	var prioritizedData = make([]ExternalAsset, len(references))
+	var usedIndexes = make(map[int]bool, len(references))
+	for priority, reference := range references {
+		var index = externalData.FindIndex(&reference)
+		if index < 0 || usedIndexes[index] {
+			return invalidOrderError
+		}
+		usedIndexes[index] = true
+		prioritizedData[priority] = externalData.Data[index]
+	}
+
+	externalData.Data = prioritizedData
+	return nil
+}
+
 type ExternalAsset struct {
 	Source       AssetExternalSource `json:"source"`
 	Ticker       string              `json:"ticker"`
 	ExchangeId   string              `json:"exchangeId"`
 	Name         string              `json:"-"`
 	ExchangeName string              `json:"-"`
+	// InstrumentType is the instrument type reported by the external source search, used to fill the
+	// asset metadata when the external asset is linked
+	InstrumentType AssetInstrumentType `json:"-"`
+}
+
+// HasSameReference reports whether both external assets reference the same asset in the same source.
+func (externalAsset *ExternalAsset) HasSameReference(other *ExternalAsset) bool {
+	return externalAsset.Source == other.Source &&
+		externalAsset.Ticker == other.Ticker &&
+		externalAsset.ExchangeId == other.ExchangeId
+}
+
+func (externalAsset *ExternalAsset) String() string {
+	return fmt.Sprintf("%s:%s:%s", externalAsset.Source, externalAsset.ExchangeId, externalAsset.Ticker)
 }
 
 type ExternalAssetQuote struct {
+	Source         AssetExternalSource
 	Ticker         string
 	ExchangeId     string
 	Currency       currency.Unit
@@ -0,0 +1,108 @@
+package domain
+
+const (
+	isinLength  = 12
+	cusipLength = 9
+)
+
+// IsValidISIN checks the format of an International Securities Identification Number (ISO 6166) and
+// its Luhn check digit, computed over the identifier with letters expanded to their numeric values
+// (A = 10 ... Z = 35).
+//
+// Example:
+//
+//	IsValidISIN("US78462F1030") // true
+func IsValidISIN(isin string) bool {
+
+	if len(isin) != isinLength || !isUpperLetter(isin[0]) || !isUpperLetter(isin[1]) || !isDigit(isin[11]) {
+		return false
+	}
+
+	var expandedDigits = make([]int, 0, isinLength*2)
+	for i := 0; i < isinLength; i++ {
+		var character = isin[i]
+		switch {
+		case isDigit(character):
+			expandedDigits = append(expandedDigits, int(character-'0'))
+		case isUpperLetter(character):
+			var value = int(character-'A') + 10
+			expandedDigits = append(expandedDigits, value/10, value%10)
+		default:
+			return false
+		}
+	}
+
+	return luhnSum(expandedDigits)%10 == 0
+}
+
+// IsValidCUSIP checks the format of a Committee on Uniform Securities Identification Procedures number
+// and its modulus 10 "double-add-double" check digit.
+//
+// Example:
+//
+//	IsValidCUSIP("78462F103") // true
+func IsValidCUSIP(cusip string) bool {
+
+	if len(cusip) != cusipLength || !isDigit(cusip[cusipLength-1]) {
+		return false
+	}
+
+	var sum int
+	for i := 0; i < cusipLength-1; i++ {
+		var value, ok = cusipCharacterValue(cusip[i])
+		if !ok {
+			return false
+		}
+
+		if i%2 == 1 {
+			value *= 2
+		}
+
+		sum += value/10 + value%10
+	}
+
+	var checkDigit = (10 - sum%10) % 10
+	return checkDigit == int(cusip[cusipLength-1]-'0')
+}
+
+// luhnSum computes the Luhn sum of the digits, doubling every second digit from the rightmost one.
+func luhnSum(digits []int) int {
+
+	var sum int
+	for i := len(digits) - 1; i >= 0; i-- {
+		var digit = digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	return sum
+}
+
+func cusipCharacterValue(character byte) (int, bool) {
+	switch {
+	case isDigit(character):
+		return int(character - '0'), true
+	case isUpperLetter(character):
+		return int(character-'A') + 10, true
+	case character == '*':
+		return 36, true
+	case character == '@':
+		return 37, true
+	case character == '#':
+		return 38, true
+	}
+	return 0, false
+}
+
+func isDigit(character byte) bool {
+	return character >= '0' && character <= '9'
+}
+
+func isUpperLetter(character byte) bool {
+	return character >= 'A' && character <= 'Z'
+}
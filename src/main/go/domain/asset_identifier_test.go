@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+func TestIsValidISIN(t *testing.T) {
+
+	var validISINs = []string{"US78462F1030", "US0378331005", "IE00B4L5Y983", "BRPETRACNPR6"}
+	for _, isin := range validISINs {
+		assert.True(t, IsValidISIN(isin), isin)
+	}
+
+	var invalidISINs = []string{
+		"",
+		"US78462F1031",  // wrong check digit
+		"US78462F103",   // too short
+		"US78462F10300", // too long
+		"1S78462F1030",  // country code with digit
+		"us78462f1030",  // lowercase
+		"US78462F103X",  // non-digit check character
+		"US78462-1030",  // invalid character
+	}
+	for _, isin := range invalidISINs {
+		assert.False(t, IsValidISIN(isin), isin)
+	}
+}
+
+func TestIsValidCUSIP(t *testing.T) {
+
+	var validCUSIPs = []string{"78462F103", "037833100", "38259P508", "594918104"}
+	for _, cusip := range validCUSIPs {
+		assert.True(t, IsValidCUSIP(cusip), cusip)
+	}
+
+	var invalidCUSIPs = []string{
+		"",
+		"78462F104",  // wrong check digit
+		"78462F10",   // too short
+		"78462F1030", // too long
+		"78462f103",  // lowercase
+		"78462F10X",  // non-digit check character
+		"78462-103",  // invalid character
+	}
+	for _, cusip := range invalidCUSIPs {
+		assert.False(t, IsValidCUSIP(cusip), cusip)
+	}
+}
+
+func TestAssetValidate(t *testing.T) {
+
+	t.Run("ValidWithoutMetadata", func(t *testing.T) {
+		var asset = Asset{Ticker: "ARCA:SPY"}
+		assert.NoError(t, asset.Validate())
+	})
+
+	t.Run("ValidWithMetadata", func(t *testing.T) {
+		var asset = Asset{
+			Ticker:         "ARCA:SPY",
+			InstrumentType: ETFInstrumentType,
+			Currency:       "USD",
+			ISIN:           "US78462F1030",
+			CUSIP:          "78462F103",
+			Exchange:       "PCX",
+		}
+		assert.NoError(t, asset.Validate())
+	})
+
+	t.Run("InvalidMetadata", func(t *testing.T) {
+		var asset = Asset{
+			Ticker:         "ARCA:SPY",
+			InstrumentType: "FUND",
+			Currency:       "XYZ",
+			ISIN:           "US78462F1031",
+			CUSIP:          "78462F104",
+		}
+
+		var err = asset.Validate()
+
+		var validationError *infra.DomainValidationError
+		require.ErrorAs(t, err, &validationError)
+
+		var messages = make([]string, len(validationError.Causes))
+		for i, cause := range validationError.Causes {
+			messages[i] = cause.Message
+		}
+		assert.Equal(
+			t,
+			[]string{
+				"Invalid instrument type FUND",
+				"Invalid currency XYZ",
+				"Invalid ISIN US78462F1031",
+				"Invalid CUSIP 78462F104",
+			},
+			messages,
+		)
+	})
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"github.com/benizzio/open-asset-allocator/langext"
+)
 
 // AssetIntegrationService defines the contract for external asset providers used by the
 // domain layer. Implementations must return normalized assets for their source and must
@@ -17,4 +21,59 @@ type AssetIntegrationService interface {
 	// QuoteAssetLastClosePrice fetches the latest close quote for one normalized external asset.
 	// It returns the provider quote translated to the domain model or an error when quoting fails.
 	QuoteAssetLastClosePrice(asset *ExternalAsset) (*ExternalAssetQuote, error)
+
+	// QuoteAssetsLastClosePrice fetches the latest close quotes for several external assets of the
+	// provider source, with a concurrency bound suited to the provider. It returns one result per asset,
+	// in the given order, each carrying the quote or the error of that asset, so a failure does not
+	// discard the other quotes. The method must honor ctx cancellation.
+	QuoteAssetsLastClosePrice(ctx context.Context, assets []*ExternalAsset) []*ExternalAssetQuoteResult
+}
+
+// ExternalAssetQuoteResult is the outcome of quoting one external asset in a batch: either Quote or
+// Err is set.
+type ExternalAssetQuoteResult struct {
+	ExternalAsset *ExternalAsset
+	Quote         *ExternalAssetQuote
+	Err           error
+}
+
+// QuoteExternalAssetsConcurrently quotes each external asset with the given function, running at most
+// maxConcurrency quotes at the same time, and collects one result per asset in the given order. Assets
+// not yet quoted when the context is done get the context error as result.
+// It is the common implementation of AssetIntegrationService.QuoteAssetsLastClosePrice.
+//
+// Example:
+//
+//	func (service *ProviderAssetIntegrationService) QuoteAssetsLastClosePrice(
+//		ctx context.Context,
+//		assets []*domain.ExternalAsset,
+//	) []*domain.ExternalAssetQuoteResult {
+//		return domain.QuoteExternalAssetsConcurrently(ctx, assets, 4, service.quoteAssetLastClosePrice)
+//	}
+func QuoteExternalAssetsConcurrently(
+	ctx context.Context,
+	assets []*ExternalAsset,
+	maxConcurrency int,
+	quote func(context.Context, *ExternalAsset) (*ExternalAssetQuote, error),
+) []*ExternalAssetQuoteResult {
+
+	var quoteAsset = func(quoteContext context.Context, asset *ExternalAsset) *ExternalAssetQuoteResult {
+
+		if err := quoteContext.Err(); err != nil {
+			return &ExternalAssetQuoteResult{ExternalAsset: asset, Err: err}
+		}
+
+		var externalAssetQuote, err = quote(quoteContext, asset)
+		return &ExternalAssetQuoteResult{ExternalAsset: asset, Quote: externalAssetQuote, Err: err}
+	}
+
+	return langext.MapConcurrentlyBoundedCtx(ctx, assets, maxConcurrency, quoteAsset)
+}
+
+// AssetQuoteResult is the outcome of quoting one asset in a batch through its linked external assets:
+// either Quote or Err is set.
+type AssetQuoteResult struct {
+	Asset *Asset
+	Quote *ExternalAssetQuote
+	Err   error
 }
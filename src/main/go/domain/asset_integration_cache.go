@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AssetIntegrationCacheEntry is a cached response of an AssetIntegrationService operation, serialized
+// as JSON in Payload and identified by CacheKey.
+type AssetIntegrationCacheEntry struct {
+	CacheKey string
+	Payload  string
+	StoredAt time.Time
+}
+
+// AssetIntegrationCacheOperationMetrics counts how the requests of one cached operation were served.
+// Stale hits were served from an expired entry while it was refreshed in the background.
+type AssetIntegrationCacheOperationMetrics struct {
+	Hits          int64
+	StaleHits     int64
+	Misses        int64
+	RefreshErrors int64
+}
+
+// AssetIntegrationCacheMetrics are the cache metrics of the integration service of one source.
+type AssetIntegrationCacheMetrics struct {
+	Source AssetExternalSource
+	Search AssetIntegrationCacheOperationMetrics
+	Quote  AssetIntegrationCacheOperationMetrics
+}
+
+// AssetIntegrationCacheRepository stores the cached responses of AssetIntegrationService operations.
+type AssetIntegrationCacheRepository interface {
+
+	// FindCacheEntry returns the entry stored with the given key, or nil when there is none.
+	FindCacheEntry(ctx context.Context, cacheKey string) (*AssetIntegrationCacheEntry, error)
+
+	// MergeCacheEntry stores the entry, replacing any entry stored with the same key.
+	MergeCacheEntry(ctx context.Context, entry *AssetIntegrationCacheEntry) error
+
+	// DeleteCacheEntriesStoredBefore removes the entries stored before the given instant.
+	DeleteCacheEntriesStoredBefore(ctx context.Context, instant time.Time) error
+}
@@ -1,11 +1,35 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type AssetRepository interface {
 	GetKnownAssets() ([]*Asset, error)
 	FindAssetByUniqueIdentifier(uniqueIdentifier string) (*Asset, error)
-	UpdateAsset(asset *Asset) (*Asset, error)
+	FindAssetById(id int64) (*Asset, error)
+	FindAssetForUpdateInTransaction(transContext context.Context, id int64) (*Asset, error)
+	FindPortfolioAssets(portfolioId int64) ([]*Asset, error)
+	UpdateAssetInTransaction(transContext context.Context, asset *Asset) (*Asset, error)
+	UpdateAssetExternalDataInTransaction(transContext context.Context, asset *Asset) (*Asset, error)
 	InsertAssetsInTransaction(transContext context.Context, assets []*Asset) ([]*Asset, error)
 	FindAssetsByTickersInTransaction(transContext context.Context, tickers []string) ([]*Asset, error)
+	FindAssetsPerTickersInTransaction(
+		transContext context.Context,
+		tickers []string,
+		referenceDate *time.Time,
+	) (AssetsPerTicker, error)
+	FindAssetAliases(assetId int64) ([]*AssetAlias, error)
+	UpdateAssetTickerInTransaction(transContext context.Context, assetId int64, ticker string) error
+	InsertAssetAliasInTransaction(transContext context.Context, alias *AssetAlias) (*AssetAlias, error)
+	DeleteAssetAliasInTransaction(transContext context.Context, assetId int64, aliasId int64) (bool, error)
+	FindAssetValuations(assetId int64) ([]*AssetValuation, error)
+	FindLatestAssetValuation(assetId int64) (*AssetValuation, error)
+	MergeAssetValuationInTransaction(transContext context.Context, valuation *AssetValuation) (*AssetValuation, error)
+	DeleteAssetValuationInTransaction(transContext context.Context, assetId int64, valuationId int64) (bool, error)
+	FindAssetEvents(assetId int64) ([]*AssetEvent, error)
+	FindAssetEventsPerAssetId(assetIds []int64) (map[int64][]*AssetEvent, error)
+	MergeAssetEvents(assetId int64, events []*AssetEvent) ([]*AssetEvent, error)
+	FindPortfolioDividendIncome(portfolioId int64) ([]*AssetIncomeRecord, error)
 }
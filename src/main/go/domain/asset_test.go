@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillMetadataFromExternalDataInPriorityOrder(t *testing.T) {
+
+	var asset = &Asset{
+		Ticker: "ARCA:IAU",
+		ExternalData: &ExternalAssetData{
+			Data: []ExternalAsset{
+				BuildManualExternalAsset(1),
+				{Source: YahooFinanceSource, Ticker: "IAU", ExchangeId: "PCX"},
+				{Source: YahooFinanceSource, Ticker: "IAU.L", ExchangeId: "LSE", InstrumentType: ETFInstrumentType},
+			},
+		},
+	}
+
+	asset.FillMetadataFromExternalData()
+
+	assert.Equal(t, "PCX", asset.Exchange)
+	assert.Equal(t, ETFInstrumentType, asset.InstrumentType)
+}
+
+func TestFillMetadataFromExternalDataKeepsExistingMetadata(t *testing.T) {
+
+	var asset = &Asset{
+		Ticker:         "ARCA:IAU",
+		InstrumentType: CommodityInstrumentType,
+		Exchange:       "NYSE",
+		ExternalData: &ExternalAssetData{
+			Data: []ExternalAsset{
+				{Source: YahooFinanceSource, Ticker: "IAU", ExchangeId: "PCX", InstrumentType: ETFInstrumentType},
+			},
+		},
+	}
+
+	asset.FillMetadataFromExternalData()
+
+	assert.Equal(t, "NYSE", asset.Exchange)
+	assert.Equal(t, CommodityInstrumentType, asset.InstrumentType)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// ManualExchangeId is the exchange identifier of the external assets of the MANUAL source, which
+// reference the asset itself by id.
+const ManualExchangeId = "MANUAL"
+
+// AssetValuation is a user-entered price point of an asset, used to quote assets without market
+// quotes through the MANUAL external source. There is at most one valuation per asset and date.
+type AssetValuation struct {
+	Id            int64
+	AssetId       int64
+	ValuationDate time.Time
+	Price         decimal.Decimal
+	Currency      string
+}
+
+// Validate checks the valuation invariants that cannot be expressed through request validation.
+//
+// Returns:
+//   - error: a DomainValidationError when the price is negative or the currency is not an ISO 4217
+//     code, nil otherwise
+func (valuation *AssetValuation) Validate() error {
+
+	var validationErrors = make([]*infra.AppError, 0)
+
+	if valuation.Price.IsNegative() {
+		validationErrors = append(
+			validationErrors,
+			infra.BuildAppErrorFormattedUnconverted(
+				valuation,
+				"Asset valuation price %s must not be negative",
+				valuation.Price.String(),
+			),
+		)
+	}
+
+	if _, err := currency.ParseISO(valuation.Currency); err != nil {
+		validationErrors = append(
+			validationErrors,
+			infra.BuildAppErrorFormattedUnconverted(valuation, "Invalid currency %s", valuation.Currency),
+		)
+	}
+
+	if len(validationErrors) > 0 {
+		return infra.BuildDomainValidationError("Asset valuation validation failed", validationErrors)
+	}
+
+	return nil
+}
+
+// BuildManualExternalAsset builds the MANUAL external asset that quotes an asset through its
+// recorded valuations.
+func BuildManualExternalAsset(assetId int64) ExternalAsset {
+	return ExternalAsset{
+		Source:     ManualSource,
+		Ticker:     strconv.FormatInt(assetId, 10),
+		ExchangeId: ManualExchangeId,
+	}
+}
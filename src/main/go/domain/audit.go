@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type AuditEntityType string
+
+const (
+	PortfolioAuditEntityType            AuditEntityType = "PORTFOLIO"
+	PortfolioObservationAuditEntityType AuditEntityType = "PORTFOLIO_OBSERVATION"
+	// PortfolioAllocationAuditEntityType entries record the allocation facts of a portfolio in one
+	// observation as a whole, identified by the observation id.
+	PortfolioAllocationAuditEntityType AuditEntityType = "PORTFOLIO_ALLOCATION"
+	AllocationPlanAuditEntityType      AuditEntityType = "ALLOCATION_PLAN"
+	AssetAuditEntityType               AuditEntityType = "ASSET"
+)
+
+// IsValid reports whether the entity type is one of the audited entity types.
+func (entityType AuditEntityType) IsValid() bool {
+	switch entityType {
+	case PortfolioAuditEntityType,
+		PortfolioObservationAuditEntityType,
+		PortfolioAllocationAuditEntityType,
+		AllocationPlanAuditEntityType,
+		AssetAuditEntityType:
+		return true
+	}
+	return false
+}
+
+type AuditAction string
+
+const (
+	CreateAuditAction AuditAction = "CREATE"
+	UpdateAuditAction AuditAction = "UPDATE"
+	DeleteAuditAction AuditAction = "DELETE"
+)
+
+// AuditEntityReference identifies an audited entity. PortfolioId is set for entities belonging to a
+// portfolio.
+type AuditEntityReference struct {
+	EntityType  AuditEntityType
+	EntityId    int64
+	PortfolioId *int64
+}
+
+// AuditActor is the user performing audited changes.
+type AuditActor struct {
+	UserId   int64
+	Username string
+}
+
+// AuditEntry records one change of an audited entity, with JSON snapshots of the entity before and after
+// the change. Before is nil for creations and After for deletions. The actor is nil for changes made
+// while authentication is disabled or by the application itself.
+type AuditEntry struct {
+	Id int64
+	AuditEntityReference
+	Action        AuditAction
+	ActorUserId   *int64
+	ActorUsername *string
+	Before        json.RawMessage
+	After         json.RawMessage
+	CreatedAt     time.Time
+}
+
+// AuditFilter filters the audit entries, newest first. AccessibleByUserId restricts the entries of
+// portfolios to the ones the user can access.
+type AuditFilter struct {
+	EntityType         AuditEntityType
+	EntityId           *int64
+	PortfolioId        *int64
+	ActorUserId        *int64
+	From               *time.Time
+	To                 *time.Time
+	AccessibleByUserId *int64
+	Limit              int
+}
+
+type auditActorContextKey struct{}
+
+// WithAuditActor returns a copy of the context carrying the actor of the audited changes made with it.
+func WithAuditActor(parentContext context.Context, actor *AuditActor) context.Context {
+	return context.WithValue(parentContext, auditActorContextKey{}, actor)
+}
+
+// AuditActorFromContext returns the actor carried by the context, or nil when there is none.
+func AuditActorFromContext(actorContext context.Context) *AuditActor {
+	var actor, _ = actorContext.Value(auditActorContextKey{}).(*AuditActor)
+	return actor
+}
+
+type AuditRepository interface {
+	SnapshotEntityInTransaction(transContext context.Context, reference *AuditEntityReference) (json.RawMessage, error)
+	InsertAuditEntryInTransaction(transContext context.Context, entry *AuditEntry) error
+	FindAuditEntries(filter *AuditFilter) ([]*AuditEntry, error)
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"time"
+)
+
+// User is a local user account, authenticated by its username and bcrypt hashed password.
+type User struct {
+	Id           int64
+	Username     string
+	PasswordHash string
+	CreatedAt    time.Time
+}
+
+// UserSession is a web UI session of a user, identified by the SHA-256 hash of the token kept in the
+// session cookie.
+type UserSession struct {
+	Id        int64
+	UserId    int64
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type APITokenScope string
+
+const (
+	// ReadAPITokenScope tokens can only read data, through the safe HTTP methods.
+	ReadAPITokenScope APITokenScope = "READ"
+	// WriteAPITokenScope tokens can also change data.
+	WriteAPITokenScope APITokenScope = "WRITE"
+)
+
+// IsValid reports whether the scope is one of the API token scopes.
+func (scope APITokenScope) IsValid() bool {
+	switch scope {
+	case ReadAPITokenScope, WriteAPITokenScope:
+		return true
+	}
+	return false
+}
+
+// APIToken is a personal API token of a user for scripts, identified by the SHA-256 hash of the token.
+// A token is active until it expires at ExpiresAt, when set, or is revoked.
+type APIToken struct {
+	Id         int64
+	UserId     int64
+	Name       string
+	TokenHash  string
+	Scopes     []APITokenScope
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// HasScope reports whether the token was granted the scope.
+func (token *APIToken) HasScope(scope APITokenScope) bool {
+	for _, tokenScope := range token.Scopes {
+		if tokenScope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Principal is the authenticated caller of a request, a user through a web UI session or an API token, or
+// an anonymous holder of a portfolio share link. APIToken is nil for sessions, and User is nil for share
+// links.
+type Principal struct {
+	User      *User
+	APIToken  *APIToken
+	ShareLink *PortfolioShareLink
+}
+
+// IsSession reports whether the principal was authenticated through a web UI session.
+func (principal *Principal) IsSession() bool {
+	return principal.User != nil && principal.APIToken == nil
+}
+
+// IsShareLink reports whether the principal was authenticated through a portfolio share link.
+func (principal *Principal) IsShareLink() bool {
+	return principal.ShareLink != nil
+}
+
+// CanWrite reports whether the principal can change data. Sessions can, API tokens only with the WRITE
+// scope and share links never.
+func (principal *Principal) CanWrite() bool {
+	if principal.IsShareLink() {
+		return false
+	}
+	return principal.IsSession() || principal.APIToken.HasScope(WriteAPITokenScope)
+}
+
+type AuthRepository interface {
+	CountUsers() (int64, error)
+	FindUser(userId int64) (*User, error)
+	FindUserByUsername(username string) (*User, error)
+	InsertUser(user *User) (*User, error)
+	UpdateUserPassword(userId int64, passwordHash string) error
+	InsertSession(session *UserSession) error
+	FindActiveSessionUser(tokenHash string, now time.Time) (*User, error)
+	DeleteSession(tokenHash string) error
+	DeleteUserSessions(userId int64) error
+	DeleteExpiredSessions(now time.Time) error
+	InsertAPIToken(token *APIToken) (*APIToken, error)
+	FindUserAPITokens(userId int64) ([]*APIToken, error)
+	FindActiveAPIToken(tokenHash string, now time.Time) (*APIToken, error)
+	UpdateAPITokenLastUsed(tokenId int64, lastUsedAt time.Time) error
+	RevokeAPIToken(userId int64, tokenId int64, revokedAt time.Time) (bool, error)
+}
@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms/sqlext"
+)
+
+type CorporateActionType string
+
+const (
+	SplitCorporateActionType        CorporateActionType = "SPLIT"
+	ReverseSplitCorporateActionType CorporateActionType = "REVERSE_SPLIT"
+	SymbolChangeCorporateActionType CorporateActionType = "SYMBOL_CHANGE"
+	MergerCorporateActionType       CorporateActionType = "MERGER"
+)
+
+type CorporateActionStatus string
+
+const (
+	PendingCorporateActionStatus  CorporateActionStatus = "PENDING"
+	AppliedCorporateActionStatus  CorporateActionStatus = "APPLIED"
+	RevertedCorporateActionStatus CorporateActionStatus = "REVERTED"
+)
+
+// CorporateAction is a split, reverse split, symbol change or merger of an asset, effective from
+// EffectiveDate. The terms depend on the action type:
+//   - SPLIT and REVERSE_SPLIT: each share becomes RatioNumerator / RatioDenominator shares
+//   - SYMBOL_CHANGE: the asset ticker becomes NewTicker
+//   - MERGER: each share becomes RatioNumerator / RatioDenominator shares of the TargetAssetId asset, plus
+//     CashPerShare in CashCurrency when the merger has cash terms
+//
+// Applying an action adjusts the portfolio observations and planned allocations from the effective date
+// on, and the ticker and aliases of the assets, keeping in Reversal what is needed to revert it.
+type CorporateAction struct {
+	Id               int64
+	AssetId          int64
+	ActionType       CorporateActionType
+	EffectiveDate    time.Time
+	RatioNumerator   decimal.Decimal
+	RatioDenominator decimal.Decimal
+	NewTicker        string
+	TargetAssetId    int64
+	CashPerShare     decimal.Decimal
+	CashCurrency     string
+	Status           CorporateActionStatus
+	AppliedAt        *time.Time
+	Reversal         *CorporateActionReversal
+}
+
+// Ratio returns the number of shares each share becomes through the action.
+func (action *CorporateAction) Ratio() decimal.Decimal {
+	return action.RatioNumerator.Div(action.RatioDenominator)
+}
+
+// AffectsPortfolioAllocations informs if applying the action changes the quantities or assets of the
+// observed portfolio allocations.
+func (action *CorporateAction) AffectsPortfolioAllocations() bool {
+	return action.ActionType != SymbolChangeCorporateActionType
+}
+
+// Validate checks the action terms required by its type.
+//
+// Returns:
+//   - error: a DomainValidationError listing every invalid term, nil otherwise
+func (action *CorporateAction) Validate() error {
+
+	var validationErrors = make([]*infra.AppError, 0)
+	var addError = func(format string, args ...any) {
+		validationErrors = append(validationErrors, infra.BuildAppErrorFormattedUnconverted(action, format, args...))
+	}
+
+	switch action.ActionType {
+	case SplitCorporateActionType, ReverseSplitCorporateActionType, MergerCorporateActionType:
+		if !action.RatioNumerator.IsPositive() || !action.RatioDenominator.IsPositive() {
+			addError("Corporate action %s requires a positive ratio numerator and denominator", action.ActionType)
+			break
+		}
+		if action.ActionType == SplitCorporateActionType &&
+			action.RatioNumerator.LessThanOrEqual(action.RatioDenominator) {
+			addError("Split ratio numerator must be greater than its denominator")
+		}
+		if action.ActionType == ReverseSplitCorporateActionType &&
+			action.RatioNumerator.GreaterThanOrEqual(action.RatioDenominator) {
+			addError("Reverse split ratio numerator must be less than its denominator")
+		}
+	case SymbolChangeCorporateActionType:
+		if action.NewTicker == "" {
+			addError("Symbol change requires the new ticker")
+		}
+	default:
+		addError("Invalid corporate action type %s", action.ActionType)
+	}
+
+	if action.ActionType == MergerCorporateActionType {
+		if action.TargetAssetId == 0 {
+			addError("Merger requires the target asset")
+		} else if action.TargetAssetId == action.AssetId {
+			addError("Merger target asset must be different from the merged asset")
+		}
+		if action.CashPerShare.IsNegative() {
+			addError("Merger cash per share %s must not be negative", action.CashPerShare.String())
+		}
+		if action.CashPerShare.IsPositive() {
+			if _, err := currency.ParseISO(action.CashCurrency); err != nil {
+				addError("Invalid merger cash currency %s", action.CashCurrency)
+			}
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return infra.BuildDomainValidationError("Corporate action validation failed", validationErrors)
+	}
+
+	return nil
+}
+
+// ValidateApplicable checks that the action can be applied, which requires it to be pending or reverted.
+func (action *CorporateAction) ValidateApplicable() error {
+	if action.Status == AppliedCorporateActionStatus {
+		return infra.BuildDomainValidationError(
+			fmt.Sprintf("Corporate action %d is already applied", action.Id),
+			nil,
+		)
+	}
+	return nil
+}
+
+// ValidateRevertible checks that the action can be reverted, which requires it to be applied and to be
+// the last applied action of the assets it changed, so reverting restores the state it found.
+//
+// Parameters:
+//   - laterAppliedActions: the actions applied after this one involving the same assets
+func (action *CorporateAction) ValidateRevertible(laterAppliedActions []*CorporateAction) error {
+
+	if action.Status != AppliedCorporateActionStatus || action.Reversal == nil {
+		return infra.BuildDomainValidationError(
+			fmt.Sprintf("Corporate action %d is not applied", action.Id),
+			nil,
+		)
+	}
+
+	if len(laterAppliedActions) > 0 {
+		return infra.BuildDomainValidationError(
+			fmt.Sprintf(
+				"Corporate action %d must be reverted before corporate action %d",
+				laterAppliedActions[0].Id,
+				action.Id,
+			),
+			nil,
+		)
+	}
+
+	return nil
+}
+
+// InvolvedAssetIds returns the ids of the assets the action changes.
+func (action *CorporateAction) InvolvedAssetIds() []int64 {
+	if action.ActionType == MergerCorporateActionType {
+		return []int64{action.AssetId, action.TargetAssetId}
+	}
+	return []int64{action.AssetId}
+}
+
+// CorporateActionReversal keeps the state changed by applying a corporate action, restored when the
+// action is reverted.
+type CorporateActionReversal struct {
+	PortfolioAllocations []*CorporateActionPortfolioAllocationSnapshot `json:"portfolioAllocations"`
+	PlannedAllocations   []*CorporateActionPlannedAllocationSnapshot   `json:"plannedAllocations"`
+	PreviousTicker       string                                        `json:"previousTicker,omitempty"`
+	CreatedAliasId       int64                                         `json:"createdAliasId,omitempty"`
+}
+
+func (reversal *CorporateActionReversal) Scan(value interface{}) error {
+	return sqlext.ScanJsonColumn(value, reversal)
+}
+
+func (reversal CorporateActionReversal) Value() (driver.Value, error) {
+	return sqlext.ValueJsonColumn(reversal)
+}
+
+// CorporateActionPortfolioAllocationSnapshot is an observed portfolio allocation as it was before a
+// corporate action was applied.
+type CorporateActionPortfolioAllocationSnapshot struct {
+	PortfolioId       int64               `json:"portfolio_id"`
+	ObservationTimeId int64               `json:"observation_time_id"`
+	AssetId           int64               `json:"asset_id"`
+	Class             string              `json:"class"`
+	CashReserve       bool                `json:"cash_reserve"`
+	AssetQuantity     decimal.NullDecimal `json:"asset_quantity"`
+	AssetMarketPrice  decimal.NullDecimal `json:"asset_market_price"`
+	TotalMarketValue  int64               `json:"total_market_value"`
+}
+
+// CorporateActionPlannedAllocationSnapshot is the asset reference of a planned allocation as it was
+// before a corporate action was applied, with the plan and portfolio it belongs to.
+type CorporateActionPlannedAllocationSnapshot struct {
+	Id               int64          `json:"id"`
+	AllocationPlanId int64          `json:"allocation_plan_id,omitempty"`
+	PortfolioId      int64          `json:"portfolio_id,omitempty"`
+	AssetId          int64          `json:"asset_id"`
+	HierarchicalId   HierarchicalId `json:"hierarchical_id"`
+}
+
+type CorporateActionRepository interface {
+	FindAssetCorporateActions(assetId int64) ([]*CorporateAction, error)
+	FindCorporateAction(id int64) (*CorporateAction, error)
+	InsertCorporateAction(action *CorporateAction) (*CorporateAction, error)
+	DeleteCorporateAction(action *CorporateAction) error
+	FindCorporateActionForUpdateInTransaction(transContext context.Context, id int64) (*CorporateAction, error)
+	FindCorporateActionsAppliedAfterInTransaction(
+		transContext context.Context,
+		action *CorporateAction,
+	) ([]*CorporateAction, error)
+	UpdateCorporateActionStatusInTransaction(transContext context.Context, action *CorporateAction) error
+	FindLaterPortfolioAllocationsInTransaction(
+		transContext context.Context,
+		action *CorporateAction,
+	) ([]*CorporateActionPortfolioAllocationSnapshot, error)
+	AdjustLaterPortfolioAllocationsInTransaction(transContext context.Context, action *CorporateAction) error
+	RestorePortfolioAllocationsInTransaction(
+		transContext context.Context,
+		action *CorporateAction,
+		snapshots []*CorporateActionPortfolioAllocationSnapshot,
+	) error
+	FindLaterPlannedAllocationsInTransaction(
+		transContext context.Context,
+		action *CorporateAction,
+	) ([]*CorporateActionPlannedAllocationSnapshot, error)
+	ReassignLaterPlannedAllocationsInTransaction(
+		transContext context.Context,
+		action *CorporateAction,
+		previousTicker string,
+		newAsset *Asset,
+	) error
+	RestorePlannedAllocationsInTransaction(
+		transContext context.Context,
+		snapshots []*CorporateActionPlannedAllocationSnapshot,
+	) error
+}
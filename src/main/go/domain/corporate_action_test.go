@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+func TestCorporateActionValidate(t *testing.T) {
+
+	var testCases = []struct {
+		name           string
+		action         CorporateAction
+		expectedDetail string
+	}{
+		{
+			name: "valid split",
+			action: CorporateAction{
+				ActionType:       SplitCorporateActionType,
+				RatioNumerator:   decimal.NewFromInt(4),
+				RatioDenominator: decimal.NewFromInt(1),
+			},
+		},
+		{
+			name: "split with ratio below one",
+			action: CorporateAction{
+				ActionType:       SplitCorporateActionType,
+				RatioNumerator:   decimal.NewFromInt(1),
+				RatioDenominator: decimal.NewFromInt(4),
+			},
+			expectedDetail: "Split ratio numerator must be greater than its denominator",
+		},
+		{
+			name: "reverse split without ratio",
+			action: CorporateAction{
+				ActionType: ReverseSplitCorporateActionType,
+			},
+			expectedDetail: "Corporate action REVERSE_SPLIT requires a positive ratio numerator and denominator",
+		},
+		{
+			name:           "symbol change without new ticker",
+			action:         CorporateAction{ActionType: SymbolChangeCorporateActionType},
+			expectedDetail: "Symbol change requires the new ticker",
+		},
+		{
+			name: "merger into the same asset",
+			action: CorporateAction{
+				AssetId:          1,
+				ActionType:       MergerCorporateActionType,
+				RatioNumerator:   decimal.NewFromInt(1),
+				RatioDenominator: decimal.NewFromInt(2),
+				TargetAssetId:    1,
+			},
+			expectedDetail: "Merger target asset must be different from the merged asset",
+		},
+		{
+			name: "merger with cash in invalid currency",
+			action: CorporateAction{
+				AssetId:          1,
+				ActionType:       MergerCorporateActionType,
+				RatioNumerator:   decimal.NewFromInt(1),
+				RatioDenominator: decimal.NewFromInt(2),
+				TargetAssetId:    2,
+				CashPerShare:     decimal.NewFromInt(5),
+				CashCurrency:     "XX",
+			},
+			expectedDetail: "Invalid merger cash currency XX",
+		},
+		{
+			name:           "unknown type",
+			action:         CorporateAction{ActionType: "SPINOFF"},
+			expectedDetail: "Invalid corporate action type SPINOFF",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			var err = testCase.action.Validate()
+
+			if testCase.expectedDetail == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *infra.DomainValidationError
+			require.True(t, errors.As(err, &validationErr), err)
+			require.Len(t, validationErr.Causes, 1)
+			assert.Equal(t, testCase.expectedDetail, validationErr.Causes[0].Message)
+		})
+	}
+}
+
+func TestCorporateActionValidateRevertible(t *testing.T) {
+
+	var appliedAt = time.Now()
+	var appliedAction = &CorporateAction{
+		Id:        1,
+		Status:    AppliedCorporateActionStatus,
+		AppliedAt: &appliedAt,
+		Reversal:  &CorporateActionReversal{},
+	}
+
+	assert.NoError(t, appliedAction.ValidateRevertible(nil))
+	assert.EqualError(
+		t,
+		appliedAction.ValidateRevertible([]*CorporateAction{{Id: 2}}),
+		"Corporate action 2 must be reverted before corporate action 1",
+	)
+	assert.EqualError(
+		t,
+		(&CorporateAction{Id: 3, Status: PendingCorporateActionStatus}).ValidateRevertible(nil),
+		"Corporate action 3 is not applied",
+	)
+}
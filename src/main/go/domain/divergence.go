@@ -35,7 +35,9 @@ type DivergenceAnalysis struct {
 	ObservationTimestamp      *PortfolioObservationTimestamp
 	AllocationPlanId          int64
 	PortfolioTotalMarketValue int64
-	Root                      []*PotentialDivergence
+	// CashReserveTotalMarketValue is the part of the portfolio total market value allocated as cash reserve.
+	CashReserveTotalMarketValue int64
+	Root                        []*PotentialDivergence
 }
 
 func (analysis *DivergenceAnalysis) AddRootDivergence(rootDivergence *PotentialDivergence) {
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type DivergenceAlertRuleType string
+
+const (
+	// DriftAboveAlertRuleType rules trigger when the allocation of a point of the hierarchy drifts from the
+	// plan by more than the threshold, in percentage points of its parent level.
+	DriftAboveAlertRuleType DivergenceAlertRuleType = "DRIFT_ABOVE"
+	// CashReserveBelowAlertRuleType rules trigger when the cash reserve allocations are below the threshold
+	// percentage of the portfolio total market value.
+	CashReserveBelowAlertRuleType DivergenceAlertRuleType = "CASH_RESERVE_BELOW"
+)
+
+// IsValid reports whether the rule type is one of the supported alert rule types.
+func (ruleType DivergenceAlertRuleType) IsValid() bool {
+	switch ruleType {
+	case DriftAboveAlertRuleType, CashReserveBelowAlertRuleType:
+		return true
+	}
+	return false
+}
+
+// DivergenceAlertRule is a condition on the divergence of a portfolio from an allocation plan, evaluated
+// whenever an observation is merged or prices are refreshed. A DRIFT_ABOVE rule watches every top level
+// point of the hierarchy, or only the one with HierarchicalId when set.
+type DivergenceAlertRule struct {
+	Id               int64
+	PortfolioId      int64
+	AllocationPlanId int64
+	Name             string
+	RuleType         DivergenceAlertRuleType
+	HierarchicalId   *string
+	ThresholdPercent decimal.Decimal
+	Enabled          bool
+	CreatedAt        time.Time
+}
+
+// DivergenceAlertBreach is a point where the evaluation of a rule meets its condition. HierarchicalId is
+// nil for rules on the whole portfolio.
+type DivergenceAlertBreach struct {
+	HierarchicalId  *string
+	ObservedPercent decimal.Decimal
+}
+
+// Evaluate returns the breaches of the rule in a divergence analysis of its allocation plan. An analysis
+// without market value has no breaches.
+func (rule *DivergenceAlertRule) Evaluate(analysis *DivergenceAnalysis) []*DivergenceAlertBreach {
+
+	var breaches = make([]*DivergenceAlertBreach, 0)
+	if analysis.PortfolioTotalMarketValue == 0 {
+		return breaches
+	}
+
+	switch rule.RuleType {
+	case DriftAboveAlertRuleType:
+		rule.collectDriftBreaches(analysis.Root, analysis.PortfolioTotalMarketValue, &breaches)
+	case CashReserveBelowAlertRuleType:
+		var cashReservePercent = percentOf(analysis.CashReserveTotalMarketValue, analysis.PortfolioTotalMarketValue)
+		if cashReservePercent.LessThan(rule.ThresholdPercent) {
+			breaches = append(breaches, &DivergenceAlertBreach{ObservedPercent: cashReservePercent})
+		}
+	}
+
+	return breaches
+}
+
+// collectDriftBreaches walks the divergences of a hierarchy level, whose total market value is
+// levelTotalMarketValue, descending into the lower levels only when the rule targets a hierarchical id.
+func (rule *DivergenceAlertRule) collectDriftBreaches(
+	divergences []*PotentialDivergence,
+	levelTotalMarketValue int64,
+	breaches *[]*DivergenceAlertBreach,
+) {
+
+	if levelTotalMarketValue == 0 {
+		return
+	}
+
+	for _, divergence := range divergences {
+
+		if rule.HierarchicalId != nil && *rule.HierarchicalId != divergence.HierarchicalId {
+			rule.collectDriftBreaches(divergence.InternalDivergences, divergence.TotalMarketValue, breaches)
+			continue
+		}
+
+		var driftPercent = percentOf(divergence.TotalMarketValueDivergence, levelTotalMarketValue)
+		if driftPercent.Abs().GreaterThan(rule.ThresholdPercent) {
+			var hierarchicalId = divergence.HierarchicalId
+			*breaches = append(
+				*breaches,
+				&DivergenceAlertBreach{HierarchicalId: &hierarchicalId, ObservedPercent: driftPercent},
+			)
+		}
+	}
+}
+
+func percentOf(value int64, total int64) decimal.Decimal {
+	return decimal.NewFromInt(value).
+		Div(decimal.NewFromInt(total)).
+		Mul(decimal.NewFromInt(100)).
+		Round(2)
+}
+
+type DivergenceAlertStatus string
+
+const (
+	TriggeredDivergenceAlertStatus    DivergenceAlertStatus = "TRIGGERED"
+	AcknowledgedDivergenceAlertStatus DivergenceAlertStatus = "ACKNOWLEDGED"
+	ResolvedDivergenceAlertStatus     DivergenceAlertStatus = "RESOLVED"
+)
+
+// IsValid reports whether the status is one of the divergence alert statuses.
+func (status DivergenceAlertStatus) IsValid() bool {
+	switch status {
+	case TriggeredDivergenceAlertStatus, AcknowledgedDivergenceAlertStatus, ResolvedDivergenceAlertStatus:
+		return true
+	}
+	return false
+}
+
+// DivergenceAlert is a breach of a rule, open while TRIGGERED or ACKNOWLEDGED. The evaluations following
+// the trigger refresh the observed percentage of an open alert, and resolve it once the breach is over.
+// There is at most one open alert per rule and hierarchical id.
+type DivergenceAlert struct {
+	Id                     int64
+	RuleId                 int64
+	RuleName               string
+	RuleType               DivergenceAlertRuleType
+	PortfolioId            int64
+	HierarchicalId         *string
+	Status                 DivergenceAlertStatus
+	ThresholdPercent       decimal.Decimal
+	ObservedPercent        decimal.Decimal
+	ObservationTimestampId int64
+	TriggeredAt            time.Time
+	LastEvaluatedAt        time.Time
+	AcknowledgedAt         *time.Time
+	ResolvedAt             *time.Time
+}
+
+// IsOpen reports whether the alert is not resolved yet.
+func (alert *DivergenceAlert) IsOpen() bool {
+	return alert.Status != ResolvedDivergenceAlertStatus
+}
+
+type DivergenceAlertRepository interface {
+	FindPortfolioAlertRules(portfolioId int64) ([]*DivergenceAlertRule, error)
+	FindPortfolioAlertRule(portfolioId int64, ruleId int64) (*DivergenceAlertRule, error)
+	FindEnabledPortfolioAlertRules(portfolioId int64) ([]*DivergenceAlertRule, error)
+	InsertAlertRule(rule *DivergenceAlertRule) (*DivergenceAlertRule, error)
+	UpdateAlertRule(rule *DivergenceAlertRule) (*DivergenceAlertRule, error)
+	DeleteAlertRule(portfolioId int64, ruleId int64) (bool, error)
+	FindOpenRuleAlertsInTransaction(transContext context.Context, ruleId int64) ([]*DivergenceAlert, error)
+	InsertAlertInTransaction(transContext context.Context, alert *DivergenceAlert) error
+	UpdateAlertEvaluationInTransaction(transContext context.Context, alert *DivergenceAlert) error
+	FindPortfolioAlerts(portfolioId int64, status *DivergenceAlertStatus, limit int) ([]*DivergenceAlert, error)
+	FindPortfolioAlert(portfolioId int64, alertId int64) (*DivergenceAlert, error)
+	UpdateAlertStatus(alert *DivergenceAlert) (*DivergenceAlert, error)
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestAlertDivergenceAnalysis() *DivergenceAnalysis {
+	return &DivergenceAnalysis{
+		PortfolioTotalMarketValue:   10000,
+		CashReserveTotalMarketValue: 500,
+		Root: []*PotentialDivergence{
+			{
+				HierarchyLevelKey:          "STOCKS",
+				HierarchicalId:             "STOCKS",
+				TotalMarketValue:           7000,
+				TotalMarketValueDivergence: 1000,
+				InternalDivergences: []*PotentialDivergence{
+					{
+						HierarchyLevelKey:          "ARCA:SPY",
+						HierarchicalId:             "ARCA:SPY|STOCKS",
+						TotalMarketValue:           5000,
+						TotalMarketValueDivergence: 800,
+					},
+					{
+						HierarchyLevelKey:          "NASDAQ:QQQ",
+						HierarchicalId:             "NASDAQ:QQQ|STOCKS",
+						TotalMarketValue:           2000,
+						TotalMarketValueDivergence: 200,
+					},
+				},
+			},
+			{
+				HierarchyLevelKey:          "BONDS",
+				HierarchicalId:             "BONDS",
+				TotalMarketValue:           3000,
+				TotalMarketValueDivergence: -1000,
+			},
+		},
+	}
+}
+
+func TestDivergenceAlertRuleEvaluateDriftAboveOnTopLevel(t *testing.T) {
+
+	var rule = DivergenceAlertRule{RuleType: DriftAboveAlertRuleType, ThresholdPercent: decimal.NewFromInt(5)}
+
+	var breaches = rule.Evaluate(buildTestAlertDivergenceAnalysis())
+
+	require.Len(t, breaches, 2)
+	assert.Equal(t, "STOCKS", *breaches[0].HierarchicalId)
+	assert.True(t, decimal.NewFromInt(10).Equal(breaches[0].ObservedPercent))
+	assert.Equal(t, "BONDS", *breaches[1].HierarchicalId)
+	assert.True(t, decimal.NewFromInt(-10).Equal(breaches[1].ObservedPercent))
+}
+
+func TestDivergenceAlertRuleEvaluateDriftAboveOnHierarchicalId(t *testing.T) {
+
+	var hierarchicalId = "ARCA:SPY|STOCKS"
+	var rule = DivergenceAlertRule{
+		RuleType:         DriftAboveAlertRuleType,
+		HierarchicalId:   &hierarchicalId,
+		ThresholdPercent: decimal.NewFromInt(10),
+	}
+
+	var breaches = rule.Evaluate(buildTestAlertDivergenceAnalysis())
+
+	require.Len(t, breaches, 1)
+	assert.Equal(t, hierarchicalId, *breaches[0].HierarchicalId)
+	assert.True(t, decimal.RequireFromString("11.43").Equal(breaches[0].ObservedPercent))
+
+	rule.ThresholdPercent = decimal.NewFromInt(12)
+	assert.Empty(t, rule.Evaluate(buildTestAlertDivergenceAnalysis()))
+}
+
+func TestDivergenceAlertRuleEvaluateCashReserveBelow(t *testing.T) {
+
+	var rule = DivergenceAlertRule{RuleType: CashReserveBelowAlertRuleType, ThresholdPercent: decimal.NewFromInt(10)}
+
+	var breaches = rule.Evaluate(buildTestAlertDivergenceAnalysis())
+
+	require.Len(t, breaches, 1)
+	assert.Nil(t, breaches[0].HierarchicalId)
+	assert.True(t, decimal.NewFromInt(5).Equal(breaches[0].ObservedPercent))
+
+	rule.ThresholdPercent = decimal.NewFromInt(5)
+	assert.Empty(t, rule.Evaluate(buildTestAlertDivergenceAnalysis()))
+}
+
+func TestDivergenceAlertRuleEvaluateWithoutMarketValue(t *testing.T) {
+
+	var rule = DivergenceAlertRule{RuleType: CashReserveBelowAlertRuleType, ThresholdPercent: decimal.NewFromInt(10)}
+
+	assert.Empty(t, rule.Evaluate(&DivergenceAnalysis{}))
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+type IdempotentRequestStatus string
+
+const (
+	// ProcessingIdempotentRequestStatus is the status of a request still being processed, whose duplicates
+	// are rejected until it is completed.
+	ProcessingIdempotentRequestStatus IdempotentRequestStatus = "PROCESSING"
+	// CompletedIdempotentRequestStatus is the status of a processed request, whose response is replayed to
+	// its duplicates.
+	CompletedIdempotentRequestStatus IdempotentRequestStatus = "COMPLETED"
+)
+
+// IdempotentResponse is the response of an idempotent request, replayed to its duplicates.
+type IdempotentResponse struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       []byte
+}
+
+// IdempotentRequest is a request sent with an idempotency key, unique in the scope of the caller until
+// ExpiresAt. Duplicates are recognized by the key and must have the same Fingerprint, a hash of the request.
+type IdempotentRequest struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	Status      IdempotentRequestStatus
+	Response    *IdempotentResponse
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// IsCompleted reports whether the request was processed and has a response to replay.
+func (request *IdempotentRequest) IsCompleted() bool {
+	return request.Status == CompletedIdempotentRequestStatus && request.Response != nil
+}
+
+type IdempotencyRepository interface {
+
+	// ClaimIdempotentRequest stores the request as processing, unless a request with the same scope and key
+	// is stored and not expired at its CreatedAt. Returns whether the request was stored.
+	ClaimIdempotentRequest(request *IdempotentRequest) (bool, error)
+
+	// FindIdempotentRequest returns the request stored with the scope and key, or nil when there is none.
+	FindIdempotentRequest(scope string, key string) (*IdempotentRequest, error)
+
+	// CompleteIdempotentRequest stores the response of a processing request.
+	CompleteIdempotentRequest(scope string, key string, response *IdempotentResponse) error
+
+	// DeleteIdempotentRequest removes the request stored with the scope and key.
+	DeleteIdempotentRequest(scope string, key string) error
+
+	// DeleteExpiredIdempotentRequests removes the requests expired at the given instant.
+	DeleteExpiredIdempotentRequests(now time.Time) error
+}
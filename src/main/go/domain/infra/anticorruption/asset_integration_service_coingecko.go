@@ -0,0 +1,258 @@
+package anticorruption
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/infra/integration"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const coinGeckoSearchResultsLimit = 5
+
+// coinGeckoQuoteConcurrency bounds the concurrent market chart requests of a batch quote, as the public
+// CoinGecko API allows few calls per minute.
+const coinGeckoQuoteConcurrency = 2
+
+// coinGeckoLastCloseDays is the market chart range used to find the last daily close, covering the
+// last complete day and the current partial one.
+const coinGeckoLastCloseDays = 2
+
+// coinGeckoServiceOrigin is a zero-value pointer used as the origin type reference
+// for AppError construction in package-level CoinGecko mapping functions.
+var coinGeckoServiceOrigin = (*CoinGeckoAssetIntegrationService)(nil)
+
+// CoinGeckoAssetIntegrationService is the anticorruption layer service that translates CoinGecko
+// integration DTSs into domain model types.
+// External assets from this source use the CoinGecko coin id as ticker and the vs-currency they are
+// priced in as exchange identifier (e.g. "bitcoin" priced in "USD").
+// Delegates HTTP communication to the CoinGeckoAssetIntegrationClient.
+type CoinGeckoAssetIntegrationService struct {
+	Client *integration.CoinGeckoAssetIntegrationClient
+}
+
+// SearchAssets queries CoinGecko for coins matching the given name or symbol and returns the most
+// relevant ones as domain ExternalAsset instances priced in the configured vs-currency.
+//
+// Parameters:
+//   - searchContext: the context for the request, honoring cancellation
+//   - queryValue: the coin name or symbol to search for
+//
+// Returns:
+//   - []*domain.ExternalAsset: the matched coins translated to external assets
+//   - error: propagated from the integration client if the call fails
+//
+// Example:
+//
+//	var coinGeckoConfig = infra.ReadConfig().IntegrationConfig.CoinGeckoConfig
+//	var client = integration.BuildCoinGeckoAssetIntegrationClient(coinGeckoConfig)
+//	var service = BuildCoinGeckoAssetIntegrationService(client)
+//	assets, err := service.SearchAssets(context.Background(), "BTC")
+func (service *CoinGeckoAssetIntegrationService) SearchAssets(
+	searchContext context.Context,
+	queryValue string,
+) ([]*domain.ExternalAsset, error) {
+
+	var searchResponse, err = service.Client.SearchCoins(searchContext, queryValue)
+	if err != nil {
+		return nil, err
+	}
+
+	var coins = searchResponse.Coins
+	if len(coins) > coinGeckoSearchResultsLimit {
+		coins = coins[:coinGeckoSearchResultsLimit]
+	}
+
+	var vsCurrency = strings.ToUpper(service.Client.GetVsCurrency())
+	var externalAssets = make([]*domain.ExternalAsset, len(coins))
+	for index := range coins {
+		externalAssets[index] = mapCoinGeckoCoinToExternalAsset(&coins[index], vsCurrency)
+	}
+
+	return externalAssets, nil
+}
+
+// mapCoinGeckoCoinToExternalAsset converts a CoinGecko search coin DTS to a domain ExternalAsset
+// priced in the given vs-currency.
+func mapCoinGeckoCoinToExternalAsset(
+	coin *integration.CoinGeckoSearchCoinDTS,
+	vsCurrency string,
+) *domain.ExternalAsset {
+	return &domain.ExternalAsset{
+		Source:         domain.CoinGeckoSource,
+		Ticker:         coin.Id,
+		ExchangeId:     vsCurrency,
+		Name:           coin.Name,
+		ExchangeName:   strings.ToUpper(coin.Symbol) + "/" + vsCurrency,
+		InstrumentType: domain.CryptoInstrumentType,
+	}
+}
+
+// QuoteAssetLastClosePrice queries the CoinGecko market chart for the last daily close of the given
+// asset, in the vs-currency set as its exchange identifier, and returns it as a domain
+// ExternalAssetQuote.
+//
+// Parameters:
+//   - asset: the external asset to quote, must have Source set to domain.CoinGeckoSource
+//
+// Returns:
+//   - *domain.ExternalAssetQuote: the asset quote with last close price, date, currency, and identifiers
+//   - error: if the asset source does not match, the vs-currency is not an ISO currency, or
+//     propagated from the integration client
+//
+// Example:
+//
+//	var asset = &domain.ExternalAsset{Source: domain.CoinGeckoSource, Ticker: "bitcoin", ExchangeId: "USD"}
+//	quote, err := service.QuoteAssetLastClosePrice(asset)
+func (service *CoinGeckoAssetIntegrationService) QuoteAssetLastClosePrice(
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+	return service.quoteAssetLastClosePrice(context.Background(), asset)
+}
+
+// QuoteAssetsLastClosePrice quotes several CoinGecko assets, with at most coinGeckoQuoteConcurrency
+// market chart requests at the same time.
+func (service *CoinGeckoAssetIntegrationService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	assets []*domain.ExternalAsset,
+) []*domain.ExternalAssetQuoteResult {
+	return domain.QuoteExternalAssetsConcurrently(
+		requestContext,
+		assets,
+		coinGeckoQuoteConcurrency,
+		service.quoteAssetLastClosePrice,
+	)
+}
+
+func (service *CoinGeckoAssetIntegrationService) quoteAssetLastClosePrice(
+	requestContext context.Context,
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+
+	var dailyCloses, err = service.GetAssetDailyCloses(requestContext, asset, coinGeckoLastCloseDays)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dailyCloses) == 0 {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"CoinGecko market chart for %s contains no prices",
+			asset.Ticker,
+		)
+	}
+
+	return dailyCloses[len(dailyCloses)-1], nil
+}
+
+// GetAssetDailyCloses queries the CoinGecko market chart for the daily closes of the given asset over
+// the last given number of days. CoinGecko reports daily prices at 00:00 UTC, which are taken as the
+// close of each day. The latest intraday price, when present, is ignored.
+//
+// Parameters:
+//   - requestContext: the context for the request
+//   - asset: the external asset to get the history of, must have Source set to domain.CoinGeckoSource
+//   - days: the number of past days of history
+//
+// Returns:
+//   - []*domain.ExternalAssetQuote: the daily closes in ascending date order
+//   - error: if the asset source does not match, the vs-currency is not an ISO currency, or
+//     propagated from the integration client
+func (service *CoinGeckoAssetIntegrationService) GetAssetDailyCloses(
+	requestContext context.Context,
+	asset *domain.ExternalAsset,
+	days int,
+) ([]*domain.ExternalAssetQuote, error) {
+
+	if asset.Source != domain.CoinGeckoSource {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"unexpected asset source %s for CoinGecko anticorruption service",
+			asset.Source,
+		)
+	}
+
+	var vsCurrency = asset.ExchangeId
+	if vsCurrency == "" {
+		vsCurrency = service.Client.GetVsCurrency()
+	}
+
+	var currencyUnit, currencyErr = currency.ParseISO(vsCurrency)
+	if currencyErr != nil {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"error parsing CoinGecko vs-currency %s: %v",
+			vsCurrency,
+			currencyErr,
+		)
+	}
+
+	var marketChart, err = service.Client.GetCoinMarketChart(
+		requestContext,
+		asset.Ticker,
+		strings.ToLower(vsCurrency),
+		days,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapCoinGeckoMarketChartToDailyCloses(marketChart, asset.Ticker, currencyUnit)
+}
+
+// mapCoinGeckoMarketChartToDailyCloses converts the market chart prices at 00:00 UTC into domain
+// ExternalAssetQuote values.
+func mapCoinGeckoMarketChartToDailyCloses(
+	marketChart *integration.CoinGeckoMarketChartDTS,
+	coinId string,
+	currencyUnit currency.Unit,
+) ([]*domain.ExternalAssetQuote, error) {
+
+	var dailyCloses = make([]*domain.ExternalAssetQuote, 0, len(marketChart.Prices))
+	for _, pricePoint := range marketChart.Prices {
+
+		if len(pricePoint) < 2 {
+			return nil, infra.BuildAppError("CoinGecko market chart contains a malformed price", coinGeckoServiceOrigin)
+		}
+
+		var priceTime = time.UnixMilli(int64(pricePoint[0])).UTC()
+		if !priceTime.Equal(priceTime.Truncate(24 * time.Hour)) {
+			continue
+		}
+
+		dailyCloses = append(
+			dailyCloses,
+			&domain.ExternalAssetQuote{
+				Source:         domain.CoinGeckoSource,
+				Ticker:         coinId,
+				ExchangeId:     currencyUnit.String(),
+				Currency:       currencyUnit,
+				LastCloseQuote: decimal.NewFromFloat(pricePoint[1]),
+				LastCloseDate:  priceTime,
+			},
+		)
+	}
+
+	return dailyCloses, nil
+}
+
+// BuildCoinGeckoAssetIntegrationService creates a new CoinGeckoAssetIntegrationService with the given
+// integration client.
+//
+// Parameters:
+//   - client: the CoinGecko HTTP integration client to delegate API calls to
+//
+// Returns:
+//   - *CoinGeckoAssetIntegrationService: the new service instance
+func BuildCoinGeckoAssetIntegrationService(
+	client *integration.CoinGeckoAssetIntegrationClient,
+) *CoinGeckoAssetIntegrationService {
+	return &CoinGeckoAssetIntegrationService{
+		Client: client,
+	}
+}
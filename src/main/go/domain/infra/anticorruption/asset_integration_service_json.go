@@ -0,0 +1,461 @@
+package anticorruption
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/infra/integration"
+	"github.com/benizzio/open-asset-allocator/infra"
+	infrajson "github.com/benizzio/open-asset-allocator/infra/json"
+)
+
+const jsonProviderUnixDateFormat = "UNIX"
+const jsonProviderUnixMilliDateFormat = "UNIX_MILLI"
+
+// jsonProviderServiceOrigin is a zero-value pointer used as the origin type reference
+// for AppError construction in package-level JSON provider mapping functions.
+var jsonProviderServiceOrigin = (*JSONProviderAssetIntegrationService)(nil)
+
+// jsonProviderSearchPaths are the compiled search mapping paths of a JSON provider. Optional paths
+// that are not declared are nil.
+type jsonProviderSearchPaths struct {
+	results        *infrajson.JSONPath
+	ticker         *infrajson.JSONPath
+	exchangeId     *infrajson.JSONPath
+	name           *infrajson.JSONPath
+	exchangeName   *infrajson.JSONPath
+	instrumentType *infrajson.JSONPath
+}
+
+// jsonProviderQuotePaths are the compiled quote mapping paths of a JSON provider. Optional paths that
+// are not declared are nil.
+type jsonProviderQuotePaths struct {
+	price    *infrajson.JSONPath
+	date     *infrajson.JSONPath
+	currency *infrajson.JSONPath
+}
+
+// JSONProviderAssetIntegrationService is the anticorruption layer service that translates the JSON
+// documents of a provider declared in the configuration into domain model types, following the paths
+// declared for the provider.
+// Delegates HTTP communication to the JSONProviderAssetIntegrationClient.
+type JSONProviderAssetIntegrationService struct {
+	Client      *integration.JSONProviderAssetIntegrationClient
+	source      domain.AssetExternalSource
+	searchPaths *jsonProviderSearchPaths
+	quotePaths  *jsonProviderQuotePaths
+}
+
+// SearchAssets queries the provider search URL and maps each result to a domain ExternalAsset. When
+// the provider has no search URL, no results are returned without calling it.
+//
+// Parameters:
+//   - searchContext: the context for the request, honoring cancellation
+//   - queryValue: the search term
+//
+// Returns:
+//   - []*domain.ExternalAsset: the results translated to external assets, skipping those without ticker
+//   - error: if the results path does not select an array, or propagated from the integration client
+func (service *JSONProviderAssetIntegrationService) SearchAssets(
+	searchContext context.Context,
+	queryValue string,
+) ([]*domain.ExternalAsset, error) {
+
+	if service.searchPaths == nil {
+		return []*domain.ExternalAsset{}, nil
+	}
+
+	var document, err = service.Client.Search(searchContext, queryValue)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultsValue, found = service.searchPaths.results.Select(document)
+	if !found {
+		return []*domain.ExternalAsset{}, nil
+	}
+
+	var results, isArray = resultsValue.([]any)
+	if !isArray {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"%s search results path %s does not select an array",
+			service.source,
+			service.searchPaths.results,
+		)
+	}
+
+	var externalAssets = make([]*domain.ExternalAsset, 0, len(results))
+	for _, result := range results {
+		var externalAsset = service.mapSearchResultToExternalAsset(result)
+		if externalAsset != nil {
+			externalAssets = append(externalAssets, externalAsset)
+		}
+	}
+
+	return externalAssets, nil
+}
+
+func (service *JSONProviderAssetIntegrationService) mapSearchResultToExternalAsset(result any) *domain.ExternalAsset {
+
+	var ticker, found = selectJSONProviderString(service.searchPaths.ticker, result)
+	if !found || ticker == "" {
+		return nil
+	}
+
+	var exchangeId, _ = selectJSONProviderString(service.searchPaths.exchangeId, result)
+	var name, _ = selectJSONProviderString(service.searchPaths.name, result)
+	var exchangeName, _ = selectJSONProviderString(service.searchPaths.exchangeName, result)
+	var instrumentTypeValue, _ = selectJSONProviderString(service.searchPaths.instrumentType, result)
+
+	var instrumentType = domain.AssetInstrumentType(strings.ToUpper(instrumentTypeValue))
+	if !instrumentType.IsValid() {
+		instrumentType = ""
+	}
+
+	return &domain.ExternalAsset{
+		Source:         service.source,
+		Ticker:         ticker,
+		ExchangeId:     exchangeId,
+		Name:           name,
+		ExchangeName:   exchangeName,
+		InstrumentType: instrumentType,
+	}
+}
+
+// QuoteAssetLastClosePrice queries the provider quote URL for the given asset and maps the response to
+// a domain ExternalAssetQuote.
+//
+// Parameters:
+//   - asset: the external asset to quote, must have Source set to the provider source
+//
+// Returns:
+//   - *domain.ExternalAssetQuote: the asset quote with last close price, date, currency, and identifiers
+//   - error: if the asset source does not match, the price, date or currency are missing or malformed
+//     in the response, or propagated from the integration client
+func (service *JSONProviderAssetIntegrationService) QuoteAssetLastClosePrice(
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+	return service.quoteAssetLastClosePrice(context.Background(), asset)
+}
+
+// QuoteAssetsLastClosePrice quotes several assets of the provider, with at most the configured quote
+// concurrency of the provider quoted at the same time.
+func (service *JSONProviderAssetIntegrationService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	assets []*domain.ExternalAsset,
+) []*domain.ExternalAssetQuoteResult {
+	return domain.QuoteExternalAssetsConcurrently(
+		requestContext,
+		assets,
+		service.Client.GetConfig().QuoteConcurrency,
+		service.quoteAssetLastClosePrice,
+	)
+}
+
+func (service *JSONProviderAssetIntegrationService) quoteAssetLastClosePrice(
+	requestContext context.Context,
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+
+	if asset.Source != service.source {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"unexpected asset source %s for %s anticorruption service",
+			asset.Source,
+			service.source,
+		)
+	}
+
+	var document, err = service.Client.Quote(requestContext, asset.Ticker, asset.ExchangeId)
+	if err != nil {
+		return nil, err
+	}
+
+	lastCloseQuote, err := service.mapQuotePrice(document, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	lastCloseDate, err := service.mapQuoteDate(document, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	currencyUnit, err := service.mapQuoteCurrency(document, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ExternalAssetQuote{
+		Source:         service.source,
+		Ticker:         asset.Ticker,
+		ExchangeId:     asset.ExchangeId,
+		Currency:       currencyUnit,
+		LastCloseQuote: lastCloseQuote,
+		LastCloseDate:  lastCloseDate,
+	}, nil
+}
+
+func (service *JSONProviderAssetIntegrationService) mapQuotePrice(
+	document any,
+	asset *domain.ExternalAsset,
+) (decimal.Decimal, error) {
+
+	var priceValue, found = selectJSONProviderString(service.quotePaths.price, document)
+	if !found {
+		return decimal.Decimal{}, infra.BuildAppErrorFormatted(
+			service,
+			"%s quote of %s has no price at %s",
+			service.source,
+			asset.Ticker,
+			service.quotePaths.price,
+		)
+	}
+
+	var price, err = decimal.NewFromString(priceValue)
+	if err != nil {
+		return decimal.Decimal{}, infra.BuildAppErrorFormatted(
+			service,
+			"error parsing %s price %s of %s: %v",
+			service.source,
+			priceValue,
+			asset.Ticker,
+			err,
+		)
+	}
+
+	return price, nil
+}
+
+// mapQuoteDate parses the quote date with the declared date format, or dates the quote on the current
+// UTC day when the provider has no date path.
+func (service *JSONProviderAssetIntegrationService) mapQuoteDate(
+	document any,
+	asset *domain.ExternalAsset,
+) (time.Time, error) {
+
+	if service.quotePaths.date == nil {
+		return time.Now().UTC().Truncate(24 * time.Hour), nil
+	}
+
+	var dateValue, found = selectJSONProviderString(service.quotePaths.date, document)
+	if !found {
+		return time.Time{}, infra.BuildAppErrorFormatted(
+			service,
+			"%s quote of %s has no date at %s",
+			service.source,
+			asset.Ticker,
+			service.quotePaths.date,
+		)
+	}
+
+	var date, err = parseJSONProviderDate(dateValue, service.Client.GetConfig().QuoteMapping.DateFormat)
+	if err != nil {
+		return time.Time{}, infra.BuildAppErrorFormatted(
+			service,
+			"error parsing %s date %s of %s: %v",
+			service.source,
+			dateValue,
+			asset.Ticker,
+			err,
+		)
+	}
+
+	return date, nil
+}
+
+// mapQuoteCurrency takes the currency from the quote response, falling back to the currency declared
+// for the provider.
+func (service *JSONProviderAssetIntegrationService) mapQuoteCurrency(
+	document any,
+	asset *domain.ExternalAsset,
+) (currency.Unit, error) {
+
+	var currencyCode, found = selectJSONProviderString(service.quotePaths.currency, document)
+	if !found || currencyCode == "" {
+		currencyCode = service.Client.GetConfig().QuoteMapping.Currency
+	}
+
+	var currencyUnit, err = currency.ParseISO(currencyCode)
+	if err != nil {
+		return currency.Unit{}, infra.BuildAppErrorFormatted(
+			service,
+			"error parsing %s currency %q of %s: %v",
+			service.source,
+			currencyCode,
+			asset.Ticker,
+			err,
+		)
+	}
+
+	return currencyUnit, nil
+}
+
+// parseJSONProviderDate parses a date in the given Go time layout, or an epoch timestamp in seconds
+// or milliseconds for the UNIX and UNIX_MILLI formats, as a UTC time.
+func parseJSONProviderDate(dateValue string, dateFormat string) (time.Time, error) {
+
+	switch dateFormat {
+	case jsonProviderUnixDateFormat, jsonProviderUnixMilliDateFormat:
+		var timestamp, err = strconv.ParseFloat(dateValue, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if dateFormat == jsonProviderUnixMilliDateFormat {
+			return time.UnixMilli(int64(timestamp)).UTC(), nil
+		}
+		return time.Unix(int64(timestamp), 0).UTC(), nil
+	}
+
+	var date, err = time.Parse(dateFormat, dateValue)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return date.UTC(), nil
+}
+
+// selectJSONProviderString selects a string or number with the path, formatting numbers without
+// exponent. A nil path selects nothing.
+func selectJSONProviderString(path *infrajson.JSONPath, document any) (string, bool) {
+
+	if path == nil {
+		return "", false
+	}
+
+	var value, found = path.Select(document)
+	if !found {
+		return "", false
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64), true
+	}
+
+	return "", false
+}
+
+// compileOptionalJSONPath compiles a declared path, returning nil when it is not declared.
+func compileOptionalJSONPath(expression string) (*infrajson.JSONPath, error) {
+	if expression == "" {
+		return nil, nil
+	}
+	return infrajson.CompileJSONPath(expression)
+}
+
+func compileJSONProviderSearchPaths(mapping *infra.JSONProviderSearchMapping) (*jsonProviderSearchPaths, error) {
+
+	var paths jsonProviderSearchPaths
+	var err error
+
+	for _, compilation := range []struct {
+		target     **infrajson.JSONPath
+		expression string
+	}{
+		{&paths.results, mapping.ResultsPath},
+		{&paths.ticker, mapping.TickerPath},
+		{&paths.exchangeId, mapping.ExchangeIdPath},
+		{&paths.name, mapping.NamePath},
+		{&paths.exchangeName, mapping.ExchangeNamePath},
+		{&paths.instrumentType, mapping.InstrumentTypePath},
+	} {
+		if *compilation.target, err = compileOptionalJSONPath(compilation.expression); err != nil {
+			return nil, err
+		}
+	}
+
+	if paths.results == nil || paths.ticker == nil {
+		return nil, fmt.Errorf("search results and ticker paths are required")
+	}
+
+	return &paths, nil
+}
+
+func compileJSONProviderQuotePaths(mapping *infra.JSONProviderQuoteMapping) (*jsonProviderQuotePaths, error) {
+
+	var paths jsonProviderQuotePaths
+	var err error
+
+	for _, compilation := range []struct {
+		target     **infrajson.JSONPath
+		expression string
+	}{
+		{&paths.price, mapping.PricePath},
+		{&paths.date, mapping.DatePath},
+		{&paths.currency, mapping.CurrencyPath},
+	} {
+		if *compilation.target, err = compileOptionalJSONPath(compilation.expression); err != nil {
+			return nil, err
+		}
+	}
+
+	if paths.price == nil {
+		return nil, fmt.Errorf("quote price path is required")
+	}
+
+	return &paths, nil
+}
+
+// BuildJSONProviderAssetIntegrationService creates a new JSONProviderAssetIntegrationService with the
+// given integration client, compiling the mapping paths of the provider declaration.
+//
+// Parameters:
+//   - client: the JSON provider HTTP integration client to delegate API calls to
+//
+// Returns:
+//   - *JSONProviderAssetIntegrationService: the new service instance
+//   - error: an AppError if a mapping path of the provider declaration is malformed
+//
+// Example:
+//
+//	for _, providerConfig := range infra.ReadConfig().IntegrationConfig.JSONProviderConfigs {
+//	    var client = integration.BuildJSONProviderAssetIntegrationClient(providerConfig)
+//	    service, err := BuildJSONProviderAssetIntegrationService(client)
+//	}
+func BuildJSONProviderAssetIntegrationService(
+	client *integration.JSONProviderAssetIntegrationClient,
+) (*JSONProviderAssetIntegrationService, error) {
+
+	var config = client.GetConfig()
+
+	var searchPaths *jsonProviderSearchPaths
+	if config.SearchURLTemplate != "" {
+		var err error
+		if searchPaths, err = compileJSONProviderSearchPaths(&config.SearchMapping); err != nil {
+			return nil, infra.BuildAppErrorFormatted(
+				jsonProviderServiceOrigin,
+				"invalid search mapping of JSON provider %s: %v",
+				config.Source,
+				err,
+			)
+		}
+	}
+
+	var quotePaths, err = compileJSONProviderQuotePaths(&config.QuoteMapping)
+	if err != nil {
+		return nil, infra.BuildAppErrorFormatted(
+			jsonProviderServiceOrigin,
+			"invalid quote mapping of JSON provider %s: %v",
+			config.Source,
+			err,
+		)
+	}
+
+	return &JSONProviderAssetIntegrationService{
+		Client:      client,
+		source:      domain.AssetExternalSource(config.Source),
+		searchPaths: searchPaths,
+		quotePaths:  quotePaths,
+	}, nil
+}
@@ -0,0 +1,252 @@
+package anticorruption
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/infra/integration"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const stooqDateLayout = "2006-01-02"
+
+// stooqQuoteConcurrency bounds the concurrent assets of a batch quote, each taking up to two requests.
+const stooqQuoteConcurrency = 2
+
+// stooqServiceOrigin is a zero-value pointer used as the origin type reference
+// for AppError construction in package-level Stooq mapping functions.
+var stooqServiceOrigin = (*StooqAssetIntegrationService)(nil)
+
+// stooqCurrenciesPerMarket maps the Stooq symbol market suffixes to the currency their quotes are
+// expressed in. Stooq does not report currencies, so only symbols of these markets are supported.
+var stooqCurrenciesPerMarket = map[string]currency.Unit{
+	"US": currency.USD,
+	"DE": currency.EUR,
+	"JP": currency.JPY,
+	"HK": currency.HKD,
+}
+
+// StooqAssetIntegrationService is the anticorruption layer service that translates Stooq CSV
+// integration DTSs into domain model types.
+// Delegates HTTP communication to the StooqAssetIntegrationClient.
+type StooqAssetIntegrationService struct {
+	Client *integration.StooqAssetIntegrationClient
+}
+
+// SearchAssets looks up the given query as a Stooq symbol. Stooq has no search endpoint, so only an
+// exact symbol with a supported market suffix (e.g. "aapl.us") can match. Other queries return no
+// results without calling Stooq.
+//
+// Parameters:
+//   - searchContext: the context for the request, honoring cancellation
+//   - queryValue: the search term, expected to be a Stooq symbol
+//
+// Returns:
+//   - []*domain.ExternalAsset: the matched external asset, or an empty slice
+//   - error: propagated from the integration client if the call fails
+//
+// Example:
+//
+//	var stooqConfig = infra.ReadConfig().IntegrationConfig.StooqConfig
+//	var service = BuildStooqAssetIntegrationService(integration.BuildStooqAssetIntegrationClient(stooqConfig))
+//	assets, err := service.SearchAssets(context.Background(), "aapl.us")
+func (service *StooqAssetIntegrationService) SearchAssets(
+	searchContext context.Context,
+	queryValue string,
+) ([]*domain.ExternalAsset, error) {
+
+	var symbol = strings.ToLower(strings.TrimSpace(queryValue))
+	if _, supported := getStooqMarketCurrency(symbol); !supported {
+		return []*domain.ExternalAsset{}, nil
+	}
+
+	var quote, err = service.Client.QuoteAsset(searchContext, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if !quote.HasData() {
+		return []*domain.ExternalAsset{}, nil
+	}
+
+	return []*domain.ExternalAsset{mapStooqQuoteToExternalAsset(quote)}, nil
+}
+
+// mapStooqQuoteToExternalAsset converts a Stooq quote DTS to a domain ExternalAsset, using the symbol
+// market suffix as the exchange identifier.
+func mapStooqQuoteToExternalAsset(quote *integration.StooqQuoteDTS) *domain.ExternalAsset {
+	return &domain.ExternalAsset{
+		Source:     domain.StooqSource,
+		Ticker:     strings.ToUpper(quote.Symbol),
+		ExchangeId: getStooqMarket(quote.Symbol),
+		Name:       quote.Name,
+	}
+}
+
+// QuoteAssetLastClosePrice queries Stooq for the last close price of the given asset and returns it as
+// a domain ExternalAssetQuote. Uses the quote endpoint and falls back to the last row of the daily
+// history when the quote has no close price.
+//
+// Parameters:
+//   - asset: the external asset to quote, must have Source set to domain.StooqSource
+//
+// Returns:
+//   - *domain.ExternalAssetQuote: the asset quote with last close price, date, currency, and identifiers
+//   - error: if the asset source does not match, the symbol market is not supported, Stooq has no
+//     data for the symbol, or propagated from the integration client
+//
+// Example:
+//
+//	var asset = &domain.ExternalAsset{Source: domain.StooqSource, Ticker: "AAPL.US", ExchangeId: "US"}
+//	quote, err := service.QuoteAssetLastClosePrice(asset)
+func (service *StooqAssetIntegrationService) QuoteAssetLastClosePrice(
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+	return service.quoteAssetLastClosePrice(context.Background(), asset)
+}
+
+// QuoteAssetsLastClosePrice quotes several Stooq assets, with at most stooqQuoteConcurrency assets
+// quoted at the same time.
+func (service *StooqAssetIntegrationService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	assets []*domain.ExternalAsset,
+) []*domain.ExternalAssetQuoteResult {
+	return domain.QuoteExternalAssetsConcurrently(
+		requestContext,
+		assets,
+		stooqQuoteConcurrency,
+		service.quoteAssetLastClosePrice,
+	)
+}
+
+func (service *StooqAssetIntegrationService) quoteAssetLastClosePrice(
+	requestContext context.Context,
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+
+	if asset.Source != domain.StooqSource {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"unexpected asset source %s for Stooq anticorruption service",
+			asset.Source,
+		)
+	}
+
+	var symbol = strings.ToLower(asset.Ticker)
+	var currencyUnit, supported = getStooqMarketCurrency(symbol)
+	if !supported {
+		return nil, infra.BuildAppErrorFormatted(service, "unsupported Stooq market for symbol %s", asset.Ticker)
+	}
+
+	var quote, err = service.Client.QuoteAsset(requestContext, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastCloseQuote decimal.Decimal
+	var lastCloseDate time.Time
+	if quote.HasData() {
+		lastCloseQuote, lastCloseDate, err = parseStooqClose(quote.Close, quote.Date)
+	} else {
+		lastCloseQuote, lastCloseDate, err = service.quoteLastCloseFromHistory(requestContext, symbol)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ExternalAssetQuote{
+		Source:         domain.StooqSource,
+		Ticker:         strings.ToUpper(symbol),
+		ExchangeId:     getStooqMarket(symbol),
+		Currency:       currencyUnit,
+		LastCloseQuote: lastCloseQuote,
+		LastCloseDate:  lastCloseDate,
+	}, nil
+}
+
+func (service *StooqAssetIntegrationService) quoteLastCloseFromHistory(
+	requestContext context.Context,
+	symbol string,
+) (decimal.Decimal, time.Time, error) {
+
+	var history, err = service.Client.GetAssetDailyHistory(requestContext, symbol)
+	if err != nil {
+		return decimal.Decimal{}, time.Time{}, err
+	}
+
+	for index := len(history) - 1; index >= 0; index-- {
+		var row = history[index]
+		if row.Close == "" || row.Close == integration.StooqNoDataValue {
+			continue
+		}
+		return parseStooqClose(row.Close, row.Date)
+	}
+
+	return decimal.Decimal{}, time.Time{}, infra.BuildAppErrorFormatted(
+		service,
+		"Stooq has no close price for symbol %s",
+		symbol,
+	)
+}
+
+// parseStooqClose parses a Stooq close price and its date, interpreted as a UTC calendar day.
+func parseStooqClose(closeValue string, dateValue string) (decimal.Decimal, time.Time, error) {
+
+	var closePrice, err = decimal.NewFromString(closeValue)
+	if err != nil {
+		return decimal.Decimal{}, time.Time{}, infra.BuildAppErrorFormatted(
+			stooqServiceOrigin,
+			"error parsing Stooq close price %s: %v",
+			closeValue,
+			err,
+		)
+	}
+
+	closeDate, err := time.Parse(stooqDateLayout, dateValue)
+	if err != nil {
+		return decimal.Decimal{}, time.Time{}, infra.BuildAppErrorFormatted(
+			stooqServiceOrigin,
+			"error parsing Stooq close date %s: %v",
+			dateValue,
+			err,
+		)
+	}
+
+	return closePrice, closeDate, nil
+}
+
+// getStooqMarket extracts the upper-case market suffix of a Stooq symbol (e.g. "US" for "aapl.us"),
+// or an empty string when the symbol has no suffix.
+func getStooqMarket(symbol string) string {
+	var separatorIndex = strings.LastIndex(symbol, ".")
+	if separatorIndex < 0 {
+		return ""
+	}
+	return strings.ToUpper(symbol[separatorIndex+1:])
+}
+
+func getStooqMarketCurrency(symbol string) (currency.Unit, bool) {
+	var currencyUnit, supported = stooqCurrenciesPerMarket[getStooqMarket(symbol)]
+	return currencyUnit, supported
+}
+
+// BuildStooqAssetIntegrationService creates a new StooqAssetIntegrationService with the given
+// integration client.
+//
+// Parameters:
+//   - client: the Stooq HTTP integration client to delegate API calls to
+//
+// Returns:
+//   - *StooqAssetIntegrationService: the new service instance
+func BuildStooqAssetIntegrationService(
+	client *integration.StooqAssetIntegrationClient,
+) *StooqAssetIntegrationService {
+	return &StooqAssetIntegrationService{
+		Client: client,
+	}
+}
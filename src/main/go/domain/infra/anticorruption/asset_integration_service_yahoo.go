@@ -2,6 +2,8 @@ package anticorruption
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -12,10 +14,25 @@ import (
 	"github.com/benizzio/open-asset-allocator/infra"
 )
 
+// yahooFinanceQuoteConcurrency bounds the concurrent chart requests of a batch quote, as Yahoo Finance
+// rate limits aggressively.
+const yahooFinanceQuoteConcurrency = 4
+
 // serviceOrigin is a zero-value pointer used as the origin type reference
 // for AppError construction in package-level mapping functions.
 var serviceOrigin = (*YahooFinanceAssetIntegrationService)(nil)
 
+// yahooFinanceInstrumentTypesPerQuoteType maps the Yahoo Finance search quoteType values to domain
+// instrument types. Quote types without a domain counterpart (e.g. INDEX, FUTURE) are left unmapped.
+var yahooFinanceInstrumentTypesPerQuoteType = map[string]domain.AssetInstrumentType{
+	"EQUITY":         domain.EquityInstrumentType,
+	"ETF":            domain.ETFInstrumentType,
+	"BOND":           domain.BondInstrumentType,
+	"CRYPTOCURRENCY": domain.CryptoInstrumentType,
+	"CURRENCY":       domain.CashInstrumentType,
+	"MONEYMARKET":    domain.CashInstrumentType,
+}
+
 // YahooFinanceAssetIntegrationService is the anticorruption layer service that translates
 // Yahoo Finance integration DTSs into domain model types.
 // Delegates HTTP communication to the YahooFinanceAssetIntegrationClient.
@@ -82,11 +99,12 @@ func mapToExternalAssets(quotes []integration.YahooFinanceSearchQuoteDTS) []*dom
 // Authored by: GitHub Copilot (claude-opus-4.6)
 func mapToExternalAsset(quote *integration.YahooFinanceSearchQuoteDTS) *domain.ExternalAsset {
 	return &domain.ExternalAsset{
-		Source:       domain.YahooFinanceSource,
-		Ticker:       quote.Symbol,
-		ExchangeId:   quote.Exchange,
-		Name:         quote.LongName,
-		ExchangeName: quote.ExchDisp,
+		Source:         domain.YahooFinanceSource,
+		Ticker:         quote.Symbol,
+		ExchangeId:     quote.Exchange,
+		Name:           quote.LongName,
+		ExchangeName:   quote.ExchDisp,
+		InstrumentType: yahooFinanceInstrumentTypesPerQuoteType[quote.QuoteType],
 	}
 }
 
@@ -118,6 +136,27 @@ func mapToExternalAsset(quote *integration.YahooFinanceSearchQuoteDTS) *domain.E
 func (service *YahooFinanceAssetIntegrationService) QuoteAssetLastClosePrice(
 	asset *domain.ExternalAsset,
 ) (*domain.ExternalAssetQuote, error) {
+	return service.quoteAssetLastClosePrice(context.Background(), asset)
+}
+
+// QuoteAssetsLastClosePrice quotes several Yahoo Finance assets, with at most
+// yahooFinanceQuoteConcurrency chart requests at the same time.
+func (service *YahooFinanceAssetIntegrationService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	assets []*domain.ExternalAsset,
+) []*domain.ExternalAssetQuoteResult {
+	return domain.QuoteExternalAssetsConcurrently(
+		requestContext,
+		assets,
+		yahooFinanceQuoteConcurrency,
+		service.quoteAssetLastClosePrice,
+	)
+}
+
+func (service *YahooFinanceAssetIntegrationService) quoteAssetLastClosePrice(
+	requestContext context.Context,
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
 
 	if asset.Source != domain.YahooFinanceSource {
 		return nil, infra.BuildAppErrorFormatted(
@@ -127,7 +166,7 @@ func (service *YahooFinanceAssetIntegrationService) QuoteAssetLastClosePrice(
 		)
 	}
 
-	var chartResponse, err = service.Client.QuoteAssetLastClosePrice(asset.Ticker)
+	var chartResponse, err = service.Client.QuoteAssetLastClosePrice(requestContext, asset.Ticker)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +210,7 @@ func mapToExternalAssetQuote(
 	}
 
 	return &domain.ExternalAssetQuote{
+		Source:         domain.YahooFinanceSource,
 		Ticker:         result.Meta.Symbol,
 		ExchangeId:     result.Meta.ExchangeName,
 		Currency:       currencyUnit,
@@ -219,6 +259,117 @@ func extractLastClose(
 	)
 }
 
+// GetAssetEvents queries the Yahoo Finance chart API for the dividend and split history of the given
+// asset and returns it as domain AssetEvent instances ordered by date. Dividends are recorded in the
+// currency of the chart response.
+//
+// Parameters:
+//   - requestContext: the context for the HTTP request
+//   - asset: the external asset, must have Source set to domain.YahooFinanceSource
+//
+// Returns:
+//   - []*domain.AssetEvent: the events of the asset, not bound to a persisted asset
+//   - error: if the asset source does not match, or propagated from the integration client,
+//     or if the response structure is invalid
+func (service *YahooFinanceAssetIntegrationService) GetAssetEvents(
+	requestContext context.Context,
+	asset *domain.ExternalAsset,
+) ([]*domain.AssetEvent, error) {
+
+	if asset.Source != domain.YahooFinanceSource {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"unexpected asset source %s for Yahoo Finance anticorruption service",
+			asset.Source,
+		)
+	}
+
+	var chartResponse, err = service.Client.GetAssetEvents(requestContext, asset.Ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToAssetEvents(chartResponse)
+}
+
+// mapToAssetEvents converts the events of a Yahoo Finance chart response DTS to domain AssetEvents,
+// ordered by date. Event dates are the UTC dates of the event timestamps.
+func mapToAssetEvents(chartResponse *integration.YahooFinanceChartResponseDTS) ([]*domain.AssetEvent, error) {
+
+	var results = chartResponse.Chart.Result
+	if len(results) == 0 {
+		return nil, infra.BuildAppError("Yahoo Finance chart response contains no results", serviceOrigin)
+	}
+
+	var result = results[0]
+	if result.Events == nil {
+		return []*domain.AssetEvent{}, nil
+	}
+
+	var assetEvents = make([]*domain.AssetEvent, 0, len(result.Events.Dividends)+len(result.Events.Splits))
+
+	if len(result.Events.Dividends) > 0 {
+
+		var currencyUnit, currencyErr = currency.ParseISO(result.Meta.Currency)
+		if currencyErr != nil {
+			return nil, infra.BuildAppErrorFormatted(
+				serviceOrigin,
+				"error parsing currency %s: %v",
+				result.Meta.Currency,
+				currencyErr,
+			)
+		}
+
+		for _, dividend := range result.Events.Dividends {
+			assetEvents = append(
+				assetEvents,
+				&domain.AssetEvent{
+					EventType: domain.DividendAssetEventType,
+					EventDate: toEventDate(dividend.Date),
+					Source:    domain.YahooFinanceSource,
+					Amount:    decimal.NewFromFloat(dividend.Amount),
+					Currency:  currencyUnit.String(),
+				},
+			)
+		}
+	}
+
+	for _, split := range result.Events.Splits {
+
+		if split.Numerator <= 0 || split.Denominator <= 0 {
+			return nil, infra.BuildAppErrorFormatted(
+				serviceOrigin,
+				"invalid Yahoo Finance split ratio %s",
+				split.SplitRatio,
+			)
+		}
+
+		assetEvents = append(
+			assetEvents,
+			&domain.AssetEvent{
+				EventType:        domain.SplitAssetEventType,
+				EventDate:        toEventDate(split.Date),
+				Source:           domain.YahooFinanceSource,
+				SplitNumerator:   decimal.NewFromFloat(split.Numerator),
+				SplitDenominator: decimal.NewFromFloat(split.Denominator),
+			},
+		)
+	}
+
+	slices.SortFunc(assetEvents, func(event1, event2 *domain.AssetEvent) int {
+		if dateComparison := event1.EventDate.Compare(event2.EventDate); dateComparison != 0 {
+			return dateComparison
+		}
+		return strings.Compare(string(event1.EventType), string(event2.EventType))
+	})
+
+	return assetEvents, nil
+}
+
+func toEventDate(unixTimestamp int64) time.Time {
+	return time.Unix(unixTimestamp, 0).UTC().Truncate(24 * time.Hour)
+}
+
 // BuildYahooFinanceAssetIntegrationService creates a new YahooFinanceAssetIntegrationService
 // with the given integration client.
 //
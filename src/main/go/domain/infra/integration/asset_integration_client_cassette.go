@@ -0,0 +1,28 @@
+package integration
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/util/http/httpclient"
+)
+
+// buildCassetteRequestOptions creates the RequestOption recording the exchanges of an integration
+// client to its cassette, or replaying them from it, when a cassette mode is configured. The cassette
+// is created once here so that every request of the client shares it. A configured cassette that cannot be
+// built, such as a missing file to replay, is an error rather than letting the requests reach the provider.
+func buildCassetteRequestOptions(config infra.HTTPCassetteConfiguration) ([]httpclient.RequestOption, error) {
+
+	var cassette, err = httpclient.BuildCassette(httpclient.CassetteMode(config.Mode), config.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("error building HTTP cassette %s in mode %s: %w", config.FilePath, config.Mode, err)
+	}
+
+	if cassette == nil {
+		return nil, nil
+	}
+
+	slog.Info("HTTP exchanges with cassette", "mode", config.Mode, "filePath", config.FilePath)
+	return []httpclient.RequestOption{httpclient.WithCassette(cassette)}, nil
+}
@@ -0,0 +1,166 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/util/http/httpclient"
+)
+
+const coinGeckoSearchPath = "/search"
+const coinGeckoMarketChartPathFormat = "/coins/%s/market_chart"
+const coinGeckoAPIKeyHeader = "x-cg-demo-api-key"
+
+// CoinGeckoAssetIntegrationClient is an HTTP client for CoinGecko-compatible crypto market data APIs.
+// Provides methods to query the search and market chart endpoints and return their responses as
+// typed DTSs.
+type CoinGeckoAssetIntegrationClient struct {
+	config         infra.CoinGeckoConfiguration
+	requestOptions []httpclient.RequestOption
+}
+
+// SearchCoins queries the CoinGecko search API for coins matching the given name or symbol.
+//
+// Parameters:
+//   - searchContext: the context for the HTTP request
+//   - queryValue: the coin name or symbol to search for
+//
+// Returns:
+//   - *CoinGeckoSearchResponseDTS: the decoded search response, ordered by CoinGecko relevance
+//   - error: an AppError if the request fails, returns a non-200 status, or decoding fails
+//
+// Example:
+//
+//	var client = BuildCoinGeckoAssetIntegrationClient(infra.CoinGeckoConfiguration{
+//	    BaseURL:    "https://api.coingecko.com/api/v3",
+//	    VsCurrency: "usd",
+//	})
+//	response, err := client.SearchCoins(context.Background(), "bitcoin")
+func (client *CoinGeckoAssetIntegrationClient) SearchCoins(
+	searchContext context.Context,
+	queryValue string,
+) (*CoinGeckoSearchResponseDTS, error) {
+
+	var requestURL, err = buildCoinGeckoURL(client.config.BaseURL, coinGeckoSearchPath, url.Values{"query": {queryValue}})
+	if err != nil {
+		return nil, infra.PropagateAsAppError(err, client)
+	}
+
+	var searchResponse, getErr = httpclient.ExecuteGetJSON[CoinGeckoSearchResponseDTS](
+		searchContext,
+		requestURL,
+		client.requestOptions...,
+	)
+	if getErr != nil {
+		return nil, infra.PropagateAsAppError(getErr, client)
+	}
+
+	return searchResponse, nil
+}
+
+// GetCoinMarketChart queries the CoinGecko market chart API for the daily price history of a coin
+// over the last given number of days, priced in the given vs-currency.
+//
+// Parameters:
+//   - requestContext: the context for the HTTP request
+//   - coinId: the CoinGecko coin identifier (e.g. "bitcoin")
+//   - vsCurrency: the currency to price the coin in (e.g. "usd")
+//   - days: the number of past days of history to return
+//
+// Returns:
+//   - *CoinGeckoMarketChartDTS: the decoded market chart, with prices in ascending time order
+//   - error: an AppError if the request fails, returns a non-200 status, or decoding fails
+//
+// Example:
+//
+//	chart, err := client.GetCoinMarketChart(context.Background(), "bitcoin", "usd", 30)
+func (client *CoinGeckoAssetIntegrationClient) GetCoinMarketChart(
+	requestContext context.Context,
+	coinId string,
+	vsCurrency string,
+	days int,
+) (*CoinGeckoMarketChartDTS, error) {
+
+	var requestURL, err = buildCoinGeckoURL(
+		client.config.BaseURL,
+		fmt.Sprintf(coinGeckoMarketChartPathFormat, url.PathEscape(coinId)),
+		url.Values{"vs_currency": {vsCurrency}, "days": {strconv.Itoa(days)}, "interval": {"daily"}},
+	)
+	if err != nil {
+		return nil, infra.PropagateAsAppError(err, client)
+	}
+
+	var marketChart, getErr = httpclient.ExecuteGetJSON[CoinGeckoMarketChartDTS](
+		requestContext,
+		requestURL,
+		client.requestOptions...,
+	)
+	if getErr != nil {
+		return nil, infra.PropagateAsAppError(getErr, client)
+	}
+
+	return marketChart, nil
+}
+
+// GetVsCurrency returns the configured default vs-currency used to price coins.
+func (client *CoinGeckoAssetIntegrationClient) GetVsCurrency() string {
+	return client.config.VsCurrency
+}
+
+// buildCoinGeckoRequestOptions sets the API key header when one is configured, as the public CoinGecko
+// API works without a key under stricter rate limits, followed by the resilience and cassette options.
+func buildCoinGeckoRequestOptions(config infra.CoinGeckoConfiguration) ([]httpclient.RequestOption, error) {
+
+	var options = []httpclient.RequestOption{httpclient.WithHeader("Accept", "application/json")}
+	if config.APIKey != "" {
+		options = append(options, httpclient.WithHeader(coinGeckoAPIKeyHeader, config.APIKey))
+	}
+
+	options = append(options, buildResilienceRequestOptions(config.Resilience)...)
+
+	cassetteOptions, err := buildCassetteRequestOptions(config.Cassette)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(options, cassetteOptions...), nil
+}
+
+// buildCoinGeckoURL constructs a full CoinGecko endpoint URL from the configured base URL, the
+// endpoint path and its query parameters.
+func buildCoinGeckoURL(baseURL string, path string, queryParams url.Values) (string, error) {
+
+	var parsedURL, err = url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing CoinGecko base URL %s: %w", baseURL, err)
+	}
+
+	parsedURL.Path = strings.TrimRight(parsedURL.Path, "/") + path
+	parsedURL.RawQuery = queryParams.Encode()
+
+	return parsedURL.String(), nil
+}
+
+// BuildCoinGeckoAssetIntegrationClient creates a new CoinGeckoAssetIntegrationClient instance.
+//
+// Parameters:
+//   - config: the CoinGecko endpoint and HTTP resilience configuration used by the client
+//
+// Returns:
+//   - *CoinGeckoAssetIntegrationClient: the new client instance
+//   - error: when the configured cassette cannot be built
+func BuildCoinGeckoAssetIntegrationClient(
+	config infra.CoinGeckoConfiguration,
+) (*CoinGeckoAssetIntegrationClient, error) {
+
+	var requestOptions, err = buildCoinGeckoRequestOptions(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CoinGeckoAssetIntegrationClient{config: config, requestOptions: requestOptions}, nil
+}
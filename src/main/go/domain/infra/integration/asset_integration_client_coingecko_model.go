@@ -0,0 +1,27 @@
+package integration
+
+// asset_integration_client_coingecko_model.go contains the Data Transfer Structures (DTS) that map to
+// the CoinGecko API JSON responses. Any CoinGecko-compatible API exposing the same endpoints can be
+// used through them.
+
+// CoinGeckoSearchCoinDTS represents a single coin result from the CoinGecko search API.
+// Fields map to the JSON response keys returned by the /search endpoint.
+type CoinGeckoSearchCoinDTS struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	Symbol        string `json:"symbol"`
+	MarketCapRank *int   `json:"market_cap_rank"`
+}
+
+// CoinGeckoSearchResponseDTS represents the top-level response from the CoinGecko search API.
+// Only the coins section is mapped, exchanges, categories and NFTs are ignored.
+type CoinGeckoSearchResponseDTS struct {
+	Coins []CoinGeckoSearchCoinDTS `json:"coins"`
+}
+
+// CoinGeckoMarketChartDTS represents the response from the CoinGecko market chart API.
+// Each price entry is a pair of a Unix timestamp in milliseconds and the price in the requested
+// vs-currency.
+type CoinGeckoMarketChartDTS struct {
+	Prices [][]float64 `json:"prices"`
+}
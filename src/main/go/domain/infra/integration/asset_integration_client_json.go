@@ -0,0 +1,130 @@
+package integration
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/util/http/httpclient"
+)
+
+const jsonProviderQueryPlaceholder = "{query}"
+const jsonProviderTickerPlaceholder = "{ticker}"
+const jsonProviderExchangeIdPlaceholder = "{exchangeId}"
+
+// JSONProviderAssetIntegrationClient is an HTTP client for a market data provider declared in the
+// configuration, with a JSON API. The responses are returned as decoded JSON documents, to be mapped
+// by the paths declared for the provider.
+type JSONProviderAssetIntegrationClient struct {
+	config         infra.JSONProviderConfiguration
+	requestOptions []httpclient.RequestOption
+}
+
+// Search queries the search URL of the provider for the given query.
+//
+// Parameters:
+//   - searchContext: the context for the HTTP request
+//   - queryValue: the search term, replacing the {query} placeholder
+//
+// Returns:
+//   - any: the decoded JSON search response
+//   - error: an AppError if the request fails, returns a non-200 status, or decoding fails
+//
+// Example:
+//
+//	var client = BuildJSONProviderAssetIntegrationClient(infra.JSONProviderConfiguration{
+//	    Source:            "INTERNAL_FEED",
+//	    SearchURLTemplate: "https://feed.internal/search?q={query}",
+//	})
+//	document, err := client.Search(context.Background(), "AAPL")
+func (client *JSONProviderAssetIntegrationClient) Search(searchContext context.Context, queryValue string) (any, error) {
+
+	var requestURL = expandJSONProviderURLTemplate(
+		client.config.SearchURLTemplate,
+		jsonProviderQueryPlaceholder, queryValue,
+	)
+
+	return client.getDocument(searchContext, requestURL)
+}
+
+// Quote queries the quote URL of the provider for the given asset.
+//
+// Parameters:
+//   - requestContext: the context for the HTTP request
+//   - ticker: the provider ticker, replacing the {ticker} placeholder
+//   - exchangeId: the provider exchange identifier, replacing the {exchangeId} placeholder
+//
+// Returns:
+//   - any: the decoded JSON quote response
+//   - error: an AppError if the request fails, returns a non-200 status, or decoding fails
+func (client *JSONProviderAssetIntegrationClient) Quote(
+	requestContext context.Context,
+	ticker string,
+	exchangeId string,
+) (any, error) {
+
+	var requestURL = expandJSONProviderURLTemplate(
+		client.config.QuoteURLTemplate,
+		jsonProviderTickerPlaceholder, ticker,
+		jsonProviderExchangeIdPlaceholder, exchangeId,
+	)
+
+	return client.getDocument(requestContext, requestURL)
+}
+
+// GetConfig returns the provider declaration the client was built from.
+func (client *JSONProviderAssetIntegrationClient) GetConfig() *infra.JSONProviderConfiguration {
+	return &client.config
+}
+
+func (client *JSONProviderAssetIntegrationClient) getDocument(requestContext context.Context, requestURL string) (any, error) {
+
+	var document, err = httpclient.ExecuteGetJSON[any](requestContext, requestURL, client.requestOptions...)
+	if err != nil {
+		return nil, infra.PropagateAsAppError(err, client)
+	}
+
+	return *document, nil
+}
+
+// expandJSONProviderURLTemplate replaces the placeholder and value pairs in the URL template. Values
+// are escaped with %20 for spaces, so they are valid both in the path and in the query of the URL.
+func expandJSONProviderURLTemplate(urlTemplate string, placeholdersAndValues ...string) string {
+
+	var replacements = make([]string, 0, len(placeholdersAndValues))
+	for index := 0; index+1 < len(placeholdersAndValues); index += 2 {
+		var escapedValue = strings.ReplaceAll(url.QueryEscape(placeholdersAndValues[index+1]), "+", "%20")
+		replacements = append(replacements, placeholdersAndValues[index], escapedValue)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(urlTemplate)
+}
+
+// BuildJSONProviderAssetIntegrationClient creates a new JSONProviderAssetIntegrationClient instance.
+//
+// Parameters:
+//   - config: the provider declaration, with its URL templates, headers, HTTP resilience and cassette
+//     configuration
+//
+// Returns:
+//   - *JSONProviderAssetIntegrationClient: the new client instance
+//   - error: when the configured cassette cannot be built
+func BuildJSONProviderAssetIntegrationClient(
+	config infra.JSONProviderConfiguration,
+) (*JSONProviderAssetIntegrationClient, error) {
+
+	cassetteOptions, err := buildCassetteRequestOptions(config.Cassette)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestOptions = []httpclient.RequestOption{httpclient.WithHeader("Accept", "application/json")}
+	for key, value := range config.Headers {
+		requestOptions = append(requestOptions, httpclient.WithHeader(key, value))
+	}
+	requestOptions = append(requestOptions, buildResilienceRequestOptions(config.Resilience)...)
+	requestOptions = append(requestOptions, cassetteOptions...)
+
+	return &JSONProviderAssetIntegrationClient{config: config, requestOptions: requestOptions}, nil
+}
@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/util/http/httpclient"
+)
+
+// buildResilienceRequestOptions creates the retry, rate limiting and circuit breaking RequestOptions
+// of an integration client from its configuration. The rate limiter and circuit breaker are created
+// once here so that their state is shared by every request of the client. Mechanisms disabled in the
+// configuration are left out.
+func buildResilienceRequestOptions(config infra.HTTPResilienceConfiguration) []httpclient.RequestOption {
+
+	var options []httpclient.RequestOption
+
+	if config.MaxRetries > 0 {
+		options = append(
+			options,
+			httpclient.WithRetry(
+				httpclient.RetryPolicy{
+					MaxRetries: config.MaxRetries,
+					BaseDelay:  config.RetryBaseDelay,
+					MaxDelay:   config.RetryMaxDelay,
+				},
+			),
+		)
+	}
+
+	var rateLimiter = httpclient.BuildHostRateLimiter(config.RateLimitPerSecond, config.RateLimitBurst)
+	if rateLimiter != nil {
+		options = append(options, httpclient.WithRateLimiter(rateLimiter))
+	}
+
+	var circuitBreaker = httpclient.BuildCircuitBreaker(
+		config.CircuitBreakerFailureThreshold,
+		config.CircuitBreakerOpenDuration,
+	)
+	if circuitBreaker != nil {
+		options = append(options, httpclient.WithCircuitBreaker(circuitBreaker))
+	}
+
+	return options
+}
@@ -0,0 +1,190 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/util/http/httpclient"
+)
+
+const stooqQuotePath = "/q/l/"
+const stooqHistoryPath = "/q/d/l/"
+const stooqQuoteFields = "sd2t2ohlcvn"
+
+// StooqAssetIntegrationClient is an HTTP client for the Stooq CSV quote and history endpoints.
+// Provides methods to query Stooq and return the CSV rows as typed DTSs.
+type StooqAssetIntegrationClient struct {
+	config         infra.StooqConfiguration
+	requestOptions []httpclient.RequestOption
+}
+
+// QuoteAsset queries the Stooq quote endpoint for the latest quote of the given symbol.
+// Stooq symbols carry the market as a suffix (e.g. "aapl.us"). When the symbol is unknown, Stooq
+// still answers with a row filled with "N/D" values, which can be checked with StooqQuoteDTS.HasData.
+//
+// Parameters:
+//   - requestContext: the context for the HTTP request
+//   - symbol: the Stooq symbol, including the market suffix
+//
+// Returns:
+//   - *StooqQuoteDTS: the quote row for the symbol
+//   - error: an AppError if the request fails, returns a non-200 status, or the CSV is malformed
+//
+// Example:
+//
+//	var client = BuildStooqAssetIntegrationClient(infra.StooqConfiguration{BaseURL: "https://stooq.com"})
+//	quote, err := client.QuoteAsset(context.Background(), "aapl.us")
+//	if err != nil {
+//	    // handle error
+//	}
+//	fmt.Println(quote.Symbol, quote.Close)
+func (client *StooqAssetIntegrationClient) QuoteAsset(
+	requestContext context.Context,
+	symbol string,
+) (*StooqQuoteDTS, error) {
+
+	var requestURL, err = buildStooqURL(
+		client.config.BaseURL,
+		stooqQuotePath,
+		url.Values{"s": {symbol}, "f": {stooqQuoteFields}, "h": {""}, "e": {"csv"}},
+	)
+	if err != nil {
+		return nil, infra.PropagateAsAppError(err, client)
+	}
+
+	records, err := httpclient.ExecuteGetCSV(requestContext, requestURL, client.requestOptions...)
+	if err != nil {
+		return nil, infra.PropagateAsAppError(err, client)
+	}
+
+	var rows = mapCSVRecordsByHeader(records)
+	if len(rows) == 0 {
+		return nil, infra.BuildAppErrorFormatted(client, "Stooq quote response for %s contains no rows", symbol)
+	}
+
+	var row = rows[0]
+	return &StooqQuoteDTS{
+		Symbol: row["Symbol"],
+		Date:   row["Date"],
+		Time:   row["Time"],
+		Open:   row["Open"],
+		High:   row["High"],
+		Low:    row["Low"],
+		Close:  row["Close"],
+		Volume: row["Volume"],
+		Name:   row["Name"],
+	}, nil
+}
+
+// GetAssetDailyHistory queries the Stooq history endpoint for the daily price history of the given
+// symbol, in ascending date order.
+//
+// Parameters:
+//   - requestContext: the context for the HTTP request
+//   - symbol: the Stooq symbol, including the market suffix
+//
+// Returns:
+//   - []StooqHistoryRowDTS: the daily history rows, empty when Stooq has no data for the symbol
+//   - error: an AppError if the request fails, returns a non-200 status, or the CSV is malformed
+//
+// Example:
+//
+//	var client = BuildStooqAssetIntegrationClient(infra.StooqConfiguration{BaseURL: "https://stooq.com"})
+//	history, err := client.GetAssetDailyHistory(context.Background(), "aapl.us")
+func (client *StooqAssetIntegrationClient) GetAssetDailyHistory(
+	requestContext context.Context,
+	symbol string,
+) ([]StooqHistoryRowDTS, error) {
+
+	var requestURL, err = buildStooqURL(
+		client.config.BaseURL,
+		stooqHistoryPath,
+		url.Values{"s": {symbol}, "i": {"d"}},
+	)
+	if err != nil {
+		return nil, infra.PropagateAsAppError(err, client)
+	}
+
+	records, err := httpclient.ExecuteGetCSV(requestContext, requestURL, client.requestOptions...)
+	if err != nil {
+		return nil, infra.PropagateAsAppError(err, client)
+	}
+
+	var rows = mapCSVRecordsByHeader(records)
+	var history = make([]StooqHistoryRowDTS, 0, len(rows))
+	for _, row := range rows {
+		history = append(
+			history,
+			StooqHistoryRowDTS{
+				Date:   row["Date"],
+				Open:   row["Open"],
+				High:   row["High"],
+				Low:    row["Low"],
+				Close:  row["Close"],
+				Volume: row["Volume"],
+			},
+		)
+	}
+
+	return history, nil
+}
+
+// buildStooqURL constructs a full Stooq endpoint URL from the configured base URL, the endpoint path
+// and its query parameters.
+func buildStooqURL(baseURL string, path string, queryParams url.Values) (string, error) {
+
+	var parsedURL, err = url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing Stooq base URL %s: %w", baseURL, err)
+	}
+
+	parsedURL.Path = strings.TrimRight(parsedURL.Path, "/") + path
+	parsedURL.RawQuery = queryParams.Encode()
+
+	return parsedURL.String(), nil
+}
+
+// mapCSVRecordsByHeader converts CSV records into maps keyed by the header row values. Stooq answers
+// unknown history symbols with a single "No data" line, which results in no rows.
+func mapCSVRecordsByHeader(records [][]string) []map[string]string {
+
+	if len(records) < 2 {
+		return nil
+	}
+
+	var header = records[0]
+	var rows = make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		var row = make(map[string]string, len(header))
+		for index, column := range header {
+			if index < len(record) {
+				row[column] = record[index]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// BuildStooqAssetIntegrationClient creates a new StooqAssetIntegrationClient instance.
+//
+// Parameters:
+//   - config: the Stooq endpoint, HTTP resilience and cassette configuration used by the client
+//
+// Returns:
+//   - *StooqAssetIntegrationClient: the new client instance
+//   - error: when the configured cassette cannot be built
+func BuildStooqAssetIntegrationClient(config infra.StooqConfiguration) (*StooqAssetIntegrationClient, error) {
+
+	cassetteOptions, err := buildCassetteRequestOptions(config.Cassette)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestOptions = append(buildResilienceRequestOptions(config.Resilience), cassetteOptions...)
+	return &StooqAssetIntegrationClient{config: config, requestOptions: requestOptions}, nil
+}
@@ -0,0 +1,36 @@
+package integration
+
+// asset_integration_client_stooq_model.go contains the Data Transfer Structures (DTS) that map to
+// the Stooq CSV responses. Values are kept as text because Stooq reports missing data as "N/D".
+
+// StooqNoDataValue is the value Stooq returns in CSV fields when there is no data for the symbol.
+const StooqNoDataValue = "N/D"
+
+// StooqQuoteDTS represents a single row from the Stooq quote CSV endpoint (/q/l/), requested with
+// the symbol, date, time, OHLC, volume and name fields.
+type StooqQuoteDTS struct {
+	Symbol string
+	Date   string
+	Time   string
+	Open   string
+	High   string
+	Low    string
+	Close  string
+	Volume string
+	Name   string
+}
+
+// HasData reports whether Stooq returned a close price for the quoted symbol.
+func (quote *StooqQuoteDTS) HasData() bool {
+	return quote.Close != "" && quote.Close != StooqNoDataValue
+}
+
+// StooqHistoryRowDTS represents a single daily row from the Stooq history CSV endpoint (/q/d/l/).
+type StooqHistoryRowDTS struct {
+	Date   string
+	Open   string
+	High   string
+	Low    string
+	Close  string
+	Volume string
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/benizzio/open-asset-allocator/infra"
@@ -28,7 +29,8 @@ var yahooFinanceDefaultOptions = []httpclient.RequestOption{
 //
 // Authored by: GitHub Copilot (claude-opus-4.6)
 type YahooFinanceAssetIntegrationClient struct {
-	config infra.YahooFinanceConfiguration
+	config         infra.YahooFinanceConfiguration
+	requestOptions []httpclient.RequestOption
 }
 
 // SearchAssets queries the Yahoo Finance search API for assets matching the given query value.
@@ -70,7 +72,7 @@ func (client *YahooFinanceAssetIntegrationClient) SearchAssets(
 	var searchResponse, getErr = httpclient.ExecuteGetJSON[YahooFinanceSearchResponseDTS](
 		searchContext,
 		requestURL,
-		yahooFinanceDefaultOptions...,
+		client.requestOptions...,
 	)
 	if getErr != nil {
 		return nil, infra.PropagateAsAppError(getErr, client)
@@ -111,6 +113,7 @@ func buildSearchAssetsURL(searchURL string, queryValue string) (string, error) {
 // metadata, timestamps, and indicator data.
 //
 // Parameters:
+//   - requestContext: the context for the HTTP request
 //   - ticker: the asset ticker symbol (e.g., "AAPL")
 //
 // Returns:
@@ -123,7 +126,7 @@ func buildSearchAssetsURL(searchURL string, queryValue string) (string, error) {
 //	    SearchURL: "https://query2.finance.yahoo.com/v1/finance/search",
 //	    ChartURL:  "https://query2.finance.yahoo.com/v8/finance/chart/",
 //	})
-//	response, err := client.QuoteAssetLastClosePrice("AAPL")
+//	response, err := client.QuoteAssetLastClosePrice(context.Background(), "AAPL")
 //	if err != nil {
 //	    // handle error
 //	}
@@ -132,6 +135,7 @@ func buildSearchAssetsURL(searchURL string, queryValue string) (string, error) {
 //
 // Authored by: GitHub Copilot (claude-opus-4.6)
 func (client *YahooFinanceAssetIntegrationClient) QuoteAssetLastClosePrice(
+	requestContext context.Context,
 	ticker string,
 ) (*YahooFinanceChartResponseDTS, error) {
 
@@ -141,9 +145,9 @@ func (client *YahooFinanceAssetIntegrationClient) QuoteAssetLastClosePrice(
 	}
 
 	var chartResponse, getErr = httpclient.ExecuteGetJSON[YahooFinanceChartResponseDTS](
-		context.Background(),
+		requestContext,
 		requestURL,
-		yahooFinanceDefaultOptions...,
+		client.requestOptions...,
 	)
 	if getErr != nil {
 		return nil, infra.PropagateAsAppError(getErr, client)
@@ -174,17 +178,81 @@ func buildQuoteAssetLastClosePriceURL(chartURL string, ticker string) (string, e
 	return parsedURL.String(), nil
 }
 
+// GetAssetEvents queries the Yahoo Finance chart API for the full daily history of the asset
+// identified by the given ticker, including its dividend and split events.
+//
+// Parameters:
+//   - requestContext: the context for the HTTP request
+//   - ticker: the asset ticker symbol (e.g., "AAPL")
+//
+// Returns:
+//   - *YahooFinanceChartResponseDTS: the decoded chart response, with the events of each result
+//   - error: an AppError if the request fails, returns a non-200 status, or decoding fails
+//
+// Example:
+//
+//	response, err := client.GetAssetEvents(context.Background(), "AAPL")
+//	if err != nil {
+//	    // handle error
+//	}
+//	for _, dividend := range response.Chart.Result[0].Events.Dividends {
+//	    fmt.Println(dividend.Date, dividend.Amount)
+//	}
+func (client *YahooFinanceAssetIntegrationClient) GetAssetEvents(
+	requestContext context.Context,
+	ticker string,
+) (*YahooFinanceChartResponseDTS, error) {
+
+	var requestURL, err = buildGetAssetEventsURL(client.config.ChartURL, ticker)
+	if err != nil {
+		return nil, infra.PropagateAsAppError(err, client)
+	}
+
+	var chartResponse, getErr = httpclient.ExecuteGetJSON[YahooFinanceChartResponseDTS](
+		requestContext,
+		requestURL,
+		client.requestOptions...,
+	)
+	if getErr != nil {
+		return nil, infra.PropagateAsAppError(getErr, client)
+	}
+
+	return chartResponse, nil
+}
+
+// buildGetAssetEventsURL constructs the Yahoo Finance chart URL requesting the dividend and split
+// events of the whole history of the given ticker.
+func buildGetAssetEventsURL(chartURL string, ticker string) (string, error) {
+
+	var parsedURL, err = url.Parse(chartURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing Yahoo Finance chart URL %s: %w", chartURL, err)
+	}
+
+	parsedURL.Path = strings.TrimRight(parsedURL.Path, "/") + "/" + url.PathEscape(ticker)
+
+	var queryParams = parsedURL.Query()
+	queryParams.Set("interval", "1d")
+	queryParams.Set("range", "max")
+	queryParams.Set("events", "div,split")
+
+	parsedURL.RawQuery = queryParams.Encode()
+
+	return parsedURL.String(), nil
+}
+
 // BuildYahooFinanceAssetIntegrationClient creates a new YahooFinanceAssetIntegrationClient instance.
 //
 // Parameters:
-//   - config: the Yahoo Finance endpoint configuration used by the client
+//   - config: the Yahoo Finance endpoint, HTTP resilience and cassette configuration used by the client
 //
 // Returns:
 //   - *YahooFinanceAssetIntegrationClient: the new client instance
+//   - error: when the configured cassette cannot be built
 //
 // Example:
 //
-//	var client = integration.BuildYahooFinanceAssetIntegrationClient(infra.YahooFinanceConfiguration{
+//	client, err := integration.BuildYahooFinanceAssetIntegrationClient(infra.YahooFinanceConfiguration{
 //	    SearchURL: "https://query2.finance.yahoo.com/v1/finance/search",
 //	    ChartURL:  "https://query2.finance.yahoo.com/v8/finance/chart/",
 //	})
@@ -193,6 +261,18 @@ func buildQuoteAssetLastClosePriceURL(chartURL string, ticker string) (string, e
 // Authored by: GitHub Copilot (claude-opus-4.6)
 func BuildYahooFinanceAssetIntegrationClient(
 	config infra.YahooFinanceConfiguration,
-) *YahooFinanceAssetIntegrationClient {
-	return &YahooFinanceAssetIntegrationClient{config: config}
+) (*YahooFinanceAssetIntegrationClient, error) {
+
+	cassetteOptions, err := buildCassetteRequestOptions(config.Cassette)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestOptions = append(
+		slices.Clone(yahooFinanceDefaultOptions),
+		buildResilienceRequestOptions(config.Resilience)...,
+	)
+	requestOptions = append(requestOptions, cassetteOptions...)
+
+	return &YahooFinanceAssetIntegrationClient{config: config, requestOptions: requestOptions}, nil
 }
@@ -11,10 +11,11 @@ package integration
 //
 // Authored by: GitHub Copilot (claude-opus-4.6)
 type YahooFinanceSearchQuoteDTS struct {
-	Symbol   string `json:"symbol"`
-	Exchange string `json:"exchange"`
-	LongName string `json:"longname"`
-	ExchDisp string `json:"exchDisp"`
+	Symbol    string `json:"symbol"`
+	Exchange  string `json:"exchange"`
+	LongName  string `json:"longname"`
+	ExchDisp  string `json:"exchDisp"`
+	QuoteType string `json:"quoteType"`
 }
 
 // YahooFinanceSearchResponseDTS represents the top-level response from the Yahoo Finance search API.
@@ -59,6 +60,30 @@ type YahooFinanceChartResultDTS struct {
 	Meta       YahooFinanceChartMetaDTS       `json:"meta"`
 	Timestamps []int64                        `json:"timestamp"`
 	Indicators YahooFinanceChartIndicatorsDTS `json:"indicators"`
+	Events     *YahooFinanceChartEventsDTS    `json:"events"`
+}
+
+// YahooFinanceChartDividendDTS represents a dividend event of the Yahoo Finance chart API response,
+// with the amount paid per share and the ex-dividend date as a unix timestamp.
+type YahooFinanceChartDividendDTS struct {
+	Amount float64 `json:"amount"`
+	Date   int64   `json:"date"`
+}
+
+// YahooFinanceChartSplitDTS represents a split event of the Yahoo Finance chart API response. The
+// numerator is the number of shares after the split and the denominator the number before it.
+type YahooFinanceChartSplitDTS struct {
+	Date        int64   `json:"date"`
+	Numerator   float64 `json:"numerator"`
+	Denominator float64 `json:"denominator"`
+	SplitRatio  string  `json:"splitRatio"`
+}
+
+// YahooFinanceChartEventsDTS represents the events section of a Yahoo Finance chart result, present
+// when requested through the events query parameter. Events are keyed by their unix timestamp.
+type YahooFinanceChartEventsDTS struct {
+	Dividends map[string]YahooFinanceChartDividendDTS `json:"dividends"`
+	Splits    map[string]YahooFinanceChartSplitDTS    `json:"splits"`
 }
 
 // YahooFinanceChartDTS represents the chart wrapper object
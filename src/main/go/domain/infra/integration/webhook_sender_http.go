@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/util/http/header"
+	"github.com/benizzio/open-asset-allocator/infra/util/http/httpclient"
+	"github.com/benizzio/open-asset-allocator/infra/util/http/mimetype"
+)
+
+// maxDrainedResponseBytes bounds how much of a webhook response is read before closing it, so the
+// connection can be reused without reading an unbounded response.
+const maxDrainedResponseBytes = 64 * 1024
+
+// WebhookHTTPSender is a domain.WebhookSender posting the webhook requests as JSON over HTTP, with the
+// configured request timeout. Redirects are not followed, so a delivery is only accepted by the
+// registered URL itself.
+type WebhookHTTPSender struct {
+	client *http.Client
+}
+
+// Send posts the request and returns the status code of the response, whose body is discarded.
+//
+// Example:
+//
+//	statusCode, err := sender.Send(ctx, &domain.WebhookRequest{URL: "https://example.com/hook", Body: body})
+func (sender *WebhookHTTPSender) Send(ctx context.Context, request *domain.WebhookRequest) (int, error) {
+
+	var httpRequest, err = http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		request.URL,
+		bytes.NewReader(request.Body),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error creating webhook request for %s: %w", request.URL, err)
+	}
+
+	httpRequest.Header.Set(header.ContentType, mimetype.ApplicationJSON)
+	for name, value := range request.Headers {
+		httpRequest.Header.Set(name, value)
+	}
+
+	response, err := sender.client.Do(httpRequest)
+	if err != nil {
+		return 0, fmt.Errorf("error posting webhook request to %s: %w", request.URL, err)
+	}
+	defer httpclient.CloseResponseBody(response)
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxDrainedResponseBytes))
+
+	return response.StatusCode, nil
+}
+
+func BuildWebhookHTTPSender(config infra.WebhookConfiguration) *WebhookHTTPSender {
+	return &WebhookHTTPSender{
+		client: &http.Client{
+			Timeout: config.RequestTimeout,
+			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
@@ -10,15 +10,16 @@ import (
 )
 
 type plannedAllocationJoinedRowDTS struct {
-	AllocationPlanId     int64
-	Name                 string
-	Type                 allocation.PlanType
-	PlannedExecutionDate sqlext.NullTime
-	PlannedAllocationId  int64
-	HierarchicalId       sqlext.NullStringSlice
-	CashReserve          bool
-	SliceSizePercentage  decimal.Decimal
-	Asset                *domain.Asset
+	AllocationPlanId      int64
+	Name                  string
+	Type                  allocation.PlanType
+	PlannedExecutionDate  sqlext.NullTime
+	PlannedAllocationId   int64
+	HierarchicalId        sqlext.NullStringSlice
+	CashReserve           bool
+	SliceSizePercentage   decimal.Decimal
+	Asset                 *domain.Asset
+	AllocationPlanVersion int64
 }
 
 func mapPlannedAllocationRows(rows []plannedAllocationJoinedRowDTS) ([]*domain.AllocationPlan, error) {
@@ -85,6 +86,7 @@ func buildAllocationPlanFromRow(
 		},
 		PlanType:             rowDTS.Type,
 		PlannedExecutionDate: rowDTS.PlannedExecutionDate.ToTimeReference(),
+		Version:              rowDTS.AllocationPlanVersion,
 	}
 	allocationPlan.AddDetail(plannedAllocation)
 
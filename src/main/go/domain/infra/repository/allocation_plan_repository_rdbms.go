@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/domain/allocation"
@@ -23,6 +25,7 @@ const (
 		    ap.name, 
 		    ap.type, 
 		    ap.planned_execution_date,
+		    ap.version AS allocation_plan_version,
 		    pa.id AS planned_allocation_id,
 		    pa.hierarchical_id, 
 		    pa.cash_reserve, 
@@ -38,12 +41,13 @@ const (
 	`
 	allocationPlanInsertSQL = `
 		INSERT INTO allocation_plan (portfolio_id, name, type)
-		VALUES ($1, $2, $3) RETURNING id
+		VALUES ($1, $2, $3) RETURNING id, version
     `
 	allocationPlanUpdateSQL = `
 		UPDATE allocation_plan 
-		SET name = $1
-		WHERE id = $2
+		SET name = $1, version = version + 1
+		WHERE id = $2 AND ($3 = 0 OR version = $3)
+		RETURNING version
 	`
 	plannedAllocationTempTableName   = "planned_allocation_merge_temp"
 	plannedAllocationTempTableDDLSQL = `
@@ -74,6 +78,18 @@ const (
 	`
 )
 
+func allocationPlanIdAndVersionSingleRowScanner(row *sql.Row) (domain.AllocationPlan, error) {
+	var plan domain.AllocationPlan
+	scanErr := row.Scan(&plan.Id, &plan.Version)
+	return plan, scanErr
+}
+
+func allocationPlanVersionSingleRowScanner(row *sql.Row) (int64, error) {
+	var version int64
+	scanErr := row.Scan(&version)
+	return version, scanErr
+}
+
 type AllocationPlanRDBMSRepository struct {
 	dbAdapter rdbms.RepositoryRDBMSAdapter
 }
@@ -100,9 +116,13 @@ func (repository *AllocationPlanRDBMSRepository) GetAllAllocationPlans(
 	return mapPlannedAllocationRows(queryResult)
 }
 
-func (repository *AllocationPlanRDBMSRepository) GetAllocationPlan(id int64) (*domain.AllocationPlan, error) {
+func (repository *AllocationPlanRDBMSRepository) GetAllocationPlan(
+	queryContext context.Context,
+	id int64,
+) (*domain.AllocationPlan, error) {
 
-	var queryBuilder = rdbms.BuildQuery[plannedAllocationJoinedRowDTS](repository.dbAdapter, allocationPlanSQL)
+	var queryBuilder = rdbms.BuildQuery[plannedAllocationJoinedRowDTS](repository.dbAdapter, allocationPlanSQL).
+		WithContext(queryContext)
 	queryBuilder.AddWhereClauseAndParam("AND ap.id = {:id}", "id", id)
 
 	var queryResult []plannedAllocationJoinedRowDTS
@@ -164,17 +184,26 @@ func (repository *AllocationPlanRDBMSRepository) InsertAllocationPlanInTransacti
 		)
 	}
 
-	id, err := rdbms.BuildQueryInTransaction[int64](transactionalContext, allocationPlanInsertSQL).
+	insertedPlan, err := rdbms.BuildQueryInTransaction[domain.AllocationPlan](
+		transactionalContext,
+		allocationPlanInsertSQL,
+	).
 		AddParams(plan.PortfolioId, plan.Name, plan.PlanType.String()).
 		Build().
-		Get(rdbms.ReturningIntIdSingleRowScanner)
+		Get(allocationPlanIdAndVersionSingleRowScanner)
 	if err != nil {
 		return infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting allocation plan", repository)
 	}
+	var id = insertedPlan.Id
+	plan.Id = id
+	plan.Version = insertedPlan.Version
 
 	return repository.mergePlannedAllocationsInTransaction(transactionalContext, id, plan.Details)
 }
 
+// UpdateAllocationPlanInTransaction updates an allocation plan and merges its planned allocations within an
+// existing SQL transaction, incrementing its version. With a nonzero version, the plan is only updated if it
+// still has that version, otherwise an infra.VersionConflictError is returned.
 func (repository *AllocationPlanRDBMSRepository) UpdateAllocationPlanInTransaction(
 	transContext context.Context,
 	plan *domain.AllocationPlan,
@@ -188,15 +217,17 @@ func (repository *AllocationPlanRDBMSRepository) UpdateAllocationPlanInTransacti
 		)
 	}
 
-	_, err := repository.dbAdapter.ExecuteInTransaction(
-		transactionalContext,
-		allocationPlanUpdateSQL,
-		plan.Name,
-		plan.Id,
-	)
-	if err != nil {
+	version, err := rdbms.BuildQueryInTransaction[int64](transactionalContext, allocationPlanUpdateSQL).
+		AddParams(plan.Name, plan.Id, plan.Version).
+		Build().
+		Get(allocationPlanVersionSingleRowScanner)
+	if errors.Is(err, sql.ErrNoRows) && !langext.IsZeroValue(plan.Version) {
+		return infra.BuildVersionConflictError("Allocation plan was changed since the requested version")
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return infra.PropagateAsAppErrorWithNewMessage(err, "Error updating allocation plan", repository)
 	}
+	plan.Version = version
 
 	return repository.mergePlannedAllocationsInTransaction(transactionalContext, int64(plan.Id), plan.Details)
 }
@@ -1,8 +1,9 @@
 package repository
 
 import (
+	"log/slog"
+
 	dbx "github.com/go-ozzo/ozzo-dbx"
-	"github.com/golang/glog"
 
 	"github.com/benizzio/open-asset-allocator/infra"
 	"github.com/benizzio/open-asset-allocator/infra/rdbms"
@@ -67,7 +68,7 @@ func (repository *AllocationRDBMSRepository) FindAvailableAllocationClassesFromA
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			glog.Errorf("Error closing rows: %v", closeErr)
+			slog.Error("Error closing rows", "error", closeErr)
 		}
 	}()
 
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+)
+
+// AssetIntegrationCacheMemoryRepository is an in-process domain.AssetIntegrationCacheRepository.
+// Its entries are lost when the application stops and are not shared between instances.
+type AssetIntegrationCacheMemoryRepository struct {
+	mutex   sync.RWMutex
+	entries map[string]domain.AssetIntegrationCacheEntry
+}
+
+func (repository *AssetIntegrationCacheMemoryRepository) FindCacheEntry(
+	_ context.Context,
+	cacheKey string,
+) (*domain.AssetIntegrationCacheEntry, error) {
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var entry, exists = repository.entries[cacheKey]
+	if !exists {
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+func (repository *AssetIntegrationCacheMemoryRepository) MergeCacheEntry(
+	_ context.Context,
+	entry *domain.AssetIntegrationCacheEntry,
+) error {
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.entries[entry.CacheKey] = *entry
+	return nil
+}
+
+func (repository *AssetIntegrationCacheMemoryRepository) DeleteCacheEntriesStoredBefore(
+	_ context.Context,
+	instant time.Time,
+) error {
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for cacheKey, entry := range repository.entries {
+		if entry.StoredAt.Before(instant) {
+			delete(repository.entries, cacheKey)
+		}
+	}
+
+	return nil
+}
+
+func BuildAssetIntegrationCacheMemoryRepository() *AssetIntegrationCacheMemoryRepository {
+	return &AssetIntegrationCacheMemoryRepository{
+		entries: make(map[string]domain.AssetIntegrationCacheEntry),
+	}
+}
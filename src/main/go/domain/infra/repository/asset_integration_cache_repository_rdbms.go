@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+)
+
+const (
+	findAssetIntegrationCacheEntrySQL = `
+		SELECT cache_key, payload, stored_at
+		FROM asset_integration_cache
+		WHERE cache_key = {:cacheKey}
+	`
+	mergeAssetIntegrationCacheEntrySQL = `
+		INSERT INTO asset_integration_cache (cache_key, payload, stored_at)
+		VALUES ({:cacheKey}, {:payload}, {:storedAt})
+		ON CONFLICT (cache_key)
+		DO UPDATE SET payload = EXCLUDED.payload, stored_at = EXCLUDED.stored_at
+		RETURNING cache_key, payload, stored_at
+	`
+	deleteAssetIntegrationCacheEntriesSQL = `
+		WITH deleted AS (
+			DELETE FROM asset_integration_cache WHERE stored_at < {:instant} RETURNING cache_key
+		)
+		SELECT count(*) AS count FROM deleted
+	`
+)
+
+type deletedRowsCountDTS struct {
+	Count int64
+}
+
+// AssetIntegrationCacheRDBMSRepository is a domain.AssetIntegrationCacheRepository persisting the
+// entries in the asset_integration_cache table, so they survive restarts and are shared between
+// application instances.
+type AssetIntegrationCacheRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+func (repository *AssetIntegrationCacheRDBMSRepository) FindCacheEntry(
+	_ context.Context,
+	cacheKey string,
+) (*domain.AssetIntegrationCacheEntry, error) {
+
+	var result domain.AssetIntegrationCacheEntry
+	err := rdbms.BuildQuery[domain.AssetIntegrationCacheEntry](repository.dbAdapter, findAssetIntegrationCacheEntrySQL).
+		AddParam("cacheKey", cacheKey).
+		Build().
+		GetInto(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting asset integration cache entry", repository)
+	}
+
+	return &result, nil
+}
+
+func (repository *AssetIntegrationCacheRDBMSRepository) MergeCacheEntry(
+	_ context.Context,
+	entry *domain.AssetIntegrationCacheEntry,
+) error {
+
+	var result domain.AssetIntegrationCacheEntry
+	err := rdbms.BuildQuery[domain.AssetIntegrationCacheEntry](repository.dbAdapter, mergeAssetIntegrationCacheEntrySQL).
+		AddParam("cacheKey", entry.CacheKey).
+		AddParam("payload", entry.Payload).
+		AddParam("storedAt", entry.StoredAt).
+		Build().
+		GetInto(&result)
+
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error merging asset integration cache entry", repository)
+}
+
+func (repository *AssetIntegrationCacheRDBMSRepository) DeleteCacheEntriesStoredBefore(
+	_ context.Context,
+	instant time.Time,
+) error {
+
+	var result deletedRowsCountDTS
+	err := rdbms.BuildQuery[deletedRowsCountDTS](repository.dbAdapter, deleteAssetIntegrationCacheEntriesSQL).
+		AddParam("instant", instant).
+		Build().
+		GetInto(&result)
+
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting asset integration cache entries", repository)
+}
+
+func BuildAssetIntegrationCacheRDBMSRepository(
+	dbAdapter rdbms.RepositoryRDBMSAdapter,
+) *AssetIntegrationCacheRDBMSRepository {
+	return &AssetIntegrationCacheRDBMSRepository{
+		dbAdapter: dbAdapter,
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+)
+
+var assetRecordUpdatableFields = []string{"Ticker", "Name", "InstrumentType", "Currency", "ISIN", "CUSIP", "Exchange"}
+
+// assetRecord is the write model of the asset table, storing empty metadata as NULL.
+type assetRecord struct {
+	Id             int64
+	Ticker         string
+	Name           string
+	InstrumentType sql.NullString
+	Currency       sql.NullString
+	ISIN           sql.NullString
+	CUSIP          sql.NullString
+	Exchange       sql.NullString
+	ExternalData   *domain.ExternalAssetData
+}
+
+func (assetRecord) TableName() string {
+	return "asset"
+}
+
+func buildAssetRecord(asset *domain.Asset) assetRecord {
+	return assetRecord{
+		Id:             asset.Id,
+		Ticker:         asset.Ticker,
+		Name:           asset.Name,
+		InstrumentType: toNullString(string(asset.InstrumentType)),
+		Currency:       toNullString(asset.Currency),
+		ISIN:           toNullString(asset.ISIN),
+		CUSIP:          toNullString(asset.CUSIP),
+		Exchange:       toNullString(asset.Exchange),
+		ExternalData:   asset.ExternalData,
+	}
+}
+
+func toNullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// toNullableValue converts empty strings to nil so that bulk inserts persist them as NULL.
+func toNullableValue(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -3,8 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 
 	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/infra"
@@ -14,12 +19,251 @@ import (
 
 const (
 	assetsSQL = `
-		SELECT id, ticker, name, external_data FROM asset
+		SELECT
+			id, ticker, name, coalesce(instrument_type, ''), coalesce(currency, ''), coalesce(isin, ''), coalesce(cusip, ''),
+			coalesce(exchange, ''), external_data, version
+		FROM asset
 	` + rdbms.WhereClausePlaceholder
+	assetForUpdateSQL = `
+		SELECT
+			id, ticker, name, coalesce(instrument_type, ''), coalesce(currency, ''), coalesce(isin, ''), coalesce(cusip, ''),
+			coalesce(exchange, ''), external_data, version
+		FROM asset
+		WHERE id = $1
+		FOR UPDATE
+	`
+	// assetByUniqueIdentifierSQL gives priority to the current ticker, then to the id, falling back to the
+	// most recent alias when the identifier is a previous ticker
+	assetByUniqueIdentifierSQL = `
+		SELECT
+			ass.id, ass.ticker, ass.name, coalesce(ass.instrument_type, ''), coalesce(ass.currency, ''),
+			coalesce(ass.isin, ''), coalesce(ass.cusip, ''), coalesce(ass.exchange, ''), ass.external_data,
+			ass.version
+		FROM asset ass
+		LEFT JOIN asset_alias aa ON aa.asset_id = ass.id AND aa.ticker = {:uniqueIdentifier}
+	` + rdbms.WhereClausePlaceholder + `
+		ORDER BY
+			ass.ticker = {:uniqueIdentifier} DESC,
+			ass.id::text = {:uniqueIdentifier} DESC,
+			aa.valid_to DESC NULLS FIRST,
+			aa.valid_from DESC NULLS LAST
+		LIMIT 1
+	`
+	// assetsPerLookupTickerSQL resolves each requested ticker ($1) to a single asset through the current
+	// tickers and the aliases valid at the reference date ($2). With a reference date, a valid alias has
+	// priority over a current ticker (the ticker may have been reused by another asset since). Without a
+	// reference date, current tickers have priority and aliases are considered regardless of validity.
+	assetsPerLookupTickerSQL = `
+		SELECT DISTINCT ON (atl.lookup_ticker)
+			atl.lookup_ticker, ass.id, ass.ticker, ass.name, coalesce(ass.instrument_type, ''),
+			coalesce(ass.currency, ''), coalesce(ass.isin, ''), coalesce(ass.cusip, ''), coalesce(ass.exchange, ''),
+			ass.external_data
+		FROM asset_ticker_lookup atl
+		JOIN asset ass ON ass.id = atl.asset_id
+		WHERE atl.lookup_ticker = ANY($1)
+			AND (
+				$2::date IS NULL
+				OR (
+					(atl.valid_from IS NULL OR atl.valid_from <= $2::date)
+					AND (atl.valid_to IS NULL OR atl.valid_to >= $2::date)
+				)
+			)
+		ORDER BY
+			atl.lookup_ticker,
+			CASE WHEN $2::date IS NULL THEN atl.alias ELSE NOT atl.alias END,
+			atl.valid_to DESC NULLS FIRST,
+			atl.valid_from DESC NULLS LAST
+	`
+	// portfolioAssetsSQL selects the assets held in the latest observation of a portfolio
+	portfolioAssetsSQL = `
+		SELECT
+			id, ticker, name, coalesce(instrument_type, ''), coalesce(currency, ''), coalesce(isin, ''), coalesce(cusip, ''),
+			coalesce(exchange, ''), external_data, version
+		FROM asset
+		WHERE id IN (
+			SELECT pa.asset_id
+			FROM portfolio_allocation_fact pa
+			WHERE pa.portfolio_id = {:portfolioId}
+				AND pa.observation_time_id = (
+					SELECT paot.id
+					FROM portfolio_allocation_fact latest_pa
+					JOIN portfolio_allocation_obs_time paot ON latest_pa.observation_time_id = paot.id
+					WHERE latest_pa.portfolio_id = {:portfolioId}
+					ORDER BY paot.observation_timestamp DESC
+					LIMIT 1
+				)
+		)
+		ORDER BY ticker
+	`
+	assetAliasesSQL = `
+		SELECT id, asset_id, ticker, source, valid_from, valid_to
+		FROM asset_alias
+		WHERE asset_id = {:assetId}
+		ORDER BY valid_from NULLS FIRST, id
+	`
+	updateAssetTickerSQL = `
+		UPDATE asset SET ticker = $1, version = version + 1 WHERE id = $2
+	`
+	updateAssetExternalDataSQL = `
+		UPDATE asset
+		SET external_data = $1, instrument_type = $2, exchange = $3, version = version + 1
+		WHERE id = $4 AND ($5 = 0 OR version = $5)
+		RETURNING
+			id, ticker, name, coalesce(instrument_type, ''), coalesce(currency, ''), coalesce(isin, ''), coalesce(cusip, ''),
+			coalesce(exchange, ''), external_data, version
+	`
+	updateAssetSQL = `
+		UPDATE asset
+		SET
+			ticker = $1, name = $2, instrument_type = $3, currency = $4, isin = $5, cusip = $6, exchange = $7,
+			version = version + 1
+		WHERE id = $8 AND ($9 = 0 OR version = $9)
+	`
+	insertAssetAliasSQL = `
+		INSERT INTO asset_alias (asset_id, ticker, source, valid_from, valid_to)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	deleteAssetAliasSQL = `
+		DELETE FROM asset_alias WHERE id = $1 AND asset_id = $2
+	`
+	assetValuationsSQL = `
+		SELECT id, asset_id, valuation_date, price, currency
+		FROM asset_valuation
+		WHERE asset_id = {:assetId}
+		ORDER BY valuation_date DESC
+	`
+	latestAssetValuationSQL = assetValuationsSQL + `
+		LIMIT 1
+	`
+	mergeAssetValuationSQL = `
+		INSERT INTO asset_valuation (asset_id, valuation_date, price, currency)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (asset_id, valuation_date)
+		DO UPDATE SET price = EXCLUDED.price, currency = EXCLUDED.currency
+		RETURNING id, asset_id, valuation_date, price, currency
+	`
+	deleteAssetValuationSQL = `
+		DELETE FROM asset_valuation WHERE id = $1 AND asset_id = $2
+	`
+	assetEventColumnsSQL = `
+		id, asset_id, event_type, event_date, source, coalesce(amount, 0) AS amount,
+		coalesce(currency, '') AS currency, coalesce(split_numerator, 0) AS split_numerator,
+		coalesce(split_denominator, 0) AS split_denominator
+	`
+	assetEventsSQL = `
+		SELECT ` + assetEventColumnsSQL + `
+		FROM asset_event
+		WHERE asset_id = ANY({:assetIds})
+		ORDER BY asset_id, event_date, event_type
+	`
+	// mergeAssetEventsSQL upserts the events given as a JSON array, so an import is applied in a single
+	// statement and repeated imports update the events already recorded
+	mergeAssetEventsSQL = `
+		INSERT INTO asset_event (
+			asset_id, event_type, event_date, source, amount, currency, split_numerator, split_denominator
+		)
+		SELECT
+			{:assetId}, imported.event_type, imported.event_date, imported.source, imported.amount,
+			imported.currency, imported.split_numerator, imported.split_denominator
+		FROM jsonb_to_recordset({:events}::jsonb) AS imported(
+			event_type varchar, event_date date, source varchar, amount numeric, currency varchar,
+			split_numerator numeric, split_denominator numeric
+		)
+		ON CONFLICT (asset_id, event_type, event_date)
+		DO UPDATE SET
+			source = EXCLUDED.source,
+			amount = EXCLUDED.amount,
+			currency = EXCLUDED.currency,
+			split_numerator = EXCLUDED.split_numerator,
+			split_denominator = EXCLUDED.split_denominator
+		RETURNING ` + assetEventColumnsSQL + `
+	`
+	// portfolioDividendIncomeSQL pairs each dividend of the assets of a portfolio with the quantity held in
+	// the latest portfolio observation up to the ex-dividend date. Dividends of assets not held in that
+	// observation produce no income.
+	portfolioDividendIncomeSQL = `
+		WITH portfolio_observation AS (
+			SELECT DISTINCT paot.id, paot.observation_timestamp
+			FROM portfolio_allocation_fact pa
+			JOIN portfolio_allocation_obs_time paot ON pa.observation_time_id = paot.id
+			WHERE pa.portfolio_id = {:portfolioId}
+		)
+		SELECT
+			ass.id, ass.ticker, ass.name, ae.event_date, ae.amount, ae.currency, sum(pa.asset_quantity),
+			obs.observation_timestamp
+		FROM asset_event ae
+		JOIN asset ass ON ass.id = ae.asset_id
+		JOIN LATERAL (
+			SELECT po.id, po.observation_timestamp
+			FROM portfolio_observation po
+			WHERE po.observation_timestamp < ae.event_date + 1
+			ORDER BY po.observation_timestamp DESC
+			LIMIT 1
+		) obs ON TRUE
+		JOIN portfolio_allocation_fact pa
+			ON pa.portfolio_id = {:portfolioId}
+			AND pa.observation_time_id = obs.id
+			AND pa.asset_id = ae.asset_id
+		WHERE ae.event_type = 'DIVIDEND'
+		GROUP BY ass.id, ass.ticker, ass.name, ae.event_date, ae.amount, ae.currency, obs.observation_timestamp
+		HAVING sum(pa.asset_quantity) > 0
+		ORDER BY ae.event_date DESC, ass.ticker
+	`
 )
 
-// assetRowScanner reads a persisted asset row, including its optional external data payload,
-// into the domain model.
+// assetEventImportRecord is the JSON representation of an imported event for the bulk merge of asset
+// events, with NULL for the fields that do not apply to the event type.
+type assetEventImportRecord struct {
+	EventType        domain.AssetEventType      `json:"event_type"`
+	EventDate        string                     `json:"event_date"`
+	Source           domain.AssetExternalSource `json:"source"`
+	Amount           *decimal.Decimal           `json:"amount"`
+	Currency         *string                    `json:"currency"`
+	SplitNumerator   *decimal.Decimal           `json:"split_numerator"`
+	SplitDenominator *decimal.Decimal           `json:"split_denominator"`
+}
+
+func buildAssetEventImportRecord(event *domain.AssetEvent) assetEventImportRecord {
+
+	var record = assetEventImportRecord{
+		EventType: event.EventType,
+		EventDate: event.EventDate.Format(time.DateOnly),
+		Source:    event.Source,
+	}
+
+	switch event.EventType {
+	case domain.DividendAssetEventType:
+		record.Amount = &event.Amount
+		record.Currency = &event.Currency
+	case domain.SplitAssetEventType:
+		record.SplitNumerator = &event.SplitNumerator
+		record.SplitDenominator = &event.SplitDenominator
+	}
+
+	return record
+}
+
+// assetIncomeRecordRowScanner reads a portfolio dividend income row into the domain model.
+func assetIncomeRecordRowScanner(rows *sql.Rows) (domain.AssetIncomeRecord, error) {
+
+	var record domain.AssetIncomeRecord
+	err := rows.Scan(
+		&record.Asset.Id,
+		&record.Asset.Ticker,
+		&record.Asset.Name,
+		&record.ExDividendDate,
+		&record.AmountPerShare,
+		&record.Currency,
+		&record.AssetQuantity,
+		&record.ObservationTimestamp,
+	)
+
+	return record, err
+}
+
+// assetRowScanner reads a persisted asset row, including its metadata and optional external data
+// payload, into the domain model.
 //
 // Co-authored by: OpenCode and Igor Benicio de Mesquita
 func assetRowScanner(rows *sql.Rows) (domain.Asset, error) {
@@ -32,7 +276,13 @@ func assetRowScanner(rows *sql.Rows) (domain.Asset, error) {
 		&asset.Id,
 		&asset.Ticker,
 		&asset.Name,
+		&asset.InstrumentType,
+		&asset.Currency,
+		&asset.ISIN,
+		&asset.CUSIP,
+		&asset.Exchange,
 		&externalDataValue,
+		&asset.Version,
 	)
 	if scanErr != nil {
 		return asset, scanErr
@@ -49,6 +299,46 @@ func assetRowScanner(rows *sql.Rows) (domain.Asset, error) {
 	return asset, scanErr
 }
 
+type assetPerLookupTicker struct {
+	lookupTicker string
+	asset        domain.Asset
+}
+
+// assetPerLookupTickerRowScanner reads a ticker resolution row, pairing the looked up ticker with
+// the asset it resolved to.
+func assetPerLookupTickerRowScanner(rows *sql.Rows) (assetPerLookupTicker, error) {
+
+	var result assetPerLookupTicker
+	var externalDataValue interface{}
+
+	scanErr := rows.Scan(
+		&result.lookupTicker,
+		&result.asset.Id,
+		&result.asset.Ticker,
+		&result.asset.Name,
+		&result.asset.InstrumentType,
+		&result.asset.Currency,
+		&result.asset.ISIN,
+		&result.asset.CUSIP,
+		&result.asset.Exchange,
+		&externalDataValue,
+	)
+	if scanErr != nil {
+		return result, scanErr
+	}
+
+	if externalDataValue != nil {
+		var externalData domain.ExternalAssetData
+		scanErr = externalData.Scan(externalDataValue)
+		if scanErr != nil {
+			return result, scanErr
+		}
+		result.asset.ExternalData = &externalData
+	}
+
+	return result, nil
+}
+
 type AssetRDBMSRepository struct {
 	dbAdapter rdbms.RepositoryRDBMSAdapter
 }
@@ -74,8 +364,31 @@ func (repository *AssetRDBMSRepository) GetKnownAssets() ([]*domain.Asset, error
 	return langext.ToPointerSlice(result), nil
 }
 
+// FindPortfolioAssets retrieves the assets held in the latest observation of a portfolio, ordered by
+// ticker. Returns an empty slice when the portfolio has no allocation history.
+//
+// Example:
+//
+//	assets, err := assetRepository.FindPortfolioAssets(1)
+func (repository *AssetRDBMSRepository) FindPortfolioAssets(portfolioId int64) ([]*domain.Asset, error) {
+
+	var queryExecutor = rdbms.BuildQuery[domain.Asset](repository.dbAdapter, portfolioAssetsSQL).
+		AddParam("portfolioId", portfolioId).
+		Build()
+
+	result, err := queryExecutor.FindWithRowScanner(assetRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio assets", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
 // FindAssetByUniqueIdentifier retrieves a single asset by numeric id or ticker. Numeric input is
-// matched against both columns to preserve the existing lookup behavior.
+// matched against both columns to preserve the existing lookup behavior, so the identifier resolves, in
+// order of priority, to the asset with it as current ticker, to the asset with it as id, and to the
+// asset with it as alias, preferring the most recent one. Callers holding an asset id use FindAssetById,
+// which is not ambiguous with numeric tickers.
 //
 // Example:
 //
@@ -84,13 +397,13 @@ func (repository *AssetRDBMSRepository) GetKnownAssets() ([]*domain.Asset, error
 // Co-authored by: OpenCode and Igor Benicio de Mesquita
 func (repository *AssetRDBMSRepository) FindAssetByUniqueIdentifier(uniqueIdentifier string) (*domain.Asset, error) {
 
-	var queryBuilder = rdbms.BuildQuery[domain.Asset](repository.dbAdapter, assetsSQL)
+	var queryBuilder = rdbms.BuildQuery[domain.Asset](repository.dbAdapter, assetByUniqueIdentifierSQL)
 
 	var whereClause string
 	if _, err := strconv.Atoi(uniqueIdentifier); err == nil {
-		whereClause = "AND (id = {:uniqueIdentifier} OR ticker = {:uniqueIdentifier})"
+		whereClause = "AND (ass.id = {:uniqueIdentifier} OR ass.ticker = {:uniqueIdentifier} OR aa.id IS NOT NULL)"
 	} else {
-		whereClause = "AND ticker = {:uniqueIdentifier}"
+		whereClause = "AND (ass.ticker = {:uniqueIdentifier} OR aa.id IS NOT NULL)"
 	}
 
 	queryBuilder.AddWhereClauseAndParam(
@@ -115,28 +428,176 @@ func (repository *AssetRDBMSRepository) FindAssetByUniqueIdentifier(uniqueIdenti
 	return &result, nil
 }
 
-// UpdateAsset updates the ticker and name fields of an existing asset identified by its ID.
-// Returns the freshly-read updated asset from the database.
+// FindAssetById retrieves the asset with the given id, or nil when it does not exist.
 //
 // Example:
 //
-//	updatedAsset, err := assetRepository.UpdateAsset(asset)
+//	asset, err := assetRepository.FindAssetById(1)
+func (repository *AssetRDBMSRepository) FindAssetById(id int64) (*domain.Asset, error) {
+
+	result, err := rdbms.BuildQuery[domain.Asset](repository.dbAdapter, assetsSQL).
+		AddWhereClauseAndParam("AND id = {:id}", "id", id).
+		Build().
+		GetWithRowScanner(assetRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting asset by id", repository)
+	}
+
+	return &result, nil
+}
+
+// FindAssetForUpdateInTransaction retrieves the asset with the given id within an existing SQL transaction,
+// locking it until the end of the transaction. Returns nil when it does not exist.
+//
+// Example:
 //
-// Co-authored by: OpenCode and GitHub Copilot
-func (repository *AssetRDBMSRepository) UpdateAsset(asset *domain.Asset) (*domain.Asset, error) {
+//	asset, err := assetRepository.FindAssetForUpdateInTransaction(transContext, 1)
+func (repository *AssetRDBMSRepository) FindAssetForUpdateInTransaction(
+	transContext context.Context,
+	id int64,
+) (*domain.Asset, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
 
-	err := repository.dbAdapter.UpdateListedFields(asset, "Ticker", "Name")
+	assets, err := rdbms.BuildQueryInTransaction[domain.Asset](transactionalContext, assetForUpdateSQL).
+		AddParams(id).
+		Build().
+		Find(assetRowScanner)
 	if err != nil {
-		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating asset", repository)
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting asset by id", repository)
 	}
 
-	var updatedAsset domain.Asset
-	err = repository.dbAdapter.Read(&updatedAsset, asset.Id)
-	return &updatedAsset, infra.PropagateAsAppErrorWithNewMessage(
-		err,
-		"Error retrieving updated asset",
-		repository,
+	if len(assets) == 0 {
+		return nil, nil
+	}
+
+	return &assets[0], nil
+}
+
+// UpdateAssetInTransaction updates the ticker, name and metadata fields of an existing asset identified by
+// its ID within an existing SQL transaction, incrementing its version. Empty metadata is persisted as NULL.
+// With a nonzero version, the asset is only updated if it still has that version, otherwise an
+// infra.VersionConflictError is returned. Returns the updated asset read within the transaction, or nil
+// when it does not exist.
+//
+// Example:
+//
+//	updatedAsset, err := assetRepository.UpdateAssetInTransaction(transContext, asset)
+func (repository *AssetRDBMSRepository) UpdateAssetInTransaction(
+	transContext context.Context,
+	asset *domain.Asset,
+) (*domain.Asset, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	var record = buildAssetRecord(asset)
+	result, err := repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		updateAssetSQL,
+		record.Ticker,
+		record.Name,
+		record.InstrumentType,
+		record.Currency,
+		record.ISIN,
+		record.CUSIP,
+		record.Exchange,
+		record.Id,
+		asset.Version,
 	)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating asset", repository)
+	}
+
+	updatedCount, err := result.RowsAffected()
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating asset", repository)
+	}
+	if updatedCount == 0 && !langext.IsZeroValue(asset.Version) {
+		return nil, infra.BuildVersionConflictError("Asset was changed since the requested version")
+	}
+
+	updatedAssets, err := rdbms.BuildQueryInTransaction[domain.Asset](transactionalContext, assetsSQL).
+		AddWhereClauseAndParams("AND id = $1", asset.Id).
+		Build().
+		Find(assetRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error retrieving updated asset", repository)
+	}
+
+	if len(updatedAssets) == 0 {
+		return nil, nil
+	}
+
+	return &updatedAssets[0], nil
+}
+
+// UpdateAssetExternalDataInTransaction replaces the linked external assets of an existing asset within an
+// existing SQL transaction, persisting NULL when no external asset remains linked, along with the
+// instrument type and exchange filled from them, and increments its version. A non-zero asset version must
+// match the persisted one. Returns the updated asset, or nil when it does not exist.
+//
+// Example:
+//
+//	updatedAsset, err := assetRepository.UpdateAssetExternalDataInTransaction(transContext, asset)
+func (repository *AssetRDBMSRepository) UpdateAssetExternalDataInTransaction(
+	transContext context.Context,
+	asset *domain.Asset,
+) (*domain.Asset, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	var externalDataValue interface{}
+	if asset.ExternalData != nil && len(asset.ExternalData.Data) > 0 {
+		var err error
+		externalDataValue, err = asset.ExternalData.Value()
+		if err != nil {
+			return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating asset external data", repository)
+		}
+	}
+
+	updatedAssets, err := rdbms.BuildQueryInTransaction[domain.Asset](transactionalContext, updateAssetExternalDataSQL).
+		AddParams(
+			externalDataValue,
+			toNullableValue(string(asset.InstrumentType)),
+			toNullableValue(asset.Exchange),
+			asset.Id,
+			asset.Version,
+		).
+		Build().
+		Find(assetRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating asset external data", repository)
+	}
+
+	if len(updatedAssets) == 0 && !langext.IsZeroValue(asset.Version) {
+		return nil, infra.BuildVersionConflictError("Asset was changed since the requested version")
+	}
+	if len(updatedAssets) == 0 {
+		return nil, nil
+	}
+
+	return &updatedAssets[0], nil
 }
 
 // InsertAssetsInTransaction bulk-inserts new assets within an existing SQL transaction,
@@ -168,7 +629,7 @@ func (repository *AssetRDBMSRepository) InsertAssetsInTransaction(
 		return assets, nil
 	}
 
-	var columns = []string{"ticker", "name", "external_data"}
+	var columns = []string{"ticker", "name", "instrument_type", "currency", "isin", "cusip", "exchange", "external_data"}
 
 	values, tickers, err := buildAssetInsertValues(assets)
 	if err != nil {
@@ -212,8 +673,8 @@ func buildAssetInsertValues(assets []*domain.Asset) ([][]interface{}, []string,
 	return values, tickers, nil
 }
 
-// buildAssetInsertValue prepares a single asset bulk insert row, including the optional external
-// data payload.
+// buildAssetInsertValue prepares a single asset bulk insert row, including the metadata and the
+// optional external data payload.
 //
 // Authored by: OpenCode
 func buildAssetInsertValue(asset *domain.Asset) ([]interface{}, error) {
@@ -229,15 +690,60 @@ func buildAssetInsertValue(asset *domain.Asset) ([]interface{}, error) {
 	return []interface{}{
 		asset.Ticker,
 		asset.Name,
+		toNullableValue(string(asset.InstrumentType)),
+		toNullableValue(asset.Currency),
+		toNullableValue(asset.ISIN),
+		toNullableValue(asset.CUSIP),
+		toNullableValue(asset.Exchange),
 		externalDataValue,
 	}, nil
 }
 
+// FindAssetsByTickersInTransaction retrieves the assets identified by the given tickers within an
+// existing SQL transaction. Each ticker resolves to at most one asset, through its current ticker or,
+// when no asset currently has it, through its aliases.
+//
+// Example:
+//
+//	assets, err := assetRepository.FindAssetsByTickersInTransaction(transContext, []string{"ARCA:BIL"})
 func (repository *AssetRDBMSRepository) FindAssetsByTickersInTransaction(
 	transContext context.Context,
 	tickers []string,
 ) ([]*domain.Asset, error) {
 
+	assetsPerTicker, err := repository.FindAssetsPerTickersInTransaction(transContext, tickers, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var persistedAssets = make([]*domain.Asset, 0, len(assetsPerTicker))
+	for _, ticker := range tickers {
+		if asset, found := assetsPerTicker[ticker]; found {
+			persistedAssets = append(persistedAssets, asset)
+		}
+	}
+
+	return persistedAssets, nil
+}
+
+// FindAssetsPerTickersInTransaction resolves each of the given tickers to an asset within an existing
+// SQL transaction, considering the current tickers and the aliases valid at the reference date.
+// Tickers that resolve to no asset are absent from the result.
+//
+// Parameters:
+//   - transContext: the SQL transactional context
+//   - tickers: the tickers to resolve
+//   - referenceDate: the date the tickers refer to, or nil when unknown
+//
+// Returns:
+//   - domain.AssetsPerTicker: the resolved assets keyed by the requested ticker
+//   - error: error if the context is not transactional or the query fails
+func (repository *AssetRDBMSRepository) FindAssetsPerTickersInTransaction(
+	transContext context.Context,
+	tickers []string,
+	referenceDate *time.Time,
+) (domain.AssetsPerTicker, error) {
+
 	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
 	if !ok {
 		return nil, infra.BuildAppError(
@@ -246,11 +752,14 @@ func (repository *AssetRDBMSRepository) FindAssetsByTickersInTransaction(
 		)
 	}
 
-	var queryExecutor = rdbms.BuildQueryInTransaction[domain.Asset](transactionalContext, assetsSQL).
-		AddWhereClauseAndParams("AND ticker = ANY($1)", tickers).
+	var queryExecutor = rdbms.BuildQueryInTransaction[assetPerLookupTicker](
+		transactionalContext,
+		assetsPerLookupTickerSQL,
+	).
+		AddParams(tickers, referenceDate).
 		Build()
 
-	persistedAssets, err := queryExecutor.Find(assetRowScanner)
+	resolvedAssets, err := queryExecutor.Find(assetPerLookupTickerRowScanner)
 	if err != nil {
 		return nil, infra.PropagateAsAppErrorWithNewMessage(
 			err,
@@ -259,7 +768,357 @@ func (repository *AssetRDBMSRepository) FindAssetsByTickersInTransaction(
 		)
 	}
 
-	return langext.ToPointerSlice(persistedAssets), nil
+	var assetsPerTicker = make(domain.AssetsPerTicker, len(resolvedAssets))
+	for _, resolvedAsset := range resolvedAssets {
+		var asset = resolvedAsset.asset
+		assetsPerTicker[resolvedAsset.lookupTicker] = &asset
+	}
+
+	return assetsPerTicker, nil
+}
+
+// FindAssetAliases retrieves the ticker aliases of an asset, ordered by validity start.
+//
+// Example:
+//
+//	aliases, err := assetRepository.FindAssetAliases(1)
+func (repository *AssetRDBMSRepository) FindAssetAliases(assetId int64) ([]*domain.AssetAlias, error) {
+
+	var result []domain.AssetAlias
+	err := rdbms.BuildQuery[domain.AssetAlias](repository.dbAdapter, assetAliasesSQL).
+		AddParam("assetId", assetId).
+		Build().
+		FindInto(&result)
+
+	return langext.ToPointerSlice(result), infra.PropagateAsAppErrorWithNewMessage(
+		err,
+		"Error getting asset aliases",
+		repository,
+	)
+}
+
+// UpdateAssetTickerInTransaction changes the ticker of an asset within an existing SQL transaction,
+// incrementing its version.
+//
+// Example:
+//
+//	err := assetRepository.UpdateAssetTickerInTransaction(transContext, 1, "NEW")
+func (repository *AssetRDBMSRepository) UpdateAssetTickerInTransaction(
+	transContext context.Context,
+	assetId int64,
+	ticker string,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	_, err := repository.dbAdapter.ExecuteInTransaction(transactionalContext, updateAssetTickerSQL, ticker, assetId)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error updating asset ticker", repository)
+}
+
+// InsertAssetAliasInTransaction persists a new ticker alias within an existing SQL transaction and
+// returns it with its generated id.
+//
+// Example:
+//
+//	persistedAlias, err := assetRepository.InsertAssetAliasInTransaction(transContext, alias)
+func (repository *AssetRDBMSRepository) InsertAssetAliasInTransaction(
+	transContext context.Context,
+	alias *domain.AssetAlias,
+) (*domain.AssetAlias, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	id, err := rdbms.BuildQueryInTransaction[int64](transactionalContext, insertAssetAliasSQL).
+		AddParams(alias.AssetId, alias.Ticker, alias.Source, alias.ValidFrom, alias.ValidTo).
+		Build().
+		Get(rdbms.ReturningIntIdSingleRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting asset alias", repository)
+	}
+
+	var insertedAlias = *alias
+	insertedAlias.Id = id
+	return &insertedAlias, nil
+}
+
+// DeleteAssetAliasInTransaction removes a persisted ticker alias of an asset within an existing SQL
+// transaction, reporting whether the alias existed for the asset.
+//
+// Example:
+//
+//	deleted, err := assetRepository.DeleteAssetAliasInTransaction(transContext, 1, 10)
+func (repository *AssetRDBMSRepository) DeleteAssetAliasInTransaction(
+	transContext context.Context,
+	assetId int64,
+	aliasId int64,
+) (bool, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return false, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	result, err := repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		deleteAssetAliasSQL,
+		aliasId,
+		assetId,
+	)
+	if err != nil {
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting asset alias", repository)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting asset alias", repository)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// FindAssetValuations retrieves the manual valuations of an asset, from the most recent to the oldest.
+//
+// Example:
+//
+//	valuations, err := assetRepository.FindAssetValuations(1)
+func (repository *AssetRDBMSRepository) FindAssetValuations(assetId int64) ([]*domain.AssetValuation, error) {
+
+	var result []domain.AssetValuation
+	err := rdbms.BuildQuery[domain.AssetValuation](repository.dbAdapter, assetValuationsSQL).
+		AddParam("assetId", assetId).
+		Build().
+		FindInto(&result)
+
+	return langext.ToPointerSlice(result), infra.PropagateAsAppErrorWithNewMessage(
+		err,
+		"Error getting asset valuations",
+		repository,
+	)
+}
+
+// FindLatestAssetValuation retrieves the most recent manual valuation of an asset, or nil when the
+// asset has no valuation.
+//
+// Example:
+//
+//	valuation, err := assetRepository.FindLatestAssetValuation(1)
+func (repository *AssetRDBMSRepository) FindLatestAssetValuation(assetId int64) (*domain.AssetValuation, error) {
+
+	var result domain.AssetValuation
+	err := rdbms.BuildQuery[domain.AssetValuation](repository.dbAdapter, latestAssetValuationSQL).
+		AddParam("assetId", assetId).
+		Build().
+		GetInto(&result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting latest asset valuation", repository)
+	}
+
+	return &result, nil
+}
+
+// MergeAssetValuationInTransaction persists a manual valuation within an existing SQL transaction, replacing
+// the price and currency of an existing valuation of the asset on the same date, and returns it with its id.
+//
+// Example:
+//
+//	persistedValuation, err := assetRepository.MergeAssetValuationInTransaction(transContext, valuation)
+func (repository *AssetRDBMSRepository) MergeAssetValuationInTransaction(
+	transContext context.Context,
+	valuation *domain.AssetValuation,
+) (*domain.AssetValuation, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	result, err := rdbms.BuildQueryInTransaction[domain.AssetValuation](
+		transactionalContext,
+		mergeAssetValuationSQL,
+	).
+		AddParams(valuation.AssetId, valuation.ValuationDate, valuation.Price, valuation.Currency).
+		Build().
+		Get(assetValuationRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error merging asset valuation", repository)
+	}
+
+	return &result, nil
+}
+
+// DeleteAssetValuationInTransaction removes a persisted manual valuation of an asset within an existing SQL
+// transaction, reporting whether the valuation existed for the asset.
+//
+// Example:
+//
+//	deleted, err := assetRepository.DeleteAssetValuationInTransaction(transContext, 1, 10)
+func (repository *AssetRDBMSRepository) DeleteAssetValuationInTransaction(
+	transContext context.Context,
+	assetId int64,
+	valuationId int64,
+) (bool, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return false, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	result, err := repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		deleteAssetValuationSQL,
+		valuationId,
+		assetId,
+	)
+	if err != nil {
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting asset valuation", repository)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting asset valuation", repository)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func assetValuationRowScanner(row *sql.Row) (domain.AssetValuation, error) {
+	var valuation domain.AssetValuation
+	var err = row.Scan(
+		&valuation.Id,
+		&valuation.AssetId,
+		&valuation.ValuationDate,
+		&valuation.Price,
+		&valuation.Currency,
+	)
+	return valuation, err
+}
+
+// FindAssetEvents retrieves the dividend and split events of an asset, ordered by date.
+//
+// Example:
+//
+//	events, err := assetRepository.FindAssetEvents(1)
+func (repository *AssetRDBMSRepository) FindAssetEvents(assetId int64) ([]*domain.AssetEvent, error) {
+
+	var eventsPerAssetId, err = repository.FindAssetEventsPerAssetId([]int64{assetId})
+	if err != nil {
+		return nil, err
+	}
+
+	var events = eventsPerAssetId[assetId]
+	if events == nil {
+		events = make([]*domain.AssetEvent, 0)
+	}
+
+	return events, nil
+}
+
+// FindAssetEventsPerAssetId retrieves the dividend and split events of several assets, grouped by asset
+// id and ordered by date. Assets without events have no entry.
+//
+// Example:
+//
+//	eventsPerAssetId, err := assetRepository.FindAssetEventsPerAssetId([]int64{1, 2})
+func (repository *AssetRDBMSRepository) FindAssetEventsPerAssetId(
+	assetIds []int64,
+) (map[int64][]*domain.AssetEvent, error) {
+
+	var result []domain.AssetEvent
+	err := rdbms.BuildQuery[domain.AssetEvent](repository.dbAdapter, assetEventsSQL).
+		AddParam("assetIds", pq.Array(assetIds)).
+		Build().
+		FindInto(&result)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting asset events", repository)
+	}
+
+	var eventsPerAssetId = make(map[int64][]*domain.AssetEvent)
+	for _, event := range langext.ToPointerSlice(result) {
+		eventsPerAssetId[event.AssetId] = append(eventsPerAssetId[event.AssetId], event)
+	}
+
+	return eventsPerAssetId, nil
+}
+
+// MergeAssetEvents persists the given events for an asset in a single statement, updating the events
+// already recorded for the same type and date, and returns the persisted events with their ids.
+//
+// Example:
+//
+//	persistedEvents, err := assetRepository.MergeAssetEvents(1, importedEvents)
+func (repository *AssetRDBMSRepository) MergeAssetEvents(
+	assetId int64,
+	events []*domain.AssetEvent,
+) ([]*domain.AssetEvent, error) {
+
+	var importRecords = make([]assetEventImportRecord, len(events))
+	for index, event := range events {
+		importRecords[index] = buildAssetEventImportRecord(event)
+	}
+
+	eventsJSON, err := json.Marshal(importRecords)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error serializing asset events", repository)
+	}
+
+	var result []domain.AssetEvent
+	err = rdbms.BuildQuery[domain.AssetEvent](repository.dbAdapter, mergeAssetEventsSQL).
+		AddParam("assetId", assetId).
+		AddParam("events", string(eventsJSON)).
+		Build().
+		FindInto(&result)
+
+	return langext.ToPointerSlice(result), infra.PropagateAsAppErrorWithNewMessage(
+		err,
+		"Error merging asset events",
+		repository,
+	)
+}
+
+// FindPortfolioDividendIncome retrieves the dividend income of a portfolio, one record per dividend of
+// an asset held in the latest portfolio observation up to the ex-dividend date, from the most recent.
+//
+// Example:
+//
+//	incomeRecords, err := assetRepository.FindPortfolioDividendIncome(1)
+func (repository *AssetRDBMSRepository) FindPortfolioDividendIncome(
+	portfolioId int64,
+) ([]*domain.AssetIncomeRecord, error) {
+
+	var queryExecutor = rdbms.BuildQuery[domain.AssetIncomeRecord](repository.dbAdapter, portfolioDividendIncomeSQL).
+		AddParam("portfolioId", portfolioId).
+		Build()
+
+	result, err := queryExecutor.FindWithRowScanner(assetIncomeRecordRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio dividend income", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
 }
 
 func BuildAssetRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *AssetRDBMSRepository {
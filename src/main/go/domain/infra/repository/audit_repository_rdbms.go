@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	defaultAuditEntriesLimit = 100
+	auditEntryColumnsSQL     = `
+		id, entity_type, entity_id, portfolio_id, action, actor_user_id, actor_username, before_data, after_data,
+		created_at
+	`
+	auditEntriesSQL = `
+		SELECT ` + auditEntryColumnsSQL + `
+		FROM audit_entry ae
+	` + rdbms.WhereClausePlaceholder + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT {:limit}
+	`
+	auditEntryInsertSQL = `
+		INSERT INTO audit_entry (
+			entity_type, entity_id, portfolio_id, action, actor_user_id, actor_username, before_data, after_data
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	accessibleAuditEntriesClauseSQL = `
+		AND (
+			ae.portfolio_id IS NULL
+			OR EXISTS (
+				SELECT 1 FROM portfolio p
+				WHERE p.id = ae.portfolio_id AND p.owner_user_id = {:accessibleByUserId}
+			)
+			OR EXISTS (
+				SELECT 1 FROM portfolio_grant pg
+				WHERE pg.portfolio_id = ae.portfolio_id AND pg.user_id = {:accessibleByUserId}
+			)
+		)
+	`
+)
+
+// auditSnapshotSQLs select the JSON snapshot of each audited entity type by the entity id ($1) and, for
+// the allocation facts, the portfolio id ($2). Snapshots are NULL or empty when the entity does not exist,
+// and leave out the versions, so updates changing nothing else are not recorded. Allocation plans include
+// their planned allocations and assets their ticker aliases.
+var auditSnapshotSQLs = map[domain.AuditEntityType]string{
+	domain.PortfolioAuditEntityType: `
+		SELECT to_jsonb(p) - 'version' FROM portfolio p WHERE p.id = $1
+	`,
+	domain.PortfolioObservationAuditEntityType: `
+		SELECT to_jsonb(paot) FROM portfolio_allocation_obs_time paot WHERE paot.id = $1
+	`,
+	domain.PortfolioAllocationAuditEntityType: `
+		SELECT jsonb_agg(
+			to_jsonb(paf) - 'portfolio_id' - 'observation_time_id'
+			ORDER BY paf.asset_id, paf."class", paf.cash_reserve
+		)
+		FROM portfolio_allocation_fact paf
+		WHERE paf.observation_time_id = $1 AND paf.portfolio_id = $2
+	`,
+	domain.AllocationPlanAuditEntityType: `
+		SELECT (to_jsonb(ap) - 'version') || jsonb_build_object(
+			'planned_allocations',
+			coalesce(
+				(
+					SELECT jsonb_agg(to_jsonb(pa) - 'allocation_plan_id' ORDER BY pa.id)
+					FROM planned_allocation pa
+					WHERE pa.allocation_plan_id = ap.id
+				),
+				'[]'::jsonb
+			)
+		)
+		FROM allocation_plan ap
+		WHERE ap.id = $1
+	`,
+	domain.AssetAuditEntityType: `
+		SELECT (to_jsonb(a) - 'version') || jsonb_build_object(
+			'aliases',
+			coalesce(
+				(
+					SELECT jsonb_agg(to_jsonb(aa) - 'asset_id' ORDER BY aa.id)
+					FROM asset_alias aa
+					WHERE aa.asset_id = a.id
+				),
+				'[]'::jsonb
+			),
+			'valuations',
+			coalesce(
+				(
+					SELECT jsonb_agg(to_jsonb(av) - 'asset_id' ORDER BY av.valuation_date)
+					FROM asset_valuation av
+					WHERE av.asset_id = a.id
+				),
+				'[]'::jsonb
+			)
+		)
+		FROM asset a
+		WHERE a.id = $1
+	`,
+}
+
+func auditSnapshotRowScanner(row *sql.Row) (json.RawMessage, error) {
+	var snapshot []byte
+	scanErr := row.Scan(&snapshot)
+	return snapshot, scanErr
+}
+
+func auditEntryRowScanner(rows *sql.Rows) (domain.AuditEntry, error) {
+
+	var entry domain.AuditEntry
+	var before, after []byte
+
+	scanErr := rows.Scan(
+		&entry.Id,
+		&entry.EntityType,
+		&entry.EntityId,
+		&entry.PortfolioId,
+		&entry.Action,
+		&entry.ActorUserId,
+		&entry.ActorUsername,
+		&before,
+		&after,
+		&entry.CreatedAt,
+	)
+
+	entry.Before = before
+	entry.After = after
+	return entry, scanErr
+}
+
+type AuditRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+// SnapshotEntityInTransaction reads the JSON snapshot of an audited entity within an existing SQL
+// transaction, or nil when the entity does not exist.
+//
+// Example:
+//
+//	before, err := auditRepository.SnapshotEntityInTransaction(
+//		transContext,
+//		&domain.AuditEntityReference{EntityType: domain.AssetAuditEntityType, EntityId: 1},
+//	)
+func (repository *AuditRDBMSRepository) SnapshotEntityInTransaction(
+	transContext context.Context,
+	reference *domain.AuditEntityReference,
+) (json.RawMessage, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	var snapshotSQL, known = auditSnapshotSQLs[reference.EntityType]
+	if !known {
+		return nil, infra.BuildAppError("Unknown audit entity type "+string(reference.EntityType), repository)
+	}
+
+	var queryBuilder = rdbms.BuildQueryInTransaction[json.RawMessage](transactionalContext, snapshotSQL).
+		AddParams(reference.EntityId)
+	if reference.EntityType == domain.PortfolioAllocationAuditEntityType {
+		queryBuilder.AddParams(reference.PortfolioId)
+	}
+
+	snapshot, err := queryBuilder.Build().Get(auditSnapshotRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error reading audit snapshot", repository)
+	}
+
+	return snapshot, nil
+}
+
+// InsertAuditEntryInTransaction appends an entry to the audit trail within the transaction of the audited
+// change, so the entry is only kept if the change commits.
+//
+// Example:
+//
+//	err := adapter.RunInTransaction(func(transContext *rdbms.SQLTransactionalContext) error {
+//		// ... audited change
+//		return auditRepository.InsertAuditEntryInTransaction(transContext, entry)
+//	})
+func (repository *AuditRDBMSRepository) InsertAuditEntryInTransaction(
+	transContext context.Context,
+	entry *domain.AuditEntry,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	_, err := repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		auditEntryInsertSQL,
+		string(entry.EntityType),
+		entry.EntityId,
+		entry.PortfolioId,
+		string(entry.Action),
+		entry.ActorUserId,
+		entry.ActorUsername,
+		nullableJSON(entry.Before),
+		nullableJSON(entry.After),
+	)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting audit entry", repository)
+}
+
+// FindAuditEntries retrieves the audit entries matching the filter, newest first, up to its limit or 100
+// entries when it has none.
+//
+// Example:
+//
+//	entries, err := auditRepository.FindAuditEntries(&domain.AuditFilter{EntityType: domain.AssetAuditEntityType})
+func (repository *AuditRDBMSRepository) FindAuditEntries(filter *domain.AuditFilter) ([]*domain.AuditEntry, error) {
+
+	var limit = filter.Limit
+	if limit == 0 {
+		limit = defaultAuditEntriesLimit
+	}
+
+	var queryBuilder = rdbms.BuildQuery[domain.AuditEntry](repository.dbAdapter, auditEntriesSQL).
+		AddParam("limit", limit)
+
+	if filter.EntityType != "" {
+		queryBuilder.AddWhereClauseAndParam("AND entity_type = {:entityType}", "entityType", filter.EntityType)
+	}
+
+	if filter.EntityId != nil {
+		queryBuilder.AddWhereClauseAndParam("AND entity_id = {:entityId}", "entityId", *filter.EntityId)
+	}
+
+	if filter.PortfolioId != nil {
+		queryBuilder.AddWhereClauseAndParam("AND portfolio_id = {:portfolioId}", "portfolioId", *filter.PortfolioId)
+	}
+
+	if filter.ActorUserId != nil {
+		queryBuilder.AddWhereClauseAndParam("AND actor_user_id = {:actorUserId}", "actorUserId", *filter.ActorUserId)
+	}
+
+	if filter.From != nil {
+		queryBuilder.AddWhereClauseAndParam("AND created_at >= {:from}", "from", *filter.From)
+	}
+
+	if filter.To != nil {
+		queryBuilder.AddWhereClauseAndParam("AND created_at < {:to}", "to", *filter.To)
+	}
+
+	if filter.AccessibleByUserId != nil {
+		queryBuilder.AddWhereClauseAndParam(
+			accessibleAuditEntriesClauseSQL,
+			"accessibleByUserId",
+			*filter.AccessibleByUserId,
+		)
+	}
+
+	result, err := queryBuilder.Build().FindWithRowScanner(auditEntryRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting audit entries", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+func BuildAuditRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *AuditRDBMSRepository {
+	return &AuditRDBMSRepository{dbAdapter: dbAdapter}
+}
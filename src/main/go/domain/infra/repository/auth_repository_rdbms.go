@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	userColumnsSQL = `
+		app_user.id, app_user.username, app_user.password_hash, app_user.created_at
+	`
+	usersCountSQL = `
+		SELECT count(*) AS count FROM app_user
+	`
+	userSQL = `
+		SELECT ` + userColumnsSQL + `
+		FROM app_user
+		WHERE app_user.id = {:userId}
+	`
+	userByUsernameSQL = `
+		SELECT ` + userColumnsSQL + `
+		FROM app_user
+		WHERE app_user.username = {:username}
+	`
+	userInsertSQL = `
+		INSERT INTO app_user (username, password_hash)
+		VALUES ({:username}, {:passwordHash})
+		RETURNING ` + userColumnsSQL
+	userPasswordUpdateSQL = `
+		UPDATE app_user SET password_hash = {:passwordHash}
+		WHERE id = {:userId}
+		RETURNING id
+	`
+	userSessionInsertSQL = `
+		INSERT INTO user_session (user_id, token_hash, expires_at)
+		VALUES ({:userId}, {:tokenHash}, {:expiresAt})
+		RETURNING id
+	`
+	activeSessionUserSQL = `
+		SELECT ` + userColumnsSQL + `
+		FROM user_session
+		JOIN app_user ON app_user.id = user_session.user_id
+		WHERE user_session.token_hash = {:tokenHash} AND user_session.expires_at > {:now}
+	`
+	userSessionDeleteSQL = `
+		WITH deleted AS (
+			DELETE FROM user_session WHERE token_hash = {:tokenHash} RETURNING id
+		)
+		SELECT count(*) AS count FROM deleted
+	`
+	userSessionsDeleteSQL = `
+		WITH deleted AS (
+			DELETE FROM user_session WHERE user_id = {:userId} RETURNING id
+		)
+		SELECT count(*) AS count FROM deleted
+	`
+	expiredSessionsDeleteSQL = `
+		WITH deleted AS (
+			DELETE FROM user_session WHERE expires_at <= {:now} RETURNING id
+		)
+		SELECT count(*) AS count FROM deleted
+	`
+	apiTokenColumnsSQL = `
+		id, user_id, name, token_hash, scopes, created_at, expires_at, last_used_at, revoked_at
+	`
+	apiTokenInsertSQL = `
+		INSERT INTO api_token (user_id, name, token_hash, scopes, expires_at)
+		VALUES ({:userId}, {:name}, {:tokenHash}, {:scopes}, {:expiresAt})
+		RETURNING ` + apiTokenColumnsSQL
+	userAPITokensSQL = `
+		SELECT ` + apiTokenColumnsSQL + `
+		FROM api_token
+		WHERE user_id = {:userId}
+		ORDER BY id
+	`
+	activeAPITokenSQL = `
+		SELECT ` + apiTokenColumnsSQL + `
+		FROM api_token
+		WHERE token_hash = {:tokenHash}
+			AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > {:now})
+	`
+	apiTokenLastUsedUpdateSQL = `
+		UPDATE api_token SET last_used_at = {:lastUsedAt}
+		WHERE id = {:tokenId}
+		RETURNING id
+	`
+	apiTokenRevokeSQL = `
+		UPDATE api_token SET revoked_at = {:revokedAt}
+		WHERE user_id = {:userId} AND id = {:tokenId} AND revoked_at IS NULL
+		RETURNING id
+	`
+)
+
+type usersCountDTS struct {
+	Count int64
+}
+
+func userRowScanner(rows *sql.Rows) (domain.User, error) {
+	var user domain.User
+	scanErr := rows.Scan(&user.Id, &user.Username, &user.PasswordHash, &user.CreatedAt)
+	return user, scanErr
+}
+
+func apiTokenRowScanner(rows *sql.Rows) (domain.APIToken, error) {
+
+	var token domain.APIToken
+	var scopes []string
+
+	scanErr := rows.Scan(
+		&token.Id,
+		&token.UserId,
+		&token.Name,
+		&token.TokenHash,
+		pq.Array(&scopes),
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.RevokedAt,
+	)
+
+	token.Scopes = make([]domain.APITokenScope, len(scopes))
+	for i, scope := range scopes {
+		token.Scopes[i] = domain.APITokenScope(scope)
+	}
+
+	return token, scanErr
+}
+
+func scopesToStrings(scopes []domain.APITokenScope) []string {
+	var values = make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+	return values
+}
+
+type AuthRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+func (repository *AuthRDBMSRepository) CountUsers() (int64, error) {
+
+	var result usersCountDTS
+	err := rdbms.BuildQuery[usersCountDTS](repository.dbAdapter, usersCountSQL).
+		Build().
+		GetInto(&result)
+	if err != nil {
+		return 0, infra.PropagateAsAppErrorWithNewMessage(err, "Error counting users", repository)
+	}
+
+	return result.Count, nil
+}
+
+// FindUser retrieves a user, or nil when it does not exist.
+func (repository *AuthRDBMSRepository) FindUser(userId int64) (*domain.User, error) {
+	return repository.getUser(
+		rdbms.BuildQuery[domain.User](repository.dbAdapter, userSQL).AddParam("userId", userId),
+	)
+}
+
+// FindUserByUsername retrieves the user with a username, or nil when it does not exist.
+func (repository *AuthRDBMSRepository) FindUserByUsername(username string) (*domain.User, error) {
+	return repository.getUser(
+		rdbms.BuildQuery[domain.User](repository.dbAdapter, userByUsernameSQL).AddParam("username", username),
+	)
+}
+
+func (repository *AuthRDBMSRepository) getUser(queryBuilder *rdbms.QueryBuilder[domain.User]) (*domain.User, error) {
+
+	result, err := queryBuilder.Build().GetWithRowScanner(userRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting user", repository)
+	}
+
+	return &result, nil
+}
+
+// InsertUser persists a new user and returns it as persisted.
+//
+// Example:
+//
+//	persistedUser, err := authRepository.InsertUser(&domain.User{Username: "admin", PasswordHash: hash})
+func (repository *AuthRDBMSRepository) InsertUser(user *domain.User) (*domain.User, error) {
+
+	result, err := rdbms.BuildQuery[domain.User](repository.dbAdapter, userInsertSQL).
+		AddParam("username", user.Username).
+		AddParam("passwordHash", user.PasswordHash).
+		Build().
+		GetWithRowScanner(userRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting user", repository)
+	}
+
+	return &result, nil
+}
+
+func (repository *AuthRDBMSRepository) UpdateUserPassword(userId int64, passwordHash string) error {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, userPasswordUpdateSQL).
+		AddParam("userId", userId).
+		AddParam("passwordHash", passwordHash).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error updating user password", repository)
+}
+
+func (repository *AuthRDBMSRepository) InsertSession(session *domain.UserSession) error {
+
+	sessionId, err := rdbms.BuildQuery[int64](repository.dbAdapter, userSessionInsertSQL).
+		AddParam("userId", session.UserId).
+		AddParam("tokenHash", session.TokenHash).
+		AddParam("expiresAt", session.ExpiresAt).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		return infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting user session", repository)
+	}
+
+	session.Id = sessionId
+	return nil
+}
+
+// FindActiveSessionUser retrieves the user of the session with a token hash, or nil when the session does
+// not exist or is expired at the given time.
+func (repository *AuthRDBMSRepository) FindActiveSessionUser(tokenHash string, now time.Time) (*domain.User, error) {
+	return repository.getUser(
+		rdbms.BuildQuery[domain.User](repository.dbAdapter, activeSessionUserSQL).
+			AddParam("tokenHash", tokenHash).
+			AddParam("now", now),
+	)
+}
+
+func (repository *AuthRDBMSRepository) DeleteSession(tokenHash string) error {
+	return repository.deleteSessions(
+		rdbms.BuildQuery[deletedRowsCountDTS](repository.dbAdapter, userSessionDeleteSQL).
+			AddParam("tokenHash", tokenHash),
+	)
+}
+
+func (repository *AuthRDBMSRepository) DeleteUserSessions(userId int64) error {
+	return repository.deleteSessions(
+		rdbms.BuildQuery[deletedRowsCountDTS](repository.dbAdapter, userSessionsDeleteSQL).
+			AddParam("userId", userId),
+	)
+}
+
+func (repository *AuthRDBMSRepository) DeleteExpiredSessions(now time.Time) error {
+	return repository.deleteSessions(
+		rdbms.BuildQuery[deletedRowsCountDTS](repository.dbAdapter, expiredSessionsDeleteSQL).
+			AddParam("now", now),
+	)
+}
+
+func (repository *AuthRDBMSRepository) deleteSessions(
+	queryBuilder *rdbms.QueryBuilder[deletedRowsCountDTS],
+) error {
+	var result deletedRowsCountDTS
+	err := queryBuilder.Build().GetInto(&result)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting user sessions", repository)
+}
+
+// InsertAPIToken persists a new API token and returns it as persisted.
+func (repository *AuthRDBMSRepository) InsertAPIToken(token *domain.APIToken) (*domain.APIToken, error) {
+
+	result, err := rdbms.BuildQuery[domain.APIToken](repository.dbAdapter, apiTokenInsertSQL).
+		AddParam("userId", token.UserId).
+		AddParam("name", token.Name).
+		AddParam("tokenHash", token.TokenHash).
+		AddParam("scopes", pq.Array(scopesToStrings(token.Scopes))).
+		AddParam("expiresAt", token.ExpiresAt).
+		Build().
+		GetWithRowScanner(apiTokenRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting API token", repository)
+	}
+
+	return &result, nil
+}
+
+// FindUserAPITokens retrieves the API tokens of a user, revoked and expired ones included, in creation
+// order.
+func (repository *AuthRDBMSRepository) FindUserAPITokens(userId int64) ([]*domain.APIToken, error) {
+
+	result, err := rdbms.BuildQuery[domain.APIToken](repository.dbAdapter, userAPITokensSQL).
+		AddParam("userId", userId).
+		Build().
+		FindWithRowScanner(apiTokenRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting API tokens", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// FindActiveAPIToken retrieves the API token with a token hash, or nil when it does not exist, is revoked
+// or is expired at the given time.
+func (repository *AuthRDBMSRepository) FindActiveAPIToken(tokenHash string, now time.Time) (*domain.APIToken, error) {
+
+	result, err := rdbms.BuildQuery[domain.APIToken](repository.dbAdapter, activeAPITokenSQL).
+		AddParam("tokenHash", tokenHash).
+		AddParam("now", now).
+		Build().
+		GetWithRowScanner(apiTokenRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting API token", repository)
+	}
+
+	return &result, nil
+}
+
+func (repository *AuthRDBMSRepository) UpdateAPITokenLastUsed(tokenId int64, lastUsedAt time.Time) error {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, apiTokenLastUsedUpdateSQL).
+		AddParam("tokenId", tokenId).
+		AddParam("lastUsedAt", lastUsedAt).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error updating API token last use", repository)
+}
+
+// RevokeAPIToken revokes an active API token of a user, returning false when it does not exist or is
+// already revoked.
+func (repository *AuthRDBMSRepository) RevokeAPIToken(userId int64, tokenId int64, revokedAt time.Time) (bool, error) {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, apiTokenRevokeSQL).
+		AddParam("userId", userId).
+		AddParam("tokenId", tokenId).
+		AddParam("revokedAt", revokedAt).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error revoking API token", repository)
+	}
+
+	return true, nil
+}
+
+func BuildAuthRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *AuthRDBMSRepository {
+	return &AuthRDBMSRepository{dbAdapter: dbAdapter}
+}
@@ -0,0 +1,746 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	corporateActionColumnsSQL = `
+		id, asset_id, action_type, effective_date, coalesce(ratio_numerator, 0), coalesce(ratio_denominator, 0),
+		coalesce(new_ticker, ''), coalesce(target_asset_id, 0), coalesce(cash_per_share, 0),
+		coalesce(cash_currency, ''), status, applied_at, reversal_data
+	`
+	assetCorporateActionsSQL = `
+		SELECT ` + corporateActionColumnsSQL + `
+		FROM corporate_action
+		WHERE asset_id = {:assetId}
+		ORDER BY effective_date, id
+	`
+	corporateActionSQL = `
+		SELECT ` + corporateActionColumnsSQL + `
+		FROM corporate_action
+		WHERE id = {:id}
+	`
+	corporateActionInsertSQL = `
+		INSERT INTO corporate_action (
+			asset_id, action_type, effective_date, ratio_numerator, ratio_denominator, new_ticker, target_asset_id,
+			cash_per_share, cash_currency
+		)
+		VALUES (
+			{:assetId}, {:actionType}, {:effectiveDate}, {:ratioNumerator}, {:ratioDenominator}, {:newTicker},
+			{:targetAssetId}, {:cashPerShare}, {:cashCurrency}
+		)
+		RETURNING ` + corporateActionColumnsSQL
+	corporateActionForUpdateSQL = `
+		SELECT ` + corporateActionColumnsSQL + `
+		FROM corporate_action
+		WHERE id = $1
+		FOR UPDATE
+	`
+	// corporateActionsAppliedAfterSQL selects the applied actions involving any of the given assets ($3)
+	// that were applied after the reference action ($1, applied at $2), the latest first
+	corporateActionsAppliedAfterSQL = `
+		SELECT ` + corporateActionColumnsSQL + `
+		FROM corporate_action
+		WHERE status = 'APPLIED'
+			AND id <> $1
+			AND applied_at > $2
+			AND (asset_id = ANY($3) OR target_asset_id = ANY($3))
+		ORDER BY applied_at DESC
+	`
+	corporateActionStatusUpdateSQL = `
+		UPDATE corporate_action
+		SET status = $1, applied_at = $2, reversal_data = $3
+		WHERE id = $4
+	`
+	// laterPortfolioAllocationsSQL selects, as a JSON array, the allocations of the action asset ($1) and
+	// of its merger target ($2) in the observations from the effective date ($3) on that hold the action
+	// asset, which are all the allocations changed when applying the action
+	laterPortfolioAllocationsSQL = `
+		SELECT coalesce(jsonb_agg(to_jsonb(later_paf)), '[]'::jsonb)
+		FROM (
+			SELECT
+				paf.portfolio_id, paf.observation_time_id, paf.asset_id, paf.class, paf.cash_reserve,
+				paf.asset_quantity, paf.asset_market_price, paf.total_market_value
+			FROM portfolio_allocation_fact paf
+			JOIN portfolio_allocation_obs_time paot ON paot.id = paf.observation_time_id
+			WHERE paf.asset_id IN ($1, $2)
+				AND paot.observation_timestamp >= $3::date
+				AND EXISTS (
+					SELECT 1
+					FROM portfolio_allocation_fact action_paf
+					WHERE action_paf.portfolio_id = paf.portfolio_id
+						AND action_paf.observation_time_id = paf.observation_time_id
+						AND action_paf.asset_id = $1
+				)
+		) later_paf
+	`
+	// laterPortfolioAllocationsSplitSQL converts the quantities and prices of the asset ($1) observed from
+	// the effective date ($3) on to the share basis after the split ratio ($2)
+	laterPortfolioAllocationsSplitSQL = `
+		UPDATE portfolio_allocation_fact paf
+		SET asset_quantity = paf.asset_quantity * $2::numeric,
+			asset_market_price = paf.asset_market_price / $2::numeric
+		FROM portfolio_allocation_obs_time paot
+		WHERE paot.id = paf.observation_time_id
+			AND paf.asset_id = $1
+			AND paot.observation_timestamp >= $3::date
+	`
+	// laterPortfolioAllocationsMergeIntoTargetSQL adds the merged asset ($1) allocations to the target asset
+	// ($2) allocations already observed in the same classification, converted by the merger ratio ($3)
+	laterPortfolioAllocationsMergeIntoTargetSQL = `
+		UPDATE portfolio_allocation_fact target_paf
+		SET asset_quantity = target_paf.asset_quantity + source_paf.asset_quantity * $3::numeric,
+			total_market_value = target_paf.total_market_value + source_paf.total_market_value
+		FROM portfolio_allocation_fact source_paf
+		JOIN portfolio_allocation_obs_time paot ON paot.id = source_paf.observation_time_id
+		WHERE source_paf.asset_id = $1
+			AND target_paf.asset_id = $2
+			AND target_paf.portfolio_id = source_paf.portfolio_id
+			AND target_paf.observation_time_id = source_paf.observation_time_id
+			AND target_paf.class = source_paf.class
+			AND target_paf.cash_reserve = source_paf.cash_reserve
+			AND paot.observation_timestamp >= $4::date
+	`
+	laterPortfolioAllocationsMergedDeleteSQL = `
+		DELETE FROM portfolio_allocation_fact source_paf
+		USING portfolio_allocation_fact target_paf, portfolio_allocation_obs_time paot
+		WHERE source_paf.asset_id = $1
+			AND target_paf.asset_id = $2
+			AND target_paf.portfolio_id = source_paf.portfolio_id
+			AND target_paf.observation_time_id = source_paf.observation_time_id
+			AND target_paf.class = source_paf.class
+			AND target_paf.cash_reserve = source_paf.cash_reserve
+			AND paot.id = source_paf.observation_time_id
+			AND paot.observation_timestamp >= $3::date
+	`
+	laterPortfolioAllocationsMergeMoveSQL = `
+		UPDATE portfolio_allocation_fact paf
+		SET asset_id = $2,
+			asset_quantity = paf.asset_quantity * $3::numeric,
+			asset_market_price = paf.asset_market_price / $3::numeric
+		FROM portfolio_allocation_obs_time paot
+		WHERE paot.id = paf.observation_time_id
+			AND paf.asset_id = $1
+			AND paot.observation_timestamp >= $4::date
+	`
+	portfolioAllocationSnapshotRecordSQL = `
+		jsonb_to_recordset($1::jsonb) AS snapshot(
+			portfolio_id int, observation_time_id int, asset_id int, class text, cash_reserve boolean,
+			asset_quantity numeric, asset_market_price numeric, total_market_value bigint
+		)
+	`
+	// snapshotPortfolioAllocationsDeleteSQL removes the current allocations of the involved assets ($2) in
+	// the observations present in the snapshot ($1), so the snapshot can be inserted back
+	snapshotPortfolioAllocationsDeleteSQL = `
+		DELETE FROM portfolio_allocation_fact paf
+		USING (
+			SELECT DISTINCT snapshot.portfolio_id, snapshot.observation_time_id
+			FROM ` + portfolioAllocationSnapshotRecordSQL + `
+		) snapshot_observation
+		WHERE paf.portfolio_id = snapshot_observation.portfolio_id
+			AND paf.observation_time_id = snapshot_observation.observation_time_id
+			AND paf.asset_id = ANY($2)
+	`
+	snapshotPortfolioAllocationsInsertSQL = `
+		INSERT INTO portfolio_allocation_fact (
+			portfolio_id, observation_time_id, asset_id, class, cash_reserve, asset_quantity, asset_market_price,
+			total_market_value
+		)
+		SELECT
+			snapshot.portfolio_id, snapshot.observation_time_id, snapshot.asset_id, snapshot.class,
+			snapshot.cash_reserve, snapshot.asset_quantity, snapshot.asset_market_price, snapshot.total_market_value
+		FROM ` + portfolioAllocationSnapshotRecordSQL
+	// laterPlannedAllocationsSQL selects, as a JSON array, the planned allocations of the asset ($1) in
+	// plans without execution date or executed from the effective date ($2) on
+	laterPlannedAllocationsSQL = `
+		SELECT coalesce(
+			jsonb_agg(
+				jsonb_build_object(
+					'id', pa.id, 'allocation_plan_id', ap.id, 'portfolio_id', ap.portfolio_id, 'asset_id', pa.asset_id,
+					'hierarchical_id', pa.hierarchical_id
+				)
+				ORDER BY pa.id
+			),
+			'[]'::jsonb
+		)
+		FROM planned_allocation pa
+		JOIN allocation_plan ap ON ap.id = pa.allocation_plan_id
+		WHERE pa.asset_id = $1
+			AND (ap.planned_execution_date IS NULL OR ap.planned_execution_date >= $2::date)
+	`
+	// laterPlannedAllocationsReassignSQL points the later planned allocations of the asset ($1) to the new
+	// asset ($4), replacing the previous ticker ($2) with the new ticker ($3) in their hierarchical ids
+	laterPlannedAllocationsReassignSQL = `
+		UPDATE planned_allocation pa
+		SET asset_id = $4, hierarchical_id = array_replace(pa.hierarchical_id, $2::text, $3::text)
+		FROM allocation_plan ap
+		WHERE ap.id = pa.allocation_plan_id
+			AND pa.asset_id = $1
+			AND (ap.planned_execution_date IS NULL OR ap.planned_execution_date >= $5::date)
+	`
+	snapshotPlannedAllocationsRestoreSQL = `
+		UPDATE planned_allocation pa
+		SET asset_id = snapshot.asset_id, hierarchical_id = snapshot.hierarchical_id
+		FROM jsonb_to_recordset($1::jsonb) AS snapshot(id int, asset_id int, hierarchical_id text[])
+		WHERE pa.id = snapshot.id
+	`
+)
+
+// corporateActionRowScanner reads a persisted corporate action, including its optional reversal data,
+// into the domain model.
+func corporateActionRowScanner(rows *sql.Rows) (domain.CorporateAction, error) {
+
+	var action domain.CorporateAction
+	var reversalValue interface{}
+
+	scanErr := rows.Scan(
+		&action.Id,
+		&action.AssetId,
+		&action.ActionType,
+		&action.EffectiveDate,
+		&action.RatioNumerator,
+		&action.RatioDenominator,
+		&action.NewTicker,
+		&action.TargetAssetId,
+		&action.CashPerShare,
+		&action.CashCurrency,
+		&action.Status,
+		&action.AppliedAt,
+		&reversalValue,
+	)
+	if scanErr != nil {
+		return action, scanErr
+	}
+
+	if reversalValue != nil {
+		var reversal domain.CorporateActionReversal
+		scanErr = reversal.Scan(reversalValue)
+		if scanErr != nil {
+			return action, scanErr
+		}
+		action.Reversal = &reversal
+	}
+
+	return action, nil
+}
+
+// jsonArrayRowScanner builds a single row scanner reading a JSON array column into a slice of T.
+func jsonArrayRowScanner[T any](row *sql.Row) ([]*T, error) {
+
+	var arrayJSON []byte
+	if err := row.Scan(&arrayJSON); err != nil {
+		return nil, err
+	}
+
+	var result []*T
+	err := json.Unmarshal(arrayJSON, &result)
+	return result, err
+}
+
+// nullableIfZero returns nil for zero values, so optional terms are persisted as NULL.
+func nullableIfZero[T comparable](value T) any {
+	var zero T
+	if value == zero {
+		return nil
+	}
+	return value
+}
+
+// corporateActionStatement is one of the statements executed in sequence to apply a corporate action.
+type corporateActionStatement struct {
+	sql    string
+	params []any
+}
+
+type CorporateActionRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+// FindAssetCorporateActions retrieves the corporate actions of an asset, ordered by effective date.
+//
+// Example:
+//
+//	actions, err := corporateActionRepository.FindAssetCorporateActions(1)
+func (repository *CorporateActionRDBMSRepository) FindAssetCorporateActions(
+	assetId int64,
+) ([]*domain.CorporateAction, error) {
+
+	result, err := rdbms.BuildQuery[domain.CorporateAction](repository.dbAdapter, assetCorporateActionsSQL).
+		AddParam("assetId", assetId).
+		Build().
+		FindWithRowScanner(corporateActionRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting corporate actions", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// FindCorporateAction retrieves a corporate action by id, or nil when it does not exist.
+//
+// Example:
+//
+//	action, err := corporateActionRepository.FindCorporateAction(1)
+func (repository *CorporateActionRDBMSRepository) FindCorporateAction(id int64) (*domain.CorporateAction, error) {
+
+	result, err := rdbms.BuildQuery[domain.CorporateAction](repository.dbAdapter, corporateActionSQL).
+		AddParam("id", id).
+		Build().
+		GetWithRowScanner(corporateActionRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting corporate action", repository)
+	}
+
+	return &result, nil
+}
+
+// InsertCorporateAction persists a new pending corporate action, storing the terms not used by its type
+// as NULL, and returns it as persisted.
+//
+// Example:
+//
+//	persistedAction, err := corporateActionRepository.InsertCorporateAction(action)
+func (repository *CorporateActionRDBMSRepository) InsertCorporateAction(
+	action *domain.CorporateAction,
+) (*domain.CorporateAction, error) {
+
+	var queryBuilder = rdbms.BuildQuery[domain.CorporateAction](repository.dbAdapter, corporateActionInsertSQL).
+		AddParam("assetId", action.AssetId).
+		AddParam("actionType", action.ActionType).
+		AddParam("effectiveDate", action.EffectiveDate).
+		AddParam("newTicker", nullableIfZero(action.NewTicker)).
+		AddParam("targetAssetId", nullableIfZero(action.TargetAssetId)).
+		AddParam("cashCurrency", nullableIfZero(action.CashCurrency))
+
+	if action.ActionType == domain.SymbolChangeCorporateActionType {
+		queryBuilder.AddParam("ratioNumerator", nil).AddParam("ratioDenominator", nil)
+	} else {
+		queryBuilder.AddParam("ratioNumerator", action.RatioNumerator).
+			AddParam("ratioDenominator", action.RatioDenominator)
+	}
+
+	if action.CashPerShare.IsZero() {
+		queryBuilder.AddParam("cashPerShare", nil)
+	} else {
+		queryBuilder.AddParam("cashPerShare", action.CashPerShare)
+	}
+
+	result, err := queryBuilder.Build().GetWithRowScanner(corporateActionRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting corporate action", repository)
+	}
+
+	return &result, nil
+}
+
+// DeleteCorporateAction removes a persisted corporate action.
+//
+// Example:
+//
+//	err := corporateActionRepository.DeleteCorporateAction(action)
+func (repository *CorporateActionRDBMSRepository) DeleteCorporateAction(action *domain.CorporateAction) error {
+	err := repository.dbAdapter.Delete(action)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting corporate action", repository)
+}
+
+// FindCorporateActionForUpdateInTransaction retrieves a corporate action by id within an existing SQL
+// transaction, locking it until the transaction ends so it is not applied or reverted concurrently.
+// Returns nil when the action does not exist.
+//
+// Example:
+//
+//	action, err := corporateActionRepository.FindCorporateActionForUpdateInTransaction(transContext, 1)
+func (repository *CorporateActionRDBMSRepository) FindCorporateActionForUpdateInTransaction(
+	transContext context.Context,
+	id int64,
+) (*domain.CorporateAction, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	result, err := rdbms.BuildQueryInTransaction[domain.CorporateAction](
+		transactionalContext,
+		corporateActionForUpdateSQL,
+	).
+		AddParams(id).
+		Build().
+		Find(corporateActionRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting corporate action", repository)
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	return &result[0], nil
+}
+
+// FindCorporateActionsAppliedAfterInTransaction retrieves, within an existing SQL transaction, the
+// applied corporate actions involving any asset of the given action that were applied after it, the
+// latest first.
+//
+// Example:
+//
+//	laterActions, err := corporateActionRepository.FindCorporateActionsAppliedAfterInTransaction(
+//		transContext,
+//		action,
+//	)
+func (repository *CorporateActionRDBMSRepository) FindCorporateActionsAppliedAfterInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+) ([]*domain.CorporateAction, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	result, err := rdbms.BuildQueryInTransaction[domain.CorporateAction](
+		transactionalContext,
+		corporateActionsAppliedAfterSQL,
+	).
+		AddParams(action.Id, action.AppliedAt, action.InvolvedAssetIds()).
+		Build().
+		Find(corporateActionRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(
+			err,
+			"Error getting corporate actions applied later",
+			repository,
+		)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// UpdateCorporateActionStatusInTransaction persists the status, application time and reversal data of
+// a corporate action within an existing SQL transaction.
+//
+// Example:
+//
+//	err := corporateActionRepository.UpdateCorporateActionStatusInTransaction(transContext, action)
+func (repository *CorporateActionRDBMSRepository) UpdateCorporateActionStatusInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	var reversal any
+	if action.Reversal != nil {
+		reversal = *action.Reversal
+	}
+
+	_, err := repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		corporateActionStatusUpdateSQL,
+		action.Status,
+		action.AppliedAt,
+		reversal,
+		action.Id,
+	)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error updating corporate action status", repository)
+}
+
+// FindLaterPortfolioAllocationsInTransaction retrieves, within an existing SQL transaction, the observed
+// portfolio allocations that applying the action changes: the allocations of the action asset, and of
+// its merger target, in the observations from the effective date on that hold the action asset.
+//
+// Example:
+//
+//	snapshots, err := corporateActionRepository.FindLaterPortfolioAllocationsInTransaction(transContext, action)
+func (repository *CorporateActionRDBMSRepository) FindLaterPortfolioAllocationsInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+) ([]*domain.CorporateActionPortfolioAllocationSnapshot, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	result, err := rdbms.BuildQueryInTransaction[[]*domain.CorporateActionPortfolioAllocationSnapshot](
+		transactionalContext,
+		laterPortfolioAllocationsSQL,
+	).
+		AddParams(action.AssetId, action.TargetAssetId, action.EffectiveDate).
+		Build().
+		Get(jsonArrayRowScanner[domain.CorporateActionPortfolioAllocationSnapshot])
+
+	return result, infra.PropagateAsAppErrorWithNewMessage(
+		err,
+		"Error getting portfolio allocations affected by corporate action",
+		repository,
+	)
+}
+
+// AdjustLaterPortfolioAllocationsInTransaction applies a split, reverse split or merger to the portfolio
+// allocations observed from its effective date on, within an existing SQL transaction. Splits convert
+// the quantities and prices of the asset by the action ratio. Mergers convert the allocations of the
+// asset into allocations of the target asset, adding them to the target allocations already observed
+// in the same classification. Market values are unchanged.
+//
+// Example:
+//
+//	err := corporateActionRepository.AdjustLaterPortfolioAllocationsInTransaction(transContext, action)
+func (repository *CorporateActionRDBMSRepository) AdjustLaterPortfolioAllocationsInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	var statements []corporateActionStatement
+
+	switch action.ActionType {
+	case domain.SplitCorporateActionType, domain.ReverseSplitCorporateActionType:
+		statements = []corporateActionStatement{
+			{
+				sql:    laterPortfolioAllocationsSplitSQL,
+				params: []any{action.AssetId, action.Ratio(), action.EffectiveDate},
+			},
+		}
+	case domain.MergerCorporateActionType:
+		var ratio = action.Ratio()
+		statements = []corporateActionStatement{
+			{
+				sql:    laterPortfolioAllocationsMergeIntoTargetSQL,
+				params: []any{action.AssetId, action.TargetAssetId, ratio, action.EffectiveDate},
+			},
+			{
+				sql:    laterPortfolioAllocationsMergedDeleteSQL,
+				params: []any{action.AssetId, action.TargetAssetId, action.EffectiveDate},
+			},
+			{
+				sql:    laterPortfolioAllocationsMergeMoveSQL,
+				params: []any{action.AssetId, action.TargetAssetId, ratio, action.EffectiveDate},
+			},
+		}
+	}
+
+	for _, statement := range statements {
+		_, err := repository.dbAdapter.ExecuteInTransaction(transactionalContext, statement.sql, statement.params...)
+		if err != nil {
+			return infra.PropagateAsAppErrorWithNewMessage(
+				err,
+				"Error adjusting portfolio allocations for corporate action",
+				repository,
+			)
+		}
+	}
+
+	return nil
+}
+
+// RestorePortfolioAllocationsInTransaction replaces, within an existing SQL transaction, the current
+// allocations of the assets involved in the action with the snapshot taken before applying it, in the
+// observations present in the snapshot.
+//
+// Example:
+//
+//	err := corporateActionRepository.RestorePortfolioAllocationsInTransaction(
+//		transContext,
+//		action,
+//		action.Reversal.PortfolioAllocations,
+//	)
+func (repository *CorporateActionRDBMSRepository) RestorePortfolioAllocationsInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+	snapshots []*domain.CorporateActionPortfolioAllocationSnapshot,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	snapshotsJSON, err := json.Marshal(snapshots)
+	if err != nil {
+		return infra.PropagateAsAppErrorWithNewMessage(err, "Error serializing portfolio allocations", repository)
+	}
+
+	_, err = repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		snapshotPortfolioAllocationsDeleteSQL,
+		string(snapshotsJSON),
+		action.InvolvedAssetIds(),
+	)
+	if err != nil {
+		return infra.PropagateAsAppErrorWithNewMessage(
+			err,
+			"Error removing portfolio allocations adjusted by corporate action",
+			repository,
+		)
+	}
+
+	_, err = repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		snapshotPortfolioAllocationsInsertSQL,
+		string(snapshotsJSON),
+	)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error restoring portfolio allocations", repository)
+}
+
+// FindLaterPlannedAllocationsInTransaction retrieves, within an existing SQL transaction, the planned
+// allocations of the action asset in plans without execution date or executed from the effective date
+// on.
+//
+// Example:
+//
+//	snapshots, err := corporateActionRepository.FindLaterPlannedAllocationsInTransaction(transContext, action)
+func (repository *CorporateActionRDBMSRepository) FindLaterPlannedAllocationsInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+) ([]*domain.CorporateActionPlannedAllocationSnapshot, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	result, err := rdbms.BuildQueryInTransaction[[]*domain.CorporateActionPlannedAllocationSnapshot](
+		transactionalContext,
+		laterPlannedAllocationsSQL,
+	).
+		AddParams(action.AssetId, action.EffectiveDate).
+		Build().
+		Get(jsonArrayRowScanner[domain.CorporateActionPlannedAllocationSnapshot])
+
+	return result, infra.PropagateAsAppErrorWithNewMessage(
+		err,
+		"Error getting planned allocations affected by corporate action",
+		repository,
+	)
+}
+
+// ReassignLaterPlannedAllocationsInTransaction points the later planned allocations of the action asset
+// to the new asset within an existing SQL transaction, replacing the previous ticker with the new asset
+// ticker in their hierarchical ids.
+//
+// Example:
+//
+//	err := corporateActionRepository.ReassignLaterPlannedAllocationsInTransaction(
+//		transContext,
+//		action,
+//		"OLD",
+//		targetAsset,
+//	)
+func (repository *CorporateActionRDBMSRepository) ReassignLaterPlannedAllocationsInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+	previousTicker string,
+	newAsset *domain.Asset,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	_, err := repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		laterPlannedAllocationsReassignSQL,
+		action.AssetId,
+		previousTicker,
+		newAsset.Ticker,
+		newAsset.Id,
+		action.EffectiveDate,
+	)
+	return infra.PropagateAsAppErrorWithNewMessage(
+		err,
+		"Error reassigning planned allocations for corporate action",
+		repository,
+	)
+}
+
+// RestorePlannedAllocationsInTransaction restores the asset and hierarchical id of planned allocations
+// from the snapshot taken before applying a corporate action, within an existing SQL transaction.
+//
+// Example:
+//
+//	err := corporateActionRepository.RestorePlannedAllocationsInTransaction(
+//		transContext,
+//		action.Reversal.PlannedAllocations,
+//	)
+func (repository *CorporateActionRDBMSRepository) RestorePlannedAllocationsInTransaction(
+	transContext context.Context,
+	snapshots []*domain.CorporateActionPlannedAllocationSnapshot,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	snapshotsJSON, err := json.Marshal(snapshots)
+	if err != nil {
+		return infra.PropagateAsAppErrorWithNewMessage(err, "Error serializing planned allocations", repository)
+	}
+
+	_, err = repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		snapshotPlannedAllocationsRestoreSQL,
+		string(snapshotsJSON),
+	)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error restoring planned allocations", repository)
+}
+
+func BuildCorporateActionRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *CorporateActionRDBMSRepository {
+	return &CorporateActionRDBMSRepository{dbAdapter: dbAdapter}
+}
@@ -0,0 +1,485 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	divergenceAlertRuleColumnsSQL = `
+		id, portfolio_id, allocation_plan_id, name, rule_type, hierarchical_id, threshold_percent, enabled,
+		created_at
+	`
+	divergenceAlertRulesSQL = `
+		SELECT ` + divergenceAlertRuleColumnsSQL + `
+		FROM divergence_alert_rule
+		WHERE portfolio_id = {:portfolioId}
+		ORDER BY id
+	`
+	enabledDivergenceAlertRulesSQL = `
+		SELECT ` + divergenceAlertRuleColumnsSQL + `
+		FROM divergence_alert_rule
+		WHERE portfolio_id = {:portfolioId} AND enabled
+		ORDER BY allocation_plan_id, id
+	`
+	divergenceAlertRuleSQL = `
+		SELECT ` + divergenceAlertRuleColumnsSQL + `
+		FROM divergence_alert_rule
+		WHERE portfolio_id = {:portfolioId} AND id = {:ruleId}
+	`
+	divergenceAlertRuleInsertSQL = `
+		INSERT INTO divergence_alert_rule (
+			portfolio_id, allocation_plan_id, name, rule_type, hierarchical_id, threshold_percent, enabled
+		)
+		VALUES (
+			{:portfolioId}, {:allocationPlanId}, {:name}, {:ruleType}, {:hierarchicalId}, {:thresholdPercent},
+			{:enabled}
+		)
+		RETURNING ` + divergenceAlertRuleColumnsSQL
+	divergenceAlertRuleUpdateSQL = `
+		UPDATE divergence_alert_rule
+		SET allocation_plan_id = {:allocationPlanId}, name = {:name}, rule_type = {:ruleType},
+			hierarchical_id = {:hierarchicalId}, threshold_percent = {:thresholdPercent}, enabled = {:enabled}
+		WHERE portfolio_id = {:portfolioId} AND id = {:ruleId}
+		RETURNING ` + divergenceAlertRuleColumnsSQL
+	divergenceAlertRuleDeleteSQL = `
+		DELETE FROM divergence_alert_rule
+		WHERE portfolio_id = {:portfolioId} AND id = {:ruleId}
+		RETURNING id
+	`
+	// divergenceAlertRuleLockSQL serializes the concurrent evaluations of a rule, so its open alerts are not
+	// triggered twice
+	divergenceAlertRuleLockSQL = `
+		SELECT id FROM divergence_alert_rule WHERE id = $1 FOR UPDATE
+	`
+	divergenceAlertColumnsSQL = `
+		alert.id, alert.rule_id, alert_rule.name, alert_rule.rule_type, alert.portfolio_id, alert.hierarchical_id,
+		alert.status, alert.threshold_percent, alert.observed_percent, alert.observation_time_id,
+		alert.triggered_at, alert.last_evaluated_at, alert.acknowledged_at, alert.resolved_at
+	`
+	openRuleDivergenceAlertsSQL = `
+		SELECT ` + divergenceAlertColumnsSQL + `
+		FROM divergence_alert alert
+		JOIN divergence_alert_rule alert_rule ON alert_rule.id = alert.rule_id
+		WHERE alert.rule_id = $1 AND alert.status <> 'RESOLVED'
+		ORDER BY alert.id
+	`
+	divergenceAlertInsertSQL = `
+		INSERT INTO divergence_alert (
+			rule_id, portfolio_id, hierarchical_id, threshold_percent, observed_percent, observation_time_id,
+			triggered_at, last_evaluated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		RETURNING id
+	`
+	divergenceAlertEvaluationUpdateSQL = `
+		UPDATE divergence_alert
+		SET status = $2, observed_percent = $3, observation_time_id = $4, last_evaluated_at = $5,
+			resolved_at = $6
+		WHERE id = $1
+	`
+	portfolioDivergenceAlertsSQL = `
+		SELECT ` + divergenceAlertColumnsSQL + `
+		FROM divergence_alert alert
+		JOIN divergence_alert_rule alert_rule ON alert_rule.id = alert.rule_id
+		WHERE alert.portfolio_id = {:portfolioId}
+			AND ({:status}::varchar IS NULL OR alert.status = {:status})
+		ORDER BY alert.triggered_at DESC, alert.id DESC
+		LIMIT {:limit}
+	`
+	portfolioDivergenceAlertSQL = `
+		SELECT ` + divergenceAlertColumnsSQL + `
+		FROM divergence_alert alert
+		JOIN divergence_alert_rule alert_rule ON alert_rule.id = alert.rule_id
+		WHERE alert.portfolio_id = {:portfolioId} AND alert.id = {:alertId}
+	`
+	divergenceAlertStatusUpdateSQL = `
+		UPDATE divergence_alert
+		SET status = {:status}, acknowledged_at = {:acknowledgedAt}, resolved_at = {:resolvedAt}
+		WHERE portfolio_id = {:portfolioId} AND id = {:alertId}
+		RETURNING id
+	`
+)
+
+func divergenceAlertRuleRowScanner(rows *sql.Rows) (domain.DivergenceAlertRule, error) {
+	var rule domain.DivergenceAlertRule
+	scanErr := rows.Scan(
+		&rule.Id,
+		&rule.PortfolioId,
+		&rule.AllocationPlanId,
+		&rule.Name,
+		&rule.RuleType,
+		&rule.HierarchicalId,
+		&rule.ThresholdPercent,
+		&rule.Enabled,
+		&rule.CreatedAt,
+	)
+	return rule, scanErr
+}
+
+func divergenceAlertRowScanner(rows *sql.Rows) (domain.DivergenceAlert, error) {
+	var alert domain.DivergenceAlert
+	scanErr := rows.Scan(
+		&alert.Id,
+		&alert.RuleId,
+		&alert.RuleName,
+		&alert.RuleType,
+		&alert.PortfolioId,
+		&alert.HierarchicalId,
+		&alert.Status,
+		&alert.ThresholdPercent,
+		&alert.ObservedPercent,
+		&alert.ObservationTimestampId,
+		&alert.TriggeredAt,
+		&alert.LastEvaluatedAt,
+		&alert.AcknowledgedAt,
+		&alert.ResolvedAt,
+	)
+	return alert, scanErr
+}
+
+type DivergenceAlertRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+// FindPortfolioAlertRules retrieves the alert rules of a portfolio, in creation order.
+//
+// Example:
+//
+//	rules, err := divergenceAlertRepository.FindPortfolioAlertRules(1)
+func (repository *DivergenceAlertRDBMSRepository) FindPortfolioAlertRules(
+	portfolioId int64,
+) ([]*domain.DivergenceAlertRule, error) {
+	return repository.findAlertRules(divergenceAlertRulesSQL, portfolioId)
+}
+
+// FindEnabledPortfolioAlertRules retrieves the enabled alert rules of a portfolio, grouped by allocation plan.
+//
+// Example:
+//
+//	rules, err := divergenceAlertRepository.FindEnabledPortfolioAlertRules(1)
+func (repository *DivergenceAlertRDBMSRepository) FindEnabledPortfolioAlertRules(
+	portfolioId int64,
+) ([]*domain.DivergenceAlertRule, error) {
+	return repository.findAlertRules(enabledDivergenceAlertRulesSQL, portfolioId)
+}
+
+func (repository *DivergenceAlertRDBMSRepository) findAlertRules(
+	querySQL string,
+	portfolioId int64,
+) ([]*domain.DivergenceAlertRule, error) {
+
+	result, err := rdbms.BuildQuery[domain.DivergenceAlertRule](repository.dbAdapter, querySQL).
+		AddParam("portfolioId", portfolioId).
+		Build().
+		FindWithRowScanner(divergenceAlertRuleRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting divergence alert rules", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// FindPortfolioAlertRule retrieves an alert rule of a portfolio, or nil when it does not exist.
+//
+// Example:
+//
+//	rule, err := divergenceAlertRepository.FindPortfolioAlertRule(1, 2)
+func (repository *DivergenceAlertRDBMSRepository) FindPortfolioAlertRule(
+	portfolioId int64,
+	ruleId int64,
+) (*domain.DivergenceAlertRule, error) {
+
+	result, err := rdbms.BuildQuery[domain.DivergenceAlertRule](repository.dbAdapter, divergenceAlertRuleSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("ruleId", ruleId).
+		Build().
+		GetWithRowScanner(divergenceAlertRuleRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting divergence alert rule", repository)
+	}
+
+	return &result, nil
+}
+
+// InsertAlertRule persists a new alert rule and returns it as persisted.
+//
+// Example:
+//
+//	persistedRule, err := divergenceAlertRepository.InsertAlertRule(rule)
+func (repository *DivergenceAlertRDBMSRepository) InsertAlertRule(
+	rule *domain.DivergenceAlertRule,
+) (*domain.DivergenceAlertRule, error) {
+
+	result, err := rdbms.BuildQuery[domain.DivergenceAlertRule](repository.dbAdapter, divergenceAlertRuleInsertSQL).
+		AddParam("portfolioId", rule.PortfolioId).
+		AddParam("allocationPlanId", rule.AllocationPlanId).
+		AddParam("name", rule.Name).
+		AddParam("ruleType", rule.RuleType).
+		AddParam("hierarchicalId", rule.HierarchicalId).
+		AddParam("thresholdPercent", rule.ThresholdPercent).
+		AddParam("enabled", rule.Enabled).
+		Build().
+		GetWithRowScanner(divergenceAlertRuleRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting divergence alert rule", repository)
+	}
+
+	return &result, nil
+}
+
+// UpdateAlertRule replaces the definition of an alert rule of a portfolio and returns it as updated, or nil
+// when it does not exist.
+//
+// Example:
+//
+//	updatedRule, err := divergenceAlertRepository.UpdateAlertRule(rule)
+func (repository *DivergenceAlertRDBMSRepository) UpdateAlertRule(
+	rule *domain.DivergenceAlertRule,
+) (*domain.DivergenceAlertRule, error) {
+
+	result, err := rdbms.BuildQuery[domain.DivergenceAlertRule](repository.dbAdapter, divergenceAlertRuleUpdateSQL).
+		AddParam("portfolioId", rule.PortfolioId).
+		AddParam("ruleId", rule.Id).
+		AddParam("allocationPlanId", rule.AllocationPlanId).
+		AddParam("name", rule.Name).
+		AddParam("ruleType", rule.RuleType).
+		AddParam("hierarchicalId", rule.HierarchicalId).
+		AddParam("thresholdPercent", rule.ThresholdPercent).
+		AddParam("enabled", rule.Enabled).
+		Build().
+		GetWithRowScanner(divergenceAlertRuleRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating divergence alert rule", repository)
+	}
+
+	return &result, nil
+}
+
+// DeleteAlertRule removes an alert rule of a portfolio with its alerts, returning false when it does not
+// exist.
+//
+// Example:
+//
+//	deleted, err := divergenceAlertRepository.DeleteAlertRule(1, 2)
+func (repository *DivergenceAlertRDBMSRepository) DeleteAlertRule(portfolioId int64, ruleId int64) (bool, error) {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, divergenceAlertRuleDeleteSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("ruleId", ruleId).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting divergence alert rule", repository)
+	}
+
+	return true, nil
+}
+
+// FindOpenRuleAlertsInTransaction locks an alert rule until the end of the transaction and retrieves its
+// open alerts.
+//
+// Example:
+//
+//	err := adapter.RunInTransaction(func(transContext *rdbms.SQLTransactionalContext) error {
+//		openAlerts, err := divergenceAlertRepository.FindOpenRuleAlertsInTransaction(transContext, 2)
+//		// ... evaluation of the rule
+//	})
+func (repository *DivergenceAlertRDBMSRepository) FindOpenRuleAlertsInTransaction(
+	transContext context.Context,
+	ruleId int64,
+) ([]*domain.DivergenceAlert, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	_, err := repository.dbAdapter.ExecuteInTransaction(transactionalContext, divergenceAlertRuleLockSQL, ruleId)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error locking divergence alert rule", repository)
+	}
+
+	result, err := rdbms.BuildQueryInTransaction[domain.DivergenceAlert](
+		transactionalContext,
+		openRuleDivergenceAlertsSQL,
+	).
+		AddParams(ruleId).
+		Build().
+		Find(divergenceAlertRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting open divergence alerts", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// InsertAlertInTransaction persists a triggered alert within an existing SQL transaction, setting its id.
+//
+// Example:
+//
+//	err := divergenceAlertRepository.InsertAlertInTransaction(transContext, alert)
+func (repository *DivergenceAlertRDBMSRepository) InsertAlertInTransaction(
+	transContext context.Context,
+	alert *domain.DivergenceAlert,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	id, err := rdbms.BuildQueryInTransaction[int64](transactionalContext, divergenceAlertInsertSQL).
+		AddParams(
+			alert.RuleId,
+			alert.PortfolioId,
+			alert.HierarchicalId,
+			alert.ThresholdPercent,
+			alert.ObservedPercent,
+			alert.ObservationTimestampId,
+			alert.TriggeredAt,
+		).
+		Build().
+		Get(rdbms.ReturningIntIdSingleRowScanner)
+	if err != nil {
+		return infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting divergence alert", repository)
+	}
+	alert.Id = id
+
+	return nil
+}
+
+// UpdateAlertEvaluationInTransaction records the outcome of the evaluation of an open alert within an
+// existing SQL transaction, with its status and observed percentage.
+//
+// Example:
+//
+//	err := divergenceAlertRepository.UpdateAlertEvaluationInTransaction(transContext, alert)
+func (repository *DivergenceAlertRDBMSRepository) UpdateAlertEvaluationInTransaction(
+	transContext context.Context,
+	alert *domain.DivergenceAlert,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	_, err := repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		divergenceAlertEvaluationUpdateSQL,
+		alert.Id,
+		string(alert.Status),
+		alert.ObservedPercent,
+		alert.ObservationTimestampId,
+		alert.LastEvaluatedAt,
+		alert.ResolvedAt,
+	)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error updating divergence alert", repository)
+}
+
+// FindPortfolioAlerts retrieves the latest alerts of a portfolio, the most recently triggered first,
+// optionally only the ones with a status.
+//
+// Example:
+//
+//	alerts, err := divergenceAlertRepository.FindPortfolioAlerts(1, nil, 50)
+func (repository *DivergenceAlertRDBMSRepository) FindPortfolioAlerts(
+	portfolioId int64,
+	status *domain.DivergenceAlertStatus,
+	limit int,
+) ([]*domain.DivergenceAlert, error) {
+
+	result, err := rdbms.BuildQuery[domain.DivergenceAlert](repository.dbAdapter, portfolioDivergenceAlertsSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("status", status).
+		AddParam("limit", limit).
+		Build().
+		FindWithRowScanner(divergenceAlertRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting divergence alerts", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// FindPortfolioAlert retrieves an alert of a portfolio, or nil when it does not exist.
+//
+// Example:
+//
+//	alert, err := divergenceAlertRepository.FindPortfolioAlert(1, 2)
+func (repository *DivergenceAlertRDBMSRepository) FindPortfolioAlert(
+	portfolioId int64,
+	alertId int64,
+) (*domain.DivergenceAlert, error) {
+
+	result, err := rdbms.BuildQuery[domain.DivergenceAlert](repository.dbAdapter, portfolioDivergenceAlertSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("alertId", alertId).
+		Build().
+		GetWithRowScanner(divergenceAlertRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting divergence alert", repository)
+	}
+
+	return &result, nil
+}
+
+// UpdateAlertStatus records a status change of an alert made by the user and returns the alert as updated,
+// or nil when it does not exist.
+//
+// Example:
+//
+//	updatedAlert, err := divergenceAlertRepository.UpdateAlertStatus(alert)
+func (repository *DivergenceAlertRDBMSRepository) UpdateAlertStatus(
+	alert *domain.DivergenceAlert,
+) (*domain.DivergenceAlert, error) {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, divergenceAlertStatusUpdateSQL).
+		AddParam("portfolioId", alert.PortfolioId).
+		AddParam("alertId", alert.Id).
+		AddParam("status", alert.Status).
+		AddParam("acknowledgedAt", alert.AcknowledgedAt).
+		AddParam("resolvedAt", alert.ResolvedAt).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating divergence alert", repository)
+	}
+
+	return repository.FindPortfolioAlert(alert.PortfolioId, alert.Id)
+}
+
+func BuildDivergenceAlertRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *DivergenceAlertRDBMSRepository {
+	return &DivergenceAlertRDBMSRepository{dbAdapter: dbAdapter}
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+)
+
+const (
+	idempotentRequestClaimSQL = `
+		INSERT INTO idempotency_key (scope, idempotency_key, request_fingerprint, status, created_at, expires_at)
+		VALUES ({:scope}, {:key}, {:fingerprint}, {:status}, {:createdAt}, {:expiresAt})
+		ON CONFLICT (scope, idempotency_key) DO UPDATE SET
+			request_fingerprint = EXCLUDED.request_fingerprint,
+			status = EXCLUDED.status,
+			response_status = NULL,
+			response_headers = NULL,
+			response_body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_key.expires_at <= EXCLUDED.created_at
+		RETURNING idempotency_key
+	`
+	idempotentRequestSQL = `
+		SELECT scope, idempotency_key, request_fingerprint, status, response_status, response_headers,
+			response_body, created_at, expires_at
+		FROM idempotency_key
+		WHERE scope = {:scope} AND idempotency_key = {:key}
+	`
+	idempotentRequestCompleteSQL = `
+		UPDATE idempotency_key SET
+			status = {:status},
+			response_status = {:responseStatus},
+			response_headers = {:responseHeaders},
+			response_body = {:responseBody}
+		WHERE scope = {:scope} AND idempotency_key = {:key}
+		RETURNING idempotency_key
+	`
+	idempotentRequestDeleteSQL = `
+		WITH deleted AS (
+			DELETE FROM idempotency_key WHERE scope = {:scope} AND idempotency_key = {:key} RETURNING scope
+		)
+		SELECT count(*) AS count FROM deleted
+	`
+	expiredIdempotentRequestsDeleteSQL = `
+		WITH deleted AS (
+			DELETE FROM idempotency_key WHERE expires_at <= {:now} RETURNING scope
+		)
+		SELECT count(*) AS count FROM deleted
+	`
+)
+
+func idempotencyKeyRowScanner(rows *sql.Rows) (string, error) {
+	var key string
+	scanErr := rows.Scan(&key)
+	return key, scanErr
+}
+
+func idempotentRequestRowScanner(rows *sql.Rows) (domain.IdempotentRequest, error) {
+
+	var request domain.IdempotentRequest
+	var responseStatus sql.NullInt64
+	var responseHeaders []byte
+	var responseBody []byte
+
+	scanErr := rows.Scan(
+		&request.Scope,
+		&request.Key,
+		&request.Fingerprint,
+		&request.Status,
+		&responseStatus,
+		&responseHeaders,
+		&responseBody,
+		&request.CreatedAt,
+		&request.ExpiresAt,
+	)
+	if scanErr != nil || !responseStatus.Valid {
+		return request, scanErr
+	}
+
+	request.Response = &domain.IdempotentResponse{
+		StatusCode: int(responseStatus.Int64),
+		Body:       responseBody,
+	}
+	if len(responseHeaders) > 0 {
+		scanErr = json.Unmarshal(responseHeaders, &request.Response.Headers)
+	}
+
+	return request, scanErr
+}
+
+// IdempotencyRDBMSRepository is a domain.IdempotencyRepository persisting the requests in the
+// idempotency_key table, so duplicates are recognized across restarts and application instances.
+type IdempotencyRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+func (repository *IdempotencyRDBMSRepository) ClaimIdempotentRequest(request *domain.IdempotentRequest) (bool, error) {
+
+	_, err := rdbms.BuildQuery[string](repository.dbAdapter, idempotentRequestClaimSQL).
+		AddParam("scope", request.Scope).
+		AddParam("key", request.Key).
+		AddParam("fingerprint", request.Fingerprint).
+		AddParam("status", domain.ProcessingIdempotentRequestStatus).
+		AddParam("createdAt", request.CreatedAt).
+		AddParam("expiresAt", request.ExpiresAt).
+		Build().
+		GetWithRowScanner(idempotencyKeyRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error claiming idempotency key", repository)
+	}
+
+	request.Status = domain.ProcessingIdempotentRequestStatus
+	return true, nil
+}
+
+func (repository *IdempotencyRDBMSRepository) FindIdempotentRequest(
+	scope string,
+	key string,
+) (*domain.IdempotentRequest, error) {
+
+	result, err := rdbms.BuildQuery[domain.IdempotentRequest](repository.dbAdapter, idempotentRequestSQL).
+		AddParam("scope", scope).
+		AddParam("key", key).
+		Build().
+		GetWithRowScanner(idempotentRequestRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting idempotent request", repository)
+	}
+
+	return &result, nil
+}
+
+func (repository *IdempotencyRDBMSRepository) CompleteIdempotentRequest(
+	scope string,
+	key string,
+	response *domain.IdempotentResponse,
+) error {
+
+	responseHeaders, err := json.Marshal(response.Headers)
+	if err != nil {
+		return infra.PropagateAsAppErrorWithNewMessage(err, "Error serializing idempotent response headers", repository)
+	}
+
+	_, err = rdbms.BuildQuery[string](repository.dbAdapter, idempotentRequestCompleteSQL).
+		AddParam("scope", scope).
+		AddParam("key", key).
+		AddParam("status", domain.CompletedIdempotentRequestStatus).
+		AddParam("responseStatus", response.StatusCode).
+		AddParam("responseHeaders", string(responseHeaders)).
+		AddParam("responseBody", response.Body).
+		Build().
+		GetWithRowScanner(idempotencyKeyRowScanner)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error completing idempotent request", repository)
+}
+
+func (repository *IdempotencyRDBMSRepository) DeleteIdempotentRequest(scope string, key string) error {
+	return repository.deleteIdempotentRequests(
+		rdbms.BuildQuery[deletedRowsCountDTS](repository.dbAdapter, idempotentRequestDeleteSQL).
+			AddParam("scope", scope).
+			AddParam("key", key),
+	)
+}
+
+func (repository *IdempotencyRDBMSRepository) DeleteExpiredIdempotentRequests(now time.Time) error {
+	return repository.deleteIdempotentRequests(
+		rdbms.BuildQuery[deletedRowsCountDTS](repository.dbAdapter, expiredIdempotentRequestsDeleteSQL).
+			AddParam("now", now),
+	)
+}
+
+func (repository *IdempotencyRDBMSRepository) deleteIdempotentRequests(
+	queryBuilder *rdbms.QueryBuilder[deletedRowsCountDTS],
+) error {
+	var result deletedRowsCountDTS
+	err := queryBuilder.Build().GetInto(&result)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting idempotent requests", repository)
+}
+
+func BuildIdempotencyRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *IdempotencyRDBMSRepository {
+	return &IdempotencyRDBMSRepository{
+		dbAdapter: dbAdapter,
+	}
+}
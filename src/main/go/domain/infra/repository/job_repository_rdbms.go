@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	jobColumnsSQL = `
+		id, job_type, coalesce(created_by_user_id, 0), params, status, progress_completed, progress_total, result, coalesce(error_message, ''),
+		created_at, started_at, finished_at
+	`
+	jobsSQL = `
+		SELECT ` + jobColumnsSQL + `
+		FROM job
+	` + rdbms.WhereClausePlaceholder + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT {:limit}
+	`
+	jobSQL = `
+		SELECT ` + jobColumnsSQL + `
+		FROM job
+		WHERE id = {:id}
+	`
+	jobInsertSQL = `
+		INSERT INTO job (job_type, params, created_by_user_id)
+		VALUES ({:jobType}, {:params}, {:createdByUserId})
+		RETURNING ` + jobColumnsSQL
+	// jobStartSQL moves a pending job to running, selecting nothing when the job is not pending anymore
+	jobStartSQL = `
+		UPDATE job
+		SET status = 'RUNNING', started_at = now(), progress_completed = 0, progress_total = 0
+		WHERE id = {:id} AND status = 'PENDING'
+		RETURNING ` + jobColumnsSQL
+	jobProgressUpdateSQL = `
+		UPDATE job
+		SET progress_completed = {:completed}, progress_total = {:total}
+		WHERE id = {:id}
+		RETURNING id
+	`
+	// jobFinishSQL records the outcome of a job that is not finished yet, selecting nothing otherwise
+	jobFinishSQL = `
+		UPDATE job
+		SET status = {:status}, result = {:result}, error_message = {:errorMessage}, finished_at = now()
+		WHERE id = {:id} AND status IN ('PENDING', 'RUNNING')
+		RETURNING ` + jobColumnsSQL
+	runningJobsRequeueSQL = `
+		UPDATE job
+		SET status = 'PENDING', started_at = NULL
+		WHERE status = 'RUNNING'
+		RETURNING id
+	`
+)
+
+const defaultJobsLimit = 100
+
+// jobRowScanner reads a persisted job, including its optional JSON result, into the domain model.
+func jobRowScanner(rows *sql.Rows) (domain.Job, error) {
+
+	var job domain.Job
+	var params, result []byte
+
+	scanErr := rows.Scan(
+		&job.Id,
+		&job.JobType,
+		&job.CreatedByUserId,
+		&params,
+		&job.Status,
+		&job.ProgressCompleted,
+		&job.ProgressTotal,
+		&result,
+		&job.ErrorMessage,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+	)
+
+	job.Params = params
+	job.Result = result
+
+	return job, scanErr
+}
+
+// nullableJSON returns the JSON document as text for a jsonb parameter, or nil for an empty document,
+// so it is persisted as NULL.
+func nullableJSON(document []byte) any {
+	if len(document) == 0 {
+		return nil
+	}
+	return string(document)
+}
+
+type JobRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+// FindJobs retrieves the jobs selected by the filter, the most recently created first, limited to the
+// filter limit, to defaultJobsLimit when it is zero, or unlimited when it is negative.
+//
+// Example:
+//
+//	jobs, err := jobRepository.FindJobs(&domain.JobFilter{Status: domain.RunningJobStatus})
+func (repository *JobRDBMSRepository) FindJobs(filter *domain.JobFilter) ([]*domain.Job, error) {
+
+	// LIMIT NULL does not limit
+	var limit any = filter.Limit
+	if filter.Limit == 0 {
+		limit = defaultJobsLimit
+	} else if filter.Limit < 0 {
+		limit = nil
+	}
+
+	var queryBuilder = rdbms.BuildQuery[domain.Job](repository.dbAdapter, jobsSQL).AddParam("limit", limit)
+
+	if filter.Status != "" {
+		queryBuilder.AddWhereClauseAndParam("AND status = {:status}", "status", filter.Status)
+	}
+
+	if filter.JobType != "" {
+		queryBuilder.AddWhereClauseAndParam("AND job_type = {:jobType}", "jobType", filter.JobType)
+	}
+
+	if filter.CreatedByUserId != 0 {
+		queryBuilder.AddWhereClauseAndParam(
+			"AND created_by_user_id = {:createdByUserId}",
+			"createdByUserId",
+			filter.CreatedByUserId,
+		)
+	}
+
+	result, err := queryBuilder.Build().FindWithRowScanner(jobRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting jobs", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// FindJob retrieves a job by id, or nil when it does not exist.
+//
+// Example:
+//
+//	job, err := jobRepository.FindJob(1)
+func (repository *JobRDBMSRepository) FindJob(id int64) (*domain.Job, error) {
+
+	result, err := rdbms.BuildQuery[domain.Job](repository.dbAdapter, jobSQL).
+		AddParam("id", id).
+		Build().
+		GetWithRowScanner(jobRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting job", repository)
+	}
+
+	return &result, nil
+}
+
+// InsertJob persists a new pending job and returns it as persisted.
+//
+// Example:
+//
+//	persistedJob, err := jobRepository.InsertJob(&domain.Job{JobType: domain.AssetEventImportJobType})
+func (repository *JobRDBMSRepository) InsertJob(job *domain.Job) (*domain.Job, error) {
+
+	var params = nullableJSON(job.Params)
+	if params == nil {
+		params = "{}"
+	}
+
+	result, err := rdbms.BuildQuery[domain.Job](repository.dbAdapter, jobInsertSQL).
+		AddParam("jobType", job.JobType).
+		AddParam("params", params).
+		AddParam("createdByUserId", nullableIfZero(job.CreatedByUserId)).
+		Build().
+		GetWithRowScanner(jobRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting job", repository)
+	}
+
+	return &result, nil
+}
+
+// StartJob moves a pending job to running and returns it as updated, or nil when the job is not
+// pending anymore.
+//
+// Example:
+//
+//	startedJob, err := jobRepository.StartJob(1)
+func (repository *JobRDBMSRepository) StartJob(id int64) (*domain.Job, error) {
+
+	result, err := rdbms.BuildQuery[domain.Job](repository.dbAdapter, jobStartSQL).
+		AddParam("id", id).
+		Build().
+		GetWithRowScanner(jobRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error starting job", repository)
+	}
+
+	return &result, nil
+}
+
+// UpdateJobProgress records the progress reported by a running job.
+//
+// Example:
+//
+//	err := jobRepository.UpdateJobProgress(1, 3, 10)
+func (repository *JobRDBMSRepository) UpdateJobProgress(id int64, completed int, total int) error {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, jobProgressUpdateSQL).
+		AddParam("id", id).
+		AddParam("completed", completed).
+		AddParam("total", total).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error updating job progress", repository)
+}
+
+// FinishJob records the final status, result and error message of a job that is not finished yet,
+// and returns it as updated, or nil when the job was already finished.
+//
+// Example:
+//
+//	finishedJob, err := jobRepository.FinishJob(job)
+func (repository *JobRDBMSRepository) FinishJob(job *domain.Job) (*domain.Job, error) {
+
+	result, err := rdbms.BuildQuery[domain.Job](repository.dbAdapter, jobFinishSQL).
+		AddParam("id", job.Id).
+		AddParam("status", job.Status).
+		AddParam("result", nullableJSON(job.Result)).
+		AddParam("errorMessage", nullableIfZero(job.ErrorMessage)).
+		Build().
+		GetWithRowScanner(jobRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error finishing job", repository)
+	}
+
+	return &result, nil
+}
+
+// RequeueRunningJobs moves the jobs left running by a previous execution of the application back to
+// pending, so they are run again, and returns how many were requeued.
+//
+// Example:
+//
+//	requeuedCount, err := jobRepository.RequeueRunningJobs()
+func (repository *JobRDBMSRepository) RequeueRunningJobs() (int64, error) {
+
+	result, err := rdbms.BuildQuery[int64](repository.dbAdapter, runningJobsRequeueSQL).
+		Build().
+		FindWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		return 0, infra.PropagateAsAppErrorWithNewMessage(err, "Error requeuing running jobs", repository)
+	}
+
+	return int64(len(result)), nil
+}
+
+func BuildJobRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *JobRDBMSRepository {
+	return &JobRDBMSRepository{dbAdapter: dbAdapter}
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	userPortfolioRoleSQL = `
+		SELECT CASE WHEN p.owner_user_id = {:userId} THEN 'OWNER' ELSE pg.role END AS role
+		FROM portfolio p
+		LEFT JOIN portfolio_grant pg ON pg.portfolio_id = p.id AND pg.user_id = {:userId}
+		WHERE p.id = {:portfolioId}
+	`
+	unownedPortfoliosAssignSQL = `
+		WITH assigned AS (
+			UPDATE portfolio SET owner_user_id = (SELECT min(id) FROM app_user)
+			WHERE owner_user_id IS NULL AND EXISTS (SELECT 1 FROM app_user)
+			RETURNING id
+		)
+		SELECT count(*) AS count FROM assigned
+	`
+	portfolioGrantsSQL = `
+		SELECT pg.portfolio_id, pg.user_id, app_user.username, pg.role, pg.created_at
+		FROM portfolio_grant pg
+		JOIN app_user ON app_user.id = pg.user_id
+		WHERE pg.portfolio_id = {:portfolioId}
+		ORDER BY app_user.username
+	`
+	portfolioGrantUpsertSQL = `
+		WITH upserted AS (
+			INSERT INTO portfolio_grant (portfolio_id, user_id, role)
+			VALUES ({:portfolioId}, {:userId}, {:role})
+			ON CONFLICT (portfolio_id, user_id) DO UPDATE SET role = EXCLUDED.role
+			RETURNING portfolio_id, user_id, role, created_at
+		)
+		SELECT upserted.portfolio_id, upserted.user_id, app_user.username, upserted.role, upserted.created_at
+		FROM upserted
+		JOIN app_user ON app_user.id = upserted.user_id
+	`
+	portfolioGrantDeleteSQL = `
+		WITH deleted AS (
+			DELETE FROM portfolio_grant WHERE portfolio_id = {:portfolioId} AND user_id = {:userId}
+			RETURNING portfolio_id
+		)
+		SELECT count(*) AS count FROM deleted
+	`
+	shareLinkColumnsSQL = `
+		id, portfolio_id, token_hash, created_by_user_id, created_at, expires_at, revoked_at
+	`
+	shareLinkInsertSQL = `
+		INSERT INTO portfolio_share_link (portfolio_id, token_hash, created_by_user_id, expires_at)
+		VALUES ({:portfolioId}, {:tokenHash}, {:createdByUserId}, {:expiresAt})
+		RETURNING ` + shareLinkColumnsSQL
+	portfolioShareLinksSQL = `
+		SELECT ` + shareLinkColumnsSQL + `
+		FROM portfolio_share_link
+		WHERE portfolio_id = {:portfolioId}
+		ORDER BY id
+	`
+	activeShareLinkSQL = `
+		SELECT ` + shareLinkColumnsSQL + `
+		FROM portfolio_share_link
+		WHERE token_hash = {:tokenHash} AND revoked_at IS NULL AND expires_at > {:now}
+	`
+	shareLinkRevokeSQL = `
+		UPDATE portfolio_share_link SET revoked_at = {:revokedAt}
+		WHERE portfolio_id = {:portfolioId} AND id = {:shareLinkId} AND revoked_at IS NULL
+		RETURNING id
+	`
+)
+
+func portfolioGrantRowScanner(rows *sql.Rows) (domain.PortfolioGrant, error) {
+	var grant domain.PortfolioGrant
+	scanErr := rows.Scan(&grant.PortfolioId, &grant.UserId, &grant.Username, &grant.Role, &grant.CreatedAt)
+	return grant, scanErr
+}
+
+func shareLinkRowScanner(rows *sql.Rows) (domain.PortfolioShareLink, error) {
+	var shareLink domain.PortfolioShareLink
+	scanErr := rows.Scan(
+		&shareLink.Id,
+		&shareLink.PortfolioId,
+		&shareLink.TokenHash,
+		&shareLink.CreatedByUserId,
+		&shareLink.CreatedAt,
+		&shareLink.ExpiresAt,
+		&shareLink.RevokedAt,
+	)
+	return shareLink, scanErr
+}
+
+type PortfolioAccessRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+// FindUserPortfolioRole retrieves the role of a user on a portfolio, OWNER for its owner or the granted
+// one otherwise. The role is empty when the user has no access or the portfolio does not exist.
+func (repository *PortfolioAccessRDBMSRepository) FindUserPortfolioRole(
+	portfolioId int64,
+	userId int64,
+) (domain.PortfolioRole, error) {
+
+	result, err := rdbms.BuildQuery[sql.NullString](repository.dbAdapter, userPortfolioRoleSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("userId", userId).
+		Build().
+		GetWithRowScanner(
+			func(rows *sql.Rows) (sql.NullString, error) {
+				var role sql.NullString
+				scanErr := rows.Scan(&role)
+				return role, scanErr
+			},
+		)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", infra.PropagateAsAppErrorWithNewMessage(err, "Error getting user portfolio role", repository)
+	}
+
+	return domain.PortfolioRole(result.String), nil
+}
+
+// AssignUnownedPortfolios makes the oldest user the owner of the portfolios without one, returning how
+// many were assigned. Nothing is assigned while there are no users.
+func (repository *PortfolioAccessRDBMSRepository) AssignUnownedPortfolios() (int64, error) {
+
+	var result deletedRowsCountDTS
+	err := rdbms.BuildQuery[deletedRowsCountDTS](repository.dbAdapter, unownedPortfoliosAssignSQL).
+		Build().
+		GetInto(&result)
+	if err != nil {
+		return 0, infra.PropagateAsAppErrorWithNewMessage(err, "Error assigning unowned portfolios", repository)
+	}
+
+	return result.Count, nil
+}
+
+// FindPortfolioGrants retrieves the access grants of a portfolio, ordered by username.
+func (repository *PortfolioAccessRDBMSRepository) FindPortfolioGrants(
+	portfolioId int64,
+) ([]*domain.PortfolioGrant, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioGrant](repository.dbAdapter, portfolioGrantsSQL).
+		AddParam("portfolioId", portfolioId).
+		Build().
+		FindWithRowScanner(portfolioGrantRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio grants", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// UpsertPortfolioGrant persists the access grant of a user to a portfolio, replacing the role of an
+// existing one, and returns it as persisted.
+func (repository *PortfolioAccessRDBMSRepository) UpsertPortfolioGrant(
+	grant *domain.PortfolioGrant,
+) (*domain.PortfolioGrant, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioGrant](repository.dbAdapter, portfolioGrantUpsertSQL).
+		AddParam("portfolioId", grant.PortfolioId).
+		AddParam("userId", grant.UserId).
+		AddParam("role", string(grant.Role)).
+		Build().
+		GetWithRowScanner(portfolioGrantRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error persisting portfolio grant", repository)
+	}
+
+	return &result, nil
+}
+
+// DeletePortfolioGrant deletes the access grant of a user to a portfolio, returning false when it does
+// not exist.
+func (repository *PortfolioAccessRDBMSRepository) DeletePortfolioGrant(portfolioId int64, userId int64) (bool, error) {
+
+	var result deletedRowsCountDTS
+	err := rdbms.BuildQuery[deletedRowsCountDTS](repository.dbAdapter, portfolioGrantDeleteSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("userId", userId).
+		Build().
+		GetInto(&result)
+	if err != nil {
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting portfolio grant", repository)
+	}
+
+	return result.Count > 0, nil
+}
+
+// InsertShareLink persists a new portfolio share link and returns it as persisted.
+func (repository *PortfolioAccessRDBMSRepository) InsertShareLink(
+	shareLink *domain.PortfolioShareLink,
+) (*domain.PortfolioShareLink, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioShareLink](repository.dbAdapter, shareLinkInsertSQL).
+		AddParam("portfolioId", shareLink.PortfolioId).
+		AddParam("tokenHash", shareLink.TokenHash).
+		AddParam("createdByUserId", shareLink.CreatedByUserId).
+		AddParam("expiresAt", shareLink.ExpiresAt).
+		Build().
+		GetWithRowScanner(shareLinkRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting portfolio share link", repository)
+	}
+
+	return &result, nil
+}
+
+// FindPortfolioShareLinks retrieves the share links of a portfolio, revoked and expired ones included, in
+// creation order.
+func (repository *PortfolioAccessRDBMSRepository) FindPortfolioShareLinks(
+	portfolioId int64,
+) ([]*domain.PortfolioShareLink, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioShareLink](repository.dbAdapter, portfolioShareLinksSQL).
+		AddParam("portfolioId", portfolioId).
+		Build().
+		FindWithRowScanner(shareLinkRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio share links", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// FindActiveShareLink retrieves the share link with a token hash, or nil when it does not exist, is revoked
+// or is expired at the given time.
+func (repository *PortfolioAccessRDBMSRepository) FindActiveShareLink(
+	tokenHash string,
+	now time.Time,
+) (*domain.PortfolioShareLink, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioShareLink](repository.dbAdapter, activeShareLinkSQL).
+		AddParam("tokenHash", tokenHash).
+		AddParam("now", now).
+		Build().
+		GetWithRowScanner(shareLinkRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio share link", repository)
+	}
+
+	return &result, nil
+}
+
+// RevokeShareLink revokes an active share link of a portfolio, returning false when it does not exist or
+// is already revoked.
+func (repository *PortfolioAccessRDBMSRepository) RevokeShareLink(
+	portfolioId int64,
+	shareLinkId int64,
+	revokedAt time.Time,
+) (bool, error) {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, shareLinkRevokeSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("shareLinkId", shareLinkId).
+		AddParam("revokedAt", revokedAt).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error revoking portfolio share link", repository)
+	}
+
+	return true, nil
+}
+
+func BuildPortfolioAccessRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *PortfolioAccessRDBMSRepository {
+	return &PortfolioAccessRDBMSRepository{dbAdapter: dbAdapter}
+}
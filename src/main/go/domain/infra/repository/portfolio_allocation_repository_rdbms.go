@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/infra"
@@ -34,6 +36,11 @@ const (
 		    ass.id AS "asset.id", 
 		    ass.ticker AS "asset.ticker", 
 		    coalesce(ass.name, '') AS "asset.name", 
+		    coalesce(ass.instrument_type, '') AS "asset.instrument_type",
+		    coalesce(ass.currency, '') AS "asset.currency",
+		    coalesce(ass.isin, '') AS "asset.isin",
+		    coalesce(ass.cusip, '') AS "asset.cusip",
+		    coalesce(ass.exchange, '') AS "asset.exchange",
 		    coalesce(ass.external_data #>> '{data,0,source}', '') AS selected_external_asset_source,
 		    coalesce(ass.external_data #>> '{data,0,ticker}', '') AS selected_external_asset_ticker,
 		    coalesce(ass.external_data #>> '{data,0,exchangeId}', '') AS selected_external_asset_exchange_id,
@@ -102,6 +109,23 @@ const (
 		VALUES ($1, $2)
 		RETURNING id
     `
+	portfolioSnapshotVersionSQL = `
+		SELECT version
+		FROM portfolio_snapshot_version
+		WHERE portfolio_id = {:portfolioId} AND observation_time_id = {:observationTimestampId}
+	`
+	portfolioSnapshotVersionUpsertSQL = `
+		INSERT INTO portfolio_snapshot_version (portfolio_id, observation_time_id)
+		VALUES ($1, $2)
+		ON CONFLICT (portfolio_id, observation_time_id)
+			DO UPDATE SET version = portfolio_snapshot_version.version + 1
+		RETURNING version
+	`
+	portfolioSnapshotVersionUpdateSQL = `
+		UPDATE portfolio_snapshot_version SET version = version + 1
+		WHERE portfolio_id = $1 AND observation_time_id = $2 AND version = $3
+		RETURNING version
+	`
 )
 
 const (
@@ -153,16 +177,18 @@ func (repository *PortfolioAllocationRDBMSRepository) FindAllPortfolioAllocation
 //
 // Example:
 //
-//	allocations, err := repository.FindPortfolioAllocationsByObservationTimestamp(1, 3)
+//	allocations, err := repository.FindPortfolioAllocationsByObservationTimestamp(requestContext, 1, 3)
 //
 // Co-authored by: OpenCode and Igor Benicio de Mesquita
 func (repository *PortfolioAllocationRDBMSRepository) FindPortfolioAllocationsByObservationTimestamp(
+	queryContext context.Context,
 	id int64,
 	observationTimestampId int64,
 ) ([]*domain.PortfolioAllocation, error) {
 
 	var queryResult []portfolioAllocationJoinedRowDTS
 	err := rdbms.BuildQuery[portfolioAllocationJoinedRowDTS](repository.dbAdapter, portfolioAllocationsSQL).
+		WithContext(queryContext).
 		AddWhereClauseAndParam(portfolioIdWhereClause, "portfolioId", id).
 		AddWhereClauseAndParam(
 			"AND pa.observation_time_id = {:observationTimestampId}",
@@ -346,6 +372,85 @@ func (repository *PortfolioAllocationRDBMSRepository) InsertObservationTimestamp
 	}, nil
 }
 
+// FindPortfolioSnapshotVersion retrieves the version of the allocations of a portfolio in an observation,
+// or 0 when the portfolio has no allocations merged in the observation.
+//
+// Example:
+//
+//	version, err := repository.FindPortfolioSnapshotVersion(1, 2)
+func (repository *PortfolioAllocationRDBMSRepository) FindPortfolioSnapshotVersion(
+	portfolioId int64,
+	observationTimestampId int64,
+) (int64, error) {
+
+	version, err := rdbms.BuildQuery[int64](repository.dbAdapter, portfolioSnapshotVersionSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("observationTimestampId", observationTimestampId).
+		Build().
+		GetWithRowScanner(snapshotVersionRowScanner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	return version, infra.PropagateAsAppErrorWithNewMessage(err, "Error querying portfolio snapshot version", repository)
+}
+
+// IncrementPortfolioSnapshotVersionInTransaction increments the version of the allocations of a portfolio in
+// an observation within an existing SQL transaction, locking it until the transaction ends, and returns the
+// new version. With a nonzero expected version, the version is only incremented if it is still the expected
+// one, otherwise an infra.VersionConflictError is returned.
+//
+// Example:
+//
+//	version, err := repository.IncrementPortfolioSnapshotVersionInTransaction(transContext, 1, 2, 3)
+func (repository *PortfolioAllocationRDBMSRepository) IncrementPortfolioSnapshotVersionInTransaction(
+	transContext context.Context,
+	portfolioId int64,
+	observationTimestampId int64,
+	expectedVersion int64,
+) (int64, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return 0, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	var queryBuilder *rdbms.SQLTransactionalQueryBuilder[int64]
+	if langext.IsZeroValue(expectedVersion) {
+		queryBuilder = rdbms.BuildQueryInTransaction[int64](transactionalContext, portfolioSnapshotVersionUpsertSQL).
+			AddParams(portfolioId, observationTimestampId)
+	} else {
+		queryBuilder = rdbms.BuildQueryInTransaction[int64](transactionalContext, portfolioSnapshotVersionUpdateSQL).
+			AddParams(portfolioId, observationTimestampId, expectedVersion)
+	}
+
+	version, err := queryBuilder.Build().Get(snapshotVersionSingleRowScanner)
+	if errors.Is(err, sql.ErrNoRows) && !langext.IsZeroValue(expectedVersion) {
+		return 0, infra.BuildVersionConflictError("Portfolio allocations were changed since the requested version")
+	}
+
+	return version, infra.PropagateAsAppErrorWithNewMessage(
+		err,
+		"Error incrementing portfolio snapshot version",
+		repository,
+	)
+}
+
+func snapshotVersionRowScanner(rows *sql.Rows) (int64, error) {
+	var version int64
+	scanErr := rows.Scan(&version)
+	return version, scanErr
+}
+
+func snapshotVersionSingleRowScanner(row *sql.Row) (int64, error) {
+	var version int64
+	scanErr := row.Scan(&version)
+	return version, scanErr
+}
+
 func BuildPortfolioAllocationRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *PortfolioAllocationRDBMSRepository {
 	return &PortfolioAllocationRDBMSRepository{dbAdapter: dbAdapter}
 }
@@ -1,6 +1,10 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+
 	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/infra"
 	"github.com/benizzio/open-asset-allocator/infra/rdbms"
@@ -9,16 +13,42 @@ import (
 
 const (
 	portfolioSQL = `
-		SELECT p.id, p.name, p.allocation_structure
+		SELECT p.id, p.name, p.allocation_structure, p.owner_user_id, p.version
 		FROM portfolio p
 	`
 )
 
+const (
+	portfolioReturningSQL = `
+		RETURNING id, name, allocation_structure, owner_user_id, version
+	`
+	portfolioInsertSQL = `
+		INSERT INTO portfolio (name, allocation_structure, owner_user_id)
+		VALUES ($1, $2, $3)
+	` + portfolioReturningSQL
+	portfolioUpdateSQL = `
+		UPDATE portfolio SET name = $1, version = version + 1
+		WHERE id = $2 AND ($3 = 0 OR version = $3)
+	` + portfolioReturningSQL
+)
+
 const (
 	queryPortfoliosError = "Error querying portfolios"
 	queryPortfolioError  = "Error querying single portfolio"
 )
 
+func portfolioSingleRowScanner(row *sql.Row) (domain.Portfolio, error) {
+	var portfolio domain.Portfolio
+	scanErr := row.Scan(
+		&portfolio.Id,
+		&portfolio.Name,
+		&portfolio.AllocationStructure,
+		&portfolio.OwnerUserId,
+		&portfolio.Version,
+	)
+	return portfolio, scanErr
+}
+
 type PortfolioRDBMSRepository struct {
 	dbAdapter rdbms.RepositoryRDBMSAdapter
 }
@@ -35,7 +65,32 @@ func (repository *PortfolioRDBMSRepository) GetAllPortfolios() ([]*domain.Portfo
 	)
 }
 
-func (repository *PortfolioRDBMSRepository) FindPortfolio(id int64) (*domain.Portfolio, error) {
+// GetUserPortfolios retrieves the portfolios a user owns or was granted access to.
+func (repository *PortfolioRDBMSRepository) GetUserPortfolios(userId int64) ([]*domain.Portfolio, error) {
+
+	var query = portfolioSQL + `
+		WHERE p.owner_user_id = {:userId}
+			OR EXISTS (
+				SELECT 1 FROM portfolio_grant pg
+				WHERE pg.portfolio_id = p.id AND pg.user_id = {:userId}
+			)
+	`
+
+	var result []domain.Portfolio
+	err := rdbms.BuildQuery[domain.Portfolio](repository.dbAdapter, query).
+		AddParam("userId", userId).Build().FindInto(&result)
+
+	return langext.ToPointerSlice(result), infra.PropagateAsAppErrorWithNewMessage(
+		err,
+		queryPortfoliosError,
+		repository,
+	)
+}
+
+func (repository *PortfolioRDBMSRepository) FindPortfolio(
+	queryContext context.Context,
+	id int64,
+) (*domain.Portfolio, error) {
 
 	var query = portfolioSQL + `
 		WHERE p.id = {:id}
@@ -43,36 +98,74 @@ func (repository *PortfolioRDBMSRepository) FindPortfolio(id int64) (*domain.Por
 
 	var result domain.Portfolio
 	err := rdbms.BuildQuery[domain.Portfolio](repository.dbAdapter, query).
+		WithContext(queryContext).
 		AddParam("id", id).Build().GetInto(&result)
 
 	return &result, infra.PropagateAsAppErrorWithNewMessage(err, queryPortfolioError, repository)
 }
 
-func (repository *PortfolioRDBMSRepository) InsertPortfolio(portfolio *domain.Portfolio) (*domain.Portfolio, error) {
-	var insertingCopyPortfolio = *portfolio
-	err := repository.dbAdapter.Insert(&insertingCopyPortfolio)
-	return &insertingCopyPortfolio, infra.PropagateAsAppErrorWithNewMessage(
+// InsertPortfolioInTransaction persists a new portfolio within an existing SQL transaction and returns it
+// as persisted.
+//
+// Example:
+//
+//	persistedPortfolio, err := portfolioRepository.InsertPortfolioInTransaction(transContext, portfolio)
+func (repository *PortfolioRDBMSRepository) InsertPortfolioInTransaction(
+	transContext context.Context,
+	portfolio *domain.Portfolio,
+) (*domain.Portfolio, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	insertedPortfolio, err := rdbms.BuildQueryInTransaction[domain.Portfolio](transactionalContext, portfolioInsertSQL).
+		AddParams(portfolio.Name, portfolio.AllocationStructure, portfolio.OwnerUserId).
+		Build().
+		Get(portfolioSingleRowScanner)
+	return &insertedPortfolio, infra.PropagateAsAppErrorWithNewMessage(
 		err,
 		"Error inserting portfolio",
 		repository,
 	)
 }
 
-func (repository *PortfolioRDBMSRepository) UpdatePortfolio(portfolio *domain.Portfolio) (*domain.Portfolio, error) {
+// UpdatePortfolioInTransaction updates the name of an existing portfolio within an existing SQL transaction
+// and returns it as updated, with its version incremented. With a nonzero version, the portfolio is only
+// updated if it still has that version, otherwise an infra.VersionConflictError is returned.
+//
+// Example:
+//
+//	updatedPortfolio, err := portfolioRepository.UpdatePortfolioInTransaction(transContext, portfolio)
+func (repository *PortfolioRDBMSRepository) UpdatePortfolioInTransaction(
+	transContext context.Context,
+	portfolio *domain.Portfolio,
+) (*domain.Portfolio, error) {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return nil, infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
 
-	var updatingCopyPortfolio = *portfolio
-	err := repository.dbAdapter.UpdateListedFields(&updatingCopyPortfolio, "Name")
+	updatedPortfolio, err := rdbms.BuildQueryInTransaction[domain.Portfolio](transactionalContext, portfolioUpdateSQL).
+		AddParams(portfolio.Name, portfolio.Id, portfolio.Version).
+		Build().
+		Get(portfolioSingleRowScanner)
+	if errors.Is(err, sql.ErrNoRows) && !langext.IsZeroValue(portfolio.Version) {
+		return nil, infra.BuildVersionConflictError("Portfolio was changed since the requested version")
+	}
 	if err != nil {
 		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating portfolio", repository)
 	}
 
-	var updatedPortfolio domain.Portfolio
-	err = repository.dbAdapter.Read(&updatedPortfolio, portfolio.Id)
-	return &updatedPortfolio, infra.PropagateAsAppErrorWithNewMessage(
-		err,
-		"Error retrieving updated portfolio",
-		repository,
-	)
+	return &updatedPortfolio, nil
 }
 
 func BuildPortfolioRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *PortfolioRDBMSRepository {
@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	portfolioScheduleColumnsSQL = `
+		id, portfolio_id, task_type, cron_expression, timezone, params, enabled, next_run_at, created_at
+	`
+	portfolioSchedulesSQL = `
+		SELECT ` + portfolioScheduleColumnsSQL + `
+		FROM portfolio_schedule
+		WHERE portfolio_id = {:portfolioId}
+		ORDER BY id
+	`
+	portfolioScheduleSQL = `
+		SELECT ` + portfolioScheduleColumnsSQL + `
+		FROM portfolio_schedule
+		WHERE portfolio_id = {:portfolioId} AND id = {:scheduleId}
+	`
+	dueSchedulesSQL = `
+		SELECT ` + portfolioScheduleColumnsSQL + `
+		FROM portfolio_schedule
+		WHERE enabled AND next_run_at <= {:now}
+		ORDER BY next_run_at, id
+	`
+	portfolioScheduleInsertSQL = `
+		INSERT INTO portfolio_schedule (portfolio_id, task_type, cron_expression, timezone, params, enabled, next_run_at)
+		VALUES ({:portfolioId}, {:taskType}, {:cronExpression}, {:timezone}, {:params}, {:enabled}, {:nextRunAt})
+		RETURNING ` + portfolioScheduleColumnsSQL
+	portfolioScheduleUpdateSQL = `
+		UPDATE portfolio_schedule
+		SET task_type = {:taskType}, cron_expression = {:cronExpression}, timezone = {:timezone},
+			params = {:params}, enabled = {:enabled}, next_run_at = {:nextRunAt}
+		WHERE portfolio_id = {:portfolioId} AND id = {:scheduleId}
+		RETURNING ` + portfolioScheduleColumnsSQL
+	portfolioScheduleDeleteSQL = `
+		DELETE FROM portfolio_schedule
+		WHERE portfolio_id = {:portfolioId} AND id = {:scheduleId}
+		RETURNING id
+	`
+	// scheduleNextRunAdvanceSQL moves the next activation of a schedule forward, selecting nothing when it
+	// was advanced or changed meanwhile, so each activation is handled once
+	scheduleNextRunAdvanceSQL = `
+		UPDATE portfolio_schedule
+		SET next_run_at = {:nextRunAt}
+		WHERE id = {:scheduleId} AND enabled AND next_run_at = {:currentNextRunAt}
+		RETURNING id
+	`
+	portfolioScheduleRunColumnsSQL = `
+		id, schedule_id, scheduled_for, status, started_at, finished_at, result, coalesce(error_message, '')
+	`
+	portfolioScheduleRunInsertSQL = `
+		INSERT INTO portfolio_schedule_run
+			(schedule_id, scheduled_for, status, started_at, finished_at, result, error_message)
+		VALUES
+			({:scheduleId}, {:scheduledFor}, {:status}, {:startedAt}, {:finishedAt}, {:result}, {:errorMessage})
+		RETURNING ` + portfolioScheduleRunColumnsSQL
+	portfolioScheduleRunsSQL = `
+		SELECT ` + portfolioScheduleRunColumnsSQL + `
+		FROM portfolio_schedule_run
+		WHERE schedule_id = {:scheduleId}
+		ORDER BY scheduled_for DESC, id DESC
+		LIMIT {:limit}
+	`
+)
+
+func portfolioScheduleRowScanner(rows *sql.Rows) (domain.PortfolioSchedule, error) {
+
+	var schedule domain.PortfolioSchedule
+	var params []byte
+
+	scanErr := rows.Scan(
+		&schedule.Id,
+		&schedule.PortfolioId,
+		&schedule.TaskType,
+		&schedule.CronExpression,
+		&schedule.Timezone,
+		&params,
+		&schedule.Enabled,
+		&schedule.NextRunAt,
+		&schedule.CreatedAt,
+	)
+
+	schedule.Params = params
+
+	return schedule, scanErr
+}
+
+func portfolioScheduleRunRowScanner(rows *sql.Rows) (domain.PortfolioScheduleRun, error) {
+
+	var run domain.PortfolioScheduleRun
+	var result []byte
+
+	scanErr := rows.Scan(
+		&run.Id,
+		&run.ScheduleId,
+		&run.ScheduledFor,
+		&run.Status,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&result,
+		&run.ErrorMessage,
+	)
+
+	run.Result = result
+
+	return run, scanErr
+}
+
+type PortfolioScheduleRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+// FindPortfolioSchedules retrieves the schedules of a portfolio, in creation order.
+//
+// Example:
+//
+//	schedules, err := portfolioScheduleRepository.FindPortfolioSchedules(1)
+func (repository *PortfolioScheduleRDBMSRepository) FindPortfolioSchedules(
+	portfolioId int64,
+) ([]*domain.PortfolioSchedule, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioSchedule](repository.dbAdapter, portfolioSchedulesSQL).
+		AddParam("portfolioId", portfolioId).
+		Build().
+		FindWithRowScanner(portfolioScheduleRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio schedules", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// FindPortfolioSchedule retrieves a schedule of a portfolio, or nil when it does not exist.
+//
+// Example:
+//
+//	schedule, err := portfolioScheduleRepository.FindPortfolioSchedule(1, 2)
+func (repository *PortfolioScheduleRDBMSRepository) FindPortfolioSchedule(
+	portfolioId int64,
+	scheduleId int64,
+) (*domain.PortfolioSchedule, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioSchedule](repository.dbAdapter, portfolioScheduleSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("scheduleId", scheduleId).
+		Build().
+		GetWithRowScanner(portfolioScheduleRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio schedule", repository)
+	}
+
+	return &result, nil
+}
+
+// FindDueSchedules retrieves the enabled schedules whose next activation is due at the given time, the
+// most overdue first.
+//
+// Example:
+//
+//	schedules, err := portfolioScheduleRepository.FindDueSchedules(time.Now())
+func (repository *PortfolioScheduleRDBMSRepository) FindDueSchedules(now time.Time) ([]*domain.PortfolioSchedule, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioSchedule](repository.dbAdapter, dueSchedulesSQL).
+		AddParam("now", now).
+		Build().
+		FindWithRowScanner(portfolioScheduleRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting due schedules", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// InsertSchedule persists a new schedule and returns it as persisted.
+//
+// Example:
+//
+//	persistedSchedule, err := portfolioScheduleRepository.InsertSchedule(schedule)
+func (repository *PortfolioScheduleRDBMSRepository) InsertSchedule(
+	schedule *domain.PortfolioSchedule,
+) (*domain.PortfolioSchedule, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioSchedule](repository.dbAdapter, portfolioScheduleInsertSQL).
+		AddParam("portfolioId", schedule.PortfolioId).
+		AddParam("taskType", schedule.TaskType).
+		AddParam("cronExpression", schedule.CronExpression).
+		AddParam("timezone", schedule.Timezone).
+		AddParam("params", string(schedule.Params)).
+		AddParam("enabled", schedule.Enabled).
+		AddParam("nextRunAt", schedule.NextRunAt).
+		Build().
+		GetWithRowScanner(portfolioScheduleRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting portfolio schedule", repository)
+	}
+
+	return &result, nil
+}
+
+// UpdateSchedule replaces the definition of a schedule of a portfolio and returns it as updated, or nil
+// when it does not exist.
+//
+// Example:
+//
+//	updatedSchedule, err := portfolioScheduleRepository.UpdateSchedule(schedule)
+func (repository *PortfolioScheduleRDBMSRepository) UpdateSchedule(
+	schedule *domain.PortfolioSchedule,
+) (*domain.PortfolioSchedule, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioSchedule](repository.dbAdapter, portfolioScheduleUpdateSQL).
+		AddParam("portfolioId", schedule.PortfolioId).
+		AddParam("scheduleId", schedule.Id).
+		AddParam("taskType", schedule.TaskType).
+		AddParam("cronExpression", schedule.CronExpression).
+		AddParam("timezone", schedule.Timezone).
+		AddParam("params", string(schedule.Params)).
+		AddParam("enabled", schedule.Enabled).
+		AddParam("nextRunAt", schedule.NextRunAt).
+		Build().
+		GetWithRowScanner(portfolioScheduleRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating portfolio schedule", repository)
+	}
+
+	return &result, nil
+}
+
+// DeleteSchedule removes a schedule of a portfolio with its run history, returning false when it does not
+// exist.
+//
+// Example:
+//
+//	deleted, err := portfolioScheduleRepository.DeleteSchedule(1, 2)
+func (repository *PortfolioScheduleRDBMSRepository) DeleteSchedule(portfolioId int64, scheduleId int64) (bool, error) {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, portfolioScheduleDeleteSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("scheduleId", scheduleId).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting portfolio schedule", repository)
+	}
+
+	return true, nil
+}
+
+// AdvanceScheduleNextRun moves the next activation of an enabled schedule from currentNextRunAt to
+// nextRunAt, returning false when the schedule was advanced, changed or disabled meanwhile.
+//
+// Example:
+//
+//	advanced, err := portfolioScheduleRepository.AdvanceScheduleNextRun(1, *schedule.NextRunAt, &nextRunAt)
+func (repository *PortfolioScheduleRDBMSRepository) AdvanceScheduleNextRun(
+	scheduleId int64,
+	currentNextRunAt time.Time,
+	nextRunAt *time.Time,
+) (bool, error) {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, scheduleNextRunAdvanceSQL).
+		AddParam("scheduleId", scheduleId).
+		AddParam("currentNextRunAt", currentNextRunAt).
+		AddParam("nextRunAt", nextRunAt).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error advancing schedule next run", repository)
+	}
+
+	return true, nil
+}
+
+// InsertScheduleRun records an activation of a schedule and returns it as persisted.
+//
+// Example:
+//
+//	persistedRun, err := portfolioScheduleRepository.InsertScheduleRun(run)
+func (repository *PortfolioScheduleRDBMSRepository) InsertScheduleRun(
+	run *domain.PortfolioScheduleRun,
+) (*domain.PortfolioScheduleRun, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioScheduleRun](repository.dbAdapter, portfolioScheduleRunInsertSQL).
+		AddParam("scheduleId", run.ScheduleId).
+		AddParam("scheduledFor", run.ScheduledFor).
+		AddParam("status", run.Status).
+		AddParam("startedAt", run.StartedAt).
+		AddParam("finishedAt", run.FinishedAt).
+		AddParam("result", nullableJSON(run.Result)).
+		AddParam("errorMessage", nullableIfZero(run.ErrorMessage)).
+		Build().
+		GetWithRowScanner(portfolioScheduleRunRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting schedule run", repository)
+	}
+
+	return &result, nil
+}
+
+// FindScheduleRuns retrieves the latest activations of a schedule, the most recent first.
+//
+// Example:
+//
+//	runs, err := portfolioScheduleRepository.FindScheduleRuns(1, 50)
+func (repository *PortfolioScheduleRDBMSRepository) FindScheduleRuns(
+	scheduleId int64,
+	limit int,
+) ([]*domain.PortfolioScheduleRun, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioScheduleRun](repository.dbAdapter, portfolioScheduleRunsSQL).
+		AddParam("scheduleId", scheduleId).
+		AddParam("limit", limit).
+		Build().
+		FindWithRowScanner(portfolioScheduleRunRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting schedule runs", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+func BuildPortfolioScheduleRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *PortfolioScheduleRDBMSRepository {
+	return &PortfolioScheduleRDBMSRepository{dbAdapter: dbAdapter}
+}
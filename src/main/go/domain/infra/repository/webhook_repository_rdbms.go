@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/rdbms"
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const (
+	portfolioWebhookColumnsSQL = `
+		id, portfolio_id, url, secret, event_types, enabled, created_at
+	`
+	portfolioWebhooksSQL = `
+		SELECT ` + portfolioWebhookColumnsSQL + `
+		FROM portfolio_webhook
+		WHERE portfolio_id = {:portfolioId}
+		ORDER BY id
+	`
+	portfolioWebhookSQL = `
+		SELECT ` + portfolioWebhookColumnsSQL + `
+		FROM portfolio_webhook
+		WHERE portfolio_id = {:portfolioId} AND id = {:webhookId}
+	`
+	portfolioWebhookInsertSQL = `
+		INSERT INTO portfolio_webhook (portfolio_id, url, secret, event_types, enabled)
+		VALUES ({:portfolioId}, {:url}, {:secret}, {:eventTypes}, {:enabled})
+		RETURNING ` + portfolioWebhookColumnsSQL
+	portfolioWebhookUpdateSQL = `
+		UPDATE portfolio_webhook
+		SET url = {:url}, secret = {:secret}, event_types = {:eventTypes}, enabled = {:enabled}
+		WHERE portfolio_id = {:portfolioId} AND id = {:webhookId}
+		RETURNING ` + portfolioWebhookColumnsSQL
+	portfolioWebhookDeleteSQL = `
+		DELETE FROM portfolio_webhook
+		WHERE portfolio_id = {:portfolioId} AND id = {:webhookId}
+		RETURNING id
+	`
+	outboxEventInsertSQL = `
+		INSERT INTO outbox_event (portfolio_id, event_type, payload)
+		VALUES ({:portfolioId}, {:eventType}, {:payload})
+		RETURNING id
+	`
+	outboxEventInsertInTransactionSQL = `
+		INSERT INTO outbox_event (portfolio_id, event_type, payload)
+		VALUES ($1, $2, $3)
+	`
+	// outboxEventsFanOutSQL marks the oldest undispatched events as dispatched and creates in the same
+	// statement one delivery per enabled webhook of the portfolio subscribed to the event, skipping the
+	// events locked by a concurrent fan out
+	outboxEventsFanOutSQL = `
+		WITH dispatched_event AS (
+			UPDATE outbox_event
+			SET dispatched_at = now()
+			WHERE id IN (
+				SELECT id
+				FROM outbox_event
+				WHERE dispatched_at IS NULL
+				ORDER BY id
+				LIMIT {:limit}
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, portfolio_id, event_type
+		), inserted_delivery AS (
+			INSERT INTO webhook_delivery (webhook_id, event_id, next_attempt_at)
+			SELECT webhook.id, dispatched_event.id, now()
+			FROM dispatched_event
+			JOIN portfolio_webhook webhook
+				ON webhook.portfolio_id = dispatched_event.portfolio_id
+				AND webhook.enabled
+				AND dispatched_event.event_type = ANY(webhook.event_types)
+			RETURNING id
+		)
+		SELECT count(*) AS count FROM dispatched_event
+	`
+	webhookDeliveryColumnsSQL = `
+		delivery.id, delivery.webhook_id, delivery.event_id, event.event_type, delivery.status,
+		delivery.attempt_count, delivery.next_attempt_at, delivery.last_status_code,
+		coalesce(delivery.last_error, ''), delivery.created_at, delivery.delivered_at
+	`
+	dueWebhookDeliveriesSQL = `
+		SELECT ` + webhookDeliveryColumnsSQL + `,
+			webhook.url, webhook.secret, event.portfolio_id, event.payload, event.created_at
+		FROM webhook_delivery delivery
+		JOIN portfolio_webhook webhook ON webhook.id = delivery.webhook_id
+		JOIN outbox_event event ON event.id = delivery.event_id
+		WHERE delivery.status = 'PENDING' AND delivery.next_attempt_at <= {:now} AND webhook.enabled
+		ORDER BY delivery.next_attempt_at, delivery.id
+		LIMIT {:limit}
+	`
+	webhookDeliveryAttemptUpdateSQL = `
+		UPDATE webhook_delivery
+		SET status = {:status}, attempt_count = {:attemptCount}, next_attempt_at = {:nextAttemptAt},
+			last_status_code = {:lastStatusCode}, last_error = {:lastError}, delivered_at = {:deliveredAt}
+		WHERE id = {:id}
+		RETURNING id
+	`
+	webhookDeliveriesSQL = `
+		SELECT ` + webhookDeliveryColumnsSQL + `
+		FROM webhook_delivery delivery
+		JOIN outbox_event event ON event.id = delivery.event_id
+		WHERE delivery.webhook_id = {:webhookId}
+		ORDER BY delivery.created_at DESC, delivery.id DESC
+		LIMIT {:limit}
+	`
+)
+
+type dispatchedEventsCountDTS struct {
+	Count int64
+}
+
+func portfolioWebhookRowScanner(rows *sql.Rows) (domain.PortfolioWebhook, error) {
+
+	var webhook domain.PortfolioWebhook
+	var eventTypes []string
+
+	scanErr := rows.Scan(
+		&webhook.Id,
+		&webhook.PortfolioId,
+		&webhook.URL,
+		&webhook.Secret,
+		pq.Array(&eventTypes),
+		&webhook.Enabled,
+		&webhook.CreatedAt,
+	)
+
+	webhook.EventTypes = make([]domain.WebhookEventType, len(eventTypes))
+	for i, eventType := range eventTypes {
+		webhook.EventTypes[i] = domain.WebhookEventType(eventType)
+	}
+
+	return webhook, scanErr
+}
+
+func webhookDeliveryScanTargets(delivery *domain.WebhookDelivery) []any {
+	return []any{
+		&delivery.Id,
+		&delivery.WebhookId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&delivery.Status,
+		&delivery.AttemptCount,
+		&delivery.NextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	}
+}
+
+func webhookDeliveryRowScanner(rows *sql.Rows) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	scanErr := rows.Scan(webhookDeliveryScanTargets(&delivery)...)
+	return delivery, scanErr
+}
+
+func dueWebhookDeliveryRowScanner(rows *sql.Rows) (domain.DueWebhookDelivery, error) {
+
+	var dueDelivery = domain.DueWebhookDelivery{
+		Delivery: &domain.WebhookDelivery{},
+		Event:    &domain.OutboxEvent{},
+	}
+	var payload []byte
+
+	var scanTargets = append(
+		webhookDeliveryScanTargets(dueDelivery.Delivery),
+		&dueDelivery.URL,
+		&dueDelivery.Secret,
+		&dueDelivery.Event.PortfolioId,
+		&payload,
+		&dueDelivery.Event.CreatedAt,
+	)
+	scanErr := rows.Scan(scanTargets...)
+
+	dueDelivery.Event.Id = dueDelivery.Delivery.EventId
+	dueDelivery.Event.EventType = dueDelivery.Delivery.EventType
+	dueDelivery.Event.Payload = payload
+
+	return dueDelivery, scanErr
+}
+
+func eventTypesToStrings(eventTypes []domain.WebhookEventType) []string {
+	var values = make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		values[i] = string(eventType)
+	}
+	return values
+}
+
+type WebhookRDBMSRepository struct {
+	dbAdapter rdbms.RepositoryRDBMSAdapter
+}
+
+// FindPortfolioWebhooks retrieves the webhooks of a portfolio, in creation order.
+//
+// Example:
+//
+//	webhooks, err := webhookRepository.FindPortfolioWebhooks(1)
+func (repository *WebhookRDBMSRepository) FindPortfolioWebhooks(portfolioId int64) ([]*domain.PortfolioWebhook, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioWebhook](repository.dbAdapter, portfolioWebhooksSQL).
+		AddParam("portfolioId", portfolioId).
+		Build().
+		FindWithRowScanner(portfolioWebhookRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio webhooks", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// FindPortfolioWebhook retrieves a webhook of a portfolio, or nil when it does not exist.
+//
+// Example:
+//
+//	webhook, err := webhookRepository.FindPortfolioWebhook(1, 2)
+func (repository *WebhookRDBMSRepository) FindPortfolioWebhook(
+	portfolioId int64,
+	webhookId int64,
+) (*domain.PortfolioWebhook, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioWebhook](repository.dbAdapter, portfolioWebhookSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("webhookId", webhookId).
+		Build().
+		GetWithRowScanner(portfolioWebhookRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting portfolio webhook", repository)
+	}
+
+	return &result, nil
+}
+
+// InsertWebhook persists a new webhook and returns it as persisted.
+//
+// Example:
+//
+//	persistedWebhook, err := webhookRepository.InsertWebhook(webhook)
+func (repository *WebhookRDBMSRepository) InsertWebhook(
+	webhook *domain.PortfolioWebhook,
+) (*domain.PortfolioWebhook, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioWebhook](repository.dbAdapter, portfolioWebhookInsertSQL).
+		AddParam("portfolioId", webhook.PortfolioId).
+		AddParam("url", webhook.URL).
+		AddParam("secret", webhook.Secret).
+		AddParam("eventTypes", pq.Array(eventTypesToStrings(webhook.EventTypes))).
+		AddParam("enabled", webhook.Enabled).
+		Build().
+		GetWithRowScanner(portfolioWebhookRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting portfolio webhook", repository)
+	}
+
+	return &result, nil
+}
+
+// UpdateWebhook replaces the definition of a webhook of a portfolio and returns it as updated, or nil
+// when it does not exist.
+//
+// Example:
+//
+//	updatedWebhook, err := webhookRepository.UpdateWebhook(webhook)
+func (repository *WebhookRDBMSRepository) UpdateWebhook(
+	webhook *domain.PortfolioWebhook,
+) (*domain.PortfolioWebhook, error) {
+
+	result, err := rdbms.BuildQuery[domain.PortfolioWebhook](repository.dbAdapter, portfolioWebhookUpdateSQL).
+		AddParam("portfolioId", webhook.PortfolioId).
+		AddParam("webhookId", webhook.Id).
+		AddParam("url", webhook.URL).
+		AddParam("secret", webhook.Secret).
+		AddParam("eventTypes", pq.Array(eventTypesToStrings(webhook.EventTypes))).
+		AddParam("enabled", webhook.Enabled).
+		Build().
+		GetWithRowScanner(portfolioWebhookRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error updating portfolio webhook", repository)
+	}
+
+	return &result, nil
+}
+
+// DeleteWebhook removes a webhook of a portfolio with its deliveries, returning false when it does not
+// exist.
+//
+// Example:
+//
+//	deleted, err := webhookRepository.DeleteWebhook(1, 2)
+func (repository *WebhookRDBMSRepository) DeleteWebhook(portfolioId int64, webhookId int64) (bool, error) {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, portfolioWebhookDeleteSQL).
+		AddParam("portfolioId", portfolioId).
+		AddParam("webhookId", webhookId).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, infra.PropagateAsAppErrorWithNewMessage(err, "Error deleting portfolio webhook", repository)
+	}
+
+	return true, nil
+}
+
+// InsertOutboxEvent writes an event to the outbox on its own, for events not emitted by a transactional
+// change.
+//
+// Example:
+//
+//	err := webhookRepository.InsertOutboxEvent(event)
+func (repository *WebhookRDBMSRepository) InsertOutboxEvent(event *domain.OutboxEvent) error {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, outboxEventInsertSQL).
+		AddParam("portfolioId", event.PortfolioId).
+		AddParam("eventType", event.EventType).
+		AddParam("payload", string(event.Payload)).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting outbox event", repository)
+}
+
+// InsertOutboxEventInTransaction writes an event to the outbox within an existing SQL transaction, so the
+// event is dispatched only when the transaction commits.
+//
+// Example:
+//
+//	err := adapter.RunInTransaction(func(transContext *rdbms.SQLTransactionalContext) error {
+//		// ... transactional change
+//		return webhookRepository.InsertOutboxEventInTransaction(transContext, event)
+//	})
+func (repository *WebhookRDBMSRepository) InsertOutboxEventInTransaction(
+	transContext context.Context,
+	event *domain.OutboxEvent,
+) error {
+
+	var transactionalContext, ok = rdbms.ToSQLTransactionalContext(transContext)
+	if !ok {
+		return infra.BuildAppError(
+			"Context is not a SQL transactional context",
+			repository,
+		)
+	}
+
+	_, err := repository.dbAdapter.ExecuteInTransaction(
+		transactionalContext,
+		outboxEventInsertInTransactionSQL,
+		event.PortfolioId,
+		string(event.EventType),
+		string(event.Payload),
+	)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error inserting outbox event", repository)
+}
+
+// FanOutOutboxEvents dispatches up to limit of the oldest undispatched outbox events, creating one pending
+// delivery per enabled webhook subscribed to each of them, and returns how many events were dispatched.
+//
+// Example:
+//
+//	dispatchedCount, err := webhookRepository.FanOutOutboxEvents(100)
+func (repository *WebhookRDBMSRepository) FanOutOutboxEvents(limit int) (int, error) {
+
+	var result dispatchedEventsCountDTS
+	err := rdbms.BuildQuery[dispatchedEventsCountDTS](repository.dbAdapter, outboxEventsFanOutSQL).
+		AddParam("limit", limit).
+		Build().
+		GetInto(&result)
+	if err != nil {
+		return 0, infra.PropagateAsAppErrorWithNewMessage(err, "Error fanning out outbox events", repository)
+	}
+
+	return int(result.Count), nil
+}
+
+// FindDueDeliveries retrieves up to limit pending deliveries of enabled webhooks due to be attempted at the
+// given time, the most overdue first.
+//
+// Example:
+//
+//	dueDeliveries, err := webhookRepository.FindDueDeliveries(time.Now(), 50)
+func (repository *WebhookRDBMSRepository) FindDueDeliveries(
+	now time.Time,
+	limit int,
+) ([]*domain.DueWebhookDelivery, error) {
+
+	result, err := rdbms.BuildQuery[domain.DueWebhookDelivery](repository.dbAdapter, dueWebhookDeliveriesSQL).
+		AddParam("now", now).
+		AddParam("limit", limit).
+		Build().
+		FindWithRowScanner(dueWebhookDeliveryRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting due webhook deliveries", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+// UpdateDeliveryAttempt records the outcome of an attempt of a delivery, with its status and next attempt.
+//
+// Example:
+//
+//	err := webhookRepository.UpdateDeliveryAttempt(delivery)
+func (repository *WebhookRDBMSRepository) UpdateDeliveryAttempt(delivery *domain.WebhookDelivery) error {
+
+	_, err := rdbms.BuildQuery[int64](repository.dbAdapter, webhookDeliveryAttemptUpdateSQL).
+		AddParam("id", delivery.Id).
+		AddParam("status", delivery.Status).
+		AddParam("attemptCount", delivery.AttemptCount).
+		AddParam("nextAttemptAt", delivery.NextAttemptAt).
+		AddParam("lastStatusCode", delivery.LastStatusCode).
+		AddParam("lastError", nullableIfZero(delivery.LastError)).
+		AddParam("deliveredAt", delivery.DeliveredAt).
+		Build().
+		GetWithRowScanner(rdbms.ReturningIntIdRowScanner)
+	return infra.PropagateAsAppErrorWithNewMessage(err, "Error updating webhook delivery", repository)
+}
+
+// FindWebhookDeliveries retrieves the latest deliveries of a webhook, the most recent first.
+//
+// Example:
+//
+//	deliveries, err := webhookRepository.FindWebhookDeliveries(1, 50)
+func (repository *WebhookRDBMSRepository) FindWebhookDeliveries(
+	webhookId int64,
+	limit int,
+) ([]*domain.WebhookDelivery, error) {
+
+	result, err := rdbms.BuildQuery[domain.WebhookDelivery](repository.dbAdapter, webhookDeliveriesSQL).
+		AddParam("webhookId", webhookId).
+		AddParam("limit", limit).
+		Build().
+		FindWithRowScanner(webhookDeliveryRowScanner)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error getting webhook deliveries", repository)
+	}
+
+	return langext.ToPointerSlice(result), nil
+}
+
+func BuildWebhookRDBMSRepository(dbAdapter rdbms.RepositoryRDBMSAdapter) *WebhookRDBMSRepository {
+	return &WebhookRDBMSRepository{dbAdapter: dbAdapter}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrJobCancelled is the cause of the context of a job cancelled on request.
+var ErrJobCancelled = errors.New("job cancelled")
+
+type JobType string
+
+const (
+	AssetEventImportJobType JobType = "ASSET_EVENT_IMPORT"
+)
+
+type JobStatus string
+
+const (
+	PendingJobStatus   JobStatus = "PENDING"
+	RunningJobStatus   JobStatus = "RUNNING"
+	SucceededJobStatus JobStatus = "SUCCEEDED"
+	FailedJobStatus    JobStatus = "FAILED"
+	CancelledJobStatus JobStatus = "CANCELLED"
+)
+
+// IsFinished reports whether the status is final, so the job is not run anymore.
+func (status JobStatus) IsFinished() bool {
+	return status == SucceededJobStatus || status == FailedJobStatus || status == CancelledJobStatus
+}
+
+// Job is a long operation run in the background by the job runner. Params and Result are JSON
+// documents whose structure depends on the job type. Progress is reported by the job as the number of
+// completed work items out of the total, both zero until the job reports it. CreatedByUserId is the user
+// submitting the job, zero when it was submitted without authentication.
+type Job struct {
+	Id                int64
+	JobType           JobType
+	CreatedByUserId   int64
+	Params            json.RawMessage
+	Status            JobStatus
+	ProgressCompleted int
+	ProgressTotal     int
+	Result            json.RawMessage
+	ErrorMessage      string
+	CreatedAt         time.Time
+	StartedAt         *time.Time
+	FinishedAt        *time.Time
+}
+
+// JobFilter selects the jobs to list. Zero value fields do not filter, except Limit, which has a default
+// when zero and caps nothing when negative.
+type JobFilter struct {
+	Status          JobStatus
+	JobType         JobType
+	CreatedByUserId int64
+	Limit           int
+}
+
+// IsVisibleTo reports whether the job can be seen by the user, which is only its creator. A zero user id,
+// of requests without authentication, sees every job.
+func (job *Job) IsVisibleTo(userId int64) bool {
+	return userId == 0 || job.CreatedByUserId == userId
+}
+
+// JobProgressReporter receives the progress of a running job.
+type JobProgressReporter interface {
+
+	// ReportProgress records that completed out of total work items of the job are done.
+	ReportProgress(completed int, total int)
+}
+
+// JobHandler runs the jobs of a type.
+type JobHandler interface {
+
+	// ValidateParams checks the parameters of a job before it is submitted.
+	// It returns a DomainValidationError when they are not valid for the job type.
+	ValidateParams(params json.RawMessage) error
+
+	// Run executes the job, reporting its progress, and returns the result to be recorded as JSON.
+	// The method must honor ctx cancellation, which happens when the job is cancelled or the
+	// application stops. Handlers must be safe to run again for a job interrupted by a stop.
+	Run(ctx context.Context, job *Job, progress JobProgressReporter) (any, error)
+}
+
+type JobRepository interface {
+	FindJobs(filter *JobFilter) ([]*Job, error)
+	FindJob(id int64) (*Job, error)
+	InsertJob(job *Job) (*Job, error)
+	StartJob(id int64) (*Job, error)
+	UpdateJobProgress(id int64, completed int, total int) error
+	FinishJob(job *Job) (*Job, error)
+	RequeueRunningJobs() (int64, error)
+}
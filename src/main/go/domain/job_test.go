@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobIsVisibleTo(t *testing.T) {
+
+	var createdJob = &Job{Id: 1, CreatedByUserId: 2}
+	assert.True(t, createdJob.IsVisibleTo(2))
+	assert.False(t, createdJob.IsVisibleTo(3))
+	assert.True(t, createdJob.IsVisibleTo(0))
+
+	var unauthenticatedJob = &Job{Id: 2}
+	assert.False(t, unauthenticatedJob.IsVisibleTo(2))
+	assert.True(t, unauthenticatedJob.IsVisibleTo(0))
+}
@@ -1,9 +1,18 @@
 package domain
 
+import (
+	"context"
+)
+
+// Portfolio is an investment portfolio. OwnerUserId is nil for portfolios created while authentication was
+// disabled, until an owner is assigned. Version is incremented on each update, and updates of a portfolio
+// with a nonzero version only apply to that version.
 type Portfolio struct {
 	Id                  int64
 	Name                string
 	AllocationStructure AllocationStructure
+	OwnerUserId         *int64
+	Version             int64
 }
 
 type AnalysisOptions struct {
@@ -13,7 +22,8 @@ type AnalysisOptions struct {
 
 type PortfolioRepository interface {
 	GetAllPortfolios() ([]*Portfolio, error)
-	FindPortfolio(id int64) (*Portfolio, error)
-	InsertPortfolio(portfolio *Portfolio) (*Portfolio, error)
-	UpdatePortfolio(portfolio *Portfolio) (*Portfolio, error)
+	GetUserPortfolios(userId int64) ([]*Portfolio, error)
+	FindPortfolio(queryContext context.Context, id int64) (*Portfolio, error)
+	InsertPortfolioInTransaction(transContext context.Context, portfolio *Portfolio) (*Portfolio, error)
+	UpdatePortfolioInTransaction(transContext context.Context, portfolio *Portfolio) (*Portfolio, error)
 }
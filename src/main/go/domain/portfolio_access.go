@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+)
+
+type PortfolioRole string
+
+const (
+	// OwnerPortfolioRole is held by the owner of a portfolio, who also manages its grants and share links.
+	OwnerPortfolioRole PortfolioRole = "OWNER"
+	// EditorPortfolioRole can read and change the data of a portfolio.
+	EditorPortfolioRole PortfolioRole = "EDITOR"
+	// ViewerPortfolioRole can only read the data of a portfolio.
+	ViewerPortfolioRole PortfolioRole = "VIEWER"
+)
+
+var portfolioRoleRanks = map[PortfolioRole]int{
+	ViewerPortfolioRole: 1,
+	EditorPortfolioRole: 2,
+	OwnerPortfolioRole:  3,
+}
+
+// IsValid reports whether the role is one of the portfolio roles.
+func (role PortfolioRole) IsValid() bool {
+	_, valid := portfolioRoleRanks[role]
+	return valid
+}
+
+// Includes reports whether the role allows everything the required role allows, like an EDITOR including
+// VIEWER. An empty role, of a user without access, includes nothing.
+func (role PortfolioRole) Includes(requiredRole PortfolioRole) bool {
+	var rank, valid = portfolioRoleRanks[role]
+	return valid && rank >= portfolioRoleRanks[requiredRole]
+}
+
+// PortfolioGrant gives a user other than the owner access to a portfolio with an EDITOR or VIEWER role.
+type PortfolioGrant struct {
+	PortfolioId int64
+	UserId      int64
+	Username    string
+	Role        PortfolioRole
+	CreatedAt   time.Time
+}
+
+// PortfolioShareLink gives read-only access to a portfolio to anyone holding its token, identified by the
+// SHA-256 hash of the token, until it expires at ExpiresAt or is revoked.
+type PortfolioShareLink struct {
+	Id              int64
+	PortfolioId     int64
+	TokenHash       string
+	CreatedByUserId int64
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+	RevokedAt       *time.Time
+}
+
+type PortfolioAccessRepository interface {
+	FindUserPortfolioRole(portfolioId int64, userId int64) (PortfolioRole, error)
+	AssignUnownedPortfolios() (int64, error)
+	FindPortfolioGrants(portfolioId int64) ([]*PortfolioGrant, error)
+	UpsertPortfolioGrant(grant *PortfolioGrant) (*PortfolioGrant, error)
+	DeletePortfolioGrant(portfolioId int64, userId int64) (bool, error)
+	InsertShareLink(shareLink *PortfolioShareLink) (*PortfolioShareLink, error)
+	FindPortfolioShareLinks(portfolioId int64) ([]*PortfolioShareLink, error)
+	FindActiveShareLink(tokenHash string, now time.Time) (*PortfolioShareLink, error)
+	RevokeShareLink(portfolioId int64, shareLinkId int64, revokedAt time.Time) (bool, error)
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortfolioRoleIncludes(t *testing.T) {
+
+	assert.True(t, OwnerPortfolioRole.Includes(EditorPortfolioRole))
+	assert.True(t, EditorPortfolioRole.Includes(ViewerPortfolioRole))
+	assert.True(t, ViewerPortfolioRole.Includes(ViewerPortfolioRole))
+	assert.False(t, ViewerPortfolioRole.Includes(EditorPortfolioRole))
+	assert.False(t, EditorPortfolioRole.Includes(OwnerPortfolioRole))
+	assert.False(t, PortfolioRole("").Includes(ViewerPortfolioRole))
+	assert.False(t, PortfolioRole("ADMIN").Includes(ViewerPortfolioRole))
+}
+
+func TestPrincipalShareLinkCannotWrite(t *testing.T) {
+
+	var shareLinkPrincipal = &Principal{ShareLink: &PortfolioShareLink{PortfolioId: 1}}
+	assert.True(t, shareLinkPrincipal.IsShareLink())
+	assert.False(t, shareLinkPrincipal.IsSession())
+	assert.False(t, shareLinkPrincipal.CanWrite())
+
+	var sessionPrincipal = &Principal{User: &User{Id: 1}}
+	assert.False(t, sessionPrincipal.IsShareLink())
+	assert.True(t, sessionPrincipal.IsSession())
+	assert.True(t, sessionPrincipal.CanWrite())
+}
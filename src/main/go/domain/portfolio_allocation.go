@@ -34,7 +34,11 @@ type PortfolioAllocationRepository interface {
 		id int64,
 		observationTimestampsLimit int,
 	) ([]*PortfolioAllocation, error)
-	FindPortfolioAllocationsByObservationTimestamp(id int64, observationTimestampId int64) (
+	FindPortfolioAllocationsByObservationTimestamp(
+		queryContext context.Context,
+		id int64,
+		observationTimestampId int64,
+	) (
 		[]*PortfolioAllocation,
 		error,
 	)
@@ -52,4 +56,11 @@ type PortfolioAllocationRepository interface {
 		transContext context.Context,
 		observationTimestamp *PortfolioObservationTimestamp,
 	) (*PortfolioObservationTimestamp, error)
+	FindPortfolioSnapshotVersion(portfolioId int64, observationTimestampId int64) (int64, error)
+	IncrementPortfolioSnapshotVersionInTransaction(
+		transContext context.Context,
+		portfolioId int64,
+		observationTimestampId int64,
+		expectedVersion int64,
+	) (int64, error)
 }
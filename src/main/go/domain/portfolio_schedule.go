@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type ScheduledTaskType string
+
+const (
+	RevaluationScheduledTaskType        ScheduledTaskType = "REVALUATION"
+	DivergenceAnalysisScheduledTaskType ScheduledTaskType = "DIVERGENCE_ANALYSIS"
+)
+
+// PortfolioSchedule is a recurring task of a portfolio, activated on a cron expression evaluated in the
+// timezone of the schedule. Params is a JSON document whose structure depends on the task type. NextRunAt
+// is the next activation, nil when the schedule is disabled or the expression has no next activation.
+type PortfolioSchedule struct {
+	Id             int64
+	PortfolioId    int64
+	TaskType       ScheduledTaskType
+	CronExpression string
+	Timezone       string
+	Params         json.RawMessage
+	Enabled        bool
+	NextRunAt      *time.Time
+	CreatedAt      time.Time
+}
+
+type ScheduleRunStatus string
+
+const (
+	SucceededScheduleRunStatus ScheduleRunStatus = "SUCCEEDED"
+	FailedScheduleRunStatus    ScheduleRunStatus = "FAILED"
+	MissedScheduleRunStatus    ScheduleRunStatus = "MISSED"
+)
+
+// PortfolioScheduleRun is an activation of a schedule. Missed activations, reached by the scheduler too
+// late to be run, have no start and finish times.
+type PortfolioScheduleRun struct {
+	Id           int64
+	ScheduleId   int64
+	ScheduledFor time.Time
+	Status       ScheduleRunStatus
+	StartedAt    *time.Time
+	FinishedAt   *time.Time
+	Result       json.RawMessage
+	ErrorMessage string
+}
+
+// ScheduledTaskHandler runs the scheduled tasks of a type.
+type ScheduledTaskHandler interface {
+
+	// ValidateParams checks the parameters of a schedule before it is persisted.
+	// It returns a DomainValidationError when they are not valid for the task type.
+	ValidateParams(params json.RawMessage) error
+
+	// Run executes the task of the schedule and returns the result to be recorded as JSON.
+	// The method must honor ctx cancellation, which happens when the scheduler stops.
+	Run(ctx context.Context, schedule *PortfolioSchedule) (any, error)
+}
+
+type PortfolioScheduleRepository interface {
+	FindPortfolioSchedules(portfolioId int64) ([]*PortfolioSchedule, error)
+	FindPortfolioSchedule(portfolioId int64, scheduleId int64) (*PortfolioSchedule, error)
+	FindDueSchedules(now time.Time) ([]*PortfolioSchedule, error)
+	InsertSchedule(schedule *PortfolioSchedule) (*PortfolioSchedule, error)
+	UpdateSchedule(schedule *PortfolioSchedule) (*PortfolioSchedule, error)
+	DeleteSchedule(portfolioId int64, scheduleId int64) (bool, error)
+	AdvanceScheduleNextRun(scheduleId int64, currentNextRunAt time.Time, nextRunAt *time.Time) (bool, error)
+	InsertScheduleRun(run *PortfolioScheduleRun) (*PortfolioScheduleRun, error)
+	FindScheduleRuns(scheduleId int64, limit int) ([]*PortfolioScheduleRun, error)
+}
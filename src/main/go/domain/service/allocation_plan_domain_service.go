@@ -25,8 +25,11 @@ func (service *AllocationPlanDomService) GetAllocationPlans(portfolioId int64, p
 	return service.allocationPlanRepository.GetAllAllocationPlans(portfolioId, planType)
 }
 
-func (service *AllocationPlanDomService) GetAllocationPlan(id int64) (*domain.AllocationPlan, error) {
-	return service.allocationPlanRepository.GetAllocationPlan(id)
+func (service *AllocationPlanDomService) GetAllocationPlan(
+	queryContext context.Context,
+	id int64,
+) (*domain.AllocationPlan, error) {
+	return service.allocationPlanRepository.GetAllocationPlan(queryContext, id)
 }
 
 func (service *AllocationPlanDomService) GetAllAllocationPlanIdentifiers(
@@ -36,11 +39,11 @@ func (service *AllocationPlanDomService) GetAllAllocationPlanIdentifiers(
 	return service.allocationPlanRepository.GetAllAllocationPlanIdentifiers(portfolioId, planType)
 }
 
-func (service *AllocationPlanDomService) GetPlannedAllocationsPerHyerarchicalIdMap(allocationPlanId int64) (
-	domain.PlannedAllocationsPerHierarchicalId,
-	error,
-) {
-	allocationPlan, err := service.GetAllocationPlan(allocationPlanId)
+func (service *AllocationPlanDomService) GetPlannedAllocationsPerHyerarchicalIdMap(
+	queryContext context.Context,
+	allocationPlanId int64,
+) (domain.PlannedAllocationsPerHierarchicalId, error) {
+	allocationPlan, err := service.GetAllocationPlan(queryContext, allocationPlanId)
 	if err != nil {
 		return nil, err
 	}
@@ -2,31 +2,86 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
 	"github.com/benizzio/open-asset-allocator/langext"
 )
 
 type AssetIntegrationServicesPerSource map[domain.AssetExternalSource]domain.AssetIntegrationService
 
+type AssetEventIntegrationServicesPerSource map[domain.AssetExternalSource]domain.AssetEventIntegrationService
+
 type AssetDomService struct {
-	assetRepository                   domain.AssetRepository
-	assetIntegrationServicesPerSource AssetIntegrationServicesPerSource
+	assetRepository                        domain.AssetRepository
+	assetIntegrationServicesPerSource      AssetIntegrationServicesPerSource
+	assetEventIntegrationServicesPerSource AssetEventIntegrationServicesPerSource
 }
 
 func (service *AssetDomService) GetKnownAssets() ([]*domain.Asset, error) {
 	return service.assetRepository.GetKnownAssets()
 }
 
+func (service *AssetDomService) GetPortfolioAssets(portfolioId int64) ([]*domain.Asset, error) {
+	return service.assetRepository.FindPortfolioAssets(portfolioId)
+}
+
+// FindAssetByUniqueIdentifier retrieves an asset by id or ticker, as given by API clients. A current
+// ticker has priority over an id, and both over an alias.
 func (service *AssetDomService) FindAssetByUniqueIdentifier(uniqueIdentifier string) (*domain.Asset, error) {
 	return service.assetRepository.FindAssetByUniqueIdentifier(uniqueIdentifier)
 }
 
-// UpdateAsset delegates the update of an asset's ticker and name to the repository.
+// FindAssetById retrieves an asset by id, or nil when it does not exist.
+func (service *AssetDomService) FindAssetById(id int64) (*domain.Asset, error) {
+	return service.assetRepository.FindAssetById(id)
+}
+
+// FindAssetForUpdateInTransaction retrieves an asset by id within an existing SQL transaction, locking it
+// until the transaction ends, so its changes are applied to its persisted state. A non-zero expected
+// version must match the persisted one.
 //
-// Authored by: GitHub Copilot
-func (service *AssetDomService) UpdateAsset(asset *domain.Asset) (*domain.Asset, error) {
-	return service.assetRepository.UpdateAsset(asset)
+// Returns:
+//   - *domain.Asset: the locked asset, or nil when it does not exist
+//   - error: a VersionConflictError when the asset changed since the expected version
+func (service *AssetDomService) FindAssetForUpdateInTransaction(
+	transContext context.Context,
+	id int64,
+	expectedVersion int64,
+) (*domain.Asset, error) {
+
+	asset, err := service.assetRepository.FindAssetForUpdateInTransaction(transContext, id)
+	if err != nil || asset == nil {
+		return nil, err
+	}
+
+	if !langext.IsZeroValue(expectedVersion) && asset.Version != expectedVersion {
+		return nil, infra.BuildVersionConflictError("Asset was changed since the requested version")
+	}
+
+	return asset, nil
+}
+
+// UpdateAssetInTransaction validates the asset metadata and updates the ticker, name and metadata of an
+// asset within an existing SQL transaction. Returns nil when the asset does not exist.
+func (service *AssetDomService) UpdateAssetInTransaction(
+	transContext context.Context,
+	asset *domain.Asset,
+) (*domain.Asset, error) {
+
+	var err = asset.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.assetRepository.UpdateAssetInTransaction(transContext, asset)
 }
 
 func (service *AssetDomService) InsertAssetsInTransaction(
@@ -43,6 +98,7 @@ func (service *AssetDomService) InsertMappedAssetsInTransaction(
 
 	var assets = make([]*domain.Asset, 0, len(assetsPerTicker))
 	for _, asset := range assetsPerTicker {
+		asset.FillMetadataFromExternalData()
 		assets = append(assets, asset)
 	}
 
@@ -59,27 +115,509 @@ func (service *AssetDomService) InsertMappedAssetsInTransaction(
 	return persistedAssetsPerTicker, nil
 }
 
-// collectIntegrationServices extracts the integration service values from the source-keyed map
-// into a slice suitable for concurrent processing.
+// PersistMappedAssetsInTransaction resolves the given tickers to already persisted assets, through
+// current tickers and the aliases valid at the reference date, and inserts only the assets that could
+// not be resolved.
+//
+// Parameters:
+//   - transContext: the SQL transactional context
+//   - assetsPerTicker: the assets without id, keyed by ticker
+//   - referenceDate: the date the tickers refer to, or nil when unknown
+//
+// Returns:
+//   - domain.AssetsPerTicker: the persisted assets keyed by the requested ticker
+//   - domain.AssetsPerTicker: the inserted assets keyed by the requested ticker
+//   - error: error if resolution or insertion fails
+func (service *AssetDomService) PersistMappedAssetsInTransaction(
+	transContext context.Context,
+	assetsPerTicker domain.AssetsPerTicker,
+	referenceDate *time.Time,
+) (domain.AssetsPerTicker, domain.AssetsPerTicker, error) {
+
+	var tickers = make([]string, 0, len(assetsPerTicker))
+	for ticker := range assetsPerTicker {
+		tickers = append(tickers, ticker)
+	}
+
+	resolvedAssetsPerTicker, err := service.assetRepository.FindAssetsPerTickersInTransaction(
+		transContext,
+		tickers,
+		referenceDate,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unresolvedAssetsPerTicker = make(domain.AssetsPerTicker)
+	for ticker, asset := range assetsPerTicker {
+		if _, resolved := resolvedAssetsPerTicker[ticker]; !resolved {
+			unresolvedAssetsPerTicker[ticker] = asset
+		}
+	}
+
+	if len(unresolvedAssetsPerTicker) == 0 {
+		return resolvedAssetsPerTicker, nil, nil
+	}
+
+	insertedAssetsPerTicker, err := service.InsertMappedAssetsInTransaction(transContext, unresolvedAssetsPerTicker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for ticker, asset := range insertedAssetsPerTicker {
+		resolvedAssetsPerTicker[ticker] = asset
+	}
+
+	return resolvedAssetsPerTicker, insertedAssetsPerTicker, nil
+}
+
+// GetAssetAliases retrieves the ticker aliases of an asset.
+func (service *AssetDomService) GetAssetAliases(assetId int64) ([]*domain.AssetAlias, error) {
+	return service.assetRepository.FindAssetAliases(assetId)
+}
+
+// InsertAssetAliasInTransaction validates and persists a new ticker alias for an asset within an existing
+// SQL transaction.
+//
+// Returns:
+//   - *domain.AssetAlias: the persisted alias
+//   - error: a DomainValidationError for an invalid alias, or the persistence error
+func (service *AssetDomService) InsertAssetAliasInTransaction(
+	transContext context.Context,
+	alias *domain.AssetAlias,
+) (*domain.AssetAlias, error) {
+
+	var err = alias.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.assetRepository.InsertAssetAliasInTransaction(transContext, alias)
+}
+
+// DeleteAssetAliasInTransaction removes a ticker alias of an asset within an existing SQL transaction.
+//
+// Returns:
+//   - bool: false when the alias does not exist for the asset
+//   - error: error if the deletion fails
+func (service *AssetDomService) DeleteAssetAliasInTransaction(
+	transContext context.Context,
+	assetId int64,
+	aliasId int64,
+) (bool, error) {
+	return service.assetRepository.DeleteAssetAliasInTransaction(transContext, assetId, aliasId)
+}
+
+// GetAssetValuations retrieves the manual valuations of an asset, from the most recent to the oldest.
+func (service *AssetDomService) GetAssetValuations(assetId int64) ([]*domain.AssetValuation, error) {
+	return service.assetRepository.FindAssetValuations(assetId)
+}
+
+// RecordAssetValuationInTransaction validates and persists a manual valuation of an asset within an existing
+// SQL transaction, replacing any valuation on the same date.
+//
+// Returns:
+//   - *domain.AssetValuation: the persisted valuation
+//   - error: a DomainValidationError for an invalid valuation, or the persistence error
+func (service *AssetDomService) RecordAssetValuationInTransaction(
+	transContext context.Context,
+	valuation *domain.AssetValuation,
+) (*domain.AssetValuation, error) {
+
+	var err = valuation.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.assetRepository.MergeAssetValuationInTransaction(transContext, valuation)
+}
+
+// LinkManualExternalAssetInTransaction links the MANUAL external asset to the asset within an existing SQL
+// transaction, as its lowest priority source, so the asset is quoted through its valuations.
+//
+// Returns:
+//   - *domain.Asset: the updated asset, nil when the MANUAL external asset is already linked
+//   - error: error if the update fails
+func (service *AssetDomService) LinkManualExternalAssetInTransaction(
+	transContext context.Context,
+	asset *domain.Asset,
+) (*domain.Asset, error) {
+
+	var manualExternalAsset = domain.BuildManualExternalAsset(asset.Id)
+	if asset.ExternalData != nil && asset.ExternalData.FindIndex(&manualExternalAsset) >= 0 {
+		return nil, nil
+	}
+
+	return service.LinkExternalAssetInTransaction(transContext, asset, manualExternalAsset)
+}
+
+// DeleteAssetValuationInTransaction removes a manual valuation of an asset within an existing SQL
+// transaction. The MANUAL external asset stays linked.
+//
+// Returns:
+//   - bool: false when the valuation does not exist for the asset
+//   - error: error if the deletion fails
+func (service *AssetDomService) DeleteAssetValuationInTransaction(
+	transContext context.Context,
+	assetId int64,
+	valuationId int64,
+) (bool, error) {
+	return service.assetRepository.DeleteAssetValuationInTransaction(transContext, assetId, valuationId)
+}
+
+// LinkExternalAssetInTransaction links an external asset to the asset as its lowest priority external
+// source, within an existing SQL transaction. Missing instrument type and exchange are filled from the
+// linked external assets.
+//
+// Returns:
+//   - *domain.Asset: the updated asset
+//   - error: a DomainValidationError when the source is invalid or the external asset is already linked
+func (service *AssetDomService) LinkExternalAssetInTransaction(
+	transContext context.Context,
+	asset *domain.Asset,
+	externalAsset domain.ExternalAsset,
+) (*domain.Asset, error) {
+
+	var externalData = copyExternalAssetData(asset)
+
+	var err = externalData.Link(externalAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.updateExternalAssetDataInTransaction(transContext, asset, externalData)
+}
+
+// UnlinkExternalAssetInTransaction removes the external asset with the same reference from the asset,
+// within an existing SQL transaction.
+//
+// Returns:
+//   - *domain.Asset: the updated asset, nil when the external asset was not linked
+//   - error: error if the update fails
+func (service *AssetDomService) UnlinkExternalAssetInTransaction(
+	transContext context.Context,
+	asset *domain.Asset,
+	reference *domain.ExternalAsset,
+) (*domain.Asset, error) {
+
+	var externalData = copyExternalAssetData(asset)
+
+	if !externalData.Unlink(reference) {
+		return nil, nil
+	}
+
+	return service.updateExternalAssetDataInTransaction(transContext, asset, externalData)
+}
+
+// PrioritizeExternalAssetsInTransaction reorders the external assets linked to the asset, from highest to
+// lowest priority, within an existing SQL transaction. Missing instrument type and exchange are filled
+// from the linked external assets in the new order.
+//
+// Returns:
+//   - *domain.Asset: the updated asset
+//   - error: a DomainValidationError when the references are not a reordering of the linked external assets
+func (service *AssetDomService) PrioritizeExternalAssetsInTransaction(
+	transContext context.Context,
+	asset *domain.Asset,
+	references []domain.ExternalAsset,
+) (*domain.Asset, error) {
+
+	var externalData = copyExternalAssetData(asset)
+
+	var err = externalData.Prioritize(references)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.updateExternalAssetDataInTransaction(transContext, asset, externalData)
+}
+
+func (service *AssetDomService) updateExternalAssetDataInTransaction(
+	transContext context.Context,
+	asset *domain.Asset,
+	externalData *domain.ExternalAssetData,
+) (*domain.Asset, error) {
+	var updatingAsset = *asset
+	updatingAsset.ExternalData = externalData
+	updatingAsset.FillMetadataFromExternalData()
+	return service.assetRepository.UpdateAssetExternalDataInTransaction(transContext, &updatingAsset)
+}
+
+// copyExternalAssetData returns a copy of the asset external data that can be modified without
+// affecting the asset.
+func copyExternalAssetData(asset *domain.Asset) *domain.ExternalAssetData {
+	var externalData = &domain.ExternalAssetData{Data: make([]domain.ExternalAsset, 0)}
+	if asset.ExternalData != nil {
+		externalData.Data = append(externalData.Data, asset.ExternalData.Data...)
+	}
+	return externalData
+}
+
+// QuoteAssetLastClosePrice quotes the last close price of an asset through its linked external assets,
+// trying them in priority order and falling back to the next one when quoting fails.
+//
+// Parameters:
+//   - asset: the asset to quote
+//
+// Returns:
+//   - *domain.ExternalAssetQuote: the quote from the highest priority external source that succeeded
+//   - error: a DomainValidationError when the asset has no linked external asset, or an error joining
+//     the failures of every external source
+func (service *AssetDomService) QuoteAssetLastClosePrice(asset *domain.Asset) (*domain.ExternalAssetQuote, error) {
+
+	if asset.ExternalData == nil || len(asset.ExternalData.Data) == 0 {
+		return nil, infra.BuildDomainValidationError(
+			"Asset "+asset.Ticker+" has no linked external asset to quote",
+			nil,
+		)
+	}
+
+	var quoteErrors = make([]error, 0, len(asset.ExternalData.Data))
+	for index := range asset.ExternalData.Data {
+
+		var externalAsset = &asset.ExternalData.Data[index]
+
+		quote, err := service.quoteExternalAssetLastClosePrice(externalAsset)
+		if err == nil {
+			return quote, nil
+		}
+
+		quoteErrors = append(quoteErrors, err)
+	}
+
+	return nil, infra.PropagateAsAppErrorWithNewMessage(
+		errors.Join(quoteErrors...),
+		"Every linked external source failed to quote asset "+asset.Ticker,
+		service,
+	)
+}
+
+func (service *AssetDomService) quoteExternalAssetLastClosePrice(
+	externalAsset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+
+	integrationService, ok := service.assetIntegrationServicesPerSource[externalAsset.Source]
+	if !ok {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"No integration service configured for source %s",
+			externalAsset.Source,
+		)
+	}
+
+	return integrationService.QuoteAssetLastClosePrice(externalAsset)
+}
+
+// externalAssetQuoteBatch holds the external assets of one source to quote in a round of a batch quote,
+// with the index of the asset each one is linked to.
+type externalAssetQuoteBatch struct {
+	integrationService domain.AssetIntegrationService
+	externalAssets     []*domain.ExternalAsset
+	assetIndexes       []int
+}
+
+type indexedExternalAssetQuoteResult struct {
+	assetIndex int
+	result     *domain.ExternalAssetQuoteResult
+}
+
+// QuoteAssetsLastClosePrice quotes the last close prices of several assets, such as the holdings of a
+// portfolio. Like QuoteAssetLastClosePrice, the linked external assets of each asset are tried in
+// priority order. Each round quotes the next linked external asset of the assets not quoted yet, grouped
+// per source and with the sources quoted concurrently, each within its own concurrency bound.
+//
+// Parameters:
+//   - requestContext: the context of the request, cancelling the remaining quotes when done
+//   - assets: the assets to quote
 //
-// Authored by: GitHub Copilot (claude-opus-4.6)
-func collectIntegrationServices(servicesPerSource AssetIntegrationServicesPerSource) []domain.AssetIntegrationService {
-	var services = make([]domain.AssetIntegrationService, 0, len(servicesPerSource))
-	for _, integrationService := range servicesPerSource {
-		services = append(services, integrationService)
+// Returns:
+//   - []*domain.AssetQuoteResult: one result per asset, in the given order, with the quote or the error
+//     of that asset, so that failures do not discard the other quotes
+func (service *AssetDomService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	assets []*domain.Asset,
+) []*domain.AssetQuoteResult {
+
+	var results = make([]*domain.AssetQuoteResult, len(assets))
+	var quoteErrors = make([][]error, len(assets))
+	var pendingAssetIndexes = make([]int, 0, len(assets))
+
+	for index, asset := range assets {
+		if asset.ExternalData == nil || len(asset.ExternalData.Data) == 0 {
+			results[index] = &domain.AssetQuoteResult{
+				Asset: asset,
+				Err: infra.BuildDomainValidationError(
+					"Asset "+asset.Ticker+" has no linked external asset to quote",
+					nil,
+				),
+			}
+			continue
+		}
+		pendingAssetIndexes = append(pendingAssetIndexes, index)
+	}
+
+	for priority := 0; len(pendingAssetIndexes) > 0; priority++ {
+
+		var batches = service.buildExternalAssetQuoteBatches(assets, pendingAssetIndexes, priority, quoteErrors)
+		var roundResults = quoteExternalAssetQuoteBatches(requestContext, batches)
+
+		for _, roundResult := range roundResults {
+			var assetIndex = roundResult.assetIndex
+			if roundResult.result.Err == nil {
+				results[assetIndex] = &domain.AssetQuoteResult{Asset: assets[assetIndex], Quote: roundResult.result.Quote}
+			} else {
+				quoteErrors[assetIndex] = append(quoteErrors[assetIndex], roundResult.result.Err)
+			}
+		}
+
+		var nextPendingAssetIndexes = make([]int, 0, len(pendingAssetIndexes))
+		for _, assetIndex := range pendingAssetIndexes {
+
+			if results[assetIndex] != nil {
+				continue
+			}
+
+			if priority+1 < len(assets[assetIndex].ExternalData.Data) && requestContext.Err() == nil {
+				nextPendingAssetIndexes = append(nextPendingAssetIndexes, assetIndex)
+				continue
+			}
+
+			// a done context interrupts the round without results for the assets not quoted yet
+			var quoteErr = errors.Join(append(quoteErrors[assetIndex], requestContext.Err())...)
+			results[assetIndex] = &domain.AssetQuoteResult{
+				Asset: assets[assetIndex],
+				Err: infra.PropagateAsAppErrorWithNewMessage(
+					quoteErr,
+					"Every linked external source failed to quote asset "+assets[assetIndex].Ticker,
+					service,
+				),
+			}
+		}
+
+		pendingAssetIndexes = nextPendingAssetIndexes
+	}
+
+	return results
+}
+
+// buildExternalAssetQuoteBatches groups the external assets of the given priority of the pending assets
+// per source. External assets of sources without integration service fail right away.
+func (service *AssetDomService) buildExternalAssetQuoteBatches(
+	assets []*domain.Asset,
+	pendingAssetIndexes []int,
+	priority int,
+	quoteErrors [][]error,
+) []*externalAssetQuoteBatch {
+
+	var batchesPerSource = make(map[domain.AssetExternalSource]*externalAssetQuoteBatch)
+	var batches = make([]*externalAssetQuoteBatch, 0)
+
+	for _, assetIndex := range pendingAssetIndexes {
+
+		var externalAsset = &assets[assetIndex].ExternalData.Data[priority]
+
+		batch, exists := batchesPerSource[externalAsset.Source]
+		if !exists {
+
+			integrationService, configured := service.assetIntegrationServicesPerSource[externalAsset.Source]
+			if !configured {
+				quoteErrors[assetIndex] = append(
+					quoteErrors[assetIndex],
+					infra.BuildAppErrorFormatted(
+						service,
+						"No integration service configured for source %s",
+						externalAsset.Source,
+					),
+				)
+				continue
+			}
+
+			batch = &externalAssetQuoteBatch{integrationService: integrationService}
+			batchesPerSource[externalAsset.Source] = batch
+			batches = append(batches, batch)
+		}
+
+		batch.externalAssets = append(batch.externalAssets, externalAsset)
+		batch.assetIndexes = append(batch.assetIndexes, assetIndex)
+	}
+
+	return batches
+}
+
+// quoteExternalAssetQuoteBatches quotes the batches of every source concurrently, collecting the results
+// of each batch on its own, so the quotes of the batches completed before the context is done are kept.
+// Errors are carried per asset in the results, and the assets a batch did not quote get the context
+// error, so no batch interrupts the others.
+func quoteExternalAssetQuoteBatches(
+	requestContext context.Context,
+	batches []*externalAssetQuoteBatch,
+) []indexedExternalAssetQuoteResult {
+
+	var quoteBatch = func(
+		quoteContext context.Context,
+		batch *externalAssetQuoteBatch,
+	) []indexedExternalAssetQuoteResult {
+
+		var batchResults = batch.integrationService.QuoteAssetsLastClosePrice(quoteContext, batch.externalAssets)
+
+		var indexedResults = make([]indexedExternalAssetQuoteResult, len(batch.externalAssets))
+		for index, externalAsset := range batch.externalAssets {
+
+			var batchResult *domain.ExternalAssetQuoteResult
+			if index < len(batchResults) {
+				batchResult = batchResults[index]
+			}
+			if batchResult == nil {
+				batchResult = &domain.ExternalAssetQuoteResult{
+					ExternalAsset: externalAsset,
+					Err:           buildUnquotedExternalAssetError(quoteContext, batch, externalAsset),
+				}
+			}
+
+			indexedResults[index] = indexedExternalAssetQuoteResult{
+				assetIndex: batch.assetIndexes[index],
+				result:     batchResult,
+			}
+		}
+
+		return indexedResults
+	}
+
+	var batchesResults = langext.MapConcurrentlyBoundedCtx(requestContext, batches, len(batches), quoteBatch)
+
+	var roundResults = make([]indexedExternalAssetQuoteResult, 0)
+	for _, batchResults := range batchesResults {
+		roundResults = append(roundResults, batchResults...)
+	}
+	return roundResults
+}
+
+func buildUnquotedExternalAssetError(
+	quoteContext context.Context,
+	batch *externalAssetQuoteBatch,
+	externalAsset *domain.ExternalAsset,
+) error {
+	if err := quoteContext.Err(); err != nil {
+		return err
 	}
-	return services
+	return infra.BuildAppErrorFormatted(
+		batch.integrationService,
+		"No quote returned for external asset %s",
+		externalAsset.String(),
+	)
 }
 
 // SearchExternalAssets queries all configured external asset integration services concurrently
 // for assets matching the given query, and returns the aggregated results.
+// A failing source (outage, rate limit) is logged and skipped, so the remaining sources still provide
+// redundancy; the search only fails when every configured source fails.
 //
 // Parameters:
 //   - query: the search term to query across all configured external sources
 //
 // Returns:
-//   - []*domain.ExternalAsset: the aggregated external assets from all sources
-//   - error: the first error encountered from any source, or nil if all succeeded
+//   - []*domain.ExternalAsset: the aggregated external assets from the sources that succeeded
+//   - error: the joined errors of all sources when every source failed, or nil otherwise
 //
 // Co-authored by: OpenCode and benizzio
 func (service *AssetDomService) SearchExternalAssets(
@@ -87,24 +625,218 @@ func (service *AssetDomService) SearchExternalAssets(
 	query string,
 ) ([]*domain.ExternalAsset, error) {
 
-	var integrationServices = collectIntegrationServices(service.assetIntegrationServicesPerSource)
+	var sources = slices.Collect(maps.Keys(service.assetIntegrationServicesPerSource))
 
-	var searchAssetsOnService = func(
+	var failuresMutex sync.Mutex
+	var failures = make([]error, 0, len(sources))
+
+	var searchAssetsOnSource = func(
 		searchContext context.Context,
-		integrationService domain.AssetIntegrationService,
+		source domain.AssetExternalSource,
 	) ([]*domain.ExternalAsset, error) {
-		return integrationService.SearchAssets(searchContext, query)
+
+		var integrationService = service.assetIntegrationServicesPerSource[source]
+		var externalAssets, err = integrationService.SearchAssets(searchContext, query)
+		if err != nil {
+			slog.WarnContext(
+				searchContext,
+				"Error searching external assets, skipping source",
+				"source", source,
+				"error", err,
+			)
+
+			failuresMutex.Lock()
+			failures = append(failures, err)
+			failuresMutex.Unlock()
+
+			return nil, nil
+		}
+
+		return externalAssets, nil
+	}
+
+	var externalAssets, err = langext.FlatMapConcurrentlyCtx(requestContext, sources, searchAssetsOnSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sources) > 0 && len(failures) == len(sources) {
+		var joinedFailures = errors.Join(failures...)
+		return nil, infra.PropagateAsAppErrorWithNewMessage(
+			joinedFailures,
+			"All external asset sources failed searching for \""+query+"\": "+joinedFailures.Error(),
+			service,
+		)
+	}
+
+	return externalAssets, nil
+}
+
+// GetIntegrationCacheMetrics returns the cache metrics of the integration services that are cached,
+// ordered by source.
+func (service *AssetDomService) GetIntegrationCacheMetrics() []*domain.AssetIntegrationCacheMetrics {
+
+	var metrics = make([]*domain.AssetIntegrationCacheMetrics, 0, len(service.assetIntegrationServicesPerSource))
+	for _, integrationService := range service.assetIntegrationServicesPerSource {
+		if cachedService, ok := integrationService.(*CachedAssetIntegrationService); ok {
+			metrics = append(metrics, cachedService.GetMetrics())
+		}
 	}
 
-	return langext.FlatMapConcurrentlyCtx(requestContext, integrationServices, searchAssetsOnService)
+	slices.SortFunc(
+		metrics,
+		func(first, second *domain.AssetIntegrationCacheMetrics) int {
+			return strings.Compare(string(first.Source), string(second.Source))
+		},
+	)
+
+	return metrics
+}
+
+// GetAssetEvents retrieves the dividend and split events recorded for an asset, ordered by date.
+func (service *AssetDomService) GetAssetEvents(assetId int64) ([]*domain.AssetEvent, error) {
+	return service.assetRepository.FindAssetEvents(assetId)
+}
+
+// ImportAssetEvents fetches the dividend and split history of an asset from its linked external assets
+// able to report events, trying them in priority order and falling back to the next one when fetching
+// fails, and records it. Events already recorded for the same type and date are updated.
+//
+// Returns:
+//   - []*domain.AssetEvent: every event recorded for the asset after the import, ordered by date
+//   - error: a DomainValidationError when no linked external asset reports events, or an error joining
+//     the failures of every external source
+func (service *AssetDomService) ImportAssetEvents(
+	requestContext context.Context,
+	asset *domain.Asset,
+) ([]*domain.AssetEvent, error) {
+
+	var importErrors = make([]error, 0)
+	if asset.ExternalData != nil {
+		for index := range asset.ExternalData.Data {
+
+			var externalAsset = &asset.ExternalData.Data[index]
+
+			eventIntegrationService, ok := service.assetEventIntegrationServicesPerSource[externalAsset.Source]
+			if !ok {
+				continue
+			}
+
+			events, err := eventIntegrationService.GetAssetEvents(requestContext, externalAsset)
+			if err != nil {
+				importErrors = append(importErrors, err)
+				continue
+			}
+
+			return service.recordImportedAssetEvents(asset.Id, events)
+		}
+	}
+
+	if len(importErrors) == 0 {
+		return nil, infra.BuildDomainValidationError(
+			"Asset "+asset.Ticker+" has no linked external asset providing dividend and split events",
+			nil,
+		)
+	}
+
+	return nil, infra.PropagateAsAppErrorWithNewMessage(
+		errors.Join(importErrors...),
+		"Every linked external source failed to provide events for asset "+asset.Ticker,
+		service,
+	)
+}
+
+func (service *AssetDomService) recordImportedAssetEvents(
+	assetId int64,
+	events []*domain.AssetEvent,
+) ([]*domain.AssetEvent, error) {
+
+	if len(events) > 0 {
+
+		for _, event := range events {
+			event.AssetId = assetId
+		}
+
+		var _, err = service.assetRepository.MergeAssetEvents(assetId, events)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return service.assetRepository.FindAssetEvents(assetId)
+}
+
+// GetPortfolioIncomeRecords derives the dividend income of a portfolio from the recorded dividends of
+// its assets, from the most recent. The quantity of each record is the one held in the latest portfolio
+// observation up to the ex-dividend date, adjusted for the splits between the observation and that date.
+func (service *AssetDomService) GetPortfolioIncomeRecords(portfolioId int64) ([]*domain.AssetIncomeRecord, error) {
+
+	incomeRecords, err := service.assetRepository.FindPortfolioDividendIncome(portfolioId)
+	if err != nil || len(incomeRecords) == 0 {
+		return incomeRecords, err
+	}
+
+	var assetIds = collectDistinctAssetIds(incomeRecords, func(record *domain.AssetIncomeRecord) int64 {
+		return record.Asset.Id
+	})
+	eventsPerAssetId, err := service.assetRepository.FindAssetEventsPerAssetId(assetIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range incomeRecords {
+		var factor = domain.SplitAdjustmentFactor(
+			eventsPerAssetId[record.Asset.Id],
+			record.ObservationTimestamp,
+			record.ExDividendDate,
+		)
+		record.AssetQuantity = record.AssetQuantity.Mul(factor)
+	}
+
+	return incomeRecords, nil
+}
+
+// AdjustPortfolioAllocationsForSplits converts the quantities and prices of historical allocations to
+// the current share basis, applying the recorded splits of each asset effective after the allocation
+// observation.
+func (service *AssetDomService) AdjustPortfolioAllocationsForSplits(allocations []*domain.PortfolioAllocation) error {
+
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	var assetIds = collectDistinctAssetIds(allocations, func(allocation *domain.PortfolioAllocation) int64 {
+		return allocation.Asset.Id
+	})
+	eventsPerAssetId, err := service.assetRepository.FindAssetEventsPerAssetId(assetIds)
+	if err != nil {
+		return err
+	}
+
+	domain.AdjustPortfolioAllocationsForSplits(allocations, eventsPerAssetId, time.Now())
+
+	return nil
+}
+
+func collectDistinctAssetIds[T any](items []T, getAssetId func(T) int64) []int64 {
+	var assetIds = make([]int64, 0, len(items))
+	for _, item := range items {
+		var assetId = getAssetId(item)
+		if !slices.Contains(assetIds, assetId) {
+			assetIds = append(assetIds, assetId)
+		}
+	}
+	return assetIds
 }
 
 func BuildAssetDomService(
 	assetRepository domain.AssetRepository,
 	integrationServices AssetIntegrationServicesPerSource,
+	eventIntegrationServices AssetEventIntegrationServicesPerSource,
 ) *AssetDomService {
 	return &AssetDomService{
-		assetRepository:                   assetRepository,
-		assetIntegrationServicesPerSource: integrationServices,
+		assetRepository:                        assetRepository,
+		assetIntegrationServicesPerSource:      integrationServices,
+		assetEventIntegrationServicesPerSource: eventIntegrationServices,
 	}
 }
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// blockingIntegrationService is a fake provider that only returns from batch quotes when the context is
+// done, without any result.
+type blockingIntegrationService struct {
+	countingIntegrationService
+}
+
+func (service *blockingIntegrationService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	_ []*domain.ExternalAsset,
+) []*domain.ExternalAssetQuoteResult {
+	<-requestContext.Done()
+	return nil
+}
+
+func TestQuoteAssetsLastClosePriceKeepsQuotesOfCompletedBatches(t *testing.T) {
+
+	var assetService = &AssetDomService{
+		assetIntegrationServicesPerSource: AssetIntegrationServicesPerSource{
+			domain.YahooFinanceSource: &countingIntegrationService{},
+			domain.StooqSource:        &blockingIntegrationService{},
+		},
+	}
+
+	var quotedAsset = &domain.Asset{
+		Ticker:       "ARCA:IAU",
+		ExternalData: &domain.ExternalAssetData{Data: []domain.ExternalAsset{*testQuotedAsset}},
+	}
+	var unfinishedAsset = &domain.Asset{
+		Ticker: "ARCA:SPY",
+		ExternalData: &domain.ExternalAssetData{
+			Data: []domain.ExternalAsset{{Source: domain.StooqSource, Ticker: "SPY.US", ExchangeId: "US"}},
+		},
+	}
+
+	var quoteContext, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var results = assetService.QuoteAssetsLastClosePrice(quoteContext, []*domain.Asset{quotedAsset, unfinishedAsset})
+
+	require.Len(t, results, 2)
+
+	assert.NoError(t, results[0].Err)
+	require.NotNil(t, results[0].Quote)
+	assert.Equal(t, "IAU", results[0].Quote.Ticker)
+
+	assert.Nil(t, results[1].Quote)
+	var appError *infra.AppError
+	require.ErrorAs(t, results[1].Err, &appError)
+	assert.ErrorIs(t, appError.Cause, context.DeadlineExceeded)
+}
+
+// failingSearchIntegrationService is a fake provider whose search always fails, as in an outage or rate limit.
+type failingSearchIntegrationService struct {
+	countingIntegrationService
+}
+
+func (service *failingSearchIntegrationService) SearchAssets(_ context.Context, _ string) (
+	[]*domain.ExternalAsset,
+	error,
+) {
+	return nil, errors.New("rate limited")
+}
+
+func TestSearchExternalAssetsSkipsFailingSource(t *testing.T) {
+
+	var assetService = &AssetDomService{
+		assetIntegrationServicesPerSource: AssetIntegrationServicesPerSource{
+			domain.YahooFinanceSource: &countingIntegrationService{},
+			domain.StooqSource:        &failingSearchIntegrationService{},
+		},
+	}
+
+	var externalAssets, err = assetService.SearchExternalAssets(context.Background(), "IAU")
+
+	require.NoError(t, err)
+	require.Len(t, externalAssets, 1)
+	assert.Equal(t, domain.YahooFinanceSource, externalAssets[0].Source)
+	assert.Equal(t, "IAU", externalAssets[0].Ticker)
+}
+
+func TestSearchExternalAssetsFailsWhenAllSourcesFail(t *testing.T) {
+
+	var assetService = &AssetDomService{
+		assetIntegrationServicesPerSource: AssetIntegrationServicesPerSource{
+			domain.YahooFinanceSource: &failingSearchIntegrationService{},
+			domain.StooqSource:        &failingSearchIntegrationService{},
+		},
+	}
+
+	var externalAssets, err = assetService.SearchExternalAssets(context.Background(), "IAU")
+
+	assert.Nil(t, externalAssets)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "rate limited")
+}
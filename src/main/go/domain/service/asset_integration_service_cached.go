@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const assetIntegrationCacheRefreshTimeout = 30 * time.Second
+const assetIntegrationCacheCleanupWritesInterval = 500
+
+type assetIntegrationCacheCounters struct {
+	hits          atomic.Int64
+	staleHits     atomic.Int64
+	misses        atomic.Int64
+	refreshErrors atomic.Int64
+}
+
+func (counters *assetIntegrationCacheCounters) snapshot() domain.AssetIntegrationCacheOperationMetrics {
+	return domain.AssetIntegrationCacheOperationMetrics{
+		Hits:          counters.hits.Load(),
+		StaleHits:     counters.staleHits.Load(),
+		Misses:        counters.misses.Load(),
+		RefreshErrors: counters.refreshErrors.Load(),
+	}
+}
+
+// cachedExternalAssetDTS is the cached form of a domain.ExternalAsset search result, keeping the
+// fields hidden from the domain JSON representation.
+type cachedExternalAssetDTS struct {
+	Source         domain.AssetExternalSource `json:"source"`
+	Ticker         string                     `json:"ticker"`
+	ExchangeId     string                     `json:"exchangeId"`
+	Name           string                     `json:"name"`
+	ExchangeName   string                     `json:"exchangeName"`
+	InstrumentType domain.AssetInstrumentType `json:"instrumentType"`
+}
+
+// cachedExternalAssetQuoteDTS is the cached form of a domain.ExternalAssetQuote, with the currency
+// as its ISO 4217 code.
+type cachedExternalAssetQuoteDTS struct {
+	Source         domain.AssetExternalSource `json:"source"`
+	Ticker         string                     `json:"ticker"`
+	ExchangeId     string                     `json:"exchangeId"`
+	Currency       string                     `json:"currency"`
+	LastCloseQuote decimal.Decimal            `json:"lastCloseQuote"`
+	LastCloseDate  time.Time                  `json:"lastCloseDate"`
+}
+
+// cachedOperation describes how to load, serialize and account one cached operation of the
+// integration service.
+type cachedOperation[T any] struct {
+	ttl      time.Duration
+	counters *assetIntegrationCacheCounters
+	load     func(requestContext context.Context) (T, error)
+	encode   func(value T) any
+	decode   func(payload string) (T, error)
+}
+
+// CachedAssetIntegrationService decorates the domain.AssetIntegrationService of a source with a
+// cache of its search and quote responses. Entries younger than the operation TTL are served as they
+// are. Entries older than the TTL but within the stale TTL are still served, while a single
+// background request per entry refreshes them. Older entries, or provider errors, are never served.
+// Cache storage failures are logged and fall back to the provider.
+type CachedAssetIntegrationService struct {
+	source              domain.AssetExternalSource
+	delegate            domain.AssetIntegrationService
+	cacheRepository     domain.AssetIntegrationCacheRepository
+	config              infra.AssetIntegrationCacheConfiguration
+	searchCounters      assetIntegrationCacheCounters
+	quoteCounters       assetIntegrationCacheCounters
+	refreshesInProgress sync.Map
+	writesSinceCleanup  atomic.Int64
+	now                 func() time.Time
+}
+
+func (service *CachedAssetIntegrationService) SearchAssets(
+	requestContext context.Context,
+	queryValue string,
+) ([]*domain.ExternalAsset, error) {
+
+	var operation = &cachedOperation[[]*domain.ExternalAsset]{
+		ttl:      service.config.SearchTTL,
+		counters: &service.searchCounters,
+		load: func(loadContext context.Context) ([]*domain.ExternalAsset, error) {
+			return service.delegate.SearchAssets(loadContext, queryValue)
+		},
+		encode: func(externalAssets []*domain.ExternalAsset) any {
+			return mapToCachedExternalAssetDTSs(externalAssets)
+		},
+		decode: decodeCachedExternalAssets,
+	}
+
+	return getOrLoadCached(requestContext, service, operation, string(service.source)+":search:"+queryValue)
+}
+
+func (service *CachedAssetIntegrationService) QuoteAssetLastClosePrice(
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+	var operation, cacheKey = service.buildQuoteOperation(asset)
+	return getOrLoadCached(context.Background(), service, operation, cacheKey)
+}
+
+// QuoteAssetsLastClosePrice serves the cached quotes and requests the missing ones from the decorated
+// service in a single batch, so that its concurrency bound still applies.
+func (service *CachedAssetIntegrationService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	assets []*domain.ExternalAsset,
+) []*domain.ExternalAssetQuoteResult {
+
+	if service.config.QuoteTTL <= 0 {
+		return service.delegate.QuoteAssetsLastClosePrice(requestContext, assets)
+	}
+
+	var results = make([]*domain.ExternalAssetQuoteResult, len(assets))
+	var missedAssets = make([]*domain.ExternalAsset, 0, len(assets))
+	var missedAssetIndexes = make([]int, 0, len(assets))
+
+	for index, asset := range assets {
+
+		var operation, cacheKey = service.buildQuoteOperation(asset)
+		if quote, found := findCached(requestContext, service, operation, cacheKey); found {
+			results[index] = &domain.ExternalAssetQuoteResult{ExternalAsset: asset, Quote: quote}
+			continue
+		}
+
+		missedAssets = append(missedAssets, asset)
+		missedAssetIndexes = append(missedAssetIndexes, index)
+	}
+
+	if len(missedAssets) == 0 {
+		return results
+	}
+
+	var loadedResults = service.delegate.QuoteAssetsLastClosePrice(requestContext, missedAssets)
+	for missedIndex, loadedResult := range loadedResults {
+
+		if loadedResult.Err == nil {
+			var _, cacheKey = service.buildQuoteOperation(loadedResult.ExternalAsset)
+			service.storeCacheEntry(requestContext, cacheKey, mapToCachedExternalAssetQuoteDTS(loadedResult.Quote))
+		}
+
+		results[missedAssetIndexes[missedIndex]] = loadedResult
+	}
+
+	return results
+}
+
+func (service *CachedAssetIntegrationService) buildQuoteOperation(
+	asset *domain.ExternalAsset,
+) (*cachedOperation[*domain.ExternalAssetQuote], string) {
+
+	var operation = &cachedOperation[*domain.ExternalAssetQuote]{
+		ttl:      service.config.QuoteTTL,
+		counters: &service.quoteCounters,
+		load: func(_ context.Context) (*domain.ExternalAssetQuote, error) {
+			return service.delegate.QuoteAssetLastClosePrice(asset)
+		},
+		encode: func(quote *domain.ExternalAssetQuote) any {
+			return mapToCachedExternalAssetQuoteDTS(quote)
+		},
+		decode: decodeCachedExternalAssetQuote,
+	}
+
+	return operation, string(service.source) + ":quote:" + asset.ExchangeId + ":" + asset.Ticker
+}
+
+// GetMetrics returns the cache metrics accumulated since the service was built.
+func (service *CachedAssetIntegrationService) GetMetrics() *domain.AssetIntegrationCacheMetrics {
+	return &domain.AssetIntegrationCacheMetrics{
+		Source: service.source,
+		Search: service.searchCounters.snapshot(),
+		Quote:  service.quoteCounters.snapshot(),
+	}
+}
+
+func getOrLoadCached[T any](
+	requestContext context.Context,
+	service *CachedAssetIntegrationService,
+	operation *cachedOperation[T],
+	cacheKey string,
+) (T, error) {
+
+	if operation.ttl <= 0 {
+		return operation.load(requestContext)
+	}
+
+	if value, found := findCached(requestContext, service, operation, cacheKey); found {
+		return value, nil
+	}
+
+	return loadAndStore(requestContext, service, operation, cacheKey)
+}
+
+// findCached returns the cached value when the entry can still be served, refreshing it in the
+// background when it is stale, and accounts the request as a hit, stale hit or miss.
+func findCached[T any](
+	requestContext context.Context,
+	service *CachedAssetIntegrationService,
+	operation *cachedOperation[T],
+	cacheKey string,
+) (T, bool) {
+
+	var entry = service.findCacheEntry(requestContext, cacheKey)
+	if entry != nil {
+
+		var age = service.now().Sub(entry.StoredAt)
+		if age < operation.ttl+service.config.StaleTTL {
+
+			value, err := operation.decode(entry.Payload)
+			if err == nil {
+
+				if age < operation.ttl {
+					operation.counters.hits.Add(1)
+				} else {
+					operation.counters.staleHits.Add(1)
+					refreshInBackground(service, operation, cacheKey)
+				}
+
+				return value, true
+			}
+
+			slog.WarnContext(
+				requestContext,
+				"Discarding undecodable asset integration cache entry",
+				"cacheKey", cacheKey,
+				"error", err,
+			)
+		}
+	}
+
+	operation.counters.misses.Add(1)
+
+	var zeroValue T
+	return zeroValue, false
+}
+
+func loadAndStore[T any](
+	requestContext context.Context,
+	service *CachedAssetIntegrationService,
+	operation *cachedOperation[T],
+	cacheKey string,
+) (T, error) {
+
+	value, err := operation.load(requestContext)
+	if err != nil {
+		return value, err
+	}
+
+	service.storeCacheEntry(requestContext, cacheKey, operation.encode(value))
+	return value, nil
+}
+
+// refreshInBackground reloads an expired entry without blocking the request that found it, unless
+// a refresh of the same entry is already in progress.
+func refreshInBackground[T any](
+	service *CachedAssetIntegrationService,
+	operation *cachedOperation[T],
+	cacheKey string,
+) {
+
+	if _, inProgress := service.refreshesInProgress.LoadOrStore(cacheKey, struct{}{}); inProgress {
+		return
+	}
+
+	go func() {
+		defer service.refreshesInProgress.Delete(cacheKey)
+
+		var refreshContext, cancel = context.WithTimeout(context.Background(), assetIntegrationCacheRefreshTimeout)
+		defer cancel()
+
+		if _, err := loadAndStore(refreshContext, service, operation, cacheKey); err != nil {
+			operation.counters.refreshErrors.Add(1)
+			slog.Warn("Error refreshing asset integration cache entry", "cacheKey", cacheKey, "error", err)
+		}
+	}()
+}
+
+func (service *CachedAssetIntegrationService) findCacheEntry(
+	requestContext context.Context,
+	cacheKey string,
+) *domain.AssetIntegrationCacheEntry {
+
+	entry, err := service.cacheRepository.FindCacheEntry(requestContext, cacheKey)
+	if err != nil {
+		slog.WarnContext(
+			requestContext,
+			"Error reading asset integration cache entry",
+			"cacheKey", cacheKey,
+			"error", err,
+		)
+		return nil
+	}
+
+	return entry
+}
+
+func (service *CachedAssetIntegrationService) storeCacheEntry(
+	requestContext context.Context,
+	cacheKey string,
+	value any,
+) {
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		slog.WarnContext(
+			requestContext,
+			"Error serializing asset integration cache entry",
+			"cacheKey", cacheKey,
+			"error", err,
+		)
+		return
+	}
+
+	var entry = &domain.AssetIntegrationCacheEntry{
+		CacheKey: cacheKey,
+		Payload:  string(payload),
+		StoredAt: service.now(),
+	}
+
+	if err = service.cacheRepository.MergeCacheEntry(requestContext, entry); err != nil {
+		slog.WarnContext(
+			requestContext,
+			"Error writing asset integration cache entry",
+			"cacheKey", cacheKey,
+			"error", err,
+		)
+		return
+	}
+
+	if service.writesSinceCleanup.Add(1)%assetIntegrationCacheCleanupWritesInterval == 0 {
+		service.deleteUnservableCacheEntries(requestContext)
+	}
+}
+
+// deleteUnservableCacheEntries removes the entries that are too old to be served by any operation,
+// so that the storage does not grow with every distinct search query.
+func (service *CachedAssetIntegrationService) deleteUnservableCacheEntries(requestContext context.Context) {
+
+	var maxAge = max(service.config.SearchTTL, service.config.QuoteTTL) + service.config.StaleTTL
+
+	var err = service.cacheRepository.DeleteCacheEntriesStoredBefore(requestContext, service.now().Add(-maxAge))
+	if err != nil {
+		slog.WarnContext(requestContext, "Error deleting expired asset integration cache entries", "error", err)
+	}
+}
+
+func mapToCachedExternalAssetDTSs(externalAssets []*domain.ExternalAsset) []cachedExternalAssetDTS {
+	var externalAssetDTSs = make([]cachedExternalAssetDTS, len(externalAssets))
+	for index, externalAsset := range externalAssets {
+		externalAssetDTSs[index] = cachedExternalAssetDTS{
+			Source:         externalAsset.Source,
+			Ticker:         externalAsset.Ticker,
+			ExchangeId:     externalAsset.ExchangeId,
+			Name:           externalAsset.Name,
+			ExchangeName:   externalAsset.ExchangeName,
+			InstrumentType: externalAsset.InstrumentType,
+		}
+	}
+	return externalAssetDTSs
+}
+
+func decodeCachedExternalAssets(payload string) ([]*domain.ExternalAsset, error) {
+
+	var externalAssetDTSs []cachedExternalAssetDTS
+	if err := json.Unmarshal([]byte(payload), &externalAssetDTSs); err != nil {
+		return nil, err
+	}
+
+	var externalAssets = make([]*domain.ExternalAsset, len(externalAssetDTSs))
+	for index, externalAssetDTS := range externalAssetDTSs {
+		externalAssets[index] = &domain.ExternalAsset{
+			Source:         externalAssetDTS.Source,
+			Ticker:         externalAssetDTS.Ticker,
+			ExchangeId:     externalAssetDTS.ExchangeId,
+			Name:           externalAssetDTS.Name,
+			ExchangeName:   externalAssetDTS.ExchangeName,
+			InstrumentType: externalAssetDTS.InstrumentType,
+		}
+	}
+
+	return externalAssets, nil
+}
+
+func mapToCachedExternalAssetQuoteDTS(quote *domain.ExternalAssetQuote) cachedExternalAssetQuoteDTS {
+	return cachedExternalAssetQuoteDTS{
+		Source:         quote.Source,
+		Ticker:         quote.Ticker,
+		ExchangeId:     quote.ExchangeId,
+		Currency:       quote.Currency.String(),
+		LastCloseQuote: quote.LastCloseQuote,
+		LastCloseDate:  quote.LastCloseDate,
+	}
+}
+
+func decodeCachedExternalAssetQuote(payload string) (*domain.ExternalAssetQuote, error) {
+
+	var quoteDTS cachedExternalAssetQuoteDTS
+	if err := json.Unmarshal([]byte(payload), &quoteDTS); err != nil {
+		return nil, err
+	}
+
+	currencyUnit, err := currency.ParseISO(quoteDTS.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ExternalAssetQuote{
+		Source:         quoteDTS.Source,
+		Ticker:         quoteDTS.Ticker,
+		ExchangeId:     quoteDTS.ExchangeId,
+		Currency:       currencyUnit,
+		LastCloseQuote: quoteDTS.LastCloseQuote,
+		LastCloseDate:  quoteDTS.LastCloseDate,
+	}, nil
+}
+
+// BuildCachedAssetIntegrationService wraps the integration service of a source with a cache stored in
+// the given repository.
+//
+// Example:
+//
+//	var cachedService = service.BuildCachedAssetIntegrationService(
+//	    domain.YahooFinanceSource,
+//	    yahooFinanceIntegrationService,
+//	    repository.BuildAssetIntegrationCacheMemoryRepository(),
+//	    config.IntegrationConfig.CacheConfig,
+//	)
+func BuildCachedAssetIntegrationService(
+	source domain.AssetExternalSource,
+	delegate domain.AssetIntegrationService,
+	cacheRepository domain.AssetIntegrationCacheRepository,
+	config infra.AssetIntegrationCacheConfiguration,
+) *CachedAssetIntegrationService {
+	return &CachedAssetIntegrationService{
+		source:          source,
+		delegate:        delegate,
+		cacheRepository: cacheRepository,
+		config:          config,
+		now:             time.Now,
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/domain/infra/repository"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// countingIntegrationService is a fake provider returning a quote with the number of calls received
+// as price, or failing when fail is set.
+type countingIntegrationService struct {
+	calls atomic.Int64
+	fail  atomic.Bool
+}
+
+func (service *countingIntegrationService) SearchAssets(_ context.Context, queryValue string) (
+	[]*domain.ExternalAsset,
+	error,
+) {
+	service.calls.Add(1)
+	return []*domain.ExternalAsset{
+		{
+			Source:         domain.YahooFinanceSource,
+			Ticker:         queryValue,
+			ExchangeId:     "NYQ",
+			Name:           "Test Asset",
+			ExchangeName:   "NYSE",
+			InstrumentType: domain.AssetInstrumentType("ETF"),
+		},
+	}, nil
+}
+
+func (service *countingIntegrationService) QuoteAssetLastClosePrice(asset *domain.ExternalAsset) (
+	*domain.ExternalAssetQuote,
+	error,
+) {
+	var calls = service.calls.Add(1)
+	if service.fail.Load() {
+		return nil, errors.New("provider unavailable")
+	}
+
+	return &domain.ExternalAssetQuote{
+		Source:         asset.Source,
+		Ticker:         asset.Ticker,
+		ExchangeId:     asset.ExchangeId,
+		Currency:       currency.USD,
+		LastCloseQuote: decimal.NewFromInt(calls),
+		LastCloseDate:  time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (service *countingIntegrationService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	assets []*domain.ExternalAsset,
+) []*domain.ExternalAssetQuoteResult {
+	var quoteAsset = func(_ context.Context, asset *domain.ExternalAsset) (*domain.ExternalAssetQuote, error) {
+		return service.QuoteAssetLastClosePrice(asset)
+	}
+	return domain.QuoteExternalAssetsConcurrently(requestContext, assets, 1, quoteAsset)
+}
+
+var testQuotedAsset = &domain.ExternalAsset{Source: domain.YahooFinanceSource, Ticker: "IAU", ExchangeId: "PCX"}
+
+func buildTestCachedService(delegate domain.AssetIntegrationService, now *time.Time) *CachedAssetIntegrationService {
+	var cachedService = BuildCachedAssetIntegrationService(
+		domain.YahooFinanceSource,
+		delegate,
+		repository.BuildAssetIntegrationCacheMemoryRepository(),
+		infra.AssetIntegrationCacheConfiguration{
+			SearchTTL: time.Hour,
+			QuoteTTL:  time.Minute,
+			StaleTTL:  time.Hour,
+		},
+	)
+	cachedService.now = func() time.Time { return *now }
+	return cachedService
+}
+
+// waitForRefreshes waits until the background refreshes of the cached service finish.
+func waitForRefreshes(t *testing.T, cachedService *CachedAssetIntegrationService) {
+	require.Eventually(
+		t,
+		func() bool {
+			var inProgress = false
+			cachedService.refreshesInProgress.Range(
+				func(_, _ any) bool {
+					inProgress = true
+					return false
+				},
+			)
+			return !inProgress
+		},
+		time.Second,
+		time.Millisecond,
+	)
+}
+
+func TestCachedAssetIntegrationService_QuoteFreshHit(t *testing.T) {
+
+	var now = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	var delegate = &countingIntegrationService{}
+	var cachedService = buildTestCachedService(delegate, &now)
+
+	var firstQuote, err = cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+	secondQuote, err := cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), delegate.calls.Load())
+	assert.Equal(t, firstQuote, secondQuote)
+	assert.Equal(
+		t,
+		domain.AssetIntegrationCacheOperationMetrics{Hits: 1, Misses: 1},
+		cachedService.GetMetrics().Quote,
+	)
+}
+
+func TestCachedAssetIntegrationService_QuoteStaleWhileRevalidate(t *testing.T) {
+
+	var now = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	var delegate = &countingIntegrationService{}
+	var cachedService = buildTestCachedService(delegate, &now)
+
+	_, err := cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Minute)
+	staleQuote, err := cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Equal(staleQuote.LastCloseQuote), "the stale quote is served")
+
+	waitForRefreshes(t, cachedService)
+
+	refreshedQuote, err := cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(2).Equal(refreshedQuote.LastCloseQuote), "the refreshed quote is served")
+
+	assert.Equal(t, int64(2), delegate.calls.Load())
+	assert.Equal(
+		t,
+		domain.AssetIntegrationCacheOperationMetrics{Hits: 1, StaleHits: 1, Misses: 1},
+		cachedService.GetMetrics().Quote,
+	)
+}
+
+func TestCachedAssetIntegrationService_QuoteRefreshErrorKeepsStaleEntry(t *testing.T) {
+
+	var now = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	var delegate = &countingIntegrationService{}
+	var cachedService = buildTestCachedService(delegate, &now)
+
+	_, err := cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.NoError(t, err)
+
+	delegate.fail.Store(true)
+	now = now.Add(2 * time.Minute)
+	staleQuote, err := cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Equal(staleQuote.LastCloseQuote))
+
+	waitForRefreshes(t, cachedService)
+	assert.Equal(t, int64(1), cachedService.GetMetrics().Quote.RefreshErrors)
+
+	// beyond the stale TTL the entry is not served anymore and the provider error is returned
+	now = now.Add(2 * time.Hour)
+	_, err = cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.Error(t, err)
+}
+
+func TestCachedAssetIntegrationService_SearchKeepsAllFields(t *testing.T) {
+
+	var now = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	var delegate = &countingIntegrationService{}
+	var cachedService = buildTestCachedService(delegate, &now)
+
+	firstResults, err := cachedService.SearchAssets(context.Background(), "IAU")
+	require.NoError(t, err)
+	cachedResults, err := cachedService.SearchAssets(context.Background(), "IAU")
+	require.NoError(t, err)
+	_, err = cachedService.SearchAssets(context.Background(), "GLD")
+	require.NoError(t, err)
+
+	assert.Equal(t, firstResults, cachedResults)
+	assert.Equal(t, int64(2), delegate.calls.Load())
+	assert.Equal(
+		t,
+		domain.AssetIntegrationCacheOperationMetrics{Hits: 1, Misses: 2},
+		cachedService.GetMetrics().Search,
+	)
+}
+
+func TestCachedAssetIntegrationService_DisabledOperationIsNotCached(t *testing.T) {
+
+	var delegate = &countingIntegrationService{}
+	var cachedService = BuildCachedAssetIntegrationService(
+		domain.YahooFinanceSource,
+		delegate,
+		repository.BuildAssetIntegrationCacheMemoryRepository(),
+		infra.AssetIntegrationCacheConfiguration{SearchTTL: time.Hour},
+	)
+
+	for range 2 {
+		_, err := cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(2), delegate.calls.Load())
+	assert.Equal(t, domain.AssetIntegrationCacheOperationMetrics{}, cachedService.GetMetrics().Quote)
+}
+
+func TestCachedAssetIntegrationService_BatchQuoteRequestsOnlyMissingAssets(t *testing.T) {
+
+	var now = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	var delegate = &countingIntegrationService{}
+	var cachedService = buildTestCachedService(delegate, &now)
+	var otherQuotedAsset = &domain.ExternalAsset{Source: domain.YahooFinanceSource, Ticker: "GLD", ExchangeId: "PCX"}
+
+	_, err := cachedService.QuoteAssetLastClosePrice(testQuotedAsset)
+	require.NoError(t, err)
+
+	var results = cachedService.QuoteAssetsLastClosePrice(
+		context.Background(),
+		[]*domain.ExternalAsset{testQuotedAsset, otherQuotedAsset},
+	)
+
+	require.Len(t, results, 2)
+	assert.Same(t, testQuotedAsset, results[0].ExternalAsset)
+	assert.True(t, decimal.NewFromInt(1).Equal(results[0].Quote.LastCloseQuote), "served from the cache")
+	assert.Same(t, otherQuotedAsset, results[1].ExternalAsset)
+	assert.True(t, decimal.NewFromInt(2).Equal(results[1].Quote.LastCloseQuote), "requested from the provider")
+	assert.Equal(t, int64(2), delegate.calls.Load())
+
+	// the quote requested in the batch is now cached
+	_, err = cachedService.QuoteAssetLastClosePrice(otherQuotedAsset)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), delegate.calls.Load())
+}
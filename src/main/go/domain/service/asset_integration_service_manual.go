@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"golang.org/x/text/currency"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// manualQuoteConcurrency bounds the concurrent valuation lookups of a batch quote, to leave database
+// connections to other requests.
+const manualQuoteConcurrency = 4
+
+// ManualAssetIntegrationService is the domain.AssetIntegrationService of the MANUAL source. It quotes
+// assets without market quotes through the valuations recorded for them, instead of an external
+// provider. Its external assets reference the quoted asset by id (see domain.BuildManualExternalAsset).
+type ManualAssetIntegrationService struct {
+	assetRepository domain.AssetRepository
+}
+
+// SearchAssets returns no results, as manual valuations are not discoverable through external asset
+// searches. The MANUAL external asset is linked when the first valuation of an asset is recorded.
+func (service *ManualAssetIntegrationService) SearchAssets(
+	_ context.Context,
+	_ string,
+) ([]*domain.ExternalAsset, error) {
+	return []*domain.ExternalAsset{}, nil
+}
+
+// QuoteAssetLastClosePrice quotes the referenced asset with its most recent recorded valuation.
+//
+// Parameters:
+//   - asset: the MANUAL external asset, with the quoted asset id as ticker
+//
+// Returns:
+//   - *domain.ExternalAssetQuote: the most recent valuation as a quote
+//   - error: if the source does not match, the referenced asset does not exist or has no valuation,
+//     or the lookup fails
+func (service *ManualAssetIntegrationService) QuoteAssetLastClosePrice(
+	asset *domain.ExternalAsset,
+) (*domain.ExternalAssetQuote, error) {
+
+	if asset.Source != domain.ManualSource {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"unexpected asset source %s for manual integration service",
+			asset.Source,
+		)
+	}
+
+	assetId, err := strconv.ParseInt(asset.Ticker, 10, 64)
+	if err != nil {
+		return nil, infra.BuildAppErrorFormatted(service, "invalid manual external asset reference %s", asset.Ticker)
+	}
+
+	valuation, err := service.assetRepository.FindLatestAssetValuation(assetId)
+	if err != nil {
+		return nil, err
+	}
+
+	if valuation == nil {
+		return nil, infra.BuildAppErrorFormatted(service, "no valuation recorded for asset %d", assetId)
+	}
+
+	currencyUnit, err := currency.ParseISO(valuation.Currency)
+	if err != nil {
+		return nil, infra.BuildAppErrorFormatted(
+			service,
+			"error parsing valuation currency %s: %v",
+			valuation.Currency,
+			err,
+		)
+	}
+
+	return &domain.ExternalAssetQuote{
+		Source:         domain.ManualSource,
+		Ticker:         asset.Ticker,
+		ExchangeId:     domain.ManualExchangeId,
+		Currency:       currencyUnit,
+		LastCloseQuote: valuation.Price,
+		LastCloseDate:  valuation.ValuationDate,
+	}, nil
+}
+
+// QuoteAssetsLastClosePrice quotes several assets with their most recent recorded valuations, with at
+// most manualQuoteConcurrency database lookups at the same time.
+func (service *ManualAssetIntegrationService) QuoteAssetsLastClosePrice(
+	requestContext context.Context,
+	assets []*domain.ExternalAsset,
+) []*domain.ExternalAssetQuoteResult {
+
+	var quoteAsset = func(_ context.Context, asset *domain.ExternalAsset) (*domain.ExternalAssetQuote, error) {
+		return service.QuoteAssetLastClosePrice(asset)
+	}
+
+	return domain.QuoteExternalAssetsConcurrently(requestContext, assets, manualQuoteConcurrency, quoteAsset)
+}
+
+func BuildManualAssetIntegrationService(assetRepository domain.AssetRepository) *ManualAssetIntegrationService {
+	return &ManualAssetIntegrationService{assetRepository: assetRepository}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+)
+
+// AuditDomService writes the audit trail of the changes, within the transactions of the changes, and
+// queries it.
+//
+// Changes are audited by snapshotting the entity before the change and recording the change after it:
+//
+//	before, err := auditDomService.SnapshotInTransaction(transContext, reference)
+//	// ... change of the entity
+//	err = auditDomService.RecordChangeInTransaction(transContext, reference, before)
+type AuditDomService struct {
+	auditRepository domain.AuditRepository
+}
+
+// SnapshotInTransaction reads the JSON snapshot of an audited entity, or nil when it does not exist yet.
+func (service *AuditDomService) SnapshotInTransaction(
+	transContext context.Context,
+	reference *domain.AuditEntityReference,
+) (json.RawMessage, error) {
+	return service.auditRepository.SnapshotEntityInTransaction(transContext, reference)
+}
+
+// RecordChangeInTransaction appends an entry to the audit trail with the snapshot of the entity taken
+// before its change and a new one taken now. See recordAuditedChangeInTransaction.
+func (service *AuditDomService) RecordChangeInTransaction(
+	transContext context.Context,
+	reference *domain.AuditEntityReference,
+	before json.RawMessage,
+) error {
+	return recordAuditedChangeInTransaction(service.auditRepository, transContext, reference, before)
+}
+
+// GetAuditEntries returns the audit entries matching the filter, newest first.
+func (service *AuditDomService) GetAuditEntries(filter *domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	return service.auditRepository.FindAuditEntries(filter)
+}
+
+// recordAuditedChangeInTransaction appends an entry to the audit trail with the snapshot of the entity
+// taken before its change and a new one taken now, by the actor carried by the transactional context. The
+// action derives from the snapshots, and nothing is recorded when the entity did not change.
+func recordAuditedChangeInTransaction(
+	auditRepository domain.AuditRepository,
+	transContext context.Context,
+	reference *domain.AuditEntityReference,
+	before json.RawMessage,
+) error {
+
+	after, err := auditRepository.SnapshotEntityInTransaction(transContext, reference)
+	if err != nil {
+		return err
+	}
+
+	var action domain.AuditAction
+	switch {
+	case bytes.Equal(before, after):
+		return nil
+	case before == nil:
+		action = domain.CreateAuditAction
+	case after == nil:
+		action = domain.DeleteAuditAction
+	default:
+		action = domain.UpdateAuditAction
+	}
+
+	var entry = &domain.AuditEntry{
+		AuditEntityReference: *reference,
+		Action:               action,
+		Before:               before,
+		After:                after,
+	}
+
+	if actor := domain.AuditActorFromContext(transContext); actor != nil {
+		entry.ActorUserId = &actor.UserId
+		entry.ActorUsername = &actor.Username
+	}
+
+	return auditRepository.InsertAuditEntryInTransaction(transContext, entry)
+}
+
+func BuildAuditDomService(auditRepository domain.AuditRepository) *AuditDomService {
+	return &AuditDomService{auditRepository: auditRepository}
+}
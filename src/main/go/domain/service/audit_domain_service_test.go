@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+)
+
+// inMemoryAuditRepository is a fake repository returning a fixed snapshot and keeping the inserted entries
+// in memory.
+type inMemoryAuditRepository struct {
+	snapshot json.RawMessage
+	entries  []*domain.AuditEntry
+}
+
+func (repository *inMemoryAuditRepository) SnapshotEntityInTransaction(
+	_ context.Context,
+	_ *domain.AuditEntityReference,
+) (json.RawMessage, error) {
+	return repository.snapshot, nil
+}
+
+func (repository *inMemoryAuditRepository) InsertAuditEntryInTransaction(
+	_ context.Context,
+	entry *domain.AuditEntry,
+) error {
+	repository.entries = append(repository.entries, entry)
+	return nil
+}
+
+func (repository *inMemoryAuditRepository) FindAuditEntries(_ *domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	return repository.entries, nil
+}
+
+var testAuditReference = &domain.AuditEntityReference{EntityType: domain.AssetAuditEntityType, EntityId: 1}
+
+func TestRecordAuditedChangeInTransactionActions(t *testing.T) {
+
+	var testCases = []struct {
+		name           string
+		before         json.RawMessage
+		after          json.RawMessage
+		expectedAction domain.AuditAction
+	}{
+		{"creation", nil, json.RawMessage(`{"name":"A"}`), domain.CreateAuditAction},
+		{"update", json.RawMessage(`{"name":"A"}`), json.RawMessage(`{"name":"B"}`), domain.UpdateAuditAction},
+		{"deletion", json.RawMessage(`{"name":"A"}`), nil, domain.DeleteAuditAction},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			var repository = &inMemoryAuditRepository{snapshot: testCase.after}
+
+			err := recordAuditedChangeInTransaction(repository, context.Background(), testAuditReference, testCase.before)
+			require.NoError(t, err)
+
+			require.Len(t, repository.entries, 1)
+			var entry = repository.entries[0]
+			assert.Equal(t, testCase.expectedAction, entry.Action)
+			assert.Equal(t, *testAuditReference, entry.AuditEntityReference)
+			assert.Equal(t, testCase.before, entry.Before)
+			assert.Equal(t, testCase.after, entry.After)
+			assert.Nil(t, entry.ActorUserId)
+			assert.Nil(t, entry.ActorUsername)
+		})
+	}
+}
+
+func TestRecordAuditedChangeInTransactionSkipsUnchangedEntity(t *testing.T) {
+
+	var repository = &inMemoryAuditRepository{snapshot: json.RawMessage(`{"name":"A"}`)}
+
+	err := recordAuditedChangeInTransaction(
+		repository,
+		context.Background(),
+		testAuditReference,
+		json.RawMessage(`{"name":"A"}`),
+	)
+	require.NoError(t, err)
+
+	assert.Empty(t, repository.entries)
+}
+
+func TestRecordAuditedChangeInTransactionRecordsContextActor(t *testing.T) {
+
+	var repository = &inMemoryAuditRepository{snapshot: json.RawMessage(`{"name":"B"}`)}
+	var actorContext = domain.WithAuditActor(
+		context.Background(),
+		&domain.AuditActor{UserId: 7, Username: "auditor"},
+	)
+
+	err := recordAuditedChangeInTransaction(
+		repository,
+		actorContext,
+		testAuditReference,
+		json.RawMessage(`{"name":"A"}`),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, repository.entries, 1)
+	require.NotNil(t, repository.entries[0].ActorUserId)
+	assert.Equal(t, int64(7), *repository.entries[0].ActorUserId)
+	assert.Equal(t, "auditor", *repository.entries[0].ActorUsername)
+}
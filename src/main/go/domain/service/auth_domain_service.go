@@ -0,0 +1,313 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const (
+	generatedAuthTokenBytes = 32
+	apiTokenPrefix          = "oaa_"
+	maxUsernameLength       = 100
+	maxAPITokenNameLength   = 100
+	minPasswordLength       = 8
+	// bcrypt only hashes the first 72 bytes of a password
+	maxPasswordBytes = 72
+)
+
+// unknownUserPasswordHash is compared on logins of unknown usernames, so they take as long as the logins
+// of existing users and do not reveal which usernames exist.
+var unknownUserPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("unknown user password"), bcrypt.DefaultCost)
+
+type AuthDomService struct {
+	authRepository domain.AuthRepository
+	config         infra.AuthConfiguration
+}
+
+// EnsureBootstrapUser creates the configured bootstrap user when there are no users yet, so the first
+// login is possible. It does nothing when no bootstrap user is configured.
+func (service *AuthDomService) EnsureBootstrapUser() error {
+
+	if service.config.BootstrapUsername == "" {
+		return nil
+	}
+
+	var usersCount, err = service.authRepository.CountUsers()
+	if err != nil || usersCount > 0 {
+		return err
+	}
+
+	_, err = service.CreateUser(service.config.BootstrapUsername, service.config.BootstrapPassword)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Bootstrap user created", "username", service.config.BootstrapUsername)
+	return nil
+}
+
+// CreateUser validates and persists a new local user, hashing its password with bcrypt.
+//
+// Returns:
+//   - *domain.User: the persisted user
+//   - error: a DomainValidationError when the username is blank, too long or taken, or the password is
+//     not valid
+func (service *AuthDomService) CreateUser(username string, password string) (*domain.User, error) {
+
+	username = strings.TrimSpace(username)
+	if username == "" || len(username) > maxUsernameLength {
+		return nil, infra.BuildDomainValidationError("Username must have between 1 and 100 characters", nil)
+	}
+
+	if err := validatePassword(password); err != nil {
+		return nil, err
+	}
+
+	existingUser, err := service.authRepository.FindUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if existingUser != nil {
+		return nil, infra.BuildDomainValidationError("Username "+username+" is already taken", nil)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error hashing user password", service)
+	}
+
+	return service.authRepository.InsertUser(&domain.User{Username: username, PasswordHash: string(passwordHash)})
+}
+
+// ChangePassword replaces the password of a user after checking the current one, ending all the sessions
+// of the user.
+//
+// Returns:
+//   - error: a DomainValidationError when the current password is wrong or the new one is not valid
+func (service *AuthDomService) ChangePassword(userId int64, currentPassword string, newPassword string) error {
+
+	var user, err = service.authRepository.FindUser(userId)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || !passwordMatches(user.PasswordHash, currentPassword) {
+		return infra.BuildDomainValidationError("Current password is incorrect", nil)
+	}
+
+	if err = validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return infra.PropagateAsAppErrorWithNewMessage(err, "Error hashing user password", service)
+	}
+
+	if err = service.authRepository.UpdateUserPassword(userId, string(passwordHash)); err != nil {
+		return err
+	}
+
+	return service.authRepository.DeleteUserSessions(userId)
+}
+
+// Login checks the credentials of a user and starts a web UI session, purging the expired sessions.
+//
+// Returns:
+//   - *domain.User: the logged user, or nil when the credentials are invalid
+//   - string: the session token to keep in the session cookie
+//   - time.Time: the expiration of the session
+//   - error: when the session cannot be persisted
+func (service *AuthDomService) Login(username string, password string) (*domain.User, string, time.Time, error) {
+
+	var user, err = service.authRepository.FindUserByUsername(strings.TrimSpace(username))
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+
+	if user == nil {
+		passwordMatches(string(unknownUserPasswordHash), password)
+		return nil, "", time.Time{}, nil
+	}
+
+	if !passwordMatches(user.PasswordHash, password) {
+		return nil, "", time.Time{}, nil
+	}
+
+	var now = time.Now()
+	if err = service.authRepository.DeleteExpiredSessions(now); err != nil {
+		return nil, "", time.Time{}, err
+	}
+
+	sessionToken, err := generateAuthToken()
+	if err != nil {
+		return nil, "", time.Time{}, infra.PropagateAsAppErrorWithNewMessage(
+			err,
+			"Error generating session token",
+			service,
+		)
+	}
+
+	var session = &domain.UserSession{
+		UserId:    user.Id,
+		TokenHash: HashAuthToken(sessionToken),
+		ExpiresAt: now.Add(service.config.SessionTTL),
+	}
+	if err = service.authRepository.InsertSession(session); err != nil {
+		return nil, "", time.Time{}, err
+	}
+
+	return user, sessionToken, session.ExpiresAt, nil
+}
+
+func (service *AuthDomService) Logout(sessionToken string) error {
+	return service.authRepository.DeleteSession(HashAuthToken(sessionToken))
+}
+
+// AuthenticateSession resolves the principal of a session token, or nil when the session does not exist or
+// is expired.
+func (service *AuthDomService) AuthenticateSession(sessionToken string) (*domain.Principal, error) {
+
+	var user, err = service.authRepository.FindActiveSessionUser(HashAuthToken(sessionToken), time.Now())
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	return &domain.Principal{User: user}, nil
+}
+
+// AuthenticateAPIToken resolves the principal of an API token, or nil when the token does not exist, is
+// revoked or is expired, and records its use.
+func (service *AuthDomService) AuthenticateAPIToken(rawToken string) (*domain.Principal, error) {
+
+	if !strings.HasPrefix(rawToken, apiTokenPrefix) {
+		return nil, nil
+	}
+
+	var now = time.Now()
+	var token, err = service.authRepository.FindActiveAPIToken(HashAuthToken(rawToken), now)
+	if err != nil || token == nil {
+		return nil, err
+	}
+
+	user, err := service.authRepository.FindUser(token.UserId)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	if err = service.authRepository.UpdateAPITokenLastUsed(token.Id, now); err != nil {
+		return nil, err
+	}
+	token.LastUsedAt = &now
+
+	return &domain.Principal{User: user, APIToken: token}, nil
+}
+
+// CreateAPIToken validates and persists a new personal API token of a user. The token itself is only
+// returned here, as only its hash is persisted.
+//
+// Returns:
+//   - *domain.APIToken: the persisted token
+//   - string: the token to send in the Authorization header of the API requests
+//   - error: a DomainValidationError when the name, scopes or expiration are not valid
+func (service *AuthDomService) CreateAPIToken(
+	userId int64,
+	name string,
+	scopes []domain.APITokenScope,
+	expiresAt *time.Time,
+) (*domain.APIToken, string, error) {
+
+	var token = &domain.APIToken{UserId: userId, Name: strings.TrimSpace(name), ExpiresAt: expiresAt}
+	if err := validateAPIToken(token, scopes); err != nil {
+		return nil, "", err
+	}
+
+	for _, scope := range scopes {
+		if !token.HasScope(scope) {
+			token.Scopes = append(token.Scopes, scope)
+		}
+	}
+
+	var rawTokenSuffix, err = generateAuthToken()
+	if err != nil {
+		return nil, "", infra.PropagateAsAppErrorWithNewMessage(err, "Error generating API token", service)
+	}
+	var rawToken = apiTokenPrefix + rawTokenSuffix
+	token.TokenHash = HashAuthToken(rawToken)
+
+	persistedToken, err := service.authRepository.InsertAPIToken(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return persistedToken, rawToken, nil
+}
+
+func (service *AuthDomService) GetUserAPITokens(userId int64) ([]*domain.APIToken, error) {
+	return service.authRepository.FindUserAPITokens(userId)
+}
+
+func (service *AuthDomService) RevokeAPIToken(userId int64, tokenId int64) (bool, error) {
+	return service.authRepository.RevokeAPIToken(userId, tokenId, time.Now())
+}
+
+// HashAuthToken computes the hex encoded SHA-256 hash under which a session or API token is persisted.
+func HashAuthToken(rawToken string) string {
+	var hash = sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(hash[:])
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordBytes {
+		return infra.BuildDomainValidationError("Password must have between 8 and 72 bytes", nil)
+	}
+	return nil
+}
+
+func validateAPIToken(token *domain.APIToken, scopes []domain.APITokenScope) error {
+
+	if token.Name == "" || len(token.Name) > maxAPITokenNameLength {
+		return infra.BuildDomainValidationError("API token name must have between 1 and 100 characters", nil)
+	}
+
+	if len(scopes) == 0 {
+		return infra.BuildDomainValidationError("API token requires at least one scope", nil)
+	}
+
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return infra.BuildDomainValidationError("Invalid API token scope "+string(scope), nil)
+		}
+	}
+
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()) {
+		return infra.BuildDomainValidationError("API token expiration must be in the future", nil)
+	}
+
+	return nil
+}
+
+func passwordMatches(passwordHash string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) == nil
+}
+
+func generateAuthToken() (string, error) {
+	var tokenBytes = make([]byte, generatedAuthTokenBytes)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+func BuildAuthDomService(authRepository domain.AuthRepository, config infra.AuthConfiguration) *AuthDomService {
+	return &AuthDomService{authRepository: authRepository, config: config}
+}
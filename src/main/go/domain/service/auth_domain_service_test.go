@@ -0,0 +1,325 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// inMemoryAuthRepository is a fake repository keeping the users, sessions and API tokens in memory.
+type inMemoryAuthRepository struct {
+	users     []*domain.User
+	sessions  []*domain.UserSession
+	apiTokens []*domain.APIToken
+}
+
+func (repository *inMemoryAuthRepository) CountUsers() (int64, error) {
+	return int64(len(repository.users)), nil
+}
+
+func (repository *inMemoryAuthRepository) FindUser(userId int64) (*domain.User, error) {
+	for _, user := range repository.users {
+		if user.Id == userId {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repository *inMemoryAuthRepository) FindUserByUsername(username string) (*domain.User, error) {
+	for _, user := range repository.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repository *inMemoryAuthRepository) InsertUser(user *domain.User) (*domain.User, error) {
+	user.Id = int64(len(repository.users) + 1)
+	repository.users = append(repository.users, user)
+	return user, nil
+}
+
+func (repository *inMemoryAuthRepository) UpdateUserPassword(userId int64, passwordHash string) error {
+	var user, _ = repository.FindUser(userId)
+	user.PasswordHash = passwordHash
+	return nil
+}
+
+func (repository *inMemoryAuthRepository) InsertSession(session *domain.UserSession) error {
+	session.Id = int64(len(repository.sessions) + 1)
+	repository.sessions = append(repository.sessions, session)
+	return nil
+}
+
+func (repository *inMemoryAuthRepository) FindActiveSessionUser(tokenHash string, now time.Time) (*domain.User, error) {
+	for _, session := range repository.sessions {
+		if session.TokenHash == tokenHash && session.ExpiresAt.After(now) {
+			return repository.FindUser(session.UserId)
+		}
+	}
+	return nil, nil
+}
+
+func (repository *inMemoryAuthRepository) DeleteSession(tokenHash string) error {
+	return repository.deleteSessions(func(session *domain.UserSession) bool { return session.TokenHash == tokenHash })
+}
+
+func (repository *inMemoryAuthRepository) DeleteUserSessions(userId int64) error {
+	return repository.deleteSessions(func(session *domain.UserSession) bool { return session.UserId == userId })
+}
+
+func (repository *inMemoryAuthRepository) DeleteExpiredSessions(now time.Time) error {
+	return repository.deleteSessions(func(session *domain.UserSession) bool { return !session.ExpiresAt.After(now) })
+}
+
+func (repository *inMemoryAuthRepository) deleteSessions(matches func(*domain.UserSession) bool) error {
+	var keptSessions = make([]*domain.UserSession, 0, len(repository.sessions))
+	for _, session := range repository.sessions {
+		if !matches(session) {
+			keptSessions = append(keptSessions, session)
+		}
+	}
+	repository.sessions = keptSessions
+	return nil
+}
+
+func (repository *inMemoryAuthRepository) InsertAPIToken(token *domain.APIToken) (*domain.APIToken, error) {
+	token.Id = int64(len(repository.apiTokens) + 1)
+	repository.apiTokens = append(repository.apiTokens, token)
+	return token, nil
+}
+
+func (repository *inMemoryAuthRepository) FindUserAPITokens(userId int64) ([]*domain.APIToken, error) {
+	var userTokens = make([]*domain.APIToken, 0)
+	for _, token := range repository.apiTokens {
+		if token.UserId == userId {
+			userTokens = append(userTokens, token)
+		}
+	}
+	return userTokens, nil
+}
+
+func (repository *inMemoryAuthRepository) FindActiveAPIToken(tokenHash string, now time.Time) (*domain.APIToken, error) {
+	for _, token := range repository.apiTokens {
+		if token.TokenHash == tokenHash && token.RevokedAt == nil &&
+			(token.ExpiresAt == nil || token.ExpiresAt.After(now)) {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repository *inMemoryAuthRepository) UpdateAPITokenLastUsed(tokenId int64, lastUsedAt time.Time) error {
+	repository.apiTokens[tokenId-1].LastUsedAt = &lastUsedAt
+	return nil
+}
+
+func (repository *inMemoryAuthRepository) RevokeAPIToken(userId int64, tokenId int64, revokedAt time.Time) (bool, error) {
+	for _, token := range repository.apiTokens {
+		if token.Id == tokenId && token.UserId == userId && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+var testAuthConfig = infra.AuthConfiguration{
+	Mode:              infra.AuthLocalMode,
+	SessionTTL:        time.Hour,
+	BootstrapUsername: "admin",
+	BootstrapPassword: "bootstrap-password",
+}
+
+func buildTestAuthDomService(t *testing.T) (*AuthDomService, *inMemoryAuthRepository) {
+	t.Helper()
+
+	var repository = &inMemoryAuthRepository{}
+	var service = BuildAuthDomService(repository, testAuthConfig)
+	require.NoError(t, service.EnsureBootstrapUser())
+
+	return service, repository
+}
+
+func TestAuthDomServiceEnsureBootstrapUserOnlyWithoutUsers(t *testing.T) {
+
+	var service, repository = buildTestAuthDomService(t)
+
+	require.Len(t, repository.users, 1)
+	assert.Equal(t, "admin", repository.users[0].Username)
+	assert.NotEqual(t, "bootstrap-password", repository.users[0].PasswordHash)
+
+	require.NoError(t, service.EnsureBootstrapUser())
+	assert.Len(t, repository.users, 1)
+}
+
+func TestAuthDomServiceLoginAndSessionAuthentication(t *testing.T) {
+
+	var service, repository = buildTestAuthDomService(t)
+
+	user, sessionToken, _, err := service.Login("admin", "wrong-password")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+	assert.Empty(t, sessionToken)
+
+	user, sessionToken, _, err = service.Login("unknown", "bootstrap-password")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+
+	user, sessionToken, expiresAt, err := service.Login("admin", "bootstrap-password")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.NotEmpty(t, sessionToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+	require.Len(t, repository.sessions, 1)
+	assert.Equal(t, HashAuthToken(sessionToken), repository.sessions[0].TokenHash)
+
+	principal, err := service.AuthenticateSession(sessionToken)
+	require.NoError(t, err)
+	require.NotNil(t, principal)
+	assert.True(t, principal.IsSession())
+	assert.True(t, principal.CanWrite())
+
+	require.NoError(t, service.Logout(sessionToken))
+	principal, err = service.AuthenticateSession(sessionToken)
+	require.NoError(t, err)
+	assert.Nil(t, principal)
+}
+
+func TestAuthDomServiceChangePasswordEndsSessions(t *testing.T) {
+
+	var service, repository = buildTestAuthDomService(t)
+	var _, sessionToken, _, err = service.Login("admin", "bootstrap-password")
+	require.NoError(t, err)
+
+	err = service.ChangePassword(1, "wrong-password", "new-password")
+	assert.ErrorContains(t, err, "Current password is incorrect")
+
+	err = service.ChangePassword(1, "bootstrap-password", "short")
+	assert.ErrorContains(t, err, "Password must have between 8 and 72 bytes")
+
+	require.NoError(t, service.ChangePassword(1, "bootstrap-password", "new-password"))
+	assert.Empty(t, repository.sessions)
+
+	principal, err := service.AuthenticateSession(sessionToken)
+	require.NoError(t, err)
+	assert.Nil(t, principal)
+
+	user, _, _, err := service.Login("admin", "new-password")
+	require.NoError(t, err)
+	assert.NotNil(t, user)
+}
+
+func TestAuthDomServiceCreateUserValidations(t *testing.T) {
+
+	var service, _ = buildTestAuthDomService(t)
+
+	_, err := service.CreateUser("admin", "another-password")
+	assert.ErrorContains(t, err, "Username admin is already taken")
+
+	_, err = service.CreateUser(" ", "another-password")
+	assert.ErrorContains(t, err, "Username must have between 1 and 100 characters")
+
+	_, err = service.CreateUser("other", strings.Repeat("x", 73))
+	assert.ErrorContains(t, err, "Password must have between 8 and 72 bytes")
+
+	user, err := service.CreateUser(" other ", "another-password")
+	require.NoError(t, err)
+	assert.Equal(t, "other", user.Username)
+}
+
+func TestAuthDomServiceAPITokenScopesAndRevocation(t *testing.T) {
+
+	var service, repository = buildTestAuthDomService(t)
+
+	token, rawToken, err := service.CreateAPIToken(
+		1,
+		"reporting script",
+		[]domain.APITokenScope{domain.ReadAPITokenScope, domain.ReadAPITokenScope},
+		nil,
+	)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rawToken, "oaa_"))
+	assert.Equal(t, []domain.APITokenScope{domain.ReadAPITokenScope}, token.Scopes)
+	assert.Equal(t, HashAuthToken(rawToken), repository.apiTokens[0].TokenHash)
+
+	principal, err := service.AuthenticateAPIToken(rawToken)
+	require.NoError(t, err)
+	require.NotNil(t, principal)
+	assert.False(t, principal.IsSession())
+	assert.False(t, principal.CanWrite())
+	assert.NotNil(t, principal.APIToken.LastUsedAt)
+
+	revoked, err := service.RevokeAPIToken(1, token.Id)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	principal, err = service.AuthenticateAPIToken(rawToken)
+	require.NoError(t, err)
+	assert.Nil(t, principal)
+
+	_, writeToken, err := service.CreateAPIToken(1, "import script", []domain.APITokenScope{"WRITE"}, nil)
+	require.NoError(t, err)
+	principal, err = service.AuthenticateAPIToken(writeToken)
+	require.NoError(t, err)
+	require.NotNil(t, principal)
+	assert.True(t, principal.CanWrite())
+}
+
+func TestAuthDomServiceCreateAPITokenValidations(t *testing.T) {
+
+	var service, _ = buildTestAuthDomService(t)
+	var past = time.Now().Add(-time.Hour)
+
+	var testCases = []struct {
+		name            string
+		tokenName       string
+		scopes          []domain.APITokenScope
+		expiresAt       *time.Time
+		expectedMessage string
+	}{
+		{
+			name:            "blank name",
+			tokenName:       " ",
+			scopes:          []domain.APITokenScope{domain.ReadAPITokenScope},
+			expectedMessage: "API token name must have between 1 and 100 characters",
+		},
+		{
+			name:            "no scopes",
+			tokenName:       "script",
+			expectedMessage: "API token requires at least one scope",
+		},
+		{
+			name:            "invalid scope",
+			tokenName:       "script",
+			scopes:          []domain.APITokenScope{"ADMIN"},
+			expectedMessage: "Invalid API token scope ADMIN",
+		},
+		{
+			name:            "expiration in the past",
+			tokenName:       "script",
+			scopes:          []domain.APITokenScope{domain.ReadAPITokenScope},
+			expiresAt:       &past,
+			expectedMessage: "API token expiration must be in the future",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				_, _, err := service.CreateAPIToken(1, testCase.tokenName, testCase.scopes, testCase.expiresAt)
+				var validationError *infra.DomainValidationError
+				require.ErrorAs(t, err, &validationError)
+				assert.Equal(t, testCase.expectedMessage, validationError.Message)
+			},
+		)
+	}
+}
@@ -0,0 +1,514 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+type CorporateActionDomService struct {
+	corporateActionRepository domain.CorporateActionRepository
+	assetRepository           domain.AssetRepository
+}
+
+func (service *CorporateActionDomService) GetAssetCorporateActions(assetId int64) ([]*domain.CorporateAction, error) {
+	return service.corporateActionRepository.FindAssetCorporateActions(assetId)
+}
+
+// GetAssetCorporateAction retrieves a corporate action of an asset, or nil when the action does not
+// exist or belongs to another asset.
+func (service *CorporateActionDomService) GetAssetCorporateAction(
+	assetId int64,
+	corporateActionId int64,
+) (*domain.CorporateAction, error) {
+
+	action, err := service.corporateActionRepository.FindCorporateAction(corporateActionId)
+	if err != nil || action == nil || action.AssetId != assetId {
+		return nil, err
+	}
+
+	return action, nil
+}
+
+// InsertCorporateAction validates and persists a new pending corporate action. Merger targets must be
+// existing assets.
+func (service *CorporateActionDomService) InsertCorporateAction(
+	action *domain.CorporateAction,
+) (*domain.CorporateAction, error) {
+
+	if err := action.Validate(); err != nil {
+		return nil, err
+	}
+
+	if action.ActionType == domain.MergerCorporateActionType {
+		if _, err := service.getActionAsset(action.TargetAssetId); err != nil {
+			return nil, err
+		}
+	}
+
+	return service.corporateActionRepository.InsertCorporateAction(action)
+}
+
+// DeleteCorporateAction removes a corporate action of an asset, which must not be applied.
+//
+// Returns:
+//   - bool: false when the action does not exist or belongs to another asset
+//   - error: a DomainValidationError when the action is applied, or the persistence error
+func (service *CorporateActionDomService) DeleteCorporateAction(assetId int64, corporateActionId int64) (bool, error) {
+
+	action, err := service.GetAssetCorporateAction(assetId, corporateActionId)
+	if err != nil || action == nil {
+		return false, err
+	}
+
+	if action.Status == domain.AppliedCorporateActionStatus {
+		return false, infra.BuildDomainValidationError(
+			fmt.Sprintf("Corporate action %d must be reverted before being deleted", action.Id),
+			nil,
+		)
+	}
+
+	return true, service.corporateActionRepository.DeleteCorporateAction(action)
+}
+
+// ApplyCorporateActionInTransaction applies a pending or reverted corporate action within an existing
+// SQL transaction. The portfolio allocations observed from the effective date on are converted to the
+// share basis after the action, and moved to the target asset on mergers. Planned allocations of plans
+// not executed before the effective date follow the new ticker or target asset, and the previous ticker
+// is kept as an alias. Everything changed is recorded in the action reversal data.
+//
+// Returns:
+//   - *domain.CorporateAction: the applied action
+//   - error: a DomainValidationError when the action cannot be applied, or the persistence error
+func (service *CorporateActionDomService) ApplyCorporateActionInTransaction(
+	transContext context.Context,
+	corporateActionId int64,
+) (*domain.CorporateAction, error) {
+
+	action, err := service.findCorporateActionForUpdate(transContext, corporateActionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = action.ValidateApplicable(); err != nil {
+		return nil, err
+	}
+
+	asset, err := service.getActionAssetForUpdate(transContext, action.AssetId)
+	if err != nil {
+		return nil, err
+	}
+
+	var reversal = &domain.CorporateActionReversal{PreviousTicker: asset.Ticker}
+
+	if action.AffectsPortfolioAllocations() {
+		err = service.adjustPortfolioAllocationsInTransaction(transContext, action, reversal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch action.ActionType {
+	case domain.SymbolChangeCorporateActionType:
+		err = service.applySymbolChangeInTransaction(transContext, action, asset, reversal)
+	case domain.MergerCorporateActionType:
+		err = service.applyMergerInTransaction(transContext, action, asset, reversal)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var appliedAt = time.Now()
+	action.Status = domain.AppliedCorporateActionStatus
+	action.AppliedAt = &appliedAt
+	action.Reversal = reversal
+
+	err = service.corporateActionRepository.UpdateCorporateActionStatusInTransaction(transContext, action)
+	if err != nil {
+		return nil, err
+	}
+
+	return action, nil
+}
+
+func (service *CorporateActionDomService) adjustPortfolioAllocationsInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+	reversal *domain.CorporateActionReversal,
+) error {
+
+	portfolioAllocations, err := service.corporateActionRepository.FindLaterPortfolioAllocationsInTransaction(
+		transContext,
+		action,
+	)
+	if err != nil {
+		return err
+	}
+	reversal.PortfolioAllocations = portfolioAllocations
+
+	return service.corporateActionRepository.AdjustLaterPortfolioAllocationsInTransaction(transContext, action)
+}
+
+// applySymbolChangeInTransaction renames the asset, keeping the previous ticker as an alias valid up to
+// the day before the effective date.
+func (service *CorporateActionDomService) applySymbolChangeInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+	asset *domain.Asset,
+	reversal *domain.CorporateActionReversal,
+) error {
+
+	existingAssets, err := service.assetRepository.FindAssetsByTickersInTransaction(
+		transContext,
+		[]string{action.NewTicker},
+	)
+	if err != nil {
+		return err
+	}
+	for _, existingAsset := range existingAssets {
+		if existingAsset.Id != asset.Id && existingAsset.Ticker == action.NewTicker {
+			return infra.BuildDomainValidationError(
+				fmt.Sprintf("Ticker %s already identifies another asset", action.NewTicker),
+				nil,
+			)
+		}
+	}
+
+	var renamedAsset = domain.Asset{Id: asset.Id, Ticker: action.NewTicker}
+	err = service.reassignPlannedAllocationsInTransaction(transContext, action, asset.Ticker, &renamedAsset, reversal)
+	if err != nil {
+		return err
+	}
+
+	err = service.assetRepository.UpdateAssetTickerInTransaction(transContext, asset.Id, action.NewTicker)
+	if err != nil {
+		return err
+	}
+
+	var validTo = action.EffectiveDate.AddDate(0, 0, -1)
+	return service.insertReversibleAliasInTransaction(
+		transContext,
+		&domain.AssetAlias{AssetId: asset.Id, Ticker: asset.Ticker, ValidTo: &validTo},
+		reversal,
+	)
+}
+
+// applyMergerInTransaction moves the later planned allocations of the merged asset to the target asset
+// and makes the merged asset ticker an alias of the target from the effective date on. Cash terms are
+// recorded in the action only.
+func (service *CorporateActionDomService) applyMergerInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+	asset *domain.Asset,
+	reversal *domain.CorporateActionReversal,
+) error {
+
+	targetAsset, err := service.getActionAssetForUpdate(transContext, action.TargetAssetId)
+	if err != nil {
+		return err
+	}
+
+	err = service.reassignPlannedAllocationsInTransaction(transContext, action, asset.Ticker, targetAsset, reversal)
+	if err != nil {
+		return err
+	}
+
+	var validFrom = action.EffectiveDate
+	return service.insertReversibleAliasInTransaction(
+		transContext,
+		&domain.AssetAlias{AssetId: targetAsset.Id, Ticker: asset.Ticker, ValidFrom: &validFrom},
+		reversal,
+	)
+}
+
+func (service *CorporateActionDomService) reassignPlannedAllocationsInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+	previousTicker string,
+	newAsset *domain.Asset,
+	reversal *domain.CorporateActionReversal,
+) error {
+
+	plannedAllocations, err := service.corporateActionRepository.FindLaterPlannedAllocationsInTransaction(
+		transContext,
+		action,
+	)
+	if err != nil {
+		return err
+	}
+	reversal.PlannedAllocations = plannedAllocations
+
+	return service.corporateActionRepository.ReassignLaterPlannedAllocationsInTransaction(
+		transContext,
+		action,
+		previousTicker,
+		newAsset,
+	)
+}
+
+func (service *CorporateActionDomService) insertReversibleAliasInTransaction(
+	transContext context.Context,
+	alias *domain.AssetAlias,
+	reversal *domain.CorporateActionReversal,
+) error {
+
+	persistedAlias, err := service.assetRepository.InsertAssetAliasInTransaction(transContext, alias)
+	if err != nil {
+		return err
+	}
+
+	reversal.CreatedAliasId = persistedAlias.Id
+	return nil
+}
+
+// RevertCorporateActionInTransaction reverts an applied corporate action within an existing SQL
+// transaction, restoring the portfolio allocations, planned allocations, ticker and aliases recorded
+// when it was applied. Only the last applied action of its assets can be reverted.
+//
+// Returns:
+//   - *domain.CorporateAction: the reverted action
+//   - error: a DomainValidationError when the action cannot be reverted, or the persistence error
+func (service *CorporateActionDomService) RevertCorporateActionInTransaction(
+	transContext context.Context,
+	corporateActionId int64,
+) (*domain.CorporateAction, error) {
+
+	action, err := service.findCorporateActionForUpdate(transContext, corporateActionId)
+	if err != nil {
+		return nil, err
+	}
+
+	laterAppliedActions, err := service.corporateActionRepository.FindCorporateActionsAppliedAfterInTransaction(
+		transContext,
+		action,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = action.ValidateRevertible(laterAppliedActions); err != nil {
+		return nil, err
+	}
+
+	var reversal = action.Reversal
+
+	err = service.corporateActionRepository.RestorePortfolioAllocationsInTransaction(
+		transContext,
+		action,
+		reversal.PortfolioAllocations,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.corporateActionRepository.RestorePlannedAllocationsInTransaction(
+		transContext,
+		reversal.PlannedAllocations,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if action.ActionType == domain.SymbolChangeCorporateActionType {
+		err = service.assetRepository.UpdateAssetTickerInTransaction(
+			transContext,
+			action.AssetId,
+			reversal.PreviousTicker,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if reversal.CreatedAliasId != 0 {
+		// an alias already deleted since the application is left as is
+		_, err = service.assetRepository.DeleteAssetAliasInTransaction(
+			transContext,
+			action.AssetId,
+			reversal.CreatedAliasId,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	action.Status = domain.RevertedCorporateActionStatus
+	action.AppliedAt = nil
+	action.Reversal = nil
+
+	err = service.corporateActionRepository.UpdateCorporateActionStatusInTransaction(transContext, action)
+	if err != nil {
+		return nil, err
+	}
+
+	return action, nil
+}
+
+// FindChangedEntityReferencesInTransaction returns, within an existing SQL transaction, the references of
+// the audited entities changed by applying a corporate action or, when it is applied, by reverting it: its
+// assets, whose ticker or aliases change, the allocations of the portfolio observations adjusted and the
+// allocation plans reassigned. Plans reassigned by actions applied before their reversal data kept the
+// plans are left out.
+func (service *CorporateActionDomService) FindChangedEntityReferencesInTransaction(
+	transContext context.Context,
+	corporateActionId int64,
+) ([]*domain.AuditEntityReference, error) {
+
+	action, err := service.findCorporateActionForUpdate(transContext, corporateActionId)
+	if err != nil {
+		return nil, err
+	}
+
+	var portfolioAllocations []*domain.CorporateActionPortfolioAllocationSnapshot
+	var plannedAllocations []*domain.CorporateActionPlannedAllocationSnapshot
+
+	if action.Status == domain.AppliedCorporateActionStatus && action.Reversal != nil {
+		portfolioAllocations = action.Reversal.PortfolioAllocations
+		plannedAllocations = action.Reversal.PlannedAllocations
+	} else {
+		portfolioAllocations, plannedAllocations, err = service.findLaterAllocationsInTransaction(
+			transContext,
+			action,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var references = []*domain.AuditEntityReference{
+		{EntityType: domain.AssetAuditEntityType, EntityId: action.AssetId},
+	}
+	if action.ActionType == domain.MergerCorporateActionType {
+		references = append(
+			references,
+			&domain.AuditEntityReference{EntityType: domain.AssetAuditEntityType, EntityId: action.TargetAssetId},
+		)
+	}
+
+	var observationKeys = make(map[[2]int64]bool)
+	for _, portfolioAllocation := range portfolioAllocations {
+		var key = [2]int64{portfolioAllocation.PortfolioId, portfolioAllocation.ObservationTimeId}
+		if observationKeys[key] {
+			continue
+		}
+		observationKeys[key] = true
+		references = append(references, &domain.AuditEntityReference{
+			EntityType:  domain.PortfolioAllocationAuditEntityType,
+			EntityId:    portfolioAllocation.ObservationTimeId,
+			PortfolioId: &portfolioAllocation.PortfolioId,
+		})
+	}
+
+	var planIds = make(map[int64]bool)
+	for _, plannedAllocation := range plannedAllocations {
+		if plannedAllocation.AllocationPlanId == 0 || planIds[plannedAllocation.AllocationPlanId] {
+			continue
+		}
+		planIds[plannedAllocation.AllocationPlanId] = true
+		references = append(references, &domain.AuditEntityReference{
+			EntityType:  domain.AllocationPlanAuditEntityType,
+			EntityId:    plannedAllocation.AllocationPlanId,
+			PortfolioId: &plannedAllocation.PortfolioId,
+		})
+	}
+
+	return references, nil
+}
+
+func (service *CorporateActionDomService) findLaterAllocationsInTransaction(
+	transContext context.Context,
+	action *domain.CorporateAction,
+) (
+	[]*domain.CorporateActionPortfolioAllocationSnapshot,
+	[]*domain.CorporateActionPlannedAllocationSnapshot,
+	error,
+) {
+
+	var portfolioAllocations []*domain.CorporateActionPortfolioAllocationSnapshot
+	if action.AffectsPortfolioAllocations() {
+		var err error
+		portfolioAllocations, err = service.corporateActionRepository.FindLaterPortfolioAllocationsInTransaction(
+			transContext,
+			action,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var plannedAllocations []*domain.CorporateActionPlannedAllocationSnapshot
+	if action.ActionType == domain.SymbolChangeCorporateActionType ||
+		action.ActionType == domain.MergerCorporateActionType {
+		var err error
+		plannedAllocations, err = service.corporateActionRepository.FindLaterPlannedAllocationsInTransaction(
+			transContext,
+			action,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return portfolioAllocations, plannedAllocations, nil
+}
+
+func (service *CorporateActionDomService) findCorporateActionForUpdate(
+	transContext context.Context,
+	corporateActionId int64,
+) (*domain.CorporateAction, error) {
+
+	action, err := service.corporateActionRepository.FindCorporateActionForUpdateInTransaction(
+		transContext,
+		corporateActionId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if action == nil {
+		return nil, infra.BuildAppErrorFormatted(service, "Corporate action with id %d not found", corporateActionId)
+	}
+
+	return action, nil
+}
+
+func (service *CorporateActionDomService) getActionAsset(assetId int64) (*domain.Asset, error) {
+	asset, err := service.assetRepository.FindAssetById(assetId)
+	return validateActionAssetFound(assetId, asset, err)
+}
+
+// getActionAssetForUpdate reads an asset of an action within the transaction applying it, locking the asset
+// so its ticker does not change until the action is applied.
+func (service *CorporateActionDomService) getActionAssetForUpdate(
+	transContext context.Context,
+	assetId int64,
+) (*domain.Asset, error) {
+	asset, err := service.assetRepository.FindAssetForUpdateInTransaction(transContext, assetId)
+	return validateActionAssetFound(assetId, asset, err)
+}
+
+func validateActionAssetFound(assetId int64, asset *domain.Asset, err error) (*domain.Asset, error) {
+
+	if err != nil {
+		return nil, err
+	}
+
+	if asset == nil {
+		return nil, infra.BuildDomainValidationError(fmt.Sprintf("Asset %d not found", assetId), nil)
+	}
+
+	return asset, nil
+}
+
+func BuildCorporateActionDomService(
+	corporateActionRepository domain.CorporateActionRepository,
+	assetRepository domain.AssetRepository,
+) *CorporateActionDomService {
+	return &CorporateActionDomService{
+		corporateActionRepository: corporateActionRepository,
+		assetRepository:           assetRepository,
+	}
+}
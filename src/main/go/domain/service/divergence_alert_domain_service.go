@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+var maxAlertThresholdPercent = decimal.NewFromInt(100)
+
+type DivergenceAlertDomService struct {
+	divergenceAlertRepository domain.DivergenceAlertRepository
+	allocationPlanRepository  domain.AllocationPlanRepository
+}
+
+func (service *DivergenceAlertDomService) GetPortfolioAlertRules(
+	portfolioId int64,
+) ([]*domain.DivergenceAlertRule, error) {
+	return service.divergenceAlertRepository.FindPortfolioAlertRules(portfolioId)
+}
+
+// GetPortfolioAlertRule retrieves an alert rule of a portfolio, or nil when it does not exist.
+func (service *DivergenceAlertDomService) GetPortfolioAlertRule(
+	portfolioId int64,
+	ruleId int64,
+) (*domain.DivergenceAlertRule, error) {
+	return service.divergenceAlertRepository.FindPortfolioAlertRule(portfolioId, ruleId)
+}
+
+func (service *DivergenceAlertDomService) GetEnabledPortfolioAlertRules(
+	portfolioId int64,
+) ([]*domain.DivergenceAlertRule, error) {
+	return service.divergenceAlertRepository.FindEnabledPortfolioAlertRules(portfolioId)
+}
+
+// InsertAlertRule validates and persists a new alert rule.
+//
+// Returns:
+//   - *domain.DivergenceAlertRule: the persisted rule
+//   - error: a DomainValidationError when the rule is not valid or its allocation plan is not one of the
+//     portfolio
+func (service *DivergenceAlertDomService) InsertAlertRule(
+	rule *domain.DivergenceAlertRule,
+) (*domain.DivergenceAlertRule, error) {
+
+	if err := service.validateAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	return service.divergenceAlertRepository.InsertAlertRule(rule)
+}
+
+// UpdateAlertRule validates and replaces the definition of an alert rule. Its open alerts are kept, and
+// evaluated against the new definition from the next evaluation on.
+//
+// Returns:
+//   - *domain.DivergenceAlertRule: the updated rule, or nil when it does not exist
+//   - error: a DomainValidationError when the rule is not valid or its allocation plan is not one of the
+//     portfolio
+func (service *DivergenceAlertDomService) UpdateAlertRule(
+	rule *domain.DivergenceAlertRule,
+) (*domain.DivergenceAlertRule, error) {
+
+	if err := service.validateAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	return service.divergenceAlertRepository.UpdateAlertRule(rule)
+}
+
+func (service *DivergenceAlertDomService) DeleteAlertRule(portfolioId int64, ruleId int64) (bool, error) {
+	return service.divergenceAlertRepository.DeleteAlertRule(portfolioId, ruleId)
+}
+
+// EvaluateAlertRuleInTransaction applies the breaches of a rule in a divergence analysis to its alerts
+// within an existing SQL transaction. Breaches without an open alert trigger a new one, open alerts still
+// breached are refreshed and open alerts no longer breached are resolved.
+//
+// Returns:
+//   - []*domain.DivergenceAlert: the alerts triggered by the evaluation
+//   - error: when the alerts cannot be read or persisted
+func (service *DivergenceAlertDomService) EvaluateAlertRuleInTransaction(
+	transContext context.Context,
+	rule *domain.DivergenceAlertRule,
+	analysis *domain.DivergenceAnalysis,
+	evaluatedAt time.Time,
+) ([]*domain.DivergenceAlert, error) {
+
+	var openAlerts, err = service.divergenceAlertRepository.FindOpenRuleAlertsInTransaction(transContext, rule.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var openAlertsPerHierarchicalId = make(map[string]*domain.DivergenceAlert, len(openAlerts))
+	for _, openAlert := range openAlerts {
+		openAlertsPerHierarchicalId[alertKey(openAlert.HierarchicalId)] = openAlert
+	}
+
+	var triggeredAlerts = make([]*domain.DivergenceAlert, 0)
+	for _, breach := range rule.Evaluate(analysis) {
+
+		var key = alertKey(breach.HierarchicalId)
+		var openAlert = openAlertsPerHierarchicalId[key]
+
+		if openAlert != nil {
+			delete(openAlertsPerHierarchicalId, key)
+			openAlert.ObservedPercent = breach.ObservedPercent
+			openAlert.ObservationTimestampId = analysis.ObservationTimestamp.Id
+			openAlert.LastEvaluatedAt = evaluatedAt
+			err = service.divergenceAlertRepository.UpdateAlertEvaluationInTransaction(transContext, openAlert)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var triggeredAlert = &domain.DivergenceAlert{
+			RuleId:                 rule.Id,
+			RuleName:               rule.Name,
+			RuleType:               rule.RuleType,
+			PortfolioId:            rule.PortfolioId,
+			HierarchicalId:         breach.HierarchicalId,
+			Status:                 domain.TriggeredDivergenceAlertStatus,
+			ThresholdPercent:       rule.ThresholdPercent,
+			ObservedPercent:        breach.ObservedPercent,
+			ObservationTimestampId: analysis.ObservationTimestamp.Id,
+			TriggeredAt:            evaluatedAt,
+			LastEvaluatedAt:        evaluatedAt,
+		}
+		err = service.divergenceAlertRepository.InsertAlertInTransaction(transContext, triggeredAlert)
+		if err != nil {
+			return nil, err
+		}
+		triggeredAlerts = append(triggeredAlerts, triggeredAlert)
+	}
+
+	for _, recoveredAlert := range openAlertsPerHierarchicalId {
+		recoveredAlert.Status = domain.ResolvedDivergenceAlertStatus
+		recoveredAlert.ObservationTimestampId = analysis.ObservationTimestamp.Id
+		recoveredAlert.LastEvaluatedAt = evaluatedAt
+		recoveredAlert.ResolvedAt = &evaluatedAt
+		err = service.divergenceAlertRepository.UpdateAlertEvaluationInTransaction(transContext, recoveredAlert)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return triggeredAlerts, nil
+}
+
+func (service *DivergenceAlertDomService) GetPortfolioAlerts(
+	portfolioId int64,
+	status *domain.DivergenceAlertStatus,
+	limit int,
+) ([]*domain.DivergenceAlert, error) {
+	return service.divergenceAlertRepository.FindPortfolioAlerts(portfolioId, status, limit)
+}
+
+// GetPortfolioAlert retrieves an alert of a portfolio, or nil when it does not exist.
+func (service *DivergenceAlertDomService) GetPortfolioAlert(
+	portfolioId int64,
+	alertId int64,
+) (*domain.DivergenceAlert, error) {
+	return service.divergenceAlertRepository.FindPortfolioAlert(portfolioId, alertId)
+}
+
+// AcknowledgeAlert marks a triggered alert as seen by the user. The alert stays open until resolved.
+// Acknowledging an acknowledged alert changes nothing.
+//
+// Returns:
+//   - *domain.DivergenceAlert: the acknowledged alert, or nil when it does not exist
+//   - error: a DomainValidationError when the alert is already resolved
+func (service *DivergenceAlertDomService) AcknowledgeAlert(
+	portfolioId int64,
+	alertId int64,
+) (*domain.DivergenceAlert, error) {
+
+	var alert, err = service.divergenceAlertRepository.FindPortfolioAlert(portfolioId, alertId)
+	if err != nil || alert == nil {
+		return nil, err
+	}
+
+	switch alert.Status {
+	case domain.AcknowledgedDivergenceAlertStatus:
+		return alert, nil
+	case domain.ResolvedDivergenceAlertStatus:
+		return nil, infra.BuildDomainValidationError(
+			"Divergence alert is resolved and cannot be acknowledged",
+			nil,
+		)
+	}
+
+	var now = time.Now()
+	alert.Status = domain.AcknowledgedDivergenceAlertStatus
+	alert.AcknowledgedAt = &now
+
+	return service.divergenceAlertRepository.UpdateAlertStatus(alert)
+}
+
+// ResolveAlert closes an open alert. A later evaluation still breaching its rule triggers a new alert.
+//
+// Returns:
+//   - *domain.DivergenceAlert: the resolved alert, or nil when it does not exist
+//   - error: a DomainValidationError when the alert is already resolved
+func (service *DivergenceAlertDomService) ResolveAlert(
+	portfolioId int64,
+	alertId int64,
+) (*domain.DivergenceAlert, error) {
+
+	var alert, err = service.divergenceAlertRepository.FindPortfolioAlert(portfolioId, alertId)
+	if err != nil || alert == nil {
+		return nil, err
+	}
+
+	if !alert.IsOpen() {
+		return nil, infra.BuildDomainValidationError("Divergence alert is already resolved", nil)
+	}
+
+	var now = time.Now()
+	alert.Status = domain.ResolvedDivergenceAlertStatus
+	alert.ResolvedAt = &now
+
+	return service.divergenceAlertRepository.UpdateAlertStatus(alert)
+}
+
+func (service *DivergenceAlertDomService) validateAlertRule(rule *domain.DivergenceAlertRule) error {
+
+	if err := validateAlertRuleDefinition(rule); err != nil {
+		return err
+	}
+
+	var planIdentifiers, err = service.allocationPlanRepository.GetAllAllocationPlanIdentifiers(rule.PortfolioId, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, planIdentifier := range planIdentifiers {
+		if planIdentifier.Id == rule.AllocationPlanId {
+			return nil
+		}
+	}
+
+	return infra.BuildDomainValidationError(
+		fmt.Sprintf("Allocation plan %d is not a plan of the portfolio", rule.AllocationPlanId),
+		nil,
+	)
+}
+
+func validateAlertRuleDefinition(rule *domain.DivergenceAlertRule) error {
+
+	if !rule.RuleType.IsValid() {
+		return infra.BuildDomainValidationError("Invalid divergence alert rule type "+string(rule.RuleType), nil)
+	}
+
+	if rule.ThresholdPercent.IsNegative() || rule.ThresholdPercent.GreaterThan(maxAlertThresholdPercent) {
+		return infra.BuildDomainValidationError(
+			"Divergence alert rule thresholdPercent must be between 0 and 100",
+			nil,
+		)
+	}
+
+	if rule.HierarchicalId != nil {
+
+		if rule.RuleType != domain.DriftAboveAlertRuleType {
+			return infra.BuildDomainValidationError(
+				"Divergence alert rule hierarchicalId only applies to DRIFT_ABOVE rules",
+				nil,
+			)
+		}
+
+		if strings.TrimSpace(*rule.HierarchicalId) == "" {
+			return infra.BuildDomainValidationError("Divergence alert rule hierarchicalId must not be blank", nil)
+		}
+	}
+
+	return nil
+}
+
+// alertKey identifies the open alert of a rule for a hierarchical id, the empty key standing for the rules
+// on the whole portfolio.
+func alertKey(hierarchicalId *string) string {
+	if hierarchicalId == nil {
+		return ""
+	}
+	return *hierarchicalId
+}
+
+func BuildDivergenceAlertDomService(
+	divergenceAlertRepository domain.DivergenceAlertRepository,
+	allocationPlanRepository domain.AllocationPlanRepository,
+) *DivergenceAlertDomService {
+	return &DivergenceAlertDomService{
+		divergenceAlertRepository: divergenceAlertRepository,
+		allocationPlanRepository:  allocationPlanRepository,
+	}
+}
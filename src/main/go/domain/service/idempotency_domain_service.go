@@ -0,0 +1,108 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyDomService struct {
+	idempotencyRepository domain.IdempotencyRepository
+	config                infra.IdempotencyConfiguration
+	now                   func() time.Time
+}
+
+// BeginIdempotentRequest claims the idempotency key of a request in the scope of its caller, for the
+// configured window, or finds the request previously sent with the key. Expired requests are removed
+// first, so their keys can be sent again.
+//
+// Returns:
+//   - *domain.IdempotentRequest: the claimed request, to be completed or released after being processed,
+//     or the previous request sent with the key, not to be processed again
+//   - bool: whether the key was claimed
+//   - error: a DomainValidationError when the key is blank or longer than 255 characters
+func (service *IdempotencyDomService) BeginIdempotentRequest(
+	scope string,
+	key string,
+	fingerprint string,
+) (*domain.IdempotentRequest, bool, error) {
+
+	if strings.TrimSpace(key) == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, false, infra.BuildDomainValidationError(
+			"Idempotency key must have between 1 and 255 characters",
+			nil,
+		)
+	}
+
+	var now = service.now()
+	if err := service.idempotencyRepository.DeleteExpiredIdempotentRequests(now); err != nil {
+		return nil, false, err
+	}
+
+	var request = &domain.IdempotentRequest{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(service.config.KeyTTL),
+	}
+
+	claimed, err := service.idempotencyRepository.ClaimIdempotentRequest(request)
+	if err != nil || claimed {
+		return request, claimed, err
+	}
+
+	previousRequest, err := service.idempotencyRepository.FindIdempotentRequest(scope, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the previous request was released after the claim attempt, so the key is free again
+	if previousRequest == nil {
+		claimed, err = service.idempotencyRepository.ClaimIdempotentRequest(request)
+		if err != nil || claimed {
+			return request, claimed, err
+		}
+		return nil, false, infra.BuildAppError("Idempotency key is concurrently being claimed", service)
+	}
+
+	return previousRequest, false, nil
+}
+
+// CompleteIdempotentRequest stores the response of a claimed request, to be replayed to its duplicates until
+// the key expires.
+func (service *IdempotencyDomService) CompleteIdempotentRequest(
+	request *domain.IdempotentRequest,
+	response *domain.IdempotentResponse,
+) error {
+
+	var err = service.idempotencyRepository.CompleteIdempotentRequest(request.Scope, request.Key, response)
+	if err != nil {
+		return err
+	}
+
+	request.Status = domain.CompletedIdempotentRequestStatus
+	request.Response = response
+	return nil
+}
+
+// ReleaseIdempotentRequest frees the key of a claimed request that could not be processed, so it can be
+// retried with the same key.
+func (service *IdempotencyDomService) ReleaseIdempotentRequest(request *domain.IdempotentRequest) error {
+	return service.idempotencyRepository.DeleteIdempotentRequest(request.Scope, request.Key)
+}
+
+func BuildIdempotencyDomService(
+	idempotencyRepository domain.IdempotencyRepository,
+	config infra.IdempotencyConfiguration,
+) *IdempotencyDomService {
+	return &IdempotencyDomService{
+		idempotencyRepository: idempotencyRepository,
+		config:                config,
+		now:                   time.Now,
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// inMemoryIdempotencyRepository is a fake repository keeping the idempotent requests in memory, by scope
+// and key.
+type inMemoryIdempotencyRepository struct {
+	requests map[string]*domain.IdempotentRequest
+}
+
+func idempotentRequestMapKey(scope string, key string) string {
+	return scope + "|" + key
+}
+
+func (repository *inMemoryIdempotencyRepository) ClaimIdempotentRequest(request *domain.IdempotentRequest) (bool, error) {
+
+	var mapKey = idempotentRequestMapKey(request.Scope, request.Key)
+	if storedRequest, ok := repository.requests[mapKey]; ok && storedRequest.ExpiresAt.After(request.CreatedAt) {
+		return false, nil
+	}
+
+	request.Status = domain.ProcessingIdempotentRequestStatus
+	var storedRequest = *request
+	repository.requests[mapKey] = &storedRequest
+	return true, nil
+}
+
+func (repository *inMemoryIdempotencyRepository) FindIdempotentRequest(
+	scope string,
+	key string,
+) (*domain.IdempotentRequest, error) {
+	return repository.requests[idempotentRequestMapKey(scope, key)], nil
+}
+
+func (repository *inMemoryIdempotencyRepository) CompleteIdempotentRequest(
+	scope string,
+	key string,
+	response *domain.IdempotentResponse,
+) error {
+	var storedRequest = repository.requests[idempotentRequestMapKey(scope, key)]
+	storedRequest.Status = domain.CompletedIdempotentRequestStatus
+	storedRequest.Response = response
+	return nil
+}
+
+func (repository *inMemoryIdempotencyRepository) DeleteIdempotentRequest(scope string, key string) error {
+	delete(repository.requests, idempotentRequestMapKey(scope, key))
+	return nil
+}
+
+func (repository *inMemoryIdempotencyRepository) DeleteExpiredIdempotentRequests(now time.Time) error {
+	for mapKey, request := range repository.requests {
+		if !request.ExpiresAt.After(now) {
+			delete(repository.requests, mapKey)
+		}
+	}
+	return nil
+}
+
+func buildTestIdempotencyDomService(now *time.Time) *IdempotencyDomService {
+	var repository = &inMemoryIdempotencyRepository{requests: make(map[string]*domain.IdempotentRequest)}
+	var service = BuildIdempotencyDomService(repository, infra.IdempotencyConfiguration{KeyTTL: time.Hour})
+	service.now = func() time.Time { return *now }
+	return service
+}
+
+func TestIdempotencyDomServiceReplaysCompletedRequest(t *testing.T) {
+
+	var now = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	var service = buildTestIdempotencyDomService(&now)
+
+	request, claimed, err := service.BeginIdempotentRequest("1", "key-1", "fingerprint")
+	require.NoError(t, err)
+	require.True(t, claimed)
+	assert.Equal(t, now.Add(time.Hour), request.ExpiresAt)
+
+	duplicate, claimed, err := service.BeginIdempotentRequest("1", "key-1", "fingerprint")
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	assert.False(t, duplicate.IsCompleted())
+
+	var response = &domain.IdempotentResponse{StatusCode: 201, Body: []byte(`{"id":1}`)}
+	require.NoError(t, service.CompleteIdempotentRequest(request, response))
+
+	duplicate, claimed, err = service.BeginIdempotentRequest("1", "key-1", "fingerprint")
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	require.True(t, duplicate.IsCompleted())
+	assert.Equal(t, response, duplicate.Response)
+
+	_, claimed, err = service.BeginIdempotentRequest("2", "key-1", "fingerprint")
+	require.NoError(t, err)
+	assert.True(t, claimed, "keys are unique per scope")
+}
+
+func TestIdempotencyDomServiceReclaimsExpiredOrReleasedKey(t *testing.T) {
+
+	var now = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	var service = buildTestIdempotencyDomService(&now)
+
+	request, claimed, err := service.BeginIdempotentRequest("1", "key-1", "fingerprint")
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	require.NoError(t, service.ReleaseIdempotentRequest(request))
+	request, claimed, err = service.BeginIdempotentRequest("1", "key-1", "fingerprint")
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.NoError(t, service.CompleteIdempotentRequest(request, &domain.IdempotentResponse{StatusCode: 204}))
+
+	now = now.Add(time.Hour)
+	_, claimed, err = service.BeginIdempotentRequest("1", "key-1", "other fingerprint")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestIdempotencyDomServiceRejectsInvalidKey(t *testing.T) {
+
+	var now = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	var service = buildTestIdempotencyDomService(&now)
+
+	for _, key := range []string{" ", string(make([]byte, maxIdempotencyKeyLength+1))} {
+		_, _, err := service.BeginIdempotentRequest("1", key, "fingerprint")
+		var validationErr *infra.DomainValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	}
+}
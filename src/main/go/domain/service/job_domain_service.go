@@ -0,0 +1,141 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+type JobHandlersPerType map[domain.JobType]domain.JobHandler
+
+type JobDomService struct {
+	jobRepository      domain.JobRepository
+	jobHandlersPerType JobHandlersPerType
+}
+
+func (service *JobDomService) GetJobs(filter *domain.JobFilter) ([]*domain.Job, error) {
+	return service.jobRepository.FindJobs(filter)
+}
+
+// GetJob retrieves a job by id, or nil when it does not exist.
+func (service *JobDomService) GetJob(id int64) (*domain.Job, error) {
+	return service.jobRepository.FindJob(id)
+}
+
+// GetJobHandler returns the handler running the jobs of the type, or false when the type has none.
+func (service *JobDomService) GetJobHandler(jobType domain.JobType) (domain.JobHandler, bool) {
+	var handler, exists = service.jobHandlersPerType[jobType]
+	return handler, exists
+}
+
+// InsertJob validates the parameters of a new job through the handler of its type and persists it as
+// pending, created by the user, or without creator when the user id is zero.
+//
+// Returns:
+//   - *domain.Job: the persisted job
+//   - error: a DomainValidationError when the job type has no handler or the parameters are not valid
+func (service *JobDomService) InsertJob(
+	jobType domain.JobType,
+	params json.RawMessage,
+	createdByUserId int64,
+) (*domain.Job, error) {
+
+	var handler, exists = service.GetJobHandler(jobType)
+	if !exists {
+		return nil, infra.BuildDomainValidationError(fmt.Sprintf("Invalid job type %s", jobType), nil)
+	}
+
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+
+	if err := handler.ValidateParams(params); err != nil {
+		return nil, err
+	}
+
+	return service.jobRepository.InsertJob(
+		&domain.Job{JobType: jobType, Params: params, CreatedByUserId: createdByUserId},
+	)
+}
+
+// StartJob moves a pending job to running.
+//
+// Returns:
+//   - *domain.Job: the started job, or nil when the job is not pending anymore
+//   - error: the persistence error
+func (service *JobDomService) StartJob(id int64) (*domain.Job, error) {
+	return service.jobRepository.StartJob(id)
+}
+
+func (service *JobDomService) UpdateJobProgress(id int64, completed int, total int) error {
+	return service.jobRepository.UpdateJobProgress(id, completed, total)
+}
+
+// SucceedJob records the successful end of a job with its result, marshalled to JSON.
+func (service *JobDomService) SucceedJob(job *domain.Job, result any) (*domain.Job, error) {
+
+	var resultJSON, err = json.Marshal(result)
+	if err != nil {
+		return service.FailJob(job, fmt.Errorf("error marshalling job result: %w", err))
+	}
+
+	return service.finishJob(job, domain.SucceededJobStatus, resultJSON, "")
+}
+
+// FailJob records the failure of a job with the error message.
+func (service *JobDomService) FailJob(job *domain.Job, cause error) (*domain.Job, error) {
+	return service.finishJob(job, domain.FailedJobStatus, nil, cause.Error())
+}
+
+// CancelJob records the cancellation of a job that is not finished.
+func (service *JobDomService) CancelJob(job *domain.Job) (*domain.Job, error) {
+	return service.finishJob(job, domain.CancelledJobStatus, nil, "")
+}
+
+// finishJob records the final status of a job. When the job was finished meanwhile, it is returned as
+// persisted.
+func (service *JobDomService) finishJob(
+	job *domain.Job,
+	status domain.JobStatus,
+	result json.RawMessage,
+	errorMessage string,
+) (*domain.Job, error) {
+
+	var finishedJob = *job
+	finishedJob.Status = status
+	finishedJob.Result = result
+	finishedJob.ErrorMessage = errorMessage
+
+	persistedJob, err := service.jobRepository.FinishJob(&finishedJob)
+	if err != nil || persistedJob != nil {
+		return persistedJob, err
+	}
+
+	return service.jobRepository.FindJob(job.Id)
+}
+
+// GetJobsToResume moves the jobs left running by a previous execution back to pending and returns
+// every pending job, the oldest first, to be run again.
+func (service *JobDomService) GetJobsToResume() ([]*domain.Job, error) {
+
+	if _, err := service.jobRepository.RequeueRunningJobs(); err != nil {
+		return nil, err
+	}
+
+	var pendingJobs, err = service.jobRepository.FindJobs(
+		&domain.JobFilter{Status: domain.PendingJobStatus, Limit: -1},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(pendingJobs)
+	return pendingJobs, nil
+}
+
+func BuildJobDomService(jobRepository domain.JobRepository, jobHandlersPerType JobHandlersPerType) *JobDomService {
+	return &JobDomService{jobRepository: jobRepository, jobHandlersPerType: jobHandlersPerType}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const shareLinkTokenPrefix = "oaas_"
+
+// PortfolioAccessDomService authorizes the access of principals to portfolios and manages the access grants
+// and read-only share links of the portfolios.
+type PortfolioAccessDomService struct {
+	portfolioAccessRepository domain.PortfolioAccessRepository
+	authRepository            domain.AuthRepository
+}
+
+// GetPrincipalPortfolioRole resolves the role of a principal on a portfolio. Share links are VIEWER of their
+// own portfolio only. The role is empty when the principal has no access.
+func (service *PortfolioAccessDomService) GetPrincipalPortfolioRole(
+	principal *domain.Principal,
+	portfolioId int64,
+) (domain.PortfolioRole, error) {
+
+	if principal.IsShareLink() {
+		if principal.ShareLink.PortfolioId == portfolioId {
+			return domain.ViewerPortfolioRole, nil
+		}
+		return "", nil
+	}
+
+	return service.portfolioAccessRepository.FindUserPortfolioRole(portfolioId, principal.User.Id)
+}
+
+// AssignUnownedPortfolios makes the oldest user the owner of the portfolios created while authentication
+// was disabled, returning how many were assigned.
+func (service *PortfolioAccessDomService) AssignUnownedPortfolios() (int64, error) {
+	return service.portfolioAccessRepository.AssignUnownedPortfolios()
+}
+
+func (service *PortfolioAccessDomService) GetPortfolioGrants(portfolioId int64) ([]*domain.PortfolioGrant, error) {
+	return service.portfolioAccessRepository.FindPortfolioGrants(portfolioId)
+}
+
+// GrantPortfolioAccess gives the user with a username an EDITOR or VIEWER role on a portfolio, replacing
+// the role previously granted to them.
+func (service *PortfolioAccessDomService) GrantPortfolioAccess(
+	portfolioId int64,
+	username string,
+	role domain.PortfolioRole,
+) (*domain.PortfolioGrant, error) {
+
+	if role == domain.OwnerPortfolioRole {
+		return nil, infra.BuildDomainValidationError("The OWNER role cannot be granted", nil)
+	}
+	if !role.IsValid() {
+		return nil, infra.BuildDomainValidationError(fmt.Sprintf("Invalid portfolio role %s", role), nil)
+	}
+
+	username = strings.TrimSpace(username)
+	user, err := service.authRepository.FindUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, infra.BuildDomainValidationError(fmt.Sprintf("User %s does not exist", username), nil)
+	}
+
+	currentRole, err := service.portfolioAccessRepository.FindUserPortfolioRole(portfolioId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if currentRole == domain.OwnerPortfolioRole {
+		return nil, infra.BuildDomainValidationError("The owner of a portfolio cannot be granted another role", nil)
+	}
+
+	return service.portfolioAccessRepository.UpsertPortfolioGrant(
+		&domain.PortfolioGrant{PortfolioId: portfolioId, UserId: user.Id, Role: role},
+	)
+}
+
+// RevokePortfolioAccess removes the access granted to a user on a portfolio, returning false when there
+// was none.
+func (service *PortfolioAccessDomService) RevokePortfolioAccess(portfolioId int64, userId int64) (bool, error) {
+	return service.portfolioAccessRepository.DeletePortfolioGrant(portfolioId, userId)
+}
+
+// CreateShareLink persists a new read-only share link of a portfolio, valid until expiresAt. The token of
+// the link is only returned here, as only its hash is persisted.
+//
+// Returns:
+//   - *domain.PortfolioShareLink: the persisted share link
+//   - string: the token to send in the X-Share-Token header or shareToken query parameter of the requests
+//   - error: a validation error when expiresAt is not in the future, or any persistence error
+func (service *PortfolioAccessDomService) CreateShareLink(
+	portfolioId int64,
+	createdByUserId int64,
+	expiresAt time.Time,
+) (*domain.PortfolioShareLink, string, error) {
+
+	if !expiresAt.After(time.Now()) {
+		return nil, "", infra.BuildDomainValidationError("Share link expiration must be in the future", nil)
+	}
+
+	var rawTokenSuffix, err = generateAuthToken()
+	if err != nil {
+		return nil, "", infra.PropagateAsAppErrorWithNewMessage(err, "Error generating share link token", service)
+	}
+	var rawToken = shareLinkTokenPrefix + rawTokenSuffix
+
+	shareLink, err := service.portfolioAccessRepository.InsertShareLink(
+		&domain.PortfolioShareLink{
+			PortfolioId:     portfolioId,
+			TokenHash:       HashAuthToken(rawToken),
+			CreatedByUserId: createdByUserId,
+			ExpiresAt:       expiresAt,
+		},
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return shareLink, rawToken, nil
+}
+
+func (service *PortfolioAccessDomService) GetPortfolioShareLinks(
+	portfolioId int64,
+) ([]*domain.PortfolioShareLink, error) {
+	return service.portfolioAccessRepository.FindPortfolioShareLinks(portfolioId)
+}
+
+func (service *PortfolioAccessDomService) RevokeShareLink(portfolioId int64, shareLinkId int64) (bool, error) {
+	return service.portfolioAccessRepository.RevokeShareLink(portfolioId, shareLinkId, time.Now())
+}
+
+// AuthenticateShareLink resolves the anonymous principal of a share link token, or nil when the link does
+// not exist, is revoked or is expired.
+func (service *PortfolioAccessDomService) AuthenticateShareLink(rawToken string) (*domain.Principal, error) {
+
+	if !strings.HasPrefix(rawToken, shareLinkTokenPrefix) {
+		return nil, nil
+	}
+
+	var shareLink, err = service.portfolioAccessRepository.FindActiveShareLink(HashAuthToken(rawToken), time.Now())
+	if err != nil || shareLink == nil {
+		return nil, err
+	}
+
+	return &domain.Principal{ShareLink: shareLink}, nil
+}
+
+func BuildPortfolioAccessDomService(
+	portfolioAccessRepository domain.PortfolioAccessRepository,
+	authRepository domain.AuthRepository,
+) *PortfolioAccessDomService {
+	return &PortfolioAccessDomService{
+		portfolioAccessRepository: portfolioAccessRepository,
+		authRepository:            authRepository,
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// inMemoryPortfolioAccessRepository is a fake repository keeping the portfolio owners, grants and share
+// links in memory.
+type inMemoryPortfolioAccessRepository struct {
+	portfolioOwners map[int64]int64
+	grants          []*domain.PortfolioGrant
+	shareLinks      []*domain.PortfolioShareLink
+}
+
+func (repository *inMemoryPortfolioAccessRepository) FindUserPortfolioRole(
+	portfolioId int64,
+	userId int64,
+) (domain.PortfolioRole, error) {
+	if repository.portfolioOwners[portfolioId] == userId {
+		return domain.OwnerPortfolioRole, nil
+	}
+	for _, grant := range repository.grants {
+		if grant.PortfolioId == portfolioId && grant.UserId == userId {
+			return grant.Role, nil
+		}
+	}
+	return "", nil
+}
+
+func (repository *inMemoryPortfolioAccessRepository) AssignUnownedPortfolios() (int64, error) {
+	return 0, nil
+}
+
+func (repository *inMemoryPortfolioAccessRepository) FindPortfolioGrants(
+	portfolioId int64,
+) ([]*domain.PortfolioGrant, error) {
+	var portfolioGrants = make([]*domain.PortfolioGrant, 0)
+	for _, grant := range repository.grants {
+		if grant.PortfolioId == portfolioId {
+			portfolioGrants = append(portfolioGrants, grant)
+		}
+	}
+	return portfolioGrants, nil
+}
+
+func (repository *inMemoryPortfolioAccessRepository) UpsertPortfolioGrant(
+	grant *domain.PortfolioGrant,
+) (*domain.PortfolioGrant, error) {
+	for _, existingGrant := range repository.grants {
+		if existingGrant.PortfolioId == grant.PortfolioId && existingGrant.UserId == grant.UserId {
+			existingGrant.Role = grant.Role
+			return existingGrant, nil
+		}
+	}
+	repository.grants = append(repository.grants, grant)
+	return grant, nil
+}
+
+func (repository *inMemoryPortfolioAccessRepository) DeletePortfolioGrant(
+	portfolioId int64,
+	userId int64,
+) (bool, error) {
+	for i, grant := range repository.grants {
+		if grant.PortfolioId == portfolioId && grant.UserId == userId {
+			repository.grants = append(repository.grants[:i], repository.grants[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (repository *inMemoryPortfolioAccessRepository) InsertShareLink(
+	shareLink *domain.PortfolioShareLink,
+) (*domain.PortfolioShareLink, error) {
+	shareLink.Id = int64(len(repository.shareLinks) + 1)
+	repository.shareLinks = append(repository.shareLinks, shareLink)
+	return shareLink, nil
+}
+
+func (repository *inMemoryPortfolioAccessRepository) FindPortfolioShareLinks(
+	portfolioId int64,
+) ([]*domain.PortfolioShareLink, error) {
+	var portfolioShareLinks = make([]*domain.PortfolioShareLink, 0)
+	for _, shareLink := range repository.shareLinks {
+		if shareLink.PortfolioId == portfolioId {
+			portfolioShareLinks = append(portfolioShareLinks, shareLink)
+		}
+	}
+	return portfolioShareLinks, nil
+}
+
+func (repository *inMemoryPortfolioAccessRepository) FindActiveShareLink(
+	tokenHash string,
+	now time.Time,
+) (*domain.PortfolioShareLink, error) {
+	for _, shareLink := range repository.shareLinks {
+		if shareLink.TokenHash == tokenHash && shareLink.RevokedAt == nil && shareLink.ExpiresAt.After(now) {
+			return shareLink, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repository *inMemoryPortfolioAccessRepository) RevokeShareLink(
+	portfolioId int64,
+	shareLinkId int64,
+	revokedAt time.Time,
+) (bool, error) {
+	for _, shareLink := range repository.shareLinks {
+		if shareLink.Id == shareLinkId && shareLink.PortfolioId == portfolioId && shareLink.RevokedAt == nil {
+			shareLink.RevokedAt = &revokedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// buildTestPortfolioAccessDomService builds the service with the bootstrap user "admin" (id 1) owning the
+// portfolio 1 and a second user "advisor" (id 2).
+func buildTestPortfolioAccessDomService(t *testing.T) *PortfolioAccessDomService {
+	t.Helper()
+
+	var authDomService, authRepository = buildTestAuthDomService(t)
+	_, err := authDomService.CreateUser("advisor", "advisor-password")
+	require.NoError(t, err)
+
+	var accessRepository = &inMemoryPortfolioAccessRepository{portfolioOwners: map[int64]int64{1: 1}}
+	return BuildPortfolioAccessDomService(accessRepository, authRepository)
+}
+
+func TestPortfolioAccessDomServiceGrantPortfolioAccess(t *testing.T) {
+
+	var service = buildTestPortfolioAccessDomService(t)
+	var advisorPrincipal = &domain.Principal{User: &domain.User{Id: 2}}
+
+	role, err := service.GetPrincipalPortfolioRole(advisorPrincipal, 1)
+	require.NoError(t, err)
+	assert.Empty(t, role)
+
+	grant, err := service.GrantPortfolioAccess(1, " advisor ", domain.ViewerPortfolioRole)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), grant.UserId)
+
+	role, err = service.GetPrincipalPortfolioRole(advisorPrincipal, 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ViewerPortfolioRole, role)
+
+	_, err = service.GrantPortfolioAccess(1, "advisor", domain.EditorPortfolioRole)
+	require.NoError(t, err)
+	role, err = service.GetPrincipalPortfolioRole(advisorPrincipal, 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EditorPortfolioRole, role)
+
+	revoked, err := service.RevokePortfolioAccess(1, 2)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	role, err = service.GetPrincipalPortfolioRole(advisorPrincipal, 1)
+	require.NoError(t, err)
+	assert.Empty(t, role)
+}
+
+func TestPortfolioAccessDomServiceGrantPortfolioAccessValidations(t *testing.T) {
+
+	var service = buildTestPortfolioAccessDomService(t)
+
+	var testCases = []struct {
+		name            string
+		username        string
+		role            domain.PortfolioRole
+		expectedMessage string
+	}{
+		{
+			name:            "owner role",
+			username:        "advisor",
+			role:            domain.OwnerPortfolioRole,
+			expectedMessage: "The OWNER role cannot be granted",
+		},
+		{
+			name:            "invalid role",
+			username:        "advisor",
+			role:            "ADMIN",
+			expectedMessage: "Invalid portfolio role ADMIN",
+		},
+		{
+			name:            "unknown user",
+			username:        "unknown",
+			role:            domain.ViewerPortfolioRole,
+			expectedMessage: "User unknown does not exist",
+		},
+		{
+			name:            "owner",
+			username:        "admin",
+			role:            domain.ViewerPortfolioRole,
+			expectedMessage: "The owner of a portfolio cannot be granted another role",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				_, err := service.GrantPortfolioAccess(1, testCase.username, testCase.role)
+				var validationError *infra.DomainValidationError
+				require.ErrorAs(t, err, &validationError)
+				assert.Equal(t, testCase.expectedMessage, validationError.Message)
+			},
+		)
+	}
+}
+
+func TestPortfolioAccessDomServiceShareLinks(t *testing.T) {
+
+	var service = buildTestPortfolioAccessDomService(t)
+
+	_, _, err := service.CreateShareLink(1, 1, time.Now().Add(-time.Hour))
+	assert.ErrorContains(t, err, "Share link expiration must be in the future")
+
+	shareLink, rawToken, err := service.CreateShareLink(1, 1, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, HashAuthToken(rawToken), shareLink.TokenHash)
+
+	principal, err := service.AuthenticateShareLink(rawToken)
+	require.NoError(t, err)
+	require.NotNil(t, principal)
+	assert.False(t, principal.CanWrite())
+
+	role, err := service.GetPrincipalPortfolioRole(principal, 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ViewerPortfolioRole, role)
+
+	role, err = service.GetPrincipalPortfolioRole(principal, 2)
+	require.NoError(t, err)
+	assert.Empty(t, role)
+
+	principal, err = service.AuthenticateShareLink("oaa_" + rawToken)
+	require.NoError(t, err)
+	assert.Nil(t, principal)
+
+	revoked, err := service.RevokeShareLink(1, shareLink.Id)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	principal, err = service.AuthenticateShareLink(rawToken)
+	require.NoError(t, err)
+	assert.Nil(t, principal)
+}
@@ -23,10 +23,12 @@ func (service *PortfolioAllocationDomService) GetPortfolioAllocationHistory(port
 }
 
 func (service *PortfolioAllocationDomService) FindPortfolioAllocationsByObservationTimestamp(
+	queryContext context.Context,
 	id int64,
 	observationTimestampId int64,
 ) ([]*domain.PortfolioAllocation, error) {
 	return service.portfolioAllocationRepository.FindPortfolioAllocationsByObservationTimestamp(
+		queryContext,
 		id,
 		observationTimestampId,
 	)
@@ -92,6 +94,33 @@ func (service *PortfolioAllocationDomService) getHierarchyLevelFieldValue(
 	return extractorFunction(allocation), nil
 }
 
+// GetPortfolioSnapshotVersion returns the version of the allocations of a portfolio in an observation, or 0
+// when the portfolio has no allocations in the observation.
+func (service *PortfolioAllocationDomService) GetPortfolioSnapshotVersion(
+	portfolioId int64,
+	observationTimestampId int64,
+) (int64, error) {
+	return service.portfolioAllocationRepository.FindPortfolioSnapshotVersion(portfolioId, observationTimestampId)
+}
+
+// IncrementPortfolioSnapshotVersionInTransaction increments the version of the allocations of a portfolio in
+// an observation within an existing SQL transaction, locking them against concurrent merges until the
+// transaction ends. With a nonzero expected version, the version is only incremented from that version,
+// otherwise an infra.VersionConflictError is returned.
+func (service *PortfolioAllocationDomService) IncrementPortfolioSnapshotVersionInTransaction(
+	transContext context.Context,
+	portfolioId int64,
+	observationTimestampId int64,
+	expectedVersion int64,
+) (int64, error) {
+	return service.portfolioAllocationRepository.IncrementPortfolioSnapshotVersionInTransaction(
+		transContext,
+		portfolioId,
+		observationTimestampId,
+		expectedVersion,
+	)
+}
+
 func (service *PortfolioAllocationDomService) MergePortfolioAllocationsInTransaction(
 	transContext context.Context,
 	portfolioId int64,
@@ -136,7 +165,25 @@ func BuildPortfolioAllocationDomService(
 			"class": func(allocation *domain.PortfolioAllocation) string {
 				return allocation.Class
 			},
+			"assetInstrumentType": func(allocation *domain.PortfolioAllocation) string {
+				return valueOrUnspecified(string(allocation.Asset.InstrumentType))
+			},
+			"assetCurrency": func(allocation *domain.PortfolioAllocation) string {
+				return valueOrUnspecified(allocation.Asset.Currency)
+			},
+			"assetExchange": func(allocation *domain.PortfolioAllocation) string {
+				return valueOrUnspecified(allocation.Asset.Exchange)
+			},
 		},
 		portfolioAllocationRepository: portfolioAllocationRepository,
 	}
 }
+
+// valueOrUnspecified returns the value of an optional hierarchy level field, or the unspecified placeholder
+// when the value is empty.
+func valueOrUnspecified(value string) string {
+	if value == "" {
+		return domain.UnspecifiedHierarchyLevelValue
+	}
+	return value
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+)
+
+var testAssetMetadataHierarchy = domain.AllocationHierarchy{
+	{Name: "Assets", Field: "assetTicker"},
+	{Name: "Exchanges", Field: "assetExchange"},
+	{Name: "Currencies", Field: "assetCurrency"},
+	{Name: "Instrument types", Field: "assetInstrumentType"},
+}
+
+func TestGenerateHierarchicalIdWithAssetMetadata(t *testing.T) {
+
+	var allocationService = BuildPortfolioAllocationDomService(nil)
+	var allocation = &domain.PortfolioAllocation{
+		Asset: domain.Asset{
+			Ticker:         "ARCA:IAU",
+			InstrumentType: domain.ETFInstrumentType,
+			Currency:       "USD",
+			Exchange:       "ARCA",
+		},
+	}
+
+	var hierarchicalId, err = allocationService.GenerateHierarchicalId(allocation, testAssetMetadataHierarchy, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ARCA:IAU|ARCA|USD|ETF", hierarchicalId)
+}
+
+func TestGenerateHierarchicalIdWithEmptyAssetMetadataUsesPlaceholder(t *testing.T) {
+
+	var allocationService = BuildPortfolioAllocationDomService(nil)
+	var allocation = &domain.PortfolioAllocation{Asset: domain.Asset{Ticker: "ARCA:IAU"}}
+
+	var hierarchicalId, err = allocationService.GenerateHierarchicalId(allocation, testAssetMetadataHierarchy, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ARCA:IAU|UNSPECIFIED|UNSPECIFIED|UNSPECIFIED", hierarchicalId)
+
+	var instrumentTypeSegment, segmentErr = allocationService.GetIdSegment(allocation, &testAssetMetadataHierarchy[3])
+
+	require.NoError(t, segmentErr)
+	assert.Equal(t, domain.UnspecifiedHierarchyLevelValue, instrumentTypeSegment)
+}
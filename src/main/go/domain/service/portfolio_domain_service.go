@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"github.com/benizzio/open-asset-allocator/domain"
 	"github.com/benizzio/open-asset-allocator/langext"
 )
@@ -13,18 +15,28 @@ func (service *PortfolioDomService) GetPortfolios() ([]*domain.Portfolio, error)
 	return service.portfolioRepository.GetAllPortfolios()
 }
 
-func (service *PortfolioDomService) GetPortfolio(id int64) (*domain.Portfolio, error) {
-	return service.portfolioRepository.FindPortfolio(id)
+// GetUserPortfolios returns the portfolios a user owns or was granted access to.
+func (service *PortfolioDomService) GetUserPortfolios(userId int64) ([]*domain.Portfolio, error) {
+	return service.portfolioRepository.GetUserPortfolios(userId)
+}
+
+func (service *PortfolioDomService) GetPortfolio(queryContext context.Context, id int64) (*domain.Portfolio, error) {
+	return service.portfolioRepository.FindPortfolio(queryContext, id)
 }
 
-func (service *PortfolioDomService) PersistPortfolio(portfolio *domain.Portfolio) (*domain.Portfolio, error) {
+// PersistPortfolioInTransaction inserts a portfolio without id, or updates an existing one, within an
+// existing SQL transaction.
+func (service *PortfolioDomService) PersistPortfolioInTransaction(
+	transContext context.Context,
+	portfolio *domain.Portfolio,
+) (*domain.Portfolio, error) {
 
 	var persistedPortfolio *domain.Portfolio
 	var err error
 	if langext.IsZeroValue(portfolio.Id) {
-		persistedPortfolio, err = service.portfolioRepository.InsertPortfolio(portfolio)
+		persistedPortfolio, err = service.portfolioRepository.InsertPortfolioInTransaction(transContext, portfolio)
 	} else {
-		persistedPortfolio, err = service.portfolioRepository.UpdatePortfolio(portfolio)
+		persistedPortfolio, err = service.portfolioRepository.UpdatePortfolioInTransaction(transContext, portfolio)
 	}
 
 	if err != nil {
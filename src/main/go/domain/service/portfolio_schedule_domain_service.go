@@ -0,0 +1,286 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/cron"
+)
+
+// maxMissedRunsRecorded bounds the missed activations recorded at once for a schedule, so a frequent
+// schedule reached after a long downtime does not flood its run history.
+const maxMissedRunsRecorded = 100
+
+type ScheduledTaskHandlersPerType map[domain.ScheduledTaskType]domain.ScheduledTaskHandler
+
+type PortfolioScheduleDomService struct {
+	portfolioScheduleRepository domain.PortfolioScheduleRepository
+	scheduledTaskHandlers       ScheduledTaskHandlersPerType
+}
+
+func (service *PortfolioScheduleDomService) GetPortfolioSchedules(
+	portfolioId int64,
+) ([]*domain.PortfolioSchedule, error) {
+	return service.portfolioScheduleRepository.FindPortfolioSchedules(portfolioId)
+}
+
+// GetPortfolioSchedule retrieves a schedule of a portfolio, or nil when it does not exist.
+func (service *PortfolioScheduleDomService) GetPortfolioSchedule(
+	portfolioId int64,
+	scheduleId int64,
+) (*domain.PortfolioSchedule, error) {
+	return service.portfolioScheduleRepository.FindPortfolioSchedule(portfolioId, scheduleId)
+}
+
+// GetScheduledTaskHandler returns the handler running the tasks of the type, or false when the type has
+// none.
+func (service *PortfolioScheduleDomService) GetScheduledTaskHandler(
+	taskType domain.ScheduledTaskType,
+) (domain.ScheduledTaskHandler, bool) {
+	var handler, exists = service.scheduledTaskHandlers[taskType]
+	return handler, exists
+}
+
+// InsertSchedule validates and persists a new schedule, with its next activation when enabled.
+//
+// Returns:
+//   - *domain.PortfolioSchedule: the persisted schedule
+//   - error: a DomainValidationError when the task type, cron expression, timezone or parameters are not
+//     valid
+func (service *PortfolioScheduleDomService) InsertSchedule(
+	schedule *domain.PortfolioSchedule,
+) (*domain.PortfolioSchedule, error) {
+
+	if err := service.prepareSchedule(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return service.portfolioScheduleRepository.InsertSchedule(schedule)
+}
+
+// UpdateSchedule validates and replaces the definition of a schedule, recomputing its next activation.
+//
+// Returns:
+//   - *domain.PortfolioSchedule: the updated schedule, or nil when it does not exist
+//   - error: a DomainValidationError when the task type, cron expression, timezone or parameters are not
+//     valid
+func (service *PortfolioScheduleDomService) UpdateSchedule(
+	schedule *domain.PortfolioSchedule,
+) (*domain.PortfolioSchedule, error) {
+
+	if err := service.prepareSchedule(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return service.portfolioScheduleRepository.UpdateSchedule(schedule)
+}
+
+func (service *PortfolioScheduleDomService) DeleteSchedule(portfolioId int64, scheduleId int64) (bool, error) {
+	return service.portfolioScheduleRepository.DeleteSchedule(portfolioId, scheduleId)
+}
+
+func (service *PortfolioScheduleDomService) GetScheduleRuns(
+	scheduleId int64,
+	limit int,
+) ([]*domain.PortfolioScheduleRun, error) {
+	return service.portfolioScheduleRepository.FindScheduleRuns(scheduleId, limit)
+}
+
+func (service *PortfolioScheduleDomService) GetDueSchedules(now time.Time) ([]*domain.PortfolioSchedule, error) {
+	return service.portfolioScheduleRepository.FindDueSchedules(now)
+}
+
+// ClaimDueActivation advances a due schedule to its first activation after now and decides what to do with
+// the activations it passed. Only the latest passed activation is run, and only when it is reached within
+// the misfire threshold. The other ones are recorded as missed.
+//
+// Returns:
+//   - *time.Time: the activation to run, or nil when there is none or the schedule was handled or changed
+//     meanwhile
+//   - error: the error evaluating the schedule or persisting its activations
+func (service *PortfolioScheduleDomService) ClaimDueActivation(
+	schedule *domain.PortfolioSchedule,
+	now time.Time,
+	misfireThreshold time.Duration,
+) (*time.Time, error) {
+
+	if schedule.NextRunAt == nil {
+		return nil, nil
+	}
+
+	var expression, location, err = parseScheduleTiming(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	var passedActivations = []time.Time{*schedule.NextRunAt}
+	var nextRunAt = expression.Next(schedule.NextRunAt.In(location))
+	for !nextRunAt.IsZero() && !nextRunAt.After(now) {
+		passedActivations = append(passedActivations, nextRunAt)
+		nextRunAt = expression.Next(nextRunAt)
+	}
+
+	advanced, err := service.portfolioScheduleRepository.AdvanceScheduleNextRun(
+		schedule.Id,
+		*schedule.NextRunAt,
+		timeOrNil(nextRunAt),
+	)
+	if err != nil || !advanced {
+		return nil, err
+	}
+
+	var activationToRun *time.Time
+	var latestActivation = passedActivations[len(passedActivations)-1]
+	if now.Sub(latestActivation) <= misfireThreshold {
+		activationToRun = &latestActivation
+		passedActivations = passedActivations[:len(passedActivations)-1]
+	}
+
+	if err = service.recordMissedActivations(schedule, passedActivations); err != nil {
+		return nil, err
+	}
+
+	return activationToRun, nil
+}
+
+func (service *PortfolioScheduleDomService) recordMissedActivations(
+	schedule *domain.PortfolioSchedule,
+	missedActivations []time.Time,
+) error {
+
+	if len(missedActivations) > maxMissedRunsRecorded {
+		slog.Warn(
+			"Schedule missed too many activations, recording only the latest",
+			"scheduleId", schedule.Id,
+			"missedCount", len(missedActivations),
+			"recordedCount", maxMissedRunsRecorded,
+		)
+		missedActivations = missedActivations[len(missedActivations)-maxMissedRunsRecorded:]
+	}
+
+	for _, missedActivation := range missedActivations {
+
+		var missedRun = &domain.PortfolioScheduleRun{
+			ScheduleId:   schedule.Id,
+			ScheduledFor: missedActivation,
+			Status:       domain.MissedScheduleRunStatus,
+		}
+
+		if _, err := service.portfolioScheduleRepository.InsertScheduleRun(missedRun); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RecordScheduleRun records the outcome of a run of a schedule, succeeded with its result marshalled to
+// JSON when runErr is nil, or failed with the error message otherwise.
+func (service *PortfolioScheduleDomService) RecordScheduleRun(
+	schedule *domain.PortfolioSchedule,
+	scheduledFor time.Time,
+	startedAt time.Time,
+	result any,
+	runErr error,
+) (*domain.PortfolioScheduleRun, error) {
+
+	var finishedAt = time.Now()
+	var run = &domain.PortfolioScheduleRun{
+		ScheduleId:   schedule.Id,
+		ScheduledFor: scheduledFor,
+		Status:       domain.SucceededScheduleRunStatus,
+		StartedAt:    &startedAt,
+		FinishedAt:   &finishedAt,
+	}
+
+	if runErr == nil {
+		var resultJSON, err = json.Marshal(result)
+		if err != nil {
+			runErr = fmt.Errorf("error marshalling scheduled task result: %w", err)
+		}
+		run.Result = resultJSON
+	}
+
+	if runErr != nil {
+		run.Status = domain.FailedScheduleRunStatus
+		run.Result = nil
+		run.ErrorMessage = runErr.Error()
+	}
+
+	return service.portfolioScheduleRepository.InsertScheduleRun(run)
+}
+
+// prepareSchedule validates a schedule and sets its next activation after now, or nil when it is disabled.
+func (service *PortfolioScheduleDomService) prepareSchedule(schedule *domain.PortfolioSchedule, now time.Time) error {
+
+	var handler, exists = service.GetScheduledTaskHandler(schedule.TaskType)
+	if !exists {
+		return infra.BuildDomainValidationError(fmt.Sprintf("Invalid scheduled task type %s", schedule.TaskType), nil)
+	}
+
+	if schedule.Timezone == "" {
+		schedule.Timezone = time.UTC.String()
+	}
+
+	var expression, location, err = parseScheduleTiming(schedule)
+	if err != nil {
+		return err
+	}
+
+	if len(schedule.Params) == 0 {
+		schedule.Params = json.RawMessage("{}")
+	}
+
+	if err = handler.ValidateParams(schedule.Params); err != nil {
+		return err
+	}
+
+	schedule.NextRunAt = nil
+	if schedule.Enabled {
+		schedule.NextRunAt = timeOrNil(expression.Next(now.In(location)))
+	}
+
+	return nil
+}
+
+// parseScheduleTiming parses the cron expression and timezone of a schedule.
+//
+// Returns:
+//   - *cron.Expression: the parsed cron expression
+//   - *time.Location: the location of the timezone
+//   - error: a DomainValidationError when either is not valid
+func parseScheduleTiming(schedule *domain.PortfolioSchedule) (*cron.Expression, *time.Location, error) {
+
+	var expression, err = cron.Parse(schedule.CronExpression)
+	if err != nil {
+		return nil, nil, infra.BuildDomainValidationError("Invalid cron expression: "+err.Error(), nil)
+	}
+
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, nil, infra.BuildDomainValidationError("Invalid timezone "+schedule.Timezone, nil)
+	}
+
+	return expression, location, nil
+}
+
+func timeOrNil(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
+
+func BuildPortfolioScheduleDomService(
+	portfolioScheduleRepository domain.PortfolioScheduleRepository,
+	scheduledTaskHandlers ScheduledTaskHandlersPerType,
+) *PortfolioScheduleDomService {
+	return &PortfolioScheduleDomService{
+		portfolioScheduleRepository: portfolioScheduleRepository,
+		scheduledTaskHandlers:       scheduledTaskHandlers,
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+)
+
+// recordingScheduleRepository is a fake repository keeping the advanced next activation and the recorded
+// runs. Methods not used by the activation claim are left to the embedded nil interface.
+type recordingScheduleRepository struct {
+	domain.PortfolioScheduleRepository
+	advanced      bool
+	nextRunAt     *time.Time
+	recordedRuns  []*domain.PortfolioScheduleRun
+	advanceResult bool
+}
+
+func (repository *recordingScheduleRepository) AdvanceScheduleNextRun(
+	_ int64,
+	_ time.Time,
+	nextRunAt *time.Time,
+) (bool, error) {
+	repository.advanced = true
+	repository.nextRunAt = nextRunAt
+	return repository.advanceResult, nil
+}
+
+func (repository *recordingScheduleRepository) InsertScheduleRun(
+	run *domain.PortfolioScheduleRun,
+) (*domain.PortfolioScheduleRun, error) {
+	repository.recordedRuns = append(repository.recordedRuns, run)
+	return run, nil
+}
+
+func buildTestSchedule(cronExpression string, nextRunAt time.Time) *domain.PortfolioSchedule {
+	return &domain.PortfolioSchedule{
+		Id:             1,
+		TaskType:       domain.RevaluationScheduledTaskType,
+		CronExpression: cronExpression,
+		Timezone:       "UTC",
+		Enabled:        true,
+		NextRunAt:      &nextRunAt,
+	}
+}
+
+func TestClaimDueActivationRunsActivationWithinThreshold(t *testing.T) {
+
+	var repository = &recordingScheduleRepository{advanceResult: true}
+	var service = BuildPortfolioScheduleDomService(repository, nil)
+
+	var schedule = buildTestSchedule("0 9 * * 1", time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC))
+	var now = time.Date(2024, 3, 11, 9, 0, 30, 0, time.UTC)
+
+	var activation, err = service.ClaimDueActivation(schedule, now, 10*time.Minute)
+	require.NoError(t, err)
+
+	require.NotNil(t, activation)
+	assert.Equal(t, *schedule.NextRunAt, *activation)
+	require.NotNil(t, repository.nextRunAt)
+	assert.Equal(t, time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC), repository.nextRunAt.UTC())
+	assert.Empty(t, repository.recordedRuns)
+}
+
+func TestClaimDueActivationRecordsMissedActivations(t *testing.T) {
+
+	var repository = &recordingScheduleRepository{advanceResult: true}
+	var service = BuildPortfolioScheduleDomService(repository, nil)
+
+	var schedule = buildTestSchedule("0 * * * *", time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC))
+	var now = time.Date(2024, 3, 11, 12, 5, 0, 0, time.UTC)
+
+	var activation, err = service.ClaimDueActivation(schedule, now, 10*time.Minute)
+	require.NoError(t, err)
+
+	require.NotNil(t, activation)
+	assert.Equal(t, time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC), activation.UTC())
+	assert.Equal(t, time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC), repository.nextRunAt.UTC())
+
+	require.Len(t, repository.recordedRuns, 3)
+	for index, run := range repository.recordedRuns {
+		assert.Equal(t, domain.MissedScheduleRunStatus, run.Status)
+		assert.Equal(t, time.Date(2024, 3, 11, 9+index, 0, 0, 0, time.UTC), run.ScheduledFor.UTC())
+	}
+}
+
+func TestClaimDueActivationMissesActivationBeyondThreshold(t *testing.T) {
+
+	var repository = &recordingScheduleRepository{advanceResult: true}
+	var service = BuildPortfolioScheduleDomService(repository, nil)
+
+	var schedule = buildTestSchedule("0 18 L * *", time.Date(2024, 2, 29, 18, 0, 0, 0, time.UTC))
+	var now = time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	var activation, err = service.ClaimDueActivation(schedule, now, time.Hour)
+	require.NoError(t, err)
+
+	assert.Nil(t, activation)
+	assert.Equal(t, time.Date(2024, 3, 31, 18, 0, 0, 0, time.UTC), repository.nextRunAt.UTC())
+	require.Len(t, repository.recordedRuns, 1)
+	assert.Equal(t, domain.MissedScheduleRunStatus, repository.recordedRuns[0].Status)
+}
+
+func TestClaimDueActivationSkipsScheduleHandledMeanwhile(t *testing.T) {
+
+	var repository = &recordingScheduleRepository{advanceResult: false}
+	var service = BuildPortfolioScheduleDomService(repository, nil)
+
+	var schedule = buildTestSchedule("0 * * * *", time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC))
+	var now = time.Date(2024, 3, 11, 12, 5, 0, 0, time.UTC)
+
+	var activation, err = service.ClaimDueActivation(schedule, now, 10*time.Minute)
+	require.NoError(t, err)
+
+	assert.True(t, repository.advanced)
+	assert.Nil(t, activation)
+	assert.Empty(t, repository.recordedRuns)
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const webhookSignaturePrefix = "sha256="
+
+const (
+	generatedWebhookSecretBytes = 32
+	minWebhookSecretLength      = 16
+)
+
+// webhookEventBody is the JSON body delivered to the webhooks, wrapping the payload of the event.
+type webhookEventBody struct {
+	Id          int64                   `json:"id"`
+	Type        domain.WebhookEventType `json:"type"`
+	PortfolioId int64                   `json:"portfolioId"`
+	CreatedAt   time.Time               `json:"createdAt"`
+	Data        json.RawMessage         `json:"data"`
+}
+
+type WebhookDomService struct {
+	webhookRepository domain.WebhookRepository
+	webhookSender     domain.WebhookSender
+	webhookConfig     infra.WebhookConfiguration
+}
+
+func (service *WebhookDomService) GetPortfolioWebhooks(portfolioId int64) ([]*domain.PortfolioWebhook, error) {
+	return service.webhookRepository.FindPortfolioWebhooks(portfolioId)
+}
+
+// GetPortfolioWebhook retrieves a webhook of a portfolio, or nil when it does not exist.
+func (service *WebhookDomService) GetPortfolioWebhook(
+	portfolioId int64,
+	webhookId int64,
+) (*domain.PortfolioWebhook, error) {
+	return service.webhookRepository.FindPortfolioWebhook(portfolioId, webhookId)
+}
+
+// InsertWebhook validates and persists a new webhook, generating its secret when none is given.
+//
+// Returns:
+//   - *domain.PortfolioWebhook: the persisted webhook
+//   - error: a DomainValidationError when the URL, event types or secret are not valid
+func (service *WebhookDomService) InsertWebhook(webhook *domain.PortfolioWebhook) (*domain.PortfolioWebhook, error) {
+
+	if webhook.Secret == "" {
+		var secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, infra.PropagateAsAppErrorWithNewMessage(err, "Error generating webhook secret", service)
+		}
+		webhook.Secret = secret
+	}
+
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return service.webhookRepository.InsertWebhook(webhook)
+}
+
+// UpdateWebhook validates and replaces the definition of a webhook, keeping its secret when none is given.
+//
+// Returns:
+//   - *domain.PortfolioWebhook: the updated webhook, or nil when it does not exist
+//   - error: a DomainValidationError when the URL, event types or secret are not valid
+func (service *WebhookDomService) UpdateWebhook(webhook *domain.PortfolioWebhook) (*domain.PortfolioWebhook, error) {
+
+	if webhook.Secret == "" {
+
+		var existingWebhook, err = service.webhookRepository.FindPortfolioWebhook(webhook.PortfolioId, webhook.Id)
+		if err != nil || existingWebhook == nil {
+			return nil, err
+		}
+
+		webhook.Secret = existingWebhook.Secret
+	}
+
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return service.webhookRepository.UpdateWebhook(webhook)
+}
+
+func (service *WebhookDomService) DeleteWebhook(portfolioId int64, webhookId int64) (bool, error) {
+	return service.webhookRepository.DeleteWebhook(portfolioId, webhookId)
+}
+
+func (service *WebhookDomService) GetWebhookDeliveries(webhookId int64, limit int) ([]*domain.WebhookDelivery, error) {
+	return service.webhookRepository.FindWebhookDeliveries(webhookId, limit)
+}
+
+// EmitEventInTransaction writes an event of a portfolio to the outbox within the transaction of the change
+// that emits it, with the payload marshalled to JSON. The event is delivered only if the transaction
+// commits.
+func (service *WebhookDomService) EmitEventInTransaction(
+	transContext context.Context,
+	portfolioId int64,
+	eventType domain.WebhookEventType,
+	payload any,
+) error {
+
+	var event, err = buildOutboxEvent(portfolioId, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	return service.webhookRepository.InsertOutboxEventInTransaction(transContext, event)
+}
+
+// EmitEvent writes an event of a portfolio to the outbox, with the payload marshalled to JSON, for events
+// that are not emitted by a transactional change.
+func (service *WebhookDomService) EmitEvent(portfolioId int64, eventType domain.WebhookEventType, payload any) error {
+
+	var event, err = buildOutboxEvent(portfolioId, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	return service.webhookRepository.InsertOutboxEvent(event)
+}
+
+// FanOutOutboxEvents dispatches up to limit outbox events to the deliveries of the subscribed webhooks,
+// returning how many events were dispatched.
+func (service *WebhookDomService) FanOutOutboxEvents(limit int) (int, error) {
+	return service.webhookRepository.FanOutOutboxEvents(limit)
+}
+
+func (service *WebhookDomService) GetDueDeliveries(now time.Time, limit int) ([]*domain.DueWebhookDelivery, error) {
+	return service.webhookRepository.FindDueDeliveries(now, limit)
+}
+
+// AttemptDelivery sends a signed request delivering the event to the webhook and records the outcome of
+// the attempt. A response with a 2xx status completes the delivery. Any other outcome schedules the next
+// attempt with an exponential backoff, or fails the delivery when it is out of attempts. An attempt failing
+// because the context is done, such as when the dispatcher stops, is not recorded, so the delivery stays due.
+//
+// Returns:
+//   - *domain.WebhookDelivery: the delivery with the outcome of the attempt
+//   - error: the error building the request or recording the attempt, or the context error of an interrupted
+//     attempt, not the failure of the attempt
+func (service *WebhookDomService) AttemptDelivery(
+	ctx context.Context,
+	dueDelivery *domain.DueWebhookDelivery,
+) (*domain.WebhookDelivery, error) {
+
+	var request, err = buildWebhookRequest(dueDelivery, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	statusCode, sendErr := service.webhookSender.Send(ctx, request)
+	if sendErr != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var delivery = dueDelivery.Delivery
+	service.recordAttemptOutcome(delivery, statusCode, sendErr, time.Now())
+
+	if err = service.webhookRepository.UpdateDeliveryAttempt(delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (service *WebhookDomService) recordAttemptOutcome(
+	delivery *domain.WebhookDelivery,
+	statusCode int,
+	sendErr error,
+	now time.Time,
+) {
+
+	delivery.AttemptCount++
+	delivery.LastStatusCode = nil
+	if sendErr == nil {
+		delivery.LastStatusCode = &statusCode
+	}
+
+	if sendErr == nil && statusCode >= 200 && statusCode < 300 {
+		delivery.Status = domain.SucceededWebhookDeliveryStatus
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	if sendErr != nil {
+		delivery.LastError = sendErr.Error()
+	} else {
+		delivery.LastError = fmt.Sprintf("webhook responded with status %d", statusCode)
+	}
+
+	if delivery.AttemptCount >= service.webhookConfig.MaxAttempts {
+		delivery.Status = domain.FailedWebhookDeliveryStatus
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	var nextAttemptAt = now.Add(service.computeRetryDelay(delivery.AttemptCount))
+	delivery.NextAttemptAt = &nextAttemptAt
+}
+
+// computeRetryDelay doubles the base delay for each attempt already made, up to the max delay.
+func (service *WebhookDomService) computeRetryDelay(attemptCount int) time.Duration {
+
+	var delay = service.webhookConfig.RetryBaseDelay
+	for attempt := 1; attempt < attemptCount && delay < service.webhookConfig.RetryMaxDelay; attempt++ {
+		delay *= 2
+	}
+
+	return min(delay, service.webhookConfig.RetryMaxDelay)
+}
+
+// SignWebhookPayload computes the signature sent in the X-Webhook-Signature header: the hex encoded
+// HMAC-SHA256 of the timestamp and the body joined by a dot, keyed by the secret of the webhook, prefixed
+// by sha256=. Receivers verify a delivery by computing it from the received X-Webhook-Timestamp header and
+// body.
+//
+// Example:
+//
+//	signature := service.SignWebhookPayload(webhook.Secret, 1700000000, body)
+//	// sha256=5d0c...
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	var mac = hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func buildWebhookRequest(dueDelivery *domain.DueWebhookDelivery, now time.Time) (*domain.WebhookRequest, error) {
+
+	var event = dueDelivery.Event
+	var body, err = json.Marshal(
+		webhookEventBody{
+			Id:          event.Id,
+			Type:        event.EventType,
+			PortfolioId: event.PortfolioId,
+			CreatedAt:   event.CreatedAt,
+			Data:        event.Payload,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling webhook event body: %w", err)
+	}
+
+	var timestamp = now.Unix()
+	return &domain.WebhookRequest{
+		URL: dueDelivery.URL,
+		Headers: map[string]string{
+			WebhookEventHeader:     string(event.EventType),
+			WebhookDeliveryHeader:  strconv.FormatInt(dueDelivery.Delivery.Id, 10),
+			WebhookTimestampHeader: strconv.FormatInt(timestamp, 10),
+			WebhookSignatureHeader: SignWebhookPayload(dueDelivery.Secret, timestamp, body),
+		},
+		Body: body,
+	}, nil
+}
+
+func buildOutboxEvent(
+	portfolioId int64,
+	eventType domain.WebhookEventType,
+	payload any,
+) (*domain.OutboxEvent, error) {
+
+	var payloadJSON, err = json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling %s event payload: %w", eventType, err)
+	}
+
+	return &domain.OutboxEvent{PortfolioId: portfolioId, EventType: eventType, Payload: payloadJSON}, nil
+}
+
+func validateWebhook(webhook *domain.PortfolioWebhook) error {
+
+	var webhookURL, err = url.Parse(webhook.URL)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return infra.BuildDomainValidationError(fmt.Sprintf("Invalid webhook URL %s", webhook.URL), nil)
+	}
+
+	if len(webhook.EventTypes) == 0 {
+		return infra.BuildDomainValidationError("Webhook must subscribe to at least one event type", nil)
+	}
+
+	for _, eventType := range webhook.EventTypes {
+		if !eventType.IsValid() {
+			return infra.BuildDomainValidationError(fmt.Sprintf("Invalid webhook event type %s", eventType), nil)
+		}
+	}
+
+	if len(webhook.Secret) < minWebhookSecretLength {
+		return infra.BuildDomainValidationError(
+			fmt.Sprintf("Webhook secret must have at least %d characters", minWebhookSecretLength),
+			nil,
+		)
+	}
+
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	var secretBytes = make([]byte, generatedWebhookSecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secretBytes), nil
+}
+
+func BuildWebhookDomService(
+	webhookRepository domain.WebhookRepository,
+	webhookSender domain.WebhookSender,
+	webhookConfig infra.WebhookConfiguration,
+) *WebhookDomService {
+	return &WebhookDomService{
+		webhookRepository: webhookRepository,
+		webhookSender:     webhookSender,
+		webhookConfig:     webhookConfig,
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/domain"
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+// recordingWebhookRepository is a fake repository keeping the updated delivery. Methods not used by the
+// delivery attempts are left to the embedded nil interface.
+type recordingWebhookRepository struct {
+	domain.WebhookRepository
+	updatedDelivery *domain.WebhookDelivery
+}
+
+func (repository *recordingWebhookRepository) UpdateDeliveryAttempt(delivery *domain.WebhookDelivery) error {
+	repository.updatedDelivery = delivery
+	return nil
+}
+
+// stubWebhookSender is a fake sender keeping the sent request and answering with a fixed outcome.
+type stubWebhookSender struct {
+	sentRequest *domain.WebhookRequest
+	statusCode  int
+	err         error
+}
+
+func (sender *stubWebhookSender) Send(_ context.Context, request *domain.WebhookRequest) (int, error) {
+	sender.sentRequest = request
+	return sender.statusCode, sender.err
+}
+
+var testWebhookConfig = infra.WebhookConfiguration{
+	MaxAttempts:    3,
+	RetryBaseDelay: time.Minute,
+	RetryMaxDelay:  3 * time.Minute,
+}
+
+func buildTestDueDelivery(attemptCount int) *domain.DueWebhookDelivery {
+	return &domain.DueWebhookDelivery{
+		Delivery: &domain.WebhookDelivery{
+			Id:           7,
+			WebhookId:    3,
+			EventId:      5,
+			EventType:    domain.SnapshotMergedWebhookEventType,
+			Status:       domain.PendingWebhookDeliveryStatus,
+			AttemptCount: attemptCount,
+		},
+		URL:    "https://example.com/hook",
+		Secret: "test-webhook-secret",
+		Event: &domain.OutboxEvent{
+			Id:          5,
+			PortfolioId: 1,
+			EventType:   domain.SnapshotMergedWebhookEventType,
+			Payload:     json.RawMessage(`{"observationTimestampId":2}`),
+			CreatedAt:   time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+
+	var body = []byte(`{"id":5}`)
+
+	var mac = hmac.New(sha256.New, []byte("test-webhook-secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	var expectedSignature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, expectedSignature, SignWebhookPayload("test-webhook-secret", 1700000000, body))
+	assert.NotEqual(t, expectedSignature, SignWebhookPayload("other-webhook-secret", 1700000000, body))
+	assert.NotEqual(t, expectedSignature, SignWebhookPayload("test-webhook-secret", 1700000001, body))
+}
+
+func TestAttemptDeliverySendsSignedEvent(t *testing.T) {
+
+	var repository = &recordingWebhookRepository{}
+	var sender = &stubWebhookSender{statusCode: 204}
+	var service = BuildWebhookDomService(repository, sender, testWebhookConfig)
+
+	var delivery, err = service.AttemptDelivery(context.Background(), buildTestDueDelivery(0))
+	require.NoError(t, err)
+
+	require.NotNil(t, sender.sentRequest)
+	assert.Equal(t, "https://example.com/hook", sender.sentRequest.URL)
+	assert.JSONEq(
+		t,
+		`
+			{
+				"id": 5,
+				"type": "SNAPSHOT_MERGED",
+				"portfolioId": 1,
+				"createdAt": "2024-03-11T09:00:00Z",
+				"data": {"observationTimestampId": 2}
+			}
+		`,
+		string(sender.sentRequest.Body),
+	)
+
+	var headers = sender.sentRequest.Headers
+	assert.Equal(t, "SNAPSHOT_MERGED", headers[WebhookEventHeader])
+	assert.Equal(t, "7", headers[WebhookDeliveryHeader])
+	timestamp, err := strconv.ParseInt(headers[WebhookTimestampHeader], 10, 64)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		SignWebhookPayload("test-webhook-secret", timestamp, sender.sentRequest.Body),
+		headers[WebhookSignatureHeader],
+	)
+
+	assert.Same(t, delivery, repository.updatedDelivery)
+	assert.Equal(t, domain.SucceededWebhookDeliveryStatus, delivery.Status)
+	assert.Equal(t, 1, delivery.AttemptCount)
+	require.NotNil(t, delivery.LastStatusCode)
+	assert.Equal(t, 204, *delivery.LastStatusCode)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestAttemptDeliverySchedulesRetryWithBackoff(t *testing.T) {
+
+	var repository = &recordingWebhookRepository{}
+	var sender = &stubWebhookSender{statusCode: 503}
+	var service = BuildWebhookDomService(repository, sender, testWebhookConfig)
+
+	var beforeAttempt = time.Now()
+	var delivery, err = service.AttemptDelivery(context.Background(), buildTestDueDelivery(1))
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.PendingWebhookDeliveryStatus, delivery.Status)
+	assert.Equal(t, 2, delivery.AttemptCount)
+	assert.Equal(t, "webhook responded with status 503", delivery.LastError)
+	require.NotNil(t, delivery.LastStatusCode)
+	assert.Equal(t, 503, *delivery.LastStatusCode)
+	require.NotNil(t, delivery.NextAttemptAt)
+	assert.WithinRange(
+		t,
+		*delivery.NextAttemptAt,
+		beforeAttempt.Add(2*time.Minute),
+		time.Now().Add(2*time.Minute),
+	)
+	assert.Nil(t, delivery.DeliveredAt)
+}
+
+func TestAttemptDeliveryFailsWhenOutOfAttempts(t *testing.T) {
+
+	var repository = &recordingWebhookRepository{}
+	var sender = &stubWebhookSender{err: errors.New("connection refused")}
+	var service = BuildWebhookDomService(repository, sender, testWebhookConfig)
+
+	var delivery, err = service.AttemptDelivery(context.Background(), buildTestDueDelivery(2))
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.FailedWebhookDeliveryStatus, delivery.Status)
+	assert.Equal(t, 3, delivery.AttemptCount)
+	assert.Equal(t, "connection refused", delivery.LastError)
+	assert.Nil(t, delivery.LastStatusCode)
+	assert.Nil(t, delivery.NextAttemptAt)
+}
+
+func TestAttemptDeliveryInterruptedByCancellationIsNotRecorded(t *testing.T) {
+
+	var repository = &recordingWebhookRepository{}
+	var sender = &stubWebhookSender{err: context.Canceled}
+	var service = BuildWebhookDomService(repository, sender, testWebhookConfig)
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	var dueDelivery = buildTestDueDelivery(2)
+	var delivery, err = service.AttemptDelivery(ctx, dueDelivery)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Nil(t, delivery)
+	assert.Nil(t, repository.updatedDelivery)
+	assert.Equal(t, domain.PendingWebhookDeliveryStatus, dueDelivery.Delivery.Status)
+	assert.Equal(t, 2, dueDelivery.Delivery.AttemptCount)
+	assert.Empty(t, dueDelivery.Delivery.LastError)
+}
+
+func TestComputeRetryDelayIsCappedByMaxDelay(t *testing.T) {
+
+	var service = BuildWebhookDomService(nil, nil, testWebhookConfig)
+
+	assert.Equal(t, time.Minute, service.computeRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, service.computeRetryDelay(2))
+	assert.Equal(t, 3*time.Minute, service.computeRetryDelay(3))
+	assert.Equal(t, 3*time.Minute, service.computeRetryDelay(50))
+}
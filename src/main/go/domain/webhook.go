@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type WebhookEventType string
+
+const (
+	SnapshotMergedWebhookEventType              WebhookEventType = "SNAPSHOT_MERGED"
+	AllocationPlanPersistedWebhookEventType     WebhookEventType = "ALLOCATION_PLAN_PERSISTED"
+	DivergenceThresholdExceededWebhookEventType WebhookEventType = "DIVERGENCE_THRESHOLD_EXCEEDED"
+	DivergenceAlertTriggeredWebhookEventType    WebhookEventType = "DIVERGENCE_ALERT_TRIGGERED"
+)
+
+// IsValid reports whether the event type is one of the events emitted to webhooks.
+func (eventType WebhookEventType) IsValid() bool {
+	switch eventType {
+	case SnapshotMergedWebhookEventType,
+		AllocationPlanPersistedWebhookEventType,
+		DivergenceThresholdExceededWebhookEventType,
+		DivergenceAlertTriggeredWebhookEventType:
+		return true
+	}
+	return false
+}
+
+// PortfolioWebhook is a URL notified of the events of a portfolio it subscribes to. Each delivery is
+// signed with the secret of the webhook, so the receiver can verify it came from the application.
+type PortfolioWebhook struct {
+	Id          int64
+	PortfolioId int64
+	URL         string
+	Secret      string
+	EventTypes  []WebhookEventType
+	Enabled     bool
+	CreatedAt   time.Time
+}
+
+// OutboxEvent is a domain event of a portfolio, written to the outbox in the transaction of the change
+// that emits it. DispatchedAt is set when the event is fanned out to the deliveries of the subscribed
+// webhooks.
+type OutboxEvent struct {
+	Id           int64
+	PortfolioId  int64
+	EventType    WebhookEventType
+	Payload      json.RawMessage
+	CreatedAt    time.Time
+	DispatchedAt *time.Time
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	PendingWebhookDeliveryStatus   WebhookDeliveryStatus = "PENDING"
+	SucceededWebhookDeliveryStatus WebhookDeliveryStatus = "SUCCEEDED"
+	FailedWebhookDeliveryStatus    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook. A pending delivery is attempted at
+// NextAttemptAt, and the outcome of its last attempt is kept in LastStatusCode, nil when no response was
+// received, and LastError.
+type WebhookDelivery struct {
+	Id             int64
+	WebhookId      int64
+	EventId        int64
+	EventType      WebhookEventType
+	Status         WebhookDeliveryStatus
+	AttemptCount   int
+	NextAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// DueWebhookDelivery is a pending delivery due to be attempted, with the webhook target and the event to
+// send.
+type DueWebhookDelivery struct {
+	Delivery *WebhookDelivery
+	URL      string
+	Secret   string
+	Event    *OutboxEvent
+}
+
+// WebhookRequest is a signed HTTP POST request delivering an event to a webhook.
+type WebhookRequest struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// WebhookSender sends the requests delivering events to webhooks.
+type WebhookSender interface {
+
+	// Send posts the request and returns the status code of the response. It returns an error when no
+	// response is received. The method must honor ctx cancellation.
+	Send(ctx context.Context, request *WebhookRequest) (int, error)
+}
+
+type WebhookRepository interface {
+	FindPortfolioWebhooks(portfolioId int64) ([]*PortfolioWebhook, error)
+	FindPortfolioWebhook(portfolioId int64, webhookId int64) (*PortfolioWebhook, error)
+	InsertWebhook(webhook *PortfolioWebhook) (*PortfolioWebhook, error)
+	UpdateWebhook(webhook *PortfolioWebhook) (*PortfolioWebhook, error)
+	DeleteWebhook(portfolioId int64, webhookId int64) (bool, error)
+	InsertOutboxEvent(event *OutboxEvent) error
+	InsertOutboxEventInTransaction(transContext context.Context, event *OutboxEvent) error
+	FanOutOutboxEvents(limit int) (int, error)
+	FindDueDeliveries(now time.Time, limit int) ([]*DueWebhookDelivery, error)
+	UpdateDeliveryAttempt(delivery *WebhookDelivery) error
+	FindWebhookDeliveries(webhookId int64, limit int) ([]*WebhookDelivery, error)
+}
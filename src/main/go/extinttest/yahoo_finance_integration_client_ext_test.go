@@ -31,11 +31,12 @@ const iauExpectedCurrency = "USD"
 // Authored by: GitHub Copilot (claude-opus-4.6)
 func TestSearchAssets_IAU(t *testing.T) {
 
-	var client = integration.BuildYahooFinanceAssetIntegrationClient(
+	client, err := integration.BuildYahooFinanceAssetIntegrationClient(
 		infra.ReadConfig().IntegrationConfig.YahooFinanceConfig,
 	)
+	require.NoError(t, err)
 
-	var searchResponse, err = client.SearchAssets(context.Background(), iauTicker)
+	searchResponse, err := client.SearchAssets(context.Background(), iauTicker)
 
 	require.NoError(t, err, "SearchAssets should not return an error")
 	require.NotNil(t, searchResponse, "SearchAssets response should not be nil")
@@ -61,11 +62,12 @@ func TestSearchAssets_IAU(t *testing.T) {
 // Authored by: GitHub Copilot (claude-opus-4.6)
 func TestQuoteAssetLastClosePrice_IAU(t *testing.T) {
 
-	var client = integration.BuildYahooFinanceAssetIntegrationClient(
+	client, err := integration.BuildYahooFinanceAssetIntegrationClient(
 		infra.ReadConfig().IntegrationConfig.YahooFinanceConfig,
 	)
+	require.NoError(t, err)
 
-	var chartResponse, err = client.QuoteAssetLastClosePrice(iauTicker)
+	chartResponse, err := client.QuoteAssetLastClosePrice(context.Background(), iauTicker)
 
 	require.NoError(t, err, "QuoteAssetLastClosePrice should not return an error")
 	require.NotNil(t, chartResponse, "QuoteAssetLastClosePrice response should not be nil")
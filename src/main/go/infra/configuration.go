@@ -1,13 +1,86 @@
 package infra
 
 import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/benizzio/open-asset-allocator/langext"
 )
 
 const defaultYahooFinanceSearchURL = "https://query2.finance.yahoo.com/v1/finance/search"
 const defaultYahooFinanceChartURL = "https://query2.finance.yahoo.com/v8/finance/chart/"
+const defaultStooqBaseURL = "https://stooq.com"
+const defaultCoinGeckoBaseURL = "https://api.coingecko.com/api/v3"
+const defaultCoinGeckoVsCurrency = "usd"
+
+const defaultAssetIntegrationCacheSearchTTL = time.Hour
+const defaultAssetIntegrationCacheQuoteTTL = 15 * time.Minute
+const defaultAssetIntegrationCacheStaleTTL = time.Hour
+
+const defaultHTTPCassetteDir = "cassettes"
+
+const defaultJSONProviderQuoteConcurrency = 2
+
+const defaultJobWorkerCount = 2
+
+const defaultSchedulerTickInterval = time.Minute
+const defaultSchedulerMisfireThreshold = 10 * time.Minute
+
+const defaultWebhookDispatchInterval = 5 * time.Second
+const defaultWebhookRequestTimeout = 10 * time.Second
+const defaultWebhookMaxAttempts = 8
+const defaultWebhookRetryBaseDelay = 30 * time.Second
+const defaultWebhookRetryMaxDelay = time.Hour
+
+const defaultAuthSessionTTL = 7 * 24 * time.Hour
+const defaultAuthSessionCookieName = "oaa_session"
+
+const defaultIdempotencyKeyTTL = 24 * time.Hour
+
+var defaultJSONProviderResilience = HTTPResilienceConfiguration{
+	MaxRetries:                     2,
+	RetryBaseDelay:                 500 * time.Millisecond,
+	RetryMaxDelay:                  10 * time.Second,
+	CircuitBreakerFailureThreshold: 5,
+	CircuitBreakerOpenDuration:     30 * time.Second,
+}
+
+var defaultYahooFinanceResilience = HTTPResilienceConfiguration{
+	MaxRetries:                     3,
+	RetryBaseDelay:                 500 * time.Millisecond,
+	RetryMaxDelay:                  10 * time.Second,
+	RateLimitPerSecond:             2,
+	RateLimitBurst:                 5,
+	CircuitBreakerFailureThreshold: 5,
+	CircuitBreakerOpenDuration:     30 * time.Second,
+}
+
+var defaultStooqResilience = HTTPResilienceConfiguration{
+	MaxRetries:                     2,
+	RetryBaseDelay:                 time.Second,
+	RetryMaxDelay:                  10 * time.Second,
+	RateLimitPerSecond:             1,
+	RateLimitBurst:                 3,
+	CircuitBreakerFailureThreshold: 5,
+	CircuitBreakerOpenDuration:     time.Minute,
+}
+
+// the public CoinGecko API allows around 30 calls per minute
+var defaultCoinGeckoResilience = HTTPResilienceConfiguration{
+	MaxRetries:                     3,
+	RetryBaseDelay:                 time.Second,
+	RetryMaxDelay:                  30 * time.Second,
+	RateLimitPerSecond:             0.5,
+	RateLimitBurst:                 5,
+	CircuitBreakerFailureThreshold: 5,
+	CircuitBreakerOpenDuration:     time.Minute,
+}
 
 type GinServerConfiguration struct {
 	Port                   string
@@ -17,6 +90,54 @@ type GinServerConfiguration struct {
 	webStaticSourcePath    string
 	apiRootPath            string
 	ApiOnly                bool
+	AuthConfig             AuthConfiguration
+	IdempotencyConfig      IdempotencyConfiguration
+}
+
+type AuthMode string
+
+const (
+	// AuthDisabledMode serves the API without authentication, for local usage only.
+	AuthDisabledMode AuthMode = "DISABLED"
+	// AuthLocalMode requires a session of a local user, or one of their API tokens, for every API request.
+	AuthLocalMode AuthMode = "LOCAL"
+)
+
+// AuthConfiguration configures the authentication of the API requests. In LOCAL mode the web UI sessions
+// last SessionTTL in a cookie named SessionCookieName, sent only over HTTPS when SecureCookie is set. When
+// there are no users, a first user is created on start from BootstrapUsername and BootstrapPassword.
+type AuthConfiguration struct {
+	Mode              AuthMode
+	SessionTTL        time.Duration
+	SessionCookieName string
+	SecureCookie      bool
+	BootstrapUsername string
+	BootstrapPassword string `json:"-"`
+}
+
+// IsEnabled reports whether the API requests are authenticated. An unset mode is DISABLED.
+func (config *AuthConfiguration) IsEnabled() bool {
+	return config.Mode == AuthLocalMode
+}
+
+// IdempotencyConfiguration configures the idempotency keys of the POST requests. A key identifies the
+// duplicates of its request, answered with the original response, for KeyTTL after it is first sent.
+type IdempotencyConfiguration struct {
+	KeyTTL time.Duration
+}
+
+type LogFormat string
+
+const (
+	TextLogFormat LogFormat = "TEXT"
+	JSONLogFormat LogFormat = "JSON"
+)
+
+// LogConfiguration configures the structured logs of the application, written from Level on as TEXT
+// (key=value) or JSON records.
+type LogConfiguration struct {
+	Level  slog.Level
+	Format LogFormat
 }
 
 type RDBMSConfiguration struct {
@@ -24,19 +145,162 @@ type RDBMSConfiguration struct {
 	RdbmsURL   string
 }
 
+// HTTPResilienceConfiguration configures the retries, rate limiting and circuit breaking of the HTTP
+// requests to an integrated provider. Zero values disable the corresponding mechanism.
+type HTTPResilienceConfiguration struct {
+	MaxRetries                     int
+	RetryBaseDelay                 time.Duration
+	RetryMaxDelay                  time.Duration
+	RateLimitPerSecond             float64
+	RateLimitBurst                 int
+	CircuitBreakerFailureThreshold int
+	CircuitBreakerOpenDuration     time.Duration
+}
+
+type HTTPCassetteMode string
+
+const (
+	HTTPCassetteRecordMode HTTPCassetteMode = "RECORD"
+	HTTPCassetteReplayMode HTTPCassetteMode = "REPLAY"
+)
+
+// HTTPCassetteConfiguration configures the recording of the HTTP exchanges with an integrated provider
+// to a cassette file, or their replay from it without network access. An empty Mode disables both.
+type HTTPCassetteConfiguration struct {
+	Mode     HTTPCassetteMode
+	FilePath string
+}
+
 type YahooFinanceConfiguration struct {
-	SearchURL string
-	ChartURL  string
+	SearchURL  string
+	ChartURL   string
+	Resilience HTTPResilienceConfiguration
+	Cassette   HTTPCassetteConfiguration
+}
+
+type StooqConfiguration struct {
+	BaseURL    string
+	Resilience HTTPResilienceConfiguration
+	Cassette   HTTPCassetteConfiguration
+}
+
+type CoinGeckoConfiguration struct {
+	BaseURL    string
+	APIKey     string `json:"-"`
+	VsCurrency string
+	Resilience HTTPResilienceConfiguration
+	Cassette   HTTPCassetteConfiguration
+}
+
+// JSONProviderSearchMapping declares where the fields of each search result are found in the search
+// response of a JSON provider. ResultsPath selects the array of results, and the other paths are
+// relative to each result. Only ResultsPath and TickerPath are required.
+type JSONProviderSearchMapping struct {
+	ResultsPath        string `json:"resultsPath"`
+	TickerPath         string `json:"tickerPath"`
+	ExchangeIdPath     string `json:"exchangeIdPath"`
+	NamePath           string `json:"namePath"`
+	ExchangeNamePath   string `json:"exchangeNamePath"`
+	InstrumentTypePath string `json:"instrumentTypePath"`
+}
+
+// JSONProviderQuoteMapping declares where the fields of the last close quote are found in the quote
+// response of a JSON provider. DateFormat is a Go time layout, or UNIX or UNIX_MILLI for epoch
+// timestamps, and the quote is dated on the current UTC day when DatePath is not set. Currency is used
+// when CurrencyPath is not set or not found in the response.
+type JSONProviderQuoteMapping struct {
+	PricePath    string `json:"pricePath"`
+	DatePath     string `json:"datePath"`
+	DateFormat   string `json:"dateFormat"`
+	CurrencyPath string `json:"currencyPath"`
+	Currency     string `json:"currency"`
+}
+
+// JSONProviderConfiguration declares a market data provider with a JSON HTTP API, integrated without
+// provider specific code. The URL templates have placeholders replaced by URL-escaped values: {query}
+// in SearchURLTemplate, and {ticker} and {exchangeId} in QuoteURLTemplate. Search is disabled when
+// SearchURLTemplate is empty. Header values may reference environment variables as ${NAME}, so secrets
+// are kept out of the provider declaration.
+type JSONProviderConfiguration struct {
+	Source            string
+	SearchURLTemplate string
+	QuoteURLTemplate  string
+	Headers           map[string]string `json:"-"`
+	QuoteConcurrency  int
+	SearchMapping     JSONProviderSearchMapping
+	QuoteMapping      JSONProviderQuoteMapping
+	Resilience        HTTPResilienceConfiguration
+	Cassette          HTTPCassetteConfiguration
+}
+
+// jsonProviderDeclaration is a provider declared in the JSON providers file.
+type jsonProviderDeclaration struct {
+	Source            string                    `json:"source"`
+	SearchURLTemplate string                    `json:"searchURLTemplate"`
+	QuoteURLTemplate  string                    `json:"quoteURLTemplate"`
+	Headers           map[string]string         `json:"headers"`
+	QuoteConcurrency  int                       `json:"quoteConcurrency"`
+	Search            JSONProviderSearchMapping `json:"search"`
+	Quote             JSONProviderQuoteMapping  `json:"quote"`
+}
+
+type AssetIntegrationCacheStorage string
+
+const (
+	AssetIntegrationCacheMemoryStorage AssetIntegrationCacheStorage = "MEMORY"
+	AssetIntegrationCacheRDBMSStorage  AssetIntegrationCacheStorage = "RDBMS"
+)
+
+// AssetIntegrationCacheConfiguration configures the cache of the external market data provider
+// responses. A response is fresh for the TTL of its operation and then served stale, while being
+// refreshed in the background, for StaleTTL. A zero TTL disables the cache of the operation.
+type AssetIntegrationCacheConfiguration struct {
+	Storage   AssetIntegrationCacheStorage
+	SearchTTL time.Duration
+	QuoteTTL  time.Duration
+	StaleTTL  time.Duration
 }
 
 type IntegrationConfiguration struct {
-	YahooFinanceConfig YahooFinanceConfiguration
+	YahooFinanceConfig  YahooFinanceConfiguration
+	StooqConfig         StooqConfiguration
+	CoinGeckoConfig     CoinGeckoConfiguration
+	JSONProviderConfigs []JSONProviderConfiguration
+	CacheConfig         AssetIntegrationCacheConfiguration
+}
+
+// JobConfiguration configures the background job runner. WorkerCount bounds how many jobs run at the
+// same time, the others waiting as pending.
+type JobConfiguration struct {
+	WorkerCount int
+}
+
+// SchedulerConfiguration configures the scheduler of the recurring portfolio tasks. The due schedules are
+// checked every TickInterval, and an activation reached later than MisfireThreshold is recorded as missed
+// instead of run.
+type SchedulerConfiguration struct {
+	TickInterval     time.Duration
+	MisfireThreshold time.Duration
+}
+
+// WebhookConfiguration configures the delivery of the portfolio events to webhooks. The outbox is
+// dispatched every DispatchInterval, and a failed delivery is retried with an exponential backoff from
+// RetryBaseDelay up to RetryMaxDelay, until it has been attempted MaxAttempts times.
+type WebhookConfiguration struct {
+	DispatchInterval time.Duration
+	RequestTimeout   time.Duration
+	MaxAttempts      int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
 }
 
 type Configuration struct {
 	GinServerConfig   GinServerConfiguration
 	RdbmsConfig       RDBMSConfiguration
 	IntegrationConfig IntegrationConfiguration
+	JobConfig         JobConfiguration
+	SchedulerConfig   SchedulerConfiguration
+	WebhookConfig     WebhookConfiguration
 }
 
 func (config *Configuration) String() string {
@@ -58,6 +322,26 @@ func ReadConfig() *Configuration {
 		yahooFinanceChartURL = defaultYahooFinanceChartURL
 	}
 
+	var stooqBaseURL = os.Getenv("STOOQ_BASE_URL")
+	if stooqBaseURL == "" {
+		stooqBaseURL = defaultStooqBaseURL
+	}
+
+	var coinGeckoBaseURL = os.Getenv("COINGECKO_BASE_URL")
+	if coinGeckoBaseURL == "" {
+		coinGeckoBaseURL = defaultCoinGeckoBaseURL
+	}
+
+	var coinGeckoVsCurrency = os.Getenv("COINGECKO_VS_CURRENCY")
+	if coinGeckoVsCurrency == "" {
+		coinGeckoVsCurrency = defaultCoinGeckoVsCurrency
+	}
+
+	var assetIntegrationCacheStorage = AssetIntegrationCacheStorage(os.Getenv("ASSET_INTEGRATION_CACHE_STORAGE"))
+	if assetIntegrationCacheStorage == "" {
+		assetIntegrationCacheStorage = AssetIntegrationCacheMemoryStorage
+	}
+
 	return &Configuration{
 		GinServerConfig: GinServerConfiguration{
 			Port:                   os.Getenv("PORT"),
@@ -67,6 +351,25 @@ func ReadConfig() *Configuration {
 			webStaticSourcePath:    tempWebStaticContentPath + tempWebStaticSourceRelPath,
 			apiRootPath:            "/api",
 			ApiOnly:                false,
+			AuthConfig: AuthConfiguration{
+				Mode: readAuthMode(),
+				SessionTTL: readEnvOrDefault(
+					"AUTH_SESSION_TTL",
+					defaultAuthSessionTTL,
+					time.ParseDuration,
+				),
+				SessionCookieName: readEnvOrDefault(
+					"AUTH_SESSION_COOKIE_NAME",
+					defaultAuthSessionCookieName,
+					func(value string) (string, error) { return value, nil },
+				),
+				SecureCookie:      readEnvOrDefault("AUTH_SESSION_COOKIE_SECURE", false, strconv.ParseBool),
+				BootstrapUsername: os.Getenv("AUTH_BOOTSTRAP_USERNAME"),
+				BootstrapPassword: os.Getenv("AUTH_BOOTSTRAP_PASSWORD"),
+			},
+			IdempotencyConfig: IdempotencyConfiguration{
+				KeyTTL: readEnvOrDefault("IDEMPOTENCY_KEY_TTL", defaultIdempotencyKeyTTL, time.ParseDuration),
+			},
 		},
 		RdbmsConfig: RDBMSConfiguration{
 			DriverName: os.Getenv("RDBMS_DRIVER_NAME"),
@@ -74,9 +377,309 @@ func ReadConfig() *Configuration {
 		},
 		IntegrationConfig: IntegrationConfiguration{
 			YahooFinanceConfig: YahooFinanceConfiguration{
-				SearchURL: yahooFinanceSearchURL,
-				ChartURL:  yahooFinanceChartURL,
+				SearchURL:  yahooFinanceSearchURL,
+				ChartURL:   yahooFinanceChartURL,
+				Resilience: readHTTPResilienceConfig("YAHOO_FINANCE", defaultYahooFinanceResilience),
+				Cassette:   readHTTPCassetteConfig("yahoo_finance.json"),
 			},
+			StooqConfig: StooqConfiguration{
+				BaseURL:    stooqBaseURL,
+				Resilience: readHTTPResilienceConfig("STOOQ", defaultStooqResilience),
+				Cassette:   readHTTPCassetteConfig("stooq.json"),
+			},
+			CoinGeckoConfig: CoinGeckoConfiguration{
+				BaseURL:    coinGeckoBaseURL,
+				APIKey:     os.Getenv("COINGECKO_API_KEY"),
+				VsCurrency: coinGeckoVsCurrency,
+				Resilience: readHTTPResilienceConfig("COINGECKO", defaultCoinGeckoResilience),
+				Cassette:   readHTTPCassetteConfig("coingecko.json"),
+			},
+			JSONProviderConfigs: readJSONProviderConfigs(os.Getenv("JSON_PROVIDERS_CONFIG_PATH")),
+			CacheConfig: AssetIntegrationCacheConfiguration{
+				Storage: assetIntegrationCacheStorage,
+				SearchTTL: readEnvOrDefault(
+					"ASSET_INTEGRATION_CACHE_SEARCH_TTL",
+					defaultAssetIntegrationCacheSearchTTL,
+					time.ParseDuration,
+				),
+				QuoteTTL: readEnvOrDefault(
+					"ASSET_INTEGRATION_CACHE_QUOTE_TTL",
+					defaultAssetIntegrationCacheQuoteTTL,
+					time.ParseDuration,
+				),
+				StaleTTL: readEnvOrDefault(
+					"ASSET_INTEGRATION_CACHE_STALE_TTL",
+					defaultAssetIntegrationCacheStaleTTL,
+					time.ParseDuration,
+				),
+			},
+		},
+		JobConfig: JobConfiguration{
+			WorkerCount: readEnvOrDefault("JOB_WORKER_COUNT", defaultJobWorkerCount, strconv.Atoi),
+		},
+		SchedulerConfig: SchedulerConfiguration{
+			TickInterval: readEnvOrDefault(
+				"SCHEDULER_TICK_INTERVAL",
+				defaultSchedulerTickInterval,
+				time.ParseDuration,
+			),
+			MisfireThreshold: readEnvOrDefault(
+				"SCHEDULER_MISFIRE_THRESHOLD",
+				defaultSchedulerMisfireThreshold,
+				time.ParseDuration,
+			),
 		},
+		WebhookConfig: WebhookConfiguration{
+			DispatchInterval: readEnvOrDefault(
+				"WEBHOOK_DISPATCH_INTERVAL",
+				defaultWebhookDispatchInterval,
+				time.ParseDuration,
+			),
+			RequestTimeout: readEnvOrDefault(
+				"WEBHOOK_REQUEST_TIMEOUT",
+				defaultWebhookRequestTimeout,
+				time.ParseDuration,
+			),
+			MaxAttempts: readEnvOrDefault("WEBHOOK_MAX_ATTEMPTS", defaultWebhookMaxAttempts, strconv.Atoi),
+			RetryBaseDelay: readEnvOrDefault(
+				"WEBHOOK_RETRY_BASE_DELAY",
+				defaultWebhookRetryBaseDelay,
+				time.ParseDuration,
+			),
+			RetryMaxDelay: readEnvOrDefault(
+				"WEBHOOK_RETRY_MAX_DELAY",
+				defaultWebhookRetryMaxDelay,
+				time.ParseDuration,
+			),
+		},
+	}
+}
+
+// ReadLogConfig reads the log configuration from the LOG_LEVEL (DEBUG, INFO, WARN or ERROR, INFO when not
+// set) and LOG_FORMAT (TEXT or JSON, TEXT when not set) environment variables. It is read apart from the
+// other configurations, before the logger exists to report them, so an invalid value is returned as an error.
+func ReadLogConfig() (LogConfiguration, error) {
+
+	var config = LogConfiguration{Level: slog.LevelInfo, Format: TextLogFormat}
+
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := config.Level.UnmarshalText([]byte(level)); err != nil {
+			return config, fmt.Errorf("invalid LOG_LEVEL %q: %w", level, err)
+		}
+	}
+
+	var format = LogFormat(strings.ToUpper(os.Getenv("LOG_FORMAT")))
+	switch format {
+	case "":
+	case TextLogFormat, JSONLogFormat:
+		config.Format = format
+	default:
+		return config, fmt.Errorf("invalid LOG_FORMAT %q, expected %s or %s", format, TextLogFormat, JSONLogFormat)
+	}
+
+	return config, nil
+}
+
+// readAuthMode reads the authentication mode from the AUTH_MODE environment variable, DISABLED when not
+// set. An invalid value stops the application instead of falling back, so a misconfiguration never leaves
+// the API unprotected.
+func readAuthMode() AuthMode {
+
+	var mode = AuthMode(strings.ToUpper(os.Getenv("AUTH_MODE")))
+	switch mode {
+	case "":
+		return AuthDisabledMode
+	case AuthDisabledMode, AuthLocalMode:
+		return mode
 	}
+
+	LogFatal("Invalid AUTH_MODE, expected "+string(AuthDisabledMode)+" or "+string(AuthLocalMode), "mode", mode)
+	return ""
+}
+
+// readHTTPResilienceConfig reads the resilience configuration of a provider from the environment
+// variables with the given prefix (e.g. YAHOO_FINANCE_HTTP_MAX_RETRIES), keeping the defaults for
+// the ones that are not set or invalid.
+func readHTTPResilienceConfig(
+	envPrefix string,
+	defaults HTTPResilienceConfiguration,
+) HTTPResilienceConfiguration {
+
+	var prefix = envPrefix + "_HTTP_"
+
+	return HTTPResilienceConfiguration{
+		MaxRetries:     readEnvOrDefault(prefix+"MAX_RETRIES", defaults.MaxRetries, strconv.Atoi),
+		RetryBaseDelay: readEnvOrDefault(prefix+"RETRY_BASE_DELAY", defaults.RetryBaseDelay, time.ParseDuration),
+		RetryMaxDelay:  readEnvOrDefault(prefix+"RETRY_MAX_DELAY", defaults.RetryMaxDelay, time.ParseDuration),
+		RateLimitPerSecond: readEnvOrDefault(
+			prefix+"RATE_LIMIT_PER_SECOND",
+			defaults.RateLimitPerSecond,
+			func(value string) (float64, error) { return strconv.ParseFloat(value, 64) },
+		),
+		RateLimitBurst: readEnvOrDefault(prefix+"RATE_LIMIT_BURST", defaults.RateLimitBurst, strconv.Atoi),
+		CircuitBreakerFailureThreshold: readEnvOrDefault(
+			prefix+"CIRCUIT_BREAKER_FAILURE_THRESHOLD",
+			defaults.CircuitBreakerFailureThreshold,
+			strconv.Atoi,
+		),
+		CircuitBreakerOpenDuration: readEnvOrDefault(
+			prefix+"CIRCUIT_BREAKER_OPEN_DURATION",
+			defaults.CircuitBreakerOpenDuration,
+			time.ParseDuration,
+		),
+	}
+}
+
+// readHTTPCassetteConfig reads the cassette configuration shared by the providers from the
+// HTTP_CASSETTE_MODE and HTTP_CASSETTE_DIR environment variables. Each provider uses its own cassette
+// file in the directory.
+func readHTTPCassetteConfig(fileName string) HTTPCassetteConfiguration {
+
+	var mode = readEnvOrDefault(
+		"HTTP_CASSETTE_MODE",
+		"",
+		func(value string) (HTTPCassetteMode, error) {
+			var mode = HTTPCassetteMode(value)
+			if mode != HTTPCassetteRecordMode && mode != HTTPCassetteReplayMode {
+				return "", fmt.Errorf("expected %s or %s", HTTPCassetteRecordMode, HTTPCassetteReplayMode)
+			}
+			return mode, nil
+		},
+	)
+
+	var cassetteDir = os.Getenv("HTTP_CASSETTE_DIR")
+	if cassetteDir == "" {
+		cassetteDir = defaultHTTPCassetteDir
+	}
+
+	return HTTPCassetteConfiguration{
+		Mode:     mode,
+		FilePath: filepath.Join(cassetteDir, fileName),
+	}
+}
+
+// readJSONProviderConfigs reads the JSON providers declared in the given file, a JSON array of provider
+// declarations. The resilience and cassette configurations of each provider are read from the
+// environment as for the built-in providers, using the upper-case source as prefix (e.g.
+// INTERNAL_FEED_HTTP_MAX_RETRIES). Invalid declarations are logged and left out.
+//
+// Example file:
+//
+//	[{
+//	    "source": "INTERNAL_FEED",
+//	    "searchURLTemplate": "https://feed.internal/search?q={query}",
+//	    "quoteURLTemplate": "https://feed.internal/quote/{ticker}",
+//	    "headers": {"Authorization": "Bearer ${INTERNAL_FEED_TOKEN}"},
+//	    "search": {"resultsPath": "$.results", "tickerPath": "$.symbol", "namePath": "$.name"},
+//	    "quote": {"pricePath": "$.close", "datePath": "$.date", "dateFormat": "2006-01-02", "currency": "USD"}
+//	}]
+func readJSONProviderConfigs(filePath string) []JSONProviderConfiguration {
+
+	if filePath == "" {
+		return nil
+	}
+
+	var content, err = os.ReadFile(filePath)
+	if err != nil {
+		slog.Error("Error reading JSON providers file, no JSON provider configured", "filePath", filePath, "error", err)
+		return nil
+	}
+
+	var declarations []jsonProviderDeclaration
+	if err = json.Unmarshal(content, &declarations); err != nil {
+		slog.Error("Error parsing JSON providers file, no JSON provider configured", "filePath", filePath, "error", err)
+		return nil
+	}
+
+	var configs = make([]JSONProviderConfiguration, 0, len(declarations))
+	var declaredSources = make(map[string]bool, len(declarations))
+	for index, declaration := range declarations {
+
+		var source = strings.ToUpper(strings.TrimSpace(declaration.Source))
+		if err = validateJSONProviderDeclaration(source, &declaration); err != nil {
+			slog.Error("Invalid JSON provider, ignoring it", "index", index, "filePath", filePath, "error", err)
+			continue
+		}
+
+		if declaredSources[source] {
+			slog.Error("Duplicated JSON provider, ignoring it", "source", source, "filePath", filePath)
+			continue
+		}
+		declaredSources[source] = true
+
+		var headers = make(map[string]string, len(declaration.Headers))
+		for key, value := range declaration.Headers {
+			headers[key] = os.ExpandEnv(value)
+		}
+
+		var quoteConcurrency = declaration.QuoteConcurrency
+		if quoteConcurrency <= 0 {
+			quoteConcurrency = defaultJSONProviderQuoteConcurrency
+		}
+
+		configs = append(
+			configs,
+			JSONProviderConfiguration{
+				Source:            source,
+				SearchURLTemplate: declaration.SearchURLTemplate,
+				QuoteURLTemplate:  declaration.QuoteURLTemplate,
+				Headers:           headers,
+				QuoteConcurrency:  quoteConcurrency,
+				SearchMapping:     declaration.Search,
+				QuoteMapping:      declaration.Quote,
+				Resilience:        readHTTPResilienceConfig(source, defaultJSONProviderResilience),
+				Cassette:          readHTTPCassetteConfig(strings.ToLower(source) + ".json"),
+			},
+		)
+	}
+
+	return configs
+}
+
+func validateJSONProviderDeclaration(source string, declaration *jsonProviderDeclaration) error {
+
+	if source == "" {
+		return fmt.Errorf("source is required")
+	}
+
+	if declaration.QuoteURLTemplate == "" || declaration.Quote.PricePath == "" {
+		return fmt.Errorf("provider %s requires the quote URL template and the quote price path", source)
+	}
+
+	if declaration.Quote.DatePath != "" && declaration.Quote.DateFormat == "" {
+		return fmt.Errorf("provider %s requires the quote date format with the quote date path", source)
+	}
+
+	if declaration.Quote.CurrencyPath == "" && declaration.Quote.Currency == "" {
+		return fmt.Errorf("provider %s requires the quote currency or the quote currency path", source)
+	}
+
+	var search = declaration.Search
+	if declaration.SearchURLTemplate != "" && (search.ResultsPath == "" || search.TickerPath == "") {
+		return fmt.Errorf("provider %s requires the search results and ticker paths", source)
+	}
+
+	return nil
+}
+
+func readEnvOrDefault[T any](envName string, defaultValue T, parse func(string) (T, error)) T {
+
+	var envValue = os.Getenv(envName)
+	if envValue == "" {
+		return defaultValue
+	}
+
+	var value, err = parse(envValue)
+	if err != nil {
+		slog.Warn(
+			"Invalid environment variable value, using default",
+			"name", envName,
+			"value", envValue,
+			"default", defaultValue,
+			"error", err,
+		)
+		return defaultValue
+	}
+
+	return value
 }
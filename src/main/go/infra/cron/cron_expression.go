@@ -0,0 +1,238 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds the search of the next activation, so expressions that never match, such as
+// "0 0 30 2 *", do not search forever.
+const maxSearchYears = 5
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type fieldBounds struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	minuteBounds     = fieldBounds{name: "minute", min: 0, max: 59}
+	hourBounds       = fieldBounds{name: "hour", min: 0, max: 23}
+	dayOfMonthBounds = fieldBounds{name: "day of month", min: 1, max: 31}
+	monthBounds      = fieldBounds{name: "month", min: 1, max: 12}
+	dayOfWeekBounds  = fieldBounds{name: "day of week", min: 0, max: 7}
+)
+
+// Expression is a parsed cron expression with the five standard fields: minute, hour, day of month,
+// month and day of week. Fields accept *, values, ranges (1-5), lists (1,15) and steps (*/15, 0-30/10).
+// Day of week 0 and 7 are both Sunday. The day of month also accepts L, the last day of the month. As in
+// standard cron, when both day fields are restricted a day matches when either of them matches. The
+// macros @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are also accepted.
+type Expression struct {
+	source          string
+	minutes         [60]bool
+	hours           [24]bool
+	daysOfMonth     [32]bool
+	lastDayOfMonth  bool
+	months          [13]bool
+	daysOfWeek      [7]bool
+	dayOfMonthIsAll bool
+	dayOfWeekIsAll  bool
+}
+
+// Parse parses a cron expression.
+//
+// Example:
+//
+//	monthEnd, err := cron.Parse("0 18 L * *")
+func Parse(source string) (*Expression, error) {
+
+	var normalizedSource = strings.TrimSpace(source)
+	if macro, isMacro := macros[strings.ToLower(normalizedSource)]; isMacro {
+		normalizedSource = macro
+	}
+
+	var fields = strings.Fields(normalizedSource)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, found %d", source, len(fields))
+	}
+
+	var expression = &Expression{source: strings.TrimSpace(source)}
+
+	if err := parseField(fields[0], minuteBounds, expression.minutes[:]); err != nil {
+		return nil, err
+	}
+
+	if err := parseField(fields[1], hourBounds, expression.hours[:]); err != nil {
+		return nil, err
+	}
+
+	var dayOfMonthField = fields[2]
+	if strings.EqualFold(dayOfMonthField, "L") {
+		expression.lastDayOfMonth = true
+	} else if err := parseField(dayOfMonthField, dayOfMonthBounds, expression.daysOfMonth[:]); err != nil {
+		return nil, err
+	}
+	expression.dayOfMonthIsAll = dayOfMonthField == "*"
+
+	if err := parseField(fields[3], monthBounds, expression.months[:]); err != nil {
+		return nil, err
+	}
+
+	var daysOfWeek [8]bool
+	if err := parseField(fields[4], dayOfWeekBounds, daysOfWeek[:]); err != nil {
+		return nil, err
+	}
+	copy(expression.daysOfWeek[:], daysOfWeek[:7])
+	expression.daysOfWeek[0] = expression.daysOfWeek[0] || daysOfWeek[7]
+	expression.dayOfWeekIsAll = fields[4] == "*"
+
+	return expression, nil
+}
+
+// Next returns the first activation strictly after the given time, in the location of that time, or the
+// zero time when the expression has no activation in the next years.
+func (expression *Expression) Next(after time.Time) time.Time {
+
+	var candidate = after.Truncate(time.Minute).Add(time.Minute)
+	var searchLimit = candidate.AddDate(maxSearchYears, 0, 0)
+	var location = candidate.Location()
+
+	for candidate.Before(searchLimit) {
+
+		var year, month, day = candidate.Date()
+
+		if !expression.months[month] {
+			candidate = time.Date(year, month+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if !expression.matchesDay(candidate) {
+			candidate = time.Date(year, month, day+1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if !expression.hours[candidate.Hour()] {
+			candidate = time.Date(year, month, day, candidate.Hour()+1, 0, 0, 0, location)
+			continue
+		}
+
+		if !expression.minutes[candidate.Minute()] {
+			candidate = candidate.Add(time.Minute)
+			continue
+		}
+
+		return candidate
+	}
+
+	return time.Time{}
+}
+
+func (expression *Expression) String() string {
+	return expression.source
+}
+
+func (expression *Expression) matchesDay(day time.Time) bool {
+
+	var matchesDayOfMonth = expression.daysOfMonth[day.Day()] ||
+		(expression.lastDayOfMonth && day.AddDate(0, 0, 1).Month() != day.Month())
+	var matchesDayOfWeek = expression.daysOfWeek[day.Weekday()]
+
+	if expression.dayOfMonthIsAll || expression.dayOfWeekIsAll {
+		return matchesDayOfMonth && matchesDayOfWeek
+	}
+
+	return matchesDayOfMonth || matchesDayOfWeek
+}
+
+// parseField marks in values the values of the field, a comma separated list of *, single values or
+// ranges, each with an optional step.
+func parseField(field string, bounds fieldBounds, values []bool) error {
+
+	for _, part := range strings.Split(field, ",") {
+
+		var rangePart, stepPart, hasStep = strings.Cut(part, "/")
+
+		var step = 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return fmt.Errorf("invalid %s step %q", bounds.name, stepPart)
+			}
+		}
+
+		var start, end, err = parseRange(rangePart, bounds)
+		if err != nil {
+			return err
+		}
+
+		// a single value with a step runs up to the maximum, as in 5/15
+		if hasStep && start == end && rangePart != "*" {
+			end = bounds.max
+		}
+
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+
+	return nil
+}
+
+func parseRange(rangePart string, bounds fieldBounds) (int, int, error) {
+
+	if rangePart == "*" {
+		return bounds.min, bounds.max, nil
+	}
+
+	var startPart, endPart, isRange = strings.Cut(rangePart, "-")
+
+	var start, err = parseValue(startPart, bounds)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !isRange {
+		return start, start, nil
+	}
+
+	end, err := parseValue(endPart, bounds)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end < start {
+		return 0, 0, fmt.Errorf("invalid %s range %q", bounds.name, rangePart)
+	}
+
+	return start, end, nil
+}
+
+func parseValue(valuePart string, bounds fieldBounds) (int, error) {
+
+	var value, err = strconv.Atoi(valuePart)
+	if err != nil || value < bounds.min || value > bounds.max {
+		return 0, fmt.Errorf(
+			"invalid %s %q, expected a value from %d to %d",
+			bounds.name,
+			valuePart,
+			bounds.min,
+			bounds.max,
+		)
+	}
+
+	return value, nil
+}
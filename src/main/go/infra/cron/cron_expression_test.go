@@ -0,0 +1,60 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionNext(t *testing.T) {
+
+	var testCases = []struct {
+		expression string
+		after      string
+		expected   string
+	}{
+		{expression: "* * * * *", after: "2024-03-10T10:15:30Z", expected: "2024-03-10T10:16:00Z"},
+		{expression: "*/15 * * * *", after: "2024-03-10T10:15:00Z", expected: "2024-03-10T10:30:00Z"},
+		{expression: "0 9 * * 1", after: "2024-03-10T10:00:00Z", expected: "2024-03-11T09:00:00Z"},
+		{expression: "0 9 * * 1-5", after: "2024-03-08T09:00:00Z", expected: "2024-03-11T09:00:00Z"},
+		{expression: "30 18 L * *", after: "2024-02-10T00:00:00Z", expected: "2024-02-29T18:30:00Z"},
+		{expression: "30 18 L * *", after: "2024-02-29T18:30:00Z", expected: "2024-03-31T18:30:00Z"},
+		{expression: "0 0 1,15 * *", after: "2024-01-01T00:00:00Z", expected: "2024-01-15T00:00:00Z"},
+		{expression: "0 0 13 * 5", after: "2024-01-01T00:00:00Z", expected: "2024-01-05T00:00:00Z"},
+		{expression: "0 0 * * 7", after: "2024-03-10T10:00:00Z", expected: "2024-03-17T00:00:00Z"},
+		{expression: "5/20 8 * 12 *", after: "2024-03-10T10:00:00Z", expected: "2024-12-01T08:05:00Z"},
+		{expression: "@monthly", after: "2024-12-31T23:59:00Z", expected: "2025-01-01T00:00:00Z"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expression+" after "+testCase.after, func(t *testing.T) {
+
+			var expression, err = Parse(testCase.expression)
+			require.NoError(t, err)
+
+			after, err := time.Parse(time.RFC3339, testCase.after)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, expression.Next(after).Format(time.RFC3339))
+		})
+	}
+}
+
+func TestExpressionNextWithoutActivation(t *testing.T) {
+
+	var expression, err = Parse("0 0 30 2 *")
+	require.NoError(t, err)
+
+	assert.True(t, expression.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+}
+
+func TestParseFailsForMalformedExpressions(t *testing.T) {
+
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "* * * * * *", "@never"} {
+		var _, err = Parse(expression)
+		assert.Error(t, err, expression)
+	}
+}
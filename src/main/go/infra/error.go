@@ -2,9 +2,8 @@ package infra
 
 import (
 	"fmt"
+	"log/slog"
 	"reflect"
-
-	"github.com/golang/glog"
 )
 
 // ======================================================================
@@ -35,6 +34,16 @@ func (domError *DomainValidationError) Error() string {
 	return domError.Message
 }
 
+// VersionConflictError represents a change rejected because it was based on a stale version of the data,
+// changed concurrently since it was read.
+type VersionConflictError struct {
+	Message string
+}
+
+func (conflictError *VersionConflictError) Error() string {
+	return conflictError.Message
+}
+
 // ======================================================================
 // Error API
 // ======================================================================
@@ -66,19 +75,25 @@ func BuildDomainValidationError(message string, causes []*AppError) error {
 	return &DomainValidationError{Message: message, Causes: causes}
 }
 
+func BuildVersionConflictError(message string) error {
+	return &VersionConflictError{Message: message}
+}
+
 // ======================================================================
 // Internal functionality
 // ======================================================================
 
 func newAppError(message string, cause error, originType any) *AppError {
-	logError(message, cause, originType)
+	logAppError(message, cause, originType)
 	return &AppError{Message: message, Cause: cause}
 }
 
-func logError(message string, cause error, origin any) {
-	var errorLog = "Error in " + reflect.TypeOf(origin).String() + ": " + message
+// logAppError logs the creation of an AppError in debug level only, as it is logged as an error where it is
+// finally handled (e.g. in the response to a request), with the context of its handling.
+func logAppError(message string, cause error, origin any) {
+	var attrs = []any{"message", message, "origin", reflect.TypeOf(origin).String()}
 	if cause != nil {
-		errorLog += ". Cause: " + cause.Error()
+		attrs = append(attrs, "cause", cause.Error())
 	}
-	glog.Error(errorLog)
+	slog.Debug("App error created", attrs...)
 }
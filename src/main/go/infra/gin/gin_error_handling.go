@@ -5,16 +5,18 @@ package gin
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang/glog"
 
 	"github.com/benizzio/open-asset-allocator/api/rest/model"
 	"github.com/benizzio/open-asset-allocator/infra"
+	"github.com/benizzio/open-asset-allocator/infra/json"
+	"github.com/benizzio/open-asset-allocator/infra/validation"
 )
 
-// HandleAPIError handles an error from the API layer by logging it and sending an appropriate HTTP response.
+// HandleAPIError handles an error from the API layer by logging it and sending an appropriate RFC 7807 HTTP response.
 // It first attempts to match the error to a known domain error type (e.g. DomainValidationError) and sends
 // a specific response. If no domain match is found, it falls back to a generic 500 Internal Server Error.
 // Returns true if an error was present and handled, false otherwise.
@@ -32,7 +34,7 @@ func HandleAPIError(context *gin.Context, message string, cause error) bool {
 	var handle = cause != nil
 	if handle {
 
-		glog.Error(message, ": ", cause)
+		slog.ErrorContext(context.Request.Context(), message, "error", cause)
 
 		var handled = handleDomainError(context, cause)
 		if handled {
@@ -40,10 +42,11 @@ func HandleAPIError(context *gin.Context, message string, cause error) bool {
 		}
 
 		// Fallback for unhandled errors
-		context.JSON(
-			http.StatusInternalServerError, model.ErrorResponse{
-				ErrorMessage: "Internal server error",
-			},
+		SendProblemResponse(
+			context,
+			http.StatusInternalServerError,
+			HTTPStatusProblemType(http.StatusInternalServerError),
+			"Internal server error",
 		)
 	}
 
@@ -51,48 +54,64 @@ func HandleAPIError(context *gin.Context, message string, cause error) bool {
 }
 
 // handleDomainError checks if the error matches a known domain error type and sends the corresponding HTTP response.
+// The causes of a DomainValidationError are sent as the errors of the problem.
 //
 // Co-authored by: GitHub Copilot
 func handleDomainError(context *gin.Context, cause error) bool {
 
 	if domValidationError, ok := errors.AsType[*infra.DomainValidationError](cause); ok {
 
-		var validationMessages = make([]string, len(domValidationError.Causes))
+		var problemErrors = make([]*model.ProblemError, len(domValidationError.Causes))
 		for i, validationError := range domValidationError.Causes {
-			validationMessages[i] = validationError.Message
+			problemErrors[i] = &model.ProblemError{Detail: validationError.Message}
 		}
-		context.JSON(
-			http.StatusBadRequest, model.ErrorResponse{
-				ErrorMessage: domValidationError.Message,
-				Details:      validationMessages,
-			},
+		SendProblemResponse(
+			context,
+			http.StatusBadRequest,
+			ValidationProblemType,
+			domValidationError.Message,
+			problemErrors...,
 		)
 
 		return true
 	}
 
+	if versionConflictError, ok := errors.AsType[*infra.VersionConflictError](cause); ok {
+		SendProblemResponse(
+			context,
+			http.StatusPreconditionFailed,
+			VersionConflictProblemType,
+			versionConflictError.Message,
+		)
+		return true
+	}
+
 	return false
 }
 
-// sendValidationErrorResponse sends a standardized HTTP response for validation validationErrors.
+// sendValidationErrorResponse sends a standardized HTTP response for validation validationErrors, with the
+// JSON pointer of the invalid field of each message.
 //
 // Authored by: GitHub Copilot
-func sendValidationErrorResponse(context *gin.Context, errorMessages []string) {
-	context.JSON(
-		http.StatusBadRequest, model.ErrorResponse{
-			ErrorMessage: "Validation failed",
-			Details:      errorMessages,
-		},
-	)
+func sendValidationErrorResponse(context *gin.Context, validationMessages []*validation.ValidationMessage) {
+
+	var problemErrors = make([]*model.ProblemError, len(validationMessages))
+	for i, validationMessage := range validationMessages {
+		problemErrors[i] = &model.ProblemError{
+			Pointer: json.ToJSONPointer(validationMessage.FieldPath),
+			Detail:  validationMessage.Message,
+		}
+	}
+
+	SendProblemResponse(context, http.StatusBadRequest, ValidationProblemType, "", problemErrors...)
 }
 
 // SendDataNotFoundResponse sends a standardized HTTP 404 response for a missing resource.
 func SendDataNotFoundResponse(context *gin.Context, dataType string, id string) {
-	context.JSON(
+	SendProblemResponse(
+		context,
 		http.StatusNotFound,
-		model.ErrorResponse{
-			ErrorMessage: "Data not found",
-			Details:      []string{dataType + " with identifier " + id + " not found"},
-		},
+		DataNotFoundProblemType,
+		dataType+" with identifier "+id+" not found",
 	)
 }
@@ -0,0 +1,88 @@
+package gin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/benizzio/open-asset-allocator/api/rest/model"
+)
+
+// ProblemContentType is the content type of the RFC 7807 error responses.
+const ProblemContentType = "application/problem+json"
+
+const (
+	problemTypeURIPrefix = "urn:open-asset-allocator:problem:"
+	blankProblemTypeURI  = "about:blank"
+)
+
+// ProblemType is a kind of problem of the RFC 7807 error responses, identified by its type URI and summarized
+// by its title.
+type ProblemType struct {
+	URI   string
+	Title string
+}
+
+var (
+	// ValidationProblemType is the problem of requests with invalid data, detailed per invalid field.
+	ValidationProblemType = BuildProblemType("validation", "Validation failed")
+	// DataNotFoundProblemType is the problem of requests for data that does not exist.
+	DataNotFoundProblemType = BuildProblemType("data-not-found", "Data not found")
+	// VersionConflictProblemType is the problem of changes based on a stale version of the data.
+	VersionConflictProblemType = BuildProblemType("version-conflict", "Version conflict")
+)
+
+// HTTPStatusProblemType returns the problem type of errors with no semantics beyond their HTTP status: the
+// "about:blank" type titled by the status text, as defined by RFC 7807.
+func HTTPStatusProblemType(status int) ProblemType {
+	return ProblemType{URI: blankProblemTypeURI, Title: http.StatusText(status)}
+}
+
+// SendProblemResponse sends an RFC 7807 application/problem+json error response, with the path of the request
+// as the problem instance.
+//
+// Example:
+//
+//	gininfra.SendProblemResponse(
+//		context,
+//		http.StatusUnauthorized,
+//		gininfra.HTTPStatusProblemType(http.StatusUnauthorized),
+//		"Authentication required",
+//	)
+func SendProblemResponse(
+	context *gin.Context,
+	status int,
+	problemType ProblemType,
+	detail string,
+	errors ...*model.ProblemError,
+) {
+
+	var problem = model.ProblemDetails{
+		Type:     problemType.URI,
+		Title:    problemType.Title,
+		Status:   status,
+		Detail:   detail,
+		Instance: context.Request.URL.Path,
+		Errors:   errors,
+	}
+
+	context.Header("Content-Type", ProblemContentType)
+	context.JSON(status, problem)
+}
+
+// AbortWithProblemResponse sends an RFC 7807 error response like SendProblemResponse, aborting the remaining
+// handlers of the request.
+func AbortWithProblemResponse(
+	context *gin.Context,
+	status int,
+	problemType ProblemType,
+	detail string,
+	errors ...*model.ProblemError,
+) {
+	SendProblemResponse(context, status, problemType, detail, errors...)
+	context.Abort()
+}
+
+func BuildProblemType(name string, title string) ProblemType {
+	return ProblemType{URI: problemTypeURIPrefix + name, Title: title}
+}
@@ -15,7 +15,7 @@ import (
 // Co-authored by: GitHub Copilot and OpenCode
 func BindAndValidateJSONWithInvalidResponse(context *gin.Context, bindingTarget interface{}) (bool, error) {
 
-	var allErrorMessages []string
+	var allErrorMessages []*validation.ValidationMessage
 
 	// Phase 1: Bind the JSON to the target struct (includes shallow validation)
 	bindingErr := context.ShouldBindJSON(bindingTarget)
@@ -49,15 +49,15 @@ func BindAndValidateJSONWithInvalidResponse(context *gin.Context, bindingTarget
 // validation messages emitted by the shallow and deep validation passes.
 //
 // Authored by: OpenCode
-func deduplicateValidationMessages(messages []string) []string {
-	var uniqueMessages = make([]string, 0, len(messages))
-	var seenMessages = make(map[string]struct{}, len(messages))
+func deduplicateValidationMessages(messages []*validation.ValidationMessage) []*validation.ValidationMessage {
+	var uniqueMessages = make([]*validation.ValidationMessage, 0, len(messages))
+	var seenMessages = make(map[validation.ValidationMessage]struct{}, len(messages))
 	for _, message := range messages {
-		if _, alreadySeen := seenMessages[message]; alreadySeen {
+		if _, alreadySeen := seenMessages[*message]; alreadySeen {
 			continue
 		}
 
-		seenMessages[message] = struct{}{}
+		seenMessages[*message] = struct{}{}
 		uniqueMessages = append(uniqueMessages, message)
 	}
 
@@ -12,14 +12,17 @@ import (
 // Authored by: GitHub Copilot
 func BindAndValidateQueryWithInvalidResponse(context *gin.Context, bindingTarget interface{}) (bool, error) {
 
-	var allErrorMessages []string
+	var allErrorMessages []*validation.ValidationMessage
 
 	var bindingErr = context.ShouldBindQuery(bindingTarget)
 	if bindingErr != nil {
 
 		var validationErrors = validation.MapValidationErrorsToMessages(bindingErr, bindingTarget)
 		if len(validationErrors) == 0 {
-			allErrorMessages = append(allErrorMessages, "malformed or invalid query parameters")
+			allErrorMessages = append(
+				allErrorMessages,
+				&validation.ValidationMessage{Message: "malformed or invalid query parameters"},
+			)
 		} else {
 			allErrorMessages = append(allErrorMessages, validationErrors...)
 		}
@@ -38,10 +38,19 @@ func TestBindAndValidateQueryWithInvalidResponse_MalformedQuery(t *testing.T) {
 	assert.False(t, valid)
 	assert.Equal(t, http.StatusBadRequest, recorder.Code)
 
-	var errorResponse model.ErrorResponse
-	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errorResponse))
-	assert.Equal(t, "Validation failed", errorResponse.ErrorMessage)
-	require.Len(t, errorResponse.Details, 2)
-	assert.Equal(t, "malformed or invalid query parameters", errorResponse.Details[0])
-	assert.Equal(t, "Field 'Limit' failed validation: is required", errorResponse.Details[1])
+	assert.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+
+	var problem model.ProblemDetails
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(t, ValidationProblemType.URI, problem.Type)
+	assert.Equal(t, "Validation failed", problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/test", problem.Instance)
+	require.Len(t, problem.Errors, 2)
+	assert.Equal(t, &model.ProblemError{Detail: "malformed or invalid query parameters"}, problem.Errors[0])
+	assert.Equal(
+		t,
+		&model.ProblemError{Pointer: "/Limit", Detail: "Field 'Limit' failed validation: is required"},
+		problem.Errors[1],
+	)
 }
@@ -0,0 +1,60 @@
+package gin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+	// unmatchableVersion is the expected version of If-Match headers no version can match, like weak or
+	// malformed entity tags
+	unmatchableVersion int64 = -1
+)
+
+// SetVersionETag sets the ETag header of the response to the version of the data it represents. Data without
+// a version (0), like a portfolio snapshot not yet observed, gets no ETag.
+func SetVersionETag(context *gin.Context, version int64) {
+	if version <= 0 {
+		return
+	}
+	context.Header(etagHeader, strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// GetIfMatchVersion returns the version expected by the If-Match header of the request, to be compared with
+// the current version of the changed data.
+//
+// Returns:
+//   - 0 when the header is absent or "*", so the change applies to any version
+//   - the version of a strong entity tag set by SetVersionETag
+//   - -1, matching no version, for any other entity tag
+func GetIfMatchVersion(context *gin.Context) int64 {
+
+	var ifMatch = strings.TrimSpace(context.GetHeader(ifMatchHeader))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0
+	}
+
+	unquoted, err := strconv.Unquote(ifMatch)
+	if err != nil {
+		return unmatchableVersion
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return unmatchableVersion
+	}
+
+	return version
+}
+
+// SendPreconditionFailedResponse sends the HTTP 412 response of a change based on a stale version, with the
+// current representation of the data and its version.
+func SendPreconditionFailedResponse(context *gin.Context, version int64, currentRepresentation any) {
+	SetVersionETag(context, version)
+	context.JSON(http.StatusPreconditionFailed, currentRepresentation)
+}
@@ -0,0 +1,65 @@
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gingonic "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIfMatchVersion(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+
+	var testCases = []struct {
+		name            string
+		ifMatch         string
+		expectedVersion int64
+	}{
+		{"absent", "", 0},
+		{"any", "*", 0},
+		{"strong entity tag", `"3"`, 3},
+		{"weak entity tag", `W/"3"`, unmatchableVersion},
+		{"unquoted", "3", unmatchableVersion},
+		{"not a version", `"abc"`, unmatchableVersion},
+		{"zero version", `"0"`, unmatchableVersion},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			var context, _ = gingonic.CreateTestContext(httptest.NewRecorder())
+			context.Request = httptest.NewRequest(http.MethodPut, "/test", nil)
+			if testCase.ifMatch != "" {
+				context.Request.Header.Set(ifMatchHeader, testCase.ifMatch)
+			}
+
+			assert.Equal(t, testCase.expectedVersion, GetIfMatchVersion(context))
+		})
+	}
+}
+
+func TestSendPreconditionFailedResponse(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+
+	var recorder = httptest.NewRecorder()
+	var context, _ = gingonic.CreateTestContext(recorder)
+
+	SendPreconditionFailedResponse(context, 4, map[string]string{"name": "current"})
+
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+	assert.Equal(t, `"4"`, recorder.Header().Get(etagHeader))
+	assert.JSONEq(t, `{"name": "current"}`, recorder.Body.String())
+}
+
+func TestSetVersionETagWithoutVersion(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+
+	var recorder = httptest.NewRecorder()
+	var context, _ = gingonic.CreateTestContext(recorder)
+
+	SetVersionETag(context, 0)
+
+	assert.Empty(t, recorder.Header().Get(etagHeader))
+}
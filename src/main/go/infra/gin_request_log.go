@@ -0,0 +1,59 @@
+package infra
+
+import (
+	"crypto/rand"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIdHeader carries the id of a request, sent back in its response. An id received from a client or a
+// proxy is kept, so its logs can be correlated with theirs.
+const RequestIdHeader = "X-Request-Id"
+
+var validRequestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestIdMiddleware assigns an id to each request, passed down in the context of the request so every
+// record logged while handling it, from the controllers to the repositories, carries the id. A request
+// handled again, like the front-end routes answered with the root HTML, keeps its id.
+func requestIdMiddleware(context *gin.Context) {
+
+	var requestId = RequestIdFromContext(context.Request.Context())
+	if requestId == "" {
+		requestId = context.GetHeader(RequestIdHeader)
+	}
+	if !validRequestIdPattern.MatchString(requestId) {
+		requestId = rand.Text()
+	}
+
+	context.Header(RequestIdHeader, requestId)
+	context.Request = context.Request.WithContext(WithRequestId(context.Request.Context(), requestId))
+
+	context.Next()
+}
+
+// requestLogMiddleware logs each handled request with its id, replacing the default access log of gin.
+func requestLogMiddleware(context *gin.Context) {
+
+	var start = time.Now()
+
+	context.Next()
+
+	var level = slog.LevelInfo
+	if context.Writer.Status() >= 500 {
+		level = slog.LevelError
+	}
+
+	slog.Log(
+		context.Request.Context(),
+		level,
+		"Request handled",
+		"method", context.Request.Method,
+		"path", context.Request.URL.Path,
+		"status", context.Writer.Status(),
+		"latency", time.Since(start),
+		"clientIP", context.ClientIP(),
+	)
+}
@@ -4,25 +4,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"path/filepath"
 	"reflect"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang/glog"
 )
 
 type GinServer struct {
-	router     *gin.Engine
-	config     GinServerConfiguration
-	httpServer *http.Server
+	router                   *gin.Engine
+	config                   GinServerConfiguration
+	httpServer               *http.Server
+	authenticationMiddleware gin.HandlerFunc
+	idempotencyMiddleware    gin.HandlerFunc
 }
 
+// RESTRoute is a route of a REST controller. Unauthenticated routes, such as the login, skip the
+// authentication middleware. Doc describes the route in the OpenAPI specification of the API.
 type RESTRoute struct {
-	Method   string
-	Path     string
-	Handlers gin.HandlersChain
+	Method          string
+	Path            string
+	Handlers        gin.HandlersChain
+	Unauthenticated bool
+	Doc             RESTRouteDoc
+}
+
+// RESTRouteDoc is the metadata of a RESTRoute documented in the OpenAPI specification of the API. Query,
+// RequestBody and ResponseBody are zero values of the types bound from the query parameters and the body of
+// the requests and sent in the body of the responses (e.g. model.PortfolioDTS{} or []*model.AssetDTS{}),
+// described by reflection. ResponseStatus defaults to 200 OK, or 204 No Content for routes with no
+// ResponseBody.
+type RESTRouteDoc struct {
+	Summary        string
+	Query          any
+	RequestBody    any
+	ResponseStatus int
+	ResponseBody   any
 }
 
 type GinServerRESTController interface {
@@ -36,10 +55,10 @@ func (server *GinServer) configBasicRoutes() {
 }
 
 func (server *GinServer) configStaticFilesRoute() {
-	glog.Infof(
-		"Serving static source files at %s from %s",
-		server.config.webStaticSourceRelPath,
-		server.config.webStaticSourcePath,
+	slog.Info(
+		"Serving static source files",
+		"path", server.config.webStaticSourceRelPath,
+		"directory", server.config.webStaticSourcePath,
 	)
 	server.router.Static(
 		server.config.webStaticSourceRelPath,
@@ -50,10 +69,10 @@ func (server *GinServer) configStaticFilesRoute() {
 func (server *GinServer) configRootHMTLAndDependenciesRoute() {
 
 	var rootHTMLPath = server.config.webStaticContentPath + "/" + server.config.rootHTMLFilename
-	glog.Infof("Serving root HTML file at / from  %s", rootHTMLPath)
+	slog.Info("Serving root HTML file at /", "file", rootHTMLPath)
 	server.router.StaticFile("/", rootHTMLPath)
 
-	glog.Infof("Serving .js, .js.map, .css files from root to load bundles")
+	slog.Info("Serving .js, .js.map, .css files from root to load bundles")
 	server.router.GET(
 		"/:filepath", func(context *gin.Context) {
 
@@ -80,7 +99,7 @@ func (server *GinServer) configRootHMTLAndDependenciesRoute() {
 
 func (server *GinServer) configUnknownStandardRoutes() {
 
-	glog.Infof("All unexpected requests outside the API path will return the root HTML for front-end routing")
+	slog.Info("All unexpected requests outside the API path will return the root HTML for front-end routing")
 	server.router.NoRoute(
 		func(context *gin.Context) {
 			if context.Request.Method == "GET" && !server.isRequestToAPI(context) {
@@ -103,14 +122,23 @@ func (server *GinServer) isRequestToAPI(context *gin.Context) bool {
 
 func (server *GinServer) configRESTRoutes(routes []RESTRoute) {
 	for _, route := range routes {
-		server.router.Handle(route.Method, route.Path, route.Handlers...)
+
+		var handlers = route.Handlers
+		if server.idempotencyMiddleware != nil && route.Method == http.MethodPost && !route.Unauthenticated {
+			handlers = append(gin.HandlersChain{server.idempotencyMiddleware}, handlers...)
+		}
+		if server.authenticationMiddleware != nil && !route.Unauthenticated {
+			handlers = append(gin.HandlersChain{server.authenticationMiddleware}, handlers...)
+		}
+
+		server.router.Handle(route.Method, route.Path, handlers...)
 	}
 }
 
 func (server *GinServer) configControllerRoutes(controllers []GinServerRESTController) {
-	glog.Infof("Received %d controllers to config routes", len(controllers))
+	slog.Info("Received controllers to config routes", "count", len(controllers))
 	for _, controller := range controllers {
-		glog.Infof("Configuring controller routes for %s", reflect.TypeOf(controller))
+		slog.Debug("Configuring controller routes", "controller", reflect.TypeOf(controller).String())
 		server.configRESTRoutes(controller.BuildRoutes())
 	}
 }
@@ -123,43 +151,66 @@ func (server *GinServer) start() {
 	}
 
 	go func() {
-		glog.Infof("Starting server on %s", server.httpServer.Addr)
+		slog.Info("Starting server", "address", server.httpServer.Addr)
 		if err := server.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			glog.Fatalf("listen: %s\n", err)
+			LogFatal("Error listening", "error", err)
 		}
 	}()
 }
 
 func (server *GinServer) stop(stopContext context.Context) {
 	if err := server.httpServer.Shutdown(stopContext); err != nil {
-		glog.Fatal("Server forced to shutdown: ", err)
+		LogFatal("Server forced to shutdown", "error", err)
 	}
 }
 
+// SetAuthenticationMiddleware sets the middleware authenticating the requests to the controller routes,
+// which must be called before Init. The static web content is served without authentication.
+func (server *GinServer) SetAuthenticationMiddleware(middleware gin.HandlerFunc) {
+	slog.Info("Authentication enabled for the controller routes")
+	server.authenticationMiddleware = middleware
+}
+
+// SetIdempotencyMiddleware sets the middleware honoring the Idempotency-Key header of the POST requests to
+// the controller routes, after their authentication, which must be called before Init. Unauthenticated
+// routes, such as the login, are never replayed.
+func (server *GinServer) SetIdempotencyMiddleware(middleware gin.HandlerFunc) {
+	slog.Info("Idempotency keys enabled for the POST controller routes")
+	server.idempotencyMiddleware = middleware
+}
+
 func (server *GinServer) Init(controllers []GinServerRESTController) {
 
-	glog.Infof("Configuring server before initialization")
+	slog.Info("Configuring server before initialization")
 
 	if !server.config.ApiOnly {
-		glog.Infof("Configuring basic routes")
+		slog.Info("Configuring basic routes")
 		server.configBasicRoutes()
 	}
 
-	glog.Infof("Configuring controller routes")
+	slog.Info("Configuring controller routes")
 	server.configControllerRoutes(controllers)
 
-	glog.Infof("STARTING server ==========>")
+	slog.Info("STARTING server ==========>")
 	server.start()
 }
 
 func (server *GinServer) Stop(stopContext context.Context) {
-	glog.Info("STOPPING server <==========")
+	slog.Info("STOPPING server <==========")
 	server.stop(stopContext)
 }
 
+// buildGinRouter builds the router with the request ids and the structured request logs in place of the
+// default gin logger.
+func buildGinRouter() *gin.Engine {
+	var router = gin.New()
+	router.Use(requestIdMiddleware, requestLogMiddleware, gin.Recovery())
+	return router
+}
+
 func BuildGinServer(config *Configuration) *GinServer {
 	return &GinServer{
-		router: gin.Default(),
+		router: buildGinRouter(),
 		config: config.GinServerConfig,
 	}
 }
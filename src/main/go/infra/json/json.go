@@ -22,6 +22,40 @@ func GetJSONFieldName(namespace string, fieldName string, structType reflect.Typ
 	return jsonFieldPath
 }
 
+// ToJSONPointer converts a JSON field path computed by GetJSONFieldName, as in "details[0].hierarchicalId",
+// into the equivalent RFC 6901 JSON pointer, as in "/details/0/hierarchicalId". An empty path is the empty
+// pointer, referencing the whole document.
+func ToJSONPointer(jsonFieldPath string) string {
+
+	var pointer strings.Builder
+	for _, pathPart := range parseNamespace(jsonFieldPath) {
+
+		if pathPart == "" {
+			continue
+		}
+
+		var bracketIndex = strings.Index(pathPart, "[")
+		if bracketIndex == -1 {
+			bracketIndex = len(pathPart)
+		}
+
+		if bracketIndex > 0 {
+			pointer.WriteString("/" + jsonPointerEscaper.Replace(pathPart[:bracketIndex]))
+		}
+
+		for _, index := range strings.Split(pathPart[bracketIndex:], "[") {
+			if index = strings.TrimSuffix(index, "]"); index != "" {
+				pointer.WriteString("/" + jsonPointerEscaper.Replace(index))
+			}
+		}
+	}
+
+	return pointer.String()
+}
+
+// jsonPointerEscaper escapes the reference tokens of JSON pointers, as defined by RFC 6901.
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 // buildJSONFieldPath resolves each namespace segment to its JSON field name while preserving
 // collection indexes so validation messages match the external API contract.
 //
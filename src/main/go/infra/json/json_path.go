@@ -0,0 +1,118 @@
+package json
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a compiled JSONPath-style expression selecting a single value of a decoded JSON document.
+// It supports the root "$", dot-separated object keys and bracketed array indexes, as in
+// "$.quoteResponse.result[0].regularMarketPrice". The leading "$" may be omitted, and the expression
+// "$" alone selects the whole document.
+type JSONPath struct {
+	expression string
+	segments   []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func (path *JSONPath) String() string {
+	return path.expression
+}
+
+// Select returns the value at the path in a document decoded by encoding/json into an any value.
+//
+// Returns:
+//   - any: the selected value
+//   - bool: false when a key or index of the path does not exist in the document, or a JSON null is
+//     reached
+func (path *JSONPath) Select(document any) (any, bool) {
+
+	var current = document
+	for _, segment := range path.segments {
+
+		if current == nil {
+			return nil, false
+		}
+
+		if segment.isIndex {
+			var array, isArray = current.([]any)
+			if !isArray || segment.index >= len(array) {
+				return nil, false
+			}
+			current = array[segment.index]
+			continue
+		}
+
+		var object, isObject = current.(map[string]any)
+		if !isObject {
+			return nil, false
+		}
+
+		var value, exists = object[segment.key]
+		if !exists {
+			return nil, false
+		}
+		current = value
+	}
+
+	return current, current != nil
+}
+
+// CompileJSONPath parses a JSONPath-style expression.
+//
+// Returns:
+//   - *JSONPath: the compiled path
+//   - error: if the expression is empty, has empty keys or has malformed indexes
+//
+// Example:
+//
+//	var path, err = json.CompileJSONPath("$.results[0].price")
+//	price, found := path.Select(document)
+func CompileJSONPath(expression string) (*JSONPath, error) {
+
+	var trimmedExpression = strings.TrimSpace(expression)
+	if trimmedExpression == "" {
+		return nil, fmt.Errorf("empty JSON path")
+	}
+
+	var remaining = strings.TrimPrefix(trimmedExpression, "$")
+	var segments []jsonPathSegment
+
+	for remaining != "" {
+
+		switch remaining[0] {
+		case '.':
+			remaining = remaining[1:]
+		case '[':
+			var closingIndex = strings.IndexByte(remaining, ']')
+			if closingIndex < 0 {
+				return nil, fmt.Errorf("unclosed index in JSON path %s", expression)
+			}
+			var index, err = strconv.Atoi(remaining[1:closingIndex])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %q in JSON path %s", remaining[1:closingIndex], expression)
+			}
+			segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+			remaining = remaining[closingIndex+1:]
+			continue
+		}
+
+		var keyEnd = strings.IndexAny(remaining, ".[")
+		if keyEnd < 0 {
+			keyEnd = len(remaining)
+		}
+		if keyEnd == 0 {
+			return nil, fmt.Errorf("empty key in JSON path %s", expression)
+		}
+		segments = append(segments, jsonPathSegment{key: remaining[:keyEnd]})
+		remaining = remaining[keyEnd:]
+	}
+
+	return &JSONPath{expression: trimmedExpression, segments: segments}, nil
+}
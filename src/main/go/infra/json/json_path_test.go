@@ -0,0 +1,61 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPathSelect(t *testing.T) {
+
+	var document any
+	require.NoError(
+		t,
+		json.Unmarshal(
+			[]byte(`{"quote": {"results": [{"symbol": "AAPL", "price": 189.5, "meta": null}]}, "total": 1}`),
+			&document,
+		),
+	)
+
+	var testCases = []struct {
+		expression    string
+		expectedValue any
+		expectedFound bool
+	}{
+		{expression: "$.quote.results[0].symbol", expectedValue: "AAPL", expectedFound: true},
+		{expression: "quote.results[0].price", expectedValue: 189.5, expectedFound: true},
+		{expression: "$.total", expectedValue: float64(1), expectedFound: true},
+		{expression: "$.quote.results[1].symbol"},
+		{expression: "$.quote.results[0].meta"},
+		{expression: "$.quote.missing"},
+		{expression: "$.total.value"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expression, func(t *testing.T) {
+
+			var path, err = CompileJSONPath(testCase.expression)
+			require.NoError(t, err)
+
+			var value, found = path.Select(document)
+			assert.Equal(t, testCase.expectedFound, found)
+			assert.Equal(t, testCase.expectedValue, value)
+		})
+	}
+
+	var rootPath, err = CompileJSONPath("$")
+	require.NoError(t, err)
+	var value, found = rootPath.Select(document)
+	assert.True(t, found)
+	assert.Equal(t, document, value)
+}
+
+func TestCompileJSONPathFailsForMalformedExpressions(t *testing.T) {
+
+	for _, expression := range []string{"", "$.results[", "$.results[-1]", "$.results[x]", "$..price"} {
+		var _, err = CompileJSONPath(expression)
+		assert.Error(t, err, expression)
+	}
+}
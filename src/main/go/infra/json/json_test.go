@@ -0,0 +1,28 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToJSONPointer(t *testing.T) {
+
+	var testCases = []struct {
+		jsonFieldPath   string
+		expectedPointer string
+	}{
+		{jsonFieldPath: "name", expectedPointer: "/name"},
+		{jsonFieldPath: "details[0].hierarchicalId", expectedPointer: "/details/0/hierarchicalId"},
+		{jsonFieldPath: "details[1].hierarchicalId[0]", expectedPointer: "/details/1/hierarchicalId/0"},
+		{jsonFieldPath: "matrix[0][2]", expectedPointer: "/matrix/0/2"},
+		{jsonFieldPath: "a/b.c~d", expectedPointer: "/a~1b/c~0d"},
+		{jsonFieldPath: "", expectedPointer: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.jsonFieldPath, func(t *testing.T) {
+			assert.Equal(t, testCase.expectedPointer, ToJSONPointer(testCase.jsonFieldPath))
+		})
+	}
+}
@@ -1,32 +1,80 @@
 package infra
 
 import (
-	"flag"
+	"context"
 	"fmt"
-
-	"github.com/golang/glog"
+	"log/slog"
+	"os"
 )
 
+const requestIdLogKey = "requestId"
+
+type requestIdContextKey struct{}
+
+// ConfigLogger sets the structured logger of the application as the slog default, writing to stderr the
+// records of the configured level and format. Returns true when the logger could not be configured.
 func ConfigLogger() bool {
 
-	err := flag.Set("logtostderr", "true")
+	var config, err = ReadLogConfig()
 	if err != nil {
-		fmt.Println("Error setting logtostderr flag: ", err)
+		fmt.Println("Error configuring logger: ", err)
 		return true
 	}
-	err = flag.Set("alsologtostderr", "true")
-	if err != nil {
-		fmt.Println("Error setting alsologtostderr flag: ", err)
-		return true
+
+	slog.SetDefault(slog.New(buildRequestIdLogHandler(buildLogHandler(config))))
+	return false
+}
+
+func buildLogHandler(config LogConfiguration) slog.Handler {
+
+	var options = &slog.HandlerOptions{Level: config.Level}
+	if config.Format == JSONLogFormat {
+		return slog.NewJSONHandler(os.Stderr, options)
 	}
-	err = flag.Set("stderrthreshold", "INFO")
-	if err != nil {
-		fmt.Println("Error setting stderrthreshold flag: ", err)
-		return true
+
+	return slog.NewTextHandler(os.Stderr, options)
+}
+
+// LogFatal logs the message and its attributes as an error and exits the application.
+func LogFatal(message string, args ...any) {
+	slog.Error(message, args...)
+	os.Exit(1)
+}
+
+// WithRequestId returns a copy of the context carrying the id of the request it handles, logged with every
+// record written from it.
+func WithRequestId(parentContext context.Context, requestId string) context.Context {
+	return context.WithValue(parentContext, requestIdContextKey{}, requestId)
+}
+
+// RequestIdFromContext returns the id of the request handled with the context, or an empty string when it is
+// not handling a request.
+func RequestIdFromContext(requestContext context.Context) string {
+	var requestId, _ = requestContext.Value(requestIdContextKey{}).(string)
+	return requestId
+}
+
+// requestIdLogHandler adds the id of the request, when there is one in the context of the record, to the
+// records of the wrapped handler, so the logs of a request can be traced through all the layers.
+type requestIdLogHandler struct {
+	slog.Handler
+}
+
+func (handler *requestIdLogHandler) Handle(recordContext context.Context, record slog.Record) error {
+	if requestId := RequestIdFromContext(recordContext); requestId != "" {
+		record.AddAttrs(slog.String(requestIdLogKey, requestId))
 	}
+	return handler.Handler.Handle(recordContext, record)
+}
 
-	flag.Parse()
-	defer glog.Flush()
+func (handler *requestIdLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return buildRequestIdLogHandler(handler.Handler.WithAttrs(attrs))
+}
 
-	return false
+func (handler *requestIdLogHandler) WithGroup(name string) slog.Handler {
+	return buildRequestIdLogHandler(handler.Handler.WithGroup(name))
+}
+
+func buildRequestIdLogHandler(handler slog.Handler) slog.Handler {
+	return &requestIdLogHandler{Handler: handler}
 }
@@ -0,0 +1,244 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+const (
+	jsonContentType          = "application/json"
+	restControllerTypeSuffix = "RESTController"
+)
+
+// DocumentConfig configures the OpenAPI document of the REST routes of the API.
+type DocumentConfig struct {
+	Title   string
+	Version string
+	// ErrorContentType and ErrorBody describe the error responses of every route.
+	ErrorContentType string
+	ErrorBody        any
+	// SecuritySchemes are the alternative ways of authenticating the requests to the routes, required by
+	// every route except the unauthenticated ones. Empty when authentication is disabled.
+	SecuritySchemes map[string]*SecurityScheme
+	// IdempotencyKeyHeader is the optional header making the authenticated POST routes idempotent, as the
+	// infra.GinServer applies the idempotency middleware to them.
+	IdempotencyKeyHeader string
+}
+
+type documentBuilder struct {
+	config       DocumentConfig
+	document     *Document
+	schemas      *schemaRegistry
+	operationIds map[string]bool
+}
+
+// BuildDocument builds the OpenAPI document of the routes of the REST controllers, from their
+// infra.RESTRouteDoc. The routes of each controller are tagged by the controller name, path parameters are
+// derived from the route paths, and the query parameters and bodies are described by reflection over the
+// form, json and validate tags of their types.
+func BuildDocument(config DocumentConfig, controllers []infra.GinServerRESTController) *Document {
+
+	var builder = &documentBuilder{
+		config: config,
+		document: &Document{
+			OpenAPI: openAPIVersion,
+			Info:    Info{Title: config.Title, Version: config.Version},
+			Paths:   make(map[string]*PathItem),
+		},
+		schemas:      buildSchemaRegistry(),
+		operationIds: make(map[string]bool),
+	}
+
+	builder.addSecuritySchemes()
+	for _, controller := range controllers {
+		var tag = controllerTag(controller)
+		builder.document.Tags = append(builder.document.Tags, &Tag{Name: tag})
+		for _, route := range controller.BuildRoutes() {
+			builder.addRoute(tag, route)
+		}
+	}
+	builder.document.Components.Schemas = builder.schemas.schemas
+
+	return builder.document
+}
+
+func (builder *documentBuilder) addSecuritySchemes() {
+
+	if len(builder.config.SecuritySchemes) == 0 {
+		return
+	}
+
+	var schemeNames = make([]string, 0, len(builder.config.SecuritySchemes))
+	for schemeName := range builder.config.SecuritySchemes {
+		schemeNames = append(schemeNames, schemeName)
+	}
+	sort.Strings(schemeNames)
+
+	for _, schemeName := range schemeNames {
+		builder.document.Security = append(builder.document.Security, map[string][]string{schemeName: {}})
+	}
+	builder.document.Components.SecuritySchemes = builder.config.SecuritySchemes
+}
+
+func (builder *documentBuilder) addRoute(tag string, route infra.RESTRoute) {
+
+	var path, parameters = toOpenAPIPath(route.Path)
+	var operation = &Operation{
+		Tags:        []string{tag},
+		Summary:     route.Doc.Summary,
+		OperationId: builder.operationId(route),
+		Parameters:  parameters,
+		Responses:   builder.responses(route.Doc),
+	}
+
+	if route.Doc.Query != nil {
+		var queryParameters = builder.schemas.queryParameters(reflect.TypeOf(route.Doc.Query))
+		operation.Parameters = append(operation.Parameters, queryParameters...)
+	}
+
+	if builder.config.IdempotencyKeyHeader != "" && route.Method == http.MethodPost && !route.Unauthenticated {
+		operation.Parameters = append(
+			operation.Parameters,
+			&Parameter{
+				Name:        builder.config.IdempotencyKeyHeader,
+				In:          "header",
+				Description: "Key making retries of the request return the original response",
+				Schema:      &Schema{Type: "string", MaxLength: toLengthBound(255, false, 0)},
+			},
+		)
+	}
+
+	if route.Doc.RequestBody != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  builder.content(jsonContentType, route.Doc.RequestBody),
+		}
+	}
+
+	if route.Unauthenticated && len(builder.document.Security) > 0 {
+		operation.Security = &SecurityRequirements{}
+	}
+
+	var pathItem, found = builder.document.Paths[path]
+	if !found {
+		pathItem = &PathItem{}
+		builder.document.Paths[path] = pathItem
+	}
+	(*pathItem)[strings.ToLower(route.Method)] = operation
+}
+
+// responses describes the successful response of a route and, as the default response, its errors.
+func (builder *documentBuilder) responses(routeDoc infra.RESTRouteDoc) map[string]*Response {
+
+	var status = routeDoc.ResponseStatus
+	if status == 0 && routeDoc.ResponseBody == nil {
+		status = http.StatusNoContent
+	} else if status == 0 {
+		status = http.StatusOK
+	}
+
+	var successResponse = &Response{Description: http.StatusText(status)}
+	if routeDoc.ResponseBody != nil {
+		successResponse.Content = builder.content(jsonContentType, routeDoc.ResponseBody)
+	}
+
+	var responses = map[string]*Response{strconv.Itoa(status): successResponse}
+	if builder.config.ErrorBody != nil {
+		responses["default"] = &Response{
+			Description: "Error",
+			Content:     builder.content(builder.config.ErrorContentType, builder.config.ErrorBody),
+		}
+	}
+
+	return responses
+}
+
+func (builder *documentBuilder) content(contentType string, body any) map[string]*MediaType {
+	return map[string]*MediaType{
+		contentType: {Schema: builder.schemas.schemaOf(reflect.TypeOf(body))},
+	}
+}
+
+// operationId identifies the operation of a route by the name of its last handler, the one of the controller,
+// suffixed by a counter when another route already has it.
+func (builder *documentBuilder) operationId(route infra.RESTRoute) string {
+
+	var name = strings.ToLower(route.Method)
+	if len(route.Handlers) > 0 {
+		name = handlerName(route.Handlers.Last())
+	}
+
+	var operationId = name
+	for counter := 2; builder.operationIds[operationId]; counter++ {
+		operationId = name + strconv.Itoa(counter)
+	}
+	builder.operationIds[operationId] = true
+
+	return operationId
+}
+
+// handlerName returns the name of a handler function or method, as "getPortfolios" for the method value
+// controller.getPortfolios, named ".../rest.(*PortfolioRESTController).getPortfolios-fm" by the runtime.
+func handlerName(handler any) string {
+
+	var function = runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if function == nil {
+		return ""
+	}
+
+	var name = function.Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// toOpenAPIPath converts a gin route path to an OpenAPI path, as "/api/portfolio/{portfolioId}" for
+// "/api/portfolio/:portfolioId", with the parameters of its path segments.
+func toOpenAPIPath(ginPath string) (string, []*Parameter) {
+
+	var segments = strings.Split(ginPath, "/")
+	var parameters []*Parameter
+	for i, segment := range segments {
+
+		if !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			continue
+		}
+
+		var name = segment[1:]
+		segments[i] = "{" + name + "}"
+		parameters = append(
+			parameters,
+			&Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}},
+		)
+	}
+
+	return strings.Join(segments, "/"), parameters
+}
+
+// controllerTag tags the routes of a controller by its type name, as "Allocation Plan" for
+// AllocationPlanRESTController.
+func controllerTag(controller infra.GinServerRESTController) string {
+
+	var controllerType = reflect.TypeOf(controller)
+	for controllerType.Kind() == reflect.Pointer {
+		controllerType = controllerType.Elem()
+	}
+
+	var name = strings.TrimSuffix(controllerType.Name(), restControllerTypeSuffix)
+
+	var tag strings.Builder
+	for i, character := range name {
+		if i > 0 && unicode.IsUpper(character) {
+			tag.WriteRune(' ')
+		}
+		tag.WriteRune(character)
+	}
+
+	return tag.String()
+}
@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+)
+
+type testProblemDTS struct {
+	Title string `json:"title"`
+}
+
+type TestItemRESTController struct{}
+
+func (controller *TestItemRESTController) BuildRoutes() []infra.RESTRoute {
+	return []infra.RESTRoute{
+		{
+			Method:   http.MethodGet,
+			Path:     "/api/item/:itemId",
+			Handlers: gin.HandlersChain{controller.getItem},
+			Doc: infra.RESTRouteDoc{
+				Summary:      "Get an item",
+				ResponseBody: testSchemaItemDTS{},
+			},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/api/item",
+			Handlers: gin.HandlersChain{controller.postItem},
+			Doc: infra.RESTRouteDoc{
+				Query:          testSchemaQueryDTS{},
+				RequestBody:    testSchemaItemDTS{},
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   testSchemaItemDTS{},
+			},
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/item/:itemId/archive",
+			Handlers:        gin.HandlersChain{controller.postItem},
+			Unauthenticated: true,
+		},
+	}
+}
+
+func (controller *TestItemRESTController) getItem(*gin.Context) {}
+
+func (controller *TestItemRESTController) postItem(*gin.Context) {}
+
+func TestBuildDocumentFromRouteMetadata(t *testing.T) {
+
+	var document = BuildDocument(
+		DocumentConfig{
+			Title:                "Test API",
+			Version:              "1",
+			ErrorContentType:     "application/problem+json",
+			ErrorBody:            testProblemDTS{},
+			SecuritySchemes:      map[string]*SecurityScheme{"apiToken": {Type: "http", Scheme: "bearer"}},
+			IdempotencyKeyHeader: "Idempotency-Key",
+		},
+		[]infra.GinServerRESTController{&TestItemRESTController{}},
+	)
+
+	assert.Equal(t, openAPIVersion, document.OpenAPI)
+	assert.Equal(t, Info{Title: "Test API", Version: "1"}, document.Info)
+	assert.Equal(t, []*Tag{{Name: "Test Item"}}, document.Tags)
+	assert.Equal(t, SecurityRequirements{{"apiToken": {}}}, document.Security)
+
+	require.Contains(t, document.Paths, "/api/item/{itemId}")
+	var getOperation = (*document.Paths["/api/item/{itemId}"])["get"]
+	require.NotNil(t, getOperation)
+	assert.Equal(t, "getItem", getOperation.OperationId)
+	assert.Equal(t, "Get an item", getOperation.Summary)
+	assert.Equal(t, []string{"Test Item"}, getOperation.Tags)
+	assert.Equal(
+		t,
+		[]*Parameter{{Name: "itemId", In: "path", Required: true, Schema: &Schema{Type: "string"}}},
+		getOperation.Parameters,
+	)
+	assert.Nil(t, getOperation.Security)
+	assert.Equal(
+		t,
+		componentSchemaRefPrefix+"testSchemaItemDTS",
+		getOperation.Responses["200"].Content[jsonContentType].Schema.Ref,
+	)
+	assert.Equal(
+		t,
+		componentSchemaRefPrefix+"testProblemDTS",
+		getOperation.Responses["default"].Content["application/problem+json"].Schema.Ref,
+	)
+
+	require.Contains(t, document.Paths, "/api/item")
+	var postOperation = (*document.Paths["/api/item"])["post"]
+	require.NotNil(t, postOperation)
+	require.Len(t, postOperation.Parameters, 4)
+	assert.Equal(t, "limit", postOperation.Parameters[2].Name)
+	assert.Equal(t, "Idempotency-Key", postOperation.Parameters[3].Name)
+	assert.Equal(t, "header", postOperation.Parameters[3].In)
+	assert.True(t, postOperation.RequestBody.Required)
+	assert.Contains(t, postOperation.Responses, "201")
+
+	var unauthenticatedOperation = (*document.Paths["/api/item/{itemId}/archive"])["post"]
+	require.NotNil(t, unauthenticatedOperation)
+	assert.Equal(t, "postItem2", unauthenticatedOperation.OperationId)
+	assert.Equal(t, &SecurityRequirements{}, unauthenticatedOperation.Security)
+	assert.Len(t, unauthenticatedOperation.Parameters, 1, "unauthenticated routes are not idempotent")
+	assert.Nil(t, unauthenticatedOperation.Responses["204"].Content)
+
+	assert.Contains(t, document.Components.Schemas, "testSchemaItemDTS")
+	assert.Contains(t, document.Components.SecuritySchemes, "apiToken")
+}
@@ -0,0 +1,322 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/benizzio/open-asset-allocator/langext"
+)
+
+const componentSchemaRefPrefix = "#/components/schemas/"
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	decimalType    = reflect.TypeFor[decimal.Decimal]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemaRegistry describes Go types as JSON schemas, by reflection over their json and validate tags. The
+// schemas of named struct types are kept as components of the document, referenced by the other schemas.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// schemaOf describes a type as a JSON schema, with nil pointers to values described as nullable.
+func (registry *schemaRegistry) schemaOf(valueType reflect.Type) *Schema {
+
+	var nullable = false
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+		nullable = true
+	}
+
+	var schema = registry.nonNullableSchemaOf(valueType)
+	if nullable && schema.Ref == "" {
+		schema.Nullable = true
+	}
+
+	return schema
+}
+
+func (registry *schemaRegistry) nonNullableSchemaOf(valueType reflect.Type) *Schema {
+
+	switch valueType {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case decimalType:
+		return &Schema{Type: "string", Format: "decimal"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch valueType.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if valueType.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: registry.schemaOf(valueType.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: registry.schemaOf(valueType.Elem())}
+	case reflect.Struct:
+		return registry.structSchemaRef(valueType)
+	default:
+		return &Schema{}
+	}
+}
+
+// structSchemaRef references the component schema of a named struct type, registering it on first use.
+// Anonymous struct types are described inline.
+func (registry *schemaRegistry) structSchemaRef(structType reflect.Type) *Schema {
+
+	if structType.Name() == "" {
+		return registry.structSchema(structType)
+	}
+
+	var name, registered = registry.names[structType]
+	if !registered {
+
+		name = registry.componentName(structType)
+		registry.names[structType] = name
+
+		// registered before being described, so recursive types reference it instead of recursing forever
+		var schema = &Schema{}
+		registry.schemas[name] = schema
+		*schema = *registry.structSchema(structType)
+	}
+
+	return &Schema{Ref: componentSchemaRefPrefix + name}
+}
+
+// componentName names the component schema of a struct type by the type name, qualified by its package
+// when another type already has the name.
+func (registry *schemaRegistry) componentName(structType reflect.Type) string {
+
+	var name = structType.Name()
+	if _, taken := registry.schemas[name]; taken {
+		name = path.Base(structType.PkgPath()) + name
+	}
+
+	return name
+}
+
+func (registry *schemaRegistry) structSchema(structType reflect.Type) *Schema {
+	var schema = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	registry.addStructProperties(schema, structType)
+	return schema
+}
+
+// addStructProperties describes the JSON fields of a struct as properties of its schema, following the
+// encoding/json rules: fields tagged "-" and unexported fields are left out, and the fields of untagged
+// embedded structs are promoted.
+func (registry *schemaRegistry) addStructProperties(schema *Schema, structType reflect.Type) {
+
+	for i := 0; i < structType.NumField(); i++ {
+
+		var field = structType.Field(i)
+		var jsonTag = field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+
+		var fieldType = langext.UnwrapType(field.Type)
+		if field.Anonymous && jsonTag == "" && fieldType.Kind() == reflect.Struct {
+			registry.addStructProperties(schema, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		var name = langext.ExtractJSONFieldName(field)
+		var fieldSchema = registry.schemaOf(field.Type)
+		if applyValidateTag(fieldSchema, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = fieldSchema
+	}
+}
+
+// queryParameters describes the fields of a query parameters struct, named by their form tags, as query
+// parameters.
+func (registry *schemaRegistry) queryParameters(queryType reflect.Type) []*Parameter {
+
+	queryType = langext.UnwrapType(queryType)
+
+	var parameters = make([]*Parameter, 0, queryType.NumField())
+	for i := 0; i < queryType.NumField(); i++ {
+
+		var field = queryType.Field(i)
+		var name, _, _ = strings.Cut(field.Tag.Get("form"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var schema = registry.schemaOf(field.Type)
+		schema.Nullable = false
+
+		parameters = append(
+			parameters,
+			&Parameter{
+				Name:     name,
+				In:       "query",
+				Required: applyValidateTag(schema, field.Tag.Get("validate")),
+				Schema:   schema,
+			},
+		)
+	}
+
+	return parameters
+}
+
+// applyValidateTag documents the rules of a validate tag as constraints of the schema of the validated
+// value, returning whether the value is required. The rules after a dive are applied to the items of the
+// schema. Rules with no JSON schema equivalent are ignored, as are the constraints of referenced schemas.
+func applyValidateTag(schema *Schema, validateTag string) bool {
+
+	if validateTag == "" {
+		return false
+	}
+
+	return applyValidateRules(schema, strings.Split(validateTag, ","))
+}
+
+func applyValidateRules(schema *Schema, rules []string) bool {
+
+	var required = false
+	for i, rule := range rules {
+
+		var name, param, _ = strings.Cut(rule, "=")
+		if name == "required" {
+			required = true
+			continue
+		}
+		if schema.Ref != "" {
+			continue
+		}
+
+		switch name {
+		case "dive":
+			if schema.Items != nil {
+				applyValidateRules(schema.Items, rules[i+1:])
+			}
+			return required
+		case "oneof":
+			schema.Enum = toEnumValues(schema, strings.Fields(param))
+		case "min", "gte":
+			applyLowerBound(schema, param, false)
+		case "max", "lte":
+			applyUpperBound(schema, param, false)
+		case "gt":
+			applyLowerBound(schema, param, true)
+		case "lt":
+			applyUpperBound(schema, param, true)
+		case "len":
+			applyLowerBound(schema, param, false)
+			applyUpperBound(schema, param, false)
+		case "email":
+			schema.Format = "email"
+		case "url", "http_url", "uri":
+			schema.Format = "uri"
+		case "uuid":
+			schema.Format = "uuid"
+		}
+	}
+
+	return required
+}
+
+// applyLowerBound documents a minimum as the minimum length of strings, the minimum items of arrays or the
+// minimum of numbers, as validated by the min, gte and gt rules.
+func applyLowerBound(schema *Schema, param string, exclusive bool) {
+
+	var bound, err = strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		schema.MinLength = toLengthBound(bound, exclusive, 1)
+	case "array":
+		schema.MinItems = toLengthBound(bound, exclusive, 1)
+	case "integer", "number":
+		schema.Minimum = &bound
+		schema.ExclusiveMinimum = exclusive
+	}
+}
+
+// applyUpperBound documents a maximum as the maximum length of strings, the maximum items of arrays or the
+// maximum of numbers, as validated by the max, lte and lt rules.
+func applyUpperBound(schema *Schema, param string, exclusive bool) {
+
+	var bound, err = strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		schema.MaxLength = toLengthBound(bound, exclusive, -1)
+	case "array":
+		schema.MaxItems = toLengthBound(bound, exclusive, -1)
+	case "integer", "number":
+		schema.Maximum = &bound
+		schema.ExclusiveMaximum = exclusive
+	}
+}
+
+func toLengthBound(bound float64, exclusive bool, exclusiveOffset int) *int {
+	var length = int(bound)
+	if exclusive {
+		length += exclusiveOffset
+	}
+	return &length
+}
+
+// toEnumValues converts the values of a oneof rule to the type of the schema, as the enum of numbers must
+// list numbers.
+func toEnumValues(schema *Schema, values []string) []any {
+
+	var enum = make([]any, len(values))
+	for i, value := range values {
+		enum[i] = value
+		if schema.Type != "integer" && schema.Type != "number" {
+			continue
+		}
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			enum[i] = number
+		}
+	}
+
+	return enum
+}
+
+func buildSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSchemaItemDTS struct {
+	Name string `json:"name" validate:"required,max=40"`
+}
+
+type testSchemaDTS struct {
+	Id          *int64               `json:"id"`
+	Name        string               `json:"name" validate:"required,max=100"`
+	Kind        string               `json:"kind" validate:"omitempty,oneof=A B"`
+	Weight      int                  `json:"weight" validate:"gte=1,lt=10"`
+	Price       decimal.Decimal      `json:"price"`
+	ObservedAt  *time.Time           `json:"observedAt"`
+	Items       []*testSchemaItemDTS `json:"items" validate:"required,min=1,dive,required"`
+	Params      json.RawMessage      `json:"params"`
+	Tags        map[string]string    `json:"tags"`
+	Parent      *testSchemaDTS       `json:"parent"`
+	Ignored     string               `json:"-"`
+	notExported string
+}
+
+type testSchemaQueryDTS struct {
+	Status string     `form:"status" json:"status" validate:"omitempty,oneof=OPEN CLOSED"`
+	From   *time.Time `form:"from" json:"from"`
+	Limit  int        `form:"limit" json:"limit" validate:"required,min=0,max=1000"`
+}
+
+func TestSchemaOfStructDescribesFieldsAndValidationRules(t *testing.T) {
+
+	var registry = buildSchemaRegistry()
+
+	var schema = registry.schemaOf(reflect.TypeFor[[]*testSchemaDTS]())
+	assert.Equal(t, "array", schema.Type)
+	assert.Equal(t, componentSchemaRefPrefix+"testSchemaDTS", schema.Items.Ref)
+
+	var componentSchema = registry.schemas["testSchemaDTS"]
+	require.NotNil(t, componentSchema)
+	assert.Equal(t, []string{"name", "items"}, componentSchema.Required)
+	assert.NotContains(t, componentSchema.Properties, "Ignored")
+	assert.NotContains(t, componentSchema.Properties, "notExported")
+
+	var properties = componentSchema.Properties
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64", Nullable: true}, properties["id"])
+	assert.Equal(t, "string", properties["name"].Type)
+	assert.Equal(t, 100, *properties["name"].MaxLength)
+	assert.Equal(t, []any{"A", "B"}, properties["kind"].Enum)
+	assert.Equal(t, 1.0, *properties["weight"].Minimum)
+	assert.False(t, properties["weight"].ExclusiveMinimum)
+	assert.Equal(t, 10.0, *properties["weight"].Maximum)
+	assert.True(t, properties["weight"].ExclusiveMaximum)
+	assert.Equal(t, &Schema{Type: "string", Format: "decimal"}, properties["price"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time", Nullable: true}, properties["observedAt"])
+	assert.Equal(t, 1, *properties["items"].MinItems)
+	assert.Equal(t, componentSchemaRefPrefix+"testSchemaItemDTS", properties["items"].Items.Ref)
+	assert.Equal(t, &Schema{}, properties["params"])
+	assert.Equal(t, &Schema{Type: "string"}, properties["tags"].AdditionalProperties)
+	assert.Equal(t, componentSchemaRefPrefix+"testSchemaDTS", properties["parent"].Ref)
+
+	var itemSchema = registry.schemas["testSchemaItemDTS"]
+	require.NotNil(t, itemSchema)
+	assert.Equal(t, []string{"name"}, itemSchema.Required)
+	assert.Equal(t, 40, *itemSchema.Properties["name"].MaxLength)
+}
+
+func TestQueryParametersDescribesFormFields(t *testing.T) {
+
+	var parameters = buildSchemaRegistry().queryParameters(reflect.TypeFor[testSchemaQueryDTS]())
+
+	require.Len(t, parameters, 3)
+	assert.Equal(t, "status", parameters[0].Name)
+	assert.Equal(t, "query", parameters[0].In)
+	assert.False(t, parameters[0].Required)
+	assert.Equal(t, []any{"OPEN", "CLOSED"}, parameters[0].Schema.Enum)
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, parameters[1].Schema)
+	assert.Equal(t, "limit", parameters[2].Name)
+	assert.True(t, parameters[2].Required)
+	assert.Equal(t, 0.0, *parameters[2].Schema.Minimum)
+	assert.Equal(t, 1000.0, *parameters[2].Schema.Maximum)
+}
@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"embed"
+)
+
+// SwaggerUIHTML is the Swagger UI page browsing the OpenAPI document served as openapi.json next to it. The
+// page and its assets are embedded in the binary and served by the application, loading nothing from other
+// origins.
+//
+//go:embed swagger_ui.html
+var SwaggerUIHTML []byte
+
+// swaggerUIFiles holds the Swagger UI assets vendored from the swagger-ui-dist npm package, at the version
+// pinned in swagger-ui-dist/VERSION, and the script initializing the page.
+//
+//go:embed swagger-ui-dist swagger_ui_init.js
+var swaggerUIFiles embed.FS
+
+// swaggerUIAssetPaths maps the names of the assets referenced by the Swagger UI page to their embedded files.
+var swaggerUIAssetPaths = map[string]string{
+	"swagger-ui.css":       "swagger-ui-dist/swagger-ui.css",
+	"swagger-ui-bundle.js": "swagger-ui-dist/swagger-ui-bundle.js",
+	"swagger-ui-init.js":   "swagger_ui_init.js",
+}
+
+// FindSwaggerUIAsset retrieves an asset referenced by the Swagger UI page.
+//
+// Returns:
+//   - []byte: the content of the asset
+//   - bool: false when the name is not an asset of the page, or the asset is not vendored
+func FindSwaggerUIAsset(name string) ([]byte, bool) {
+
+	var assetPath, ok = swaggerUIAssetPaths[name]
+	if !ok {
+		return nil, false
+	}
+
+	content, err := swaggerUIFiles.ReadFile(assetPath)
+	return content, err == nil
+}
+
+// IsSwaggerUIVendored reports whether all the assets referenced by the Swagger UI page are embedded. The
+// swagger-ui-dist assets are vendored with `make vendor-swagger-ui`.
+func IsSwaggerUIVendored() bool {
+	for name := range swaggerUIAssetPaths {
+		if _, ok := FindSwaggerUIAsset(name); !ok {
+			return false
+		}
+	}
+	return true
+}
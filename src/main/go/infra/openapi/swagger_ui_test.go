@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSwaggerUIAssetOnlyFindsAssetsOfThePage(t *testing.T) {
+
+	content, found := FindSwaggerUIAsset("swagger-ui-init.js")
+	require.True(t, found)
+	assert.Contains(t, string(content), `url: "openapi.json"`)
+
+	_, found = FindSwaggerUIAsset("VERSION")
+	assert.False(t, found)
+
+	_, found = FindSwaggerUIAsset("../swagger_ui.go")
+	assert.False(t, found)
+}
+
+func TestSwaggerUIHTMLOnlyReferencesEmbeddedAssets(t *testing.T) {
+
+	var page = string(SwaggerUIHTML)
+
+	assert.NotContains(t, page, "://")
+	for assetName := range swaggerUIAssetPaths {
+		assert.Contains(t, page, `"docs/assets/`+assetName+`"`)
+	}
+}
@@ -6,11 +6,11 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	dbx "github.com/go-ozzo/ozzo-dbx"
-	"github.com/golang/glog"
 	"github.com/lib/pq"
 
 	"github.com/benizzio/open-asset-allocator/infra"
@@ -32,13 +32,12 @@ type Adapter struct {
 
 func (adapter *Adapter) openPool() {
 
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Opening connection to %s at %s", adapter.config.DriverName, adapter.config.RdbmsURL)
+	slog.Debug("Opening connection", "driver", adapter.config.DriverName, "url", adapter.config.RdbmsURL)
 
 	var connectionError error
 	adapter.connectionPool, connectionError = sql.Open(adapter.config.DriverName, adapter.config.RdbmsURL)
 	if connectionError != nil {
-		glog.Fatal("Error opening database connection: ", connectionError)
+		infra.LogFatal("Error opening database connection", "error", connectionError)
 		return
 	}
 }
@@ -55,36 +54,36 @@ func (adapter *Adapter) buildDBX() {
 }
 
 func (adapter *Adapter) Init() {
-	glog.Infof("Opening connection pool")
+	slog.Info("Opening connection pool")
 	adapter.openPool()
-	glog.Infof("Configuring connection pool")
+	slog.Info("Configuring connection pool")
 	adapter.configPool()
-	glog.Infof("Configuring ozzo-dbx enhancer")
+	slog.Info("Configuring ozzo-dbx enhancer")
 	adapter.buildDBX()
 }
 
 func (adapter *Adapter) Stop() {
 	err := adapter.connectionPool.Close()
 	if err != nil {
-		glog.Fatal("Error closing database connection: ", err)
+		infra.LogFatal("Error closing database connection", "error", err)
 		return
 	}
 }
 
 func (adapter *Adapter) Ping() {
 
-	glog.Info("Pinging database to test connection")
+	slog.Info("Pinging database to test connection")
 
 	pingContext, cancel := buildPingContext()
 	defer cancel()
 
 	err := adapter.connectionPool.PingContext(pingContext)
 	if err != nil {
-		glog.Fatal("Error pinging database connection: ", err)
+		infra.LogFatal("Error pinging database connection", "error", err)
 		return
 	}
 
-	glog.Info("Ping successful!")
+	slog.Info("Ping successful!")
 }
 
 func buildPingContext() (context.Context, context.CancelFunc) {
@@ -92,7 +91,7 @@ func buildPingContext() (context.Context, context.CancelFunc) {
 	go func() {
 		<-pingContext.Done()
 		if errors.Is(pingContext.Err(), context.DeadlineExceeded) {
-			glog.Fatal("Error pinging database connection: timeout")
+			infra.LogFatal("Error pinging database connection: timeout")
 		}
 	}()
 	return pingContext, cancel
@@ -113,8 +112,17 @@ func (adapter *Adapter) getDBX() *dbx.DB {
 func (adapter *Adapter) RunInTransaction(
 	transactionalFunction func(transContext *SQLTransactionalContext) error,
 ) error {
+	return adapter.RunInTransactionWithContext(context.Background(), transactionalFunction)
+}
+
+// RunInTransactionWithContext runs the function in a transaction whose context derives from the parent
+// context, so values of the parent, like the actor of a request, are available to the transactional code.
+func (adapter *Adapter) RunInTransactionWithContext(
+	parentContext context.Context,
+	transactionalFunction func(transContext *SQLTransactionalContext) error,
+) error {
 
-	transContext, err := adapter.buildTransactionalContext()
+	transContext, err := adapter.buildTransactionalContext(parentContext)
 	if err != nil {
 		return err
 	}
@@ -124,8 +132,10 @@ func (adapter *Adapter) RunInTransaction(
 	return adapter.runInTransaction(transContext, transactionalFunction)
 }
 
-func (adapter *Adapter) buildTransactionalContext() (*SQLTransactionalContext, error) {
-	var transactionContext, err = withTransaction(adapter.connectionPool)
+func (adapter *Adapter) buildTransactionalContext(
+	parentContext context.Context,
+) (*SQLTransactionalContext, error) {
+	var transactionContext, err = withTransaction(parentContext, adapter.connectionPool)
 	if err != nil {
 		return nil, err
 	}
@@ -137,10 +147,10 @@ func (adapter *Adapter) recoverPanicInTransaction(
 ) {
 	if r := recover(); r != nil {
 
-		glog.Errorf("Recovered from panic during transactional operation: %v", r)
+		slog.ErrorContext(transContext, "Recovered from panic during transactional operation", "panic", r)
 		var transaction = transContext.GetTransaction()
 		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
-			glog.Errorf("Transaction rollback failed: %v", rollbackErr)
+			slog.ErrorContext(transContext, "Transaction rollback failed", "error", rollbackErr)
 		}
 
 		panic(r)
@@ -169,31 +179,32 @@ func (adapter *Adapter) runInTransaction(
 }
 
 func (adapter *Adapter) Insert(model interface{}) error {
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Inserting model %T", model)
+	slog.Debug("Inserting model", "model", fmt.Sprintf("%T", model))
 	return adapter.dbx.Model(model).Insert()
 }
 
 func (adapter *Adapter) UpdateListedFields(model interface{}, fields ...string) error {
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Updating model %T with fields %v", model, fields)
+	slog.Debug("Updating model", "model", fmt.Sprintf("%T", model), "fields", fields)
 	return adapter.dbx.Model(model).Update(fields...)
 }
 
 func (adapter *Adapter) Read(model interface{}, id any) error {
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Reading model %T with id %v", model, id)
+	slog.Debug("Reading model", "model", fmt.Sprintf("%T", model), "id", id)
 	return adapter.dbx.Select().Model(id, model)
 }
 
+func (adapter *Adapter) Delete(model interface{}) error {
+	slog.Debug("Deleting model", "model", fmt.Sprintf("%T", model))
+	return adapter.dbx.Model(model).Delete()
+}
+
 func (adapter *Adapter) ExecuteInTransaction(
 	transContext *SQLTransactionalContext,
 	sql string,
 	params ...any,
 ) (sql.Result, error) {
 	var transaction = transContext.GetTransaction()
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Executing statement in transaction %s \n with params %s", sql, params)
+	slog.DebugContext(transContext, "Executing statement in transaction", "sql", sql, "params", params)
 	return transaction.Exec(sql, processParamsForPostgreSQL(params...)...)
 }
 
@@ -206,7 +217,7 @@ func (adapter *Adapter) InsertBulkInTransaction(
 
 	var transaction = transContext.GetTransaction()
 
-	statement, err := createBulkInsertPreparedStatement(tableName, columns, transaction)
+	statement, err := createBulkInsertPreparedStatement(transContext, tableName, columns, transaction)
 	if err != nil {
 		return err
 	}
@@ -214,21 +225,25 @@ func (adapter *Adapter) InsertBulkInTransaction(
 	defer func(statement *sql.Stmt) {
 		err := statement.Close()
 		if err != nil {
-			glog.Errorf("Error closing prepared statement: %v", err)
+			slog.ErrorContext(transContext, "Error closing prepared statement", "error", err)
 		}
 	}(statement)
 
-	return executeBulkInsertPreparedStatement(statement, values)
+	return executeBulkInsertPreparedStatement(transContext, statement, values)
 }
 
-func createBulkInsertPreparedStatement(tableName string, columns []string, transaction *sql.Tx) (*sql.Stmt, error) {
+func createBulkInsertPreparedStatement(
+	transContext *SQLTransactionalContext,
+	tableName string,
+	columns []string,
+	transaction *sql.Tx,
+) (*sql.Stmt, error) {
 	var copyInSQL = fmt.Sprintf(
 		"COPY %s (%s) FROM STDIN",
 		pq.QuoteIdentifier(tableName),
 		strings.Join(quoteIdentifiers(columns), ", "),
 	)
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Preparing statement in transaction %s", copyInSQL)
+	slog.DebugContext(transContext, "Preparing statement in transaction", "sql", copyInSQL)
 	return transaction.Prepare(copyInSQL)
 }
 
@@ -245,10 +260,13 @@ func quoteIdentifiers(identifiers []string) []string {
 // serialized values as regular SQL execution.
 //
 // Co-authored by: OpenCode and Igor Benicio de Mesquita
-func executeBulkInsertPreparedStatement(copyStatement *sql.Stmt, values [][]any) error {
+func executeBulkInsertPreparedStatement(
+	transContext *SQLTransactionalContext,
+	copyStatement *sql.Stmt,
+	values [][]any,
+) error {
 
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Executing statement in transaction with values %s", values)
+	slog.DebugContext(transContext, "Executing statement in transaction", "values", values)
 
 	var err error
 	for _, value := range values {
@@ -317,6 +335,7 @@ func BuildDatabaseAdapter(config *infra.Configuration) *Adapter {
 func BuildQuery[T any](adapter RepositoryRDBMSAdapter, querySQL string) *QueryBuilder[T] {
 	return &QueryBuilder[T]{
 		dbx:          adapter.getDBX(),
+		context:      context.Background(),
 		querySQL:     querySQL,
 		params:       dbx.Params{},
 		whereClauses: make([]string, 0),
@@ -328,6 +347,7 @@ func BuildQueryInTransaction[T any](
 	sql string,
 ) *SQLTransactionalQueryBuilder[T] {
 	return &SQLTransactionalQueryBuilder[T]{
+		context:      transContext,
 		transaction:  transContext.GetTransaction(),
 		querySQL:     sql,
 		params:       make([]any, 0),
@@ -344,6 +364,7 @@ type RepositoryRDBMSAdapter interface {
 	Insert(model interface{}) error
 	UpdateListedFields(model interface{}, fields ...string) error
 	Read(model interface{}, id any) error
+	Delete(model interface{}) error
 	ExecuteInTransaction(transContext *SQLTransactionalContext, sql string, params ...any) (sql.Result, error)
 	InsertBulkInTransaction(
 		transContext *SQLTransactionalContext,
@@ -355,4 +376,8 @@ type RepositoryRDBMSAdapter interface {
 
 type TransactionManager interface {
 	RunInTransaction(transactionalFunction func(transContext *SQLTransactionalContext) error) error
+	RunInTransactionWithContext(
+		parentContext context.Context,
+		transactionalFunction func(transContext *SQLTransactionalContext) error,
+	) error
 }
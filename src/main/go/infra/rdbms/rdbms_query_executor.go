@@ -1,10 +1,11 @@
 package rdbms
 
 import (
+	"context"
 	"database/sql"
+	"log/slog"
 
 	dbx "github.com/go-ozzo/ozzo-dbx"
-	"github.com/golang/glog"
 )
 
 // ================================================
@@ -25,6 +26,7 @@ import (
 // Co-authored by: GitHub Copilot and Igor Benicio de Mesquita
 type QueryBuilder[T any] struct {
 	dbx          *dbx.DB
+	context      context.Context
 	querySQL     string
 	whereClauses []string
 	params       dbx.Params
@@ -37,11 +39,18 @@ func (builder *QueryBuilder[T]) Build() *QueryExecutor[T] {
 
 	var processedSQL = processSQL(builder.querySQL, builder.whereClauses)
 
-	var query = builder.dbx.NewQuery(processedSQL)
+	var query = builder.dbx.NewQuery(processedSQL).WithContext(builder.context)
 	var queryExecutor = withParams[T](query, builder.params)
 	return queryExecutor
 }
 
+// WithContext sets the context the query is executed and logged with, like the context of the request it
+// is part of, instead of the background context.
+func (builder *QueryBuilder[T]) WithContext(queryContext context.Context) *QueryBuilder[T] {
+	builder.context = queryContext
+	return builder
+}
+
 func (builder *QueryBuilder[T]) AddParam(name string, value any) *QueryBuilder[T] {
 	builder.params[name] = value
 	return builder
@@ -86,23 +95,29 @@ func withParams[T any](query *dbx.Query, params dbx.Params) *QueryExecutor[T] {
 }
 
 func (executor *QueryExecutor[T]) FindInto(target *[]T) error {
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Executing query %s \n with params %v", executor.query.SQL(), executor.query.Params())
+	executor.logQuery()
 	return executor.query.All(target)
 }
 
 func (executor *QueryExecutor[T]) GetInto(target *T) error {
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Executing query %s \n with params %v", executor.query.SQL(), executor.query.Params())
+	executor.logQuery()
 	return executor.query.One(target)
 }
 
 func (executor *QueryExecutor[T]) GetRows() (*dbx.Rows, error) {
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Executing query %s \n with params %v", executor.query.SQL(), executor.query.Params())
+	executor.logQuery()
 	return executor.query.Rows()
 }
 
+func (executor *QueryExecutor[T]) logQuery() {
+	slog.DebugContext(
+		executor.query.Context(),
+		"Executing query",
+		"sql", executor.query.SQL(),
+		"params", executor.query.Params(),
+	)
+}
+
 // FindWithRowScanner executes the query and maps each result row with the provided scanner.
 //
 // Example:
@@ -121,7 +136,7 @@ func (executor *QueryExecutor[T]) FindWithRowScanner(rowScanner RowScanner[T]) (
 
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			glog.Errorf("Error closing rows: %v", closeErr)
+			slog.ErrorContext(executor.query.Context(), "Error closing rows", "error", closeErr)
 		}
 	}()
 
@@ -130,7 +145,7 @@ func (executor *QueryExecutor[T]) FindWithRowScanner(rowScanner RowScanner[T]) (
 	for rows.Next() {
 		rowValue, scanErr := rowScanner(rows.Rows)
 		if scanErr != nil {
-			glog.Errorf("Error scanning row %d: %v", index, scanErr)
+			slog.ErrorContext(executor.query.Context(), "Error scanning row", "index", index, "error", scanErr)
 			return nil, scanErr
 		}
 
@@ -168,7 +183,7 @@ func (executor *QueryExecutor[T]) GetWithRowScanner(rowScanner RowScanner[T]) (T
 
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			glog.Errorf("Error closing rows: %v", closeErr)
+			slog.ErrorContext(executor.query.Context(), "Error closing rows", "error", closeErr)
 		}
 	}()
 
@@ -1,9 +1,9 @@
 package rdbms
 
 import (
+	"context"
 	"database/sql"
-
-	"github.com/golang/glog"
+	"log/slog"
 )
 
 // ================================================
@@ -11,6 +11,7 @@ import (
 // ================================================
 
 type SQLTransactionalQueryBuilder[T any] struct {
+	context      context.Context
 	transaction  *sql.Tx
 	querySQL     string
 	whereClauses []string
@@ -78,8 +79,12 @@ func (executor *SQLTransactionalQueryExecutor[T]) Find(rowScanner RowScanner[T])
 
 	var builder = executor.queryBuilder
 
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Executing transactional query %s \n with params %v", builder.querySQL, builder.params)
+	slog.DebugContext(
+		builder.context,
+		"Executing transactional query",
+		"sql", builder.querySQL,
+		"params", builder.params,
+	)
 	rows, err := builder.transaction.Query(builder.querySQL, builder.params...)
 	if err != nil {
 		return nil, err
@@ -87,7 +92,7 @@ func (executor *SQLTransactionalQueryExecutor[T]) Find(rowScanner RowScanner[T])
 
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			glog.Errorf("Error closing rows: %v", closeErr)
+			slog.ErrorContext(builder.context, "Error closing rows", "error", closeErr)
 		}
 	}()
 
@@ -97,7 +102,7 @@ func (executor *SQLTransactionalQueryExecutor[T]) Find(rowScanner RowScanner[T])
 
 		rowValue, scanErr := rowScanner(rows)
 		if scanErr != nil {
-			glog.Errorf("Error scanning row %d: %v", index, scanErr)
+			slog.ErrorContext(builder.context, "Error scanning row", "index", index, "error", scanErr)
 			return nil, scanErr
 		}
 
@@ -116,8 +121,12 @@ func (executor *SQLTransactionalQueryExecutor[T]) Get(rowScanner SingleRowScanne
 
 	var builder = executor.queryBuilder
 
-	// TODO verification for debug logging, this should be logged only in debug mode
-	glog.Infof("Executing transactional query %s \n with params %v", builder.querySQL, builder.params)
+	slog.DebugContext(
+		builder.context,
+		"Executing transactional query",
+		"sql", builder.querySQL,
+		"params", builder.params,
+	)
 	var row = builder.transaction.QueryRow(builder.querySQL, builder.params...)
 	return rowScanner(row)
 }
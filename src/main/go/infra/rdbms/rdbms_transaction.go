@@ -17,15 +17,15 @@ func (transactionalContext *SQLTransactionalContext) GetTransaction() *sql.Tx {
 	return transactionalContext.Value(sqlTransactionContextKey).(*sql.Tx)
 }
 
-func withTransaction(db *sql.DB) (*SQLTransactionalContext, error) {
+func withTransaction(parentContext context.Context, db *sql.DB) (*SQLTransactionalContext, error) {
 
 	var transaction, err = db.Begin()
 	if err != nil {
 		return nil, err
 	}
 
-	var parentContext = context.WithValue(context.Background(), sqlTransactionContextKey, transaction)
-	return &SQLTransactionalContext{parentContext}, nil
+	var transactionContext = context.WithValue(parentContext, sqlTransactionContextKey, transaction)
+	return &SQLTransactionalContext{transactionContext}, nil
 }
 
 type TransactionalContext interface {
@@ -2,12 +2,12 @@ package httpclient
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
-
-	"github.com/golang/glog"
 )
 
 const defaultTimeout = 10 * time.Second
@@ -17,7 +17,17 @@ const defaultTimeout = 10 * time.Second
 // other properties without modifying the function signatures for each new requirement.
 //
 // Authored by: GitHub Copilot (claude-opus-4.6)
-type RequestOption func(*http.Request)
+type RequestOption func(*requestSettings)
+
+// requestSettings holds the request modifiers and resilience mechanisms collected from the
+// RequestOptions of a single execution.
+type requestSettings struct {
+	requestModifiers []func(*http.Request)
+	retryPolicy      *RetryPolicy
+	rateLimiter      *HostRateLimiter
+	circuitBreaker   *CircuitBreaker
+	cassette         *Cassette
+}
 
 // WithHeader returns a RequestOption that sets a single header key-value pair on the request.
 // If the header already exists, it is replaced.
@@ -38,15 +48,21 @@ type RequestOption func(*http.Request)
 //
 // Authored by: GitHub Copilot (claude-opus-4.6)
 func WithHeader(key string, value string) RequestOption {
-	return func(request *http.Request) {
-		request.Header.Set(key, value)
+	return func(settings *requestSettings) {
+		settings.requestModifiers = append(
+			settings.requestModifiers,
+			func(request *http.Request) {
+				request.Header.Set(key, value)
+			},
+		)
 	}
 }
 
 // ExecuteGet performs an HTTP GET request to the given URL and validates the response status code.
 // Returns the response if the status code is http.StatusOK. For non-200 responses, the response
 // body is closed before returning the error. Accepts variadic RequestOption functions to customize
-// the request before execution.
+// the request before execution, including retries (WithRetry), rate limiting (WithRateLimiter) and
+// circuit breaking (WithCircuitBreaker).
 //
 // Parameters:
 //   - requestURL: the fully constructed URL to send the GET request to
@@ -69,17 +85,102 @@ func WithHeader(key string, value string) RequestOption {
 // Co-authored by: OpenCode and benizzio
 func ExecuteGet(requestContext context.Context, requestURL string, options ...RequestOption) (*http.Response, error) {
 
+	var settings requestSettings
+	for _, option := range options {
+		option(&settings)
+	}
+
+	for attempt := 0; ; attempt++ {
+
+		var response, err = executeGetAttempt(requestContext, requestURL, &settings)
+
+		var retryPolicy = settings.retryPolicy
+		if retryPolicy == nil || attempt >= retryPolicy.MaxRetries || !isRetryable(requestContext, response, err) {
+			return validateGetResponse(requestURL, response, err)
+		}
+
+		delay, canRetry := retryPolicy.computeDelay(attempt, response, time.Now())
+		if !canRetry {
+			return validateGetResponse(requestURL, response, err)
+		}
+
+		if response != nil {
+			CloseResponseBody(response)
+		}
+
+		slog.WarnContext(
+			requestContext,
+			"Retrying HTTP GET request after failure",
+			"url", requestURL,
+			"delay", delay,
+			"retry", attempt+1,
+			"maxRetries", retryPolicy.MaxRetries,
+			"failure", describeAttemptFailure(response, err),
+		)
+
+		if err = sleepWithContext(requestContext, delay); err != nil {
+			return nil, fmt.Errorf("HTTP GET request to %s interrupted while waiting to retry: %w", requestURL, err)
+		}
+	}
+}
+
+// executeGetAttempt sends a single GET request, going through the rate limiter and circuit breaker
+// when configured. With a replaying cassette the response is served from it instead, and with a
+// recording cassette the exchange is saved to it.
+func executeGetAttempt(
+	requestContext context.Context,
+	requestURL string,
+	settings *requestSettings,
+) (*http.Response, error) {
+
 	var request, err = http.NewRequestWithContext(requestContext, http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating HTTP GET request for %s: %w", requestURL, err)
 	}
 
-	for _, option := range options {
-		option(request)
+	for _, modifier := range settings.requestModifiers {
+		modifier(request)
+	}
+
+	if settings.cassette != nil && settings.cassette.isReplaying() {
+		return settings.cassette.replay(request)
+	}
+
+	if settings.rateLimiter != nil {
+		if err = settings.rateLimiter.Wait(requestContext, request.URL.Host); err != nil {
+			return nil, fmt.Errorf("HTTP GET request to %s interrupted while rate limited: %w", requestURL, err)
+		}
+	}
+
+	if settings.circuitBreaker != nil {
+		if err = settings.circuitBreaker.Allow(); err != nil {
+			return nil, fmt.Errorf("HTTP GET request to %s rejected: %w", requestURL, err)
+		}
 	}
 
 	var client = &http.Client{Timeout: defaultTimeout}
 	response, err := client.Do(request)
+
+	if settings.circuitBreaker != nil {
+		if requestContext.Err() != nil {
+			// cancelled by the caller, the outcome says nothing about the provider
+			settings.circuitBreaker.abandon()
+		} else {
+			settings.circuitBreaker.RecordResult(!isRetryable(requestContext, response, err))
+		}
+	}
+
+	if settings.cassette != nil && err == nil {
+		return settings.cassette.record(request, response)
+	}
+
+	return response, err
+}
+
+// validateGetResponse returns the response of the last attempt if its status code is http.StatusOK.
+// For non-200 responses, the response body is closed before returning the error.
+func validateGetResponse(requestURL string, response *http.Response, err error) (*http.Response, error) {
+
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +193,13 @@ func ExecuteGet(requestContext context.Context, requestURL string, options ...Re
 	return response, nil
 }
 
+func describeAttemptFailure(response *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("status %d", response.StatusCode)
+}
+
 // ExecuteGetJSON performs an HTTP GET request to the given URL, validates the response,
 // decodes the JSON body into the target type T, and closes the response body.
 // This is a convenience function that combines ExecuteGet, DecodeJSONResponse, and
@@ -133,6 +241,41 @@ func ExecuteGetJSON[T any](requestContext context.Context, requestURL string, op
 	return DecodeJSONResponse[T](response)
 }
 
+// ExecuteGetCSV performs an HTTP GET request to the given URL, validates the response, reads the
+// CSV body into records and closes the response body. The first record is the header row when the
+// endpoint returns one. Accepts variadic RequestOption functions to customize the request before
+// execution.
+//
+// Parameters:
+//   - requestURL: the fully constructed URL to send the GET request to
+//   - options: variadic functional options applied to the request before execution
+//
+// Returns:
+//   - [][]string: the CSV records, including the header row
+//   - error: if the request fails, returns a non-200 status, or CSV parsing fails
+//
+// Example:
+//
+//	records, err := httpclient.ExecuteGetCSV(context.Background(), "https://api.example.com/data.csv")
+//	if err != nil {
+//	    // handle error
+//	}
+//	fmt.Println(records[0])
+func ExecuteGetCSV(requestContext context.Context, requestURL string, options ...RequestOption) ([][]string, error) {
+
+	var response, err = ExecuteGet(requestContext, requestURL, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer CloseResponseBody(response)
+
+	var reader = csv.NewReader(response.Body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return reader.ReadAll()
+}
+
 // DecodeJSONResponse decodes the body of an HTTP response into the target type T.
 // Uses json.NewDecoder for stream-based decoding.
 //
@@ -185,6 +328,6 @@ func DecodeJSONResponse[T any](response *http.Response) (*T, error) {
 func CloseResponseBody(response *http.Response) {
 	var err = response.Body.Close()
 	if err != nil {
-		glog.Errorf("Error closing HTTP response body: %v", err)
+		slog.Error("Error closing HTTP response body", "error", err)
 	}
 }
@@ -0,0 +1,266 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrCassetteInteractionNotFound is returned, wrapped, when a replaying Cassette has no recorded
+// interaction for a request.
+var ErrCassetteInteractionNotFound = errors.New("no recorded interaction in cassette")
+
+// recordedResponseHeaders are the response headers kept in cassettes. Other headers, such as cookies,
+// are left out so cassettes can be committed as fixtures.
+var recordedResponseHeaders = []string{"Content-Type", "Retry-After"}
+
+type CassetteMode string
+
+const (
+	// CassetteRecordMode sends the requests to the provider and saves each exchange in the cassette,
+	// replacing the previous recording of the same request.
+	CassetteRecordMode CassetteMode = "RECORD"
+	// CassetteReplayMode serves the requests from the exchanges saved in the cassette, without network
+	// access. Rate limiting and circuit breaking are skipped, since the provider is not reached.
+	CassetteReplayMode CassetteMode = "REPLAY"
+)
+
+// CassetteInteraction is a request to a provider and the response it received, as saved in a
+// cassette file. Requests are identified by method and URL.
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type CassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type CassetteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+type cassetteFile struct {
+	Interactions []*CassetteInteraction `json:"interactions"`
+}
+
+// Cassette records the HTTP exchanges with a provider to a JSON file, or replays them from it. The
+// file is loaded on the first request and, when recording, rewritten after each exchange. A Cassette
+// is safe for concurrent use.
+type Cassette struct {
+	mode         CassetteMode
+	filePath     string
+	mutex        sync.Mutex
+	loaded       bool
+	interactions []*CassetteInteraction
+}
+
+// BuildCassette creates a Cassette in the given mode backed by the given file.
+//
+// Returns:
+//   - *Cassette: the cassette, or nil when mode is empty
+//   - error: if mode is not RECORD or REPLAY, the file path is empty, or the file to replay is not readable
+//
+// Example:
+//
+//	cassette, err := httpclient.BuildCassette(httpclient.CassetteReplayMode, "cassettes/yahoo_finance.json")
+//	response, err := httpclient.ExecuteGet(context.Background(), url, httpclient.WithCassette(cassette))
+func BuildCassette(mode CassetteMode, filePath string) (*Cassette, error) {
+
+	if mode == "" {
+		return nil, nil
+	}
+
+	if mode != CassetteRecordMode && mode != CassetteReplayMode {
+		return nil, fmt.Errorf("invalid cassette mode %s", mode)
+	}
+
+	if filePath == "" {
+		return nil, fmt.Errorf("cassette in %s mode requires a file path", mode)
+	}
+
+	if mode == CassetteReplayMode {
+		if _, err := os.Stat(filePath); err != nil {
+			return nil, fmt.Errorf("cassette to replay is not readable: %w", err)
+		}
+	}
+
+	return &Cassette{mode: mode, filePath: filePath}, nil
+}
+
+// WithCassette returns a RequestOption that records the request exchange to the cassette or replays
+// it from the cassette, according to the cassette mode. A nil cassette is ignored.
+func WithCassette(cassette *Cassette) RequestOption {
+	return func(settings *requestSettings) {
+		if cassette != nil {
+			settings.cassette = cassette
+		}
+	}
+}
+
+func (cassette *Cassette) isReplaying() bool {
+	return cassette.mode == CassetteReplayMode
+}
+
+// replay builds the response to a request from its recorded interaction.
+func (cassette *Cassette) replay(request *http.Request) (*http.Response, error) {
+
+	cassette.mutex.Lock()
+	defer cassette.mutex.Unlock()
+
+	if err := cassette.load(); err != nil {
+		return nil, err
+	}
+
+	var interaction = cassette.findInteraction(request.Method, request.URL.String())
+	if interaction == nil {
+		return nil, fmt.Errorf(
+			"%w %s for %s %s",
+			ErrCassetteInteractionNotFound,
+			cassette.filePath,
+			request.Method,
+			request.URL.String(),
+		)
+	}
+
+	var recordedResponse = interaction.Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResponse.StatusCode, http.StatusText(recordedResponse.StatusCode)),
+		StatusCode:    recordedResponse.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recordedResponse.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(recordedResponse.Body)),
+		ContentLength: int64(len(recordedResponse.Body)),
+		Request:       request,
+	}, nil
+}
+
+// record saves the exchange of a request in the cassette, returning an equivalent response whose body
+// can still be read by the caller. Failures to save the cassette are logged and do not fail the request.
+func (cassette *Cassette) record(request *http.Request, response *http.Response) (*http.Response, error) {
+
+	var body, err = io.ReadAll(response.Body)
+	CloseResponseBody(response)
+	if err != nil {
+		return nil, fmt.Errorf("error reading HTTP response of %s to record it: %w", request.URL.String(), err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	var header = make(http.Header)
+	for _, headerName := range recordedResponseHeaders {
+		if values := response.Header.Values(headerName); len(values) > 0 {
+			header[headerName] = values
+		}
+	}
+
+	var interaction = &CassetteInteraction{
+		Request:  CassetteRequest{Method: request.Method, URL: request.URL.String()},
+		Response: CassetteResponse{StatusCode: response.StatusCode, Header: header, Body: string(body)},
+	}
+
+	cassette.mutex.Lock()
+	defer cassette.mutex.Unlock()
+
+	if err = cassette.load(); err != nil {
+		slog.ErrorContext(
+			request.Context(),
+			"Error loading cassette to record",
+			"url", request.URL.String(),
+			"error", err,
+		)
+		return response, nil
+	}
+
+	cassette.putInteraction(interaction)
+
+	if err = cassette.save(); err != nil {
+		slog.ErrorContext(
+			request.Context(),
+			"Error saving cassette after recording",
+			"url", request.URL.String(),
+			"error", err,
+		)
+	}
+
+	return response, nil
+}
+
+func (cassette *Cassette) findInteraction(method string, url string) *CassetteInteraction {
+	for _, interaction := range cassette.interactions {
+		if interaction.Request.Method == method && interaction.Request.URL == url {
+			return interaction
+		}
+	}
+	return nil
+}
+
+func (cassette *Cassette) putInteraction(interaction *CassetteInteraction) {
+	for index, existing := range cassette.interactions {
+		if existing.Request == interaction.Request {
+			cassette.interactions[index] = interaction
+			return
+		}
+	}
+	cassette.interactions = append(cassette.interactions, interaction)
+}
+
+// load reads the cassette file once. A missing file is an empty cassette when recording, and an error
+// when replaying.
+func (cassette *Cassette) load() error {
+
+	if cassette.loaded {
+		return nil
+	}
+
+	var content, err = os.ReadFile(cassette.filePath)
+	if errors.Is(err, os.ErrNotExist) && !cassette.isReplaying() {
+		cassette.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading cassette %s: %w", cassette.filePath, err)
+	}
+
+	var file cassetteFile
+	if err = json.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("error parsing cassette %s: %w", cassette.filePath, err)
+	}
+
+	cassette.interactions = file.Interactions
+	cassette.loaded = true
+	return nil
+}
+
+// save rewrites the cassette file through a temporary file, so an interrupted save does not leave a
+// corrupted cassette.
+func (cassette *Cassette) save() error {
+
+	var content, err = json.MarshalIndent(cassetteFile{Interactions: cassette.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(cassette.filePath), 0o755); err != nil {
+		return err
+	}
+
+	var temporaryFilePath = cassette.filePath + ".tmp"
+	if err = os.WriteFile(temporaryFilePath, content, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryFilePath, cassette.filePath)
+}
@@ -0,0 +1,109 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cassetteTestQuote struct {
+	Symbol string  `json:"symbol"`
+	Price  float64 `json:"price"`
+}
+
+func TestCassette_ReplaysRecordedExchangesOffline(t *testing.T) {
+
+	var requestCount atomic.Int32
+	var server = httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				requestCount.Add(1)
+				writer.Header().Set("Content-Type", "application/json")
+				writer.Header().Set("Set-Cookie", "session=secret")
+				_, _ = writer.Write([]byte(`{"symbol": "` + request.URL.Query().Get("symbol") + `", "price": 10.5}`))
+			},
+		),
+	)
+	var quoteURL = server.URL + "/quote?symbol=AAPL"
+	var cassetteFilePath = filepath.Join(t.TempDir(), "cassettes", "provider.json")
+
+	var recordingCassette, err = BuildCassette(CassetteRecordMode, cassetteFilePath)
+	require.NoError(t, err)
+
+	recordedQuote, err := ExecuteGetJSON[cassetteTestQuote](
+		context.Background(),
+		quoteURL,
+		WithCassette(recordingCassette),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, cassetteTestQuote{Symbol: "AAPL", Price: 10.5}, *recordedQuote)
+	server.Close()
+
+	replayingCassette, err := BuildCassette(CassetteReplayMode, cassetteFilePath)
+	require.NoError(t, err)
+
+	response, err := ExecuteGet(context.Background(), quoteURL, WithCassette(replayingCassette))
+	require.NoError(t, err)
+	defer CloseResponseBody(response)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	assert.Empty(t, response.Header.Get("Set-Cookie"))
+
+	replayedQuote, err := DecodeJSONResponse[cassetteTestQuote](response)
+	require.NoError(t, err)
+	assert.Equal(t, *recordedQuote, *replayedQuote)
+	assert.Equal(t, int32(1), requestCount.Load())
+}
+
+func TestCassette_ReplayFailsForUnrecordedRequest(t *testing.T) {
+
+	var server, requestCount = buildSequenceServer(t, nil, http.StatusOK)
+	var cassetteFilePath = filepath.Join(t.TempDir(), "provider.json")
+
+	var recordingCassette, err = BuildCassette(CassetteRecordMode, cassetteFilePath)
+	require.NoError(t, err)
+	response, err := ExecuteGet(context.Background(), server.URL+"/recorded", WithCassette(recordingCassette))
+	require.NoError(t, err)
+	CloseResponseBody(response)
+
+	replayingCassette, err := BuildCassette(CassetteReplayMode, cassetteFilePath)
+	require.NoError(t, err)
+
+	_, err = ExecuteGet(
+		context.Background(),
+		server.URL+"/unrecorded",
+		WithCassette(replayingCassette),
+		WithRetry(fastRetryPolicy),
+	)
+
+	assert.True(t, errors.Is(err, ErrCassetteInteractionNotFound), err)
+	assert.Equal(t, int32(1), requestCount.Load())
+}
+
+func TestBuildCassette(t *testing.T) {
+
+	var cassette, err = BuildCassette("", "provider.json")
+	assert.NoError(t, err)
+	assert.Nil(t, cassette)
+
+	_, err = BuildCassette("PLAYBACK", "provider.json")
+	assert.EqualError(t, err, "invalid cassette mode PLAYBACK")
+
+	_, err = BuildCassette(CassetteReplayMode, "")
+	assert.EqualError(t, err, "cassette in REPLAY mode requires a file path")
+
+	var missingFilePath = filepath.Join(t.TempDir(), "missing.json")
+	_, err = BuildCassette(CassetteReplayMode, missingFilePath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	cassette, err = BuildCassette(CassetteRecordMode, missingFilePath)
+	assert.NoError(t, err)
+	assert.NotNil(t, cassette)
+}
@@ -0,0 +1,330 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped, when a request is rejected by an open CircuitBreaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// ================================================
+// RETRY
+// ================================================
+
+// RetryPolicy configures the retries of a request on transport errors and on 429 and 5xx responses.
+// Delays grow exponentially from BaseDelay up to MaxDelay, with jitter. A Retry-After header in the
+// response takes precedence over the computed delay, and the request is not retried when it asks
+// for a delay longer than MaxDelay.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// WithRetry returns a RequestOption that retries the request according to the given policy.
+// A policy with MaxRetries lower than 1 disables retries.
+//
+// Example:
+//
+//	response, err := httpclient.ExecuteGet(context.Background(), url,
+//	    httpclient.WithRetry(httpclient.RetryPolicy{MaxRetries: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second}),
+//	)
+func WithRetry(policy RetryPolicy) RequestOption {
+	return func(settings *requestSettings) {
+		if policy.MaxRetries > 0 {
+			settings.retryPolicy = &policy
+		}
+	}
+}
+
+// isRetryable reports whether a request attempt failed in a way that may succeed when retried.
+// Rejections by an open circuit, requests missing from a replaying cassette and errors caused by the
+// cancellation or deadline of the request context are not retryable.
+func isRetryable(requestContext context.Context, response *http.Response, err error) bool {
+
+	if err != nil {
+		return requestContext.Err() == nil &&
+			!errors.Is(err, ErrCircuitOpen) &&
+			!errors.Is(err, ErrCassetteInteractionNotFound)
+	}
+
+	return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError
+}
+
+// computeDelay returns the delay before the retry following the given attempt (zero-based), and false
+// when the response asks for a longer delay than the policy allows.
+func (policy *RetryPolicy) computeDelay(attempt int, response *http.Response, now time.Time) (time.Duration, bool) {
+
+	if retryAfter, ok := parseRetryAfter(response, now); ok {
+		if policy.MaxDelay > 0 && retryAfter > policy.MaxDelay {
+			return 0, false
+		}
+		return retryAfter, true
+	}
+
+	var delay = policy.BaseDelay << attempt
+	if delay <= 0 || (policy.MaxDelay > 0 && delay > policy.MaxDelay) {
+		delay = policy.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0, true
+	}
+
+	// equal jitter: half of the delay is fixed and the other half random, to spread concurrent retries
+	var halfDelay = delay / 2
+	return halfDelay + rand.N(delay-halfDelay+1), true
+}
+
+// parseRetryAfter reads the Retry-After header of a response, in delay-seconds or HTTP-date format.
+func parseRetryAfter(response *http.Response, now time.Time) (time.Duration, bool) {
+
+	if response == nil {
+		return 0, false
+	}
+
+	var retryAfterValue = response.Header.Get("Retry-After")
+	if retryAfterValue == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(retryAfterValue); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if retryAfterDate, err := http.ParseTime(retryAfterValue); err == nil {
+		var delay = retryAfterDate.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// sleepWithContext waits for the given duration, returning early with the context error when the
+// context is done.
+func sleepWithContext(requestContext context.Context, duration time.Duration) error {
+
+	if duration <= 0 {
+		return requestContext.Err()
+	}
+
+	var timer = time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-requestContext.Done():
+		return requestContext.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ================================================
+// RATE LIMITING
+// ================================================
+
+// HostRateLimiter is a token bucket rate limiter with a separate bucket per request host. Each bucket
+// holds up to burst tokens and refills at ratePerSecond tokens per second. It is safe for concurrent
+// use and is meant to be shared by every request to the same provider.
+type HostRateLimiter struct {
+	ratePerSecond float64
+	burst         float64
+	mutex         sync.Mutex
+	buckets       map[string]*tokenBucket
+	now           func() time.Time
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// WithRateLimiter returns a RequestOption that waits for a token of the request host in the given
+// limiter before each attempt. A nil limiter disables rate limiting.
+func WithRateLimiter(limiter *HostRateLimiter) RequestOption {
+	return func(settings *requestSettings) {
+		settings.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a token is available for the given host, or the context is done.
+func (limiter *HostRateLimiter) Wait(requestContext context.Context, host string) error {
+	for {
+		var delay = limiter.reserve(host)
+		if delay <= 0 {
+			return nil
+		}
+
+		if err := sleepWithContext(requestContext, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token from the host bucket when one is available, or returns how long to wait for
+// the next one.
+func (limiter *HostRateLimiter) reserve(host string) time.Duration {
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	var now = limiter.now()
+	var bucket, exists = limiter.buckets[host]
+	if !exists {
+		bucket = &tokenBucket{tokens: limiter.burst, lastRefill: now}
+		limiter.buckets[host] = bucket
+	}
+
+	var elapsedSeconds = now.Sub(bucket.lastRefill).Seconds()
+	bucket.tokens = min(limiter.burst, bucket.tokens+elapsedSeconds*limiter.ratePerSecond)
+	bucket.lastRefill = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+
+	var missingTokens = 1 - bucket.tokens
+	return time.Duration(missingTokens / limiter.ratePerSecond * float64(time.Second))
+}
+
+// BuildHostRateLimiter creates a HostRateLimiter allowing ratePerSecond requests per second to each
+// host, with bursts of up to burst requests. Returns nil, disabling rate limiting, when ratePerSecond
+// is not positive.
+//
+// Example:
+//
+//	var limiter = httpclient.BuildHostRateLimiter(2, 5)
+//	response, err := httpclient.ExecuteGet(context.Background(), url, httpclient.WithRateLimiter(limiter))
+func BuildHostRateLimiter(ratePerSecond float64, burst int) *HostRateLimiter {
+
+	if ratePerSecond <= 0 {
+		return nil
+	}
+
+	return &HostRateLimiter{
+		ratePerSecond: ratePerSecond,
+		burst:         float64(max(burst, 1)),
+		buckets:       make(map[string]*tokenBucket),
+		now:           time.Now,
+	}
+}
+
+// ================================================
+// CIRCUIT BREAKER
+// ================================================
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker stops sending requests to a failing provider. After failureThreshold consecutive
+// failed requests it opens, rejecting requests with ErrCircuitOpen for openDuration. Then it lets a
+// single trial request through: success closes the circuit and failure opens it again.
+// It is safe for concurrent use and is meant to be shared by every request to the same provider.
+type CircuitBreaker struct {
+	failureThreshold    int
+	openDuration        time.Duration
+	mutex               sync.Mutex
+	state               circuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	now                 func() time.Time
+}
+
+// WithCircuitBreaker returns a RequestOption that guards the request with the given circuit breaker.
+// A nil breaker disables it.
+func WithCircuitBreaker(breaker *CircuitBreaker) RequestOption {
+	return func(settings *requestSettings) {
+		settings.circuitBreaker = breaker
+	}
+}
+
+// Allow reports whether a request may be sent, moving an open circuit to half-open once its open
+// duration has elapsed.
+//
+// Returns:
+//   - error: ErrCircuitOpen when the circuit is open or a half-open trial is already in progress
+func (breaker *CircuitBreaker) Allow() error {
+
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	switch breaker.state {
+	case circuitOpen:
+		if breaker.now().Sub(breaker.openedAt) < breaker.openDuration {
+			return ErrCircuitOpen
+		}
+		breaker.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+// RecordResult updates the circuit with the outcome of an allowed request.
+func (breaker *CircuitBreaker) RecordResult(success bool) {
+
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	if success {
+		breaker.state = circuitClosed
+		breaker.consecutiveFailures = 0
+		return
+	}
+
+	breaker.consecutiveFailures++
+	if breaker.state == circuitHalfOpen || breaker.consecutiveFailures >= breaker.failureThreshold {
+		breaker.state = circuitOpen
+		breaker.openedAt = breaker.now()
+	}
+}
+
+// abandon releases a half-open trial whose request was cancelled before completion, letting the next
+// request be the trial instead.
+func (breaker *CircuitBreaker) abandon() {
+
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	if breaker.state == circuitHalfOpen {
+		breaker.state = circuitOpen
+	}
+}
+
+// BuildCircuitBreaker creates a CircuitBreaker that opens after failureThreshold consecutive failures
+// for openDuration. Returns nil, disabling the breaker, when failureThreshold is not positive.
+//
+// Example:
+//
+//	var breaker = httpclient.BuildCircuitBreaker(5, 30*time.Second)
+//	response, err := httpclient.ExecuteGet(context.Background(), url, httpclient.WithCircuitBreaker(breaker))
+func BuildCircuitBreaker(failureThreshold int, openDuration time.Duration) *CircuitBreaker {
+
+	if failureThreshold <= 0 {
+		return nil
+	}
+
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+		state:            circuitClosed,
+		now:              time.Now,
+	}
+}
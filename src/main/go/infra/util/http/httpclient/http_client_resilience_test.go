@@ -0,0 +1,262 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildSequenceServer creates a test server answering each request with the next status code of the
+// sequence, repeating the last one, and counting the requests received.
+func buildSequenceServer(t *testing.T, headers http.Header, statusCodes ...int) (*httptest.Server, *atomic.Int32) {
+
+	var requestCount atomic.Int32
+	var server = httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, _ *http.Request) {
+				var index = int(requestCount.Add(1)) - 1
+				for key, values := range headers {
+					writer.Header()[key] = values
+				}
+				writer.WriteHeader(statusCodes[min(index, len(statusCodes)-1)])
+			},
+		),
+	)
+	t.Cleanup(server.Close)
+
+	return server, &requestCount
+}
+
+var fastRetryPolicy = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestExecuteGet_RetriesRetryableStatuses(t *testing.T) {
+
+	var server, requestCount = buildSequenceServer(
+		t,
+		nil,
+		http.StatusTooManyRequests,
+		http.StatusServiceUnavailable,
+		http.StatusOK,
+	)
+
+	var response, err = ExecuteGet(context.Background(), server.URL, WithRetry(fastRetryPolicy))
+
+	require.NoError(t, err)
+	defer CloseResponseBody(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int32(3), requestCount.Load())
+}
+
+func TestExecuteGet_StopsRetryingAfterMaxRetries(t *testing.T) {
+
+	var server, requestCount = buildSequenceServer(t, nil, http.StatusBadGateway)
+
+	var _, err = ExecuteGet(context.Background(), server.URL, WithRetry(fastRetryPolicy))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "returned status 502")
+	assert.Equal(t, int32(4), requestCount.Load())
+}
+
+func TestExecuteGet_DoesNotRetryClientErrors(t *testing.T) {
+
+	var server, requestCount = buildSequenceServer(t, nil, http.StatusNotFound)
+
+	var _, err = ExecuteGet(context.Background(), server.URL, WithRetry(fastRetryPolicy))
+
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requestCount.Load())
+}
+
+func TestExecuteGet_DoesNotRetryWithoutPolicy(t *testing.T) {
+
+	var server, requestCount = buildSequenceServer(t, nil, http.StatusServiceUnavailable, http.StatusOK)
+
+	var _, err = ExecuteGet(context.Background(), server.URL)
+
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requestCount.Load())
+}
+
+func TestExecuteGet_GivesUpWhenRetryAfterExceedsMaxDelay(t *testing.T) {
+
+	var server, requestCount = buildSequenceServer(
+		t,
+		http.Header{"Retry-After": {"120"}},
+		http.StatusTooManyRequests,
+		http.StatusOK,
+	)
+
+	var _, err = ExecuteGet(context.Background(), server.URL, WithRetry(fastRetryPolicy))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "returned status 429")
+	assert.Equal(t, int32(1), requestCount.Load())
+}
+
+func TestExecuteGet_StopsWaitingToRetryWhenContextIsCancelled(t *testing.T) {
+
+	var server, requestCount = buildSequenceServer(t, nil, http.StatusServiceUnavailable)
+	var requestContext, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var _, err = ExecuteGet(
+		requestContext,
+		server.URL,
+		WithRetry(RetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: time.Minute}),
+	)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), requestCount.Load())
+}
+
+func TestRetryPolicy_ComputeDelay(t *testing.T) {
+
+	var policy = RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	var now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("TestRetryPolicy_ComputeDelay_ExponentialWithJitter", func(t *testing.T) {
+		for attempt, expectedDelay := range []time.Duration{
+			100 * time.Millisecond,
+			200 * time.Millisecond,
+			400 * time.Millisecond,
+			800 * time.Millisecond,
+			time.Second,
+		} {
+			var delay, canRetry = policy.computeDelay(attempt, nil, now)
+			assert.True(t, canRetry)
+			assert.GreaterOrEqual(t, delay, expectedDelay/2)
+			assert.LessOrEqual(t, delay, expectedDelay)
+		}
+	})
+
+	t.Run("TestRetryPolicy_ComputeDelay_RetryAfterSeconds", func(t *testing.T) {
+		var response = &http.Response{Header: http.Header{"Retry-After": {"1"}}}
+		var delay, canRetry = policy.computeDelay(0, response, now)
+		assert.True(t, canRetry)
+		assert.Equal(t, time.Second, delay)
+	})
+
+	t.Run("TestRetryPolicy_ComputeDelay_RetryAfterDate", func(t *testing.T) {
+		var response = &http.Response{
+			Header: http.Header{"Retry-After": {now.Add(500 * time.Millisecond).Format(http.TimeFormat)}},
+		}
+		var delay, canRetry = policy.computeDelay(0, response, now)
+		assert.True(t, canRetry)
+		// HTTP dates have second precision
+		assert.Equal(t, time.Duration(0), delay)
+	})
+
+	t.Run("TestRetryPolicy_ComputeDelay_InvalidRetryAfterIsIgnored", func(t *testing.T) {
+		var response = &http.Response{Header: http.Header{"Retry-After": {"soon"}}}
+		var delay, canRetry = policy.computeDelay(0, response, now)
+		assert.True(t, canRetry)
+		assert.LessOrEqual(t, delay, 100*time.Millisecond)
+	})
+}
+
+func TestHostRateLimiter_LimitsPerHost(t *testing.T) {
+
+	var now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var limiter = BuildHostRateLimiter(2, 2)
+	limiter.now = func() time.Time { return now }
+
+	assert.Zero(t, limiter.reserve("a.example.com"))
+	assert.Zero(t, limiter.reserve("a.example.com"))
+	assert.Equal(t, 500*time.Millisecond, limiter.reserve("a.example.com"))
+
+	// other hosts have their own bucket
+	assert.Zero(t, limiter.reserve("b.example.com"))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Zero(t, limiter.reserve("a.example.com"))
+	assert.Equal(t, 500*time.Millisecond, limiter.reserve("a.example.com"))
+}
+
+func TestHostRateLimiter_DisabledWithoutRate(t *testing.T) {
+	assert.Nil(t, BuildHostRateLimiter(0, 5))
+}
+
+func TestHostRateLimiter_WaitStopsWhenContextIsCancelled(t *testing.T) {
+
+	var limiter = BuildHostRateLimiter(0.001, 1)
+	var requestContext, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, limiter.Wait(requestContext, "example.com"))
+	require.ErrorIs(t, limiter.Wait(requestContext, "example.com"), context.DeadlineExceeded)
+}
+
+func TestCircuitBreaker_OpensAndRecovers(t *testing.T) {
+
+	var now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var breaker = BuildCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	require.NoError(t, breaker.Allow())
+	breaker.RecordResult(false)
+	require.NoError(t, breaker.Allow())
+	breaker.RecordResult(false)
+
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, breaker.Allow(), "a trial request is allowed after the open duration")
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen, "only one trial request is allowed")
+
+	breaker.RecordResult(false)
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen, "a failed trial opens the circuit again")
+
+	now = now.Add(time.Minute)
+	require.NoError(t, breaker.Allow())
+	breaker.RecordResult(true)
+	require.NoError(t, breaker.Allow())
+	require.NoError(t, breaker.Allow())
+}
+
+func TestExecuteGet_CircuitBreakerRejectsRequestsWhenOpen(t *testing.T) {
+
+	var server, requestCount = buildSequenceServer(t, nil, http.StatusInternalServerError)
+	var breaker = BuildCircuitBreaker(2, time.Minute)
+
+	for range 2 {
+		var _, err = ExecuteGet(context.Background(), server.URL, WithCircuitBreaker(breaker))
+		require.Error(t, err)
+	}
+
+	var _, err = ExecuteGet(
+		context.Background(),
+		server.URL,
+		WithCircuitBreaker(breaker),
+		WithRetry(fastRetryPolicy),
+	)
+
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), requestCount.Load())
+}
+
+func TestExecuteGet_AppliesHeaders(t *testing.T) {
+
+	var receivedUserAgent string
+	var server = httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				receivedUserAgent = request.Header.Get("User-Agent")
+				writer.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer server.Close()
+
+	var response, err = ExecuteGet(context.Background(), server.URL, WithHeader("User-Agent", "test-agent"))
+
+	require.NoError(t, err)
+	CloseResponseBody(response)
+	assert.Equal(t, "test-agent", receivedUserAgent)
+}
@@ -14,8 +14,8 @@ import (
 // to validate fields that may have been missed by shallow validation.
 //
 // Authored by: GitHub Copilot
-func DeepValidate(target interface{}) []string {
-	var errorMessages []string
+func DeepValidate(target interface{}) []*ValidationMessage {
+	var errorMessages []*ValidationMessage
 	validateValueDeep(reflect.ValueOf(target), "", &errorMessages)
 	return errorMessages
 }
@@ -23,7 +23,7 @@ func DeepValidate(target interface{}) []string {
 // validateValueDeep recursively validates a reflect.Value and its nested structures
 //
 // Co-authored by: GitHub Copilot
-func validateValueDeep(value reflect.Value, fieldPath string, errorMessages *[]string) {
+func validateValueDeep(value reflect.Value, fieldPath string, errorMessages *[]*ValidationMessage) {
 
 	// Dereference all pointer levels using langext.DereferenceValue
 	actualValue, isNil := langext.DereferenceValue(value)
@@ -45,7 +45,7 @@ func validateValueDeep(value reflect.Value, fieldPath string, errorMessages *[]s
 // It delegates actual field validation to the validator library
 //
 // Authored by: GitHub Copilot
-func validateStructDeep(structValue reflect.Value, fieldPath string, errorMessages *[]string) {
+func validateStructDeep(structValue reflect.Value, fieldPath string, errorMessages *[]*ValidationMessage) {
 
 	// Use validator library to validate this struct's fields
 	validateStructWithValidator(structValue, fieldPath, errorMessages)
@@ -73,7 +73,7 @@ func validateStructDeep(structValue reflect.Value, fieldPath string, errorMessag
 // and formats errors consistently with the rest of the validation system
 //
 // Authored by: GitHub Copilot and OpenCode
-func validateStructWithValidator(structValue reflect.Value, fieldPath string, errorMessages *[]string) {
+func validateStructWithValidator(structValue reflect.Value, fieldPath string, errorMessages *[]*ValidationMessage) {
 
 	// Create validator instance
 	validate := validator.New()
@@ -91,12 +91,7 @@ func validateStructWithValidator(structValue reflect.Value, fieldPath string, er
 
 				// Build the field path using JSON property names
 				errorFieldPath := buildValidationErrorPathWithJSON(fieldPath, validationError, structType)
-				errorMessage := fmt.Sprintf(
-					"Field '%s' failed validation: %s",
-					errorFieldPath,
-					formatValidationError(validationError),
-				)
-				*errorMessages = append(*errorMessages, errorMessage)
+				*errorMessages = append(*errorMessages, buildFieldValidationMessage(errorFieldPath, validationError))
 			}
 		}
 	}
@@ -150,7 +145,7 @@ func buildValidationErrorPathWithJSON(
 // validateSliceDeep validates all elements in a slice or array by recursively validating each element
 //
 // Authored by: GitHub Copilot
-func validateSliceDeep(sliceValue reflect.Value, fieldPath string, errorMessages *[]string) {
+func validateSliceDeep(sliceValue reflect.Value, fieldPath string, errorMessages *[]*ValidationMessage) {
 
 	for i := 0; i < sliceValue.Len(); i++ {
 		element := sliceValue.Index(i)
@@ -11,6 +11,13 @@ import (
 	"github.com/benizzio/open-asset-allocator/langext"
 )
 
+// ValidationMessage is the human-readable message of a failed validation, with the JSON path of the
+// validated field (as in "details[0].hierarchicalId"), empty when the validation is not of a single field.
+type ValidationMessage struct {
+	FieldPath string
+	Message   string
+}
+
 // MapValidationErrorsToMessages maps validation errors from the validator library to human-readable messages.
 // It uses JSON property names for field identification.
 //
@@ -22,7 +29,7 @@ import (
 //   - A slice of human-readable error messages, or nil if the input error is not validation errors
 //
 // Co-authored by: GitHub Copilot
-func MapValidationErrorsToMessages(inputError error, targetStruct interface{}) []string {
+func MapValidationErrorsToMessages(inputError error, targetStruct interface{}) []*ValidationMessage {
 	var validationErrors = asValidationErrors(inputError)
 	if validationErrors == nil {
 		return nil
@@ -45,9 +52,12 @@ func asValidationErrors(inputError error) validator.ValidationErrors {
 // FormatValidationErrorMessages converts validation errors into human-readable messages.
 //
 // Authored by: GitHub Copilot
-func FormatValidationErrorMessages(validationErrors validator.ValidationErrors, targetStruct interface{}) []string {
+func FormatValidationErrorMessages(
+	validationErrors validator.ValidationErrors,
+	targetStruct interface{},
+) []*ValidationMessage {
 
-	errorMessages := make([]string, 0, len(validationErrors))
+	errorMessages := make([]*ValidationMessage, 0, len(validationErrors))
 
 	structType := langext.GetStructType(targetStruct)
 
@@ -62,17 +72,25 @@ func FormatValidationErrorMessages(validationErrors validator.ValidationErrors,
 // formatErrorMessage formats a single validation error into a human-readable message.
 //
 // Authored by: GitHub Copilot
-func formatErrorMessage(validationError validator.FieldError, structType reflect.Type) string {
+func formatErrorMessage(validationError validator.FieldError, structType reflect.Type) *ValidationMessage {
 	// Extract needed information from validation error
 	namespace := validationError.Namespace()
 	fieldName := validationError.Field()
 	jsonFieldName := json.GetJSONFieldName(namespace, fieldName, structType)
 
-	return fmt.Sprintf(
-		"Field '%s' failed validation: %s",
-		jsonFieldName,
-		formatValidationError(validationError),
-	)
+	return buildFieldValidationMessage(jsonFieldName, validationError)
+}
+
+// buildFieldValidationMessage builds the message of a validation error of the field with the JSON path.
+func buildFieldValidationMessage(jsonFieldPath string, validationError validator.FieldError) *ValidationMessage {
+	return &ValidationMessage{
+		FieldPath: jsonFieldPath,
+		Message: fmt.Sprintf(
+			"Field '%s' failed validation: %s",
+			jsonFieldPath,
+			formatValidationError(validationError),
+		),
+	}
 }
 
 // formatValidationError formats validation error into readable messages.
@@ -871,10 +871,14 @@ func TestPostAllocationPlanValidation_MissingName(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'name' failed validation: is required"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/name", "detail": "Field 'name' failed validation: is required"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // Test validation: missing required field 'details'
@@ -900,10 +904,14 @@ func TestPostAllocationPlanValidation_MissingDetails(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'details' failed validation: is required"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/details", "detail": "Field 'details' failed validation: is required"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // Test validation: empty 'details' array (min length violation)
@@ -930,10 +938,14 @@ func TestPostAllocationPlanValidation_EmptyDetails(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'details' failed validation: must be at least 1"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/details", "detail": "Field 'details' failed validation: must be at least 1"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // Test validation: missing required field 'hierarchicalId' in a planned allocation
@@ -962,10 +974,14 @@ func TestPostAllocationPlanValidation_MissingHierarchicalId(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'details[0].hierarchicalId' failed validation: is required"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/details/0/hierarchicalId", "detail": "Field 'details[0].hierarchicalId' failed validation: is required"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // Test validation: empty 'hierarchicalId' array (min length violation)
@@ -994,10 +1010,14 @@ func TestPostAllocationPlanValidation_EmptyHierarchicalId(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'details[0].hierarchicalId' failed validation: must be at least 1"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/details/0/hierarchicalId", "detail": "Field 'details[0].hierarchicalId' failed validation: must be at least 1"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // Test validation (domain): duplicate hierarchical ids within the same plan should be rejected.
@@ -1038,10 +1058,15 @@ func TestPostAllocationPlanValidation_DuplicateHierarchicalIds(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Allocation plan validation failed",
-		"details": ["Planned allocations contain duplicated hierarchical IDs: ARCA:BIL|BONDS"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations contain duplicated hierarchical IDs: ARCA:BIL|BONDS"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // Test validation (domain): child slice sizes within a parent must not exceed 100%.
@@ -1082,10 +1107,15 @@ func TestPostAllocationPlanValidation_PercentageSumExceedsParentLimit(t *testing
 	assert.NoError(t, err)
 
 	var expected = `{
-        "errorMessage": "Allocation plan validation failed",
-        "details": ["Planned allocations slice sizes exceed 100% within hierarchy level(s): Classes = BONDS (120%)"]
-    }`
-	assert.JSONEq(t, expected, string(body))
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations slice sizes exceed 100% within hierarchy level(s): Classes = BONDS (120%)"}
+		]
+	}`
+	assertProblemJSON(t, expected, string(body))
 }
 
 func TestPostAllocationPlanValidation_PercentageSumBelowParentLimit(t *testing.T) {
@@ -1123,10 +1153,15 @@ func TestPostAllocationPlanValidation_PercentageSumBelowParentLimit(t *testing.T
 	assert.NoError(t, err)
 
 	var expected = `{
-        "errorMessage": "Allocation plan validation failed",
-        "details": ["Planned allocations slice sizes sum to less than 100% within hierarchy level(s): Classes = BONDS (80%)"]
-    }`
-	assert.JSONEq(t, expected, string(body))
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations slice sizes sum to less than 100% within hierarchy level(s): Classes = BONDS (80%)"}
+		]
+	}`
+	assertProblemJSON(t, expected, string(body))
 }
 
 // Test validation (domain): top-level slice sizes must not exceed 100%.
@@ -1162,10 +1197,15 @@ func TestPostAllocationPlanValidation_TopLevelPercentageSumExceedsLimit(t *testi
 	assert.NoError(t, err)
 
 	var expected = `{
-       	"errorMessage": "Allocation plan validation failed",
-        "details": ["Planned allocations slice sizes exceed 100% within hierarchy level(s): Classes (TOP) (130%)"]
-    }`
-	assert.JSONEq(t, expected, string(body))
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations slice sizes exceed 100% within hierarchy level(s): Classes (TOP) (130%)"}
+		]
+	}`
+	assertProblemJSON(t, expected, string(body))
 }
 
 func TestPostAllocationPlanValidation_TopLevelPercentageSumBelowLimit(t *testing.T) {
@@ -1198,10 +1238,15 @@ func TestPostAllocationPlanValidation_TopLevelPercentageSumBelowLimit(t *testing
 	assert.NoError(t, err)
 
 	var expected = `{
-       	"errorMessage": "Allocation plan validation failed",
-        "details": ["Planned allocations slice sizes sum to less than 100% within hierarchy level(s): Classes (TOP) (80%)"]
-    }`
-	assert.JSONEq(t, expected, string(body))
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations slice sizes sum to less than 100% within hierarchy level(s): Classes (TOP) (80%)"}
+		]
+	}`
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostAllocationPlanValidation_NameExceedsMaxLength tests that posting an allocation plan
@@ -1232,10 +1277,14 @@ func TestPostAllocationPlanValidation_NameExceedsMaxLength(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'name' failed validation: must not exceed 100"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/name", "detail": "Field 'name' failed validation: must not exceed 100"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostAllocationPlanValidation_TypeExceedsMaxLength tests that posting an allocation plan
@@ -1267,10 +1316,14 @@ func TestPostAllocationPlanValidation_TypeExceedsMaxLength(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'type' failed validation: must not exceed 50"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/type", "detail": "Field 'type' failed validation: must not exceed 50"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostAllocationPlanValidation_InvalidSizeHierarchyBranches tests that posting an allocation plan
@@ -1310,10 +1363,15 @@ func TestPostAllocationPlanValidation_InvalidSizeHierarchyBranches(t *testing.T)
 	assert.NoError(t, err)
 
 	var expected = `{
-        "errorMessage": "Allocation plan validation failed",
-        "details": ["Planned allocations contain hierarchy branches with invalid size: \nEXTRA_LEVEL -> STOCKS -> ARCA:EWZ\n for portfolio hierarchy: Classes -> Assets"]
-    }`
-	assert.JSONEq(t, expected, string(body))
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations contain hierarchy branches with invalid size: \nEXTRA_LEVEL -> STOCKS -> ARCA:EWZ\n for portfolio hierarchy: Classes -> Assets"}
+		]
+	}`
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostAllocationPlanValidation_MissingParentHierarchyBranches tests that posting an allocation plan
@@ -1353,10 +1411,15 @@ func TestPostAllocationPlanValidation_MissingParentHierarchyBranches(t *testing.
 	assert.NoError(t, err)
 
 	var expected = `{
-        "errorMessage": "Allocation plan validation failed",
-        "details": ["Planned allocations contain hierarchy branches with missing parent levels: \n -> ARCA:SPY\n for portfolio hierarchy: Classes -> Assets"]
-    }`
-	assert.JSONEq(t, expected, string(body))
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations contain hierarchy branches with missing parent levels: \n -> ARCA:SPY\n for portfolio hierarchy: Classes -> Assets"}
+		]
+	}`
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostAllocationPlanValidation_ChildlessHierarchyBranches tests that posting an allocation plan
@@ -1394,10 +1457,15 @@ func TestPostAllocationPlanValidation_ChildlessHierarchyBranches(t *testing.T) {
 	assert.NoError(t, err)
 
 	var expected = `{
-        "errorMessage": "Allocation plan validation failed",
-        "details": ["Planned allocations contain hierarchy branches with missing child levels: \nSTOCKS\n for portfolio hierarchy: Classes -> Assets"]
-    }`
-	assert.JSONEq(t, expected, string(body))
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations contain hierarchy branches with missing child levels: \nSTOCKS\n for portfolio hierarchy: Classes -> Assets"}
+		]
+	}`
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostAllocationPlanValidation_MultipleChildlessHierarchyBranches tests that posting an allocation plan
@@ -1435,8 +1503,13 @@ func TestPostAllocationPlanValidation_MultipleChildlessHierarchyBranches(t *test
 
 	// Both BONDS and STOCKS branches are childless (root node stripped from output)
 	var expected = `{
-        "errorMessage": "Allocation plan validation failed",
-        "details": ["Planned allocations contain hierarchy branches with missing child levels: \nBONDS\nSTOCKS\n for portfolio hierarchy: Classes -> Assets"]
-    }`
-	assert.JSONEq(t, expected, string(body))
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "Allocation plan validation failed",
+		"errors": [
+			{"detail": "Planned allocations contain hierarchy branches with missing child levels: \nBONDS\nSTOCKS\n for portfolio hierarchy: Classes -> Assets"}
+		]
+	}`
+	assertProblemJSON(t, expected, string(body))
 }
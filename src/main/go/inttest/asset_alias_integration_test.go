@@ -0,0 +1,349 @@
+package inttest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+// TestAssetAliasLifecycle verifies registering, listing, resolving and deleting a ticker alias
+// through the /api/asset/:assetIdOrTicker/alias endpoints.
+func TestAssetAliasLifecycle(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:ALIASNEW", "Test Asset With Alias")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/alias",
+		`
+			{
+				"ticker": "TEST:ALIASOLD",
+				"source": "SWS",
+				"validTo": "2024-12-31T00:00:00Z"
+			}
+		`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var aliasId int64
+	err := inttestinfra.FetchWithDBQuery(
+		"SELECT id FROM asset_alias WHERE asset_id = {:assetId} AND ticker = 'TEST:ALIASOLD'",
+		dbx.Params{"assetId": testAsset.Id},
+		func(rows *dbx.Rows) error {
+			return rows.Scan(&aliasId)
+		},
+	)
+	require.NoError(t, err)
+	require.NotZero(t, aliasId)
+	var aliasIdString = strconv.FormatInt(aliasId, 10)
+
+	assert.JSONEq(
+		t,
+		`
+			{
+				"id": `+aliasIdString+`,
+				"ticker": "TEST:ALIASOLD",
+				"source": "SWS",
+				"validTo": "2024-12-31T00:00:00Z"
+			}
+		`,
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, "TEST:ALIASNEW/alias", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			[
+				{
+					"id": `+aliasIdString+`,
+					"ticker": "TEST:ALIASOLD",
+					"source": "SWS",
+					"validTo": "2024-12-31T00:00:00Z"
+				}
+			]
+		`,
+		responseBody,
+	)
+
+	// the previous ticker resolves to the asset
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, "TEST:ALIASOLD", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			{
+				"id": `+testAssetIdString+`,
+				"ticker": "TEST:ALIASNEW",
+				"name": "Test Asset With Alias"
+			}
+		`,
+		responseBody,
+	)
+
+	// the alias is not deleted through another asset
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodDelete, "1/alias/"+aliasIdString, "")
+	assert.Equal(t, http.StatusNotFound, statusCode, responseBody)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodDelete,
+		testAssetIdString+"/alias/"+aliasIdString,
+		"",
+	)
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	assert.Empty(t, responseBody)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, "TEST:ALIASOLD", "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assertProblemJSON(
+		t,
+		`
+			{
+				"type": "urn:open-asset-allocator:problem:data-not-found",
+				"title": "Data not found",
+				"status": 404,
+				"detail": "Asset with identifier TEST:ALIASOLD not found"
+			}
+		`,
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodDelete,
+		testAssetIdString+"/alias/"+aliasIdString,
+		"",
+	)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assertProblemJSON(
+		t,
+		`
+			{
+				"type": "urn:open-asset-allocator:problem:data-not-found",
+				"title": "Data not found",
+				"status": 404,
+				"detail": "Asset alias with identifier `+aliasIdString+` not found"
+			}
+		`,
+		responseBody,
+	)
+}
+
+// TestGetAssetByAliasPrefersCurrentTicker verifies that a ticker reused by another asset resolves to the
+// asset currently holding it, instead of the asset that held it in the past.
+func TestGetAssetByAliasPrefersCurrentTicker(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:RENAMED", "Test Asset Renamed")
+	insertTestAssetAlias(t, testAsset.Id, "ARCA:BIL", "", "2020-01-01")
+
+	var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, "ARCA:BIL", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			{
+				"id": 1,
+				"name": "SPDR Bloomberg 1-3 Month T-Bill ETF",
+				"ticker": "ARCA:BIL"
+			}
+		`,
+		responseBody,
+	)
+}
+
+// TestGetAssetByIdPrefersIdOverAlias verifies that a numeric identifier also registered as an alias of
+// another asset resolves to the asset with it as id.
+func TestGetAssetByIdPrefersIdOverAlias(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:NUMERICALIAS", "Test Asset Numeric Alias")
+	insertTestAssetAlias(t, testAsset.Id, "1", "", "")
+
+	var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, "1", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			{
+				"id": 1,
+				"name": "SPDR Bloomberg 1-3 Month T-Bill ETF",
+				"ticker": "ARCA:BIL"
+			}
+		`,
+		responseBody,
+	)
+}
+
+// TestPostAssetAliasValidation verifies request and domain validation of new ticker aliases.
+func TestPostAssetAliasValidation(t *testing.T) {
+
+	t.Run("ValidationFailsWhenTickerIsMissing", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodPost, "1/alias", `{"source": "SWS"}`)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assertProblemJSON(
+			t,
+			`
+				{
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/ticker", "detail": "Field 'ticker' failed validation: is required"}
+					]
+				}
+			`,
+			responseBody,
+		)
+	})
+
+	t.Run("ValidationFailsWhenValidityIsInverted", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodPost,
+			"1/alias",
+			`
+				{
+					"ticker": "TEST:INVERTED",
+					"validFrom": "2025-01-01T00:00:00Z",
+					"validTo": "2024-01-01T00:00:00Z"
+				}
+			`,
+		)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assertProblemJSON(
+			t,
+			`
+				{
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"detail": "Asset alias validation failed",
+					"errors": [
+						{"detail": "Asset alias TEST:INVERTED validity start must not be after its validity end"}
+					]
+				}
+			`,
+			responseBody,
+		)
+	})
+
+	t.Run("NotFoundWhenAssetDoesNotExist", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodPost,
+			"999/alias",
+			`{"ticker": "TEST:NOASSET"}`,
+		)
+
+		assert.Equal(t, http.StatusNotFound, statusCode)
+		assertProblemJSON(
+			t,
+			`
+				{
+					"type": "urn:open-asset-allocator:problem:data-not-found",
+					"title": "Data not found",
+					"status": 404,
+					"detail": "Asset with identifier 999 not found"
+				}
+			`,
+			responseBody,
+		)
+	})
+}
+
+// TestPostPortfolioAllocationHistoryResolvesTickerAlias verifies that a snapshot referencing a previous
+// ticker is attached to the existing asset while the alias is valid at the observation date, instead of
+// creating a new asset.
+func TestPostPortfolioAllocationHistoryResolvesTickerAlias(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:CURRENT", "Test Asset Current Ticker")
+	insertTestAssetAlias(t, testAsset.Id, "TEST:PREVIOUS", "", "2025-06-30")
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery(
+				`
+				DELETE FROM portfolio_allocation_fact
+				WHERE observation_time_id IN (
+					SELECT id FROM portfolio_allocation_obs_time WHERE observation_time_tag = '202506ALIAS'
+				)`,
+				nil,
+			).
+			AddCleanupQuery(
+				"DELETE FROM portfolio_allocation_obs_time WHERE observation_time_tag = '202506ALIAS'",
+				nil,
+			).
+			Build(t),
+	)
+
+	var postPortfolioSnapshotJSON = `
+		{
+			"observationTimestamp": {
+				"timeTag": "202506ALIAS",
+				"timestamp": "2025-06-01T00:00:00Z"
+			},
+			"allocations": [
+				{
+					"assetName": "Previous Ticker Name",
+					"assetTicker": "TEST:PREVIOUS",
+					"class": "STOCKS",
+					"cashReserve": false,
+					"assetQuantity": "10",
+					"assetMarketPrice": "100",
+					"totalMarketValue": "1000"
+				}
+			]
+		}
+	`
+
+	response, err := http.Post(
+		inttestinfra.TestAPIURLPrefix+"/portfolio/1/history",
+		"application/json",
+		strings.NewReader(postPortfolioSnapshotJSON),
+	)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+	require.Equal(t, http.StatusNoContent, response.StatusCode)
+
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+	inttestutil.AssertDBWithQueryMultipleRows(
+		t,
+		`
+			SELECT p.asset_id, a.ticker
+			FROM portfolio_allocation_fact p
+			JOIN asset a ON p.asset_id = a.id
+			JOIN portfolio_allocation_obs_time o ON p.observation_time_id = o.id
+			WHERE o.observation_time_tag = '202506ALIAS'
+		`,
+		[]inttestutil.AssertableNullStringMap{
+			{
+				"asset_id": inttestutil.ToAssertableNullString(testAssetIdString),
+				"ticker":   inttestutil.ToAssertableNullString("TEST:CURRENT"),
+			},
+		},
+	)
+
+	inttestutil.AssertDBWithQueryMultipleRows(
+		t,
+		"SELECT count(*) AS count FROM asset WHERE ticker = 'TEST:PREVIOUS'",
+		[]inttestutil.AssertableNullStringMap{
+			{"count": inttestutil.ToAssertableNullString("0")},
+		},
+	)
+}
@@ -0,0 +1,210 @@
+package inttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	restmodel "github.com/benizzio/open-asset-allocator/api/rest/model"
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+)
+
+const yahooFinanceEventsRequestURIFormat = "/v8/finance/chart/%s?events=div%%2Csplit&interval=1d&range=max"
+
+// TestAssetEventsImportIncomeAndSplitAdjustedHistory verifies importing dividend and split events from
+// Yahoo Finance, the dividend income derived from them for a portfolio and the split adjusted portfolio
+// history.
+func TestAssetEventsImportIncomeAndSplitAdjustedHistory(t *testing.T) {
+
+	var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Asset Events")
+	var testAsset = insertTestAsset(t, "TEST:EVENTS", "Test Asset Events")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+	var testPortfolioIdString = strconv.FormatInt(testPortfolio.Id, 10)
+
+	insertTestPortfolioObservation(t, testPortfolio.Id, "test_asset_events", "2023-01-10 00:00:00", 10, 400, testAsset.Id)
+
+	linkTestExternalAssets(
+		t,
+		testAssetIdString,
+		`{"source": "YAHOO_FINANCE", "ticker": "TSTX", "exchangeId": "NMS"}`,
+	)
+
+	yahooFinanceMockServer.ExpectGet(fmt.Sprintf(yahooFinanceEventsRequestURIFormat, "TSTX")).
+		WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+		Return(`
+			{
+				"chart": {
+					"result": [
+						{
+							"meta": {"symbol": "TSTX", "exchangeName": "NMS", "currency": "USD"},
+							"timestamp": [1676039400, 1685629800, 1694442600],
+							"indicators": {"quote": [{"close": [400.0, 100.0, 110.0]}]},
+							"events": {
+								"dividends": {
+									"1676039400": {"amount": 0.5, "date": 1676039400},
+									"1694442600": {"amount": 0.2, "date": 1694442600}
+								},
+								"splits": {
+									"1685629800": {
+										"date": 1685629800,
+										"numerator": 4,
+										"denominator": 1,
+										"splitRatio": "4:1"
+									}
+								}
+							}
+						}
+					]
+				}
+			}
+		`)
+
+	var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodPost, testAssetIdString+"/event/import", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	assert.JSONEq(
+		t,
+		`
+			[
+				{
+					"eventType": "DIVIDEND",
+					"eventDate": "2023-02-10T00:00:00Z",
+					"source": "YAHOO_FINANCE",
+					"amount": "0.5",
+					"currency": "USD"
+				},
+				{
+					"eventType": "SPLIT",
+					"eventDate": "2023-06-01T00:00:00Z",
+					"source": "YAHOO_FINANCE",
+					"splitNumerator": "4",
+					"splitDenominator": "1"
+				},
+				{
+					"eventType": "DIVIDEND",
+					"eventDate": "2023-09-11T00:00:00Z",
+					"source": "YAHOO_FINANCE",
+					"amount": "0.2",
+					"currency": "USD"
+				}
+			]
+		`,
+		removeJSONArrayItemsField(t, responseBody, "id"),
+	)
+
+	statusCode, listResponseBody := sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/event", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, responseBody, listResponseBody)
+
+	var incomeStatusCode, incomeResponseBody = getPortfolioResource(t, testPortfolioIdString+"/income")
+	assert.Equal(t, http.StatusOK, incomeStatusCode)
+	assert.JSONEq(
+		t,
+		fmt.Sprintf(
+			`
+				[
+					{
+						"assetId": %[1]d,
+						"assetTicker": "TEST:EVENTS",
+						"assetName": "Test Asset Events",
+						"exDividendDate": "2023-09-11T00:00:00Z",
+						"amountPerShare": "0.2",
+						"currency": "USD",
+						"assetQuantity": "40",
+						"totalAmount": "8",
+						"observationTimestamp": "2023-01-10T00:00:00Z"
+					},
+					{
+						"assetId": %[1]d,
+						"assetTicker": "TEST:EVENTS",
+						"assetName": "Test Asset Events",
+						"exDividendDate": "2023-02-10T00:00:00Z",
+						"amountPerShare": "0.5",
+						"currency": "USD",
+						"assetQuantity": "10",
+						"totalAmount": "5",
+						"observationTimestamp": "2023-01-10T00:00:00Z"
+					}
+				]
+			`,
+			testAsset.Id,
+		),
+		incomeResponseBody,
+	)
+
+	var historyStatusCode, historyResponseBody = getPortfolioResource(
+		t,
+		testPortfolioIdString+"/history?splitAdjusted=true",
+	)
+	require.Equal(t, http.StatusOK, historyStatusCode, historyResponseBody)
+
+	var history []restmodel.PortfolioSnapshotDTS
+	require.NoError(t, json.Unmarshal([]byte(historyResponseBody), &history))
+	require.Len(t, history, 1)
+	require.Len(t, history[0].Allocations, 1)
+	assert.Equal(t, "40", history[0].Allocations[0].AssetQuantity.String())
+	assert.Equal(t, "100", history[0].Allocations[0].AssetMarketPrice.String())
+	assert.Equal(t, "4000", history[0].Allocations[0].TotalMarketValue.String())
+}
+
+// TestImportAssetEventsFailsWithoutEventSource verifies that importing events of an asset without a
+// linked external asset able to report events is rejected.
+func TestImportAssetEventsFailsWithoutEventSource(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:NO-EVENTS", "Test Asset No Events")
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		strconv.FormatInt(testAsset.Id, 10)+"/event/import",
+		"",
+	)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assertProblemJSON(
+		t,
+		`{
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"detail": "Asset TEST:NO-EVENTS has no linked external asset providing dividend and split events"
+		}`,
+		responseBody,
+	)
+}
+
+func getPortfolioResource(t *testing.T, path string) (int, string) {
+	t.Helper()
+
+	response, err := http.Get(inttestinfra.TestAPIURLPrefix + "/portfolio/" + path)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	responseBody, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(responseBody)
+}
+
+func removeJSONArrayItemsField(t *testing.T, arrayJSON string, field string) string {
+	t.Helper()
+
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(arrayJSON), &items))
+
+	for _, item := range items {
+		delete(item, field)
+	}
+
+	result, err := json.Marshal(items)
+	require.NoError(t, err)
+
+	return string(result)
+}
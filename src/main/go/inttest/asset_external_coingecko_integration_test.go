@@ -0,0 +1,118 @@
+package inttest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+)
+
+const coinGeckoBitcoinSearchRequestURI = "/search?query=bitcoin"
+const coinGeckoBitcoinMarketChartRequestURI = "/coins/bitcoin/market_chart?days=2&interval=daily&vs_currency=usd"
+const yahooFinanceBitcoinSearchRequestURI = "/v1/finance/search?enableCb=false&enableCulturalAssets=false&enableFuzzyQuery=false&enableNavLinks=false&enableResearchReports=false&listsCount=0&newsCount=0&q=bitcoin&quotesCount=5"
+
+// TestGetExternalAssetsFromCoinGecko verifies that GET /api/external-asset includes the coins found
+// through the CoinGecko search, priced in the configured vs-currency.
+func TestGetExternalAssetsFromCoinGecko(t *testing.T) {
+
+	var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+	var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTest(t)
+
+	yahooFinanceMockServer.ExpectGet(yahooFinanceBitcoinSearchRequestURI).
+		WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+		Return(`{"quotes": []}`)
+
+	coinGeckoMockServer.ExpectGet(coinGeckoBitcoinSearchRequestURI).
+		WithHeader("Accept", "application/json").
+		Return(`
+			{
+				"coins": [
+					{
+						"id": "bitcoin",
+						"name": "Bitcoin",
+						"api_symbol": "bitcoin",
+						"symbol": "BTC",
+						"market_cap_rank": 1
+					},
+					{
+						"id": "wrapped-bitcoin",
+						"name": "Wrapped Bitcoin",
+						"api_symbol": "wrapped-bitcoin",
+						"symbol": "WBTC",
+						"market_cap_rank": 20
+					}
+				],
+				"exchanges": [],
+				"categories": []
+			}
+		`)
+
+	var statusCode, responseBody = getExternalAssets(t, "query=bitcoin")
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, `
+		[
+			{
+				"source": "COINGECKO",
+				"ticker": "bitcoin",
+				"exchangeId": "USD",
+				"name": "Bitcoin",
+				"exchangeName": "BTC/USD",
+				"instrumentType": "CRYPTO"
+			},
+			{
+				"source": "COINGECKO",
+				"ticker": "wrapped-bitcoin",
+				"exchangeId": "USD",
+				"name": "Wrapped Bitcoin",
+				"exchangeName": "WBTC/USD",
+				"instrumentType": "CRYPTO"
+			}
+		]
+	`, responseBody)
+}
+
+// TestGetAssetQuoteFromCoinGecko verifies quoting an asset linked to CoinGecko, taking the last price at
+// 00:00 UTC as the last close and ignoring the current intraday price.
+func TestGetAssetQuoteFromCoinGecko(t *testing.T) {
+
+	var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTest(t)
+
+	var testAsset = insertTestAsset(t, "TEST:BTC", "Test Asset Bitcoin")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+	linkTestExternalAssets(t, testAssetIdString, `{"source": "COINGECKO", "ticker": "bitcoin", "exchangeId": "USD"}`)
+
+	coinGeckoMockServer.ExpectGet(coinGeckoBitcoinMarketChartRequestURI).
+		Return(`
+			{
+				"prices": [
+					[1735689600000, 93429.2],
+					[1735776000000, 94419.75],
+					[1735812345000, 96886.1]
+				],
+				"market_caps": [],
+				"total_volumes": []
+			}
+		`)
+
+	var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			{
+				"source": "COINGECKO",
+				"ticker": "bitcoin",
+				"exchangeId": "USD",
+				"currency": "USD",
+				"lastCloseQuote": "94419.75",
+				"lastCloseDate": "2025-01-02T00:00:00Z"
+			}
+		`,
+		responseBody,
+	)
+}
@@ -12,6 +12,7 @@ import (
 
 const yahooFinanceExpectedUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
 const yahooFinanceSearchRequestURI = "/v1/finance/search?enableCb=false&enableCulturalAssets=false&enableFuzzyQuery=false&enableNavLinks=false&enableResearchReports=false&listsCount=0&newsCount=0&q=IAU&quotesCount=5"
+const coinGeckoSearchRequestURI = "/search?query=IAU"
 
 // TestGetExternalAssetsSuccess verifies successful GET /api/external-asset responses using the
 // shared Yahoo Finance mock server.
@@ -21,6 +22,7 @@ func TestGetExternalAssetsSuccess(t *testing.T) {
 
 	t.Run("ReturnsExternalAssets", func(t *testing.T) {
 		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTest(t)
 
 		yahooFinanceMockServer.ExpectGet(yahooFinanceSearchRequestURI).
 			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
@@ -37,6 +39,8 @@ func TestGetExternalAssetsSuccess(t *testing.T) {
 				}
 			`)
 
+		coinGeckoMockServer.ExpectGet(coinGeckoSearchRequestURI).Return(`{"coins": []}`)
+
 		var statusCode, responseBody = getExternalAssets(t, "query=IAU")
 
 		assert.Equal(t, http.StatusOK, statusCode)
@@ -53,13 +57,69 @@ func TestGetExternalAssetsSuccess(t *testing.T) {
 		`, responseBody)
 	})
 
+	t.Run("ReturnsExternalAssetsWithInstrumentType", func(t *testing.T) {
+		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTest(t)
+
+		yahooFinanceMockServer.ExpectGet(yahooFinanceSearchRequestURI).
+			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+			Return(`
+				{
+					"quotes": [
+						{
+							"symbol": "IAU",
+							"exchange": "PCX",
+							"longname": "iShares Gold Trust",
+							"exchDisp": "NYSEArca",
+							"quoteType": "ETF"
+						},
+						{
+							"symbol": "^IAU",
+							"exchange": "NIM",
+							"longname": "Unmapped Index",
+							"exchDisp": "Nasdaq",
+							"quoteType": "INDEX"
+						}
+					]
+				}
+			`)
+
+		coinGeckoMockServer.ExpectGet(coinGeckoSearchRequestURI).Return(`{"coins": []}`)
+
+		var statusCode, responseBody = getExternalAssets(t, "query=IAU")
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(t, `
+			[
+				{
+					"source": "YAHOO_FINANCE",
+					"ticker": "IAU",
+					"exchangeId": "PCX",
+					"name": "iShares Gold Trust",
+					"exchangeName": "NYSEArca",
+					"instrumentType": "ETF"
+				},
+				{
+					"source": "YAHOO_FINANCE",
+					"ticker": "^IAU",
+					"exchangeId": "NIM",
+					"name": "Unmapped Index",
+					"exchangeName": "Nasdaq"
+				}
+			]
+		`, responseBody)
+	})
+
 	t.Run("ReturnsEmptyArrayWhenYahooReturnsNoQuotes", func(t *testing.T) {
 		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTest(t)
 
 		yahooFinanceMockServer.ExpectGet(yahooFinanceSearchRequestURI).
 			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
 			Return(`{"quotes": []}`)
 
+		coinGeckoMockServer.ExpectGet(coinGeckoSearchRequestURI).Return(`{"coins": []}`)
+
 		var statusCode, responseBody = getExternalAssets(t, "query=IAU")
 
 		assert.Equal(t, http.StatusOK, statusCode)
@@ -75,35 +135,47 @@ func TestGetExternalAssetsFailure(t *testing.T) {
 
 	t.Run("ReturnsInternalServerErrorWhenYahooReturnsNon200", func(t *testing.T) {
 		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTestWithoutVerification(t)
 
 		yahooFinanceMockServer.ExpectGet(yahooFinanceSearchRequestURI).
 			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
 			ReturnCode(http.StatusTooManyRequests).
 			Return(`{"error":"rate limited"}`)
 
+		coinGeckoMockServer.ExpectGet(coinGeckoSearchRequestURI).Return(`{"coins": []}`)
+
 		var statusCode, responseBody = getExternalAssets(t, "query=IAU")
 
 		assert.Equal(t, http.StatusInternalServerError, statusCode)
-		assert.JSONEq(t, `
+		assertProblemJSON(t, `
 			{
-				"errorMessage": "Internal server error"
+				"type": "about:blank",
+				"title": "Internal Server Error",
+				"status": 500,
+				"detail": "Internal server error"
 			}
 		`, responseBody)
 	})
 
 	t.Run("ReturnsInternalServerErrorWhenYahooReturnsInvalidJSON", func(t *testing.T) {
 		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTestWithoutVerification(t)
 
 		yahooFinanceMockServer.ExpectGet(yahooFinanceSearchRequestURI).
 			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
 			Return(`{"quotes": [`)
 
+		coinGeckoMockServer.ExpectGet(coinGeckoSearchRequestURI).Return(`{"coins": []}`)
+
 		var statusCode, responseBody = getExternalAssets(t, "query=IAU")
 
 		assert.Equal(t, http.StatusInternalServerError, statusCode)
-		assert.JSONEq(t, `
+		assertProblemJSON(t, `
 			{
-				"errorMessage": "Internal server error"
+				"type": "about:blank",
+				"title": "Internal Server Error",
+				"status": 500,
+				"detail": "Internal server error"
 			}
 		`, responseBody)
 	})
@@ -114,11 +186,13 @@ func TestGetExternalAssetsFailure(t *testing.T) {
 		var statusCode, responseBody = getExternalAssets(t, "")
 
 		assert.Equal(t, http.StatusBadRequest, statusCode)
-		assert.JSONEq(t, `
+		assertProblemJSON(t, `
 			{
-				"errorMessage": "Validation failed",
-				"details": [
-					"Field 'query' failed validation: is required"
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"errors": [
+					{"pointer": "/query", "detail": "Field 'query' failed validation: is required"}
 				]
 			}
 		`, responseBody)
@@ -130,11 +204,13 @@ func TestGetExternalAssetsFailure(t *testing.T) {
 		var statusCode, responseBody = getExternalAssets(t, "query=")
 
 		assert.Equal(t, http.StatusBadRequest, statusCode)
-		assert.JSONEq(t, `
+		assertProblemJSON(t, `
 			{
-				"errorMessage": "Validation failed",
-				"details": [
-					"Field 'query' failed validation: is required"
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"errors": [
+					{"pointer": "/query", "detail": "Field 'query' failed validation: is required"}
 				]
 			}
 		`, responseBody)
@@ -147,11 +223,13 @@ func TestGetExternalAssetsFailure(t *testing.T) {
 		var statusCode, responseBody = getExternalAssets(t, "query="+oversizedQuery)
 
 		assert.Equal(t, http.StatusBadRequest, statusCode)
-		assert.JSONEq(t, `
+		assertProblemJSON(t, `
 			{
-				"errorMessage": "Validation failed",
-				"details": [
-					"Field 'query' failed validation: must not exceed 100"
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"errors": [
+					{"pointer": "/query", "detail": "Field 'query' failed validation: must not exceed 100"}
 				]
 			}
 		`, responseBody)
@@ -0,0 +1,92 @@
+package inttest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+)
+
+const jsonProviderQuoteRequestURI = "/quote/XETR/EUNL"
+
+// TestGetAssetQuoteFromJSONProvider verifies quoting assets linked to a provider declared in the
+// configuration, mapped from its JSON response through the declared paths.
+func TestGetAssetQuoteFromJSONProvider(t *testing.T) {
+
+	t.Run("ReturnsQuoteMappedFromJSONResponse", func(t *testing.T) {
+		var jsonProviderMockServer = inttestinfra.SetupJSONProviderMockTest(t)
+
+		var testAsset = insertTestAsset(t, "TEST:JSONFEED", "Test Asset JSON Feed")
+		var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+		linkTestExternalAssets(
+			t,
+			testAssetIdString,
+			`{"source": "TEST_JSON_FEED", "ticker": "EUNL", "exchangeId": "XETR"}`,
+		)
+
+		jsonProviderMockServer.ExpectGet(jsonProviderQuoteRequestURI).
+			WithHeader("X-Api-Key", "test-json-feed-key").
+			Return(`{"data": {"currency": "EUR", "quote": {"close": "98.765", "timestamp": 1735776000}}}`)
+
+		var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(
+			t,
+			`
+				{
+					"source": "TEST_JSON_FEED",
+					"ticker": "EUNL",
+					"exchangeId": "XETR",
+					"currency": "EUR",
+					"lastCloseQuote": "98.765",
+					"lastCloseDate": "2025-01-02T00:00:00Z"
+				}
+			`,
+			responseBody,
+		)
+	})
+
+	t.Run("UsesDeclaredCurrencyWhenResponseHasNone", func(t *testing.T) {
+		var jsonProviderMockServer = inttestinfra.SetupJSONProviderMockTest(t)
+
+		var testAsset = insertTestAsset(t, "TEST:JSONFEEDCURRENCY", "Test Asset JSON Feed Currency")
+		var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+		linkTestExternalAssets(
+			t,
+			testAssetIdString,
+			`{"source": "TEST_JSON_FEED", "ticker": "EUNL", "exchangeId": "XETR"}`,
+		)
+
+		jsonProviderMockServer.ExpectGet(jsonProviderQuoteRequestURI).
+			Return(`{"data": {"quote": {"close": 97.5, "timestamp": 1735776000}}}`)
+
+		var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Contains(t, responseBody, `"currency":"EUR"`)
+		assert.Contains(t, responseBody, `"lastCloseQuote":"97.5"`)
+	})
+
+	t.Run("ReturnsErrorWhenResponseHasNoPrice", func(t *testing.T) {
+		var jsonProviderMockServer = inttestinfra.SetupJSONProviderMockTest(t)
+
+		var testAsset = insertTestAsset(t, "TEST:JSONFEEDNOPRICE", "Test Asset JSON Feed No Price")
+		var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+		linkTestExternalAssets(
+			t,
+			testAssetIdString,
+			`{"source": "TEST_JSON_FEED", "ticker": "EUNL", "exchangeId": "XETR"}`,
+		)
+
+		jsonProviderMockServer.ExpectGet(jsonProviderQuoteRequestURI).
+			Return(`{"data": {"quote": {"timestamp": 1735776000}}}`)
+
+		var statusCode, _ = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+
+		assert.Equal(t, http.StatusInternalServerError, statusCode)
+	})
+}
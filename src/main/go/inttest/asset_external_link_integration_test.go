@@ -0,0 +1,453 @@
+package inttest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+const yahooFinanceChartRequestURIFormat = "/v8/finance/chart/%s?events=history&interval=1d"
+
+// TestAssetExternalAssetLinkLifecycle verifies linking, prioritizing and unlinking external assets
+// through the /api/asset/:assetIdOrTicker/external-asset endpoints.
+func TestAssetExternalAssetLinkLifecycle(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:LINKED", "Test Asset With External Links")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/external-asset",
+		`{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX", "instrumentType": "ETF"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	inttestutil.AssertDBWithQueryMultipleRows(
+		t,
+		"SELECT * FROM asset WHERE id="+testAssetIdString,
+		[]inttestutil.AssertableNullStringMap{
+			{
+				"instrument_type": inttestutil.ToAssertableNullString("ETF"),
+				"exchange":        inttestutil.ToAssertableNullString("PCX"),
+				"version":         inttestutil.ToAssertableNullString("2"),
+			},
+		},
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		"TEST:LINKED/external-asset",
+		`{"source": "YAHOO_FINANCE", "ticker": "IAU.L", "exchangeId": "LSE"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+	assert.JSONEq(
+		t,
+		`
+			[
+				{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"},
+				{"source": "YAHOO_FINANCE", "ticker": "IAU.L", "exchangeId": "LSE"}
+			]
+		`,
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPut,
+		testAssetIdString+"/external-asset/priority",
+		`
+			{
+				"externalAssets": [
+					{"source": "YAHOO_FINANCE", "ticker": "IAU.L", "exchangeId": "LSE"},
+					{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}
+				]
+			}
+		`,
+	)
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/external-asset", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			[
+				{"source": "YAHOO_FINANCE", "ticker": "IAU.L", "exchangeId": "LSE"},
+				{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}
+			]
+		`,
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodDelete,
+		testAssetIdString+"/external-asset?source=YAHOO_FINANCE&ticker=IAU.L&exchangeId=LSE",
+		"",
+	)
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	assert.Empty(t, responseBody)
+
+	var expectedExternalData = `{"data": [{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}]}`
+	assertPersistedAssetWithExternalData(
+		t,
+		testAsset.Id,
+		"TEST:LINKED",
+		"Test Asset With External Links",
+		&expectedExternalData,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodDelete,
+		testAssetIdString+"/external-asset?source=YAHOO_FINANCE&ticker=IAU.L&exchangeId=LSE",
+		"",
+	)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assertProblemJSON(
+		t,
+		`
+			{
+				"type": "urn:open-asset-allocator:problem:data-not-found",
+				"title": "Data not found",
+				"status": 404,
+				"detail": "External asset with identifier YAHOO_FINANCE:LSE:IAU.L not found"
+			}
+		`,
+		responseBody,
+	)
+}
+
+// TestAssetExternalAssetLinkRejectsStaleVersion verifies that linking an external asset conditioned to a
+// stale asset version is rejected with the current asset, keeping the links made since that version.
+func TestAssetExternalAssetLinkRejectsStaleVersion(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:LINKSTALE", "Test Asset With Stale Link")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	var statusCode, responseBody = sendAssetResourceRequestWithIfMatch(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/external-asset",
+		`{"source": "YAHOO_FINANCE", "ticker": "SLV", "exchangeId": "PCX"}`,
+		`"1"`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	statusCode, responseBody = sendAssetResourceRequestWithIfMatch(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/external-asset",
+		`{"source": "YAHOO_FINANCE", "ticker": "SLV.L", "exchangeId": "LSE"}`,
+		`"1"`,
+	)
+	assert.Equal(t, http.StatusPreconditionFailed, statusCode, responseBody)
+
+	var expectedExternalData = `{"data": [{"source": "YAHOO_FINANCE", "ticker": "SLV", "exchangeId": "PCX"}]}`
+	assertPersistedAssetWithExternalData(
+		t,
+		testAsset.Id,
+		"TEST:LINKSTALE",
+		"Test Asset With Stale Link",
+		&expectedExternalData,
+	)
+	assertPersistedAssetVersion(t, testAsset.Id, "2")
+}
+
+// TestAssetExternalAssetLinkValidation verifies request and domain validation when linking and
+// prioritizing external assets.
+func TestAssetExternalAssetLinkValidation(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:LINKVALID", "Test Asset Link Validation")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/external-asset",
+		`{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	t.Run("ValidationFailsWhenAlreadyLinked", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodPost,
+			testAssetIdString+"/external-asset",
+			`{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}`,
+		)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assertProblemJSON(
+			t,
+			`
+				{
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"detail": "External asset YAHOO_FINANCE:PCX:IAU is already linked"
+				}
+			`,
+			responseBody,
+		)
+	})
+
+	t.Run("ValidationFailsWhenSourceIsInvalid", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodPost,
+			testAssetIdString+"/external-asset",
+			`{"source": "UNKNOWN", "ticker": "IAU", "exchangeId": "PCX"}`,
+		)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assertProblemJSON(
+			t,
+			`{
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"detail": "Invalid AssetExternalSource UNKNOWN"
+			}`,
+			responseBody,
+		)
+	})
+
+	t.Run("ValidationFailsWhenPriorityDoesNotListEveryLink", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodPut,
+			testAssetIdString+"/external-asset/priority",
+			`
+				{
+					"externalAssets": [
+						{"source": "YAHOO_FINANCE", "ticker": "GLD", "exchangeId": "PCX"}
+					]
+				}
+			`,
+		)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assertProblemJSON(
+			t,
+			`{
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"detail": "External asset priority must list every linked external asset exactly once"
+			}`,
+			responseBody,
+		)
+	})
+
+	t.Run("ValidationFailsWhenUnlinkReferenceIsIncomplete", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodDelete,
+			testAssetIdString+"/external-asset?source=YAHOO_FINANCE",
+			"",
+		)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assertProblemJSON(
+			t,
+			`
+				{
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/ticker", "detail": "Field 'ticker' failed validation: is required"},
+						{"pointer": "/exchangeId", "detail": "Field 'exchangeId' failed validation: is required"}
+					]
+				}
+			`,
+			responseBody,
+		)
+	})
+}
+
+// TestGetAssetQuoteFallsBackToNextExternalAsset verifies that quoting an asset falls back to the next
+// linked external asset when the highest priority one fails.
+func TestGetAssetQuoteFallsBackToNextExternalAsset(t *testing.T) {
+
+	var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+
+	var testAsset = insertTestAsset(t, "TEST:QUOTED", "Test Asset Quoted")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	linkTestExternalAssets(
+		t,
+		testAssetIdString,
+		`{"source": "YAHOO_FINANCE", "ticker": "IAU.L", "exchangeId": "LSE"}`,
+		`{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}`,
+	)
+
+	yahooFinanceMockServer.ExpectGet(fmt.Sprintf(yahooFinanceChartRequestURIFormat, "IAU.L")).
+		WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+		ReturnCode(http.StatusInternalServerError).
+		Return(`{"error":"unavailable"}`)
+
+	yahooFinanceMockServer.ExpectGet(fmt.Sprintf(yahooFinanceChartRequestURIFormat, "IAU")).
+		WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+		Return(`
+			{
+				"chart": {
+					"result": [
+						{
+							"meta": {"symbol": "IAU", "exchangeName": "PCX", "currency": "USD"},
+							"timestamp": [1735689600, 1735776000],
+							"indicators": {"quote": [{"close": [50.1, 51.25]}]}
+						}
+					]
+				}
+			}
+		`)
+
+	var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+	var expectedLastCloseDate = time.Unix(1735776000, 0).Format(time.RFC3339Nano)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			{
+				"source": "YAHOO_FINANCE",
+				"ticker": "IAU",
+				"exchangeId": "PCX",
+				"currency": "USD",
+				"lastCloseQuote": "51.25",
+				"lastCloseDate": "`+expectedLastCloseDate+`"
+			}
+		`,
+		responseBody,
+	)
+}
+
+// TestGetAssetQuoteFailsWithoutExternalAssets verifies that quoting an asset without linked external
+// assets is rejected.
+func TestGetAssetQuoteFailsWithoutExternalAssets(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:UNQUOTED", "Test Asset Unquoted")
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodGet,
+		strconv.FormatInt(testAsset.Id, 10)+"/quote",
+		"",
+	)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assertProblemJSON(
+		t,
+		`{
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"detail": "Asset TEST:UNQUOTED has no linked external asset to quote"
+		}`,
+		responseBody,
+	)
+}
+
+// TestGetPortfolioAssetQuotesReportsPerAssetResults verifies that quoting the assets of a portfolio
+// returns a result per asset of its latest observation, reporting errors without failing the request.
+func TestGetPortfolioAssetQuotesReportsPerAssetResults(t *testing.T) {
+
+	var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Batch Quote")
+	var quotedAsset = insertTestAsset(t, "TEST:BATCH-QUOTED", "Test Asset Batch Quoted")
+	var unquotedAsset = insertTestAsset(t, "TEST:BATCH-UNQUOTED", "Test Asset Batch Unquoted")
+
+	insertTestPortfolioObservation(
+		t,
+		testPortfolio.Id,
+		"test_batch_quote",
+		"2025-10-01 00:00:00",
+		10,
+		50,
+		quotedAsset.Id,
+		unquotedAsset.Id,
+	)
+
+	linkTestExternalAssets(
+		t,
+		strconv.FormatInt(quotedAsset.Id, 10),
+		`{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}`,
+	)
+
+	yahooFinanceMockServer.ExpectGet(fmt.Sprintf(yahooFinanceChartRequestURIFormat, "IAU")).
+		WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+		Return(`
+			{
+				"chart": {
+					"result": [
+						{
+							"meta": {"symbol": "IAU", "exchangeName": "PCX", "currency": "USD"},
+							"timestamp": [1735776000],
+							"indicators": {"quote": [{"close": [51.25]}]}
+						}
+					]
+				}
+			}
+		`)
+
+	response, err := http.Get(
+		inttestinfra.TestAPIURLPrefix + "/portfolio/" + strconv.FormatInt(testPortfolio.Id, 10) + "/quote",
+	)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	var expectedLastCloseDate = time.Unix(1735776000, 0).Format(time.RFC3339Nano)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.JSONEq(
+		t,
+		fmt.Sprintf(
+			`
+				[
+					{
+						"assetId": %d,
+						"assetTicker": "TEST:BATCH-QUOTED",
+						"quote": {
+							"source": "YAHOO_FINANCE",
+							"ticker": "IAU",
+							"exchangeId": "PCX",
+							"currency": "USD",
+							"lastCloseQuote": "51.25",
+							"lastCloseDate": "%s"
+						}
+					},
+					{
+						"assetId": %d,
+						"assetTicker": "TEST:BATCH-UNQUOTED",
+						"error": "Asset TEST:BATCH-UNQUOTED has no linked external asset to quote"
+					}
+				]
+			`,
+			quotedAsset.Id,
+			expectedLastCloseDate,
+			unquotedAsset.Id,
+		),
+		string(body),
+	)
+}
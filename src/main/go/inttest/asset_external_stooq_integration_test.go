@@ -0,0 +1,183 @@
+package inttest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+)
+
+const stooqQuoteRequestURI = "/q/l/?e=csv&f=sd2t2ohlcvn&h=&s=iau.us"
+const stooqHistoryRequestURI = "/q/d/l/?i=d&s=iau.us"
+const yahooFinanceStooqSymbolSearchRequestURI = "/v1/finance/search?enableCb=false&enableCulturalAssets=false&enableFuzzyQuery=false&enableNavLinks=false&enableResearchReports=false&listsCount=0&newsCount=0&q=iau.us&quotesCount=5"
+const coinGeckoStooqSymbolSearchRequestURI = "/search?query=iau.us"
+
+// TestGetExternalAssetsFromStooq verifies that GET /api/external-asset resolves Stooq symbols through
+// the Stooq quote endpoint, alongside the Yahoo Finance search.
+func TestGetExternalAssetsFromStooq(t *testing.T) {
+
+	t.Run("ReturnsStooqExternalAsset", func(t *testing.T) {
+		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTest(t)
+		var stooqMockServer = inttestinfra.SetupStooqMockTest(t)
+
+		yahooFinanceMockServer.ExpectGet(yahooFinanceStooqSymbolSearchRequestURI).
+			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+			Return(`{"quotes": []}`)
+
+		stooqMockServer.ExpectGet(stooqQuoteRequestURI).
+			Return(
+				"Symbol,Date,Time,Open,High,Low,Close,Volume,Name\n" +
+					"IAU.US,2025-01-02,22:00:10,49.8,50.3,49.7,50.12,7001234,ISHARES GOLD TRUST\n",
+			)
+
+		coinGeckoMockServer.ExpectGet(coinGeckoStooqSymbolSearchRequestURI).Return(`{"coins": []}`)
+
+		var statusCode, responseBody = getExternalAssets(t, "query=iau.us")
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(t, `
+			[
+				{
+					"source": "STOOQ",
+					"ticker": "IAU.US",
+					"exchangeId": "US",
+					"name": "ISHARES GOLD TRUST"
+				}
+			]
+		`, responseBody)
+	})
+
+	t.Run("ReturnsEmptyArrayWhenStooqHasNoData", func(t *testing.T) {
+		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTest(t)
+		var stooqMockServer = inttestinfra.SetupStooqMockTest(t)
+
+		yahooFinanceMockServer.ExpectGet(yahooFinanceStooqSymbolSearchRequestURI).
+			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+			Return(`{"quotes": []}`)
+
+		stooqMockServer.ExpectGet(stooqQuoteRequestURI).
+			Return(
+				"Symbol,Date,Time,Open,High,Low,Close,Volume,Name\n" +
+					"IAU.US,N/D,N/D,N/D,N/D,N/D,N/D,N/D,IAU.US\n",
+			)
+
+		coinGeckoMockServer.ExpectGet(coinGeckoStooqSymbolSearchRequestURI).Return(`{"coins": []}`)
+
+		var statusCode, responseBody = getExternalAssets(t, "query=iau.us")
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(t, `[]`, responseBody)
+	})
+
+	t.Run("ReturnsInternalServerErrorWhenStooqReturnsNon200", func(t *testing.T) {
+		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var coinGeckoMockServer = inttestinfra.SetupCoinGeckoMockTestWithoutVerification(t)
+		var stooqMockServer = inttestinfra.SetupStooqMockTest(t)
+
+		yahooFinanceMockServer.ExpectGet(yahooFinanceStooqSymbolSearchRequestURI).
+			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+			Return(`{"quotes": []}`)
+
+		stooqMockServer.ExpectGet(stooqQuoteRequestURI).
+			ReturnCode(http.StatusServiceUnavailable).
+			Return("Service unavailable")
+
+		coinGeckoMockServer.ExpectGet(coinGeckoStooqSymbolSearchRequestURI).Return(`{"coins": []}`)
+
+		var statusCode, _ = getExternalAssets(t, "query=iau.us")
+
+		assert.Equal(t, http.StatusInternalServerError, statusCode)
+	})
+}
+
+// TestGetAssetQuoteFromStooq verifies quoting assets linked to Stooq, including the fallback from Yahoo
+// Finance and the fallback to the Stooq daily history.
+func TestGetAssetQuoteFromStooq(t *testing.T) {
+
+	t.Run("FallsBackToStooqWhenYahooFinanceFails", func(t *testing.T) {
+		var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+		var stooqMockServer = inttestinfra.SetupStooqMockTest(t)
+
+		var testAsset = insertTestAsset(t, "TEST:STOOQFALLBACK", "Test Asset Stooq Fallback")
+		var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+		linkTestExternalAssets(
+			t,
+			testAssetIdString,
+			`{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}`,
+			`{"source": "STOOQ", "ticker": "IAU.US", "exchangeId": "US"}`,
+		)
+
+		yahooFinanceMockServer.ExpectGet("/v8/finance/chart/IAU?events=history&interval=1d").
+			WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+			ReturnCode(http.StatusTooManyRequests).
+			Return(`{"error":"rate limited"}`)
+
+		stooqMockServer.ExpectGet(stooqQuoteRequestURI).
+			Return(
+				"Symbol,Date,Time,Open,High,Low,Close,Volume,Name\n" +
+					"IAU.US,2025-01-02,22:00:10,49.8,50.3,49.7,50.12,7001234,ISHARES GOLD TRUST\n",
+			)
+
+		var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(
+			t,
+			`
+				{
+					"source": "STOOQ",
+					"ticker": "IAU.US",
+					"exchangeId": "US",
+					"currency": "USD",
+					"lastCloseQuote": "50.12",
+					"lastCloseDate": "2025-01-02T00:00:00Z"
+				}
+			`,
+			responseBody,
+		)
+	})
+
+	t.Run("FallsBackToDailyHistoryWhenQuoteHasNoData", func(t *testing.T) {
+		var stooqMockServer = inttestinfra.SetupStooqMockTest(t)
+
+		var testAsset = insertTestAsset(t, "TEST:STOOQHISTORY", "Test Asset Stooq History")
+		var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+		linkTestExternalAssets(t, testAssetIdString, `{"source": "STOOQ", "ticker": "IAU.US", "exchangeId": "US"}`)
+
+		stooqMockServer.ExpectGet(stooqQuoteRequestURI).
+			Return(
+				"Symbol,Date,Time,Open,High,Low,Close,Volume,Name\n" +
+					"IAU.US,N/D,N/D,N/D,N/D,N/D,N/D,N/D,IAU.US\n",
+			)
+
+		stooqMockServer.ExpectGet(stooqHistoryRequestURI).
+			Return(
+				"Date,Open,High,Low,Close,Volume\n" +
+					"2024-12-30,49.1,49.6,48.9,49.45,6500000\n" +
+					"2024-12-31,49.5,49.9,49.3,49.87,5800000\n",
+			)
+
+		var statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(
+			t,
+			`
+				{
+					"source": "STOOQ",
+					"ticker": "IAU.US",
+					"exchangeId": "US",
+					"currency": "USD",
+					"lastCloseQuote": "49.87",
+					"lastCloseDate": "2024-12-31T00:00:00Z"
+				}
+			`,
+			responseBody,
+		)
+	})
+}
@@ -193,14 +193,16 @@ func TestPutAssetFailureWithoutId(t *testing.T) {
 
 	var expectedResponseJSON = `
 		{
-			"errorMessage": "Validation failed",
-			"details": [
-				"Field 'id' failed validation: is required"
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"errors": [
+				{"pointer": "/id", "detail": "Field 'id' failed validation: is required"}
 			]
 		}
 	`
 
-	assert.JSONEq(t, expectedResponseJSON, string(body))
+	assertProblemJSON(t, expectedResponseJSON, string(body))
 }
 
 // TestPutAssetFailureWithZeroId tests the PUT /api/asset endpoint returns a validation error
@@ -228,14 +230,16 @@ func TestPutAssetFailureWithZeroId(t *testing.T) {
 
 	var expectedResponseJSON = `
 		{
-			"errorMessage": "Validation failed",
-			"details": [
-				"Field 'id' failed validation: is required"
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"errors": [
+				{"pointer": "/id", "detail": "Field 'id' failed validation: is required"}
 			]
 		}
 	`
 
-	assert.JSONEq(t, expectedResponseJSON, string(body))
+	assertProblemJSON(t, expectedResponseJSON, string(body))
 }
 
 // TestPutAssetFailureWithoutRequiredFields tests the PUT /api/asset endpoint returns validation
@@ -266,14 +270,16 @@ func TestPutAssetFailureWithoutRequiredFields(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'name' failed validation: is required"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/name", "detail": "Field 'name' failed validation: is required"}
 					]
 				}
 			`
 
-			assert.JSONEq(t, expectedResponseJSON, string(body))
+			assertProblemJSON(t, expectedResponseJSON, string(body))
 		},
 	)
 
@@ -299,14 +305,16 @@ func TestPutAssetFailureWithoutRequiredFields(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'ticker' failed validation: is required"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/ticker", "detail": "Field 'ticker' failed validation: is required"}
 					]
 				}
 			`
 
-			assert.JSONEq(t, expectedResponseJSON, string(body))
+			assertProblemJSON(t, expectedResponseJSON, string(body))
 		},
 	)
 
@@ -331,15 +339,17 @@ func TestPutAssetFailureWithoutRequiredFields(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'name' failed validation: is required",
-						"Field 'ticker' failed validation: is required"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/name", "detail": "Field 'name' failed validation: is required"},
+						{"pointer": "/ticker", "detail": "Field 'ticker' failed validation: is required"}
 					]
 				}
 			`
 
-			assert.JSONEq(t, expectedResponseJSON, string(body))
+			assertProblemJSON(t, expectedResponseJSON, string(body))
 		},
 	)
 }
@@ -363,13 +373,13 @@ func TestGetAssetByIdNotFound(t *testing.T) {
 	var actualResponseJSON = string(body)
 	var expectedResponseJSON = `
 		{
-			"errorMessage": "Data not found",
-			"details": [
-				"Asset with identifier 999 not found"
-			]
+			"type": "urn:open-asset-allocator:problem:data-not-found",
+			"title": "Data not found",
+			"status": 404,
+			"detail": "Asset with identifier 999 not found"
 		}
 	`
-	assert.JSONEq(t, expectedResponseJSON, actualResponseJSON)
+	assertProblemJSON(t, expectedResponseJSON, actualResponseJSON)
 }
 
 // TestGetAssetByIdInvalidId tests the GET /api/asset/{id} endpoint with invalid ID formats.
@@ -0,0 +1,206 @@
+package inttest
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+// TestPutAssetWithMetadata tests the PUT /api/asset endpoint updating the asset metadata fields.
+func TestPutAssetWithMetadata(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:META", "Test Asset Metadata")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	var putAssetJSON = `
+		{
+			"id":` + testAssetIdString + `,
+			"ticker": "TEST:META",
+			"name": "Test Asset Metadata",
+			"instrumentType": "ETF",
+			"currency": "USD",
+			"isin": "US78462F1030",
+			"cusip": "78462F103",
+			"exchange": "PCX"
+		}
+	`
+
+	response := putAsset(t, putAssetJSON)
+	defer deferCloseResponseBody(response)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, putAssetJSON, string(body))
+
+	inttestutil.AssertDBWithQueryMultipleRows(
+		t,
+		"SELECT * FROM asset WHERE id="+testAssetIdString,
+		[]inttestutil.AssertableNullStringMap{
+			{
+				"instrument_type": inttestutil.ToAssertableNullString("ETF"),
+				"currency":        inttestutil.ToAssertableNullString("USD"),
+				"isin":            inttestutil.ToAssertableNullString("US78462F1030"),
+				"cusip":           inttestutil.ToAssertableNullString("78462F103"),
+				"exchange":        inttestutil.ToAssertableNullString("PCX"),
+			},
+		},
+	)
+
+	// omitted metadata is cleared
+	response = putAsset(
+		t,
+		`{"id":`+testAssetIdString+`, "ticker": "TEST:META", "name": "Test Asset Metadata"}`,
+	)
+	defer deferCloseResponseBody(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	inttestutil.AssertDBWithQueryMultipleRows(
+		t,
+		"SELECT * FROM asset WHERE id="+testAssetIdString,
+		[]inttestutil.AssertableNullStringMap{
+			{
+				"instrument_type": inttestutil.NullAssertableNullString(),
+				"currency":        inttestutil.NullAssertableNullString(),
+				"isin":            inttestutil.NullAssertableNullString(),
+				"cusip":           inttestutil.NullAssertableNullString(),
+				"exchange":        inttestutil.NullAssertableNullString(),
+			},
+		},
+	)
+}
+
+// TestPutAssetFailureWithInvalidMetadata tests the PUT /api/asset endpoint rejecting invalid
+// instrument types, currencies and security identifiers with wrong check digits.
+func TestPutAssetFailureWithInvalidMetadata(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:BADMETA", "Test Asset Invalid Metadata")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	response := putAsset(
+		t,
+		`
+			{
+				"id":`+testAssetIdString+`,
+				"ticker": "TEST:BADMETA",
+				"name": "Test Asset Invalid Metadata",
+				"instrumentType": "FUND",
+				"currency": "XYZ",
+				"isin": "US78462F1031",
+				"cusip": "78462F104"
+			}
+		`,
+	)
+	defer deferCloseResponseBody(response)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assertProblemJSON(
+		t,
+		`
+			{
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"detail": "Asset validation failed",
+				"errors": [
+					{"detail": "Invalid instrument type FUND"},
+					{"detail": "Invalid currency XYZ"},
+					{"detail": "Invalid ISIN US78462F1031"},
+					{"detail": "Invalid CUSIP 78462F104"}
+				]
+			}
+		`,
+		string(body),
+	)
+
+	assertPersistedAsset(t, testAsset.Id, "TEST:BADMETA", "Test Asset Invalid Metadata")
+}
+
+// TestPostPortfolioAllocationHistoryFillsAssetMetadataFromExternalAsset verifies that a new asset
+// linked to an external asset gets its instrument type and exchange from the external asset.
+func TestPostPortfolioAllocationHistoryFillsAssetMetadataFromExternalAsset(t *testing.T) {
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery(
+				`
+				DELETE FROM portfolio_allocation_fact
+				WHERE observation_time_id IN (
+					SELECT id FROM portfolio_allocation_obs_time WHERE observation_time_tag = '202509META'
+				)`,
+				nil,
+			).
+			AddCleanupQuery("DELETE FROM asset WHERE ticker = 'Test:EXTMETA'", nil).
+			AddCleanupQuery(
+				"DELETE FROM portfolio_allocation_obs_time WHERE observation_time_tag = '202509META'",
+				nil,
+			).
+			Build(t),
+	)
+
+	var postPortfolioSnapshotJSON = `
+		{
+			"observationTimestamp": {
+				"timeTag": "202509META",
+				"timestamp": "2025-09-01T00:00:00Z"
+			},
+			"allocations": [
+				{
+					"assetName": "External Asset With Metadata",
+					"assetTicker": "Test:EXTMETA",
+					"externalAsset": {
+						"source": "YAHOO_FINANCE",
+						"ticker": "IAU",
+						"exchangeId": "PCX",
+						"instrumentType": "ETF"
+					},
+					"class": "STOCKS",
+					"cashReserve": false,
+					"assetQuantity": "20",
+					"assetMarketPrice": "100",
+					"totalMarketValue": "2000"
+				}
+			]
+		}
+	`
+
+	response, err := http.Post(
+		inttestinfra.TestAPIURLPrefix+"/portfolio/2/history",
+		"application/json",
+		strings.NewReader(postPortfolioSnapshotJSON),
+	)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+	require.Equal(t, http.StatusNoContent, response.StatusCode)
+
+	inttestutil.AssertDBWithQueryMultipleRows(
+		t,
+		"SELECT * FROM asset WHERE ticker = 'Test:EXTMETA'",
+		[]inttestutil.AssertableNullStringMap{
+			{
+				"instrument_type": inttestutil.ToAssertableNullString("ETF"),
+				"exchange":        inttestutil.ToAssertableNullString("PCX"),
+				"currency":        inttestutil.NullAssertableNullString(),
+				"external_data": inttestutil.ToAssertableNullStringWithAssertion(
+					func(t *testing.T, actual sql.NullString) {
+						assert.True(t, actual.Valid)
+						assert.JSONEq(t, testExternalAssetDataJSON, actual.String)
+					},
+				),
+			},
+		},
+	)
+}
@@ -83,7 +83,7 @@ func insertTestAsset(t *testing.T, ticker string, name string) domain.Asset {
 
 	var testAsset domain.Asset
 	err = inttestinfra.FetchWithDBQuery(
-		"SELECT * FROM asset WHERE ticker = {:ticker}",
+		"SELECT id, ticker, name, external_data FROM asset WHERE ticker = {:ticker}",
 		dbx.Params{"ticker": ticker},
 		func(rows *dbx.Rows) error {
 			return rows.ScanStruct(&testAsset)
@@ -149,6 +149,18 @@ func assertPersistedAssetWithExternalData(
 	)
 }
 
+// assertPersistedAssetVersion asserts that the asset with the given ID has the expected version in the
+// database.
+func assertPersistedAssetVersion(t *testing.T, assetId int64, expectedVersion string) {
+	inttestutil.AssertDBWithQueryMultipleRows(
+		t,
+		"SELECT * FROM asset WHERE id="+strconv.FormatInt(assetId, 10),
+		[]inttestutil.AssertableNullStringMap{
+			{"version": inttestutil.ToAssertableNullString(expectedVersion)},
+		},
+	)
+}
+
 // putAsset sends a PUT request to the /api/asset endpoint with the given JSON body.
 //
 // Authored by: GitHub Copilot
@@ -193,3 +205,74 @@ func getExternalAssets(t *testing.T, rawQuery string) (int, string) {
 
 	return response.StatusCode, string(responseBodyBytes)
 }
+
+// insertTestAssetAlias inserts a ticker alias for an asset directly in the database. The alias is
+// removed with the asset by the foreign key cascade, so no cleanup is registered.
+func insertTestAssetAlias(t *testing.T, assetId int64, ticker string, validFrom string, validTo string) {
+	t.Helper()
+
+	err := inttestinfra.ExecuteDBQuery(
+		`
+			INSERT INTO asset_alias (asset_id, ticker, valid_from, valid_to)
+			VALUES ({:assetId}, {:ticker}, NULLIF({:validFrom}, '')::date, NULLIF({:validTo}, '')::date)
+		`,
+		dbx.Params{"assetId": assetId, "ticker": ticker, "validFrom": validFrom, "validTo": validTo},
+	)
+	require.NoError(t, err)
+}
+
+// sendAssetResourceRequest sends a request to an endpoint of the asset resource, relative to the
+// /api/asset path, and returns the response status code and body.
+func sendAssetResourceRequest(t *testing.T, method string, path string, requestJSON string) (int, string) {
+	t.Helper()
+	return sendAssetResourceRequestWithIfMatch(t, method, path, requestJSON, "")
+}
+
+// sendAssetResourceRequestWithIfMatch sends a request to an endpoint of the asset resource conditioned to
+// the If-Match entity tag, when not empty, and returns the response status code and body.
+func sendAssetResourceRequestWithIfMatch(
+	t *testing.T,
+	method string,
+	path string,
+	requestJSON string,
+	ifMatch string,
+) (int, string) {
+	t.Helper()
+
+	var requestBody io.Reader
+	if requestJSON != "" {
+		requestBody = strings.NewReader(requestJSON)
+	}
+
+	request, err := http.NewRequest(method, inttestinfra.TestAPIURLPrefix+"/asset/"+path, requestBody)
+	require.NoError(t, err)
+
+	request.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		request.Header.Set("If-Match", ifMatch)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	responseBody, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(responseBody)
+}
+
+// linkTestExternalAssets links the given external assets to a test asset, in priority order.
+func linkTestExternalAssets(t *testing.T, assetIdOrTicker string, externalAssetsJSON ...string) {
+	t.Helper()
+
+	for _, externalAssetJSON := range externalAssetsJSON {
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodPost,
+			assetIdOrTicker+"/external-asset",
+			externalAssetJSON,
+		)
+		require.Equal(t, http.StatusCreated, statusCode, responseBody)
+	}
+}
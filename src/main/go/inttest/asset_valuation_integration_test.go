@@ -0,0 +1,235 @@
+package inttest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+)
+
+// TestAssetValuationLifecycle verifies recording, replacing, quoting and deleting manual valuations
+// through the /api/asset/:assetIdOrTicker/valuation endpoints.
+func TestAssetValuationLifecycle(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:PRIVATEFUND", "Test Private Fund")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/valuation",
+		`{"valuationDate": "2025-03-31T00:00:00Z", "price": "1000.5", "currency": "usd"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var valuationId int64
+	err := inttestinfra.FetchWithDBQuery(
+		"SELECT id FROM asset_valuation WHERE asset_id = {:assetId} AND valuation_date = '2025-03-31'",
+		dbx.Params{"assetId": testAsset.Id},
+		func(rows *dbx.Rows) error {
+			return rows.Scan(&valuationId)
+		},
+	)
+	require.NoError(t, err)
+	require.NotZero(t, valuationId)
+	var valuationIdString = strconv.FormatInt(valuationId, 10)
+
+	assert.JSONEq(
+		t,
+		`
+			{
+				"id": `+valuationIdString+`,
+				"valuationDate": "2025-03-31T00:00:00Z",
+				"price": "1000.5",
+				"currency": "USD"
+			}
+		`,
+		responseBody,
+	)
+
+	// recording the first valuation links the MANUAL source
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/external-asset", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`[{"source": "MANUAL", "ticker": "`+testAssetIdString+`", "exchangeId": "MANUAL"}]`,
+		responseBody,
+	)
+
+	// a valuation on the same date replaces the previous one
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		"TEST:PRIVATEFUND/valuation",
+		`{"valuationDate": "2025-03-31T00:00:00Z", "price": "1010", "currency": "USD"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/valuation",
+		`{"valuationDate": "2024-12-31T00:00:00Z", "price": "980", "currency": "USD"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var previousValuationId int64
+	err = inttestinfra.FetchWithDBQuery(
+		"SELECT id FROM asset_valuation WHERE asset_id = {:assetId} AND valuation_date = '2024-12-31'",
+		dbx.Params{"assetId": testAsset.Id},
+		func(rows *dbx.Rows) error {
+			return rows.Scan(&previousValuationId)
+		},
+	)
+	require.NoError(t, err)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/valuation", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			[
+				{
+					"id": `+valuationIdString+`,
+					"valuationDate": "2025-03-31T00:00:00Z",
+					"price": "1010",
+					"currency": "USD"
+				},
+				{
+					"id": `+strconv.FormatInt(previousValuationId, 10)+`,
+					"valuationDate": "2024-12-31T00:00:00Z",
+					"price": "980",
+					"currency": "USD"
+				}
+			]
+		`,
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			{
+				"source": "MANUAL",
+				"ticker": "`+testAssetIdString+`",
+				"exchangeId": "MANUAL",
+				"currency": "USD",
+				"lastCloseQuote": "1010",
+				"lastCloseDate": "2025-03-31T00:00:00Z"
+			}
+		`,
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodDelete,
+		testAssetIdString+"/valuation/"+valuationIdString,
+		"",
+	)
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	assert.Empty(t, responseBody)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/quote", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`
+			{
+				"source": "MANUAL",
+				"ticker": "`+testAssetIdString+`",
+				"exchangeId": "MANUAL",
+				"currency": "USD",
+				"lastCloseQuote": "980",
+				"lastCloseDate": "2024-12-31T00:00:00Z"
+			}
+		`,
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodDelete,
+		testAssetIdString+"/valuation/"+valuationIdString,
+		"",
+	)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assertProblemJSON(
+		t,
+		`
+			{
+				"type": "urn:open-asset-allocator:problem:data-not-found",
+				"title": "Data not found",
+				"status": 404,
+				"detail": "Asset valuation with identifier `+valuationIdString+` not found"
+			}
+		`,
+		responseBody,
+	)
+}
+
+// TestPostAssetValuationValidation verifies request and domain validation of manual valuations.
+func TestPostAssetValuationValidation(t *testing.T) {
+
+	t.Run("ValidationFailsWhenRequiredFieldsAreMissing", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodPost,
+			"1/valuation",
+			`{"valuationDate": "2025-03-31T00:00:00Z"}`,
+		)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assertProblemJSON(
+			t,
+			`
+				{
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/price", "detail": "Field 'price' failed validation: is required"},
+						{"pointer": "/currency", "detail": "Field 'currency' failed validation: is required"}
+					]
+				}
+			`,
+			responseBody,
+		)
+	})
+
+	t.Run("ValidationFailsWhenPriceIsNegativeAndCurrencyIsInvalid", func(t *testing.T) {
+
+		var statusCode, responseBody = sendAssetResourceRequest(
+			t,
+			http.MethodPost,
+			"1/valuation",
+			`{"valuationDate": "2025-03-31T00:00:00Z", "price": "-1", "currency": "XYZ"}`,
+		)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assertProblemJSON(
+			t,
+			`
+				{
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"detail": "Asset valuation validation failed",
+					"errors": [
+						{"detail": "Asset valuation price -1 must not be negative"},
+						{"detail": "Invalid currency XYZ"}
+					]
+				}
+			`,
+			responseBody,
+		)
+	})
+}
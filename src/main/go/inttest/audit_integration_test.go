@@ -0,0 +1,203 @@
+package inttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	restmodel "github.com/benizzio/open-asset-allocator/api/rest/model"
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+)
+
+// TestGetAuditEntriesAfterAssetUpdate verifies an asset update is recorded in the audit trail with the
+// snapshots of the asset before and after it.
+func TestGetAuditEntriesAfterAssetUpdate(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:AUDIT", "Test Asset Audit Before")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	putResponse := putAsset(
+		t,
+		`{"id":`+testAssetIdString+`, "ticker":"TEST:AUDIT", "name":"Test Asset Audit After"}`,
+	)
+	defer deferCloseResponseBody(putResponse)
+	require.Equal(t, http.StatusOK, putResponse.StatusCode)
+
+	var entries = getAuditEntries(t, "entityType=ASSET&entityId="+testAssetIdString)
+	require.Len(t, entries, 1)
+
+	var entry = entries[0]
+	assert.Equal(t, "ASSET", entry.EntityType)
+	assert.Equal(t, testAsset.Id, int64(*entry.EntityId))
+	assert.Equal(t, "UPDATE", entry.Action)
+	assert.Nil(t, entry.ActorUserId)
+
+	var before, after map[string]any
+	require.NoError(t, json.Unmarshal(entry.Before, &before))
+	require.NoError(t, json.Unmarshal(entry.After, &after))
+	assert.Equal(t, "Test Asset Audit Before", before["name"])
+	assert.Equal(t, "Test Asset Audit After", after["name"])
+}
+
+// TestGetAuditEntriesAfterAssetAliasAndExternalAssetChanges verifies changes of the aliases and linked
+// external assets of an asset are recorded in the audit trail of the asset.
+func TestGetAuditEntriesAfterAssetAliasAndExternalAssetChanges(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:AUDITLINK", "Test Asset Audit Link")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/alias",
+		`{"ticker": "TEST:AUDITLINK-OLD", "validTo": "2023-12-31T00:00:00Z"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/external-asset",
+		`{"source": "YAHOO_FINANCE", "ticker": "IAU", "exchangeId": "PCX"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var entries = getAuditEntries(t, "entityType=ASSET&entityId="+testAssetIdString)
+	require.Len(t, entries, 2)
+
+	// newest first
+	var linkAfter, aliasBefore, aliasAfter map[string]any
+	require.NoError(t, json.Unmarshal(entries[0].After, &linkAfter))
+	require.NoError(t, json.Unmarshal(entries[1].Before, &aliasBefore))
+	require.NoError(t, json.Unmarshal(entries[1].After, &aliasAfter))
+
+	assert.Equal(t, "UPDATE", entries[0].Action)
+	assert.NotNil(t, linkAfter["external_data"])
+	assert.Equal(t, "UPDATE", entries[1].Action)
+	assert.Empty(t, aliasBefore["aliases"])
+	assert.Len(t, aliasAfter["aliases"], 1)
+}
+
+// TestGetAuditEntriesAfterAssetValuationChanges verifies recording and deleting a manual valuation of an asset
+// are recorded in the audit trail of the asset, along with the link of the MANUAL external asset.
+func TestGetAuditEntriesAfterAssetValuationChanges(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:AUDITVALUATION", "Test Asset Audit Valuation")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/valuation",
+		`{"valuationDate": "2025-03-31T00:00:00Z", "price": "10", "currency": "USD"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var valuation restmodel.AssetValuationDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &valuation))
+	require.NotNil(t, valuation.Id)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodDelete,
+		fmt.Sprintf("%s/valuation/%d", testAssetIdString, *valuation.Id),
+		"",
+	)
+	require.Equal(t, http.StatusNoContent, statusCode, responseBody)
+
+	var entries = getAuditEntries(t, "entityType=ASSET&entityId="+testAssetIdString)
+	require.Len(t, entries, 2)
+
+	// newest first
+	var deleteBefore, deleteAfter, recordBefore, recordAfter map[string]any
+	require.NoError(t, json.Unmarshal(entries[0].Before, &deleteBefore))
+	require.NoError(t, json.Unmarshal(entries[0].After, &deleteAfter))
+	require.NoError(t, json.Unmarshal(entries[1].Before, &recordBefore))
+	require.NoError(t, json.Unmarshal(entries[1].After, &recordAfter))
+
+	assert.Len(t, deleteBefore["valuations"], 1)
+	assert.Empty(t, deleteAfter["valuations"])
+	assert.Empty(t, recordBefore["valuations"])
+	assert.Nil(t, recordBefore["external_data"])
+	assert.Len(t, recordAfter["valuations"], 1)
+	assert.NotNil(t, recordAfter["external_data"])
+}
+
+// TestGetAuditEntriesAfterCorporateActionApply verifies the asset and allocation plan changes of an applied
+// symbol change are recorded in the audit trail.
+func TestGetAuditEntriesAfterCorporateActionApply(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Audit Corporate Action")
+	var testAsset = insertTestAsset(t, "TEST:AUDITCA-OLD", "Test Asset Audit Corporate Action")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+	insertTestPlannedAllocation(t, testPortfolio.Id, testAsset.Id, "TEST:AUDITCA-OLD")
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/corporate-action",
+		`{"actionType": "SYMBOL_CHANGE", "effectiveDate": "2024-01-01T00:00:00Z", "newTicker": "TEST:AUDITCA-NEW"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		fmt.Sprintf(
+			"%s/corporate-action/%d/apply",
+			testAssetIdString,
+			getCorporateActionIdFromResponse(t, responseBody),
+		),
+		"",
+	)
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	var assetEntries = getAuditEntries(t, "entityType=ASSET&entityId="+testAssetIdString)
+	require.Len(t, assetEntries, 1)
+
+	var assetAfter map[string]any
+	require.NoError(t, json.Unmarshal(assetEntries[0].After, &assetAfter))
+	assert.Equal(t, "TEST:AUDITCA-NEW", assetAfter["ticker"])
+	assert.Len(t, assetAfter["aliases"], 1)
+
+	var planEntries = getAuditEntries(
+		t,
+		"entityType=ALLOCATION_PLAN&portfolioId="+strconv.FormatInt(testPortfolio.Id, 10),
+	)
+	require.Len(t, planEntries, 1)
+	assert.Equal(t, "UPDATE", planEntries[0].Action)
+}
+
+// TestGetAuditEntriesFailureWithInvalidEntityType verifies the audit trail query rejects unknown entity
+// types.
+func TestGetAuditEntriesFailureWithInvalidEntityType(t *testing.T) {
+
+	response, err := http.Get(inttestinfra.TestAPIURLPrefix + "/audit?entityType=UNKNOWN")
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func getAuditEntries(t *testing.T, rawQuery string) []restmodel.AuditEntryDTS {
+	t.Helper()
+
+	response, err := http.Get(inttestinfra.TestAPIURLPrefix + "/audit?" + rawQuery)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	var entries []restmodel.AuditEntryDTS
+	require.NoError(t, json.Unmarshal(body, &entries))
+	return entries
+}
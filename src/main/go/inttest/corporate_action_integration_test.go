@@ -0,0 +1,302 @@
+package inttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+// TestCorporateActionSplitApplyAndRevert verifies that applying a split converts the portfolio
+// allocations observed from its effective date on, and that reverting it restores them.
+func TestCorporateActionSplitApplyAndRevert(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Corporate Action Split")
+	var testAsset = insertTestAsset(t, "TEST:CA-SPLIT", "Test Asset Corporate Action Split")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	insertTestPortfolioObservation(t, testPortfolio.Id, "test_ca_split_before", "2023-01-10 00:00:00", 10, 400, testAsset.Id)
+	insertTestPortfolioObservation(t, testPortfolio.Id, "test_ca_split_after", "2023-07-10 00:00:00", 10, 100, testAsset.Id)
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/corporate-action",
+		`{"actionType": "SPLIT", "effectiveDate": "2023-06-01T00:00:00Z", "ratioNumerator": 4, "ratioDenominator": 1}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+	var corporateActionId = getCorporateActionIdFromResponse(t, responseBody)
+	assert.JSONEq(
+		t,
+		fmt.Sprintf(
+			`
+				{
+					"id": %d,
+					"actionType": "SPLIT",
+					"effectiveDate": "2023-06-01T00:00:00Z",
+					"ratioNumerator": "4",
+					"ratioDenominator": "1",
+					"status": "PENDING"
+				}
+			`,
+			corporateActionId,
+		),
+		responseBody,
+	)
+
+	var corporateActionPath = fmt.Sprintf("%s/corporate-action/%d", testAssetIdString, corporateActionId)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodPost, corporateActionPath+"/apply", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	assert.Contains(t, responseBody, `"status":"APPLIED"`)
+
+	assertTestObservationQuantityAndPrice(t, testAsset.Id, "test_ca_split_before", "10", "400")
+	assertTestObservationQuantityAndPrice(t, testAsset.Id, "test_ca_split_after", "40", "25")
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodPost, corporateActionPath+"/apply", "")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assertProblemJSON(
+		t,
+		fmt.Sprintf(
+			`{
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"detail": "Corporate action %d is already applied"
+			}`,
+			corporateActionId,
+		),
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodDelete, corporateActionPath, "")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assertProblemJSON(
+		t,
+		fmt.Sprintf(
+			`{
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"detail": "Corporate action %d must be reverted before being deleted"
+			}`,
+			corporateActionId,
+		),
+		responseBody,
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodPost, corporateActionPath+"/revert", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	assert.Contains(t, responseBody, `"status":"REVERTED"`)
+
+	assertTestObservationQuantityAndPrice(t, testAsset.Id, "test_ca_split_before", "10", "400")
+	assertTestObservationQuantityAndPrice(t, testAsset.Id, "test_ca_split_after", "10", "100")
+
+	statusCode, _ = sendAssetResourceRequest(t, http.MethodDelete, corporateActionPath, "")
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/corporate-action", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, `[]`, responseBody)
+}
+
+// TestCorporateActionSymbolChangeApplyAndRevert verifies that applying a symbol change renames the asset,
+// keeps the previous ticker as an alias and updates the later planned allocations, and that reverting it
+// restores the previous state.
+func TestCorporateActionSymbolChangeApplyAndRevert(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Corporate Action Symbol Change")
+	var testAsset = insertTestAsset(t, "TEST:CA-OLD", "Test Asset Corporate Action Symbol Change")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+	var plannedAllocationId = insertTestPlannedAllocation(t, testPortfolio.Id, testAsset.Id, "TEST:CA-OLD")
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/corporate-action",
+		`{"actionType": "SYMBOL_CHANGE", "effectiveDate": "2024-01-01T00:00:00Z", "newTicker": "TEST:CA-NEW"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+	var corporateActionPath = fmt.Sprintf(
+		"%s/corporate-action/%d",
+		testAssetIdString,
+		getCorporateActionIdFromResponse(t, responseBody),
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodPost, corporateActionPath+"/apply", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	assertPersistedAsset(t, testAsset.Id, "TEST:CA-NEW", "Test Asset Corporate Action Symbol Change")
+	assertPersistedAssetVersion(t, testAsset.Id, "2")
+	assert.Equal(t, `{TEST:CA-NEW,STOCKS}`, getTestPlannedAllocationHierarchicalId(t, plannedAllocationId))
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/alias", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(
+		t,
+		`[{"ticker": "TEST:CA-OLD", "validTo": "2023-12-31T00:00:00Z"}]`,
+		removeJSONArrayItemsField(t, responseBody, "id"),
+	)
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodPost, corporateActionPath+"/revert", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	assertPersistedAsset(t, testAsset.Id, "TEST:CA-OLD", "Test Asset Corporate Action Symbol Change")
+	assertPersistedAssetVersion(t, testAsset.Id, "3")
+	assert.Equal(t, `{TEST:CA-OLD,STOCKS}`, getTestPlannedAllocationHierarchicalId(t, plannedAllocationId))
+
+	statusCode, responseBody = sendAssetResourceRequest(t, http.MethodGet, testAssetIdString+"/alias", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, `[]`, responseBody)
+}
+
+// TestPostCorporateActionFailsValidation verifies that corporate actions with terms inconsistent with
+// their type are rejected.
+func TestPostCorporateActionFailsValidation(t *testing.T) {
+
+	var testAsset = insertTestAsset(t, "TEST:CA-INVALID", "Test Asset Corporate Action Invalid")
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		strconv.FormatInt(testAsset.Id, 10)+"/corporate-action",
+		`{"actionType": "REVERSE_SPLIT", "effectiveDate": "2023-06-01T00:00:00Z", "ratioNumerator": 10, "ratioDenominator": 1}`,
+	)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assertProblemJSON(
+		t,
+		`
+			{
+				"type": "urn:open-asset-allocator:problem:validation",
+				"title": "Validation failed",
+				"status": 400,
+				"detail": "Corporate action validation failed",
+				"errors": [
+					{"detail": "Reverse split ratio numerator must be less than its denominator"}
+				]
+			}
+		`,
+		responseBody,
+	)
+}
+
+func getCorporateActionIdFromResponse(t *testing.T, responseBody string) int64 {
+	t.Helper()
+
+	var response struct {
+		Id int64 `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &response))
+	require.NotZero(t, response.Id)
+
+	return response.Id
+}
+
+func assertTestObservationQuantityAndPrice(
+	t *testing.T,
+	assetId int64,
+	observationTimeTag string,
+	expectedQuantity string,
+	expectedPrice string,
+) {
+	t.Helper()
+
+	var quantity, price decimal.Decimal
+	err := inttestinfra.FetchWithDBQuery(
+		`
+			SELECT paf.asset_quantity, paf.asset_market_price
+			FROM portfolio_allocation_fact paf
+			JOIN portfolio_allocation_obs_time paot ON paot.id = paf.observation_time_id
+			WHERE paf.asset_id = {:assetId} AND paot.observation_time_tag = {:timeTag}
+		`,
+		dbx.Params{"assetId": assetId, "timeTag": observationTimeTag},
+		func(rows *dbx.Rows) error {
+			return rows.Scan(&quantity, &price)
+		},
+	)
+	require.NoError(t, err)
+
+	assert.True(t, quantity.Equal(decimal.RequireFromString(expectedQuantity)), quantity.String())
+	assert.True(t, price.Equal(decimal.RequireFromString(expectedPrice)), price.String())
+}
+
+// insertTestPlannedAllocation inserts an allocation plan without execution date holding a single planned
+// allocation of the asset, returning the planned allocation id.
+func insertTestPlannedAllocation(t *testing.T, portfolioId int64, assetId int64, ticker string) int64 {
+	t.Helper()
+
+	var planName = "Test Plan " + ticker
+	err := inttestinfra.ExecuteDBQuery(
+		`
+			WITH inserted_plan AS (
+				INSERT INTO allocation_plan ("name", "type", portfolio_id)
+				VALUES ({:planName}, 'ALLOCATION_PLAN', {:portfolioId})
+				RETURNING id
+			)
+			INSERT INTO planned_allocation (
+				allocation_plan_id, hierarchical_id, asset_id, cash_reserve, slice_size_percentage
+			)
+			SELECT id, ARRAY[{:ticker}, 'STOCKS'], {:assetId}, FALSE, 1
+			FROM inserted_plan
+		`,
+		dbx.Params{"planName": planName, "portfolioId": portfolioId, "ticker": ticker, "assetId": assetId},
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery(
+				`DELETE FROM planned_allocation
+				WHERE allocation_plan_id IN (SELECT id FROM allocation_plan WHERE "name" = {:planName})`,
+				dbx.Params{"planName": planName},
+			).
+			AddCleanupQuery(
+				`DELETE FROM allocation_plan WHERE "name" = {:planName}`,
+				dbx.Params{"planName": planName},
+			).
+			Build(t),
+	)
+
+	var plannedAllocationId int64
+	err = inttestinfra.FetchWithDBQuery(
+		`
+			SELECT pa.id
+			FROM planned_allocation pa
+			JOIN allocation_plan ap ON ap.id = pa.allocation_plan_id
+			WHERE ap."name" = {:planName}
+		`,
+		dbx.Params{"planName": planName},
+		func(rows *dbx.Rows) error {
+			return rows.Scan(&plannedAllocationId)
+		},
+	)
+	require.NoError(t, err)
+
+	return plannedAllocationId
+}
+
+func getTestPlannedAllocationHierarchicalId(t *testing.T, plannedAllocationId int64) string {
+	t.Helper()
+
+	var hierarchicalId string
+	err := inttestinfra.FetchWithDBQuery(
+		"SELECT hierarchical_id::text FROM planned_allocation WHERE id = {:id}",
+		dbx.Params{"id": plannedAllocationId},
+		func(rows *dbx.Rows) error {
+			return rows.Scan(&hierarchicalId)
+		},
+	)
+	require.NoError(t, err)
+
+	return hierarchicalId
+}
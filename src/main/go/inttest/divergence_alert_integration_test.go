@@ -0,0 +1,340 @@
+package inttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	restmodel "github.com/benizzio/open-asset-allocator/api/rest/model"
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+// TestDivergenceAlertRuleLifecycle verifies creating, reading, updating and deleting a divergence alert rule.
+func TestDivergenceAlertRuleLifecycle(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Alert Rule Lifecycle")
+	var portfolioIdPath = strconv.FormatInt(testPortfolio.Id, 10)
+	var allocationPlanId = postTestAlertAllocationPlan(t, portfolioIdPath, "Test Alert Rule Lifecycle Plan")
+	var alertRulePath = portfolioIdPath + "/alert-rule"
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		alertRulePath,
+		`{
+			"allocationPlanId": `+allocationPlanId+`,
+			"name": "Bonds drift",
+			"ruleType": "DRIFT_ABOVE",
+			"hierarchicalId": "BONDS",
+			"thresholdPercent": "5"
+		}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var createdRule restmodel.DivergenceAlertRuleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &createdRule))
+	require.NotNil(t, createdRule.Id)
+	assert.Equal(t, "Bonds drift", createdRule.Name)
+	assert.Equal(t, "DRIFT_ABOVE", createdRule.RuleType)
+	require.NotNil(t, createdRule.HierarchicalId)
+	assert.Equal(t, "BONDS", *createdRule.HierarchicalId)
+	assert.Equal(t, "5", createdRule.ThresholdPercent.String())
+	require.NotNil(t, createdRule.Enabled)
+	assert.True(t, *createdRule.Enabled)
+
+	var alertRuleIdPath = alertRulePath + "/" + strconv.FormatInt(int64(*createdRule.Id), 10)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, alertRuleIdPath, "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPut,
+		alertRuleIdPath,
+		`{
+			"allocationPlanId": `+allocationPlanId+`,
+			"name": "Low cash reserve",
+			"ruleType": "CASH_RESERVE_BELOW",
+			"thresholdPercent": "2.5",
+			"enabled": false
+		}`,
+	)
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	var updatedRule restmodel.DivergenceAlertRuleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &updatedRule))
+	assert.Equal(t, "Low cash reserve", updatedRule.Name)
+	assert.Equal(t, "CASH_RESERVE_BELOW", updatedRule.RuleType)
+	assert.Nil(t, updatedRule.HierarchicalId)
+	assert.Equal(t, "2.5", updatedRule.ThresholdPercent.String())
+	require.NotNil(t, updatedRule.Enabled)
+	assert.False(t, *updatedRule.Enabled)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, alertRulePath, "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	var listedRules []restmodel.DivergenceAlertRuleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &listedRules))
+	require.Len(t, listedRules, 1)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodDelete, alertRuleIdPath, "")
+	require.Equal(t, http.StatusNoContent, statusCode, responseBody)
+
+	statusCode, _ = sendPortfolioResourceRequest(t, http.MethodGet, alertRuleIdPath, "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+// TestDivergenceAlertRuleValidations verifies the rule definitions rejected on creation.
+func TestDivergenceAlertRuleValidations(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Alert Rule Validations")
+	var portfolioIdPath = strconv.FormatInt(testPortfolio.Id, 10)
+	var allocationPlanId = postTestAlertAllocationPlan(t, portfolioIdPath, "Test Alert Rule Validations Plan")
+
+	var testCases = []struct {
+		name            string
+		ruleJSON        string
+		expectedMessage string
+	}{
+		{
+			name: "invalid rule type",
+			ruleJSON: `{"allocationPlanId": ` + allocationPlanId + `, "name": "Rule", "ruleType": "DRIFT_BELOW",
+				"thresholdPercent": "5"}`,
+			expectedMessage: "Invalid divergence alert rule type DRIFT_BELOW",
+		},
+		{
+			name: "threshold above 100",
+			ruleJSON: `{"allocationPlanId": ` + allocationPlanId + `, "name": "Rule", "ruleType": "DRIFT_ABOVE",
+				"thresholdPercent": "100.5"}`,
+			expectedMessage: "Divergence alert rule thresholdPercent must be between 0 and 100",
+		},
+		{
+			name: "hierarchical id on cash reserve rule",
+			ruleJSON: `{"allocationPlanId": ` + allocationPlanId + `, "name": "Rule",
+				"ruleType": "CASH_RESERVE_BELOW", "hierarchicalId": "BONDS", "thresholdPercent": "5"}`,
+			expectedMessage: "Divergence alert rule hierarchicalId only applies to DRIFT_ABOVE rules",
+		},
+		{
+			name:            "allocation plan of another portfolio",
+			ruleJSON:        `{"allocationPlanId": 1, "name": "Rule", "ruleType": "DRIFT_ABOVE", "thresholdPercent": "5"}`,
+			expectedMessage: "Allocation plan 1 is not a plan of the portfolio",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				var statusCode, responseBody = sendPortfolioResourceRequest(
+					t,
+					http.MethodPost,
+					portfolioIdPath+"/alert-rule",
+					testCase.ruleJSON,
+				)
+				assert.Equal(t, http.StatusBadRequest, statusCode, responseBody)
+				assert.Contains(t, responseBody, testCase.expectedMessage)
+			},
+		)
+	}
+}
+
+// TestDivergenceAlertTriggeredOnMerge verifies that merging an observation breaching a rule triggers an
+// alert, which can then be acknowledged and resolved.
+func TestDivergenceAlertTriggeredOnMerge(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Alert Triggered On Merge")
+	var portfolioIdPath = strconv.FormatInt(testPortfolio.Id, 10)
+	var allocationPlanId = postTestAlertAllocationPlan(t, portfolioIdPath, "Test Alert Triggered On Merge Plan")
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		portfolioIdPath+"/alert-rule",
+		`{
+			"allocationPlanId": `+allocationPlanId+`,
+			"name": "Class drift",
+			"ruleType": "DRIFT_ABOVE",
+			"thresholdPercent": "10"
+		}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	// the snapshot only holds bonds, planned as 40% of the portfolio
+	postTestWebhookSnapshot(t, portfolioIdPath, "ALERT_TRIGGERED_ON_MERGE_TEST")
+
+	var alertPath = portfolioIdPath + "/alert"
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, alertPath+"?status=TRIGGERED", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	var alerts []restmodel.DivergenceAlertDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &alerts))
+	require.Len(t, alerts, 2)
+
+	var alertsPerHierarchicalId = make(map[string]restmodel.DivergenceAlertDTS)
+	for _, alert := range alerts {
+		require.NotNil(t, alert.HierarchicalId)
+		alertsPerHierarchicalId[*alert.HierarchicalId] = alert
+	}
+	assert.Equal(t, "60", alertsPerHierarchicalId["BONDS"].ObservedPercent.String())
+	assert.Equal(t, "-60", alertsPerHierarchicalId["STOCKS"].ObservedPercent.String())
+
+	var alertIdPath = alertPath + "/" + strconv.FormatInt(int64(*alertsPerHierarchicalId["BONDS"].Id), 10)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodPost, alertIdPath+"/acknowledge", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	var acknowledgedAlert restmodel.DivergenceAlertDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &acknowledgedAlert))
+	assert.Equal(t, "ACKNOWLEDGED", acknowledgedAlert.Status)
+	assert.NotNil(t, acknowledgedAlert.AcknowledgedAt)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodPost, alertIdPath+"/resolve", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	var resolvedAlert restmodel.DivergenceAlertDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &resolvedAlert))
+	assert.Equal(t, "RESOLVED", resolvedAlert.Status)
+	assert.NotNil(t, resolvedAlert.ResolvedAt)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodPost, alertIdPath+"/acknowledge", "")
+	assert.Equal(t, http.StatusBadRequest, statusCode, responseBody)
+
+	statusCode, _ = sendPortfolioResourceRequest(t, http.MethodGet, alertPath+"/999999999", "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+// TestDivergenceAlertTriggeredOnRevaluation verifies that a revaluation breaching a rule triggers an alert,
+// with the prices refreshed by the revaluation as the only change to the portfolio.
+func TestDivergenceAlertTriggeredOnRevaluation(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Alert Triggered On Revaluation")
+	var portfolioIdPath = strconv.FormatInt(testPortfolio.Id, 10)
+	var allocationPlanId = postTestAlertAllocationPlan(t, portfolioIdPath, "Test Alert Triggered On Revaluation Plan")
+	var stocksAsset = insertTestAsset(t, "TEST:ALERT_REVALUED_STOCK", "Test Asset Alert Revalued Stock")
+	var bondsAsset = insertTestAsset(t, "TEST:ALERT_REVALUED_BOND", "Test Asset Alert Revalued Bond")
+
+	// the observation matches the 60/40 plan before the revaluation
+	var observationTimeTag = "test_alert_triggered_on_revaluation"
+	insertTestPortfolioObservation(t, testPortfolio.Id, observationTimeTag, "2025-01-10 00:00:00", 10, 60, stocksAsset.Id)
+	insertTestPortfolioObservationAllocation(t, testPortfolio.Id, observationTimeTag, "BONDS", 10, 40, bondsAsset.Id)
+	registerTestRevaluationObservationsCleanup(t, testPortfolio.Id)
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		portfolioIdPath+"/alert-rule",
+		`{
+			"allocationPlanId": `+allocationPlanId+`,
+			"name": "Class drift",
+			"ruleType": "DRIFT_ABOVE",
+			"hierarchicalId": "STOCKS",
+			"thresholdPercent": "10"
+		}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	// the stocks price rises from 60 to 150, so that stocks become 1500 of 1900 after the revaluation
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		strconv.FormatInt(stocksAsset.Id, 10)+"/valuation",
+		`{"valuationDate": "2025-03-31T00:00:00Z", "price": "150", "currency": "USD"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var alertPath = portfolioIdPath + "/alert"
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, alertPath+"?status=TRIGGERED", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	var alerts []restmodel.DivergenceAlertDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &alerts))
+	require.Empty(t, alerts)
+
+	var schedulePath = portfolioIdPath + "/schedule"
+	statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		schedulePath,
+		`{"taskType": "REVALUATION", "cronExpression": "0 0 1 * *"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var schedule restmodel.PortfolioScheduleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &schedule))
+	var scheduleId = int64(*schedule.Id)
+	setTestScheduleNextRun(t, scheduleId, time.Now().Add(-time.Second))
+
+	var runs = waitForTestScheduleRuns(t, schedulePath+"/"+strconv.FormatInt(scheduleId, 10), 1)
+	require.Len(t, runs, 1)
+	require.Equal(t, "SUCCEEDED", runs[0].Status, runs[0].ErrorMessage)
+
+	var runResult struct {
+		RevaluedAllocations int `json:"revaluedAllocations"`
+		TriggeredAlerts     int `json:"triggeredAlerts"`
+	}
+	require.NoError(t, json.Unmarshal(runs[0].Result, &runResult))
+	assert.Equal(t, 1, runResult.RevaluedAllocations)
+	assert.Equal(t, 1, runResult.TriggeredAlerts)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, alertPath+"?status=TRIGGERED", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &alerts))
+	require.Len(t, alerts, 1)
+	require.NotNil(t, alerts[0].HierarchicalId)
+	assert.Equal(t, "STOCKS", *alerts[0].HierarchicalId)
+	assert.True(t, alerts[0].ObservedPercent.GreaterThan(decimal.NewFromInt(10)), alerts[0].ObservedPercent.String())
+}
+
+// postTestAlertAllocationPlan creates a 60/40 stocks and bonds allocation plan for a test portfolio,
+// registering its cleanup, and returns its id.
+func postTestAlertAllocationPlan(t *testing.T, portfolioIdPath string, allocationPlanName string) string {
+	t.Helper()
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery(
+				`
+				DELETE FROM planned_allocation
+				WHERE allocation_plan_id IN (SELECT id FROM allocation_plan WHERE name = {:name})
+				`,
+				dbx.Params{"name": allocationPlanName},
+			).
+			AddCleanupQuery(`DELETE FROM allocation_plan WHERE name = {:name}`, dbx.Params{"name": allocationPlanName}).
+			Build(t),
+	)
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		portfolioIdPath+"/allocation-plan",
+		`{
+			"name": "`+allocationPlanName+`",
+			"details": [
+				{ "hierarchicalId": [null, "STOCKS"], "sliceSizePercentage": "0.6" },
+				{ "hierarchicalId": [null, "BONDS"], "sliceSizePercentage": "0.4" },
+				{
+					"hierarchicalId": ["ARCA:BIL", "BONDS"],
+					"sliceSizePercentage": "1",
+					"asset": { "id": 1, "name": "SPDR Bloomberg 1-3 Month T-Bill ETF", "ticker": "ARCA:BIL" }
+				}
+			]
+		}`,
+	)
+	require.Equal(t, http.StatusNoContent, statusCode, responseBody)
+
+	var allocationPlanId string
+	err := inttestinfra.FetchWithDBQuery(
+		"SELECT id FROM allocation_plan WHERE name = {:name}",
+		dbx.Params{"name": allocationPlanName},
+		func(rows *dbx.Rows) error {
+			return rows.Scan(&allocationPlanId)
+		},
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, allocationPlanId)
+
+	return allocationPlanId
+}
@@ -1,9 +1,11 @@
 package inttest
 
 import (
+	"log/slog"
 	"net/http"
+	"testing"
 
-	"github.com/golang/glog"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
 )
 
 // deferCloseResponseBody closes an HTTP response body and logs any error.
@@ -22,6 +24,12 @@ func deferCloseResponseBody(response *http.Response) {
 	}
 	err := response.Body.Close()
 	if err != nil {
-		glog.Errorf("Error closing response body: %v", err)
+		slog.Error("Error closing response body", "error", err)
 	}
 }
+
+// assertProblemJSON compares an application/problem+json error response body with the expected problem,
+// ignoring its instance, the path of the request that failed.
+func assertProblemJSON(t *testing.T, expectedJSON string, actualJSON string) {
+	inttestutil.AssertJSONEqualIgnoringFields(t, expectedJSON, actualJSON, "instance")
+}
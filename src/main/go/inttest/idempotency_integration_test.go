@@ -0,0 +1,111 @@
+package inttest
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+func postPortfolioWithIdempotencyKey(t *testing.T, postPortfolioJSON string, idempotencyKey string) *http.Response {
+
+	request, err := http.NewRequest(
+		http.MethodPost,
+		inttestinfra.TestAPIURLPrefix+"/portfolio",
+		strings.NewReader(postPortfolioJSON),
+	)
+	require.NoError(t, err)
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Idempotency-Key", idempotencyKey)
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+
+	return response
+}
+
+func TestPostWithIdempotencyKeyReplaysResponse(t *testing.T) {
+
+	var testPortfolioName = "Test Portfolio idempotent creation"
+	var idempotencyKey = "test-portfolio-idempotent-creation"
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery("DELETE FROM portfolio WHERE name={:name}", dbx.Params{"name": testPortfolioName}).
+			AddCleanupQuery("DELETE FROM idempotency_key WHERE idempotency_key={:key}", dbx.Params{"key": idempotencyKey}).
+			Build(t),
+	)
+
+	var postPortfolioJSON = `{"name":"` + testPortfolioName + `"}`
+
+	response := postPortfolioWithIdempotencyKey(t, postPortfolioJSON, idempotencyKey)
+	defer deferCloseResponseBody(response)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Empty(t, response.Header.Get("Idempotent-Replayed"))
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	replayedResponse := postPortfolioWithIdempotencyKey(t, postPortfolioJSON, idempotencyKey)
+	defer deferCloseResponseBody(replayedResponse)
+
+	assert.Equal(t, http.StatusCreated, replayedResponse.StatusCode)
+	assert.Equal(t, "true", replayedResponse.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, response.Header.Get("ETag"), replayedResponse.Header.Get("ETag"))
+	assert.Contains(t, replayedResponse.Header.Get("Content-Type"), "application/json")
+
+	replayedBody, err := io.ReadAll(replayedResponse.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(body), string(replayedBody))
+
+	inttestutil.AssertDBWithQueryMultipleRows(
+		t,
+		"SELECT count(*) AS count FROM portfolio WHERE name = '"+testPortfolioName+"'",
+		[]inttestutil.AssertableNullStringMap{
+			{"count": inttestutil.ToAssertableNullString("1")},
+		},
+	)
+}
+
+func TestPostWithIdempotencyKeyFailureWithDifferentRequest(t *testing.T) {
+
+	var testPortfolioName = "Test Portfolio idempotent key reuse"
+	var idempotencyKey = "test-portfolio-idempotent-key-reuse"
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery("DELETE FROM portfolio WHERE name={:name}", dbx.Params{"name": testPortfolioName}).
+			AddCleanupQuery("DELETE FROM idempotency_key WHERE idempotency_key={:key}", dbx.Params{"key": idempotencyKey}).
+			Build(t),
+	)
+
+	response := postPortfolioWithIdempotencyKey(t, `{"name":"`+testPortfolioName+`"}`, idempotencyKey)
+	defer deferCloseResponseBody(response)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	reusedResponse := postPortfolioWithIdempotencyKey(t, `{"name":"`+testPortfolioName+` 2"}`, idempotencyKey)
+	defer deferCloseResponseBody(reusedResponse)
+	assert.Equal(t, http.StatusUnprocessableEntity, reusedResponse.StatusCode)
+
+	body, err := io.ReadAll(reusedResponse.Body)
+	require.NoError(t, err)
+	assertProblemJSON(
+		t,
+		`{
+			"type": "urn:open-asset-allocator:problem:idempotency-key-reused",
+			"title": "Idempotency key reused",
+			"status": 422,
+			"detail": "Idempotency key was already used with a different request"
+		}`,
+		string(body),
+	)
+}
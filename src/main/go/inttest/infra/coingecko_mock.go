@@ -0,0 +1,75 @@
+package infra
+
+import (
+	"testing"
+
+	"github.com/nhatthm/httpmock"
+)
+
+var coinGeckoMockServer *httpmock.Server
+
+// SetCoinGeckoMockServer stores the shared CoinGecko mock server instance for the integration test
+// suite.
+func SetCoinGeckoMockServer(mockServer *httpmock.Server) {
+	coinGeckoMockServer = mockServer
+}
+
+// BuildAndStartCoinGeckoMockServer creates and starts the shared CoinGecko mock server used by
+// integration tests. The server is started once for the suite and individual tests must reset its
+// expectations to preserve isolation.
+func BuildAndStartCoinGeckoMockServer() *httpmock.Server {
+	var mockServer = httpmock.NewServer()
+	mockServer.WithDefaultResponseHeaders(map[string]string{"Content-Type": "application/json"})
+	return mockServer
+}
+
+// GetCoinGeckoMockServer returns the shared CoinGecko mock server instance.
+func GetCoinGeckoMockServer() *httpmock.Server {
+	return coinGeckoMockServer
+}
+
+// SetupCoinGeckoMockTest resets the shared CoinGecko mock server for the given test and registers
+// cleanup that verifies all expectations were met and clears state afterwards.
+func SetupCoinGeckoMockTest(t *testing.T) *httpmock.Server {
+	t.Helper()
+
+	var mockServer = setupCoinGeckoMockServer(t)
+
+	t.Cleanup(func() {
+		if err := mockServer.ExpectationsWereMet(); err != nil {
+			t.Errorf("CoinGecko mock expectations were not met: %v", err)
+		}
+		resetMockServer(mockServer)
+	})
+
+	return mockServer
+}
+
+// SetupCoinGeckoMockTestWithoutVerification resets the shared CoinGecko mock server for the given
+// test without verifying its expectations afterwards. Used when a concurrent external asset search
+// may be cancelled by another source failure before reaching CoinGecko.
+func SetupCoinGeckoMockTestWithoutVerification(t *testing.T) *httpmock.Server {
+	t.Helper()
+
+	var mockServer = setupCoinGeckoMockServer(t)
+
+	t.Cleanup(func() {
+		resetMockServer(mockServer)
+	})
+
+	return mockServer
+}
+
+func setupCoinGeckoMockServer(t *testing.T) *httpmock.Server {
+	t.Helper()
+
+	var mockServer = GetCoinGeckoMockServer()
+	if mockServer == nil {
+		t.Fatalf("CoinGecko mock server is not initialized; configure it in test bootstrap")
+	}
+
+	mockServer.WithTest(t)
+	resetMockServer(mockServer)
+
+	return mockServer
+}
@@ -3,13 +3,13 @@ package infra
 import (
 	"context"
 	"io"
+	"log/slog"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	dbx "github.com/go-ozzo/ozzo-dbx"
-	"github.com/golang/glog"
 	"github.com/moby/moby/api/types/container"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -92,7 +92,7 @@ func runFlywayTestcontainer(ctx context.Context) error {
 		1,
 	)
 
-	glog.Info("Starting flyway testcontainer with connection ", flywayConnectionString)
+	slog.Info("Starting flyway testcontainer", "connection", flywayConnectionString)
 	var flywayContainerRequest = testcontainers.ContainerRequest{
 		Image: FlywayImage,
 		Cmd: []string{
@@ -132,7 +132,7 @@ func runFlywayTestcontainer(ctx context.Context) error {
 
 	defer func() {
 		if err := flywayContainer.Terminate(ctx); err != nil {
-			glog.Errorf("failed to terminate flyway container: %s", err)
+			slog.Error("failed to terminate flyway container", "error", err)
 		}
 	}()
 
@@ -144,21 +144,21 @@ func runFlywayTestcontainer(ctx context.Context) error {
 		// Get logs even if the container exited
 		logReader, err := flywayContainer.Logs(ctx)
 		if err != nil {
-			glog.Errorf("failed to retrieve logs: %s", err)
+			slog.Error("failed to retrieve logs", "error", err)
 		} else {
 			defer func(logReader io.ReadCloser) {
 				err := logReader.Close()
 				if err != nil {
-					glog.Errorf("failed to close log reader: %s", err)
+					slog.Error("failed to close log reader", "error", err)
 				}
 			}(logReader)
 			logContent, _ := io.ReadAll(logReader)
-			glog.Infof("Flyway container logs:\n%s", string(logContent))
+			slog.Info("Flyway container logs", "logs", string(logContent))
 		}
 	}
 
 	if err != nil {
-		glog.Errorf("failed to start flyway container: %s", err)
+		slog.Error("failed to start flyway container", "error", err)
 		return err
 	}
 
@@ -187,12 +187,48 @@ func BuildAndStartApplication() root.App {
 		SearchURL: GetYahooFinanceMockServer().URL() + "/v1/finance/search",
 		ChartURL:  GetYahooFinanceMockServer().URL() + "/v8/finance/chart/",
 	}
+	var stooqConfig = infra.StooqConfiguration{
+		BaseURL: GetStooqMockServer().URL(),
+	}
+	var coinGeckoConfig = infra.CoinGeckoConfiguration{
+		BaseURL:    GetCoinGeckoMockServer().URL(),
+		VsCurrency: "usd",
+	}
+
+	// quote only, so the external asset searches of the other tests do not reach it
+	var jsonProviderConfig = infra.JSONProviderConfiguration{
+		Source:           JSONProviderTestSource,
+		QuoteURLTemplate: GetJSONProviderMockServer().URL() + "/quote/{exchangeId}/{ticker}",
+		Headers:          map[string]string{"X-Api-Key": "test-json-feed-key"},
+		QuoteConcurrency: 2,
+		QuoteMapping: infra.JSONProviderQuoteMapping{
+			PricePath:    "$.data.quote.close",
+			DatePath:     "$.data.quote.timestamp",
+			DateFormat:   "UNIX",
+			CurrencyPath: "$.data.currency",
+			Currency:     "EUR",
+		},
+	}
 
 	var testConfig = infra.Configuration{
 		GinServerConfig: ginServerConfig,
 		RdbmsConfig:     dbConfig,
 		IntegrationConfig: infra.IntegrationConfiguration{
-			YahooFinanceConfig: yahooFinanceConfig,
+			YahooFinanceConfig:  yahooFinanceConfig,
+			StooqConfig:         stooqConfig,
+			CoinGeckoConfig:     coinGeckoConfig,
+			JSONProviderConfigs: []infra.JSONProviderConfiguration{jsonProviderConfig},
+		},
+		// frequent ticks, so the schedules made due by the tests run without waiting
+		SchedulerConfig: infra.SchedulerConfiguration{
+			TickInterval:     200 * time.Millisecond,
+			MisfireThreshold: 10 * time.Minute,
+		},
+		// frequent dispatches and short retries, so the tests see deliveries settle without waiting
+		WebhookConfig: infra.WebhookConfiguration{
+			DispatchInterval: 200 * time.Millisecond,
+			RetryBaseDelay:   200 * time.Millisecond,
+			MaxAttempts:      2,
 		},
 	}
 
@@ -215,7 +251,7 @@ func ExecuteDBQuery(sql string, params dbx.Params) error {
 	}
 	_, err := query.Execute()
 	if err != nil {
-		glog.Errorf("Error executing query: %s", err)
+		slog.Error("Error executing query", "error", err)
 		return err
 	}
 
@@ -235,26 +271,26 @@ func FetchWithDBQuery(sql string, params dbx.Params, rowMappingFunction func(row
 	}
 	rows, err := query.Rows()
 	if err != nil {
-		glog.Errorf("Error executing query: %s", err)
+		slog.Error("Error executing query", "error", err)
 		return err
 	}
 
 	defer func(rows *dbx.Rows) {
 		err := rows.Close()
 		if err != nil {
-			glog.Errorf("Error closing rows: %s", err)
+			slog.Error("Error closing rows", "error", err)
 		}
 	}(rows)
 
 	for rows.Next() {
 		if err := rowMappingFunction(rows); err != nil {
-			glog.Errorf("Error mapping row: %s", err)
+			slog.Error("Error mapping row", "error", err)
 			return err
 		}
 	}
 
 	if err := rows.Err(); err != nil {
-		glog.Errorf("Error iterating rows: %s", err)
+		slog.Error("Error iterating rows", "error", err)
 		return err
 	}
 
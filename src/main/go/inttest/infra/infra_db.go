@@ -2,13 +2,13 @@ package infra
 
 import (
 	"context"
+	"log/slog"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	dbx "github.com/go-ozzo/ozzo-dbx"
-	"github.com/golang/glog"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -119,7 +119,7 @@ func withPostgresLogging() testcontainers.CustomizeRequestOption {
 
 func buildAndRunPostgresqlTestcontainer(ctx context.Context) (string, error) {
 
-	glog.Info("Starting PostgreSQL testcontainer...")
+	slog.Info("Starting PostgreSQL testcontainer...")
 
 	postgresContainer, err := postgres.Run(
 		ctx, PostgresqlImage,
@@ -141,30 +141,30 @@ func buildAndRunPostgresqlTestcontainer(ctx context.Context) (string, error) {
 
 	var terminateContainerDefer = func() {
 		if err := testcontainers.TerminateContainer(postgresContainer); err != nil {
-			glog.Errorf("failed to terminate container: %s", err)
+			slog.Error("failed to terminate container", "error", err)
 		}
 	}
 	deferRegistry.RegisterDefer(terminateContainerDefer)
 
 	if err != nil {
-		glog.Errorf("failed to start container: %s", err)
+		slog.Error("failed to start container", "error", err)
 		return "", err
 	}
 
 	connectionString, err := postgresContainer.ConnectionString(ctx)
 	if err != nil {
-		glog.Errorf("failed to obtain connection string: %s", err)
+		slog.Error("failed to obtain connection string", "error", err)
 		return "", err
 	}
 
-	glog.Info("PostgreSQL testcontainer initialized with no errors as ", connectionString)
+	slog.Info("PostgreSQL testcontainer initialized with no errors", "connection", connectionString)
 
 	state, err := postgresContainer.State(ctx)
 	if err != nil {
-		glog.Errorf("failed to get container state: %s", err)
+		slog.Error("failed to get container state", "error", err)
 		return "", err
 	}
-	glog.Infof("PostgreSQL container state is '%s'", state.Status)
+	slog.Info("PostgreSQL container state", "status", state.Status)
 
 	return connectionString, nil
 }
@@ -177,7 +177,7 @@ func buildDatabaseConnection(ctx context.Context) error {
 		PostgresqlConnectionString+PostgresqlConnectionStringParameters,
 	)
 	if err != nil {
-		glog.Errorf("Error opening DB connection: %s", err)
+		slog.Error("Error opening DB connection", "error", err)
 		return err
 	}
 
@@ -185,7 +185,7 @@ func buildDatabaseConnection(ctx context.Context) error {
 	var closeDBConnectionDefer = func() {
 		err := DatabaseConnection.Close()
 		if err != nil {
-			glog.Errorf("Error closing DB connection: %s", err)
+			slog.Error("Error closing DB connection", "error", err)
 		}
 	}
 	deferRegistry.RegisterDefer(closeDBConnectionDefer)
@@ -0,0 +1,56 @@
+package infra
+
+import (
+	"testing"
+
+	"github.com/nhatthm/httpmock"
+)
+
+// JSONProviderTestSource is the source of the JSON provider declared in the integration test
+// configuration, served by the JSON provider mock server.
+const JSONProviderTestSource = "TEST_JSON_FEED"
+
+var jsonProviderMockServer *httpmock.Server
+
+// SetJSONProviderMockServer stores the shared JSON provider mock server instance for the integration
+// test suite.
+func SetJSONProviderMockServer(mockServer *httpmock.Server) {
+	jsonProviderMockServer = mockServer
+}
+
+// BuildAndStartJSONProviderMockServer creates and starts the shared JSON provider mock server used by
+// integration tests. The server is started once for the suite and individual tests must reset its
+// expectations to preserve isolation.
+func BuildAndStartJSONProviderMockServer() *httpmock.Server {
+	var mockServer = httpmock.NewServer()
+	mockServer.WithDefaultResponseHeaders(map[string]string{"Content-Type": "application/json"})
+	return mockServer
+}
+
+// GetJSONProviderMockServer returns the shared JSON provider mock server instance.
+func GetJSONProviderMockServer() *httpmock.Server {
+	return jsonProviderMockServer
+}
+
+// SetupJSONProviderMockTest resets the shared JSON provider mock server for the given test and
+// registers cleanup that verifies all expectations were met and clears state afterwards.
+func SetupJSONProviderMockTest(t *testing.T) *httpmock.Server {
+	t.Helper()
+
+	var mockServer = GetJSONProviderMockServer()
+	if mockServer == nil {
+		t.Fatalf("JSON provider mock server is not initialized; configure it in test bootstrap")
+	}
+
+	mockServer.WithTest(t)
+	resetMockServer(mockServer)
+
+	t.Cleanup(func() {
+		if err := mockServer.ExpectationsWereMet(); err != nil {
+			t.Errorf("JSON provider mock expectations were not met: %v", err)
+		}
+		resetMockServer(mockServer)
+	})
+
+	return mockServer
+}
@@ -0,0 +1,51 @@
+package infra
+
+import (
+	"testing"
+
+	"github.com/nhatthm/httpmock"
+)
+
+var stooqMockServer *httpmock.Server
+
+// SetStooqMockServer stores the shared Stooq mock server instance for the integration test suite.
+func SetStooqMockServer(mockServer *httpmock.Server) {
+	stooqMockServer = mockServer
+}
+
+// BuildAndStartStooqMockServer creates and starts the shared Stooq mock server used by integration
+// tests. The server is started once for the suite and individual tests must reset its expectations
+// to preserve isolation.
+func BuildAndStartStooqMockServer() *httpmock.Server {
+	var mockServer = httpmock.NewServer()
+	mockServer.WithDefaultResponseHeaders(map[string]string{"Content-Type": "text/csv"})
+	return mockServer
+}
+
+// GetStooqMockServer returns the shared Stooq mock server instance.
+func GetStooqMockServer() *httpmock.Server {
+	return stooqMockServer
+}
+
+// SetupStooqMockTest resets the shared Stooq mock server for the given test and registers cleanup
+// that verifies all expectations were met and clears state afterwards.
+func SetupStooqMockTest(t *testing.T) *httpmock.Server {
+	t.Helper()
+
+	var mockServer = GetStooqMockServer()
+	if mockServer == nil {
+		t.Fatalf("Stooq mock server is not initialized; configure it in test bootstrap")
+	}
+
+	mockServer.WithTest(t)
+	resetMockServer(mockServer)
+
+	t.Cleanup(func() {
+		if err := mockServer.ExpectationsWereMet(); err != nil {
+			t.Errorf("Stooq mock expectations were not met: %v", err)
+		}
+		resetMockServer(mockServer)
+	})
+
+	return mockServer
+}
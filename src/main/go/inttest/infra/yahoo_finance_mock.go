@@ -51,23 +51,23 @@ func SetupYahooFinanceMockTest(t *testing.T) *httpmock.Server {
 	}
 
 	mockServer.WithTest(t)
-	resetYahooFinanceMockServer(mockServer)
+	resetMockServer(mockServer)
 
 	t.Cleanup(func() {
 		if err := mockServer.ExpectationsWereMet(); err != nil {
 			t.Errorf("Yahoo Finance mock expectations were not met: %v", err)
 		}
-		resetYahooFinanceMockServer(mockServer)
+		resetMockServer(mockServer)
 	})
 
 	return mockServer
 }
 
-// resetYahooFinanceMockServer clears all expectations and request history from the shared mock
-// server to preserve test isolation between integration tests.
+// resetMockServer clears all expectations and request history from a shared mock server to
+// preserve test isolation between integration tests.
 //
 // Authored by: GitHub Copilot
-func resetYahooFinanceMockServer(mockServer *httpmock.Server) {
+func resetMockServer(mockServer *httpmock.Server) {
 	if mockServer == nil {
 		return
 	}
@@ -35,6 +35,24 @@ func setupAndRunTests(ctx context.Context, m *testing.M) int {
 	}()
 	inttestinfra.SetYahooFinanceMockServer(yahooFinanceMockServer)
 
+	var stooqMockServer = inttestinfra.BuildAndStartStooqMockServer()
+	defer func() {
+		stooqMockServer.Close()
+	}()
+	inttestinfra.SetStooqMockServer(stooqMockServer)
+
+	var coinGeckoMockServer = inttestinfra.BuildAndStartCoinGeckoMockServer()
+	defer func() {
+		coinGeckoMockServer.Close()
+	}()
+	inttestinfra.SetCoinGeckoMockServer(coinGeckoMockServer)
+
+	var jsonProviderMockServer = inttestinfra.BuildAndStartJSONProviderMockServer()
+	defer func() {
+		jsonProviderMockServer.Close()
+	}()
+	inttestinfra.SetJSONProviderMockServer(jsonProviderMockServer)
+
 	var app = inttestinfra.BuildAndStartApplication()
 	defer func() {
 		app.Stop()
@@ -0,0 +1,243 @@
+package inttest
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	restmodel "github.com/benizzio/open-asset-allocator/api/rest/model"
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+const jobCompletionTimeout = 10 * time.Second
+
+// TestAssetEventImportJob verifies submitting an asset event import job, following it until it finishes
+// and finding it in the job list.
+func TestAssetEventImportJob(t *testing.T) {
+
+	var yahooFinanceMockServer = inttestinfra.SetupYahooFinanceMockTest(t)
+
+	var importedAsset = insertTestAsset(t, "TEST:JOB-EVENTS", "Test Asset Job Events")
+	var skippedAsset = insertTestAsset(t, "TEST:JOB-NO-EVENTS", "Test Asset Job No Events")
+
+	linkTestExternalAssets(
+		t,
+		strconv.FormatInt(importedAsset.Id, 10),
+		`{"source": "YAHOO_FINANCE", "ticker": "TSTJ", "exchangeId": "NMS"}`,
+	)
+
+	yahooFinanceMockServer.ExpectGet(fmt.Sprintf(yahooFinanceEventsRequestURIFormat, "TSTJ")).
+		WithHeader("User-Agent", yahooFinanceExpectedUserAgent).
+		Return(`
+			{
+				"chart": {
+					"result": [
+						{
+							"meta": {"symbol": "TSTJ", "exchangeName": "NMS", "currency": "USD"},
+							"timestamp": [1676039400],
+							"indicators": {"quote": [{"close": [400.0]}]},
+							"events": {"dividends": {"1676039400": {"amount": 0.5, "date": 1676039400}}}
+						}
+					]
+				}
+			}
+		`)
+
+	var statusCode, responseBody = sendJobResourceRequest(
+		t,
+		http.MethodPost,
+		"",
+		fmt.Sprintf(
+			`{"type": "ASSET_EVENT_IMPORT", "params": {"assetIds": [%d, %d]}}`,
+			importedAsset.Id,
+			skippedAsset.Id,
+		),
+	)
+	require.Equal(t, http.StatusAccepted, statusCode, responseBody)
+
+	var submittedJob restmodel.JobDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &submittedJob))
+	require.NotNil(t, submittedJob.Id)
+	cleanupTestJob(t, int64(*submittedJob.Id))
+
+	assert.Equal(t, "ASSET_EVENT_IMPORT", submittedJob.Type)
+	assert.Equal(t, "PENDING", submittedJob.Status)
+
+	var jobIdString = strconv.FormatInt(int64(*submittedJob.Id), 10)
+
+	// jobs submitted without authentication have no creator
+	inttestutil.AssertDBWithQuery(
+		t,
+		"SELECT created_by_user_id FROM job WHERE id="+jobIdString,
+		dbx.NullStringMap{"created_by_user_id": sql.NullString{}},
+	)
+
+	var finishedJob = waitForTestJobToFinish(t, jobIdString)
+
+	assert.Equal(t, "SUCCEEDED", finishedJob.Status)
+	assert.Equal(t, 2, finishedJob.ProgressCompleted)
+	assert.Equal(t, 2, finishedJob.ProgressTotal)
+	assert.NotNil(t, finishedJob.StartedAt)
+	assert.NotNil(t, finishedJob.FinishedAt)
+	assert.JSONEq(t, `{"importedAssets": 1, "skippedAssets": 1, "failures": []}`, string(finishedJob.Result))
+
+	statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodGet,
+		strconv.FormatInt(importedAsset.Id, 10)+"/event",
+		"",
+	)
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Contains(t, responseBody, `"eventType":"DIVIDEND"`)
+
+	statusCode, responseBody = sendJobResourceRequest(t, http.MethodGet, "?status=SUCCEEDED&type=ASSET_EVENT_IMPORT", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	var listedJobs []restmodel.JobDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &listedJobs))
+	assert.True(
+		t,
+		containsTestJob(listedJobs, int64(*submittedJob.Id)),
+		"expected job %s in the job list",
+		jobIdString,
+	)
+
+	statusCode, responseBody = sendJobResourceRequest(t, http.MethodPost, "/"+jobIdString+"/cancel", "")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assertProblemJSON(
+		t,
+		`{
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"detail": "Job `+jobIdString+` is already finished"
+		}`,
+		responseBody,
+	)
+}
+
+// TestSubmitJobFailsWithInvalidType verifies that a job of a type without handler is rejected.
+func TestSubmitJobFailsWithInvalidType(t *testing.T) {
+
+	var statusCode, responseBody = sendJobResourceRequest(t, http.MethodPost, "", `{"type": "UNKNOWN"}`)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assertProblemJSON(
+		t,
+		`{
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"detail": "Invalid job type UNKNOWN"
+		}`,
+		responseBody,
+	)
+}
+
+// TestSubmitJobFailsWithInvalidParams verifies that a job with parameters rejected by its handler is not
+// submitted.
+func TestSubmitJobFailsWithInvalidParams(t *testing.T) {
+
+	var statusCode, responseBody = sendJobResourceRequest(
+		t,
+		http.MethodPost,
+		"",
+		`{"type": "ASSET_EVENT_IMPORT", "params": {"assetIds": [0]}}`,
+	)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assertProblemJSON(
+		t,
+		`{
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"detail": "Invalid asset id 0"
+		}`,
+		responseBody,
+	)
+}
+
+// TestGetJobNotFound verifies the response for a job that does not exist.
+func TestGetJobNotFound(t *testing.T) {
+
+	var statusCode, _ = sendJobResourceRequest(t, http.MethodGet, "/999999999", "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	statusCode, _ = sendJobResourceRequest(t, http.MethodPost, "/999999999/cancel", "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+// sendJobResourceRequest sends a request to an endpoint of the job resource, relative to the /api/job
+// path, and returns the response status code and body.
+func sendJobResourceRequest(t *testing.T, method string, path string, requestJSON string) (int, string) {
+	t.Helper()
+
+	var requestBody io.Reader
+	if requestJSON != "" {
+		requestBody = strings.NewReader(requestJSON)
+	}
+
+	request, err := http.NewRequest(method, inttestinfra.TestAPIURLPrefix+"/job"+path, requestBody)
+	require.NoError(t, err)
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	responseBody, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(responseBody)
+}
+
+// waitForTestJobToFinish polls a job until it reaches a final status, failing the test on timeout.
+func waitForTestJobToFinish(t *testing.T, jobIdString string) restmodel.JobDTS {
+	t.Helper()
+
+	var job restmodel.JobDTS
+	require.Eventually(
+		t,
+		func() bool {
+			var statusCode, responseBody = sendJobResourceRequest(t, http.MethodGet, "/"+jobIdString, "")
+			if statusCode != http.StatusOK || json.Unmarshal([]byte(responseBody), &job) != nil {
+				return false
+			}
+			return job.Status == "SUCCEEDED" || job.Status == "FAILED" || job.Status == "CANCELLED"
+		},
+		jobCompletionTimeout,
+		50*time.Millisecond,
+	)
+
+	return job
+}
+
+func containsTestJob(jobs []restmodel.JobDTS, jobId int64) bool {
+	for _, job := range jobs {
+		if job.Id != nil && int64(*job.Id) == jobId {
+			return true
+		}
+	}
+	return false
+}
+
+func cleanupTestJob(t *testing.T, jobId int64) {
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery("DELETE FROM job WHERE id={:id}", dbx.Params{"id": jobId}).
+			Build(t),
+	)
+}
@@ -0,0 +1,87 @@
+package inttest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/infra/openapi"
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+)
+
+// TestGetOpenAPIDocument verifies the OpenAPI document is generated from the metadata of the routes, with
+// the schemas of their bodies described from the DTS types and their validate tags.
+func TestGetOpenAPIDocument(t *testing.T) {
+
+	response, err := http.Get(inttestinfra.TestAPIURLPrefix + "/openapi.json")
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, response.Header.Get("Content-Type"), "application/json")
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	var document openapi.Document
+	require.NoError(t, json.Unmarshal(body, &document))
+
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+
+	require.Contains(t, document.Paths, "/api/portfolio/{portfolioId}")
+	var getPortfolioOperation = (*document.Paths["/api/portfolio/{portfolioId}"])["get"]
+	require.NotNil(t, getPortfolioOperation)
+	assert.Equal(t, "getPortfolio", getPortfolioOperation.OperationId)
+	assert.Equal(t, []string{"Portfolio"}, getPortfolioOperation.Tags)
+	assert.Equal(
+		t,
+		"#/components/schemas/PortfolioDTS",
+		getPortfolioOperation.Responses["200"].Content["application/json"].Schema.Ref,
+	)
+	assert.Equal(
+		t,
+		"#/components/schemas/ProblemDetails",
+		getPortfolioOperation.Responses["default"].Content["application/problem+json"].Schema.Ref,
+	)
+
+	require.Contains(t, document.Components.Schemas, "PortfolioDTS")
+	var portfolioSchema = document.Components.Schemas["PortfolioDTS"]
+	assert.Contains(t, portfolioSchema.Required, "name")
+	assert.Equal(t, 100, *portfolioSchema.Properties["name"].MaxLength)
+
+	assert.NotContains(t, document.Paths, "/api/openapi.json")
+}
+
+// TestGetSwaggerUI verifies the Swagger UI browsing the OpenAPI document is served with its assets, loading
+// nothing from other origins.
+func TestGetSwaggerUI(t *testing.T) {
+
+	response, err := http.Get(inttestinfra.TestAPIURLPrefix + "/docs")
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, response.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, response.Header.Get("Content-Security-Policy"), "default-src 'self'")
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `src="docs/assets/swagger-ui-bundle.js"`)
+	assert.NotContains(t, string(body), "unpkg.com")
+
+	for _, assetName := range []string{"swagger-ui.css", "swagger-ui-bundle.js", "swagger-ui-init.js"} {
+		assetResponse, err := http.Get(inttestinfra.TestAPIURLPrefix + "/docs/assets/" + assetName)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, assetResponse.StatusCode, assetName)
+		deferCloseResponseBody(assetResponse)
+	}
+
+	assetResponse, err := http.Get(inttestinfra.TestAPIURLPrefix + "/docs/assets/VERSION")
+	require.NoError(t, err)
+	defer deferCloseResponseBody(assetResponse)
+	assert.Equal(t, http.StatusNotFound, assetResponse.StatusCode)
+}
@@ -1018,15 +1018,17 @@ func TestPostPortfolioAllocationValidations(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'observationTimestamp' failed validation: is required",
-						"Field 'allocations[0].class' failed validation: is required",
-						"Field 'allocations[0].totalMarketValue' failed validation: is required"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/observationTimestamp", "detail": "Field 'observationTimestamp' failed validation: is required"},
+						{"pointer": "/allocations/0/class", "detail": "Field 'allocations[0].class' failed validation: is required"},
+						{"pointer": "/allocations/0/totalMarketValue", "detail": "Field 'allocations[0].totalMarketValue' failed validation: is required"}
 					]
 				}
 			`
-			assert.JSONEq(t, expectedResponseJSON, actualResponseJSONNullFields)
+			assertProblemJSON(t, expectedResponseJSON, actualResponseJSONNullFields)
 		},
 	)
 
@@ -1057,13 +1059,15 @@ func TestPostPortfolioAllocationValidations(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'allocations[0].assetId' failed validation: if assetId is not provided, assetTicker and assetName must be provided"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/allocations/0/assetId", "detail": "Field 'allocations[0].assetId' failed validation: if assetId is not provided, assetTicker and assetName must be provided"}
 					]
 				}
 			`
-			assert.JSONEq(t, expectedResponseJSON, actualResponseJSONNullFields)
+			assertProblemJSON(t, expectedResponseJSON, actualResponseJSONNullFields)
 		},
 	)
 
@@ -1097,13 +1101,15 @@ func TestPostPortfolioAllocationValidations(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'allocations[0].assetId' failed validation: if assetId is not provided, assetTicker and assetName must be provided"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/allocations/0/assetId", "detail": "Field 'allocations[0].assetId' failed validation: if assetId is not provided, assetTicker and assetName must be provided"}
 					]
 				}
 			`
-			assert.JSONEq(t, expectedResponseJSON, actualResponseJSONNullFields)
+			assertProblemJSON(t, expectedResponseJSON, actualResponseJSONNullFields)
 		},
 	)
 
@@ -1131,14 +1137,16 @@ func TestPostPortfolioAllocationValidations(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'observationTimestamp' failed validation: is required",
-						"Field 'allocations[0].class' failed validation: is required"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/observationTimestamp", "detail": "Field 'observationTimestamp' failed validation: is required"},
+						{"pointer": "/allocations/0/class", "detail": "Field 'allocations[0].class' failed validation: is required"}
 					]
 				}
 			`
-			assert.JSONEq(t, expectedResponseJSON, actualResponseJSONNullFields)
+			assertProblemJSON(t, expectedResponseJSON, actualResponseJSONNullFields)
 		},
 	)
 
@@ -1163,13 +1171,15 @@ func TestPostPortfolioAllocationValidations(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'allocations' failed validation: is required"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/allocations", "detail": "Field 'allocations' failed validation: is required"}
 					]
 				}
 			`
-			assert.JSONEq(t, expectedResponseJSON, actualResponseJSONNullFields)
+			assertProblemJSON(t, expectedResponseJSON, actualResponseJSONNullFields)
 		},
 	)
 
@@ -1195,13 +1205,15 @@ func TestPostPortfolioAllocationValidations(t *testing.T) {
 
 			var expectedResponseJSON = `
 				{
-					"errorMessage": "Validation failed",
-					"details": [
-						"Field 'allocations' failed validation: must be at least 1"
+					"type": "urn:open-asset-allocator:problem:validation",
+					"title": "Validation failed",
+					"status": 400,
+					"errors": [
+						{"pointer": "/allocations", "detail": "Field 'allocations' failed validation: must be at least 1"}
 					]
 				}
 			`
-			assert.JSONEq(t, expectedResponseJSON, actualResponseJSONNullFields)
+			assertProblemJSON(t, expectedResponseJSON, actualResponseJSONNullFields)
 		},
 	)
 }
@@ -1372,10 +1384,14 @@ func TestPostPortfolioAllocationHistoryValidation_ClassExceedsMaxLength(t *testi
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'allocations[0].class' failed validation: must not exceed 100"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/allocations/0/class", "detail": "Field 'allocations[0].class' failed validation: must not exceed 100"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostPortfolioAllocationHistoryValidation_AssetTickerExceedsMaxLength tests that posting
@@ -1414,10 +1430,14 @@ func TestPostPortfolioAllocationHistoryValidation_AssetTickerExceedsMaxLength(t
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'allocations[0].assetTicker' failed validation: must not exceed 40"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/allocations/0/assetTicker", "detail": "Field 'allocations[0].assetTicker' failed validation: must not exceed 40"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostPortfolioAllocationHistoryValidation_AssetNameExceedsMaxLength tests that posting
@@ -1456,10 +1476,14 @@ func TestPostPortfolioAllocationHistoryValidation_AssetNameExceedsMaxLength(t *t
 	assert.NoError(t, err)
 
 	var expected = `{
-		"errorMessage": "Validation failed",
-		"details": ["Field 'allocations[0].assetName' failed validation: must not exceed 100"]
+		"type": "urn:open-asset-allocator:problem:validation",
+		"title": "Validation failed",
+		"status": 400,
+		"errors": [
+			{"pointer": "/allocations/0/assetName", "detail": "Field 'allocations[0].assetName' failed validation: must not exceed 100"}
+		]
 	}`
-	assert.JSONEq(t, expected, string(body))
+	assertProblemJSON(t, expected, string(body))
 }
 
 // TestPostPortfolioAllocationHistoryInsertOnlyWithExternalAsset verifies that a
@@ -1690,13 +1714,15 @@ func TestPostPortfolioAllocationHistoryValidation_InvalidExternalAssetFields(t *
 
 	var expectedResponseJSON = `
 		{
-			"errorMessage": "Validation failed",
-			"details": [
-				"Field 'allocations[0].externalAsset.source' failed validation: is required",
-				"Field 'allocations[0].externalAsset.exchangeId' failed validation: is required"
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"errors": [
+				{"pointer": "/allocations/0/externalAsset/source", "detail": "Field 'allocations[0].externalAsset.source' failed validation: is required"},
+				{"pointer": "/allocations/0/externalAsset/exchangeId", "detail": "Field 'allocations[0].externalAsset.exchangeId' failed validation: is required"}
 			]
 		}
 	`
 
-	assert.JSONEq(t, expectedResponseJSON, actualResponseJSON)
+	assertProblemJSON(t, expectedResponseJSON, actualResponseJSON)
 }
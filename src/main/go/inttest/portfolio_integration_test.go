@@ -294,14 +294,16 @@ func TestPostPortfolioFailureWithoutMandatoryFields(t *testing.T) {
 
 	var expectedResponseJSON = `
 		{
-			"errorMessage": "Validation failed",
-			"details": [
-				"Field 'name' failed validation: is required"
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"errors": [
+				{"pointer": "/name", "detail": "Field 'name' failed validation: is required"}
 			]
 		}
 	`
-	assert.JSONEq(t, expectedResponseJSON, actualResponseJSONNullFields)
-	assert.JSONEq(t, expectedResponseJSON, actualResponseJSONEmptyFields)
+	assertProblemJSON(t, expectedResponseJSON, actualResponseJSONNullFields)
+	assertProblemJSON(t, expectedResponseJSON, actualResponseJSONEmptyFields)
 }
 
 func postPortfolioForValidationFailure(t *testing.T, postPortfolioJSON string) []byte {
@@ -377,6 +379,71 @@ func TestPutPortfolio(t *testing.T) {
 	)
 }
 
+func TestPutPortfolioWithIfMatch(t *testing.T) {
+
+	var testPortfolioNameBefore = "This Test Portfolio will be updated with If-Match"
+	var testPortfolioNameAfter = "Test Portfolio update with If-Match"
+
+	testPortFolio := insertTestPortfolio(t, testPortfolioNameBefore)
+	var testPortfolioIdString = strconv.FormatInt(testPortFolio.Id, 10)
+
+	var putPortfolioJSON = `
+		{
+			"id":` + testPortfolioIdString + `,
+			"name":"` + testPortfolioNameAfter + `"
+		}
+	`
+
+	response := putPortfolioWithIfMatch(t, putPortfolioJSON, `"1"`)
+	defer deferCloseResponseBody(response)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `"2"`, response.Header.Get("ETag"))
+
+	var stalePutPortfolioJSON = `
+		{
+			"id":` + testPortfolioIdString + `,
+			"name":"` + testPortfolioNameBefore + `"
+		}
+	`
+
+	staleResponse := putPortfolioWithIfMatch(t, stalePutPortfolioJSON, `"1"`)
+	defer deferCloseResponseBody(staleResponse)
+
+	assert.Equal(t, http.StatusPreconditionFailed, staleResponse.StatusCode)
+	assert.Equal(t, `"2"`, staleResponse.Header.Get("ETag"))
+
+	body, err := io.ReadAll(staleResponse.Body)
+	assert.NoError(t, err)
+
+	var expectedResponseJSON = `
+		{
+			"id":` + testPortfolioIdString + `,
+			"name":"` + testPortfolioNameAfter + `",
+			"allocationStructure": {
+				"hierarchy": [
+					{
+						"name":"Assets",
+						"field":"assetTicker"
+					},
+					{
+						"name":"Classes",
+						"field":"class"
+					}
+				]
+			}
+		}
+	`
+	assert.JSONEq(t, expectedResponseJSON, string(body))
+
+	assertPersistedPortfolioFromAttributes(
+		t,
+		testPortFolio.Id,
+		testPortfolioNameAfter,
+		`{"hierarchy": [{"name": "Assets", "field": "assetTicker"}, {"name": "Classes", "field": "class"}]}`,
+	)
+}
+
 func TestPutPortfolioFailureWithoutMandatoryFields(t *testing.T) {
 
 	var testPortfolioName = "This Test Portfolio will be updated"
@@ -438,26 +505,30 @@ func TestPutPortfolioFailureWithoutMandatoryFields(t *testing.T) {
 
 	var expectedNoNameResponseJSON = `
 		{
-			"errorMessage": "Validation failed",
-			"details": [
-				"Field 'name' failed validation: is required"
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"errors": [
+				{"pointer": "/name", "detail": "Field 'name' failed validation: is required"}
 			]
 		}
 	`
 
 	var expectedNoIdResponseJSON = `
 		{
-			"errorMessage": "Validation failed",
-			"details": [
-				"Field 'id' failed validation: is required"
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"errors": [
+				{"pointer": "/id", "detail": "Field 'id' failed validation: is required"}
 			]
 		}
 	`
 
-	assert.JSONEq(t, expectedNoNameResponseJSON, actualResponseJSONNullFields)
-	assert.JSONEq(t, expectedNoNameResponseJSON, actualResponseJSONEmptyFields)
-	assert.JSONEq(t, expectedNoNameResponseJSON, actualResponseJSONNoFields)
-	assert.JSONEq(t, expectedNoIdResponseJSON, actualResponseJSONNoId)
+	assertProblemJSON(t, expectedNoNameResponseJSON, actualResponseJSONNullFields)
+	assertProblemJSON(t, expectedNoNameResponseJSON, actualResponseJSONEmptyFields)
+	assertProblemJSON(t, expectedNoNameResponseJSON, actualResponseJSONNoFields)
+	assertProblemJSON(t, expectedNoIdResponseJSON, actualResponseJSONNoId)
 
 	assertPersistedPortfolioFromAttributes(
 		t,
@@ -619,13 +690,15 @@ func TestPostPortfolioFailureWithNameExceedingMaxLength(t *testing.T) {
 
 	var expectedResponseJSON = `
 		{
-			"errorMessage": "Validation failed",
-			"details": [
-				"Field 'name' failed validation: must not exceed 100"
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"errors": [
+				{"pointer": "/name", "detail": "Field 'name' failed validation: must not exceed 100"}
 			]
 		}
 	`
-	assert.JSONEq(t, expectedResponseJSON, actualResponseJSON)
+	assertProblemJSON(t, expectedResponseJSON, actualResponseJSON)
 }
 
 // TestPostPortfolioSuccessWithNameAtMaxLength tests that creating a portfolio
@@ -695,11 +768,13 @@ func TestPutPortfolioFailureWithNameExceedingMaxLength(t *testing.T) {
 
 	var expectedResponseJSON = `
 		{
-			"errorMessage": "Validation failed",
-			"details": [
-				"Field 'name' failed validation: must not exceed 100"
+			"type": "urn:open-asset-allocator:problem:validation",
+			"title": "Validation failed",
+			"status": 400,
+			"errors": [
+				{"pointer": "/name", "detail": "Field 'name' failed validation: must not exceed 100"}
 			]
 		}
 	`
-	assert.JSONEq(t, expectedResponseJSON, actualResponseJSON)
+	assertProblemJSON(t, expectedResponseJSON, actualResponseJSON)
 }
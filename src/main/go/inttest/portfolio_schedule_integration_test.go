@@ -0,0 +1,361 @@
+package inttest
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	restmodel "github.com/benizzio/open-asset-allocator/api/rest/model"
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+const scheduleRunTimeout = 10 * time.Second
+
+// TestPortfolioScheduleLifecycle verifies creating, reading, updating and deleting a portfolio schedule.
+func TestPortfolioScheduleLifecycle(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Schedule Lifecycle")
+	var schedulePath = strconv.FormatInt(testPortfolio.Id, 10) + "/schedule"
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		schedulePath,
+		`{"taskType": "REVALUATION", "cronExpression": "0 18 L * *", "timezone": "America/Sao_Paulo"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var createdSchedule restmodel.PortfolioScheduleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &createdSchedule))
+	require.NotNil(t, createdSchedule.Id)
+	assert.Equal(t, "REVALUATION", createdSchedule.TaskType)
+	assert.Equal(t, "America/Sao_Paulo", createdSchedule.Timezone)
+	assert.JSONEq(t, `{}`, string(createdSchedule.Params))
+	require.NotNil(t, createdSchedule.Enabled)
+	assert.True(t, *createdSchedule.Enabled)
+	require.NotNil(t, createdSchedule.NextRunAt)
+	assert.True(t, createdSchedule.NextRunAt.After(time.Now()))
+
+	var nextRunAtInTimezone = createdSchedule.NextRunAt.In(loadTestLocation(t, "America/Sao_Paulo"))
+	assert.Equal(t, 18, nextRunAtInTimezone.Hour())
+	assert.NotEqual(t, nextRunAtInTimezone.Month(), nextRunAtInTimezone.AddDate(0, 0, 1).Month())
+
+	var scheduleIdPath = schedulePath + "/" + strconv.FormatInt(int64(*createdSchedule.Id), 10)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, schedulePath, "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	var listedSchedules []restmodel.PortfolioScheduleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &listedSchedules))
+	require.Len(t, listedSchedules, 1)
+	assert.Equal(t, *createdSchedule.Id, *listedSchedules[0].Id)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPut,
+		scheduleIdPath,
+		`{"taskType": "REVALUATION", "cronExpression": "0 9 * * 1", "enabled": false}`,
+	)
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+
+	var updatedSchedule restmodel.PortfolioScheduleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &updatedSchedule))
+	assert.Equal(t, "0 9 * * 1", updatedSchedule.CronExpression)
+	assert.Equal(t, "UTC", updatedSchedule.Timezone)
+	require.NotNil(t, updatedSchedule.Enabled)
+	assert.False(t, *updatedSchedule.Enabled)
+	assert.Nil(t, updatedSchedule.NextRunAt)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, scheduleIdPath+"/run", "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	assert.JSONEq(t, `[]`, responseBody)
+
+	statusCode, _ = sendPortfolioResourceRequest(t, http.MethodDelete, scheduleIdPath, "")
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	statusCode, _ = sendPortfolioResourceRequest(t, http.MethodGet, scheduleIdPath, "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	statusCode, _ = sendPortfolioResourceRequest(t, http.MethodDelete, scheduleIdPath, "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+// TestPostPortfolioScheduleValidation verifies the rejection of invalid schedules.
+func TestPostPortfolioScheduleValidation(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Schedule Validation")
+	var schedulePath = strconv.FormatInt(testPortfolio.Id, 10) + "/schedule"
+
+	var testCases = []struct {
+		name            string
+		requestJSON     string
+		expectedMessage string
+	}{
+		{
+			name:            "unknown task type",
+			requestJSON:     `{"taskType": "UNKNOWN", "cronExpression": "@daily"}`,
+			expectedMessage: "Invalid scheduled task type UNKNOWN",
+		},
+		{
+			name:            "invalid cron expression",
+			requestJSON:     `{"taskType": "REVALUATION", "cronExpression": "0 25 * * *"}`,
+			expectedMessage: `Invalid cron expression: invalid hour "25", expected a value from 0 to 23`,
+		},
+		{
+			name:            "invalid timezone",
+			requestJSON:     `{"taskType": "REVALUATION", "cronExpression": "@daily", "timezone": "Mars/Olympus"}`,
+			expectedMessage: "Invalid timezone Mars/Olympus",
+		},
+		{
+			name:            "divergence analysis without plan",
+			requestJSON:     `{"taskType": "DIVERGENCE_ANALYSIS", "cronExpression": "0 9 * * 1"}`,
+			expectedMessage: "Divergence analysis task requires the allocationPlanId param",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			var statusCode, responseBody = sendPortfolioResourceRequest(
+				t,
+				http.MethodPost,
+				schedulePath,
+				testCase.requestJSON,
+			)
+
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+			assertProblemJSON(
+				t,
+				fmt.Sprintf(
+					`{
+						"type": "urn:open-asset-allocator:problem:validation",
+						"title": "Validation failed",
+						"status": 400,
+						"detail": %q
+					}`,
+					testCase.expectedMessage,
+				),
+				responseBody,
+			)
+		})
+	}
+}
+
+// TestPortfolioScheduleRunsDueRevaluation verifies that the scheduler runs a due revaluation, records it
+// in the schedule history and advances the schedule to its next activation.
+func TestPortfolioScheduleRunsDueRevaluation(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Scheduled Revaluation")
+	var testAsset = insertTestAsset(t, "TEST:SCHEDULED", "Test Asset Scheduled Revaluation")
+	var testAssetIdString = strconv.FormatInt(testAsset.Id, 10)
+
+	insertTestPortfolioObservation(t, testPortfolio.Id, "test_scheduled_revaluation", "2025-01-10 00:00:00", 10, 100, testAsset.Id)
+	registerTestRevaluationObservationsCleanup(t, testPortfolio.Id)
+
+	var statusCode, responseBody = sendAssetResourceRequest(
+		t,
+		http.MethodPost,
+		testAssetIdString+"/valuation",
+		`{"valuationDate": "2025-03-31T00:00:00Z", "price": "123.45", "currency": "USD"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var schedulePath = strconv.FormatInt(testPortfolio.Id, 10) + "/schedule"
+	statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		schedulePath,
+		`{"taskType": "REVALUATION", "cronExpression": "0 0 1 * *"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var schedule restmodel.PortfolioScheduleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &schedule))
+	var scheduleId = int64(*schedule.Id)
+
+	var dueAt = time.Now().Add(-time.Second).Truncate(time.Microsecond)
+	setTestScheduleNextRun(t, scheduleId, dueAt)
+
+	var runs = waitForTestScheduleRuns(t, schedulePath+"/"+strconv.FormatInt(scheduleId, 10), 1)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "SUCCEEDED", runs[0].Status, runs[0].ErrorMessage)
+	assert.True(t, dueAt.Equal(runs[0].ScheduledFor))
+	assert.NotNil(t, runs[0].StartedAt)
+	assert.NotNil(t, runs[0].FinishedAt)
+	inttestutil.AssertJSONEqualIgnoringFields(
+		t,
+		fmt.Sprintf(
+			`
+				{
+					"quotedAssets": 1,
+					"revaluedAllocations": 1,
+					"quotes": [
+						{
+							"assetId": %d,
+							"ticker": "TEST:SCHEDULED",
+							"source": "MANUAL",
+							"price": "123.45",
+							"currency": "USD",
+							"lastCloseDate": "2025-03-31T00:00:00Z"
+						}
+					],
+					"failures": [],
+					"triggeredAlerts": 0
+				}
+			`,
+			testAsset.Id,
+		),
+		string(runs[0].Result),
+		"observationTimestampId",
+		"observationTimeTag",
+	)
+
+	var runResult struct {
+		ObservationTimestampId int64  `json:"observationTimestampId"`
+		ObservationTimeTag     string `json:"observationTimeTag"`
+	}
+	require.NoError(t, json.Unmarshal(runs[0].Result, &runResult))
+	assert.True(t, strings.HasPrefix(runResult.ObservationTimeTag, "REVALUATION "), runResult.ObservationTimeTag)
+
+	inttestutil.AssertDBWithQuery(
+		t,
+		fmt.Sprintf(
+			`SELECT asset_market_price, total_market_value
+			FROM portfolio_allocation_fact
+			WHERE portfolio_id = %d AND observation_time_id = %d AND asset_id = %d`,
+			testPortfolio.Id,
+			runResult.ObservationTimestampId,
+			testAsset.Id,
+		),
+		dbx.NullStringMap{
+			"asset_market_price": sql.NullString{String: "123.45000000", Valid: true},
+			"total_market_value": sql.NullString{String: "1235", Valid: true},
+		},
+	)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodGet,
+		schedulePath+"/"+strconv.FormatInt(scheduleId, 10),
+		"",
+	)
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &schedule))
+	require.NotNil(t, schedule.NextRunAt)
+	assert.True(t, schedule.NextRunAt.After(time.Now()))
+	assert.Equal(t, 1, schedule.NextRunAt.UTC().Day())
+}
+
+// TestPortfolioScheduleRecordsMissedActivations verifies that the activations passed beyond the misfire
+// threshold are recorded as missed instead of run.
+func TestPortfolioScheduleRecordsMissedActivations(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Missed Schedule")
+	var schedulePath = strconv.FormatInt(testPortfolio.Id, 10) + "/schedule"
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		schedulePath,
+		`{"taskType": "REVALUATION", "cronExpression": "@yearly"}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var schedule restmodel.PortfolioScheduleDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &schedule))
+	var scheduleId = int64(*schedule.Id)
+
+	var firstMissedActivation = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	setTestScheduleNextRun(t, scheduleId, firstMissedActivation)
+
+	var expectedMissedRuns = time.Now().UTC().Year() - firstMissedActivation.Year() + 1
+	var runs = waitForTestScheduleRuns(t, schedulePath+"/"+strconv.FormatInt(scheduleId, 10), expectedMissedRuns)
+
+	require.Len(t, runs, expectedMissedRuns)
+	for _, run := range runs {
+		assert.Equal(t, "MISSED", run.Status)
+		assert.Nil(t, run.StartedAt)
+	}
+	assert.True(t, firstMissedActivation.Equal(runs[len(runs)-1].ScheduledFor))
+}
+
+// sendPortfolioResourceRequest sends a request to an endpoint of the portfolio resource, relative to the
+// /api/portfolio path, and returns the response status code and body.
+func sendPortfolioResourceRequest(t *testing.T, method string, path string, requestJSON string) (int, string) {
+	t.Helper()
+
+	var requestBody io.Reader
+	if requestJSON != "" {
+		requestBody = strings.NewReader(requestJSON)
+	}
+
+	request, err := http.NewRequest(method, inttestinfra.TestAPIURLPrefix+"/portfolio/"+path, requestBody)
+	require.NoError(t, err)
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	responseBody, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(responseBody)
+}
+
+// setTestScheduleNextRun makes a schedule due at the given time, so it is handled on the next scheduler
+// tick.
+func setTestScheduleNextRun(t *testing.T, scheduleId int64, nextRunAt time.Time) {
+	t.Helper()
+
+	err := inttestinfra.ExecuteDBQuery(
+		"UPDATE portfolio_schedule SET next_run_at = {:nextRunAt} WHERE id = {:id}",
+		dbx.Params{"nextRunAt": nextRunAt, "id": scheduleId},
+	)
+	require.NoError(t, err)
+}
+
+// waitForTestScheduleRuns polls the run history of a schedule until it has the expected number of runs,
+// failing the test on timeout.
+func waitForTestScheduleRuns(
+	t *testing.T,
+	scheduleIdPath string,
+	expectedRuns int,
+) []restmodel.PortfolioScheduleRunDTS {
+	t.Helper()
+
+	var runs []restmodel.PortfolioScheduleRunDTS
+	require.Eventually(
+		t,
+		func() bool {
+			var statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, scheduleIdPath+"/run", "")
+			if statusCode != http.StatusOK || json.Unmarshal([]byte(responseBody), &runs) != nil {
+				return false
+			}
+			return len(runs) >= expectedRuns
+		},
+		scheduleRunTimeout,
+		100*time.Millisecond,
+	)
+
+	return runs
+}
+
+func loadTestLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	var location, err = time.LoadLocation(name)
+	require.NoError(t, err)
+	return location
+}
@@ -1,6 +1,7 @@
 package inttest
 
 import (
+	"database/sql"
 	"net/http"
 	"strconv"
 	"strings"
@@ -8,6 +9,7 @@ import (
 
 	dbx "github.com/go-ozzo/ozzo-dbx"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	restmodel "github.com/benizzio/open-asset-allocator/api/rest/model"
 	"github.com/benizzio/open-asset-allocator/domain"
@@ -51,6 +53,121 @@ func insertTestPortfolio(t *testing.T, testPortfolioNameBefore string) domain.Po
 	return testPortFolio
 }
 
+// insertTestPortfolioObservation inserts an observation of a test portfolio holding the given assets, each
+// with the same quantity and market price, and registers its cleanup.
+func insertTestPortfolioObservation(
+	t *testing.T,
+	portfolioId int64,
+	observationTimeTag string,
+	observationTimestamp string,
+	assetQuantity int64,
+	assetMarketPrice int64,
+	assetIds ...int64,
+) {
+	t.Helper()
+
+	var insertObservationSQL = `
+		INSERT INTO portfolio_allocation_obs_time (observation_time_tag, observation_timestamp)
+		VALUES ({:timeTag}, {:timestamp}::TIMESTAMP)
+	`
+	err := inttestinfra.ExecuteDBQuery(
+		insertObservationSQL,
+		dbx.Params{"timeTag": observationTimeTag, "timestamp": observationTimestamp},
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery(
+				`DELETE FROM portfolio_allocation_fact
+				WHERE observation_time_id IN (
+					SELECT id FROM portfolio_allocation_obs_time WHERE observation_time_tag = {:timeTag}
+				)`,
+				dbx.Params{"timeTag": observationTimeTag},
+			).
+			AddCleanupQuery(
+				"DELETE FROM portfolio_allocation_obs_time WHERE observation_time_tag = {:timeTag}",
+				dbx.Params{"timeTag": observationTimeTag},
+			).
+			Build(t),
+	)
+
+	for _, assetId := range assetIds {
+		insertTestPortfolioObservationAllocation(
+			t,
+			portfolioId,
+			observationTimeTag,
+			"STOCKS",
+			assetQuantity,
+			assetMarketPrice,
+			assetId,
+		)
+	}
+}
+
+// insertTestPortfolioObservationAllocation inserts an allocation of an asset in a class into an observation
+// inserted by insertTestPortfolioObservation, which cleans it up.
+func insertTestPortfolioObservationAllocation(
+	t *testing.T,
+	portfolioId int64,
+	observationTimeTag string,
+	class string,
+	assetQuantity int64,
+	assetMarketPrice int64,
+	assetId int64,
+) {
+	t.Helper()
+
+	var insertAllocationSQL = `
+		INSERT INTO portfolio_allocation_fact (
+			asset_id, "class", cash_reserve, asset_quantity, asset_market_price,
+			total_market_value, portfolio_id, observation_time_id
+		)
+		SELECT
+			{:assetId}, {:class}, FALSE, {:quantity}, {:price}, {:quantity}::numeric * {:price}::numeric,
+			{:portfolioId}, id
+		FROM portfolio_allocation_obs_time
+		WHERE observation_time_tag = {:timeTag}
+	`
+	err := inttestinfra.ExecuteDBQuery(
+		insertAllocationSQL,
+		dbx.Params{
+			"assetId":     assetId,
+			"class":       class,
+			"quantity":    assetQuantity,
+			"price":       assetMarketPrice,
+			"portfolioId": portfolioId,
+			"timeTag":     observationTimeTag,
+		},
+	)
+	require.NoError(t, err)
+}
+
+// registerTestRevaluationObservationsCleanup registers the cleanup of the observations recorded by the
+// REVALUATION scheduled tasks of a test portfolio.
+func registerTestRevaluationObservationsCleanup(t *testing.T, portfolioId int64) {
+	t.Helper()
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery(
+				`DELETE FROM portfolio_allocation_fact
+				WHERE portfolio_id = {:portfolioId}
+					AND observation_time_id IN (
+						SELECT id FROM portfolio_allocation_obs_time WHERE observation_time_tag LIKE 'REVALUATION %'
+					)`,
+				dbx.Params{"portfolioId": portfolioId},
+			).
+			AddCleanupQuery(
+				`DELETE FROM portfolio_allocation_obs_time paot
+				WHERE paot.observation_time_tag LIKE 'REVALUATION %'
+					AND NOT EXISTS (SELECT 1 FROM portfolio_allocation_fact pa WHERE pa.observation_time_id = paot.id)`,
+				nil,
+			).
+			Build(t),
+	)
+}
+
 func assertPersistedPortfolioFromDTS(
 	t *testing.T,
 	actualPortfolioDTS restmodel.PortfolioDTS,
@@ -76,16 +193,22 @@ func assertPersistedPortfolioFromAttributes(
 		"id":                   util.StringToNullString(portfolioIdString),
 		"name":                 util.StringToNullString(actualPortfolioName),
 		"allocation_structure": util.StringToNullString(actualPortFolioAllocationStructure),
+		"owner_user_id":        sql.NullString{},
 	}
 
 	inttestutil.AssertDBWithQuery(
 		t,
-		"SELECT * FROM portfolio WHERE id="+portfolioIdString,
+		"SELECT id, name, allocation_structure, owner_user_id FROM portfolio WHERE id="+portfolioIdString,
 		portfolioNullStringMap,
 	)
 }
 
 func putPortfolio(t *testing.T, putPortfolioJSON string) *http.Response {
+	return putPortfolioWithIfMatch(t, putPortfolioJSON, "")
+}
+
+// putPortfolioWithIfMatch sends a portfolio update conditioned to the If-Match entity tag, when not empty.
+func putPortfolioWithIfMatch(t *testing.T, putPortfolioJSON string, ifMatch string) *http.Response {
 
 	request, err := http.NewRequest(
 		http.MethodPut,
@@ -95,6 +218,9 @@ func putPortfolio(t *testing.T, putPortfolioJSON string) *http.Response {
 	assert.NoError(t, err)
 
 	request.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		request.Header.Set("If-Match", ifMatch)
+	}
 
 	client := &http.Client{}
 	response, err := client.Do(request)
@@ -0,0 +1,376 @@
+package inttest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	dbx "github.com/go-ozzo/ozzo-dbx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	restmodel "github.com/benizzio/open-asset-allocator/api/rest/model"
+	"github.com/benizzio/open-asset-allocator/domain/service"
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+	inttestutil "github.com/benizzio/open-asset-allocator/inttest/util"
+)
+
+const webhookDeliveryTimeout = 10 * time.Second
+
+// receivedTestWebhook is a webhook request received by a test receiver.
+type receivedTestWebhook struct {
+	headers http.Header
+	body    []byte
+}
+
+// testWebhookReceiver is an HTTP server recording the webhook requests it receives and answering them with
+// a fixed status code.
+type testWebhookReceiver struct {
+	server   *httptest.Server
+	mutex    sync.Mutex
+	received []receivedTestWebhook
+}
+
+func (receiver *testWebhookReceiver) receivedWebhooks() []receivedTestWebhook {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	return append([]receivedTestWebhook(nil), receiver.received...)
+}
+
+func startTestWebhookReceiver(t *testing.T, responseStatusCode int) *testWebhookReceiver {
+	t.Helper()
+
+	var receiver = &testWebhookReceiver{}
+	receiver.server = httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				var body, _ = io.ReadAll(request.Body)
+				receiver.mutex.Lock()
+				receiver.received = append(receiver.received, receivedTestWebhook{headers: request.Header, body: body})
+				receiver.mutex.Unlock()
+				writer.WriteHeader(responseStatusCode)
+			},
+		),
+	)
+	t.Cleanup(receiver.server.Close)
+
+	return receiver
+}
+
+// TestPortfolioWebhookLifecycle verifies registering, reading, updating and deleting a portfolio webhook,
+// with the secret only returned on registration.
+func TestPortfolioWebhookLifecycle(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Webhook Lifecycle")
+	var webhookPath = strconv.FormatInt(testPortfolio.Id, 10) + "/webhook"
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		webhookPath,
+		`{"url": "https://example.com/hook", "eventTypes": ["SNAPSHOT_MERGED"]}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+
+	var createdWebhook restmodel.PortfolioWebhookDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &createdWebhook))
+	require.NotNil(t, createdWebhook.Id)
+	assert.Equal(t, "https://example.com/hook", createdWebhook.URL)
+	assert.Equal(t, []string{"SNAPSHOT_MERGED"}, createdWebhook.EventTypes)
+	assert.Len(t, createdWebhook.Secret, 64)
+	require.NotNil(t, createdWebhook.Enabled)
+	assert.True(t, *createdWebhook.Enabled)
+
+	var webhookIdPath = webhookPath + "/" + strconv.FormatInt(int64(*createdWebhook.Id), 10)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, webhookIdPath, "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	assert.NotContains(t, responseBody, "secret")
+
+	statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPut,
+		webhookIdPath,
+		`{
+			"url": "https://example.com/other-hook",
+			"eventTypes": ["SNAPSHOT_MERGED", "ALLOCATION_PLAN_PERSISTED"],
+			"enabled": false
+		}`,
+	)
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	assert.NotContains(t, responseBody, "secret")
+
+	var updatedWebhook restmodel.PortfolioWebhookDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &updatedWebhook))
+	assert.Equal(t, "https://example.com/other-hook", updatedWebhook.URL)
+	assert.Equal(t, []string{"SNAPSHOT_MERGED", "ALLOCATION_PLAN_PERSISTED"}, updatedWebhook.EventTypes)
+	require.NotNil(t, updatedWebhook.Enabled)
+	assert.False(t, *updatedWebhook.Enabled)
+
+	var persistedSecret string
+	err := inttestinfra.FetchWithDBQuery(
+		"SELECT secret FROM portfolio_webhook WHERE id = {:id}",
+		dbx.Params{"id": int64(*createdWebhook.Id)},
+		func(rows *dbx.Rows) error {
+			return rows.Scan(&persistedSecret)
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, createdWebhook.Secret, persistedSecret)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodGet, webhookPath, "")
+	require.Equal(t, http.StatusOK, statusCode, responseBody)
+	var listedWebhooks []restmodel.PortfolioWebhookDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &listedWebhooks))
+	require.Len(t, listedWebhooks, 1)
+
+	statusCode, responseBody = sendPortfolioResourceRequest(t, http.MethodDelete, webhookIdPath, "")
+	require.Equal(t, http.StatusNoContent, statusCode, responseBody)
+
+	statusCode, _ = sendPortfolioResourceRequest(t, http.MethodGet, webhookIdPath, "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+// TestPortfolioWebhookValidations verifies the webhook definitions rejected on registration.
+func TestPortfolioWebhookValidations(t *testing.T) {
+
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Webhook Validations")
+	var webhookPath = strconv.FormatInt(testPortfolio.Id, 10) + "/webhook"
+
+	var testCases = []struct {
+		name                 string
+		requestJSON          string
+		expectedErrorMessage string
+	}{
+		{
+			name:                 "unsupported URL scheme",
+			requestJSON:          `{"url": "ftp://example.com/hook", "eventTypes": ["SNAPSHOT_MERGED"]}`,
+			expectedErrorMessage: "Invalid webhook URL ftp://example.com/hook",
+		},
+		{
+			name:                 "no event types",
+			requestJSON:          `{"url": "https://example.com/hook", "eventTypes": []}`,
+			expectedErrorMessage: "Webhook must subscribe to at least one event type",
+		},
+		{
+			name:                 "unknown event type",
+			requestJSON:          `{"url": "https://example.com/hook", "eventTypes": ["PORTFOLIO_DELETED"]}`,
+			expectedErrorMessage: "Invalid webhook event type PORTFOLIO_DELETED",
+		},
+		{
+			name: "short secret",
+			requestJSON: `{
+				"url": "https://example.com/hook",
+				"secret": "too-short",
+				"eventTypes": ["SNAPSHOT_MERGED"]
+			}`,
+			expectedErrorMessage: "Webhook secret must have at least 16 characters",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				var statusCode, responseBody = sendPortfolioResourceRequest(
+					t,
+					http.MethodPost,
+					webhookPath,
+					testCase.requestJSON,
+				)
+				assert.Equal(t, http.StatusBadRequest, statusCode)
+				assertProblemJSON(
+					t,
+					`{
+						"type": "urn:open-asset-allocator:problem:validation",
+						"title": "Validation failed",
+						"status": 400,
+						"detail": "`+testCase.expectedErrorMessage+`"
+					}`,
+					responseBody,
+				)
+			},
+		)
+	}
+}
+
+// TestPortfolioWebhookDeliversSignedSnapshotMergedEvent verifies that merging a snapshot delivers a signed
+// SNAPSHOT_MERGED event to the subscribed webhook and records the delivery.
+func TestPortfolioWebhookDeliversSignedSnapshotMergedEvent(t *testing.T) {
+
+	var receiver = startTestWebhookReceiver(t, http.StatusOK)
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Webhook Snapshot Merged")
+	var portfolioIdPath = strconv.FormatInt(testPortfolio.Id, 10)
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		portfolioIdPath+"/webhook",
+		`{
+			"url": "`+receiver.server.URL+`",
+			"secret": "test-webhook-secret-value",
+			"eventTypes": ["SNAPSHOT_MERGED"]
+		}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+	var createdWebhook restmodel.PortfolioWebhookDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &createdWebhook))
+
+	postTestWebhookSnapshot(t, portfolioIdPath, "2099WH01")
+
+	require.Eventually(
+		t,
+		func() bool { return len(receiver.receivedWebhooks()) > 0 },
+		webhookDeliveryTimeout,
+		100*time.Millisecond,
+	)
+
+	var receivedWebhook = receiver.receivedWebhooks()[0]
+	assert.Equal(t, "SNAPSHOT_MERGED", receivedWebhook.headers.Get(service.WebhookEventHeader))
+	assert.Equal(t, "application/json", receivedWebhook.headers.Get("Content-Type"))
+
+	timestamp, err := strconv.ParseInt(receivedWebhook.headers.Get(service.WebhookTimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		service.SignWebhookPayload("test-webhook-secret-value", timestamp, receivedWebhook.body),
+		receivedWebhook.headers.Get(service.WebhookSignatureHeader),
+	)
+
+	var receivedEvent struct {
+		Type        string `json:"type"`
+		PortfolioId int64  `json:"portfolioId"`
+		Data        struct {
+			ObservationTimeTag string `json:"observationTimeTag"`
+			AllocationCount    int    `json:"allocationCount"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(receivedWebhook.body, &receivedEvent))
+	assert.Equal(t, "SNAPSHOT_MERGED", receivedEvent.Type)
+	assert.Equal(t, testPortfolio.Id, receivedEvent.PortfolioId)
+	assert.Equal(t, "2099WH01", receivedEvent.Data.ObservationTimeTag)
+	assert.Equal(t, 1, receivedEvent.Data.AllocationCount)
+
+	var deliveries = waitForTestWebhookDeliveryStatus(
+		t,
+		portfolioIdPath+"/webhook/"+strconv.FormatInt(int64(*createdWebhook.Id), 10),
+		"SUCCEEDED",
+	)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "SNAPSHOT_MERGED", deliveries[0].EventType)
+	assert.Equal(t, 1, deliveries[0].AttemptCount)
+	require.NotNil(t, deliveries[0].LastStatusCode)
+	assert.Equal(t, http.StatusOK, *deliveries[0].LastStatusCode)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+}
+
+// TestPortfolioWebhookDeliveryFailsAfterMaxAttempts verifies that a delivery rejected by the receiver is
+// retried and marked as failed once out of attempts.
+func TestPortfolioWebhookDeliveryFailsAfterMaxAttempts(t *testing.T) {
+
+	var receiver = startTestWebhookReceiver(t, http.StatusInternalServerError)
+	var testPortfolio = insertTestPortfolio(t, "Test Portfolio Webhook Failed Delivery")
+	var portfolioIdPath = strconv.FormatInt(testPortfolio.Id, 10)
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		portfolioIdPath+"/webhook",
+		`{"url": "`+receiver.server.URL+`", "eventTypes": ["SNAPSHOT_MERGED"]}`,
+	)
+	require.Equal(t, http.StatusCreated, statusCode, responseBody)
+	var createdWebhook restmodel.PortfolioWebhookDTS
+	require.NoError(t, json.Unmarshal([]byte(responseBody), &createdWebhook))
+
+	postTestWebhookSnapshot(t, portfolioIdPath, "2099WH02")
+
+	var deliveries = waitForTestWebhookDeliveryStatus(
+		t,
+		portfolioIdPath+"/webhook/"+strconv.FormatInt(int64(*createdWebhook.Id), 10),
+		"FAILED",
+	)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, 2, deliveries[0].AttemptCount)
+	assert.Equal(t, "webhook responded with status 500", deliveries[0].LastError)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+	assert.Nil(t, deliveries[0].DeliveredAt)
+	assert.Len(t, receiver.receivedWebhooks(), 2)
+}
+
+// postTestWebhookSnapshot merges a snapshot holding a single allocation into a test portfolio, under a new
+// observation, and registers its cleanup.
+func postTestWebhookSnapshot(t *testing.T, portfolioIdPath string, observationTimeTag string) {
+	t.Helper()
+
+	t.Cleanup(
+		inttestutil.BuildCleanupFunctionBuilder().
+			AddCleanupQuery(
+				`DELETE FROM portfolio_allocation_fact
+				WHERE observation_time_id IN (
+					SELECT id FROM portfolio_allocation_obs_time WHERE observation_time_tag = {:timeTag}
+				)`,
+				dbx.Params{"timeTag": observationTimeTag},
+			).
+			AddCleanupQuery(
+				"DELETE FROM portfolio_allocation_obs_time WHERE observation_time_tag = {:timeTag}",
+				dbx.Params{"timeTag": observationTimeTag},
+			).
+			Build(t),
+	)
+
+	var statusCode, responseBody = sendPortfolioResourceRequest(
+		t,
+		http.MethodPost,
+		portfolioIdPath+"/history",
+		`{
+			"observationTimestamp": {"timeTag": "`+observationTimeTag+`", "timestamp": "2099-01-01T00:00:00Z"},
+			"allocations": [
+				{
+					"assetId": 1,
+					"assetTicker": "ARCA:BIL",
+					"class": "BONDS",
+					"cashReserve": false,
+					"assetQuantity": "10",
+					"assetMarketPrice": "100",
+					"totalMarketValue": "1000"
+				}
+			]
+		}`,
+	)
+	require.Equal(t, http.StatusNoContent, statusCode, responseBody)
+}
+
+// waitForTestWebhookDeliveryStatus polls the deliveries of a webhook until the latest one has the expected
+// status, failing the test on timeout.
+func waitForTestWebhookDeliveryStatus(
+	t *testing.T,
+	webhookIdPath string,
+	expectedStatus string,
+) []restmodel.WebhookDeliveryDTS {
+	t.Helper()
+
+	var deliveries []restmodel.WebhookDeliveryDTS
+	require.Eventually(
+		t,
+		func() bool {
+			var statusCode, responseBody = sendPortfolioResourceRequest(
+				t,
+				http.MethodGet,
+				webhookIdPath+"/delivery",
+				"",
+			)
+			if statusCode != http.StatusOK || json.Unmarshal([]byte(responseBody), &deliveries) != nil {
+				return false
+			}
+			return len(deliveries) > 0 && deliveries[0].Status == expectedStatus
+		},
+		webhookDeliveryTimeout,
+		100*time.Millisecond,
+	)
+
+	return deliveries
+}
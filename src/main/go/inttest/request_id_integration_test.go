@@ -0,0 +1,51 @@
+package inttest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benizzio/open-asset-allocator/infra"
+	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
+)
+
+func TestRequestIdIsGeneratedForEachRequest(t *testing.T) {
+
+	var firstRequestId = getPortfoliosRequestId(t, "")
+	var secondRequestId = getPortfoliosRequestId(t, "")
+
+	assert.NotEmpty(t, firstRequestId)
+	assert.NotEmpty(t, secondRequestId)
+	assert.NotEqual(t, firstRequestId, secondRequestId)
+}
+
+func TestRequestIdIsKeptFromRequest(t *testing.T) {
+	assert.Equal(t, "trace-42.a:b", getPortfoliosRequestId(t, "trace-42.a:b"))
+}
+
+func TestRequestIdIsReplacedWhenInvalid(t *testing.T) {
+
+	var requestId = getPortfoliosRequestId(t, "not a valid id\t")
+
+	assert.NotEmpty(t, requestId)
+	assert.NotEqual(t, "not a valid id\t", requestId)
+}
+
+func getPortfoliosRequestId(t *testing.T, requestId string) string {
+
+	request, err := http.NewRequest(http.MethodGet, inttestinfra.TestAPIURLPrefix+"/portfolio", nil)
+	require.NoError(t, err)
+
+	if requestId != "" {
+		request.Header.Set(infra.RequestIdHeader, requestId)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer deferCloseResponseBody(response)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	return response.Header.Get(infra.RequestIdHeader)
+}
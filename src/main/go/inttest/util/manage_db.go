@@ -1,10 +1,10 @@
 package util
 
 import (
+	"log/slog"
 	"testing"
 
 	dbx "github.com/go-ozzo/ozzo-dbx"
-	"github.com/golang/glog"
 
 	inttestinfra "github.com/benizzio/open-asset-allocator/inttest/infra"
 )
@@ -53,7 +53,7 @@ func BuildCleanupFunctionBuilder() *CleanupFunctionBuilder {
 func createDBCleanupFunctionMulti(t *testing.T, cleanupQueries []*testSQLParamsPair) func() {
 	return func() {
 		for _, query := range cleanupQueries {
-			glog.Infof("Executing test cleanup query: %s", query.sql)
+			slog.Info("Executing test cleanup query", "sql", query.sql)
 			err := inttestinfra.ExecuteDBQuery(query.sql, query.params)
 			if err != nil {
 				t.Errorf("Error executing cleanup query: %s", err)
@@ -285,3 +285,43 @@ func collectConcurrentResults[R any](resultChannel <-chan concurrentSliceResult[
 
 	return aggregated
 }
+
+// MapConcurrentlyBoundedCtx applies a context-aware function to each element of the input slice with
+// at most maxConcurrency concurrent calls, and returns the results in the input order. Unlike
+// FlatMapConcurrentlyCtx, it does not stop on failures: every input is mapped, so errors must be
+// carried in the result type, and the mapping function is expected to handle a done context.
+// A maxConcurrency lower than 1 runs the calls sequentially.
+//
+// Example:
+//
+//	results := langext.MapConcurrentlyBoundedCtx(
+//		ctx,
+//		urls,
+//		4,
+//		func(ctx context.Context, url string) FetchResult {
+//			response, err := fetchFromAPI(ctx, url)
+//			return FetchResult{Response: response, Err: err}
+//		},
+//	)
+func MapConcurrentlyBoundedCtx[I any, R any](
+	ctx context.Context,
+	inputs []I,
+	maxConcurrency int,
+	mapItem func(context.Context, I) R,
+) []R {
+
+	var results = make([]R, len(inputs))
+	var semaphore = make(chan struct{}, max(maxConcurrency, 1))
+	var waitGroup sync.WaitGroup
+
+	for index, input := range inputs {
+		semaphore <- struct{}{}
+		waitGroup.Go(func() {
+			defer func() { <-semaphore }()
+			results[index] = mapItem(ctx, input)
+		})
+	}
+
+	waitGroup.Wait()
+	return results
+}
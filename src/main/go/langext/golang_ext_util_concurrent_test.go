@@ -5,6 +5,7 @@ import (
 	"errors"
 	"runtime"
 	"slices"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -111,3 +112,54 @@ func TestFlatMapConcurrently_DoesNotLeakProducerOnWorkerError(t *testing.T) {
 		currentGoroutineCount,
 	)
 }
+
+// TestMapConcurrentlyBoundedCtx_KeepsOrderAndBound verifies that results follow the input order and
+// that no more than the given number of calls run at the same time.
+func TestMapConcurrentlyBoundedCtx_KeepsOrderAndBound(t *testing.T) {
+
+	var inputs = []int{1, 2, 3, 4, 5, 6, 7, 8}
+	var running, maxRunning atomic.Int32
+
+	var results = MapConcurrentlyBoundedCtx(
+		context.Background(),
+		inputs,
+		3,
+		func(_ context.Context, input int) int {
+			var current = running.Add(1)
+			for {
+				var observed = maxRunning.Load()
+				if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return input * 10
+		},
+	)
+
+	assert.Equal(t, []int{10, 20, 30, 40, 50, 60, 70, 80}, results)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	assert.Positive(t, maxRunning.Load())
+}
+
+// TestMapConcurrentlyBoundedCtx_MapsEveryInputWhenContextIsDone verifies that every input is still
+// handed to the mapping function, which decides how to report the done context.
+func TestMapConcurrentlyBoundedCtx_MapsEveryInputWhenContextIsDone(t *testing.T) {
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	var results = MapConcurrentlyBoundedCtx(
+		ctx,
+		[]string{"a", "b"},
+		0,
+		func(ctx context.Context, input string) error {
+			return ctx.Err()
+		},
+	)
+
+	require.Len(t, results, 2)
+	assert.ErrorIs(t, results[0], context.Canceled)
+	assert.ErrorIs(t, results[1], context.Canceled)
+}
@@ -3,13 +3,12 @@ package root
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/golang/glog"
-
 	"github.com/benizzio/open-asset-allocator/api/rest"
 	"github.com/benizzio/open-asset-allocator/application"
 	"github.com/benizzio/open-asset-allocator/domain"
@@ -23,21 +22,24 @@ import (
 )
 
 type App struct {
-	config          *infra.Configuration
-	databaseAdapter *rdbms.Adapter
-	server          *infra.GinServer
-	restControllers []infra.GinServerRESTController
+	config                    *infra.Configuration
+	databaseAdapter           *rdbms.Adapter
+	server                    *infra.GinServer
+	restControllers           []infra.GinServerRESTController
+	jobRunnerAppService       *application.JobRunnerAppService
+	schedulerAppService       *application.PortfolioSchedulerAppService
+	webhookDispatcher         *application.WebhookDispatcherAppService
+	authDomService            *service.AuthDomService
+	portfolioAccessDomService *service.PortfolioAccessDomService
 }
 
 func (app *App) buildBaseInfrastructure() {
 
 	var config = infra.ReadConfig()
-	// TODO only on debug mode
-	glog.Info("Applying environment configurations ", config)
+	slog.Debug("Applying environment configurations", "config", config)
 
 	app.completeConfig(config)
-	// TODO only on debug mode
-	glog.Info("Final configuration definitions: ", app.config)
+	slog.Debug("Final configuration definitions", "config", app.config)
 
 	app.server = infra.BuildGinServer(app.config)
 	app.databaseAdapter = rdbms.BuildDatabaseAdapter(app.config)
@@ -63,23 +65,87 @@ func (app *App) buildAppComponents() {
 	var allocationPlanRepository = repository.BuildAllocationPlanRepository(app.databaseAdapter)
 	var allocationRepository = repository.BuildAllocationRepository(app.databaseAdapter)
 	var assetRepository = repository.BuildAssetRDBMSRepository(app.databaseAdapter)
-
-	var yahooFinanceIntegrationClient = integration.BuildYahooFinanceAssetIntegrationClient(
+	var corporateActionRepository = repository.BuildCorporateActionRDBMSRepository(app.databaseAdapter)
+	var jobRepository = repository.BuildJobRDBMSRepository(app.databaseAdapter)
+	var portfolioScheduleRepository = repository.BuildPortfolioScheduleRDBMSRepository(app.databaseAdapter)
+	var webhookRepository = repository.BuildWebhookRDBMSRepository(app.databaseAdapter)
+	var divergenceAlertRepository = repository.BuildDivergenceAlertRDBMSRepository(app.databaseAdapter)
+	var authRepository = repository.BuildAuthRDBMSRepository(app.databaseAdapter)
+	var portfolioAccessRepository = repository.BuildPortfolioAccessRDBMSRepository(app.databaseAdapter)
+	var auditRepository = repository.BuildAuditRDBMSRepository(app.databaseAdapter)
+	var idempotencyRepository = repository.BuildIdempotencyRDBMSRepository(app.databaseAdapter)
+
+	yahooFinanceIntegrationClient, err := integration.BuildYahooFinanceAssetIntegrationClient(
 		app.config.IntegrationConfig.YahooFinanceConfig,
 	)
+	if err != nil {
+		infra.LogFatal("Error building Yahoo Finance integration client", "error", err)
+	}
 	var yahooFinanceIntegrationService = anticorruption.BuildYahooFinanceAssetIntegrationService(
 		yahooFinanceIntegrationClient,
 	)
 
-	var assetIntegrationServices = service.AssetIntegrationServicesPerSource{
+	stooqIntegrationClient, err := integration.BuildStooqAssetIntegrationClient(
+		app.config.IntegrationConfig.StooqConfig,
+	)
+	if err != nil {
+		infra.LogFatal("Error building Stooq integration client", "error", err)
+	}
+	var stooqIntegrationService = anticorruption.BuildStooqAssetIntegrationService(stooqIntegrationClient)
+
+	coinGeckoIntegrationClient, err := integration.BuildCoinGeckoAssetIntegrationClient(
+		app.config.IntegrationConfig.CoinGeckoConfig,
+	)
+	if err != nil {
+		infra.LogFatal("Error building CoinGecko integration client", "error", err)
+	}
+	var coinGeckoIntegrationService = anticorruption.BuildCoinGeckoAssetIntegrationService(
+		coinGeckoIntegrationClient,
+	)
+
+	var externalIntegrationServices = service.AssetIntegrationServicesPerSource{
 		domain.YahooFinanceSource: yahooFinanceIntegrationService,
+		domain.StooqSource:        stooqIntegrationService,
+		domain.CoinGeckoSource:    coinGeckoIntegrationService,
 	}
+	app.addJSONProviderIntegrationServices(externalIntegrationServices)
+
+	var assetIntegrationServices = app.buildCachedAssetIntegrationServices(externalIntegrationServices)
+	assetIntegrationServices[domain.ManualSource] = service.BuildManualAssetIntegrationService(assetRepository)
 
 	var portfolioDomService = service.BuildPortfolioDomService(portfolioRepository)
+	var auditDomService = service.BuildAuditDomService(auditRepository)
 	var portfolioAllocationDomService = service.BuildPortfolioAllocationDomService(portfolioAllocationRepository)
 	var allocationPlanDomService = service.BuildAllocationPlanDomService(allocationPlanRepository)
 	var allocationDomService = service.BuildAllocationDomService(allocationRepository)
-	var assetDomService = service.BuildAssetDomService(assetRepository, assetIntegrationServices)
+	var assetEventIntegrationServices = service.AssetEventIntegrationServicesPerSource{
+		domain.YahooFinanceSource: yahooFinanceIntegrationService,
+	}
+	var assetDomService = service.BuildAssetDomService(
+		assetRepository,
+		assetIntegrationServices,
+		assetEventIntegrationServices,
+	)
+	var corporateActionDomService = service.BuildCorporateActionDomService(corporateActionRepository, assetRepository)
+	var jobHandlers = service.JobHandlersPerType{
+		domain.AssetEventImportJobType: application.BuildAssetEventImportJobHandler(assetDomService),
+	}
+	var jobDomService = service.BuildJobDomService(jobRepository, jobHandlers)
+	var webhookDomService = service.BuildWebhookDomService(
+		webhookRepository,
+		integration.BuildWebhookHTTPSender(app.config.WebhookConfig),
+		app.config.WebhookConfig,
+	)
+	var divergenceAlertDomService = service.BuildDivergenceAlertDomService(
+		divergenceAlertRepository,
+		allocationPlanRepository,
+	)
+	app.authDomService = service.BuildAuthDomService(authRepository, app.config.GinServerConfig.AuthConfig)
+	app.portfolioAccessDomService = service.BuildPortfolioAccessDomService(portfolioAccessRepository, authRepository)
+	var idempotencyDomService = service.BuildIdempotencyDomService(
+		idempotencyRepository,
+		app.config.GinServerConfig.IdempotencyConfig,
+	)
 
 	// =====================================================
 	// Application
@@ -89,6 +155,13 @@ func (app *App) buildAppComponents() {
 		portfolioAllocationDomService,
 		allocationPlanDomService,
 	)
+	var divergenceAlertEvaluationAppService = application.BuildDivergenceAlertEvaluationAppService(
+		app.databaseAdapter,
+		divergenceAlertDomService,
+		portfolioAllocationDomService,
+		portfolioDivergenceAnalysisAppService,
+		webhookDomService,
+	)
 	var portfolioAnalysisConfigurationAppService = application.BuildPortfolioAnalysisConfigurationAppService(
 		portfolioAllocationDomService,
 		allocationPlanDomService,
@@ -97,24 +170,72 @@ func (app *App) buildAppComponents() {
 		app.databaseAdapter,
 		portfolioAllocationDomService,
 		assetDomService,
+		webhookDomService,
+		auditDomService,
+		divergenceAlertEvaluationAppService,
 	)
 	var allocationPlanManagementAppService = application.BuildAllocationPlanManagementAppService(
 		app.databaseAdapter,
 		allocationPlanDomService,
 		assetDomService,
 		portfolioDomService,
+		webhookDomService,
+		auditDomService,
+	)
+	var portfolioManagementAppService = application.BuildPortfolioManagementAppService(
+		app.databaseAdapter,
+		portfolioDomService,
+		auditDomService,
+	)
+	var assetManagementAppService = application.BuildAssetManagementAppService(
+		app.databaseAdapter,
+		assetDomService,
+		auditDomService,
+	)
+	var corporateActionManagementAppService = application.BuildCorporateActionManagementAppService(
+		app.databaseAdapter,
+		corporateActionDomService,
+		auditDomService,
 	)
+	app.jobRunnerAppService = application.BuildJobRunnerAppService(jobDomService, app.config.JobConfig)
+
+	var scheduledTaskHandlers = service.ScheduledTaskHandlersPerType{
+		domain.RevaluationScheduledTaskType: application.BuildRevaluationTaskHandler(
+			assetDomService,
+			portfolioAllocationDomService,
+			portfolioAllocationManagementAppService,
+			divergenceAlertEvaluationAppService,
+		),
+		domain.DivergenceAnalysisScheduledTaskType: application.BuildDivergenceAnalysisTaskHandler(
+			portfolioAllocationDomService,
+			portfolioDivergenceAnalysisAppService,
+			webhookDomService,
+		),
+	}
+	var portfolioScheduleDomService = service.BuildPortfolioScheduleDomService(
+		portfolioScheduleRepository,
+		scheduledTaskHandlers,
+	)
+	app.schedulerAppService = application.BuildPortfolioSchedulerAppService(
+		portfolioScheduleDomService,
+		app.config.SchedulerConfig,
+	)
+	app.webhookDispatcher = application.BuildWebhookDispatcherAppService(webhookDomService, app.config.WebhookConfig)
 
 	// =====================================================
 	// API - REST
 	// =====================================================
 	var portfolioRESTController = rest.BuildPortfolioRESTController(
+		portfolioManagementAppService,
 		portfolioDomService,
 		allocationDomService,
+		assetDomService,
+		app.portfolioAccessDomService,
 	)
 	var portfolioAllocationRESTController = rest.BuildPortfolioAllocationRESTController(
 		portfolioAllocationDomService,
 		portfolioAllocationManagementAppService,
+		assetDomService,
 	)
 	var portfolioDivergenceAnalysisRESTController = rest.BuildDivergenceAnalysisRESTController(
 		portfolioAnalysisConfigurationAppService,
@@ -124,7 +245,17 @@ func (app *App) buildAppComponents() {
 		allocationPlanDomService,
 		allocationPlanManagementAppService,
 	)
-	var assetRESTController = rest.BuildAssetRESTController(assetDomService)
+	var assetRESTController = rest.BuildAssetRESTController(assetDomService, assetManagementAppService)
+	var corporateActionRESTController = rest.BuildCorporateActionRESTController(
+		assetDomService,
+		corporateActionDomService,
+		corporateActionManagementAppService,
+	)
+	var jobRESTController = rest.BuildJobRESTController(jobDomService, app.jobRunnerAppService)
+	var portfolioScheduleRESTController = rest.BuildPortfolioScheduleRESTController(portfolioScheduleDomService)
+	var portfolioWebhookRESTController = rest.BuildPortfolioWebhookRESTController(webhookDomService)
+	var divergenceAlertRESTController = rest.BuildDivergenceAlertRESTController(divergenceAlertDomService)
+	var auditRESTController = rest.BuildAuditRESTController(auditDomService)
 
 	app.restControllers = []infra.GinServerRESTController{
 		portfolioRESTController,
@@ -132,30 +263,162 @@ func (app *App) buildAppComponents() {
 		portfolioDivergenceAnalysisRESTController,
 		portfolioAllocationRESTController,
 		assetRESTController,
+		corporateActionRESTController,
+		jobRESTController,
+		portfolioScheduleRESTController,
+		portfolioWebhookRESTController,
+		divergenceAlertRESTController,
+		auditRESTController,
+	}
+
+	app.server.SetIdempotencyMiddleware(rest.BuildIdempotencyMiddleware(idempotencyDomService).Handle)
+
+	var authConfig = app.config.GinServerConfig.AuthConfig
+	if authConfig.IsEnabled() {
+		var authenticationMiddleware = rest.BuildAuthenticationMiddleware(
+			app.authDomService,
+			app.portfolioAccessDomService,
+			authConfig,
+		)
+		app.server.SetAuthenticationMiddleware(authenticationMiddleware.Handle)
+		app.restControllers = append(
+			app.restControllers,
+			rest.BuildAuthRESTController(app.authDomService, authConfig),
+			rest.BuildPortfolioAccessRESTController(app.portfolioAccessDomService),
+		)
+	}
+
+	app.restControllers = append(app.restControllers, rest.BuildOpenAPIRESTController(app.restControllers, authConfig))
+}
+
+// addJSONProviderIntegrationServices adds the integration services of the JSON providers declared in the
+// configuration, registering their sources. Providers that cannot be built are logged and left out, except
+// for a configured cassette that cannot be built, which fails the startup like for the other providers.
+func (app *App) addJSONProviderIntegrationServices(integrationServices service.AssetIntegrationServicesPerSource) {
+
+	for _, providerConfig := range app.config.IntegrationConfig.JSONProviderConfigs {
+
+		var source = domain.AssetExternalSource(providerConfig.Source)
+		client, err := integration.BuildJSONProviderAssetIntegrationClient(providerConfig)
+		if err != nil {
+			infra.LogFatal("Error building JSON provider integration client", "source", source, "error", err)
+		}
+
+		integrationService, err := anticorruption.BuildJSONProviderAssetIntegrationService(client)
+		if err != nil {
+			slog.Error("Ignoring JSON provider", "source", source, "error", err)
+			continue
+		}
+
+		if err = domain.RegisterConfiguredExternalSource(source); err != nil {
+			slog.Error("Ignoring JSON provider", "source", source, "error", err)
+			continue
+		}
+
+		integrationServices[source] = integrationService
+		slog.Info("JSON provider configured", "source", source)
+	}
+}
+
+// buildCachedAssetIntegrationServices wraps the integration services of the external providers with the
+// configured cache, when caching is enabled for any operation.
+func (app *App) buildCachedAssetIntegrationServices(
+	integrationServices service.AssetIntegrationServicesPerSource,
+) service.AssetIntegrationServicesPerSource {
+
+	var cacheConfig = app.config.IntegrationConfig.CacheConfig
+	if cacheConfig.SearchTTL <= 0 && cacheConfig.QuoteTTL <= 0 {
+		return integrationServices
 	}
+
+	var cacheRepository domain.AssetIntegrationCacheRepository
+	if cacheConfig.Storage == infra.AssetIntegrationCacheRDBMSStorage {
+		cacheRepository = repository.BuildAssetIntegrationCacheRDBMSRepository(app.databaseAdapter)
+	} else {
+		cacheRepository = repository.BuildAssetIntegrationCacheMemoryRepository()
+	}
+
+	var cachedIntegrationServices = make(service.AssetIntegrationServicesPerSource, len(integrationServices))
+	for source, integrationService := range integrationServices {
+		cachedIntegrationServices[source] = service.BuildCachedAssetIntegrationService(
+			source,
+			integrationService,
+			cacheRepository,
+			cacheConfig,
+		)
+	}
+
+	return cachedIntegrationServices
 }
 
 func (app *App) initializeAppComponents() {
 	app.databaseAdapter.Init()
 	app.databaseAdapter.Ping()
+	app.ensureBootstrapUser()
+	app.assignUnownedPortfolios()
 	app.server.Init(app.restControllers)
+	app.schedulerAppService.Start()
+	app.webhookDispatcher.Start()
+}
+
+// ensureBootstrapUser creates the configured bootstrap user when authentication is enabled and there are no
+// users yet, as nobody could log in otherwise.
+func (app *App) ensureBootstrapUser() {
+
+	if !app.config.GinServerConfig.AuthConfig.IsEnabled() {
+		slog.Warn("Authentication is DISABLED, the API must only be reachable locally")
+		return
+	}
+
+	if err := app.authDomService.EnsureBootstrapUser(); err != nil {
+		slog.Error("Error creating bootstrap user", "error", err)
+	}
+}
+
+// assignUnownedPortfolios gives an owner to the portfolios created while authentication was disabled, as
+// nobody could access them otherwise.
+func (app *App) assignUnownedPortfolios() {
+
+	if !app.config.GinServerConfig.AuthConfig.IsEnabled() {
+		return
+	}
+
+	assignedCount, err := app.portfolioAccessDomService.AssignUnownedPortfolios()
+	if err != nil {
+		slog.Error("Error assigning unowned portfolios", "error", err)
+		return
+	}
+
+	if assignedCount > 0 {
+		slog.Info("Assigned unowned portfolios to the oldest user", "assignedCount", assignedCount)
+	}
+}
+
+// recoverInterruptedJobs resumes the background jobs interrupted by the previous stop of the application.
+func (app *App) recoverInterruptedJobs() {
+	if err := app.jobRunnerAppService.RecoverInterruptedJobs(); err != nil {
+		slog.Error("Error recovering interrupted jobs", "error", err)
+	}
 }
 
 func (app *App) closeAppComponents(stopContext context.Context) {
 	app.server.Stop(stopContext)
+	app.schedulerAppService.Stop(stopContext)
+	app.webhookDispatcher.Stop(stopContext)
+	app.jobRunnerAppService.Stop(stopContext)
 	app.databaseAdapter.Stop()
 }
 
 func (app *App) Run() {
 
-	glog.Info("Starting application on run mode...")
+	slog.Info("Starting application on run mode...")
 	app.Start()
 
 	stopChannel := buildStopChannel()
 
 	<-stopChannel
 
-	glog.Info("Received stop signal, shutting down application...")
+	slog.Info("Received stop signal, shutting down application...")
 	app.Stop()
 }
 
@@ -163,11 +426,11 @@ func (app *App) Start() {
 	app.buildBaseInfrastructure()
 	app.buildAppComponents()
 	app.initializeAppComponents()
+	app.recoverInterruptedJobs()
 }
 
 func (app *App) StartOverridingConfigs(config *infra.Configuration) {
-	// TODO only on debug mode
-	glog.Info("Starting application with overridden configurations", config)
+	slog.Debug("Starting application with overridden configurations", "config", config)
 	app.config = config
 	app.Start()
 }
@@ -178,7 +441,7 @@ func (app *App) Stop() {
 
 	app.closeAppComponents(stopContext)
 
-	glog.Info("Exiting application process")
+	slog.Info("Exiting application process")
 }
 
 func buildStopContext() (context.Context, context.CancelFunc) {
@@ -186,7 +449,7 @@ func buildStopContext() (context.Context, context.CancelFunc) {
 	go func() {
 		<-stopContext.Done()
 		if errors.Is(stopContext.Err(), context.DeadlineExceeded) {
-			glog.Fatal("Error stopping application: timeout")
+			infra.LogFatal("Error stopping application: timeout")
 		}
 	}()
 	return stopContext, cancel
@@ -1,8 +1,26 @@
 import { Asset } from "../domain/asset";
+import InfraTypesUtils from "../infra/infra-types-utils";
 
+export const DATA_NOT_FOUND_PROBLEM_TYPE = "urn:open-asset-allocator:problem:data-not-found";
+
+/**
+ * Error of an APIErrorResponse, pointing to the request field that caused it when there is one
+ */
+export type APIErrorResponseError = {
+    pointer?: string;
+    detail: string;
+};
+
+/**
+ * RFC 7807 problem details of an API error response (application/problem+json)
+ */
 export type APIErrorResponse = {
-    errorMessage: string;
-    details?: string[];
+    type?: string;
+    title: string;
+    status?: number;
+    detail?: string;
+    instance?: string;
+    errors?: APIErrorResponseError[];
 };
 
 class APIError extends Error {
@@ -19,8 +37,8 @@ const api = {
     isAPIErrorResponse(obj: unknown): obj is APIErrorResponse {
         return typeof obj === "object"
             && obj !== null
-            && "errorMessage" in obj
-            && Array.isArray((obj as APIErrorResponse).details);
+            && "title" in obj
+            && "status" in obj;
     },
     getAsset: async(uniqueIdentifier: string): Promise<Asset | APIErrorResponse> => {
 
@@ -31,7 +49,7 @@ const api = {
             const error = new APIError("Network response was not ok");
             const contentType = response.headers.get("content-type");
 
-            if(contentType && contentType.includes("application/json")) {
+            if(InfraTypesUtils.isJSONContentType(contentType)) {
                 return await response.json() as Promise<APIErrorResponse>;
             }
 
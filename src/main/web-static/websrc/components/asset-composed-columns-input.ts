@@ -1,6 +1,6 @@
 import { Asset } from "../domain/asset";
 import { BootstrapClasses, BootstrapIconClasses } from "../infra/bootstrap/constants";
-import api from "../api/api";
+import api, { DATA_NOT_FOUND_PROBLEM_TYPE } from "../api/api";
 import htmx from "htmx.org";
 import notifications from "./notifications";
 
@@ -155,7 +155,7 @@ function getAsset(rowAssetElements: AssetComposedColumnInput, searchUniqueIdenti
         .then(responseBody => {
 
             if(api.isAPIErrorResponse(responseBody)) {
-                if(responseBody.errorMessage === "Data not found") {
+                if(responseBody.type === DATA_NOT_FOUND_PROBLEM_TYPE) {
                     rowAssetElements.activateNewAssetMode();
                 }
                 else {
@@ -168,7 +168,10 @@ function getAsset(rowAssetElements: AssetComposedColumnInput, searchUniqueIdenti
         })
         .catch(error => {
             console.error("Error fetching asset:", error);
-            notifications.notifyErrorResponse({ errorMessage: "Failed to fetch asset data: " + error.message });
+            notifications.notifyErrorResponse({
+                title: "Failed to fetch asset data",
+                detail: error.message,
+            });
         });
 }
 
@@ -24,12 +24,17 @@ function buildBootstrapNotification(notification: Notification): BootstrapNotifi
 function buildBootstrapErrorNotification(errorResponse: APIErrorResponse): BootstrapNotification {
 
     const title = "Error";
-    let content = DomInfra.DomUtils.escapeHtml(errorResponse.errorMessage ?? "");
+    let content = DomInfra.DomUtils.escapeHtml(errorResponse.title ?? "");
 
-    if(errorResponse.details && errorResponse.details.length > 0) {
+    const details = [
+        errorResponse.detail,
+        ...(errorResponse.errors ?? []).map(error => error.detail),
+    ].filter(detail => !!detail);
 
-        const detailsList = errorResponse.details.map(
-            detail => `<li>${ DomInfra.DomUtils.escapeHtml(detail ?? "") }</li>`,
+    if(details.length > 0) {
+
+        const detailsList = details.map(
+            detail => `<li>${ DomInfra.DomUtils.escapeHtml(detail) }</li>`,
         ).join("");
 
         content += `<ul>${ detailsList }</ul>`;
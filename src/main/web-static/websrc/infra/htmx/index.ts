@@ -170,7 +170,7 @@ function toErrorResponse(eventDetail: AfterRequestEventDetail): APIErrorResponse
 
     const contentType = eventDetail.xhr.getResponseHeader("content-type");
 
-    if(contentType && contentType.includes("application/json")) {
+    if(InfraTypesUtils.isJSONContentType(contentType)) {
         return InfraTypesUtils.toErrorResponse(eventDetail.xhr.response);
     }
 
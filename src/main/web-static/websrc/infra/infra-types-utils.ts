@@ -1,26 +1,35 @@
 import { logger, LogLevel } from "./logging";
 import { APIErrorResponse } from "../api/api";
 
+const JSON_CONTENT_TYPE_PATTERN = /^application\/(problem\+)?json\b/;
+
 const InfraTypesUtils = {
 
+    /**
+     * Whether a response content type is JSON, including the application/problem+json of API error responses
+     */
+    isJSONContentType(contentType: string | null): boolean {
+        return !!contentType && JSON_CONTENT_TYPE_PATTERN.test(contentType.trim());
+    },
+
     toErrorResponse(errorResponseJson: string): APIErrorResponse | undefined {
 
         try {
 
             const errorResponse = JSON.parse(errorResponseJson) as APIErrorResponse;
 
-            const hasErrorProperty = errorResponse && typeof errorResponse.errorMessage === "string";
+            const hasTitleProperty = errorResponse && typeof errorResponse.title === "string";
 
-            const hasValidDetails = errorResponse
+            const hasValidErrors = errorResponse
                 && (
-                    errorResponse.details === undefined
+                    errorResponse.errors === undefined
                     || (
-                        Array.isArray(errorResponse.details)
-                        && errorResponse.details.every((detail) => typeof detail === "string")
+                        Array.isArray(errorResponse.errors)
+                        && errorResponse.errors.every((error) => error && typeof error.detail === "string")
                     )
                 );
 
-            if(errorResponse && hasErrorProperty && hasValidDetails) {
+            if(errorResponse && hasTitleProperty && hasValidErrors) {
                 return errorResponse;
             }
         } catch(jsonParseError) {
@@ -16,7 +16,7 @@ const AFTER_REQUEST_ERROR_HANDLER = (event: CustomEvent) => {
         }
         else {
             const fallbackErrorMessage = "An unexpected error occurred while communicating with the server.";
-            notifications.notifyErrorResponse({ errorMessage: fallbackErrorMessage });
+            notifications.notifyErrorResponse({ title: fallbackErrorMessage });
         }
 
         return;
@@ -0,0 +1,24 @@
+#!/usr/bin/env zsh
+
+# Vendors the Swagger UI assets served at /api/docs from the swagger-ui-dist npm package, at the version
+# pinned in the VERSION file of the vendor folder. npm pack verifies the downloaded package against the
+# integrity hash published in the npm registry.
+
+script_dir=$(dirname "$0")
+project_root=$(realpath "$script_dir")
+
+vendor_dir="$project_root/src/main/go/infra/openapi/swagger-ui-dist"
+version=$(<"$vendor_dir/VERSION")
+
+work_dir=$(mktemp -d)
+trap 'rm -rf "$work_dir"' EXIT
+
+echo "===== Vendoring swagger-ui-dist@$version"
+cd "$work_dir" || exit
+if ! npm pack --silent "swagger-ui-dist@$version"; then
+  echo "Failed to download swagger-ui-dist@$version"
+  exit 1
+fi
+
+tar -xzf "swagger-ui-dist-$version.tgz"
+cp package/swagger-ui.css package/swagger-ui-bundle.js package/LICENSE "$vendor_dir"